@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/invopop/yaml"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi31conv"
+	"github.com/getkin/kin-openapi/openapi3diff"
+	"github.com/getkin/kin-openapi/openapi3docs"
+	"github.com/getkin/kin-openapi/openapi3lint"
+	"github.com/getkin/kin-openapi/openapi3mock"
+)
+
+func runValidate(args []string, stdout io.Writer) error {
+	flags := newFlagSet("validate", "<file>")
+	defaults := flags.Bool("defaults", true, "when false, disables schemas' default field validation")
+	examples := flags.Bool("examples", true, "when false, disables all example schema validation")
+	ext := flags.Bool("ext", false, "enables visiting other files")
+	patterns := flags.Bool("patterns", true, "when false, allows schema patterns unsupported by the Go regexp engine")
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+	filename := flags.Arg(0)
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	v, err := detectVersion(data)
+	if err != nil {
+		return err
+	}
+	if v == version2 {
+		// OpenAPIv2 documents are only checked to decode
+		var doc openapi2.T
+		return yaml.Unmarshal(data, &doc)
+	}
+	doc, err := loadDocument(filename, *ext)
+	if err != nil {
+		return err
+	}
+	var opts []openapi3.ValidationOption
+	if !*defaults {
+		opts = append(opts, openapi3.DisableSchemaDefaultsValidation())
+	}
+	if !*examples {
+		opts = append(opts, openapi3.DisableExamplesValidation())
+	}
+	if !*patterns {
+		opts = append(opts, openapi3.DisableSchemaPatternValidation())
+	}
+	return doc.Validate(context.Background(), opts...)
+}
+
+func runBundle(args []string, stdout io.Writer) error {
+	flags := newFlagSet("bundle", "<file>")
+	var output outputFlags
+	output.register(flags)
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+	filename := flags.Arg(0)
+
+	doc, err := loadDocument(filename, true)
+	if err != nil {
+		return err
+	}
+	doc.InternalizeRefs(context.Background(), nil)
+	return output.write(stdout, filename, doc)
+}
+
+func runConvert(args []string, stdout io.Writer) error {
+	flags := newFlagSet("convert", "-to <2|3|3.1> <file>")
+	to := flags.String("to", "", "target version: 2, 3 or 3.1")
+	ext := flags.Bool("ext", false, "enables visiting other files")
+	var output outputFlags
+	output.register(flags)
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+	filename := flags.Arg(0)
+
+	doc, err := loadDocument(filename, *ext)
+	if err != nil {
+		return err
+	}
+	switch *to {
+	case "2":
+		doc2, err := openapi2conv.FromV3(doc)
+		if err != nil {
+			return err
+		}
+		return output.write(stdout, filename, doc2)
+	case "3", "3.0":
+		return output.write(stdout, filename, doc)
+	case "3.1":
+		data, err := openapi31conv.ToV31(doc)
+		if err != nil {
+			return err
+		}
+		return output.write(stdout, filename, data)
+	}
+	flags.Usage()
+	return errUsage
+}
+
+func runDiff(args []string, stdout io.Writer) error {
+	flags := newFlagSet("diff", "<base file> <revision file>")
+	ext := flags.Bool("ext", false, "enables visiting other files")
+	asJSON := flags.Bool("json", false, "print changes as JSON")
+	breakingOnly := flags.Bool("breaking-only", false, "only print breaking changes")
+	failOnBreaking := flags.Bool("fail-on-breaking", false, "exit with code 1 when there are breaking changes")
+	if err := parseArgs(flags, args, 2); err != nil {
+		return err
+	}
+
+	base, err := loadDocument(flags.Arg(0), *ext)
+	if err != nil {
+		return err
+	}
+	revision, err := loadDocument(flags.Arg(1), *ext)
+	if err != nil {
+		return err
+	}
+	changes := openapi3diff.Compare(base, revision)
+	if *breakingOnly {
+		changes = changes.Breaking()
+	}
+
+	if *asJSON {
+		if changes == nil {
+			changes = openapi3diff.Changes{}
+		}
+		if err := printJSON(stdout, changes); err != nil {
+			return err
+		}
+	} else {
+		for _, change := range changes {
+			fmt.Fprintln(stdout, change)
+		}
+	}
+	if *failOnBreaking && len(changes.Breaking()) != 0 {
+		return errFailed
+	}
+	return nil
+}
+
+func runLint(args []string, stdout io.Writer) error {
+	flags := newFlagSet("lint", "<file>")
+	ext := flags.Bool("ext", false, "enables visiting other files")
+	asJSON := flags.Bool("json", false, "print findings as JSON")
+	disable := flags.String("disable", "", "comma-separated names of rules to disable")
+	failOn := flags.String("fail-on", openapi3lint.SeverityError.String(), "exit with code 1 when there are findings of at least this severity: info, warning, error or none")
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+	var failSeverity openapi3lint.Severity
+	if *failOn != "none" {
+		if err := failSeverity.UnmarshalText([]byte(*failOn)); err != nil {
+			return err
+		}
+	}
+
+	doc, err := loadDocument(flags.Arg(0), *ext)
+	if err != nil {
+		return err
+	}
+	var opts []openapi3lint.Option
+	if *disable != "" {
+		opts = append(opts, openapi3lint.DisableRules(strings.Split(*disable, ",")...))
+	}
+	findings := openapi3lint.New(opts...).Lint(doc)
+
+	if *asJSON {
+		if findings == nil {
+			findings = openapi3lint.Findings{}
+		}
+		if err := printJSON(stdout, findings); err != nil {
+			return err
+		}
+	} else {
+		for _, finding := range findings {
+			fmt.Fprintln(stdout, finding)
+		}
+	}
+	if *failOn != "none" && len(findings.AtLeast(failSeverity)) != 0 {
+		return errFailed
+	}
+	return nil
+}
+
+func runMock(args []string, stdout io.Writer) error {
+	flags := newFlagSet("mock", "<file>")
+	ext := flags.Bool("ext", false, "enables visiting other files")
+	addr := flags.String("addr", ":8080", "address to listen on")
+	noValidation := flags.Bool("no-validation", false, "do not validate requests")
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+
+	doc, err := loadDocument(flags.Arg(0), *ext)
+	if err != nil {
+		return err
+	}
+	// Serve the paths at the root whatever the servers of the document
+	doc.Servers = nil
+	var opts []openapi3mock.Option
+	if *noValidation {
+		opts = append(opts, openapi3mock.WithoutRequestValidation())
+	}
+	server, err := openapi3mock.NewServer(doc, opts...)
+	if err != nil {
+		return err
+	}
+	log.Printf("Serving mock responses for %s on %s", flags.Arg(0), *addr)
+	return http.ListenAndServe(*addr, server)
+}
+
+func runDocs(args []string, stdout io.Writer) error {
+	flags := newFlagSet("docs", "<file>")
+	ext := flags.Bool("ext", false, "enables visiting other files")
+	addr := flags.String("addr", ":8080", "address to listen on")
+	ui := flags.String("ui", string(openapi3docs.SwaggerUI), "viewer of the document: swagger-ui, redoc or elements")
+	assets := flags.String("assets", "", "URL of the scripts and styles of the viewer (defaults to the copies embedded in the binary)")
+	cdn := flags.Bool("cdn", false, "loads the scripts and styles of the viewer from a CDN")
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+
+	doc, err := loadDocument(flags.Arg(0), *ext)
+	if err != nil {
+		return err
+	}
+	opts := []openapi3docs.Option{openapi3docs.WithUI(openapi3docs.UI(*ui)), openapi3docs.WithAssetsURL(*assets)}
+	if *cdn {
+		opts = append(opts, openapi3docs.WithCDN())
+	}
+	handler, err := openapi3docs.NewHandler(doc, opts...)
+	if err != nil {
+		return err
+	}
+	log.Printf("Serving documentation for %s on %s", flags.Arg(0), *addr)
+	return http.ListenAndServe(*addr, handler)
+}
+
+func printJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
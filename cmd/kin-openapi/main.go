@@ -0,0 +1,241 @@
+// Command kin-openapi validates, bundles, converts, compares, lints and mocks OpenAPI documents.
+//
+// Usage:
+//
+//	kin-openapi <command> [flags] <file>...
+//
+// Run `kin-openapi <command> -h` for the flags of a command.
+// The exit code is 1 when a document is invalid, has breaking changes or lint errors
+// and 2 on usage errors.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/invopop/yaml"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi31conv"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string, stdout io.Writer) error
+}
+
+var commands = []command{
+	{"validate", "validate a document", runValidate},
+	{"bundle", "resolve the external references of a document into its components", runBundle},
+	{"convert", "convert a document between OpenAPI 2, 3.0 and 3.1", runConvert},
+	{"diff", "list the changes between two revisions of a document", runDiff},
+	{"lint", "check a document against style rules", runLint},
+	{"mock", "serve mock responses for the operations of a document", runMock},
+	{"docs", "serve interactive documentation for a document", runDocs},
+}
+
+// errFailed is returned by commands whose outcome was negative, after printing it.
+var errFailed = errors.New("failed")
+
+// errUsage is returned by commands called with wrong arguments, after printing their usage.
+var errUsage = errors.New("usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		switch err := cmd.run(args[1:], stdout); err {
+		case nil:
+			return 0
+		case errFailed:
+			return 1
+		case errUsage, flag.ErrHelp:
+			return 2
+		default:
+			fmt.Fprintf(stderr, "kin-openapi %s: %v\n", cmd.name, err)
+			return 1
+		}
+	}
+	usage(stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: kin-openapi <command> [flags] <file>...")
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", cmd.name, cmd.usage)
+	}
+}
+
+// newFlagSet returns the flag set of a command, printing its usage to stderr.
+func newFlagSet(name, arguments string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: kin-openapi %s [flags] %s\n", name, arguments)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseArgs parses the flags of a command, which takes n file arguments.
+func parseArgs(flags *flag.FlagSet, args []string, n int) error {
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return err
+		}
+		// The flag set has already printed the error and its usage
+		return errUsage
+	}
+	if flags.NArg() != n {
+		flags.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+type version int
+
+const (
+	versionUnknown version = iota
+	version2
+	version30
+	version31
+)
+
+// detectVersion returns the OpenAPI version of a JSON or YAML document.
+func detectVersion(data []byte) (version, error) {
+	var vd struct {
+		OpenAPI string `json:"openapi" yaml:"openapi"`
+		Swagger string `json:"swagger" yaml:"swagger"`
+	}
+	if err := yaml.Unmarshal(data, &vd); err != nil {
+		return versionUnknown, err
+	}
+	switch {
+	case strings.HasPrefix(vd.OpenAPI, "3.1"):
+		return version31, nil
+	case vd.OpenAPI == "3" || strings.HasPrefix(vd.OpenAPI, "3."):
+		return version30, nil
+	case vd.Swagger == "2" || strings.HasPrefix(vd.Swagger, "2."):
+		return version2, nil
+	}
+	return versionUnknown, errors.New("missing or incorrect 'openapi' or 'swagger' field")
+}
+
+// loadDocument loads an OpenAPI 2, 3.0 or 3.1 document as OpenAPI 3.0.
+func loadDocument(filename string, ext bool) (*openapi3.T, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	v, err := detectVersion(data)
+	if err != nil {
+		return nil, err
+	}
+	switch v {
+	case version2:
+		var doc2 openapi2.T
+		if err := yaml.Unmarshal(data, &doc2); err != nil {
+			return nil, err
+		}
+		return openapi2conv.ToV3(&doc2)
+	case version31:
+		return openapi31conv.FromV31(data)
+	}
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = ext
+	return loader.LoadFromFile(filename)
+}
+
+// outputFlags are the flags of commands writing a document.
+type outputFlags struct {
+	output string
+	format string
+}
+
+func (o *outputFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.output, "o", "", "output file (default stdout)")
+	flags.StringVar(&o.format, "format", "", "output format, json or yaml (default from the output or input file extension)")
+}
+
+// write encodes the JSON or YAML value of v to the output.
+func (o *outputFlags) write(stdout io.Writer, input string, v interface{}) error {
+	data, ok := v.([]byte)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return err
+		}
+	}
+
+	format := o.format
+	if format == "" {
+		name := o.output
+		if name == "" {
+			name = input
+		}
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".yaml", ".yml":
+			format = "yaml"
+		default:
+			format = "json"
+		}
+	}
+	switch format {
+	case "json":
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, "", "  "); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		data = buf.Bytes()
+	case "yaml":
+		// Documents are encoded to YAML directly, so as to keep their field order
+		if m, ok := v.(yamlv3.Marshaler); ok {
+			var buf bytes.Buffer
+			enc := yamlv3.NewEncoder(&buf)
+			enc.SetIndent(2)
+			if err := enc.Encode(m); err != nil {
+				return err
+			}
+			if err := enc.Close(); err != nil {
+				return err
+			}
+			data = buf.Bytes()
+			break
+		}
+		var err error
+		if data, err = yaml.JSONToYAML(data); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+
+	if o.output == "" {
+		_, err := stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(o.output, data, 0644)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	specV3 = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema: {$ref: 'pet.yaml#/Pet'}
+`
+	specPet = `
+Pet:
+  type: object
+  properties:
+    name: {type: string, example: 1}
+`
+	specV2 = `
+swagger: '2.0'
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      responses:
+        '200': {description: Pets}
+`
+)
+
+// writeSpecs writes files, by name, to a temporary directory and returns its path.
+func writeSpecs(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, data := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600))
+	}
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	dir := writeSpecs(t, map[string]string{
+		"openapi.yaml": specV3,
+		"pet.yaml":     specPet,
+		"swagger.yaml": specV2,
+		"invalid.yaml": "openapi: 3.0.0\npaths: {}\n",
+		"unknown.yaml": "asyncapi: 2.0.0\n",
+	})
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{name: "no command", args: nil, code: 2, stderr: "Usage: kin-openapi <command>"},
+		{name: "unknown command", args: []string{"check"}, code: 2, stderr: "Usage: kin-openapi <command>"},
+		{name: "missing file argument", args: []string{"validate"}, code: 2},
+		{name: "unknown flag", args: []string{"validate", "-strict", path("openapi.yaml")}, code: 2},
+		{name: "help", args: []string{"bundle", "-h"}, code: 2},
+		{name: "v3", args: []string{"validate", "-ext", "-examples=false", path("openapi.yaml")}, code: 0},
+		{name: "v3 invalid example", args: []string{"validate", "-ext", path("openapi.yaml")}, code: 1, stderr: "kin-openapi validate: invalid paths: invalid path /pets: invalid operation GET: invalid example"},
+		{name: "v3 external refs disallowed", args: []string{"validate", path("openapi.yaml")}, code: 1, stderr: "encountered disallowed external reference"},
+		{name: "v2", args: []string{"validate", path("swagger.yaml")}, code: 0},
+		{name: "invalid", args: []string{"validate", path("invalid.yaml")}, code: 1, stderr: "invalid info: must be an object"},
+		{name: "unknown version", args: []string{"validate", path("unknown.yaml")}, code: 1, stderr: "missing or incorrect 'openapi' or 'swagger' field"},
+		{name: "missing file", args: []string{"validate", path("missing.yaml")}, code: 1, stderr: "no such file or directory"},
+		{name: "bundle", args: []string{"bundle", path("openapi.yaml")}, code: 0},
+		{name: "bundle missing file", args: []string{"bundle", path("missing.yaml")}, code: 1, stderr: "kin-openapi bundle:"},
+		{name: "bundle unsupported format", args: []string{"bundle", "-format", "xml", path("openapi.yaml")}, code: 1, stderr: `unsupported format "xml"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			require.Equal(t, test.code, run(test.args, &stdout, &stderr), stderr.String())
+			require.Contains(t, stderr.String(), test.stderr)
+		})
+	}
+}
+
+func TestRunValidate(t *testing.T) {
+	dir := writeSpecs(t, map[string]string{
+		"openapi.yaml":    specV3,
+		"pet.yaml":        specPet,
+		"swagger.yaml":    specV2,
+		"swagger.json":    `{"swagger": "2.0", "info": {"title": "Pets", "version": "1.0.0"}, "paths": {"/pets": []}}`,
+		"openapi31.yaml":  "openapi: 3.1.0\ninfo: {title: Pets, version: 1.0.0}\npaths: {}\n",
+		"openapi31.json":  `{"openapi": "3.1.0", "info": {"title": "Pets", "version": "1.0.0"}}`,
+		"not-openapi.txt": "Pets\n",
+	})
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "v3", args: []string{"-ext", "-examples=false", "openapi.yaml"}},
+		{name: "v3 examples", args: []string{"-ext", "openapi.yaml"}, wantErr: "invalid example: field must be set to string"},
+		{name: "v2 decodes", args: []string{"swagger.yaml"}},
+		{name: "v2 does not decode", args: []string{"swagger.json"}, wantErr: "json: cannot unmarshal array"},
+		{name: "v3.1", args: []string{"openapi31.yaml"}},
+		{name: "v3.1 invalid", args: []string{"openapi31.json"}, wantErr: "invalid paths: must be an object"},
+		{name: "not a document", args: []string{"not-openapi.txt"}, wantErr: "cannot unmarshal"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args := append([]string(nil), test.args...)
+			args[len(args)-1] = filepath.Join(dir, args[len(args)-1])
+			var stdout bytes.Buffer
+			err := runValidate(args, &stdout)
+			if test.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), test.wantErr)
+			}
+			require.Empty(t, stdout.String())
+		})
+	}
+}
+
+func TestRunBundle(t *testing.T) {
+	dir := writeSpecs(t, map[string]string{
+		"openapi.yaml": specV3,
+		"pet.yaml":     specPet,
+		"openapi.json": `{"openapi": "3.0.0", "info": {"title": "Pets", "version": "1.0.0"}, "paths": {}}`,
+		"swagger.yaml": specV2,
+		"broken.yaml":  "openapi: 3.0.0\ninfo: {title: Pets, version: 1.0.0}\npaths: {}\ncomponents:\n  schemas:\n    Pet: {$ref: 'missing.yaml#/Pet'}\n",
+	})
+	output := filepath.Join(dir, "bundled.json")
+
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+		wantErr  string
+	}{
+		{name: "yaml from input", args: []string{"openapi.yaml"}, contains: "$ref: '#/components/schemas/Pet'"},
+		{name: "json from input", args: []string{"openapi.json"}, contains: `"title": "Pets"`},
+		{name: "format flag", args: []string{"-format", "json", "openapi.yaml"}, contains: `"$ref": "#/components/schemas/Pet"`},
+		{name: "v2 converted", args: []string{"-format", "json", "swagger.yaml"}, contains: `"openapi": "3.0.3"`},
+		{name: "output file", args: []string{"-o", output, "openapi.yaml"}},
+		{name: "missing ref", args: []string{"broken.yaml"}, wantErr: "missing.yaml"},
+		{name: "unsupported format", args: []string{"-format", "toml", "openapi.yaml"}, wantErr: `unsupported format "toml"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args := append([]string(nil), test.args...)
+			args[len(args)-1] = filepath.Join(dir, args[len(args)-1])
+			var stdout bytes.Buffer
+			err := runBundle(args, &stdout)
+			if test.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Contains(t, stdout.String(), test.contains)
+		})
+	}
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"$ref": "#/components/schemas/Pet"`)
+}
@@ -0,0 +1,7 @@
+// Package harreplay validates HTTP traffic recorded in HAR files against an OpenAPIv3 document.
+//
+// Each recorded request is routed with a routers.Router then the request and its response
+// are validated with openapi3filter. Violations are reported per operation.
+//
+// See http://www.softwareishard.com/blog/har-12-spec/
+package harreplay
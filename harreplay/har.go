@@ -0,0 +1,78 @@
+package harreplay
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// HAR is an HTTP Archive. Only the fields needed to replay entries are decoded.
+type HAR struct {
+	Log Log `json:"log"`
+}
+
+// Log holds the recorded entries of a HAR.
+type Log struct {
+	Entries []*Entry `json:"entries"`
+}
+
+// Entry is a recorded request and its response.
+type Entry struct {
+	StartedDateTime string    `json:"startedDateTime,omitempty"`
+	Request         *Request  `json:"request"`
+	Response        *Response `json:"response"`
+}
+
+// Request is a recorded request.
+type Request struct {
+	Method   string    `json:"method"`
+	URL      string    `json:"url"`
+	Headers  []Header  `json:"headers"`
+	PostData *PostData `json:"postData,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	Status  int      `json:"status"`
+	Headers []Header `json:"headers"`
+	Content Content  `json:"content"`
+}
+
+// Header is a recorded HTTP header.
+type Header struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PostData is the body of a recorded request.
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// Content is the body of a recorded response.
+// Text is base64-encoded when Encoding is "base64".
+type Content struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Decode reads a HAR from r.
+func Decode(r io.Reader) (*HAR, error) {
+	var har HAR
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return nil, err
+	}
+	return &har, nil
+}
+
+// DecodeFile reads a HAR from the file at path.
+func DecodeFile(path string) (*HAR, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Decode(f)
+}
@@ -0,0 +1,239 @@
+package harreplay
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// Stage tells which step of the validation of an entry failed.
+type Stage string
+
+const (
+	// StageEntry is for entries that could not be replayed, such as those with an invalid URL.
+	StageEntry Stage = "entry"
+	// StageRoute is for requests that match no operation.
+	StageRoute Stage = "route"
+	// StageRequest is for requests that do not conform to their operation.
+	StageRequest Stage = "request"
+	// StageResponse is for responses that do not conform to their operation.
+	StageResponse Stage = "response"
+)
+
+// Violation is a recorded request or response that failed validation.
+type Violation struct {
+	// Entry is the index of the entry in the HAR log.
+	Entry  int
+	Method string
+	URL    string
+	Status int
+	Stage  Stage
+	Err    error
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("entry %d: %s %s (%d): %s: %v", v.Entry, v.Method, v.URL, v.Status, v.Stage, v.Err)
+}
+
+func (v *Violation) Unwrap() error {
+	return v.Err
+}
+
+// OperationReport sums up the validation of the entries routed to an operation.
+type OperationReport struct {
+	Method      string
+	Path        string
+	OperationID string
+	// Entries is the count of entries routed to the operation.
+	Entries    int
+	Violations []*Violation
+}
+
+// Report is the result of Replay.
+type Report struct {
+	// Entries is the count of replayed entries.
+	Entries int
+	// Operations are sorted by path then method.
+	Operations []*OperationReport
+	// Unmatched holds the violations of entries that were not routed to an operation.
+	Unmatched []*Violation
+}
+
+// Valid reports whether all replayed entries passed validation.
+func (report *Report) Valid() bool {
+	if len(report.Unmatched) != 0 {
+		return false
+	}
+	for _, operation := range report.Operations {
+		if len(operation.Violations) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Violations returns all violations, in entry order.
+func (report *Report) Violations() []*Violation {
+	violations := append([]*Violation(nil), report.Unmatched...)
+	for _, operation := range report.Operations {
+		violations = append(violations, operation.Violations...)
+	}
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Entry < violations[j].Entry })
+	return violations
+}
+
+// Replay routes the entries of har with router and validates them.
+//
+// When options is nil, all errors of an entry are reported (MultiError)
+// and security requirements are not checked, as recordings seldom
+// hold credentials (NoopAuthenticationFunc).
+func Replay(ctx context.Context, router routers.Router, har *HAR, options *openapi3filter.Options) (*Report, error) {
+	if options == nil {
+		options = &openapi3filter.Options{
+			MultiError:         true,
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		}
+	}
+
+	report := &Report{}
+	operations := make(map[string]*OperationReport)
+	for i, entry := range har.Log.Entries {
+		if entry == nil || entry.Request == nil {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		report.Entries++
+
+		violation := &Violation{
+			Entry:  i,
+			Method: entry.Request.Method,
+			URL:    entry.Request.URL,
+		}
+		if entry.Response != nil {
+			violation.Status = entry.Response.Status
+		}
+
+		req, err := newRequest(ctx, entry.Request)
+		if err != nil {
+			violation.Stage, violation.Err = StageEntry, err
+			report.Unmatched = append(report.Unmatched, violation)
+			continue
+		}
+		route, pathParams, err := router.FindRoute(req)
+		if err != nil {
+			violation.Stage, violation.Err = StageRoute, err
+			report.Unmatched = append(report.Unmatched, violation)
+			continue
+		}
+
+		key := route.Method + " " + route.Path
+		operation := operations[key]
+		if operation == nil {
+			operation = &OperationReport{
+				Method: route.Method,
+				Path:   route.Path,
+			}
+			if route.Operation != nil {
+				operation.OperationID = route.Operation.OperationID
+			}
+			operations[key] = operation
+			report.Operations = append(report.Operations, operation)
+		}
+		operation.Entries++
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+		if err := openapi3filter.ValidateRequest(ctx, input); err != nil {
+			v := *violation
+			v.Stage, v.Err = StageRequest, err
+			operation.Violations = append(operation.Violations, &v)
+		}
+
+		if entry.Response == nil {
+			continue
+		}
+		body, err := responseBody(entry.Response)
+		if err != nil {
+			v := *violation
+			v.Stage, v.Err = StageEntry, err
+			operation.Violations = append(operation.Violations, &v)
+			continue
+		}
+		header := newHeader(entry.Response.Headers)
+		if mimeType := entry.Response.Content.MimeType; mimeType != "" && header.Get("Content-Type") == "" {
+			header.Set("Content-Type", mimeType)
+		}
+		err = openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 entry.Response.Status,
+			Header:                 header,
+			Body:                   ioutil.NopCloser(bytes.NewReader(body)),
+			Options:                options,
+		})
+		if err != nil {
+			v := *violation
+			v.Stage, v.Err = StageResponse, err
+			operation.Violations = append(operation.Violations, &v)
+		}
+	}
+
+	sort.SliceStable(report.Operations, func(i, j int) bool {
+		a, b := report.Operations[i], report.Operations[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	return report, nil
+}
+
+func newRequest(ctx context.Context, r *Request) (*http.Request, error) {
+	var body []byte
+	if r.PostData != nil {
+		body = []byte(r.PostData.Text)
+	}
+	req, err := http.NewRequest(r.Method, r.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header = newHeader(r.Headers)
+	if r.PostData != nil && r.PostData.MimeType != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", r.PostData.MimeType)
+	}
+	return req, nil
+}
+
+// newHeader returns the recorded headers, without HTTP/2 pseudo-headers
+// and without Content-Encoding as HAR bodies are recorded decoded.
+func newHeader(headers []Header) http.Header {
+	h := make(http.Header, len(headers))
+	for _, header := range headers {
+		if strings.HasPrefix(header.Name, ":") || strings.EqualFold(header.Name, "Content-Encoding") {
+			continue
+		}
+		h.Add(header.Name, header.Value)
+	}
+	return h
+}
+
+func responseBody(r *Response) ([]byte, error) {
+	if r.Content.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(r.Content.Text)
+	}
+	return []byte(r.Content.Text), nil
+}
@@ -0,0 +1,90 @@
+package harreplay_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/harreplay"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+func TestReplay(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.3
+info: {title: Pets, version: '1.0'}
+servers:
+- url: https://api.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+      - {name: limit, in: query, schema: {type: integer}}
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Pet'}
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+`)
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	har, err := harreplay.DecodeFile("testdata/pets.har")
+	require.NoError(t, err)
+
+	report, err := harreplay.Replay(context.Background(), router, har, nil)
+	require.NoError(t, err)
+	require.False(t, report.Valid())
+	require.Equal(t, 4, report.Entries)
+
+	require.Len(t, report.Operations, 2)
+	list, create := report.Operations[0], report.Operations[1]
+	require.Equal(t, "listPets", list.OperationID)
+	require.Equal(t, 2, list.Entries)
+	require.Len(t, list.Violations, 2)
+	require.Equal(t, 1, list.Violations[0].Entry)
+	require.Equal(t, harreplay.StageRequest, list.Violations[0].Stage)
+	require.Equal(t, 1, list.Violations[1].Entry)
+	require.Equal(t, harreplay.StageResponse, list.Violations[1].Stage)
+
+	require.Equal(t, "createPet", create.OperationID)
+	require.Equal(t, 1, create.Entries)
+	require.Empty(t, create.Violations)
+
+	require.Len(t, report.Unmatched, 1)
+	require.Equal(t, harreplay.StageRoute, report.Unmatched[0].Stage)
+	require.ErrorIs(t, report.Unmatched[0], routers.ErrMethodNotAllowed)
+
+	violations := report.Violations()
+	require.Len(t, violations, 3)
+	require.Equal(t, 3, violations[2].Entry)
+}
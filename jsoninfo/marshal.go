@@ -93,6 +93,13 @@ iteration:
 				result[field.JSONName] = []byte("null")
 				continue
 			}
+			if fieldValue.Kind() == reflect.Map && field.JSONOmitEmpty && fieldValue.Len() == 0 {
+				continue iteration
+			}
+			// Collections such as *openapi3.Paths are omitted when empty, as maps are
+			if c, ok := v.(interface{ Len() int }); ok && field.JSONOmitEmpty && c.Len() == 0 {
+				continue iteration
+			}
 			fieldData, err := v.MarshalJSON()
 			if err != nil {
 				return err
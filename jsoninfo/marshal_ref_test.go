@@ -0,0 +1,47 @@
+package jsoninfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalRef(t *testing.T) {
+	type value struct {
+		Type       string                 `json:"type"`
+		Properties map[string]interface{} `json:"properties"`
+	}
+	tests := []struct {
+		data      string
+		wantRef   string
+		wantValue *value
+	}{
+		{data: `{"$ref": "#/components/schemas/Pet"}`, wantRef: "#/components/schemas/Pet"},
+		{data: ` { "description" : "x", "$ref":"#/a" , "x-b": [1, {"$ref": "#/b"}] } `, wantRef: "#/a"},
+		{data: `{"$REF": "#/a"}`, wantRef: "#/a"},
+		{data: `{"$ref": "#/a/b"}`, wantRef: "#/a/b"},
+		{data: `{"$ref": "#/a", "$ref": "#/b"}`, wantRef: "#/b"},
+		{data: `{"$ref": "#/a", "$ref": null}`, wantRef: "#/a"},
+		{
+			data:      `{"type": "object", "properties": {"pet": {"$ref": "#/components/schemas/Pet"}, "s": "}\"{"}}`,
+			wantValue: &value{Type: "object", Properties: map[string]interface{}{"pet": map[string]interface{}{"$ref": "#/components/schemas/Pet"}, "s": `}"{`}},
+		},
+		{data: `{"type": "string", "$ref": ""}`, wantValue: &value{Type: "string"}},
+		{data: `{"type": "string", "$ref": 1}`, wantValue: &value{Type: "string"}},
+		{data: `{}`, wantValue: &value{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			var ref string
+			var v *value
+			err := UnmarshalRef([]byte(tt.data), &ref, &v)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRef, ref)
+			require.Equal(t, tt.wantValue, v)
+		})
+	}
+
+	var ref string
+	var v *value
+	require.Error(t, UnmarshalRef([]byte(`["$ref"]`), &ref, &v))
+}
@@ -0,0 +1,49 @@
+package openapi2conv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestCollectionFormat(t *testing.T) {
+	spec := []byte(`paths:
+  /pets:
+    get:
+      parameters:
+      - {name: csv, in: query, type: array, items: {type: string}, collectionFormat: csv}
+      - {name: ssv, in: query, type: array, items: {type: string}, collectionFormat: ssv}
+      - {name: pipes, in: query, type: array, items: {type: string}, collectionFormat: pipes}
+      - {name: multi, in: query, type: array, items: {type: string}, collectionFormat: multi}
+      - {name: unset, in: query, type: array, items: {type: string}}
+      responses:
+        200:
+          description: OK`)
+
+	doc3, err := v2v3YAML(spec)
+	require.NoError(t, err)
+
+	parameters := doc3.Paths.Value("/pets").Get.Parameters
+	for name, expected := range map[string]*openapi3.SerializationMethod{
+		"csv":   {Style: openapi3.SerializationForm, Explode: false},
+		"ssv":   {Style: openapi3.SerializationSpaceDelimited, Explode: false},
+		"pipes": {Style: openapi3.SerializationPipeDelimited, Explode: false},
+		"multi": {Style: openapi3.SerializationForm, Explode: true},
+		"unset": {Style: openapi3.SerializationForm, Explode: false},
+	} {
+		sm, err := parameters.GetByInAndName(openapi3.ParameterInQuery, name).SerializationMethod()
+		require.NoError(t, err)
+		require.Equal(t, expected, sm, name)
+	}
+	doc2, err := FromV3(doc3)
+	require.NoError(t, err)
+	for _, parameter := range doc2.Paths["/pets"].Get.Parameters {
+		if parameter.Name == "unset" {
+			require.Equal(t, "csv", parameter.CollectionFormat)
+		} else {
+			require.Equal(t, parameter.Name, parameter.CollectionFormat)
+		}
+	}
+}
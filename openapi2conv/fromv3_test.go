@@ -0,0 +1,165 @@
+package openapi2conv
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestFromV3Unsupported(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.3
+info: {title: Example, version: '1.0'}
+paths:
+  /pets:
+    parameters:
+    - {name: session, in: cookie, schema: {type: string}}
+    post:
+      parameters:
+      - {name: tracking, in: cookie, schema: {type: string}}
+      - {name: limit, in: query, schema: {type: integer}}
+      requestBody:
+        content:
+          text/plain:
+            schema: {type: string}
+          application/xml:
+            schema: {type: object}
+      callbacks:
+        onEvent:
+          '{$request.body#/url}':
+            post:
+              responses:
+                '200': {description: OK}
+      responses:
+        '200':
+          description: OK
+          content:
+            text/csv:
+              schema: {type: string}
+            application/xml:
+              schema: {$ref: '#/components/schemas/Pet'}
+components:
+  schemas:
+    Pet:
+      oneOf:
+      - {$ref: '#/components/schemas/Cat'}
+      - {$ref: '#/components/schemas/Dog'}
+    Cat:
+      anyOf:
+      - {type: object}
+    Dog: {type: object}
+`)
+	doc3, err := openapi3.NewLoader().LoadFromData(spec)
+	require.NoError(t, err)
+	err = doc3.Validate(context.Background())
+	require.NoError(t, err)
+
+	doc2, err := FromV3(doc3)
+	require.NoError(t, err)
+	data, err := json.Marshal(doc2)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+{
+  "swagger": "2.0",
+  "info": {"title": "Example", "version": "1.0"},
+  "definitions": {
+    "Cat": {"allOf": [{"type": "object"}]},
+    "Dog": {"type": "object"},
+    "Pet": {"x-oneOf": [{"$ref": "#/definitions/Cat"}, {"$ref": "#/definitions/Dog"}]}
+  },
+  "paths": {
+    "/pets": {
+      "x-cookieParameters": [{"in": "cookie", "name": "session", "type": "string"}],
+      "post": {
+        "consumes": ["application/xml", "text/plain"],
+        "produces": ["application/xml", "text/csv"],
+        "parameters": [
+          {"in": "body", "name": "body", "schema": {"type": "object"}},
+          {"in": "query", "name": "limit", "type": "integer"}
+        ],
+        "responses": {
+          "200": {"description": "OK", "schema": {"$ref": "#/definitions/Pet"}}
+        },
+        "x-cookieParameters": [{"in": "cookie", "name": "tracking", "type": "string"}],
+        "x-callbacks": {
+          "onEvent": {
+            "{$request.body#/url}": {
+              "post": {"responses": {"200": {"description": "OK"}}}
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`, string(data))
+}
+
+func TestFromV3FormDataIsDeterministic(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.3
+info: {title: Example, version: '1.0'}
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              required: [name, age, photo]
+              properties:
+                photo: {type: string, format: binary}
+                name: {type: string}
+                age: {type: integer}
+                color: {type: string}
+      responses:
+        '200': {description: OK}
+`)
+	doc3, err := openapi3.NewLoader().LoadFromData(spec)
+	require.NoError(t, err)
+
+	var first []byte
+	for i := 0; i < 10; i++ {
+		doc2, err := FromV3(doc3)
+		require.NoError(t, err)
+		data, err := json.Marshal(doc2)
+		require.NoError(t, err)
+		if first == nil {
+			first = data
+			var names []string
+			for _, parameter := range doc2.Paths["/pets"].Post.Parameters {
+				names = append(names, parameter.Name)
+			}
+			require.Equal(t, []string{"age", "color", "name", "photo"}, names)
+			continue
+		}
+		require.Equal(t, string(first), string(data))
+	}
+}
+
+func TestFromV3SchemaRefLeavesSchemaAsIs(t *testing.T) {
+	schema := &openapi3.Schema{
+		Nullable:   true,
+		Properties: openapi3.NewSchemas().With("b", openapi3.NewSchemaRef("#/components/schemas/B", nil)).With("a", openapi3.NewStringSchema().NewRef()),
+		AllOf:      openapi3.SchemaRefs{openapi3.NewSchemaRef("#/components/schemas/A", nil)},
+		OneOf:      openapi3.SchemaRefs{openapi3.NewObjectSchema().NewRef(), openapi3.NewSchemaRef("#/components/schemas/B", nil)},
+		AnyOf:      openapi3.SchemaRefs{openapi3.NewObjectSchema().NewRef()},
+	}
+	schema.Properties.InsertBefore("b", "c", openapi3.NewIntegerSchema().NewRef())
+	before, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	converted, _ := FromV3SchemaRef(schema.NewRef(), &openapi3.Components{})
+	after, err := json.Marshal(schema)
+	require.NoError(t, err)
+	require.JSONEq(t, string(before), string(after))
+
+	data, err := json.Marshal(converted)
+	require.NoError(t, err)
+	require.Equal(t, `{"allOf":[{"$ref":"#/definitions/A"},{"type":"object"}],"properties":{"a":{"type":"string"},"c":{"type":"integer"},"b":{"$ref":"#/definitions/B"}},"x-nullable":true,"x-oneOf":[{"type":"object"},{"$ref":"#/definitions/B"}]}`, string(data))
+}
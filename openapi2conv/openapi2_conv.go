@@ -48,7 +48,7 @@ func ToV3(doc2 *openapi2.T) (*openapi3.T, error) {
 		}
 	}
 
-	doc3.Components.Schemas = make(map[string]*openapi3.SchemaRef)
+	doc3.Components.Schemas = openapi3.NewSchemas()
 	if parameters := doc2.Parameters; len(parameters) != 0 {
 		doc3.Components.Parameters = make(map[string]*openapi3.ParameterRef)
 		doc3.Components.RequestBodies = make(map[string]*openapi3.RequestBodyRef)
@@ -61,7 +61,7 @@ func ToV3(doc2 *openapi2.T) (*openapi3.T, error) {
 				doc3.Components.RequestBodies[k] = v3RequestBody
 			case v3SchemaMap != nil:
 				for _, v3Schema := range v3SchemaMap {
-					doc3.Components.Schemas[k] = v3Schema
+					doc3.Components.Schemas.Set(k, v3Schema)
 				}
 			default:
 				doc3.Components.Parameters[k] = v3Parameter
@@ -70,30 +70,30 @@ func ToV3(doc2 *openapi2.T) (*openapi3.T, error) {
 	}
 
 	if paths := doc2.Paths; len(paths) != 0 {
-		doc3Paths := make(map[string]*openapi3.PathItem, len(paths))
+		doc3Paths := openapi3.NewPaths()
 		for path, pathItem := range paths {
 			r, err := ToV3PathItem(doc2, &doc3.Components, pathItem, doc2.Consumes)
 			if err != nil {
 				return nil, err
 			}
-			doc3Paths[path] = r
+			doc3Paths.Set(path, r)
 		}
 		doc3.Paths = doc3Paths
 	}
 
 	if responses := doc2.Responses; len(responses) != 0 {
-		doc3.Components.Responses = make(map[string]*openapi3.ResponseRef, len(responses))
+		doc3.Components.Responses = &openapi3.Responses{}
 		for k, response := range responses {
 			r, err := ToV3Response(response, doc2.Produces)
 			if err != nil {
 				return nil, err
 			}
-			doc3.Components.Responses[k] = r
+			doc3.Components.Responses.Set(k, r)
 		}
 	}
 
 	for key, schema := range ToV3Schemas(doc2.Definitions) {
-		doc3.Components.Schemas[key] = schema
+		doc3.Components.Schemas.Set(key, schema)
 	}
 
 	if m := doc2.SecurityDefinitions; len(m) != 0 {
@@ -191,13 +191,13 @@ func ToV3Operation(doc2 *openapi2.T, components *openapi3.Components, pathItem *
 	}
 
 	if responses := operation.Responses; responses != nil {
-		doc3Responses := make(openapi3.Responses, len(responses))
+		doc3Responses := &openapi3.Responses{}
 		for k, response := range responses {
 			doc3, err := ToV3Response(response, operation.Produces)
 			if err != nil {
 				return nil, err
 			}
-			doc3Responses[k] = doc3
+			doc3Responses.Set(k, doc3)
 		}
 		doc3.Responses = doc3Responses
 	}
@@ -218,7 +218,7 @@ func ToV3Parameter(components *openapi3.Components, parameter *openapi2.Paramete
 			if _, ok := components.RequestBodies[name]; ok {
 				v3Ref := strings.Replace(ref, "#/parameters/", "#/components/requestBodies/", 1)
 				return nil, &openapi3.RequestBodyRef{Ref: v3Ref}, nil, nil
-			} else if schema, ok := components.Schemas[name]; ok {
+			} else if schema := components.Schemas.Value(name); schema != nil {
 				schemaRefMap := make(map[string]*openapi3.SchemaRef)
 				if val, ok := schema.Value.Extensions["x-formData-name"]; ok {
 					name = val.(string)
@@ -331,10 +331,37 @@ func ToV3Parameter(components *openapi3.Components, parameter *openapi2.Paramete
 				Ref: schemaRefRef,
 			}),
 		}
+		if parameter.Type == "array" {
+			toV3CollectionFormat(result, parameter.CollectionFormat)
+		}
 		return &openapi3.ParameterRef{Value: result}, nil, nil, nil
 	}
 }
 
+// toV3CollectionFormat sets the style and explode of an array query parameter
+// according to its v2 collectionFormat, which defaults to "csv" unlike the v3 serialization.
+// "tsv" has no v3 equivalent and is left to the v3 defaults.
+func toV3CollectionFormat(parameter *openapi3.Parameter, collectionFormat string) {
+	if parameter.In != openapi3.ParameterInQuery {
+		return
+	}
+	explode := false
+	switch collectionFormat {
+	case "", "csv":
+		parameter.Style = openapi3.SerializationForm
+	case "ssv":
+		parameter.Style = openapi3.SerializationSpaceDelimited
+	case "pipes":
+		parameter.Style = openapi3.SerializationPipeDelimited
+	case "multi":
+		parameter.Style = openapi3.SerializationForm
+		explode = true
+	default:
+		return
+	}
+	parameter.Explode = &explode
+}
+
 func formDataBody(bodies map[string]*openapi3.SchemaRef, reqs map[string]bool, consumes []string) *openapi3.RequestBodyRef {
 	if len(bodies) != len(reqs) {
 		panic(`request bodies and them being required must match`)
@@ -348,9 +375,14 @@ func formDataBody(bodies map[string]*openapi3.SchemaRef, reqs map[string]bool, c
 			requireds = append(requireds, propName)
 		}
 	}
+	sort.Strings(requireds)
+	properties := openapi3.NewSchemas()
+	for propName, propSchema := range ToV3Schemas(bodies) {
+		properties.Set(propName, propSchema)
+	}
 	schema := &openapi3.Schema{
 		Type:       "object",
-		Properties: ToV3Schemas(bodies),
+		Properties: properties,
 		Required:   requireds,
 	}
 	return &openapi3.RequestBodyRef{
@@ -384,7 +416,7 @@ func onlyOneReqBodyParam(bodies []*openapi3.RequestBodyRef, formDataSchemas map[
 		for formDataName, formDataSchema := range formDataSchemas {
 			if formDataSchema.Ref != "" {
 				name := getParameterNameFromNewRef(formDataSchema.Ref)
-				if schema := components.Schemas[name]; schema != nil && schema.Value != nil {
+				if schema := components.Schemas.Value(name); schema != nil && schema.Value != nil {
 					if tempName, ok := schema.Value.Extensions["x-formData-name"]; ok {
 						name = tempName.(string)
 					}
@@ -476,8 +508,8 @@ func ToV3SchemaRef(schema *openapi3.SchemaRef) *openapi3.SchemaRef {
 	if schema.Value.Items != nil {
 		schema.Value.Items = ToV3SchemaRef(schema.Value.Items)
 	}
-	for k, v := range schema.Value.Properties {
-		schema.Value.Properties[k] = ToV3SchemaRef(v)
+	for k, v := range schema.Value.Properties.Map() {
+		schema.Value.Properties.Set(k, ToV3SchemaRef(v))
 	}
 	if v := schema.Value.AdditionalProperties; v != nil {
 		schema.Value.AdditionalProperties = ToV3SchemaRef(v)
@@ -585,13 +617,27 @@ func ToV3SecurityScheme(securityScheme *openapi2.SecurityScheme) (*openapi3.Secu
 }
 
 // FromV3 converts an OpenAPIv3 spec to an OpenAPIv2 spec
+//
+// Elements without an OpenAPIv2 equivalent are converted as follows:
+//   - oneOf and anyOf with a single schema are flattened into allOf,
+//     otherwise they are kept in "x-oneOf" and "x-anyOf" extensions.
+//   - cookie parameters are kept in an "x-cookieParameters" extension
+//     of the document, path item or operation declaring them.
+//   - callbacks are kept as OpenAPIv3 in an "x-callbacks" extension
+//     of the document or operation declaring them.
+//   - of several request body content types, the "application/json" one
+//     (or else the first one in lexical order) provides the body parameter.
+//     Form content types take precedence over the others.
+//   - of several response content types, the "application/json" one
+//     (or else the first one in lexical order) provides the response schema
+//     and all of them are listed in the operation's produces.
 func FromV3(doc3 *openapi3.T) (*openapi2.T, error) {
-	doc2Responses, err := FromV3Responses(doc3.Components.Responses, &doc3.Components)
+	doc2Responses, err := FromV3Responses(doc3.Components.Responses.Map(), &doc3.Components)
 	if err != nil {
 		return nil, err
 	}
 	stripNonCustomExtensions(doc3.Extensions)
-	schemas, parameters := FromV3Schemas(doc3.Components.Schemas, &doc3.Components)
+	schemas, parameters := FromV3Schemas(doc3.Components.Schemas.Map(), &doc3.Components)
 	doc2 := &openapi2.T{
 		Swagger:        "2.0",
 		Info:           *doc3.Info,
@@ -628,7 +674,7 @@ func FromV3(doc3 *openapi3.T) (*openapi2.T, error) {
 	if isHTTP {
 		doc2.Schemes = append(doc2.Schemes, "http")
 	}
-	for path, pathItem := range doc3.Paths {
+	for path, pathItem := range doc3.Paths.Map() {
 		if pathItem == nil {
 			continue
 		}
@@ -645,25 +691,43 @@ func FromV3(doc3 *openapi3.T) (*openapi2.T, error) {
 			}
 			doc2.AddOperation(path, method, doc2Operation)
 		}
-		params := openapi2.Parameters{}
-		for _, param := range pathItem.Parameters {
-			p, err := FromV3Parameter(param, &doc3.Components)
-			if err != nil {
-				return nil, err
-			}
-			params = append(params, p)
+		params, cookieParams, err := fromV3Parameters(pathItem.Parameters, &doc3.Components)
+		if err != nil {
+			return nil, err
 		}
 		sort.Sort(params)
 		doc2.Paths[path].Parameters = params
+		if len(cookieParams) != 0 {
+			doc2.Paths[path].ExtensionProps = withExtension(doc2.Paths[path].ExtensionProps, extCookieParameters, cookieParams)
+		}
 	}
 
+	cookieParams := make(map[string]*openapi2.Parameter)
 	for name, param := range doc3.Components.Parameters {
-		if doc2.Parameters[name], err = FromV3Parameter(param, &doc3.Components); err != nil {
+		p, err := FromV3Parameter(param, &doc3.Components)
+		if err != nil {
 			return nil, err
 		}
+		if param.Value != nil && param.Value.In == openapi3.ParameterInCookie {
+			cookieParams[name] = p
+			continue
+		}
+		doc2.Parameters[name] = p
+	}
+	if len(cookieParams) != 0 {
+		doc2.ExtensionProps = withExtension(doc2.ExtensionProps, extCookieParameters, cookieParams)
+	}
+	if callbacks := doc3.Components.Callbacks; len(callbacks) != 0 {
+		doc2.ExtensionProps = withExtension(doc2.ExtensionProps, extCallbacks, callbacks)
 	}
 
-	for name, requestBodyRef := range doc3.Components.RequestBodies {
+	requestBodyNames := make([]string, 0, len(doc3.Components.RequestBodies))
+	for name := range doc3.Components.RequestBodies {
+		requestBodyNames = append(requestBodyNames, name)
+	}
+	sort.Strings(requestBodyNames)
+	for _, name := range requestBodyNames {
+		requestBodyRef := doc3.Components.RequestBodies[name]
 		bodyOrRefParameters, formDataParameters, consumes, err := fromV3RequestBodies(name, requestBodyRef, &doc3.Components)
 		if err != nil {
 			return nil, err
@@ -699,6 +763,42 @@ func FromV3(doc3 *openapi3.T) (*openapi2.T, error) {
 	return doc2, nil
 }
 
+const (
+	extOneOf            = "x-oneOf"
+	extAnyOf            = "x-anyOf"
+	extCookieParameters = "x-cookieParameters"
+	extCallbacks        = "x-callbacks"
+)
+
+// withExtension returns a copy of props with the extension name set to value,
+// so that the OpenAPIv3 element the extensions were taken from is left unmodified.
+func withExtension(props openapi3.ExtensionProps, name string, value interface{}) openapi3.ExtensionProps {
+	extensions := make(map[string]interface{}, len(props.Extensions)+1)
+	for k, v := range props.Extensions {
+		extensions[k] = v
+	}
+	extensions[name] = value
+	return openapi3.ExtensionProps{Extensions: extensions}
+}
+
+// fromV3Parameters converts parameters, returning cookie parameters separately
+// as OpenAPIv2 does not support them.
+func fromV3Parameters(parameters openapi3.Parameters, components *openapi3.Components) (params, cookieParams openapi2.Parameters, err error) {
+	params = openapi2.Parameters{}
+	for _, parameter := range parameters {
+		p, err := FromV3Parameter(parameter, components)
+		if err != nil {
+			return nil, nil, err
+		}
+		if parameter.Value != nil && parameter.Value.In == openapi3.ParameterInCookie {
+			cookieParams = append(cookieParams, p)
+			continue
+		}
+		params = append(params, p)
+	}
+	return params, cookieParams, nil
+}
+
 func consumesToArray(consumes map[string]struct{}) []string {
 	consumesArr := make([]string, 0, len(consumes))
 	for key := range consumes {
@@ -721,7 +821,8 @@ func fromV3RequestBodies(name string, requestBodyRef *openapi3.RequestBodyRef, c
 
 	//Only select one formData or request body for an individual requestBody as OpenAPI 2 does not support multiples
 	if requestBodyRef.Value != nil {
-		for contentType, mediaType := range requestBodyRef.Value.Content {
+		for _, contentType := range requestBodyRef.Value.Content.MediaTypes() {
+			mediaType := requestBodyRef.Value.Content[contentType]
 			if consumes == nil {
 				consumes = make(map[string]struct{})
 			}
@@ -767,7 +868,7 @@ func FromV3Schemas(schemas map[string]*openapi3.SchemaRef, components *openapi3.
 func FromV3SchemaRef(schema *openapi3.SchemaRef, components *openapi3.Components) (*openapi3.SchemaRef, *openapi2.Parameter) {
 	if ref := schema.Ref; ref != "" {
 		name := getParameterNameFromNewRef(ref)
-		if val, ok := components.Schemas[name]; ok {
+		if val := components.Schemas.Value(name); val != nil {
 			if val.Value.Format == "binary" {
 				v2Ref := strings.Replace(ref, "#/components/schemas/", "#/parameters/", 1)
 				return nil, &openapi2.Parameter{Ref: v2Ref}
@@ -817,29 +918,58 @@ func FromV3SchemaRef(schema *openapi3.SchemaRef, components *openapi3.Components
 			}
 		}
 	}
-	if v := schema.Value.Items; v != nil {
-		schema.Value.Items, _ = FromV3SchemaRef(v, components)
+	// The schema is converted into a copy, leaving that of the caller as it is
+	converted := *schema.Value
+	if v := converted.Items; v != nil {
+		converted.Items, _ = FromV3SchemaRef(v, components)
 	}
-	keys := make([]string, 0, len(schema.Value.Properties))
-	for k := range schema.Value.Properties {
-		keys = append(keys, k)
+	if converted.Properties != nil {
+		properties, last := openapi3.NewSchemas(), ""
+		for _, key := range converted.Properties.InOrder() {
+			property, _ := FromV3SchemaRef(converted.Properties.Value(key), components)
+			properties.InsertAfter(last, key, property)
+			last = key
+		}
+		converted.Properties = properties
 	}
-	sort.Strings(keys)
-	for _, key := range keys {
-		schema.Value.Properties[key], _ = FromV3SchemaRef(schema.Value.Properties[key], components)
+	if v := converted.AdditionalProperties; v != nil {
+		converted.AdditionalProperties, _ = FromV3SchemaRef(v, components)
 	}
-	if v := schema.Value.AdditionalProperties; v != nil {
-		schema.Value.AdditionalProperties, _ = FromV3SchemaRef(v, components)
+	converted.AllOf = make(openapi3.SchemaRefs, 0, len(schema.Value.AllOf))
+	for _, v := range schema.Value.AllOf {
+		v, _ = FromV3SchemaRef(v, components)
+		converted.AllOf = append(converted.AllOf, v)
 	}
-	for i, v := range schema.Value.AllOf {
-		schema.Value.AllOf[i], _ = FromV3SchemaRef(v, components)
+	converted.OneOf, converted.AnyOf = nil, nil
+	fromV3Alternatives(&converted, extOneOf, schema.Value.OneOf, components)
+	fromV3Alternatives(&converted, extAnyOf, schema.Value.AnyOf, components)
+	if len(converted.AllOf) == 0 {
+		converted.AllOf = nil
 	}
-	if schema.Value.Nullable {
-		schema.Value.Nullable = false
-		schema.Value.Extensions["x-nullable"] = true
+	if converted.Nullable {
+		converted.Nullable = false
+		converted.ExtensionProps = withExtension(converted.ExtensionProps, "x-nullable", true)
 	}
 
-	return schema, nil
+	return &openapi3.SchemaRef{Value: &converted}, nil
+}
+
+// fromV3Alternatives converts the oneOf or anyOf schemas of schema, unsupported by OpenAPIv2.
+// A single alternative is flattened into allOf, several are kept in the extension ext.
+func fromV3Alternatives(schema *openapi3.Schema, ext string, alternatives openapi3.SchemaRefs, components *openapi3.Components) {
+	if len(alternatives) == 0 {
+		return
+	}
+	converted := make(openapi3.SchemaRefs, 0, len(alternatives))
+	for _, v := range alternatives {
+		v, _ = FromV3SchemaRef(v, components)
+		converted = append(converted, v)
+	}
+	if len(converted) == 1 {
+		schema.AllOf = append(schema.AllOf, converted[0])
+		return
+	}
+	schema.ExtensionProps = withExtension(schema.ExtensionProps, ext, converted)
 }
 
 func FromV3SecurityRequirements(requirements openapi3.SecurityRequirements) openapi2.SecurityRequirements {
@@ -865,12 +995,13 @@ func FromV3PathItem(doc3 *openapi3.T, pathItem *openapi3.PathItem) (*openapi2.Pa
 		}
 		result.SetOperation(method, r)
 	}
-	for _, parameter := range pathItem.Parameters {
-		p, err := FromV3Parameter(parameter, &doc3.Components)
-		if err != nil {
-			return nil, err
-		}
-		result.Parameters = append(result.Parameters, p)
+	params, cookieParams, err := fromV3Parameters(pathItem.Parameters, &doc3.Components)
+	if err != nil {
+		return nil, err
+	}
+	result.Parameters = append(result.Parameters, params...)
+	if len(cookieParams) != 0 {
+		result.ExtensionProps = withExtension(result.ExtensionProps, extCookieParameters, cookieParams)
 	}
 	return result, nil
 }
@@ -891,7 +1022,9 @@ nameSearch:
 
 func FromV3RequestBodyFormData(mediaType *openapi3.MediaType) openapi2.Parameters {
 	parameters := openapi2.Parameters{}
-	for propName, schemaRef := range mediaType.Schema.Value.Properties {
+	properties := mediaType.Schema.Value.Properties
+	for _, propName := range properties.InSortedOrder() {
+		schemaRef := properties.Value(propName)
 		if ref := schemaRef.Ref; ref != "" {
 			v2Ref := strings.Replace(ref, "#/components/schemas/", "#/parameters/", 1)
 			parameters = append(parameters, &openapi2.Parameter{Ref: v2Ref})
@@ -956,12 +1089,16 @@ func FromV3Operation(doc3 *openapi3.T, operation *openapi3.Operation) (*openapi2
 		resultSecurity := FromV3SecurityRequirements(*v)
 		result.Security = &resultSecurity
 	}
-	for _, parameter := range operation.Parameters {
-		r, err := FromV3Parameter(parameter, &doc3.Components)
-		if err != nil {
-			return nil, err
-		}
-		result.Parameters = append(result.Parameters, r)
+	params, cookieParams, err := fromV3Parameters(operation.Parameters, &doc3.Components)
+	if err != nil {
+		return nil, err
+	}
+	result.Parameters = append(result.Parameters, params...)
+	if len(cookieParams) != 0 {
+		result.ExtensionProps = withExtension(result.ExtensionProps, extCookieParameters, cookieParams)
+	}
+	if len(operation.Callbacks) != 0 {
+		result.ExtensionProps = withExtension(result.ExtensionProps, extCallbacks, operation.Callbacks)
 	}
 	if v := operation.RequestBody; v != nil {
 		// Find parameter name that we can use for the body
@@ -991,15 +1128,34 @@ func FromV3Operation(doc3 *openapi3.T, operation *openapi3.Operation) (*openapi2
 	sort.Sort(result.Parameters)
 
 	if responses := operation.Responses; responses != nil {
-		resultResponses, err := FromV3Responses(responses, &doc3.Components)
+		resultResponses, err := FromV3Responses(responses.Map(), &doc3.Components)
 		if err != nil {
 			return nil, err
 		}
 		result.Responses = resultResponses
+		result.Produces = fromV3Produces(responses)
 	}
 	return result, nil
 }
 
+// fromV3Produces returns the content types of responses, in lexical order,
+// or nil if "application/json" is the only one.
+func fromV3Produces(responses *openapi3.Responses) []string {
+	produces := make(map[string]struct{})
+	for _, response := range responses.Map() {
+		if response.Value == nil {
+			continue
+		}
+		for contentType := range response.Value.Content {
+			produces[contentType] = struct{}{}
+		}
+	}
+	if _, ok := produces["application/json"]; len(produces) == 0 || (ok && len(produces) == 1) {
+		return nil
+	}
+	return consumesToArray(produces)
+}
+
 func FromV3RequestBody(name string, requestBodyRef *openapi3.RequestBodyRef, mediaType *openapi3.MediaType, components *openapi3.Components) (*openapi2.Parameter, error) {
 	requestBody := requestBodyRef.Value
 
@@ -1056,13 +1212,39 @@ func FromV3Parameter(ref *openapi3.ParameterRef, components *openapi3.Components
 		result.MinItems = schema.MinItems
 		result.MaxItems = schema.MaxItems
 		result.AllowEmptyValue = schema.AllowEmptyValue
-		// result.CollectionFormat = schema.CollectionFormat
+		if schema.Type == "array" {
+			result.CollectionFormat = fromV3CollectionFormat(parameter)
+		}
 		result.UniqueItems = schema.UniqueItems
 		result.MultipleOf = schema.MultipleOf
 	}
 	return result, nil
 }
 
+// fromV3CollectionFormat returns the v2 collectionFormat matching the style and explode
+// of an array query parameter, or an empty string if these are not set.
+func fromV3CollectionFormat(parameter *openapi3.Parameter) string {
+	if parameter.In != openapi3.ParameterInQuery || (parameter.Style == "" && parameter.Explode == nil) {
+		return ""
+	}
+	sm, err := parameter.SerializationMethod()
+	if err != nil {
+		return ""
+	}
+	switch sm.Style {
+	case openapi3.SerializationForm:
+		if sm.Explode {
+			return "multi"
+		}
+		return "csv"
+	case openapi3.SerializationSpaceDelimited:
+		return "ssv"
+	case openapi3.SerializationPipeDelimited:
+		return "pipes"
+	}
+	return ""
+}
+
 func FromV3Responses(responses map[string]*openapi3.ResponseRef, components *openapi3.Components) (map[string]*openapi2.Response, error) {
 	v2Responses := make(map[string]*openapi2.Response, len(responses))
 	for k, response := range responses {
@@ -1093,9 +1275,10 @@ func FromV3Response(ref *openapi3.ResponseRef, components *openapi3.Components)
 		Description:    description,
 		ExtensionProps: response.ExtensionProps,
 	}
-	if content := response.Content; content != nil {
-		if ct := content["application/json"]; ct != nil {
+	for _, contentType := range response.Content.MediaTypes() {
+		if ct := response.Content[contentType]; ct != nil && ct.Schema != nil {
 			result.Schema, _ = FromV3SchemaRef(ct.Schema, components)
+			break
 		}
 	}
 	if headers := response.Headers; len(headers) > 0 {
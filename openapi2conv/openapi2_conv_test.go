@@ -167,6 +167,7 @@ const exampleV2 = `
 				"operationId": "example-delete",
 				"parameters": [
 					{
+						"collectionFormat": "csv",
 						"description": "Only return results that intersect the provided bounding box.",
 						"in": "query",
 						"items": {
@@ -530,6 +531,7 @@ const exampleV3 = `
 				"parameters": [
 					{
 						"description": "Only return results that intersect the provided bounding box.",
+						"explode": false,
 						"in": "query",
 						"name": "bbox",
 						"schema": {
@@ -539,7 +541,8 @@ const exampleV3 = `
 							"maxItems": 4,
 							"minItems": 4,
 							"type": "array"
-						}
+						},
+						"style": "form"
 					},
 					{
 						"in": "query",
@@ -0,0 +1,7 @@
+// Package openapi2filter validates that requests and responses conform to
+// an OpenAPIv2 (Swagger 2.0) specification document.
+//
+// The document is converted once to OpenAPIv3 with openapi2conv and validation
+// is then delegated to openapi3filter, so the same options, errors and
+// middleware apply.
+package openapi2filter
@@ -0,0 +1,106 @@
+package openapi2filter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// Filter validates HTTP requests and responses against an OpenAPIv2 document.
+type Filter struct {
+	doc    *openapi3.T
+	router routers.Router
+
+	// Options are passed to openapi3filter. DefaultOptions are used when nil.
+	Options *openapi3filter.Options
+}
+
+// NewFilter converts doc2 to OpenAPIv3, validates the result and builds a router for it.
+// doc2 is left unmodified.
+func NewFilter(doc2 *openapi2.T) (*Filter, error) {
+	data, err := json.Marshal(doc2)
+	if err != nil {
+		return nil, err
+	}
+	var clone openapi2.T
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, err
+	}
+
+	doc, err := openapi2conv.ToV3(&clone)
+	if err != nil {
+		return nil, err
+	}
+	if doc2.Host == "" && doc2.BasePath != "" {
+		// Without a host, openapi2conv does not produce a server for the base path
+		doc.AddServer(&openapi3.Server{URL: doc2.BasePath})
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &Filter{doc: doc, router: router}, nil
+}
+
+// Document returns the OpenAPIv3 document requests and responses are validated against.
+func (f *Filter) Document() *openapi3.T { return f.doc }
+
+// Router returns the router matching requests to the operations of the document.
+func (f *Filter) Router() routers.Router { return f.router }
+
+func (f *Filter) options() *openapi3filter.Options {
+	if f.Options != nil {
+		return f.Options
+	}
+	return openapi3filter.DefaultOptions
+}
+
+// ValidateRequest finds the route of req then validates req against it.
+// The returned input is to be passed to ValidateResponse and is returned
+// even when validation fails, as long as a route was found.
+func (f *Filter) ValidateRequest(ctx context.Context, req *http.Request) (*openapi3filter.RequestValidationInput, error) {
+	route, pathParams, err := f.router.FindRoute(req)
+	if err != nil {
+		return nil, err
+	}
+	input := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    f.options(),
+	}
+	return input, openapi3filter.ValidateRequest(ctx, input)
+}
+
+// ValidateResponse validates a response to the request described by input.
+func (f *Filter) ValidateResponse(ctx context.Context, input *openapi3filter.RequestValidationInput, status int, header http.Header, body []byte) error {
+	return openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 status,
+		Header:                 header,
+		Body:                   ioutil.NopCloser(bytes.NewReader(body)),
+		Options:                f.options(),
+	})
+}
+
+// Middleware returns an HTTP middleware validating requests and responses.
+// See openapi3filter.Validator.
+func (f *Filter) Middleware(options ...openapi3filter.ValidatorOption) func(http.Handler) http.Handler {
+	if f.Options != nil {
+		options = append([]openapi3filter.ValidatorOption{openapi3filter.ValidationOptions(*f.Options)}, options...)
+	}
+	return openapi3filter.NewValidator(f.router, options...).Middleware
+}
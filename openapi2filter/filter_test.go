@@ -0,0 +1,125 @@
+package openapi2filter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2filter"
+)
+
+const spec = `
+{
+  "swagger": "2.0",
+  "info": {"title": "Pets", "version": "1.0"},
+  "basePath": "/v1",
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {"name": "ids", "in": "query", "type": "array", "items": {"type": "integer"}, "maxItems": 2},
+          {"name": "tags", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"}
+        ],
+        "responses": {
+          "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}
+        }
+      },
+      "post": {
+        "parameters": [
+          {"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}
+        ],
+        "responses": {"201": {"description": "Created"}}
+      }
+    },
+    "/pets/{id}": {
+      "parameters": [
+        {"name": "id", "in": "path", "required": true, "type": "integer", "x-custom": true}
+      ],
+      "get": {
+        "responses": {"200": {"description": "OK", "schema": {"$ref": "#/definitions/Pet"}}}
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {"name": {"type": "string"}}
+    }
+  }
+}
+`
+
+func TestFilter(t *testing.T) {
+	var doc2 openapi2.T
+	err := json.Unmarshal([]byte(spec), &doc2)
+	require.NoError(t, err)
+
+	filter, err := openapi2filter.NewFilter(&doc2)
+	require.NoError(t, err)
+	require.Contains(t, doc2.Paths["/pets/{id}"].Parameters[0].Extensions, "x-custom")
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		method, target, body string
+		ok                   bool
+	}{
+		{http.MethodGet, "/v1/pets?ids=1,2", "", true},
+		{http.MethodGet, "/v1/pets?ids=1,2,3", "", false},
+		{http.MethodGet, "/v1/pets?ids=1,x", "", false},
+		{http.MethodGet, "/v1/pets?tags=a&tags=b", "", true},
+		{http.MethodGet, "/v1/pets/42", "", true},
+		{http.MethodGet, "/v1/pets/x", "", false},
+		{http.MethodPost, "/v1/pets", `{"name":"Fido"}`, true},
+		{http.MethodPost, "/v1/pets", `{}`, false},
+	} {
+		t.Run(tc.method+" "+tc.target, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			_, err := filter.ValidateRequest(ctx, req)
+			if tc.ok {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/pets/42", nil)
+	input, err := filter.ValidateRequest(ctx, req)
+	require.NoError(t, err)
+	header := http.Header{"Content-Type": []string{"application/json"}}
+	err = filter.ValidateResponse(ctx, input, 200, header, []byte(`{"name":"Fido"}`))
+	require.NoError(t, err)
+	err = filter.ValidateResponse(ctx, input, 200, header, []byte(`{"name":42}`))
+	require.Error(t, err)
+}
+
+func TestFilterMiddleware(t *testing.T) {
+	var doc2 openapi2.T
+	err := json.Unmarshal([]byte(spec), &doc2)
+	require.NoError(t, err)
+	filter, err := openapi2filter.NewFilter(&doc2)
+	require.NoError(t, err)
+
+	h := filter.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"name":"Fido"}]`))
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/pets?ids=1", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/pets?ids=a", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,527 @@
+package openapi3
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// Clone returns a deep copy of doc, which can be modified without modifying doc,
+// e.g. to derive a variant of a document shared by several users.
+//
+// References are preserved: the copy of a referencing value, e.g. a SchemaRef,
+// keeps its Ref and points to the copy of the value it references, so values
+// shared in doc, such as the component schemas, are shared in the copy too.
+// Cyclic schemas are copied as such. Caches doc keeps, such as compiled patterns,
+// are not copied but computed again when the copy needs them.
+func (doc *T) Clone() *T {
+	if doc == nil {
+		return nil
+	}
+	return cloneValue(doc).(*T)
+}
+
+// Clone returns a deep copy of pathItem (see T.Clone).
+func (pathItem *PathItem) Clone() *PathItem {
+	if pathItem == nil {
+		return nil
+	}
+	return cloneValue(pathItem).(*PathItem)
+}
+
+// Clone returns a deep copy of operation (see T.Clone).
+func (operation *Operation) Clone() *Operation {
+	if operation == nil {
+		return nil
+	}
+	return cloneValue(operation).(*Operation)
+}
+
+// Clone returns a deep copy of parameter (see T.Clone).
+func (parameter *Parameter) Clone() *Parameter {
+	if parameter == nil {
+		return nil
+	}
+	return cloneValue(parameter).(*Parameter)
+}
+
+// Clone returns a deep copy of requestBody (see T.Clone).
+func (requestBody *RequestBody) Clone() *RequestBody {
+	if requestBody == nil {
+		return nil
+	}
+	return cloneValue(requestBody).(*RequestBody)
+}
+
+// Clone returns a deep copy of response (see T.Clone).
+func (response *Response) Clone() *Response {
+	if response == nil {
+		return nil
+	}
+	return cloneValue(response).(*Response)
+}
+
+// Clone returns a deep copy of schema (see T.Clone).
+func (schema *Schema) Clone() *Schema {
+	if schema == nil {
+		return nil
+	}
+	return cloneValue(schema).(*Schema)
+}
+
+// Clone returns a deep copy of CallbackRef (see T.Clone).
+func (value *CallbackRef) Clone() *CallbackRef {
+	if value == nil {
+		return nil
+	}
+	return cloneValue(value).(*CallbackRef)
+}
+
+// Clone returns a deep copy of components (see T.Clone).
+func (components *Components) Clone() *Components {
+	if components == nil {
+		return nil
+	}
+	return cloneValue(components).(*Components)
+}
+
+// Clone returns a deep copy of contact (see T.Clone).
+func (contact *Contact) Clone() *Contact {
+	if contact == nil {
+		return nil
+	}
+	return cloneValue(contact).(*Contact)
+}
+
+// Clone returns a deep copy of discriminator (see T.Clone).
+func (discriminator *Discriminator) Clone() *Discriminator {
+	if discriminator == nil {
+		return nil
+	}
+	return cloneValue(discriminator).(*Discriminator)
+}
+
+// Clone returns a deep copy of encoding (see T.Clone).
+func (encoding *Encoding) Clone() *Encoding {
+	if encoding == nil {
+		return nil
+	}
+	return cloneValue(encoding).(*Encoding)
+}
+
+// Clone returns a deep copy of example (see T.Clone).
+func (example *Example) Clone() *Example {
+	if example == nil {
+		return nil
+	}
+	return cloneValue(example).(*Example)
+}
+
+// Clone returns a deep copy of ExampleRef (see T.Clone).
+func (value *ExampleRef) Clone() *ExampleRef {
+	if value == nil {
+		return nil
+	}
+	return cloneValue(value).(*ExampleRef)
+}
+
+// Clone returns a deep copy of ExternalDocs (see T.Clone).
+func (e *ExternalDocs) Clone() *ExternalDocs {
+	if e == nil {
+		return nil
+	}
+	return cloneValue(e).(*ExternalDocs)
+}
+
+// Clone returns a deep copy of header (see T.Clone).
+func (header *Header) Clone() *Header {
+	if header == nil {
+		return nil
+	}
+	return cloneValue(header).(*Header)
+}
+
+// Clone returns a deep copy of HeaderRef (see T.Clone).
+func (value *HeaderRef) Clone() *HeaderRef {
+	if value == nil {
+		return nil
+	}
+	return cloneValue(value).(*HeaderRef)
+}
+
+// Clone returns a deep copy of info (see T.Clone).
+func (info *Info) Clone() *Info {
+	if info == nil {
+		return nil
+	}
+	return cloneValue(info).(*Info)
+}
+
+// Clone returns a deep copy of license (see T.Clone).
+func (license *License) Clone() *License {
+	if license == nil {
+		return nil
+	}
+	return cloneValue(license).(*License)
+}
+
+// Clone returns a deep copy of link (see T.Clone).
+func (link *Link) Clone() *Link {
+	if link == nil {
+		return nil
+	}
+	return cloneValue(link).(*Link)
+}
+
+// Clone returns a deep copy of LinkRef (see T.Clone).
+func (value *LinkRef) Clone() *LinkRef {
+	if value == nil {
+		return nil
+	}
+	return cloneValue(value).(*LinkRef)
+}
+
+// Clone returns a deep copy of mediaType (see T.Clone).
+func (mediaType *MediaType) Clone() *MediaType {
+	if mediaType == nil {
+		return nil
+	}
+	return cloneValue(mediaType).(*MediaType)
+}
+
+// Clone returns a deep copy of flow (see T.Clone).
+func (flow *OAuthFlow) Clone() *OAuthFlow {
+	if flow == nil {
+		return nil
+	}
+	return cloneValue(flow).(*OAuthFlow)
+}
+
+// Clone returns a deep copy of flows (see T.Clone).
+func (flows *OAuthFlows) Clone() *OAuthFlows {
+	if flows == nil {
+		return nil
+	}
+	return cloneValue(flows).(*OAuthFlows)
+}
+
+// Clone returns a deep copy of ParameterRef (see T.Clone).
+func (value *ParameterRef) Clone() *ParameterRef {
+	if value == nil {
+		return nil
+	}
+	return cloneValue(value).(*ParameterRef)
+}
+
+// Clone returns a deep copy of RequestBodyRef (see T.Clone).
+func (value *RequestBodyRef) Clone() *RequestBodyRef {
+	if value == nil {
+		return nil
+	}
+	return cloneValue(value).(*RequestBodyRef)
+}
+
+// Clone returns a deep copy of ResponseRef (see T.Clone).
+func (value *ResponseRef) Clone() *ResponseRef {
+	if value == nil {
+		return nil
+	}
+	return cloneValue(value).(*ResponseRef)
+}
+
+// Clone returns a deep copy of SchemaRef (see T.Clone).
+func (value *SchemaRef) Clone() *SchemaRef {
+	if value == nil {
+		return nil
+	}
+	return cloneValue(value).(*SchemaRef)
+}
+
+// Clone returns a deep copy of SecurityScheme (see T.Clone).
+func (ss *SecurityScheme) Clone() *SecurityScheme {
+	if ss == nil {
+		return nil
+	}
+	return cloneValue(ss).(*SecurityScheme)
+}
+
+// Clone returns a deep copy of SecuritySchemeRef (see T.Clone).
+func (value *SecuritySchemeRef) Clone() *SecuritySchemeRef {
+	if value == nil {
+		return nil
+	}
+	return cloneValue(value).(*SecuritySchemeRef)
+}
+
+// Clone returns a deep copy of server (see T.Clone).
+func (server *Server) Clone() *Server {
+	if server == nil {
+		return nil
+	}
+	return cloneValue(server).(*Server)
+}
+
+// Clone returns a deep copy of serverVariable (see T.Clone).
+func (serverVariable *ServerVariable) Clone() *ServerVariable {
+	if serverVariable == nil {
+		return nil
+	}
+	return cloneValue(serverVariable).(*ServerVariable)
+}
+
+// Clone returns a deep copy of Tag (see T.Clone).
+func (t *Tag) Clone() *Tag {
+	if t == nil {
+		return nil
+	}
+	return cloneValue(t).(*Tag)
+}
+
+// Clone returns a deep copy of XML (see T.Clone).
+func (xml *XML) Clone() *XML {
+	if xml == nil {
+		return nil
+	}
+	return cloneValue(xml).(*XML)
+}
+
+// Clone returns a deep copy of paths (see T.Clone).
+func (paths *Paths) Clone() *Paths {
+	if paths == nil {
+		return nil
+	}
+	return cloneValue(paths).(*Paths)
+}
+
+// Clone returns a deep copy of schemas (see T.Clone).
+func (schemas *Schemas) Clone() *Schemas {
+	if schemas == nil {
+		return nil
+	}
+	return cloneValue(schemas).(*Schemas)
+}
+
+// Clone returns a deep copy of responses (see T.Clone).
+func (responses *Responses) Clone() *Responses {
+	if responses == nil {
+		return nil
+	}
+	return cloneValue(responses).(*Responses)
+}
+
+// Clone returns a deep copy of callback (see T.Clone).
+func (callback Callback) Clone() Callback {
+	return cloneValue(callback).(Callback)
+}
+
+// Clone returns a deep copy of Callbacks (see T.Clone).
+func (c Callbacks) Clone() Callbacks {
+	return cloneValue(c).(Callbacks)
+}
+
+// Clone returns a deep copy of content (see T.Clone).
+func (content Content) Clone() Content {
+	return cloneValue(content).(Content)
+}
+
+// Clone returns a deep copy of Examples (see T.Clone).
+func (e Examples) Clone() Examples {
+	return cloneValue(e).(Examples)
+}
+
+// Clone returns a deep copy of Headers (see T.Clone).
+func (h Headers) Clone() Headers {
+	return cloneValue(h).(Headers)
+}
+
+// Clone returns a deep copy of links (see T.Clone).
+func (links Links) Clone() Links {
+	return cloneValue(links).(Links)
+}
+
+// Clone returns a deep copy of ParametersMap (see T.Clone).
+func (p ParametersMap) Clone() ParametersMap {
+	return cloneValue(p).(ParametersMap)
+}
+
+// Clone returns a deep copy of Parameters (see T.Clone).
+func (p Parameters) Clone() Parameters {
+	return cloneValue(p).(Parameters)
+}
+
+// Clone returns a deep copy of RequestBodies (see T.Clone).
+func (r RequestBodies) Clone() RequestBodies {
+	return cloneValue(r).(RequestBodies)
+}
+
+// Clone returns a deep copy of SchemaRefs (see T.Clone).
+func (s SchemaRefs) Clone() SchemaRefs {
+	return cloneValue(s).(SchemaRefs)
+}
+
+// Clone returns a deep copy of security (see T.Clone).
+func (security SecurityRequirement) Clone() SecurityRequirement {
+	return cloneValue(security).(SecurityRequirement)
+}
+
+// Clone returns a deep copy of SecurityRequirements (see T.Clone).
+func (srs SecurityRequirements) Clone() SecurityRequirements {
+	return cloneValue(srs).(SecurityRequirements)
+}
+
+// Clone returns a deep copy of SecuritySchemes (see T.Clone).
+func (s SecuritySchemes) Clone() SecuritySchemes {
+	return cloneValue(s).(SecuritySchemes)
+}
+
+// Clone returns a deep copy of servers (see T.Clone).
+func (servers Servers) Clone() Servers {
+	return cloneValue(servers).(Servers)
+}
+
+// Clone returns a deep copy of tags (see T.Clone).
+func (tags Tags) Clone() Tags {
+	return cloneValue(tags).(Tags)
+}
+
+// Clone returns a deep copy of webhooks (see T.Clone).
+func (webhooks Webhooks) Clone() Webhooks {
+	return cloneValue(webhooks).(Webhooks)
+}
+
+func cloneValue(v interface{}) interface{} {
+	return newCloner().clone(reflect.ValueOf(v)).Interface()
+}
+
+// clonedPointer identifies a pointer along with its type, as a pointer to a struct
+// and to its first field are equal.
+type clonedPointer struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// cloner copies values deeply, each pointer once so that shared values remain shared.
+type cloner struct {
+	clones map[clonedPointer]reflect.Value
+}
+
+func newCloner() *cloner {
+	return &cloner{clones: make(map[clonedPointer]reflect.Value)}
+}
+
+// clone returns a deep copy of v.
+func (c *cloner) clone(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		key := clonedPointer{typ: v.Type(), ptr: v.Pointer()}
+		if clone, ok := c.clones[key]; ok {
+			return clone
+		}
+		clone := reflect.New(v.Type().Elem())
+		c.clones[key] = clone
+		clone.Elem().Set(v.Elem())
+		c.deepen(clone.Elem())
+		c.deepenCollection(clone)
+		resetCaches(clone)
+		return clone
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		clone := reflect.New(v.Type()).Elem()
+		clone.Set(c.clone(v.Elem()))
+		return clone
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		clone := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			clone.SetMapIndex(iter.Key(), c.clone(iter.Value()))
+		}
+		return clone
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		clone := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(clone, v)
+		for i := 0; i < clone.Len(); i++ {
+			c.deepen(clone.Index(i))
+		}
+		return clone
+	case reflect.Struct, reflect.Array:
+		clone := reflect.New(v.Type()).Elem()
+		clone.Set(v)
+		c.deepen(clone)
+		return clone
+	}
+	return v
+}
+
+// deepen replaces the values v, a settable copy, shares with the value it was
+// copied from by deep copies. Unexported fields are left as copied.
+func (c *cloner) deepen(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Field(i); field.CanSet() {
+				c.deepen(field)
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			c.deepen(v.Index(i))
+		}
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		v.Set(c.clone(v))
+	}
+}
+
+// deepenCollection replaces the items the copy v of a collection, whose fields are unexported,
+// shares with the collection it was copied from by deep copies.
+func (c *cloner) deepenCollection(v reflect.Value) {
+	if !v.CanInterface() {
+		return
+	}
+	switch v := v.Interface().(type) {
+	case *Paths:
+		v.m = c.clone(reflect.ValueOf(v.m)).Interface().(map[string]*PathItem)
+		v.order = cloneOrder(v.order)
+	case *Schemas:
+		v.m = c.clone(reflect.ValueOf(v.m)).Interface().(map[string]*SchemaRef)
+		v.order = cloneOrder(v.order)
+	case *Responses:
+		v.m = c.clone(reflect.ValueOf(v.m)).Interface().(map[string]*ResponseRef)
+		v.order = cloneOrder(v.order)
+	}
+}
+
+// cloneOrder returns a copy of order, the ordered keys of a collection or nil if they are sorted.
+func cloneOrder(order []string) []string {
+	if order == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(order)), order...)
+}
+
+// resetCaches clears what the copy v of a value caches about the value it was copied from.
+func resetCaches(v reflect.Value) {
+	if !v.CanInterface() {
+		return
+	}
+	switch v := v.Interface().(type) {
+	case *T:
+		v.visited = visitedComponent{}
+		v.operationIDs = nil
+	case *Schema:
+		v.enumIndex = atomic.Value{}
+		v.requiredWhen = atomic.Value{}
+		v.validatorNames = atomic.Value{}
+		v.compiledPattern = nil
+		v.validators = append([]SchemaValidatorFunc(nil), v.validators...)
+	}
+}
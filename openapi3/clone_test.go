@@ -0,0 +1,113 @@
+package openapi3
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+      - {name: name, in: query, schema: {type: string, pattern: '^[a-z]+$'}}
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+components:
+  schemas:
+    Pet:
+      type: object
+      x-owner: pets-team
+      properties:
+        name: {type: string, enum: [Garfield, Odie]}
+        friends: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+`
+	doc, err := NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+	before, err := json.Marshal(doc)
+	require.NoError(t, err)
+	_, _, operation := doc.OperationByID("listPets")
+	require.Same(t, doc.Paths.Value("/pets").Get, operation)
+
+	clone := doc.Clone()
+	data, err := json.Marshal(clone)
+	require.NoError(t, err)
+	require.JSONEq(t, string(before), string(data))
+	_, _, operation = clone.OperationByID("listPets")
+	require.Same(t, clone.Paths.Value("/pets").Get, operation)
+
+	pet := clone.Components.Schemas.Value("Pet").Value
+	require.NotSame(t, doc.Components.Schemas.Value("Pet").Value, pet)
+	items := clone.Paths.Value("/pets").Get.Responses.Get(200).Value.Content.Get("application/json").Schema.Value.Items
+	require.Equal(t, "#/components/schemas/Pet", items.Ref)
+	require.Same(t, pet, items.Value)
+	require.Same(t, pet, pet.Properties.Value("friends").Value.Items.Value)
+
+	// Modifying the copy leaves doc as is
+	pet.Properties.Value("name").Value.Enum = append(pet.Properties.Value("name").Value.Enum, "Nermal")
+	pet.Extensions["x-owner"] = "cats-team"
+	clone.Paths.Value("/pets").Get.Parameters[0].Value.Schema.Value.Pattern = "^[A-Z]+$"
+	clone.Paths.Value("/pets").Get.OperationID = "listAnimals"
+	data, err = json.Marshal(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, string(before), string(data))
+
+	// Caches are not shared
+	require.NoError(t, pet.VisitJSON(map[string]interface{}{"name": "Nermal"}))
+	require.Error(t, doc.Components.Schemas.Value("Pet").Value.VisitJSON(map[string]interface{}{"name": "Nermal"}))
+	require.NoError(t, clone.Paths.Value("/pets").Get.Parameters[0].Value.Schema.Value.VisitJSON("ODIE"))
+
+	operation = doc.Paths.Value("/pets").Get.Clone()
+	operation.Parameters[0].Value.Name = "kind"
+	require.Equal(t, "name", doc.Paths.Value("/pets").Get.Parameters[0].Value.Name)
+	require.Nil(t, (*Schema)(nil).Clone())
+}
+
+func TestCloneModelTypes(t *testing.T) {
+	schemaRef := NewSchemaRef("", NewObjectSchema().WithProperty("name", NewStringSchema()))
+	clone := schemaRef.Clone()
+	clone.Value.Properties.Value("name").Value.Type = "integer"
+	require.Equal(t, "string", schemaRef.Value.Properties.Value("name").Value.Type)
+
+	content := NewContentWithJSONSchemaRef(schemaRef)
+	contentClone := content.Clone()
+	contentClone.Get("application/json").Schema.Value.Title = "Pet"
+	require.Empty(t, schemaRef.Value.Title)
+	mediaType := content.Get("application/json").Clone()
+	require.NotSame(t, schemaRef, mediaType.Schema)
+
+	header := &Header{Parameter{Schema: schemaRef}}
+	headerClone := header.Clone()
+	headerClone.Schema.Value.Type = "array"
+	require.Equal(t, "object", header.Schema.Value.Type)
+
+	callback := Callback{"{$request.body#/url}": &PathItem{Post: NewOperation()}}
+	callbackClone := callback.Clone()
+	callbackClone["{$request.body#/url}"].Post.OperationID = "notify"
+	require.Empty(t, callback["{$request.body#/url}"].Post.OperationID)
+
+	components := &Components{Schemas: NewSchemas().With("Pet", schemaRef)}
+	componentsClone := components.Clone()
+	componentsClone.Schemas.Delete("Pet")
+	require.Equal(t, 1, components.Schemas.Len())
+
+	parameters := Parameters{{Value: NewQueryParameter("limit")}}
+	parametersClone := parameters.Clone()
+	parametersClone[0].Value.Name = "offset"
+	require.Equal(t, "limit", parameters[0].Value.Name)
+
+	require.Nil(t, (*SchemaRef)(nil).Clone())
+	require.Nil(t, Content(nil).Clone())
+}
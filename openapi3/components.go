@@ -14,11 +14,11 @@ import (
 type Components struct {
 	ExtensionProps `json:"-" yaml:"-"`
 
-	Schemas         Schemas         `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+	Schemas         *Schemas        `json:"schemas,omitempty" yaml:"schemas,omitempty"`
 	Parameters      ParametersMap   `json:"parameters,omitempty" yaml:"parameters,omitempty"`
 	Headers         Headers         `json:"headers,omitempty" yaml:"headers,omitempty"`
 	RequestBodies   RequestBodies   `json:"requestBodies,omitempty" yaml:"requestBodies,omitempty"`
-	Responses       Responses       `json:"responses,omitempty" yaml:"responses,omitempty"`
+	Responses       *Responses      `json:"responses,omitempty" yaml:"responses,omitempty"`
 	SecuritySchemes SecuritySchemes `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
 	Examples        Examples        `json:"examples,omitempty" yaml:"examples,omitempty"`
 	Links           Links           `json:"links,omitempty" yaml:"links,omitempty"`
@@ -34,6 +34,11 @@ func (components *Components) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(components)
 }
 
+// MarshalYAML returns the YAML encoding of Components.
+func (components *Components) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(components)
+}
+
 // UnmarshalJSON sets Components to a copy of data.
 func (components *Components) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, components)
@@ -43,13 +48,13 @@ func (components *Components) UnmarshalJSON(data []byte) error {
 func (components *Components) Validate(ctx context.Context, opts ...ValidationOption) (err error) {
 	ctx = WithValidationOptions(ctx, opts...)
 
-	schemas := make([]string, 0, len(components.Schemas))
-	for name := range components.Schemas {
+	schemas := make([]string, 0, components.Schemas.Len())
+	for name := range components.Schemas.items() {
 		schemas = append(schemas, name)
 	}
 	sort.Strings(schemas)
 	for _, k := range schemas {
-		v := components.Schemas[k]
+		v := components.Schemas.Value(k)
 		if err = ValidateIdentifier(k); err != nil {
 			return fmt.Errorf("schema %q: %w", k, err)
 		}
@@ -88,13 +93,13 @@ func (components *Components) Validate(ctx context.Context, opts ...ValidationOp
 		}
 	}
 
-	responses := make([]string, 0, len(components.Responses))
-	for name := range components.Responses {
+	responses := make([]string, 0, components.Responses.Len())
+	for name := range components.Responses.items() {
 		responses = append(responses, name)
 	}
 	sort.Strings(responses)
 	for _, k := range responses {
-		v := components.Responses[k]
+		v := components.Responses.Value(k)
 		if err = ValidateIdentifier(k); err != nil {
 			return fmt.Errorf("response %q: %w", k, err)
 		}
@@ -0,0 +1,323 @@
+package openapi3
+
+import (
+	"strconv"
+)
+
+// OperationOption sets a field of the Operation returned by NewOperation,
+// e.g. WithSummary or WithResponse.
+type OperationOption interface {
+	applyToOperation(*Operation)
+}
+
+// ParameterOption sets a field of the Parameter returned by NewPathParameter,
+// NewQueryParameter, NewHeaderParameter or NewCookieParameter, e.g. WithSchema.
+type ParameterOption interface {
+	applyToParameter(*Parameter)
+}
+
+// SchemaOption sets a keyword of the Schema returned by NewSchema, NewIntegerSchema
+// and the other typed schema constructors, e.g. Min or Items.
+type SchemaOption interface {
+	applyToSchema(*Schema)
+}
+
+type operationOption func(*Operation)
+
+func (opt operationOption) applyToOperation(operation *Operation) { opt(operation) }
+
+type parameterOption func(*Parameter)
+
+func (opt parameterOption) applyToParameter(parameter *Parameter) { opt(parameter) }
+
+type schemaOption func(*Schema)
+
+func (opt schemaOption) applyToSchema(schema *Schema) { opt(schema) }
+
+func newOperation(operation *Operation, opts []OperationOption) *Operation {
+	for _, opt := range opts {
+		opt.applyToOperation(operation)
+	}
+	return operation
+}
+
+func newParameter(parameter *Parameter, opts []ParameterOption) *Parameter {
+	for _, opt := range opts {
+		opt.applyToParameter(parameter)
+	}
+	return parameter
+}
+
+func newSchema(schema *Schema, opts []SchemaOption) *Schema {
+	for _, opt := range opts {
+		opt.applyToSchema(schema)
+	}
+	return schema
+}
+
+// WithSummary sets the summary of an operation.
+func WithSummary(summary string) OperationOption {
+	return operationOption(func(operation *Operation) { operation.Summary = summary })
+}
+
+// WithOperationID sets the operationId of an operation.
+func WithOperationID(id string) OperationOption {
+	return operationOption(func(operation *Operation) { operation.OperationID = id })
+}
+
+// WithTags appends tags to the tags of an operation.
+func WithTags(tags ...string) OperationOption {
+	return operationOption(func(operation *Operation) { operation.Tags = append(operation.Tags, tags...) })
+}
+
+// WithParameter appends parameter to the parameters of an operation.
+func WithParameter(parameter *Parameter) OperationOption {
+	return operationOption(func(operation *Operation) { operation.AddParameter(parameter) })
+}
+
+// WithRequestBody sets the request body of an operation.
+func WithRequestBody(requestBody *RequestBody) OperationOption {
+	return operationOption(func(operation *Operation) {
+		operation.RequestBody = &RequestBodyRef{Value: requestBody}
+	})
+}
+
+// WithResponse sets the response of an operation to status, or the default response
+// when status is 0.
+func WithResponse(status int, response *Response) OperationOption {
+	return operationOption(func(operation *Operation) {
+		if operation.Responses == nil {
+			operation.Responses = &Responses{}
+		}
+		operation.Responses.Set(statusKey(status), &ResponseRef{Value: response})
+	})
+}
+
+// WithSecurity sets the security requirements of an operation, overriding those of the document.
+func WithSecurity(requirements ...SecurityRequirement) OperationOption {
+	return operationOption(func(operation *Operation) {
+		security := SecurityRequirements(requirements)
+		operation.Security = &security
+	})
+}
+
+// WithRequired makes a parameter required.
+func WithRequired() ParameterOption {
+	return parameterOption(func(parameter *Parameter) { parameter.Required = true })
+}
+
+// WithSchema sets the schema of a parameter, removing its content: a parameter
+// has either.
+func WithSchema(schema *Schema) ParameterOption {
+	return parameterOption(func(parameter *Parameter) {
+		parameter.WithSchema(schema)
+		parameter.Content = nil
+	})
+}
+
+// WithStyle sets the serialization style of a parameter, e.g. SerializationForm.
+func WithStyle(style string) ParameterOption {
+	return parameterOption(func(parameter *Parameter) { parameter.Style = style })
+}
+
+// WithExplode sets whether a parameter is exploded.
+func WithExplode(explode bool) ParameterOption {
+	return parameterOption(func(parameter *Parameter) { parameter.Explode = &explode })
+}
+
+// CommonOption is an option of operations, parameters and schemas alike,
+// e.g. WithDescription.
+type CommonOption interface {
+	OperationOption
+	ParameterOption
+	SchemaOption
+}
+
+type describedOption struct {
+	description *string
+	deprecated  bool
+}
+
+func (opt describedOption) applyToOperation(operation *Operation) {
+	if opt.description != nil {
+		operation.Description = *opt.description
+	}
+	operation.Deprecated = operation.Deprecated || opt.deprecated
+}
+
+func (opt describedOption) applyToParameter(parameter *Parameter) {
+	if opt.description != nil {
+		parameter.Description = *opt.description
+	}
+	parameter.Deprecated = parameter.Deprecated || opt.deprecated
+}
+
+func (opt describedOption) applyToSchema(schema *Schema) {
+	if opt.description != nil {
+		schema.Description = *opt.description
+	}
+	schema.Deprecated = schema.Deprecated || opt.deprecated
+}
+
+// WithDescription sets the description of an operation, parameter or schema.
+func WithDescription(description string) CommonOption {
+	return describedOption{description: &description}
+}
+
+// WithDeprecated marks an operation, parameter or schema as deprecated.
+func WithDeprecated() CommonOption {
+	return describedOption{deprecated: true}
+}
+
+// Title sets the title of a schema.
+func Title(title string) SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.Title = title })
+}
+
+// Formatted sets the format of a schema, e.g. "email".
+func Formatted(format string) SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.Format = format })
+}
+
+// Nullable allows null as a value of a schema.
+func Nullable() SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.Nullable = true })
+}
+
+// ReadOnly marks a schema as read-only, clearing writeOnly: a schema is not both.
+func ReadOnly() SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.ReadOnly, schema.WriteOnly = true, false })
+}
+
+// WriteOnly marks a schema as write-only, clearing readOnly: a schema is not both.
+func WriteOnly() SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.ReadOnly, schema.WriteOnly = false, true })
+}
+
+// Enum sets the values a schema allows.
+func Enum(values ...interface{}) SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.Enum = values })
+}
+
+// Default sets the default value of a schema.
+func Default(value interface{}) SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.Default = value })
+}
+
+// Min sets the inclusive lower bound of the numbers a schema allows.
+func Min(value float64) SchemaOption {
+	return schemaOption(func(schema *Schema) {
+		schema.Min, schema.ExclusiveMin, schema.ExclusiveMinValue = &value, false, nil
+	})
+}
+
+// Max sets the inclusive upper bound of the numbers a schema allows.
+func Max(value float64) SchemaOption {
+	return schemaOption(func(schema *Schema) {
+		schema.Max, schema.ExclusiveMax, schema.ExclusiveMaxValue = &value, false, nil
+	})
+}
+
+// ExclusiveMin sets the exclusive lower bound of the numbers a schema allows,
+// in the OpenAPI 3.0 form: minimum along with exclusiveMinimum set to true.
+func ExclusiveMin(value float64) SchemaOption {
+	return schemaOption(func(schema *Schema) {
+		schema.Min, schema.ExclusiveMin, schema.ExclusiveMinValue = &value, true, nil
+	})
+}
+
+// ExclusiveMax sets the exclusive upper bound of the numbers a schema allows,
+// in the OpenAPI 3.0 form: maximum along with exclusiveMaximum set to true.
+func ExclusiveMax(value float64) SchemaOption {
+	return schemaOption(func(schema *Schema) {
+		schema.Max, schema.ExclusiveMax, schema.ExclusiveMaxValue = &value, true, nil
+	})
+}
+
+// MultipleOf sets the number the numbers a schema allows are multiples of.
+// It must be greater than 0.
+func MultipleOf(value float64) SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.MultipleOf = &value })
+}
+
+// MinLength sets the minimum length of the strings a schema allows.
+func MinLength(n uint64) SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.MinLength = n })
+}
+
+// MaxLength sets the maximum length of the strings a schema allows.
+func MaxLength(n uint64) SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.MaxLength = &n })
+}
+
+// Pattern sets the regular expression the strings a schema allows match.
+func Pattern(pattern string) SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.WithPattern(pattern) })
+}
+
+// Items sets the schema of the items of the arrays a schema allows.
+func Items(items *Schema) SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.WithItems(items) })
+}
+
+// MinItems sets the minimum number of items of the arrays a schema allows.
+func MinItems(n uint64) SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.MinItems = n })
+}
+
+// MaxItems sets the maximum number of items of the arrays a schema allows.
+func MaxItems(n uint64) SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.MaxItems = &n })
+}
+
+// UniqueItems makes a schema allow arrays of unique items only.
+func UniqueItems() SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.UniqueItems = true })
+}
+
+// Property sets the schema of the property name of the objects a schema allows.
+func Property(name string, property *Schema) SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.WithProperty(name, property) })
+}
+
+// Required appends names to the properties required by a schema, each once.
+func Required(names ...string) SchemaOption {
+	return schemaOption(func(schema *Schema) {
+		for _, name := range names {
+			if indexOfKey(schema.Required, name) < 0 {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	})
+}
+
+// MinProps sets the minimum number of properties of the objects a schema allows.
+func MinProps(n uint64) SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.MinProps = n })
+}
+
+// MaxProps sets the maximum number of properties of the objects a schema allows.
+func MaxProps(n uint64) SchemaOption {
+	return schemaOption(func(schema *Schema) { schema.MaxProps = &n })
+}
+
+// AdditionalProperties sets the schema of the properties of the objects a schema allows
+// other than those set with Property, or disallows them when additional is nil.
+func AdditionalProperties(additional *Schema) SchemaOption {
+	return schemaOption(func(schema *Schema) {
+		if additional == nil {
+			f := false
+			schema.AdditionalProperties, schema.AdditionalPropertiesAllowed = nil, &f
+			return
+		}
+		schema.WithAdditionalProperties(additional).AdditionalPropertiesAllowed = nil
+	})
+}
+
+// statusKey returns the key of the response to status in Responses.
+func statusKey(status int) string {
+	if status == 0 {
+		return "default"
+	}
+	return strconv.Itoa(status)
+}
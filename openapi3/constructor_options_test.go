@@ -0,0 +1,76 @@
+package openapi3
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstructorOptions(t *testing.T) {
+	limit := NewIntegerSchema(Min(1), Max(100), Default(20), WithDescription("Page size"))
+	require.Equal(t, &Schema{
+		Type:        TypeInteger,
+		Min:         Float64Ptr(1),
+		Max:         Float64Ptr(100),
+		Default:     20,
+		Description: "Page size",
+	}, limit)
+
+	pet := NewObjectSchema(
+		Property("id", NewInt64Schema(ReadOnly())),
+		Property("name", NewStringSchema(MinLength(1), MaxLength(20))),
+		Property("tags", NewArraySchema(Items(NewStringSchema()), UniqueItems())),
+		Required("name", "id", "name"),
+		AdditionalProperties(nil),
+	)
+	require.Equal(t, []string{"name", "id"}, pet.Required)
+	require.Equal(t, Uint64Ptr(20), pet.Properties.Value("name").Value.MaxLength)
+
+	operation := NewOperation(
+		WithOperationID("listPets"),
+		WithSummary("List pets"),
+		WithTags("pets"),
+		WithParameter(NewQueryParameter("limit", WithSchema(limit))),
+		WithParameter(NewHeaderParameter("X-Request-Id", WithRequired(), WithDeprecated(), WithSchema(NewUUIDSchema()))),
+		WithResponse(200, NewResponse().WithDescription("Pets").WithJSONSchema(NewArraySchema(Items(pet)))),
+	)
+	require.Len(t, operation.Parameters, 2)
+	require.True(t, operation.Parameters[1].Value.Required)
+	require.True(t, operation.Parameters[1].Value.Deprecated)
+	require.Equal(t, []string{"200"}, operation.Responses.InOrder())
+
+	doc := &T{
+		OpenAPI: "3.0.3",
+		Info:    &Info{Title: "Pets", Version: "1.0.0"},
+		Paths:   NewPaths().With("/pets", &PathItem{Get: operation}),
+	}
+	require.NoError(t, doc.Validate(context.Background()))
+	data, err := json.Marshal(operation.Responses)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"200": {"description": "Pets", "content": {"application/json": {"schema": {"type": "array", "items": {
+		"type": "object",
+		"required": ["name", "id"],
+		"additionalProperties": false,
+		"properties": {
+			"id": {"type": "integer", "format": "int64", "readOnly": true},
+			"name": {"type": "string", "minLength": 1, "maxLength": 20},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true}
+		}
+	}}}}}}`, string(data))
+
+	// Operations have the responses they are given only
+	require.Nil(t, NewOperation().Responses)
+	require.Nil(t, NewOperation(WithSummary("Ping")).Responses)
+	require.EqualError(t, NewOperation(WithSummary("Ping")).Validate(context.Background()), "value of responses must be an object")
+
+	// Responses are left as is, so that validation reports what they miss
+	response := NewResponse()
+	operation = NewOperation(WithResponse(0, response))
+	require.Nil(t, response.Description)
+	require.Same(t, response, operation.Responses.Default().Value)
+	require.EqualError(t, operation.Validate(context.Background()), "a short description of the response is required")
+
+	require.Equal(t, NewSchema().WithMin(3).WithExclusiveMin(true), NewSchema(ExclusiveMin(3)))
+}
@@ -62,6 +62,22 @@ func NewContentWithFormDataSchemaRef(schema *SchemaRef) Content {
 	}
 }
 
+// MediaTypes returns the media types of content, "application/json" first
+// then the others in lexical order, e.g. to pick the preferred one.
+func (content Content) MediaTypes() []string {
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Slice(mediaTypes, func(i, j int) bool {
+		if isJSON := mediaTypes[i] == "application/json"; isJSON != (mediaTypes[j] == "application/json") {
+			return isJSON
+		}
+		return mediaTypes[i] < mediaTypes[j]
+	})
+	return mediaTypes
+}
+
 func (content Content) Get(mime string) *MediaType {
 	// If the mime is empty then short-circuit to the wildcard.
 	// We do this here so that we catch only the specific case of
@@ -111,3 +111,13 @@ func TestContent_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestContentMediaTypes(t *testing.T) {
+	content := Content{
+		"text/plain":       NewMediaType(),
+		"application/xml":  NewMediaType(),
+		"application/json": NewMediaType(),
+	}
+	require.Equal(t, []string{"application/json", "application/xml", "text/plain"}, content.MediaTypes())
+	require.Empty(t, Content(nil).MediaTypes())
+}
@@ -20,6 +20,11 @@ func (discriminator *Discriminator) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(discriminator)
 }
 
+// MarshalYAML returns the YAML encoding of Discriminator.
+func (discriminator *Discriminator) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(discriminator)
+}
+
 // UnmarshalJSON sets Discriminator to a copy of data.
 func (discriminator *Discriminator) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, discriminator)
@@ -45,6 +45,11 @@ func (encoding *Encoding) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(encoding)
 }
 
+// MarshalYAML returns the YAML encoding of Encoding.
+func (encoding *Encoding) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(encoding)
+}
+
 // UnmarshalJSON sets Encoding to a copy of data.
 func (encoding *Encoding) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, encoding)
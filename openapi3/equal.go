@@ -0,0 +1,195 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Equal reports whether schema and other are semantically equal,
+// which reflect.DeepEqual does not tell:
+//   - descriptions, summaries, titles, examples and external docs are ignored;
+//   - the order of lists, e.g. of required properties, enum values or allOf
+//     subschemas, is ignored;
+//   - references are resolved: a reference is equal to the value it references,
+//     whichever its location;
+//   - values, e.g. of defaults or extensions, are compared as JSON, so 1 and 1.0 are equal;
+//   - bounds are compared whichever form of exclusiveMinimum and exclusiveMaximum
+//     they use (see Minimum and Maximum).
+//
+// Cyclic schemas are compared as such.
+func (schema *Schema) Equal(other *Schema) bool {
+	return newEqualer().equal(reflect.ValueOf(schema), reflect.ValueOf(other))
+}
+
+// Equal reports whether operation and other are semantically equal, as Schema.Equal
+// compares schemas: e.g. parameters are compared whichever their order.
+func (operation *Operation) Equal(other *Operation) bool {
+	return newEqualer().equal(reflect.ValueOf(operation), reflect.ValueOf(other))
+}
+
+// ignoredEqualFields are the fields of the model that document it, and Equal ignores.
+var ignoredEqualFields = map[string]struct{}{
+	"Description":  {},
+	"Summary":      {},
+	"Title":        {},
+	"Example":      {},
+	"Examples":     {},
+	"ExternalDocs": {},
+}
+
+// boundFields are the fields of Schema compared by their bounds.
+var boundFields = map[string]struct{}{
+	"Min":               {},
+	"Max":               {},
+	"ExclusiveMin":      {},
+	"ExclusiveMax":      {},
+	"ExclusiveMinValue": {},
+	"ExclusiveMaxValue": {},
+}
+
+var typeOfRefField = reflect.TypeOf("")
+
+// equalPair identifies two pointers being compared, to compare cycles once.
+type equalPair struct {
+	typ  reflect.Type
+	a, b uintptr
+}
+
+type equaler struct {
+	comparing map[equalPair]struct{}
+}
+
+func newEqualer() *equaler {
+	return &equaler{comparing: make(map[equalPair]struct{})}
+}
+
+func (e *equaler) equal(a, b reflect.Value) bool {
+	// Collections such as Paths are compared by their items, so that empty ones equal nil
+	a, _ = collectionItems(a)
+	b, _ = collectionItems(b)
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		if a.Pointer() == b.Pointer() {
+			return true
+		}
+		// Pointers already being compared are equal unless the comparison
+		// in progress finds otherwise.
+		pair := equalPair{typ: a.Type(), a: a.Pointer(), b: b.Pointer()}
+		if _, ok := e.comparing[pair]; ok {
+			return true
+		}
+		e.comparing[pair] = struct{}{}
+		defer delete(e.comparing, pair)
+		return e.equal(a.Elem(), b.Elem())
+	case reflect.Struct:
+		return e.equalStructs(a, b)
+	case reflect.Slice:
+		return e.equalUnordered(a, b)
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		iter := a.MapRange()
+		for iter.Next() {
+			value := b.MapIndex(iter.Key())
+			if !value.IsValid() || !e.equal(iter.Value(), value) {
+				return false
+			}
+		}
+		return true
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equalJSONValues(a.Interface(), b.Interface())
+	}
+	return a.Interface() == b.Interface()
+}
+
+func (e *equaler) equalStructs(a, b reflect.Value) bool {
+	typ := a.Type()
+	if ref, ok := typ.FieldByName("Ref"); ok && ref.Type == typeOfRefField {
+		if value, ok := typ.FieldByName("Value"); ok && len(ref.Index) == 1 && len(value.Index) == 1 {
+			// Compare what references reference
+			va, vb := a.FieldByIndex(value.Index), b.FieldByIndex(value.Index)
+			if va.IsNil() && vb.IsNil() {
+				return a.FieldByIndex(ref.Index).String() == b.FieldByIndex(ref.Index).String()
+			}
+			return e.equal(va, vb)
+		}
+	}
+	isSchema := typ == typeOfSchema.Elem()
+	if isSchema && a.CanAddr() && b.CanAddr() {
+		sa, sb := a.Addr().Interface().(*Schema), b.Addr().Interface().(*Schema)
+		minA, exclusiveMinA, okMinA := sa.Minimum()
+		minB, exclusiveMinB, okMinB := sb.Minimum()
+		maxA, exclusiveMaxA, okMaxA := sa.Maximum()
+		maxB, exclusiveMaxB, okMaxB := sb.Maximum()
+		if minA != minB || exclusiveMinA != exclusiveMinB || okMinA != okMinB ||
+			maxA != maxB || exclusiveMaxA != exclusiveMaxB || okMaxA != okMaxB {
+			return false
+		}
+	} else {
+		isSchema = false
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if _, ok := ignoredEqualFields[field.Name]; ok {
+			continue
+		}
+		if _, ok := boundFields[field.Name]; ok && isSchema {
+			continue
+		}
+		if !e.equal(a.Field(i), b.Field(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalUnordered reports whether each item of a equals a distinct item of b, and the converse.
+func (e *equaler) equalUnordered(a, b reflect.Value) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	matched := make([]bool, b.Len())
+	for i := 0; i < a.Len(); i++ {
+		found := false
+		for j := 0; j < b.Len(); j++ {
+			if !matched[j] && e.equal(a.Index(i), b.Index(j)) {
+				matched[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// equalJSONValues reports whether a and b have the same JSON value.
+func equalJSONValues(a, b interface{}) bool {
+	va, errA := normalizeJSONValue(a)
+	vb, errB := normalizeJSONValue(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func normalizeJSONValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
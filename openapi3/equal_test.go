@@ -0,0 +1,94 @@
+package openapi3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEqual(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      summary: List pets
+      parameters:
+      - {name: limit, in: query, schema: {type: integer, minimum: 1}}
+      - {name: kind, in: query, schema: {$ref: '#/components/schemas/Kind'}}
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+  /animals:
+    get:
+      summary: List animals
+      description: Lists every animal.
+      parameters:
+      - name: kind
+        in: query
+        schema: {type: string, enum: [dog, cat], description: The kind of animal}
+      - {name: limit, in: query, description: Page size, schema: {type: integer, minimum: 1.0}}
+      responses:
+        '200':
+          description: Animals
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Animal'}}
+  /cats:
+    get:
+      parameters:
+      - {name: limit, in: query, schema: {type: integer, minimum: 0, exclusiveMinimum: true}}
+      - {name: kind, in: query, schema: {type: string, enum: [cat, dog]}}
+      responses:
+        '200': {description: Cats}
+components:
+  schemas:
+    Kind: {type: string, enum: [cat, dog], title: Kind}
+    Pet:
+      type: object
+      required: [name, kind]
+      properties:
+        name: {type: string}
+        kind: {$ref: '#/components/schemas/Kind'}
+        friends: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+    Animal:
+      type: object
+      description: An animal
+      required: [kind, name]
+      properties:
+        name: {type: string, example: Garfield}
+        kind: {type: string, enum: [dog, cat]}
+        friends: {type: array, items: {$ref: '#/components/schemas/Animal'}}
+`
+	doc, err := NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	schemas := doc.Components.Schemas
+	pets, animals, cats := doc.Paths.Value("/pets").Get, doc.Paths.Value("/animals").Get, doc.Paths.Value("/cats").Get
+
+	require.True(t, schemas.Value("Pet").Value.Equal(schemas.Value("Animal").Value))
+	require.True(t, schemas.Value("Pet").Value.Equal(schemas.Value("Pet").Value.Clone()))
+	require.True(t, pets.Equal(animals))
+	require.True(t, (*Operation)(nil).Equal(nil))
+	require.False(t, pets.Equal(nil))
+
+	// Bounds compare whichever their form
+	exclusive := NewIntegerSchema(ExclusiveMin(0))
+	require.True(t, exclusive.Equal(NewIntegerSchema().WithExclusiveMinValue(0)))
+	require.False(t, exclusive.Equal(NewIntegerSchema(Min(0))))
+	// minimum: 1 differs from exclusiveMinimum: 0
+	require.False(t, pets.Equal(cats))
+
+	animal := schemas.Value("Animal").Value.Clone()
+	animal.Properties.Value("friends").Value.Items.Value.Properties.Value("name").Value.MaxLength = Uint64Ptr(20)
+	require.False(t, schemas.Value("Pet").Value.Equal(animal))
+	animal = schemas.Value("Animal").Value.Clone()
+	animal.Required = append(animal.Required, "friends")
+	require.False(t, schemas.Value("Pet").Value.Equal(animal))
+	animal = schemas.Value("Animal").Value.Clone()
+	animal.Extensions["x-go-type"] = "Animal"
+	require.False(t, schemas.Value("Pet").Value.Equal(animal))
+}
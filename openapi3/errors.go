@@ -2,7 +2,9 @@ package openapi3
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
+	"strings"
 )
 
 // MultiError is a collection of errors, intended for when
@@ -48,6 +50,63 @@ func (me MultiError) As(target interface{}) bool {
 	return false
 }
 
+// MarshalJSON encodes the errors as a JSON array, each with its own MarshalJSON
+// if it has one or else as an object with its message as detail.
+func (me MultiError) MarshalJSON() ([]byte, error) {
+	items := make([]json.RawMessage, 0, len(me))
+	for _, e := range me {
+		item, err := marshalError(e)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return json.Marshal(items)
+}
+
+func marshalError(err error) ([]byte, error) {
+	if m, ok := err.(json.Marshaler); ok {
+		return m.MarshalJSON()
+	}
+	return json.Marshal(struct {
+		Detail string `json:"detail"`
+	}{ErrorDetail(err)})
+}
+
+// ErrorDetail returns the message of err with the schema errors it holds
+// printed at SchemaErrorShort verbosity, so that it never includes schemas nor values
+// whatever the verbosity they were returned with.
+func ErrorDetail(err error) string {
+	switch e := err.(type) {
+	case *SchemaError:
+		return e.detail()
+	case MultiError:
+		return spliceDetails(" | ", e)
+	case multiErrorForOneOf:
+		return spliceDetails(" Or ", e)
+	}
+	msg := err.Error()
+	if inner := errors.Unwrap(err); inner != nil {
+		// Errors wrapping with %w print the message of what they wrap as is
+		if innerMsg := inner.Error(); strings.Contains(msg, innerMsg) {
+			return strings.Replace(msg, innerMsg, ErrorDetail(inner), 1)
+		}
+		return ErrorDetail(inner)
+	}
+	return msg
+}
+
+func spliceDetails(sep string, errs []error) string {
+	buff := &bytes.Buffer{}
+	for i, e := range errs {
+		buff.WriteString(ErrorDetail(e))
+		if i != len(errs)-1 {
+			buff.WriteString(sep)
+		}
+	}
+	return buff.String()
+}
+
 type multiErrorForOneOf MultiError
 
 func (meo multiErrorForOneOf) Error() string {
@@ -49,6 +49,11 @@ func (example *Example) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(example)
 }
 
+// MarshalYAML returns the YAML encoding of Example.
+func (example *Example) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(example)
+}
+
 // UnmarshalJSON sets Example to a copy of data.
 func (example *Example) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, example)
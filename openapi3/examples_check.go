@@ -0,0 +1,250 @@
+package openapi3
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ExampleError is an example or default value that does not conform to its schema.
+type ExampleError struct {
+	// Location is a JSON pointer to the value, e.g. "/paths/~1pets/get/parameters/0/example".
+	Location string
+	Value    interface{}
+	Err      error
+}
+
+func (e *ExampleError) Error() string {
+	return e.Location + ": " + e.Err.Error()
+}
+
+func (e *ExampleError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateExamples validates all example, examples and default values of doc
+// against their schema, under parameters, headers, media types and schemas.
+//
+// Unlike Validate, which stops at the first invalid example, it returns
+// a MultiError holding an *ExampleError for each invalid value, or nil.
+// Referenced components are checked once, at their definition.
+// Examples of request bodies are validated as requests (ignoring readOnly properties)
+// and those of responses as responses (ignoring writeOnly properties).
+// As validation sets the defaults of missing properties, an invalid default
+// is also reported for the examples lacking its property.
+func (doc *T) ValidateExamples(ctx context.Context) error {
+	c := &examplesChecker{visited: make(map[*Schema]bool)}
+	components := doc.Components
+	for _, name := range componentNames(components.Schemas) {
+		c.checkSchemaRef(jsonPointer("components", "schemas", name), components.Schemas.Value(name), nil, true)
+	}
+	for _, name := range componentNames(components.Parameters) {
+		if p := components.Parameters[name]; p != nil && p.Value != nil {
+			c.checkParameter(jsonPointer("components", "parameters", name), p.Value)
+		}
+	}
+	for _, name := range componentNames(components.Headers) {
+		if h := components.Headers[name]; h != nil && h.Value != nil {
+			c.checkParameter(jsonPointer("components", "headers", name), &h.Value.Parameter)
+		}
+	}
+	for _, name := range componentNames(components.RequestBodies) {
+		if rb := components.RequestBodies[name]; rb != nil && rb.Value != nil {
+			c.checkContent(jsonPointer("components", "requestBodies", name, "content"), rb.Value.Content, VisitAsRequest())
+		}
+	}
+	for _, name := range componentNames(components.Responses) {
+		c.checkResponse(jsonPointer("components", "responses", name), components.Responses.Value(name), false)
+	}
+	for _, name := range componentNames(components.Callbacks) {
+		if callback := components.Callbacks[name]; callback != nil && callback.Ref == "" && callback.Value != nil {
+			c.checkCallback(jsonPointer("components", "callbacks", name), *callback.Value)
+		}
+	}
+	c.checkPaths(jsonPointer("paths"), doc.Paths.items())
+	c.checkPaths(jsonPointer("webhooks"), doc.Webhooks)
+
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return c.errs
+}
+
+type examplesChecker struct {
+	errs    MultiError
+	visited map[*Schema]bool
+}
+
+func (c *examplesChecker) check(location string, value interface{}, schema *Schema, opts ...SchemaValidationOption) {
+	if value == nil || schema == nil {
+		return
+	}
+	opts = append(opts, MultiErrors())
+	// Validation sets the defaults of missing properties: work on a copy not to alter the document
+	if err := schema.VisitJSON(copyJSON(value), opts...); err != nil {
+		c.errs = append(c.errs, &ExampleError{Location: location, Value: value, Err: err})
+	}
+}
+
+func (c *examplesChecker) checkPaths(location string, paths map[string]*PathItem) {
+	for _, path := range componentNames(paths) {
+		pathItem := paths[path]
+		if pathItem == nil {
+			continue
+		}
+		pathLocation := location + jsonPointer(path)
+		c.checkParameters(pathLocation, pathItem.Parameters)
+		operations := pathItem.Operations()
+		for _, method := range componentNames(operations) {
+			operation := operations[method]
+			operationLocation := pathLocation + jsonPointer(strings.ToLower(method))
+			c.checkParameters(operationLocation, operation.Parameters)
+			if rb := operation.RequestBody; rb != nil && rb.Ref == "" && rb.Value != nil {
+				c.checkContent(operationLocation+jsonPointer("requestBody", "content"), rb.Value.Content, VisitAsRequest())
+			}
+			for _, status := range componentNames(operation.Responses) {
+				c.checkResponse(operationLocation+jsonPointer("responses", status), operation.Responses.Value(status), true)
+			}
+			for _, name := range componentNames(operation.Callbacks) {
+				if callback := operation.Callbacks[name]; callback != nil && callback.Ref == "" && callback.Value != nil {
+					c.checkCallback(operationLocation+jsonPointer("callbacks", name), *callback.Value)
+				}
+			}
+		}
+	}
+}
+
+func (c *examplesChecker) checkCallback(location string, callback Callback) {
+	c.checkPaths(location, callback)
+}
+
+func (c *examplesChecker) checkParameters(location string, parameters Parameters) {
+	for i, p := range parameters {
+		if p != nil && p.Ref == "" && p.Value != nil {
+			c.checkParameter(location+jsonPointer("parameters", strconv.Itoa(i)), p.Value)
+		}
+	}
+}
+
+func (c *examplesChecker) checkParameter(location string, parameter *Parameter) {
+	if parameter.Schema != nil && parameter.Schema.Value != nil {
+		schema := parameter.Schema.Value
+		c.check(location+jsonPointer("example"), parameter.Example, schema)
+		for _, name := range componentNames(parameter.Examples) {
+			if example := parameter.Examples[name]; example != nil && example.Value != nil {
+				c.check(location+jsonPointer("examples", name, "value"), example.Value.Value, schema)
+			}
+		}
+		c.checkSchemaRef(location+jsonPointer("schema"), parameter.Schema, nil, false)
+	}
+	c.checkContent(location+jsonPointer("content"), parameter.Content)
+}
+
+func (c *examplesChecker) checkResponse(location string, response *ResponseRef, inline bool) {
+	if response == nil || response.Value == nil || (inline && response.Ref != "") {
+		return
+	}
+	for _, name := range componentNames(response.Value.Headers) {
+		if h := response.Value.Headers[name]; h != nil && h.Ref == "" && h.Value != nil {
+			c.checkParameter(location+jsonPointer("headers", name), &h.Value.Parameter)
+		}
+	}
+	c.checkContent(location+jsonPointer("content"), response.Value.Content, VisitAsResponse())
+}
+
+func (c *examplesChecker) checkContent(location string, content Content, opts ...SchemaValidationOption) {
+	for _, name := range componentNames(content) {
+		mediaType := content[name]
+		if mediaType == nil || mediaType.Schema == nil || mediaType.Schema.Value == nil {
+			continue
+		}
+		mediaTypeLocation := location + jsonPointer(name)
+		schema := mediaType.Schema.Value
+		c.check(mediaTypeLocation+jsonPointer("example"), mediaType.Example, schema, opts...)
+		for _, exampleName := range componentNames(mediaType.Examples) {
+			if example := mediaType.Examples[exampleName]; example != nil && example.Value != nil {
+				c.check(mediaTypeLocation+jsonPointer("examples", exampleName, "value"), example.Value.Value, schema, opts...)
+			}
+		}
+		c.checkSchemaRef(mediaTypeLocation+jsonPointer("schema"), mediaType.Schema, opts, false)
+	}
+}
+
+// checkSchemaRef checks the example and default of a schema and of its subschemas.
+// References to components are skipped unless component is set, as they are checked at their definition.
+func (c *examplesChecker) checkSchemaRef(location string, ref *SchemaRef, opts []SchemaValidationOption, component bool) {
+	if ref == nil || ref.Value == nil {
+		return
+	}
+	if ref.Ref != "" && !component && strings.HasPrefix(ref.Ref, "#/components/") {
+		return
+	}
+	schema := ref.Value
+	if c.visited[schema] {
+		return
+	}
+	c.visited[schema] = true
+
+	c.check(location+jsonPointer("example"), schema.Example, schema, opts...)
+	c.check(location+jsonPointer("default"), schema.Default, schema, opts...)
+
+	for _, name := range componentNames(schema.Properties) {
+		c.checkSchemaRef(location+jsonPointer("properties", name), schema.Properties.Value(name), opts, false)
+	}
+	c.checkSchemaRef(location+jsonPointer("items"), schema.Items, opts, false)
+	c.checkSchemaRef(location+jsonPointer("additionalProperties"), schema.AdditionalProperties, opts, false)
+	c.checkSchemaRef(location+jsonPointer("not"), schema.Not, opts, false)
+	c.checkSchemaRef(location+jsonPointer("contentSchema"), schema.ContentSchema, opts, false)
+	for keyword, refs := range map[string]SchemaRefs{"allOf": schema.AllOf, "anyOf": schema.AnyOf, "oneOf": schema.OneOf} {
+		for i, ref := range refs {
+			c.checkSchemaRef(location+jsonPointer(keyword, strconv.Itoa(i)), ref, opts, false)
+		}
+	}
+}
+
+// copyJSON deep copies the maps and slices of a decoded JSON value.
+func copyJSON(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			m[k] = copyJSON(v)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(value))
+		for i, v := range value {
+			s[i] = copyJSON(v)
+		}
+		return s
+	}
+	return value
+}
+
+// componentNames returns the sorted keys of a map of components.
+func componentNames(m interface{}) []string {
+	if collection, ok := m.(orderedMap); ok {
+		names := collection.InOrder()
+		sort.Strings(names)
+		return names
+	}
+	keys := reflect.ValueOf(m).MapKeys()
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		names = append(names, key.String())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// jsonPointer returns the JSON pointer made of the given reference tokens.
+func jsonPointer(tokens ...string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1))
+	}
+	return b.String()
+}
@@ -0,0 +1,127 @@
+package openapi3_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestValidateExamples(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets/{id}:
+    parameters:
+    - name: id
+      in: path
+      required: true
+      schema: {type: integer}
+      example: abc
+    get:
+      parameters:
+      - name: limit
+        in: query
+        schema: {type: integer, maximum: 10, default: 20}
+        examples:
+          ok: {value: 5}
+          tooBig: {value: 50}
+      responses:
+        '200':
+          description: A pet
+          headers:
+            X-Rate-Limit:
+              schema: {type: integer}
+              example: 100
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+              example: {id: 1, name: Rex}
+    put:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+            example: {name: Rex}
+      responses:
+        '204': {description: Updated}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id: {type: integer, readOnly: true}
+        name: {type: string, example: 42}
+        kind:
+          type: string
+          enum: [cat, dog]
+          example: bird
+          default: cat
+        tag: {type: string, maxLength: 3, default: none}
+`
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+
+	err = doc.ValidateExamples(context.Background())
+	require.Error(t, err)
+	var locations []string
+	for _, err := range err.(openapi3.MultiError) {
+		var exampleErr *openapi3.ExampleError
+		require.True(t, errors.As(err, &exampleErr))
+		locations = append(locations, exampleErr.Location)
+	}
+	require.Equal(t, []string{
+		"/components/schemas/Pet/properties/kind/example",
+		"/components/schemas/Pet/properties/name/example",
+		"/components/schemas/Pet/properties/tag/default",
+		"/paths/~1pets~1{id}/parameters/0/example",
+		"/paths/~1pets~1{id}/get/parameters/0/examples/tooBig/value",
+		"/paths/~1pets~1{id}/get/parameters/0/schema/default",
+		// These examples lack tag, so its invalid default is validated with them
+		"/paths/~1pets~1{id}/get/responses/200/content/application~1json/example",
+		"/paths/~1pets~1{id}/put/requestBody/content/application~1json/example",
+	}, locations)
+	require.Contains(t, err.Error(), "/paths/~1pets~1{id}/parameters/0/example: ")
+
+	// The document is left untouched
+	example := doc.Paths.Value("/pets/{id}").Put.RequestBody.Value.Content["application/json"].Example
+	require.Equal(t, map[string]interface{}{"name": "Rex"}, example)
+}
+
+func TestValidateExamplesReadOnly(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+            example: {name: Rex}
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+              example: {id: 1, name: Rex}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id: {type: integer, readOnly: true}
+        name: {type: string}
+`
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	require.NoError(t, doc.ValidateExamples(context.Background()))
+}
@@ -0,0 +1,184 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ExpandEnvOption allows tweaking the expansion of placeholders by T.ExpandEnv.
+type ExpandEnvOption func(*envExpander)
+
+// WithEnvLookup sets the function looking up the values of variables.
+// It defaults to os.LookupEnv.
+func WithEnvLookup(lookup func(name string) (string, bool)) ExpandEnvOption {
+	return func(e *envExpander) {
+		e.lookup = lookup
+	}
+}
+
+// WithEnvExtensions also expands the placeholders in the string values of the extensions
+// of the given names (e.g. "x-logo") set on the document, its info, servers, path items,
+// operations and security schemes.
+func WithEnvExtensions(names ...string) ExpandEnvOption {
+	return func(e *envExpander) {
+		for _, name := range names {
+			e.extensions[name] = struct{}{}
+		}
+	}
+}
+
+type envExpander struct {
+	lookup     func(name string) (string, bool)
+	extensions map[string]struct{}
+	undefined  map[string]struct{}
+}
+
+// ExpandEnv replaces the ${VAR} and ${VAR:-default} placeholders in the URLs of the servers
+// of doc, of its path items and of its operations, in the default values and enums
+// of their variables, and in the URLs of the OAuth flows and OpenID Connect security schemes,
+// with the values of the environment variables.
+// This allows to parameterize a document per environment, e.g. with
+//
+//	servers:
+//	- url: ${API_URL:-http://localhost:8080}/v1
+//
+// ExpandEnv is not called by Loader: call it after loading documents, before validating them.
+// It returns an error naming the variables that are undefined and have no default value,
+// after expanding the others.
+func (doc *T) ExpandEnv(opts ...ExpandEnvOption) error {
+	e := &envExpander{
+		lookup:     os.LookupEnv,
+		extensions: make(map[string]struct{}),
+		undefined:  make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	e.expandExtensions(&doc.ExtensionProps)
+	if doc.Info != nil {
+		e.expandExtensions(&doc.Info.ExtensionProps)
+	}
+	e.expandServers(doc.Servers)
+	for _, path := range componentNames(doc.Paths) {
+		pathItem := doc.Paths.Value(path)
+		if pathItem == nil {
+			continue
+		}
+		e.expandExtensions(&pathItem.ExtensionProps)
+		e.expandServers(pathItem.Servers)
+		operations := pathItem.Operations()
+		for _, method := range componentNames(operations) {
+			operation := operations[method]
+			e.expandExtensions(&operation.ExtensionProps)
+			if operation.Servers != nil {
+				e.expandServers(*operation.Servers)
+			}
+		}
+	}
+	for _, name := range componentNames(doc.Components.SecuritySchemes) {
+		if ref := doc.Components.SecuritySchemes[name]; ref != nil && ref.Value != nil {
+			e.expandSecurityScheme(ref.Value)
+		}
+	}
+
+	if len(e.undefined) != 0 {
+		return fmt.Errorf("undefined environment variables: %s", strings.Join(componentNames(e.undefined), ", "))
+	}
+	return nil
+}
+
+func (e *envExpander) expandServers(servers Servers) {
+	for _, server := range servers {
+		if server == nil {
+			continue
+		}
+		e.expandExtensions(&server.ExtensionProps)
+		server.URL = e.expand(server.URL)
+		for _, name := range componentNames(server.Variables) {
+			variable := server.Variables[name]
+			if variable == nil {
+				continue
+			}
+			variable.Default = e.expand(variable.Default)
+			for i, value := range variable.Enum {
+				variable.Enum[i] = e.expand(value)
+			}
+		}
+	}
+}
+
+func (e *envExpander) expandSecurityScheme(ss *SecurityScheme) {
+	e.expandExtensions(&ss.ExtensionProps)
+	ss.OpenIdConnectUrl = e.expand(ss.OpenIdConnectUrl)
+	if flows := ss.Flows; flows != nil {
+		for _, flow := range []*OAuthFlow{flows.Implicit, flows.Password, flows.ClientCredentials, flows.AuthorizationCode} {
+			if flow == nil {
+				continue
+			}
+			flow.AuthorizationURL = e.expand(flow.AuthorizationURL)
+			flow.TokenURL = e.expand(flow.TokenURL)
+			flow.RefreshURL = e.expand(flow.RefreshURL)
+		}
+	}
+}
+
+// expandExtensions expands the placeholders in the string values of the selected extensions
+// of props, whether they are strings or raw JSON values as loaded.
+func (e *envExpander) expandExtensions(props *ExtensionProps) {
+	for name := range e.extensions {
+		switch value := props.Extensions[name].(type) {
+		case string:
+			props.Extensions[name] = e.expand(value)
+		case json.RawMessage:
+			// Placeholders can only appear in the strings of JSON values,
+			// so that they are replaced with JSON-encoded strings
+			props.Extensions[name] = json.RawMessage(e.expandWith(string(value), func(s string) string {
+				data, _ := json.Marshal(s)
+				return string(data[1 : len(data)-1])
+			}))
+		}
+	}
+}
+
+func (e *envExpander) expand(s string) string {
+	return e.expandWith(s, func(s string) string { return s })
+}
+
+// expandWith replaces the placeholders in s with the values of their variables
+// as returned by quote. Undefined variables without default values are recorded
+// and their placeholders are left as they are.
+func (e *envExpander) expandWith(s string, quote func(string) string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		j := strings.IndexByte(s[i:], '}')
+		if j < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		placeholder := s[i : i+j+1]
+		b.WriteString(s[:i])
+		s = s[i+j+1:]
+
+		name, defaultValue, hasDefault := placeholder[2:len(placeholder)-1], "", false
+		if k := strings.Index(name, ":-"); k >= 0 {
+			name, defaultValue, hasDefault = name[:k], name[k+2:], true
+		}
+		if value, ok := e.lookup(name); ok && (value != "" || !hasDefault) {
+			b.WriteString(quote(value))
+		} else if hasDefault {
+			// The default value appears in the document as is, so it needs no quoting
+			b.WriteString(defaultValue)
+		} else {
+			e.undefined[name] = struct{}{}
+			b.WriteString(placeholder)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandEnv(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+  x-logo: {url: '${CDN_URL}/logo.png', alt: Pets}
+servers:
+- url: ${API_URL:-http://localhost:8080}/v1
+  variables:
+    region:
+      default: ${REGION:-eu}
+      enum: ['${REGION:-eu}', us]
+- url: https://${HOST}/${UNDEFINED}
+paths:
+  /pets:
+    servers:
+    - url: ${PETS_URL}
+    get:
+      x-path: ${PATH}
+      responses:
+        '200': {description: OK}
+components:
+  securitySchemes:
+    oauth:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: ${AUTH_URL}/token
+          scopes: {}
+    oidc:
+      type: openIdConnect
+      openIdConnectUrl: ${AUTH_URL}/.well-known/openid-configuration
+`
+	doc, err := NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+
+	env := map[string]string{
+		"CDN_URL":  `https://cdn.example.com/"pets"`,
+		"API_URL":  "https://api.example.com",
+		"REGION":   "",
+		"HOST":     "example.com",
+		"PETS_URL": "https://pets.example.com",
+		"AUTH_URL": "https://auth.example.com",
+	}
+	err = doc.ExpandEnv(WithEnvLookup(func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}), WithEnvExtensions("x-logo"))
+	require.EqualError(t, err, "undefined environment variables: UNDEFINED")
+
+	require.Equal(t, "https://api.example.com/v1", doc.Servers[0].URL)
+	require.Equal(t, "eu", doc.Servers[0].Variables["region"].Default)
+	require.Equal(t, []string{"eu", "us"}, doc.Servers[0].Variables["region"].Enum)
+	require.Equal(t, "https://example.com/${UNDEFINED}", doc.Servers[1].URL)
+	require.Equal(t, "https://pets.example.com", doc.Paths.Value("/pets").Servers[0].URL)
+	require.Equal(t, "https://auth.example.com/token", doc.Components.SecuritySchemes["oauth"].Value.Flows.ClientCredentials.TokenURL)
+	require.Equal(t, "https://auth.example.com/.well-known/openid-configuration", doc.Components.SecuritySchemes["oidc"].Value.OpenIdConnectUrl)
+
+	var logo map[string]string
+	require.NoError(t, json.Unmarshal(doc.Info.Extensions["x-logo"].(json.RawMessage), &logo))
+	require.Equal(t, map[string]string{"url": `https://cdn.example.com/"pets"/logo.png`, "alt": "Pets"}, logo)
+	// Extensions which are not selected are left as they are
+	require.JSONEq(t, `"${PATH}"`, string(doc.Paths.Value("/pets").Get.Extensions["x-path"].(json.RawMessage)))
+}
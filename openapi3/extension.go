@@ -1,6 +1,8 @@
 package openapi3
 
 import (
+	"encoding/json"
+
 	"github.com/getkin/kin-openapi/jsoninfo"
 )
 
@@ -36,3 +38,22 @@ func (props *ExtensionProps) DecodeWith(decoder *jsoninfo.ObjectDecoder, value i
 	props.Extensions = result
 	return nil
 }
+
+// DecodeExtension decodes the value of the extension with the given name into v
+// and reports whether the extension is set.
+// It handles both raw JSON values (as set when unmarshaling a document)
+// and Go values (as set programmatically).
+func (props *ExtensionProps) DecodeExtension(name string, v interface{}) (bool, error) {
+	value, ok := props.Extensions[name]
+	if !ok {
+		return false, nil
+	}
+	data, isRaw := value.(json.RawMessage)
+	if !isRaw {
+		var err error
+		if data, err = json.Marshal(value); err != nil {
+			return true, err
+		}
+	}
+	return true, json.Unmarshal(data, v)
+}
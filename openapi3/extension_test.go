@@ -123,3 +123,32 @@ func TestExtensionProps_DecodeWith(t *testing.T) {
 		require.Empty(t, value.Field4)
 	})
 }
+
+func TestExtensionProps_DecodeExtension(t *testing.T) {
+	props := ExtensionProps{Extensions: map[string]interface{}{
+		"x-raw": json.RawMessage(`{"k":42}`),
+		"x-go":  map[string]interface{}{"k": 43},
+		"x-bad": json.RawMessage(`"str"`),
+	}}
+	var value struct {
+		Key int `json:"k"`
+	}
+
+	found, err := props.DecodeExtension("x-raw", &value)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, 42, value.Key)
+
+	found, err = props.DecodeExtension("x-go", &value)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, 43, value.Key)
+
+	found, err = props.DecodeExtension("x-bad", &value)
+	require.Error(t, err)
+	require.True(t, found)
+
+	found, err = props.DecodeExtension("x-missing", &value)
+	require.NoError(t, err)
+	require.False(t, found)
+}
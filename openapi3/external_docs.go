@@ -23,6 +23,11 @@ func (e *ExternalDocs) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(e)
 }
 
+// MarshalYAML returns the YAML encoding of ExternalDocs.
+func (e *ExternalDocs) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(e)
+}
+
 // UnmarshalJSON sets ExternalDocs to a copy of data.
 func (e *ExternalDocs) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, e)
@@ -0,0 +1,46 @@
+//go:build go1.18
+// +build go1.18
+
+package openapi3
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// FuzzVisitJSON checks that validating any JSON value against any valid schema does not panic:
+//
+//	go test ./openapi3 -run '^$' -fuzz FuzzVisitJSON
+func FuzzVisitJSON(f *testing.F) {
+	for _, seed := range []struct{ schema, value string }{
+		{`{"type": "string", "minLength": 2, "pattern": "^[a-z]+$"}`, `"abc"`},
+		{`{"type": "string", "format": "date-time"}`, `"2021-01-01T00:00:00Z"`},
+		{`{"type": "number", "multipleOf": 0.1, "maximum": 1e300}`, `1e308`},
+		{`{"type": "integer", "exclusiveMinimum": true, "minimum": 0}`, `-0`},
+		{`{"type": "array", "items": {"type": "integer"}, "uniqueItems": true}`, `[1, 2, 1]`},
+		{`{"type": "array", "items": {"type": "array", "items": {}}}`, strings.Repeat("[", 1000) + strings.Repeat("]", 1000)},
+		{`{"type": "object", "required": ["a"], "properties": {"a": {"type": "string", "nullable": true}}, "additionalProperties": false}`, `{"a": null, "b": 1}`},
+		{`{"oneOf": [{"type": "string"}, {"type": "integer"}], "discriminator": {"propertyName": "kind"}}`, `{"kind": "x"}`},
+		{`{"allOf": [null], "properties": {"a": null}}`, `{"a": 1}`},
+		{`{"anyOf": [{"maxLength": 1}], "allOf": [{"enum": ["a", "b"]}], "not": {"const": "c"}}`, `"\xff\xfe"`},
+	} {
+		f.Add([]byte(seed.schema), []byte(seed.value))
+	}
+	f.Fuzz(func(t *testing.T, schemaData, valueData []byte) {
+		var schema Schema
+		if err := json.Unmarshal(schemaData, &schema); err != nil {
+			return
+		}
+		if err := schema.Validate(context.Background()); err != nil {
+			return
+		}
+		var value interface{}
+		if err := json.Unmarshal(valueData, &value); err != nil {
+			return
+		}
+		_ = schema.VisitJSON(value)
+		_ = schema.VisitJSON(value, MultiErrors(), EnableFormatValidation())
+	})
+}
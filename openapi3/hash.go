@@ -0,0 +1,32 @@
+package openapi3
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// Hash returns a stable hexadecimal SHA-256 digest of the semantically relevant
+// content of doc, so that caches and deployment pipelines can cheaply detect
+// that a document actually changed.
+//
+// The digest ignores descriptions, summaries and examples, the order of keys
+// and of required properties, and the formatting of the source. It covers
+// extensions, which often drive tools, and references as they are written.
+// doc is not modified.
+func (doc *T) Hash() (string, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	var canonical T
+	if err := json.Unmarshal(data, &canonical); err != nil {
+		return "", err
+	}
+	canonical.Minify(KeepExtensions(), KeepRefs())
+	if data, err = json.Marshal(&canonical); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
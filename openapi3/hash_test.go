@@ -0,0 +1,87 @@
+package openapi3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash(t *testing.T) {
+	hash := func(spec string) string {
+		doc, err := NewLoader().LoadFromData([]byte(spec))
+		require.NoError(t, err)
+		h, err := doc.Hash()
+		require.NoError(t, err)
+		require.Len(t, h, 64)
+		return h
+	}
+
+	base := hash(`
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0, x-a: 1, x-b: 2}
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id, name]
+                properties:
+                  id: {type: integer}
+                  name: {type: string}
+`)
+
+	// Reordered keys, other descriptions, examples and formatting.
+	require.Equal(t, base, hash(`{
+  "paths": {"/pets": {"get": {"summary": "List pets", "responses": {"200": {
+    "description": "The pets",
+    "content": {"application/json": {
+      "example": {"id": 1, "name": "Rex"},
+      "schema": {"properties": {"name": {"type": "string"}, "id": {"type": "integer"}},
+                 "required": ["name", "id"], "type": "object"}}}}}}}},
+  "info": {"x-b": 2, "version": "1.0.0", "x-a": 1, "title": "Pets", "description": "Pets"},
+  "openapi": "3.0.0"
+}`))
+
+	// A changed extension or schema changes the hash.
+	require.NotEqual(t, base, hash(`
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0, x-a: 1, x-b: 3}
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id, name]
+                properties:
+                  id: {type: integer}
+                  name: {type: string}
+`))
+	require.NotEqual(t, base, hash(`
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0, x-a: 1, x-b: 2}
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id: {type: integer}
+                  name: {type: string}
+`))
+}
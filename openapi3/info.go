@@ -25,6 +25,11 @@ func (info *Info) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(info)
 }
 
+// MarshalYAML returns the YAML encoding of Info.
+func (info *Info) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(info)
+}
+
 // UnmarshalJSON sets Info to a copy of data.
 func (info *Info) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, info)
@@ -72,6 +77,11 @@ func (contact *Contact) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(contact)
 }
 
+// MarshalYAML returns the YAML encoding of Contact.
+func (contact *Contact) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(contact)
+}
+
 // UnmarshalJSON sets Contact to a copy of data.
 func (contact *Contact) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, contact)
@@ -98,6 +108,11 @@ func (license *License) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(license)
 }
 
+// MarshalYAML returns the YAML encoding of License.
+func (license *License) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(license)
+}
+
 // UnmarshalJSON sets License to a copy of data.
 func (license *License) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, license)
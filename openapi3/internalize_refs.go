@@ -29,22 +29,6 @@ func DefaultRefNameResolver(ref string) string {
 	return filepath.Base(ref)
 }
 
-func schemaNames(s Schemas) []string {
-	out := make([]string, 0, len(s))
-	for i := range s {
-		out = append(out, i)
-	}
-	return out
-}
-
-func parametersMapNames(s ParametersMap) []string {
-	out := make([]string, 0, len(s))
-	for i := range s {
-		out = append(out, i)
-	}
-	return out
-}
-
 func isExternalRef(ref string, parentIsExternal bool) bool {
 	return ref != "" && (!strings.HasPrefix(ref, "#/components/") || parentIsExternal)
 }
@@ -55,15 +39,15 @@ func (doc *T) addSchemaToSpec(s *SchemaRef, refNameResolver RefNameResolver, par
 	}
 
 	name := refNameResolver(s.Ref)
-	if _, ok := doc.Components.Schemas[name]; ok {
+	if doc.Components.Schemas.Value(name) != nil {
 		s.Ref = "#/components/schemas/" + name
 		return true
 	}
 
 	if doc.Components.Schemas == nil {
-		doc.Components.Schemas = make(Schemas)
+		doc.Components.Schemas = NewSchemas()
 	}
-	doc.Components.Schemas[name] = s.Value.NewRef()
+	doc.Components.Schemas.Set(name, s.Value.NewRef())
 	s.Ref = "#/components/schemas/" + name
 	return true
 }
@@ -125,14 +109,14 @@ func (doc *T) addResponseToSpec(r *ResponseRef, refNameResolver RefNameResolver,
 		return false
 	}
 	name := refNameResolver(r.Ref)
-	if _, ok := doc.Components.Responses[name]; ok {
+	if doc.Components.Responses.Value(name) != nil {
 		r.Ref = "#/components/responses/" + name
 		return true
 	}
 	if doc.Components.Responses == nil {
-		doc.Components.Responses = make(Responses)
+		doc.Components.Responses = &Responses{}
 	}
-	doc.Components.Responses[name] = &ResponseRef{Value: r.Value}
+	doc.Components.Responses.Set(name, &ResponseRef{Value: r.Value})
 	r.Ref = "#/components/responses/" + name
 	return true
 }
@@ -214,13 +198,14 @@ func (doc *T) derefSchema(s *Schema, refNameResolver RefNameResolver, parentIsEx
 			}
 		}
 	}
-	for _, s2 := range s.Properties {
+	for _, name := range componentNames(s.Properties) {
+		s2 := s.Properties.Value(name)
 		isExternal := doc.addSchemaToSpec(s2, refNameResolver, parentIsExternal)
 		if s2 != nil {
 			doc.derefSchema(s2.Value, refNameResolver, isExternal || parentIsExternal)
 		}
 	}
-	for _, ref := range []*SchemaRef{s.Not, s.AdditionalProperties, s.Items} {
+	for _, ref := range []*SchemaRef{s.Not, s.AdditionalProperties, s.Items, s.ContentSchema} {
 		isExternal := doc.addSchemaToSpec(ref, refNameResolver, parentIsExternal)
 		if ref != nil {
 			doc.derefSchema(ref.Value, refNameResolver, isExternal || parentIsExternal)
@@ -229,7 +214,8 @@ func (doc *T) derefSchema(s *Schema, refNameResolver RefNameResolver, parentIsEx
 }
 
 func (doc *T) derefHeaders(hs Headers, refNameResolver RefNameResolver, parentIsExternal bool) {
-	for _, h := range hs {
+	for _, name := range componentNames(hs) {
+		h := hs[name]
 		isExternal := doc.addHeaderToSpec(h, refNameResolver, parentIsExternal)
 		if doc.isVisitedHeader(h.Value) {
 			continue
@@ -239,32 +225,37 @@ func (doc *T) derefHeaders(hs Headers, refNameResolver RefNameResolver, parentIs
 }
 
 func (doc *T) derefExamples(es Examples, refNameResolver RefNameResolver, parentIsExternal bool) {
-	for _, e := range es {
+	for _, name := range componentNames(es) {
+		e := es[name]
 		doc.addExampleToSpec(e, refNameResolver, parentIsExternal)
 	}
 }
 
 func (doc *T) derefContent(c Content, refNameResolver RefNameResolver, parentIsExternal bool) {
-	for _, mediatype := range c {
+	for _, mediaType := range componentNames(c) {
+		mediatype := c[mediaType]
 		isExternal := doc.addSchemaToSpec(mediatype.Schema, refNameResolver, parentIsExternal)
 		if mediatype.Schema != nil {
 			doc.derefSchema(mediatype.Schema.Value, refNameResolver, isExternal || parentIsExternal)
 		}
 		doc.derefExamples(mediatype.Examples, refNameResolver, parentIsExternal)
-		for _, e := range mediatype.Encoding {
+		for _, name := range componentNames(mediatype.Encoding) {
+			e := mediatype.Encoding[name]
 			doc.derefHeaders(e.Headers, refNameResolver, parentIsExternal)
 		}
 	}
 }
 
 func (doc *T) derefLinks(ls Links, refNameResolver RefNameResolver, parentIsExternal bool) {
-	for _, l := range ls {
+	for _, name := range componentNames(ls) {
+		l := ls[name]
 		doc.addLinkToSpec(l, refNameResolver, parentIsExternal)
 	}
 }
 
-func (doc *T) derefResponses(es Responses, refNameResolver RefNameResolver, parentIsExternal bool) {
-	for _, e := range es {
+func (doc *T) derefResponses(es *Responses, refNameResolver RefNameResolver, parentIsExternal bool) {
+	for _, status := range componentNames(es) {
+		e := es.Value(status)
 		isExternal := doc.addResponseToSpec(e, refNameResolver, parentIsExternal)
 		if e.Value != nil {
 			doc.derefHeaders(e.Value.Headers, refNameResolver, isExternal || parentIsExternal)
@@ -287,7 +278,8 @@ func (doc *T) derefRequestBody(r RequestBody, refNameResolver RefNameResolver, p
 }
 
 func (doc *T) derefPaths(paths map[string]*PathItem, refNameResolver RefNameResolver, parentIsExternal bool) {
-	for _, ops := range paths {
+	for _, path := range componentNames(paths) {
+		ops := paths[path]
 		// inline full operations
 		ops.Ref = ""
 
@@ -295,12 +287,15 @@ func (doc *T) derefPaths(paths map[string]*PathItem, refNameResolver RefNameReso
 			doc.addParameterToSpec(param, refNameResolver, parentIsExternal)
 		}
 
-		for _, op := range ops.Operations() {
+		operations := ops.Operations()
+		for _, method := range componentNames(operations) {
+			op := operations[method]
 			isExternal := doc.addRequestBodyToSpec(op.RequestBody, refNameResolver, parentIsExternal)
 			if op.RequestBody != nil && op.RequestBody.Value != nil {
 				doc.derefRequestBody(*op.RequestBody.Value, refNameResolver, parentIsExternal || isExternal)
 			}
-			for _, cb := range op.Callbacks {
+			for _, name := range componentNames(op.Callbacks) {
+				cb := op.Callbacks[name]
 				isExternal := doc.addCallbackToSpec(cb, refNameResolver, parentIsExternal)
 				if cb.Value != nil {
 					doc.derefPaths(*cb.Value, refNameResolver, parentIsExternal || isExternal)
@@ -336,17 +331,15 @@ func (doc *T) InternalizeRefs(ctx context.Context, refNameResolver func(ref stri
 	}
 
 	// Handle components section
-	names := schemaNames(doc.Components.Schemas)
-	for _, name := range names {
-		schema := doc.Components.Schemas[name]
+	for _, name := range componentNames(doc.Components.Schemas) {
+		schema := doc.Components.Schemas.Value(name)
 		isExternal := doc.addSchemaToSpec(schema, refNameResolver, false)
 		if schema != nil {
 			schema.Ref = "" // always dereference the top level
 			doc.derefSchema(schema.Value, refNameResolver, isExternal)
 		}
 	}
-	names = parametersMapNames(doc.Components.Parameters)
-	for _, name := range names {
+	for _, name := range componentNames(doc.Components.Parameters) {
 		p := doc.Components.Parameters[name]
 		isExternal := doc.addParameterToSpec(p, refNameResolver, false)
 		if p != nil && p.Value != nil {
@@ -355,7 +348,8 @@ func (doc *T) InternalizeRefs(ctx context.Context, refNameResolver func(ref stri
 		}
 	}
 	doc.derefHeaders(doc.Components.Headers, refNameResolver, false)
-	for _, req := range doc.Components.RequestBodies {
+	for _, name := range componentNames(doc.Components.RequestBodies) {
+		req := doc.Components.RequestBodies[name]
 		isExternal := doc.addRequestBodyToSpec(req, refNameResolver, false)
 		if req != nil && req.Value != nil {
 			req.Ref = "" // always dereference the top level
@@ -363,12 +357,14 @@ func (doc *T) InternalizeRefs(ctx context.Context, refNameResolver func(ref stri
 		}
 	}
 	doc.derefResponses(doc.Components.Responses, refNameResolver, false)
-	for _, ss := range doc.Components.SecuritySchemes {
+	for _, name := range componentNames(doc.Components.SecuritySchemes) {
+		ss := doc.Components.SecuritySchemes[name]
 		doc.addSecuritySchemeToSpec(ss, refNameResolver, false)
 	}
 	doc.derefExamples(doc.Components.Examples, refNameResolver, false)
 	doc.derefLinks(doc.Components.Links, refNameResolver, false)
-	for _, cb := range doc.Components.Callbacks {
+	for _, name := range componentNames(doc.Components.Callbacks) {
+		cb := doc.Components.Callbacks[name]
 		isExternal := doc.addCallbackToSpec(cb, refNameResolver, false)
 		if cb != nil && cb.Value != nil {
 			cb.Ref = "" // always dereference the top level
@@ -376,5 +372,6 @@ func (doc *T) InternalizeRefs(ctx context.Context, refNameResolver func(ref stri
 		}
 	}
 
-	doc.derefPaths(doc.Paths, refNameResolver, false)
+	doc.derefPaths(doc.Paths.items(), refNameResolver, false)
+	doc.derefPaths(doc.Webhooks, refNameResolver, false)
 }
@@ -63,3 +63,40 @@ func TestInternalizeRefs(t *testing.T) {
 		})
 	}
 }
+
+func TestInternalizeRefsIsDeterministic(t *testing.T) {
+	documents := map[string][]byte{
+		"openapi.yaml": []byte(`
+openapi: 3.0.3
+info: {title: API, version: "1"}
+paths: {}
+components:
+  schemas:
+    Owner:
+      type: object
+      properties:
+        b: {$ref: "b/pet.yaml"}
+        a: {$ref: "a/pet.yaml"}
+        c: {$ref: "c/pet.yaml"}
+`),
+		"a/pet.yaml": []byte(`{type: string, description: a}`),
+		"b/pet.yaml": []byte(`{type: string, description: b}`),
+		"c/pet.yaml": []byte(`{type: string, description: c}`),
+	}
+
+	var first []byte
+	for i := 0; i < 10; i++ {
+		doc, err := NewLoader().LoadFromDocuments(documents, "openapi.yaml")
+		require.NoError(t, err)
+		doc.InternalizeRefs(context.Background(), nil)
+		data, err := doc.MarshalJSON()
+		require.NoError(t, err)
+		if first == nil {
+			first = data
+			// Refs sharing a name are internalized in order
+			require.Equal(t, "a", doc.Components.Schemas.Value("pet").Value.Description)
+			continue
+		}
+		require.Equal(t, string(first), string(data))
+	}
+}
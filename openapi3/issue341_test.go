@@ -22,5 +22,5 @@ func TestIssue341(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, []byte(`{"components":{},"info":{"title":"test file","version":"n/a"},"openapi":"3.0.0","paths":{"/testpath":{"get":{"responses":{"200":{"$ref":"#/components/responses/testpath_200_response"}}}}}}`), bs)
 
-	require.Equal(t, "string", doc.Paths["/testpath"].Get.Responses["200"].Value.Content["application/json"].Schema.Value.Type)
+	require.Equal(t, "string", doc.Paths.Value("/testpath").Get.Responses.Value("200").Value.Content["application/json"].Schema.Value.Type)
 }
@@ -36,10 +36,10 @@ info:
 	require.NoError(t, err)
 
 	require.Equal(t, "An API", doc.Info.Title)
-	require.Equal(t, 2, len(doc.Components.Schemas))
-	require.Equal(t, 0, len(doc.Paths))
+	require.Equal(t, 2, doc.Components.Schemas.Len())
+	require.Equal(t, 0, doc.Paths.Len())
 
-	require.Equal(t, "string", doc.Components.Schemas["schema2"].Value.Properties["prop"].Value.Type)
+	require.Equal(t, "string", doc.Components.Schemas.Value("schema2").Value.Properties.Value("prop").Value.Type)
 }
 
 func TestMultijsonTagSerialization(t *testing.T) {
@@ -69,7 +69,7 @@ info:
 	err = doc.Validate(loader.Context)
 	require.NoError(t, err)
 
-	for propName, propSchema := range doc.Components.Schemas {
+	for propName, propSchema := range doc.Components.Schemas.Map() {
 		ap := propSchema.Value.AdditionalProperties
 		apa := propSchema.Value.AdditionalPropertiesAllowed
 
@@ -71,7 +71,7 @@ components:
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
-			err = doc.Components.Schemas["Something"].Value.Properties["field"].Value.VisitJSON(test.value)
+			err = doc.Components.Schemas.Value("Something").Value.Properties.Value("field").Value.VisitJSON(test.value)
 
 			test.checkErr(t, err)
 		})
@@ -0,0 +1,415 @@
+package openapi3
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// orderedMap is implemented by the collections whose keys are ordered, see Paths.InOrder.
+type orderedMap interface {
+	InOrder() []string
+	Len() int
+	Delete(key string)
+	// itemsValue returns the map of the items, possibly nil, for walkers of documents by reflection.
+	itemsValue() reflect.Value
+}
+
+var (
+	_ orderedMap = (*Paths)(nil)
+	_ orderedMap = (*Schemas)(nil)
+	_ orderedMap = (*Responses)(nil)
+)
+
+// collectionItems returns the map of the items of v if it is a collection such as *Paths,
+// whose items must then be removed with Delete, or else v.
+func collectionItems(v reflect.Value) (reflect.Value, orderedMap) {
+	if v.Kind() == reflect.Ptr && v.CanInterface() {
+		if m, ok := v.Interface().(orderedMap); ok {
+			return m.itemsValue(), m
+		}
+	}
+	return v, nil
+}
+
+// sortedKeys returns the keys of a collection in the order of order, or else sorted,
+// given its length and a function calling add for each key.
+func sortedKeys(order []string, n int, keys func(add func(string))) []string {
+	if order != nil {
+		return append([]string(nil), order...)
+	}
+	if n == 0 {
+		return nil
+	}
+	sorted := make([]string, 0, n)
+	keys(func(key string) { sorted = append(sorted, key) })
+	sort.Strings(sorted)
+	return sorted
+}
+
+// orderedWith returns order, the ordered keys of a collection or nil if they are sorted,
+// with key added last unless it is a key already, as exists tells.
+func orderedWith(order []string, key string, exists bool) []string {
+	if order == nil || exists {
+		return order
+	}
+	return append(order, key)
+}
+
+// orderedWithout returns order, the ordered keys of a collection or nil, without key.
+func orderedWithout(order []string, key string) []string {
+	if i := indexOfKey(order, key); i >= 0 {
+		return append(order[:i:i], order[i+1:]...)
+	}
+	return order
+}
+
+// orderedAs returns the keys of a collection in the order of keys, those which are not in keys
+// following sorted, given a function telling whether a key is one of the collection and its keys.
+func orderedAs(keys []string, has func(string) bool, all []string) []string {
+	order := make([]string, 0, len(all))
+	seen := make(map[string]struct{}, len(all))
+	for _, key := range keys {
+		if _, ok := seen[key]; !ok && has(key) {
+			seen[key] = struct{}{}
+			order = append(order, key)
+		}
+	}
+	for _, key := range all {
+		if _, ok := seen[key]; !ok {
+			order = append(order, key)
+		}
+	}
+	return order
+}
+
+// insertKey returns order, the ordered keys of a collection, with key moved or added
+// before or after mark, or else last if mark is not a key.
+func insertKey(order []string, mark, key string, after bool) []string {
+	keys := make([]string, 0, len(order)+1)
+	for _, k := range order {
+		if k != key {
+			keys = append(keys, k)
+		}
+	}
+	i := indexOfKey(keys, mark)
+	if i < 0 {
+		return append(keys, key)
+	}
+	if after {
+		i++
+	}
+	keys = append(keys, "")
+	copy(keys[i+1:], keys[i:])
+	keys[i] = key
+	return keys
+}
+
+func indexOfKey(keys []string, key string) int {
+	for i, k := range keys {
+		if k == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// marshalOrderedJSON returns the JSON object of the values of keys, in the order of keys.
+func marshalOrderedJSON(keys []string, value func(key string) interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		data, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+		buf.WriteByte(':')
+		if data, err = json.Marshal(value(key)); err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// InOrder returns the paths in order.
+//
+// The keys of Paths, Schemas (such as the schemas of components and the properties
+// of schemas) and Responses are sorted, unless they are ordered: InsertBefore and
+// InsertAfter position them, as does the Loader when PreserveKeyOrder is set. Once
+// ordered, keys set with Set follow the others and keep their position when set again.
+// Keys are marshaled to JSON and YAML in order.
+func (paths *Paths) InOrder() []string {
+	if paths == nil {
+		return nil
+	}
+	return sortedKeys(paths.order, len(paths.m), func(add func(string)) {
+		for key := range paths.m {
+			add(key)
+		}
+	})
+}
+
+// Index returns the index of path in InOrder, or -1 if it is not a path of paths.
+func (paths *Paths) Index(path string) int {
+	return indexOfKey(paths.InOrder(), path)
+}
+
+// InsertBefore sets the item of path, positioned right before mark,
+// or else last if mark is not a path of paths.
+func (paths *Paths) InsertBefore(mark, path string, pathItem *PathItem) {
+	order := insertKey(paths.InOrder(), mark, path, false)
+	paths.Set(path, pathItem)
+	paths.order = order
+}
+
+// InsertAfter sets the item of path, positioned right after mark,
+// or else last if mark is not a path of paths.
+func (paths *Paths) InsertAfter(mark, path string, pathItem *PathItem) {
+	order := insertKey(paths.InOrder(), mark, path, true)
+	paths.Set(path, pathItem)
+	paths.order = order
+}
+
+// setOrder orders the paths in the order of keys, others following sorted.
+func (paths *Paths) setOrder(keys []string) {
+	paths.order = orderedAs(keys, func(key string) bool { _, ok := paths.m[key]; return ok }, paths.InOrder())
+}
+
+// MarshalJSON returns the JSON encoding of paths, in order.
+func (paths *Paths) MarshalJSON() ([]byte, error) {
+	if paths == nil {
+		return []byte("null"), nil
+	}
+	return marshalOrderedJSON(paths.InOrder(), func(key string) interface{} { return paths.m[key] })
+}
+
+func (paths *Paths) itemsValue() reflect.Value {
+	return reflect.ValueOf(paths.items())
+}
+
+// MarshalYAML returns the YAML encoding of paths, in order.
+func (paths *Paths) MarshalYAML() (interface{}, error) {
+	return orderedYAMLNode(paths.InOrder(), func(key string) reflect.Value { return reflect.ValueOf(paths.Value(key)) })
+}
+
+// UnmarshalJSON sets paths to a copy of data, with its paths sorted.
+func (paths *Paths) UnmarshalJSON(data []byte) error {
+	var m map[string]*PathItem
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	paths.m, paths.order = m, nil
+	return nil
+}
+
+// InOrder returns the names of the schemas in order (see Paths.InOrder).
+func (schemas *Schemas) InOrder() []string {
+	if schemas == nil {
+		return nil
+	}
+	return sortedKeys(schemas.order, len(schemas.m), func(add func(string)) {
+		for key := range schemas.m {
+			add(key)
+		}
+	})
+}
+
+// Index returns the index of name in InOrder, or -1 if it is not a name of schemas.
+func (schemas *Schemas) Index(name string) int {
+	return indexOfKey(schemas.InOrder(), name)
+}
+
+// InsertBefore sets the schema of name, positioned right before mark,
+// or else last if mark is not a name of schemas.
+func (schemas *Schemas) InsertBefore(mark, name string, schema *SchemaRef) {
+	order := insertKey(schemas.InOrder(), mark, name, false)
+	schemas.Set(name, schema)
+	schemas.order = order
+}
+
+// InsertAfter sets the schema of name, positioned right after mark,
+// or else last if mark is not a name of schemas.
+func (schemas *Schemas) InsertAfter(mark, name string, schema *SchemaRef) {
+	order := insertKey(schemas.InOrder(), mark, name, true)
+	schemas.Set(name, schema)
+	schemas.order = order
+}
+
+// setOrder orders the schemas in the order of keys, others following sorted.
+func (schemas *Schemas) setOrder(keys []string) {
+	schemas.order = orderedAs(keys, func(key string) bool { _, ok := schemas.m[key]; return ok }, schemas.InOrder())
+}
+
+// MarshalJSON returns the JSON encoding of schemas, in order.
+func (schemas *Schemas) MarshalJSON() ([]byte, error) {
+	if schemas == nil {
+		return []byte("null"), nil
+	}
+	return marshalOrderedJSON(schemas.InOrder(), func(key string) interface{} { return schemas.m[key] })
+}
+
+func (schemas *Schemas) itemsValue() reflect.Value {
+	return reflect.ValueOf(schemas.items())
+}
+
+// MarshalYAML returns the YAML encoding of schemas, in order.
+func (schemas *Schemas) MarshalYAML() (interface{}, error) {
+	return orderedYAMLNode(schemas.InOrder(), func(key string) reflect.Value { return reflect.ValueOf(schemas.Value(key)) })
+}
+
+// UnmarshalJSON sets schemas to a copy of data, with its names sorted.
+func (schemas *Schemas) UnmarshalJSON(data []byte) error {
+	var m map[string]*SchemaRef
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	schemas.m, schemas.order = m, nil
+	return nil
+}
+
+// InOrder returns the keys of the responses in order (see Paths.InOrder).
+func (responses *Responses) InOrder() []string {
+	if responses == nil {
+		return nil
+	}
+	return sortedKeys(responses.order, len(responses.m), func(add func(string)) {
+		for key := range responses.m {
+			add(key)
+		}
+	})
+}
+
+// Index returns the index of key in InOrder, or -1 if it is not a key of responses.
+func (responses *Responses) Index(key string) int {
+	return indexOfKey(responses.InOrder(), key)
+}
+
+// InsertBefore sets the response of key, positioned right before mark,
+// or else last if mark is not a key of responses.
+func (responses *Responses) InsertBefore(mark, key string, response *ResponseRef) {
+	order := insertKey(responses.InOrder(), mark, key, false)
+	responses.Set(key, response)
+	responses.order = order
+}
+
+// InsertAfter sets the response of key, positioned right after mark,
+// or else last if mark is not a key of responses.
+func (responses *Responses) InsertAfter(mark, key string, response *ResponseRef) {
+	order := insertKey(responses.InOrder(), mark, key, true)
+	responses.Set(key, response)
+	responses.order = order
+}
+
+// setOrder orders the responses in the order of keys, others following sorted.
+func (responses *Responses) setOrder(keys []string) {
+	responses.order = orderedAs(keys, func(key string) bool { _, ok := responses.m[key]; return ok }, responses.InOrder())
+}
+
+// MarshalJSON returns the JSON encoding of responses, in order.
+func (responses *Responses) MarshalJSON() ([]byte, error) {
+	if responses == nil {
+		return []byte("null"), nil
+	}
+	return marshalOrderedJSON(responses.InOrder(), func(key string) interface{} { return responses.m[key] })
+}
+
+func (responses *Responses) itemsValue() reflect.Value {
+	return reflect.ValueOf(responses.items())
+}
+
+// MarshalYAML returns the YAML encoding of responses, in order.
+func (responses *Responses) MarshalYAML() (interface{}, error) {
+	return orderedYAMLNode(responses.InOrder(), func(key string) reflect.Value { return reflect.ValueOf(responses.Value(key)) })
+}
+
+// UnmarshalJSON sets responses to a copy of data, with its keys sorted.
+func (responses *Responses) UnmarshalJSON(data []byte) error {
+	var m map[string]*ResponseRef
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	responses.m, responses.order = m, nil
+	return nil
+}
+
+// preserveKeyOrder orders the keys of the paths, component schemas and responses,
+// and operation responses of doc in the order of data, the document doc was loaded from.
+func (doc *T) preserveKeyOrder(data []byte) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	if len(root.Content) == 0 {
+		return nil
+	}
+	node := root.Content[0]
+
+	pathsNode := yamlMappingValue(node, "paths")
+	if keys := yamlMappingKeys(pathsNode); len(keys) != 0 && doc.Paths != nil {
+		doc.Paths.setOrder(keys)
+	}
+	for _, path := range yamlMappingKeys(pathsNode) {
+		pathItem := doc.Paths.Value(path)
+		if pathItem == nil {
+			continue
+		}
+		pathItemNode := yamlMappingValue(pathsNode, path)
+		for method, operation := range pathItem.Operations() {
+			operationNode := yamlMappingValue(pathItemNode, strings.ToLower(method))
+			if keys := yamlMappingKeys(yamlMappingValue(operationNode, "responses")); len(keys) != 0 && operation.Responses != nil {
+				operation.Responses.setOrder(keys)
+			}
+		}
+	}
+
+	componentsNode := yamlMappingValue(node, "components")
+	if keys := yamlMappingKeys(yamlMappingValue(componentsNode, "schemas")); len(keys) != 0 && doc.Components.Schemas != nil {
+		doc.Components.Schemas.setOrder(keys)
+	}
+	if keys := yamlMappingKeys(yamlMappingValue(componentsNode, "responses")); len(keys) != 0 && doc.Components.Responses != nil {
+		doc.Components.Responses.setOrder(keys)
+	}
+	return nil
+}
+
+// yamlMappingValue returns the value of key in node, a mapping, or nil.
+func yamlMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			value := node.Content[i+1]
+			if value.Kind == yaml.AliasNode {
+				value = value.Alias
+			}
+			return value
+		}
+	}
+	return nil
+}
+
+// yamlMappingKeys returns the keys of node, a mapping, in order.
+func yamlMappingKeys(node *yaml.Node) []string {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	keys := make([]string, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+	return keys
+}
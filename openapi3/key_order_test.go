@@ -0,0 +1,104 @@
+package openapi3
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestKeyOrder(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      responses:
+        '200': {description: Pets}
+        default: {$ref: '#/components/responses/Error'}
+        '400': {description: Bad request}
+  /cats: {}
+  /birds: {}
+components:
+  schemas:
+    Pet: {type: object}
+    Error: {type: object}
+    Cat: {type: object}
+  responses:
+    Error: {description: Error}
+`
+	loader := NewLoader()
+	loader.PreserveKeyOrder = true
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	responses := doc.Paths.Value("/pets").Get.Responses
+
+	require.Equal(t, []string{"/pets", "/cats", "/birds"}, doc.Paths.InOrder())
+	require.Equal(t, []string{"Pet", "Error", "Cat"}, doc.Components.Schemas.InOrder())
+	require.Equal(t, []string{"200", "default", "400"}, responses.InOrder())
+	require.Equal(t, 1, doc.Paths.Index("/cats"))
+	require.Equal(t, -1, doc.Paths.Index("/dogs"))
+
+	doc.Paths.InsertBefore("/cats", "/dogs", &PathItem{})
+	doc.Paths.InsertAfter("/pets", "/birds", doc.Paths.Value("/birds"))
+	doc.Paths.InsertAfter("/fish", "/hamsters", &PathItem{})
+	// Keys set follow, in the order they are set
+	doc.Paths.Set("/zebras", &PathItem{})
+	doc.Paths.Set("/apes", &PathItem{})
+	require.Equal(t, []string{"/pets", "/birds", "/dogs", "/cats", "/hamsters", "/zebras", "/apes"}, doc.Paths.InOrder())
+
+	doc.Components.Schemas.InsertBefore("Pet", "Id", NewSchemaRef("", NewStringSchema()))
+	require.Equal(t, []string{"Id", "Pet", "Error", "Cat"}, doc.Components.Schemas.InOrder())
+	responses.InsertAfter("200", "201", &ResponseRef{Value: NewResponse().WithDescription("Created")})
+	require.Equal(t, []string{"200", "201", "default", "400"}, responses.InOrder())
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"paths":{"/pets":{"get":{"responses":{"200":{"description":"Pets"},"201":{"description":"Created"},"default":{"$ref":"#/components/responses/Error"},"400":{"description":"Bad request"}}}},"/birds":{},"/dogs":{},"/cats":{},"/hamsters":{},"/zebras":{},"/apes":{}}`)
+	require.Contains(t, string(data), `"schemas":{"Id":{"type":"string"},"Pet":{"type":"object"},"Error":{"type":"object"},"Cat":{"type":"object"}}`)
+
+	var buf bytes.Buffer
+	require.NoError(t, yaml.NewEncoder(&buf).Encode(doc))
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &node))
+	require.Equal(t, []string{"/pets", "/birds", "/dogs", "/cats", "/hamsters", "/zebras", "/apes"},
+		yamlMappingKeys(yamlMappingValue(node.Content[0], "paths")))
+
+	// Keys are sorted unless ordered
+	doc, err = NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	require.Equal(t, []string{"/birds", "/cats", "/pets"}, doc.Paths.InOrder())
+	require.Equal(t, []string{"200", "400", "default"}, doc.Paths.Value("/pets").Get.Responses.InOrder())
+	data, err = json.Marshal(doc.Components.Schemas)
+	require.NoError(t, err)
+	require.Equal(t, `{"Cat":{"type":"object"},"Error":{"type":"object"},"Pet":{"type":"object"}}`, string(data))
+}
+
+func TestKeyOrderSet(t *testing.T) {
+	schemas := NewSchemas()
+	schemas.InsertAfter("", "Dog", NewSchemaRef("", NewObjectSchema()))
+	schemas.InsertAfter("Dog", "Pet", NewSchemaRef("", NewObjectSchema()))
+	schemas.InsertBefore("Dog", "Bird", NewSchemaRef("", NewObjectSchema()))
+	require.Equal(t, []string{"Bird", "Dog", "Pet"}, schemas.InOrder())
+
+	// Setting a key keeps its position, deleting it removes it
+	schemas.Set("Dog", NewSchemaRef("", NewStringSchema()))
+	require.Equal(t, []string{"Bird", "Dog", "Pet"}, schemas.InOrder())
+	require.Equal(t, "string", schemas.Value("Dog").Value.Type)
+	schemas.Delete("Bird")
+	schemas.Set("Bird", NewSchemaRef("", NewObjectSchema()))
+	require.Equal(t, []string{"Dog", "Pet", "Bird"}, schemas.InOrder())
+	require.Equal(t, 3, schemas.Len())
+
+	// Clones keep the order
+	doc := &T{Paths: NewPaths()}
+	doc.Paths.InsertAfter("", "/pets", &PathItem{})
+	doc.Paths.InsertBefore("/pets", "/cats", &PathItem{})
+	clone := doc.Clone()
+	require.Equal(t, []string{"/cats", "/pets"}, clone.Paths.InOrder())
+	clone.Paths.Delete("/cats")
+	require.Equal(t, []string{"/cats", "/pets"}, doc.Paths.InOrder())
+}
@@ -45,6 +45,11 @@ func (link *Link) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(link)
 }
 
+// MarshalYAML returns the YAML encoding of Link.
+func (link *Link) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(link)
+}
+
 // UnmarshalJSON sets Link to a copy of data.
 func (link *Link) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, link)
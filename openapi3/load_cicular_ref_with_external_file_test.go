@@ -29,23 +29,21 @@ func TestLoadCircularRefFromFile(t *testing.T) {
 
 	foo := &openapi3.SchemaRef{
 		Value: &openapi3.Schema{
-			Properties: map[string]*openapi3.SchemaRef{
-				"foo2": {
+			Properties: openapi3.NewSchemas().
+				With("foo2", &openapi3.SchemaRef{
 					Ref: "other.yml#/components/schemas/Foo2", // reference to an external file
 					Value: &openapi3.Schema{
-						Properties: map[string]*openapi3.SchemaRef{
-							"id": {
-								Value: &openapi3.Schema{Type: "string"}},
-						},
+						Properties: openapi3.NewSchemas().
+							With("id", &openapi3.SchemaRef{
+								Value: &openapi3.Schema{Type: "string"}}),
 					},
-				},
-			},
+				}),
 		},
 	}
-	bar := &openapi3.SchemaRef{Value: &openapi3.Schema{Properties: make(map[string]*openapi3.SchemaRef)}}
+	bar := &openapi3.SchemaRef{Value: &openapi3.Schema{Properties: openapi3.NewSchemas()}}
 	// circular reference
-	bar.Value.Properties["foo"] = &openapi3.SchemaRef{Ref: "#/components/schemas/Foo", Value: foo.Value}
-	foo.Value.Properties["bar"] = &openapi3.SchemaRef{Ref: "#/components/schemas/Bar", Value: bar.Value}
+	bar.Value.Properties.Set("foo", &openapi3.SchemaRef{Ref: "#/components/schemas/Foo", Value: foo.Value})
+	foo.Value.Properties.Set("bar", &openapi3.SchemaRef{Ref: "#/components/schemas/Bar", Value: bar.Value})
 
 	want := &openapi3.T{
 		OpenAPI: "3.0.3",
@@ -54,10 +52,9 @@ func TestLoadCircularRefFromFile(t *testing.T) {
 			Version: "1.0",
 		},
 		Components: openapi3.Components{
-			Schemas: map[string]*openapi3.SchemaRef{
-				"Foo": foo,
-				"Bar": bar,
-			},
+			Schemas: openapi3.NewSchemas().
+				With("Foo", foo).
+				With("Bar", bar),
 		},
 	}
 
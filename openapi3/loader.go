@@ -12,6 +12,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/invopop/yaml"
 )
@@ -35,11 +36,35 @@ type Loader struct {
 	// ReadFromURIFunc allows overriding the any file/URL reading func
 	ReadFromURIFunc ReadFromURIFunc
 
+	// Sandbox, when set, restricts where the documents external refs point to are read.
+	Sandbox *Sandbox
+
+	// Set FetchConcurrency above 1 so the external documents referenced by loaded specs,
+	// directly or not, are read up to FetchConcurrency at a time before refs are resolved.
+	// ReadFromURIFunc must then be safe for concurrent use.
+	FetchConcurrency int
+
+	// FetchRetries is how many times reading an external document is retried after
+	// a transient failure, i.e. a network error or an HTTPStatusError with a 5xx status code.
+	FetchRetries int
+
+	// FetchRetryDelay is how long reading an external document waits before its first retry,
+	// doubling for each later one up to 5s, unless Context is done. It defaults to 100ms.
+	FetchRetryDelay time.Duration
+
+	// Set PreserveKeyOrder so the paths, component schemas and responses, and operation
+	// responses of loaded documents keep the order of their keys, e.g. when marshaled
+	// (see Paths.InOrder), as long as the documents are not garbage collected.
+	// They are sorted otherwise.
+	PreserveKeyOrder bool
+
 	Context context.Context
 
 	rootDir      string
 	rootLocation string
 
+	fetched map[string]*fetchedDocument
+
 	visitedPathItemRefs map[string]struct{}
 
 	visitedDocuments map[string]*T
@@ -68,7 +93,12 @@ func (loader *Loader) resetVisitedPathItemRefs() {
 // LoadFromURI loads a spec from a remote URL
 func (loader *Loader) LoadFromURI(location *url.URL) (*T, error) {
 	loader.resetVisitedPathItemRefs()
-	return loader.loadFromURIInternal(location)
+	data, err := loader.fetchURL(location)
+	if err != nil {
+		return nil, err
+	}
+	loader.prefetch(data, location)
+	return loader.loadFromDataWithPathInternal(data, location)
 }
 
 // LoadFromFile loads a spec from a local file path
@@ -77,6 +107,22 @@ func (loader *Loader) LoadFromFile(location string) (*T, error) {
 	return loader.LoadFromURI(&url.URL{Path: filepath.ToSlash(location)})
 }
 
+// LoadFromDocuments loads the spec at location, the URI or file path of one of documents,
+// resolving its external refs from documents only (see ReadFromMap).
+// It overrides ReadFromURIFunc and enables IsExternalRefsAllowed.
+func (loader *Loader) LoadFromDocuments(documents map[string][]byte, location string) (*T, error) {
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = ReadFromMap(documents)
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" && u.Host == "" {
+		return loader.LoadFromFile(location)
+	}
+	return loader.LoadFromURI(u)
+}
+
 func (loader *Loader) loadFromURIInternal(location *url.URL) (*T, error) {
 	data, err := loader.readURL(location)
 	if err != nil {
@@ -122,20 +168,19 @@ func (loader *Loader) loadSingleElementFromURI(ref string, rootPath *url.URL, el
 	return resolvedPath, nil
 }
 
-func (loader *Loader) readURL(location *url.URL) ([]byte, error) {
-	if f := loader.ReadFromURIFunc; f != nil {
-		return f(loader, location)
-	}
-	return DefaultReadFromURI(loader, location)
-}
-
 // LoadFromData loads a spec from a byte array
 func (loader *Loader) LoadFromData(data []byte) (*T, error) {
 	loader.resetVisitedPathItemRefs()
+	loader.fetched = nil
 	doc := &T{}
 	if err := unmarshal(data, doc); err != nil {
 		return nil, err
 	}
+	if loader.PreserveKeyOrder {
+		if err := doc.preserveKeyOrder(data); err != nil {
+			return nil, err
+		}
+	}
 	if err := loader.ResolveRefsIn(doc, nil); err != nil {
 		return nil, err
 	}
@@ -146,6 +191,7 @@ func (loader *Loader) LoadFromData(data []byte) (*T, error) {
 // elements and returns a *T with all resolved data or an error if unable to load data or resolve refs.
 func (loader *Loader) LoadFromDataWithPath(data []byte, location *url.URL) (*T, error) {
 	loader.resetVisitedPathItemRefs()
+	loader.prefetch(data, location)
 	return loader.loadFromDataWithPathInternal(data, location)
 }
 
@@ -165,6 +211,11 @@ func (loader *Loader) loadFromDataWithPathInternal(data []byte, location *url.UR
 	if err := unmarshal(data, doc); err != nil {
 		return nil, err
 	}
+	if loader.PreserveKeyOrder {
+		if err := doc.preserveKeyOrder(data); err != nil {
+			return nil, err
+		}
+	}
 	if err := loader.ResolveRefsIn(doc, location); err != nil {
 		return nil, err
 	}
@@ -207,12 +258,12 @@ func (loader *Loader) ResolveRefsIn(doc *T, location *url.URL) (err error) {
 			return
 		}
 	}
-	for _, component := range components.Responses {
+	for _, component := range components.Responses.items() {
 		if err = loader.resolveResponseRef(doc, component, location); err != nil {
 			return
 		}
 	}
-	for _, component := range components.Schemas {
+	for _, component := range components.Schemas.items() {
 		if err = loader.resolveSchemaRef(doc, component, location, []string{}); err != nil {
 			return
 		}
@@ -242,7 +293,7 @@ func (loader *Loader) ResolveRefsIn(doc *T, location *url.URL) (err error) {
 	}
 
 	// Visit all operations
-	for entrypoint, pathItem := range doc.Paths {
+	for entrypoint, pathItem := range doc.Paths.items() {
 		if pathItem == nil {
 			continue
 		}
@@ -251,6 +302,18 @@ func (loader *Loader) ResolveRefsIn(doc *T, location *url.URL) (err error) {
 		}
 	}
 
+	for _, name := range componentNames(doc.Webhooks) {
+		pathItem := doc.Webhooks[name]
+		if pathItem == nil {
+			continue
+		}
+		if err = loader.resolvePathItemRef(doc, "webhooks:"+name, pathItem, location); err != nil {
+			return
+		}
+	}
+
+	// Operations sharing an operationId are reported by validation
+	doc.operationIDs, _ = doc.Paths.operationIndex()
 	return
 }
 
@@ -367,7 +430,8 @@ func drillIntoField(cursor interface{}, fieldName string) (interface{}, error) {
 		return s.Value.AdditionalProperties, nil
 	}
 
-	switch val := reflect.Indirect(reflect.ValueOf(cursor)); val.Kind() {
+	items, _ := collectionItems(reflect.ValueOf(cursor))
+	switch val := reflect.Indirect(items); val.Kind() {
 	case reflect.Map:
 		elementValue := val.MapIndex(reflect.ValueOf(fieldName))
 		if !elementValue.IsValid() {
@@ -745,6 +809,7 @@ func (loader *Loader) resolveSchemaRef(doc *T, component *SchemaRef, documentPat
 	if value == nil {
 		return nil
 	}
+	value.convertExclusiveBounds(doc.OpenAPI)
 
 	// ResolveRefs referred schemas
 	if v := value.Items; v != nil {
@@ -752,7 +817,7 @@ func (loader *Loader) resolveSchemaRef(doc *T, component *SchemaRef, documentPat
 			return err
 		}
 	}
-	for _, v := range value.Properties {
+	for _, v := range value.Properties.items() {
 		if err := loader.resolveSchemaRef(doc, v, documentPath, visited); err != nil {
 			return err
 		}
@@ -767,6 +832,11 @@ func (loader *Loader) resolveSchemaRef(doc *T, component *SchemaRef, documentPat
 			return err
 		}
 	}
+	if v := value.ContentSchema; v != nil {
+		if err := loader.resolveSchemaRef(doc, v, documentPath, visited); err != nil {
+			return err
+		}
+	}
 	for _, v := range value.AllOf {
 		if err := loader.resolveSchemaRef(doc, v, documentPath, visited); err != nil {
 			return err
@@ -1019,7 +1089,7 @@ func (loader *Loader) resolvePathItemRef(doc *T, entrypoint string, pathItem *Pa
 			if doc.Paths == nil {
 				return failedToResolveRefFragmentPart(ref, "paths")
 			}
-			resolved := doc.Paths[id]
+			resolved := doc.Paths.Value(id)
 			if resolved == nil {
 				return failedToResolveRefFragmentPart(ref, id)
 			}
@@ -1046,7 +1116,7 @@ func (loader *Loader) resolvePathItemRefContinued(doc *T, pathItem *PathItem, do
 				return
 			}
 		}
-		for _, response := range operation.Responses {
+		for _, response := range operation.Responses.items() {
 			if err = loader.resolveResponseRef(doc, response, documentPath); err != nil {
 				return
 			}
@@ -0,0 +1,101 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/invopop/yaml"
+	"github.com/stretchr/testify/require"
+)
+
+// largeSpec returns a document with the given number of resources, each having
+// a schema of components and CRUD operations using it.
+func largeSpec(resources int) map[string]interface{} {
+	paths := make(map[string]interface{})
+	schemas := make(map[string]interface{})
+	for i := 0; i < resources; i++ {
+		name := fmt.Sprintf("Resource%d", i)
+		ref := map[string]interface{}{"$ref": "#/components/schemas/" + name}
+		schemas[name] = map[string]interface{}{
+			"type":     "object",
+			"required": []string{"id", "name"},
+			"properties": map[string]interface{}{
+				"id":          map[string]interface{}{"type": "integer", "format": "int64"},
+				"name":        map[string]interface{}{"type": "string", "maxLength": 100},
+				"tags":        map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				"description": map[string]interface{}{"type": "string", "description": "A long enough description of the resource"},
+				"metadata": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": map[string]interface{}{"type": "string"},
+				},
+			},
+		}
+		content := map[string]interface{}{"application/json": map[string]interface{}{"schema": ref}}
+		idParameter := map[string]interface{}{
+			"name": "id", "in": "path", "required": true,
+			"schema": map[string]interface{}{"type": "integer"},
+		}
+		paths[fmt.Sprintf("/resources%d", i)] = map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": fmt.Sprintf("list%s", name),
+				"parameters": []interface{}{
+					map[string]interface{}{"name": "limit", "in": "query", "schema": map[string]interface{}{"type": "integer"}},
+				},
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{
+						"description": "OK",
+						"content": map[string]interface{}{"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{"type": "array", "items": ref},
+						}},
+					},
+				},
+			},
+			"post": map[string]interface{}{
+				"operationId": fmt.Sprintf("create%s", name),
+				"requestBody": map[string]interface{}{"required": true, "content": content},
+				"responses":   map[string]interface{}{"201": map[string]interface{}{"description": "Created", "content": content}},
+			},
+		}
+		paths[fmt.Sprintf("/resources%d/{id}", i)] = map[string]interface{}{
+			"parameters": []interface{}{idParameter},
+			"get": map[string]interface{}{
+				"operationId": fmt.Sprintf("get%s", name),
+				"responses":   map[string]interface{}{"200": map[string]interface{}{"description": "OK", "content": content}},
+			},
+			"delete": map[string]interface{}{
+				"operationId": fmt.Sprintf("delete%s", name),
+				"responses":   map[string]interface{}{"204": map[string]interface{}{"description": "Deleted"}},
+			},
+		}
+	}
+	return map[string]interface{}{
+		"openapi":    "3.0.3",
+		"info":       map[string]interface{}{"title": "Large", "version": "1.0.0"},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+}
+
+func benchmarkLoadFromData(b *testing.B, data []byte) {
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewLoader().LoadFromData(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoadFromDataJSON(b *testing.B) {
+	data, err := json.Marshal(largeSpec(500))
+	require.NoError(b, err)
+	benchmarkLoadFromData(b, data)
+}
+
+func BenchmarkLoadFromDataYAML(b *testing.B) {
+	data, err := yaml.Marshal(largeSpec(500))
+	require.NoError(b, err)
+	benchmarkLoadFromData(b, data)
+}
@@ -36,7 +36,7 @@ func TestJSONSpecResponseDescriptionEmptiness(t *testing.T) {
 		loader := NewLoader()
 		doc, err := loader.LoadFromData(spec)
 		require.NoError(t, err)
-		got := doc.Paths["/path1"].Get.Responses["200"].Value.Description
+		got := doc.Paths.Value("/path1").Get.Responses.Value("200").Value.Description
 		expected := ""
 		require.Equal(t, &expected, got)
 		t.Log("Empty description provided: valid spec")
@@ -49,7 +49,7 @@ func TestJSONSpecResponseDescriptionEmptiness(t *testing.T) {
 		loader := NewLoader()
 		doc, err := loader.LoadFromData(spec)
 		require.NoError(t, err)
-		got := doc.Paths["/path1"].Get.Responses["200"].Value.Description
+		got := doc.Paths.Value("/path1").Get.Responses.Value("200").Value.Description
 		expected := "My response"
 		require.Equal(t, &expected, got)
 		t.Log("Non-empty description provided: valid spec")
@@ -61,7 +61,7 @@ func TestJSONSpecResponseDescriptionEmptiness(t *testing.T) {
 		loader := NewLoader()
 		doc, err := loader.LoadFromData(data)
 		require.NoError(t, err)
-		got := doc.Paths["/path1"].Get.Responses["200"].Value.Description
+		got := doc.Paths.Value("/path1").Get.Responses.Value("200").Value.Description
 		require.Nil(t, got)
 		t.Log("No description provided: invalid spec")
 		err = doc.Validate(loader.Context)
@@ -0,0 +1,185 @@
+package openapi3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fetchedDocument is the outcome of reading an external document ahead of resolving refs.
+type fetchedDocument struct {
+	data []byte
+	err  error
+}
+
+// readURL reads the document a ref points to.
+func (loader *Loader) readURL(location *url.URL) ([]byte, error) {
+	if sandbox := loader.Sandbox; sandbox != nil {
+		if err := sandbox.Check(location); err != nil {
+			return nil, err
+		}
+	}
+	if fetched, ok := loader.fetched[location.String()]; ok {
+		return fetched.data, fetched.err
+	}
+	return loader.fetchURL(location)
+}
+
+const (
+	defaultFetchRetryDelay = 100 * time.Millisecond
+	maxFetchRetryDelay     = 5 * time.Second
+)
+
+// fetchURL reads the document at location, retrying transient failures up to FetchRetries
+// times with exponential backoff.
+func (loader *Loader) fetchURL(location *url.URL) (data []byte, err error) {
+	read := loader.ReadFromURIFunc
+	if read == nil {
+		read = DefaultReadFromURI
+	}
+	ctx := loader.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	delay := loader.FetchRetryDelay
+	if delay <= 0 {
+		delay = defaultFetchRetryDelay
+	}
+	for attempt := 0; ; attempt++ {
+		if data, err = read(loader, location); err == nil {
+			return
+		}
+		if attempt >= loader.FetchRetries || !isTransientFetchError(err) {
+			return
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if delay *= 2; delay > maxFetchRetryDelay {
+			delay = maxFetchRetryDelay
+		}
+	}
+}
+
+// isTransientFetchError returns whether reading a document may succeed when retried after err:
+// when the server failed with a 5xx status code, or the network did.
+func isTransientFetchError(err error) bool {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	// Requests fail with url.Errors, which are net.Errors whatever their cause, e.g. redirects
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// prefetch reads the external documents data, the document at location, references
+// directly or not, up to FetchConcurrency at a time, so that resolving refs then finds
+// them read already.
+func (loader *Loader) prefetch(data []byte, location *url.URL) {
+	loader.fetched = nil
+	if loader.FetchConcurrency <= 1 || location == nil || !loader.IsExternalRefsAllowed {
+		return
+	}
+	fetched := make(map[string]*fetchedDocument)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, loader.FetchConcurrency)
+
+	var visit func(data []byte, location *url.URL)
+	visit = func(data []byte, location *url.URL) {
+		for _, uri := range externalRefLocations(data, location) {
+			if loader.Sandbox != nil && loader.Sandbox.Check(uri) != nil {
+				continue
+			}
+			key := uri.String()
+			mu.Lock()
+			_, ok := fetched[key]
+			document := &fetchedDocument{}
+			if !ok {
+				fetched[key] = document
+			}
+			mu.Unlock()
+			if ok {
+				continue
+			}
+
+			wg.Add(1)
+			go func(uri *url.URL) {
+				defer wg.Done()
+				sem <- struct{}{}
+				document.data, document.err = loader.fetchURL(uri)
+				<-sem
+				if document.err == nil {
+					visit(document.data, uri)
+				}
+			}(uri)
+		}
+	}
+	visit(data, location)
+	wg.Wait()
+
+	loader.fetched = fetched
+}
+
+// externalRefLocations returns the locations of the documents the $refs of data point to,
+// relative to location, in order.
+func externalRefLocations(data []byte, location *url.URL) []*url.URL {
+	var doc interface{}
+	if err := unmarshal(data, &doc); err != nil {
+		return nil
+	}
+	refs := make(map[string]struct{})
+	collectRefs(doc, refs)
+
+	locations := make(map[string]*url.URL, len(refs))
+	for ref := range refs {
+		if strings.HasPrefix(ref, "#") {
+			continue
+		}
+		parsed, err := url.Parse(ref)
+		if err != nil || (parsed.Path == "" && parsed.Host == "") {
+			continue
+		}
+		parsed.Fragment = ""
+		resolved, err := resolvePath(location, parsed)
+		if err != nil {
+			continue
+		}
+		locations[resolved.String()] = resolved
+	}
+	result := make([]*url.URL, 0, len(locations))
+	for _, key := range componentNames(locations) {
+		result = append(result, locations[key])
+	}
+	return result
+}
+
+func collectRefs(v interface{}, refs map[string]struct{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			refs[ref] = struct{}{}
+		}
+		for _, value := range v {
+			collectRefs(value, refs)
+		}
+	case []interface{}:
+		for _, value := range v {
+			collectRefs(value, refs)
+		}
+	}
+}
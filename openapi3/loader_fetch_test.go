@@ -0,0 +1,156 @@
+package openapi3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoaderFetchConcurrency(t *testing.T) {
+	const schemas = 8
+	var paths strings.Builder
+	for i := 0; i < schemas; i++ {
+		fmt.Fprintf(&paths, "  /p%d:\n    $ref: paths/p%d.yaml\n", i, i)
+	}
+	root := "openapi: 3.0.0\ninfo: {title: Test, version: 1.0.0}\npaths:\n" + paths.String()
+
+	var mu sync.Mutex
+	reads := make(map[string]int)
+	var inFlight, maxInFlight int32
+	failed := make(map[string]bool)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		reads[r.URL.Path]++
+		// Every document fails to be read once
+		fail := !failed[r.URL.Path]
+		failed[r.URL.Path] = true
+		mu.Unlock()
+		if fail {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/openapi.yaml":
+			fmt.Fprint(w, root)
+		case strings.HasPrefix(r.URL.Path, "/paths/"):
+			fmt.Fprint(w, "get:\n  responses:\n    '200':\n      description: OK\n      content:\n        application/json:\n          schema: {$ref: '../schemas.yaml#/Pet'}\n")
+		case r.URL.Path == "/schemas.yaml":
+			fmt.Fprint(w, "Pet: {type: string}\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	location, err := url.Parse(ts.URL + "/openapi.yaml")
+	require.NoError(t, err)
+
+	loader := NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = ReadFromHTTP(ts.Client())
+	loader.FetchConcurrency = 4
+	loader.FetchRetries = 1
+	loader.FetchRetryDelay = time.Millisecond
+	doc, err := loader.LoadFromURI(location)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	require.Equal(t, schemas, doc.Paths.Len())
+	require.Equal(t, "string", doc.Paths.Value("/p3").Get.Responses.Get(200).Value.Content.Get("application/json").Schema.Value.Type)
+
+	require.Len(t, reads, schemas+2)
+	for path, n := range reads {
+		require.Equal(t, 2, n, path)
+	}
+	require.Greater(t, maxInFlight, int32(1))
+	require.LessOrEqual(t, maxInFlight, int32(4))
+}
+
+func TestLoaderFetchRetries(t *testing.T) {
+	attempts := 0
+	loader := NewLoader()
+	loader.ReadFromURIFunc = func(*Loader, *url.URL) ([]byte, error) {
+		attempts++
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("attempt %d failed", attempts)}
+	}
+	loader.FetchRetries = 2
+	loader.FetchRetryDelay = time.Millisecond
+	_, err := loader.LoadFromURI(&url.URL{Path: "openapi.yaml"})
+	require.EqualError(t, err, "dial tcp: attempt 3 failed")
+	require.Equal(t, 3, attempts)
+
+	// Failures which are not transient are not retried
+	for _, failure := range []error{
+		ErrURINotSupported,
+		errors.New("invalid document"),
+		&HTTPStatusError{URL: "http://example.com/openapi.yaml", StatusCode: http.StatusNotFound},
+		&url.Error{Op: "Get", URL: "http://example.com/openapi.yaml", Err: ErrRefNotAllowed},
+	} {
+		attempts = 0
+		loader.ReadFromURIFunc = func(*Loader, *url.URL) ([]byte, error) {
+			attempts++
+			return nil, failure
+		}
+		_, err = loader.LoadFromURI(&url.URL{Path: "openapi.yaml"})
+		require.Equal(t, failure, err)
+		require.Equal(t, 1, attempts, failure.Error())
+	}
+}
+
+func TestLoaderFetchRetriesBackoff(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+	location, err := url.Parse(ts.URL + "/openapi.yaml")
+	require.NoError(t, err)
+
+	loader := NewLoader()
+	loader.ReadFromURIFunc = ReadFromHTTP(ts.Client())
+	loader.FetchRetries = 2
+	loader.FetchRetryDelay = 20 * time.Millisecond
+	_, err = loader.LoadFromURI(location)
+	require.EqualError(t, err, fmt.Sprintf("error loading %q: request returned status code 503", location.String()))
+	require.Len(t, times, 3)
+	require.GreaterOrEqual(t, times[1].Sub(times[0]), 20*time.Millisecond)
+	require.GreaterOrEqual(t, times[2].Sub(times[1]), 40*time.Millisecond)
+
+	// Retries stop once the context is done
+	times = nil
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	loader.Context = ctx
+	loader.FetchRetries = 10
+	loader.FetchRetryDelay = time.Hour
+	start := time.Now()
+	_, err = loader.LoadFromURI(location)
+	require.Error(t, err)
+	require.Len(t, times, 1)
+	require.Less(t, int64(time.Since(start)), int64(time.Second))
+}
@@ -0,0 +1,76 @@
+package openapi3
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoaderLoadFromDocuments(t *testing.T) {
+	documents := map[string][]byte{
+		"specs/openapi.yaml": []byte(`
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    $ref: paths/pets.yaml
+components:
+  schemas:
+    Error:
+      $ref: https://example.com/common.json#/components/schemas/Error
+`),
+		"./specs/paths/pets.yaml": []byte(`
+get:
+  responses:
+    '200':
+      description: OK
+      content:
+        application/json:
+          schema: {$ref: '../schemas.yaml#/Pet'}
+`),
+		"specs/schemas.yaml": []byte(`
+Pet:
+  type: object
+  properties:
+    name: {type: string}
+`),
+		"https://example.com/common.json": []byte(`{"components": {"schemas": {"Error": {"type": "string"}}}}`),
+	}
+
+	loader := NewLoader()
+	doc, err := loader.LoadFromDocuments(documents, "specs/openapi.yaml")
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	schema := doc.Paths.Value("/pets").Get.Responses.Get(200).Value.Content.Get("application/json").Schema.Value
+	require.Equal(t, "string", schema.Properties.Value("name").Value.Type)
+	require.Equal(t, "string", doc.Components.Schemas.Value("Error").Value.Type)
+
+	documents["https://example.com/openapi.yaml"] = []byte(`
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    Error: {$ref: 'common.json#/components/schemas/Error'}
+`)
+	doc, err = NewLoader().LoadFromDocuments(documents, "https://example.com/openapi.yaml")
+	require.NoError(t, err)
+	require.Equal(t, "string", doc.Components.Schemas.Value("Error").Value.Type)
+
+	_, err = NewLoader().LoadFromDocuments(documents, "specs/missing.yaml")
+	require.True(t, errors.Is(err, ErrURINotSupported))
+}
+
+func TestReadFromMapFallback(t *testing.T) {
+	read := ReadFromURIs(ReadFromMap(map[string][]byte{"a.yaml": []byte("a")}), func(*Loader, *url.URL) ([]byte, error) {
+		return []byte("fallback"), nil
+	})
+	data, err := read(nil, &url.URL{Path: "./a.yaml"})
+	require.NoError(t, err)
+	require.Equal(t, "a", string(data))
+	data, err = read(nil, &url.URL{Path: "b.yaml"})
+	require.NoError(t, err)
+	require.Equal(t, "fallback", string(data))
+}
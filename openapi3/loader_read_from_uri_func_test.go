@@ -20,7 +20,7 @@ func TestLoaderReadFromURIFunc(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, doc)
 	require.NoError(t, doc.Validate(loader.Context))
-	require.Equal(t, "bar", doc.Paths["/foo"].Get.Responses.Get(200).Value.Content.Get("application/json").Schema.Value.Properties["foo2"].Value.Properties["foo"].Value.Properties["bar"].Value.Example)
+	require.Equal(t, "bar", doc.Paths.Value("/foo").Get.Responses.Get(200).Value.Content.Get("application/json").Schema.Value.Properties.Value("foo2").Value.Properties.Value("foo").Value.Properties.Value("bar").Value.Example)
 }
 
 type multipleSourceLoaderExample struct {
@@ -67,7 +67,7 @@ func TestResolveSchemaExternalRef(t *testing.T) {
 	err = doc.Validate(loader.Context)
 	require.NoError(t, err)
 
-	refRootVisited := doc.Components.Schemas["Root"].Value.AllOf[0]
+	refRootVisited := doc.Components.Schemas.Value("Root").Value.AllOf[0]
 	require.Equal(t, fmt.Sprintf("%s#/components/schemas/External", externalLocation.String()), refRootVisited.Ref)
 	require.NotNil(t, refRootVisited.Value)
 }
@@ -26,8 +26,8 @@ var refTestDataEntries = []refTestDataEntry{
 		name:            "SchemaRef",
 		contentTemplate: externalSchemaRefTemplate,
 		testFunc: func(t *testing.T, doc *T) {
-			require.NotNil(t, doc.Components.Schemas["TestSchema"].Value.Type)
-			require.Equal(t, "string", doc.Components.Schemas["TestSchema"].Value.Type)
+			require.NotNil(t, doc.Components.Schemas.Value("TestSchema").Value.Type)
+			require.Equal(t, "string", doc.Components.Schemas.Value("TestSchema").Value.Type)
 		},
 	},
 	{
@@ -35,7 +35,7 @@ var refTestDataEntries = []refTestDataEntry{
 		contentTemplate: externalResponseRefTemplate,
 		testFunc: func(t *testing.T, doc *T) {
 			desc := "description"
-			require.Equal(t, &desc, doc.Components.Responses["TestResponse"].Value.Description)
+			require.Equal(t, &desc, doc.Components.Responses.Value("TestResponse").Value.Description)
 		},
 	},
 	{
@@ -81,42 +81,42 @@ var refTestDataEntries = []refTestDataEntry{
 		name:            "PathParameterRef",
 		contentTemplate: externalPathParameterRefTemplate,
 		testFunc: func(t *testing.T, doc *T) {
-			require.NotNil(t, doc.Paths["/test/{id}"].Parameters[0].Value.Name)
-			require.Equal(t, "id", doc.Paths["/test/{id}"].Parameters[0].Value.Name)
+			require.NotNil(t, doc.Paths.Value("/test/{id}").Parameters[0].Value.Name)
+			require.Equal(t, "id", doc.Paths.Value("/test/{id}").Parameters[0].Value.Name)
 		},
 	},
 	{
 		name:            "PathOperationParameterRef",
 		contentTemplate: externalPathOperationParameterRefTemplate,
 		testFunc: func(t *testing.T, doc *T) {
-			require.NotNil(t, doc.Paths["/test/{id}"].Get.Parameters[0].Value)
-			require.Equal(t, "id", doc.Paths["/test/{id}"].Get.Parameters[0].Value.Name)
+			require.NotNil(t, doc.Paths.Value("/test/{id}").Get.Parameters[0].Value)
+			require.Equal(t, "id", doc.Paths.Value("/test/{id}").Get.Parameters[0].Value.Name)
 		},
 	},
 	{
 		name:            "PathOperationRequestBodyRef",
 		contentTemplate: externalPathOperationRequestBodyRefTemplate,
 		testFunc: func(t *testing.T, doc *T) {
-			require.NotNil(t, doc.Paths["/test"].Post.RequestBody.Value)
-			require.NotNil(t, doc.Paths["/test"].Post.RequestBody.Value.Content)
+			require.NotNil(t, doc.Paths.Value("/test").Post.RequestBody.Value)
+			require.NotNil(t, doc.Paths.Value("/test").Post.RequestBody.Value.Content)
 		},
 	},
 	{
 		name:            "PathOperationResponseRef",
 		contentTemplate: externalPathOperationResponseRefTemplate,
 		testFunc: func(t *testing.T, doc *T) {
-			require.NotNil(t, doc.Paths["/test"].Post.Responses["default"].Value)
+			require.NotNil(t, doc.Paths.Value("/test").Post.Responses.Value("default").Value)
 			desc := "description"
-			require.Equal(t, &desc, doc.Paths["/test"].Post.Responses["default"].Value.Description)
+			require.Equal(t, &desc, doc.Paths.Value("/test").Post.Responses.Value("default").Value.Description)
 		},
 	},
 	{
 		name:            "PathOperationParameterSchemaRef",
 		contentTemplate: externalPathOperationParameterSchemaRefTemplate,
 		testFunc: func(t *testing.T, doc *T) {
-			require.NotNil(t, doc.Paths["/test/{id}"].Get.Parameters[0].Value.Schema.Value)
-			require.Equal(t, "string", doc.Paths["/test/{id}"].Get.Parameters[0].Value.Schema.Value.Type)
-			require.Equal(t, "id", doc.Paths["/test/{id}"].Get.Parameters[0].Value.Name)
+			require.NotNil(t, doc.Paths.Value("/test/{id}").Get.Parameters[0].Value.Schema.Value)
+			require.Equal(t, "string", doc.Paths.Value("/test/{id}").Get.Parameters[0].Value.Schema.Value.Type)
+			require.Equal(t, "id", doc.Paths.Value("/test/{id}").Get.Parameters[0].Value.Name)
 		},
 	},
 
@@ -124,7 +124,7 @@ var refTestDataEntries = []refTestDataEntry{
 		name:            "PathOperationParameterRefWithContentInQuery",
 		contentTemplate: externalPathOperationParameterWithContentInQueryTemplate,
 		testFunc: func(t *testing.T, doc *T) {
-			schemaRef := doc.Paths["/test/{id}"].Get.Parameters[0].Value.Content["application/json"].Schema
+			schemaRef := doc.Paths.Value("/test/{id}").Get.Parameters[0].Value.Content["application/json"].Schema
 			require.NotNil(t, schemaRef.Value)
 			require.Equal(t, "string", schemaRef.Value.Type)
 		},
@@ -134,36 +134,36 @@ var refTestDataEntries = []refTestDataEntry{
 		name:            "PathOperationRequestBodyExampleRef",
 		contentTemplate: externalPathOperationRequestBodyExampleRefTemplate,
 		testFunc: func(t *testing.T, doc *T) {
-			require.NotNil(t, doc.Paths["/test"].Post.RequestBody.Value.Content["application/json"].Examples["application/json"].Value)
-			require.Equal(t, "description", doc.Paths["/test"].Post.RequestBody.Value.Content["application/json"].Examples["application/json"].Value.Description)
+			require.NotNil(t, doc.Paths.Value("/test").Post.RequestBody.Value.Content["application/json"].Examples["application/json"].Value)
+			require.Equal(t, "description", doc.Paths.Value("/test").Post.RequestBody.Value.Content["application/json"].Examples["application/json"].Value.Description)
 		},
 	},
 	{
 		name:            "PathOperationReqestBodyContentSchemaRef",
 		contentTemplate: externalPathOperationReqestBodyContentSchemaRefTemplate,
 		testFunc: func(t *testing.T, doc *T) {
-			require.NotNil(t, doc.Paths["/test"].Post.RequestBody.Value.Content["application/json"].Schema.Value)
-			require.Equal(t, "string", doc.Paths["/test"].Post.RequestBody.Value.Content["application/json"].Schema.Value.Type)
+			require.NotNil(t, doc.Paths.Value("/test").Post.RequestBody.Value.Content["application/json"].Schema.Value)
+			require.Equal(t, "string", doc.Paths.Value("/test").Post.RequestBody.Value.Content["application/json"].Schema.Value.Type)
 		},
 	},
 	{
 		name:            "PathOperationResponseExampleRef",
 		contentTemplate: externalPathOperationResponseExampleRefTemplate,
 		testFunc: func(t *testing.T, doc *T) {
-			require.NotNil(t, doc.Paths["/test"].Post.Responses["default"].Value)
+			require.NotNil(t, doc.Paths.Value("/test").Post.Responses.Value("default").Value)
 			desc := "testdescription"
-			require.Equal(t, &desc, doc.Paths["/test"].Post.Responses["default"].Value.Description)
-			require.Equal(t, "description", doc.Paths["/test"].Post.Responses["default"].Value.Content["application/json"].Examples["application/json"].Value.Description)
+			require.Equal(t, &desc, doc.Paths.Value("/test").Post.Responses.Value("default").Value.Description)
+			require.Equal(t, "description", doc.Paths.Value("/test").Post.Responses.Value("default").Value.Content["application/json"].Examples["application/json"].Value.Description)
 		},
 	},
 	{
 		name:            "PathOperationResponseSchemaRef",
 		contentTemplate: externalPathOperationResponseSchemaRefTemplate,
 		testFunc: func(t *testing.T, doc *T) {
-			require.NotNil(t, doc.Paths["/test"].Post.Responses["default"].Value)
+			require.NotNil(t, doc.Paths.Value("/test").Post.Responses.Value("default").Value)
 			desc := "testdescription"
-			require.Equal(t, &desc, doc.Paths["/test"].Post.Responses["default"].Value.Description)
-			require.Equal(t, "string", doc.Paths["/test"].Post.Responses["default"].Value.Content["application/json"].Schema.Value.Type)
+			require.Equal(t, &desc, doc.Paths.Value("/test").Post.Responses.Value("default").Value.Description)
+			require.Equal(t, "string", doc.Paths.Value("/test").Post.Responses.Value("default").Value.Content["application/json"].Schema.Value.Type)
 		},
 	},
 	{
@@ -178,8 +178,8 @@ var refTestDataEntries = []refTestDataEntry{
 		name:            "RequestResponseHeaderRef",
 		contentTemplate: externalRequestResponseHeaderRefTemplate,
 		testFunc: func(t *testing.T, doc *T) {
-			require.NotNil(t, doc.Paths["/test"].Post.Responses["default"].Value.Headers["X-TEST-HEADER"].Value.Description)
-			require.Equal(t, "description", doc.Paths["/test"].Post.Responses["default"].Value.Headers["X-TEST-HEADER"].Value.Description)
+			require.NotNil(t, doc.Paths.Value("/test").Post.Responses.Value("default").Value.Headers["X-TEST-HEADER"].Value.Description)
+			require.Equal(t, "description", doc.Paths.Value("/test").Post.Responses.Value("default").Value.Headers["X-TEST-HEADER"].Value.Description)
 		},
 	},
 }
@@ -724,8 +724,8 @@ var relativeDocRefsTestDataEntries = []refTestDataEntry{
 		name:            "SchemaRef",
 		contentTemplate: relativeSchemaDocsRefTemplate,
 		testFunc: func(t *testing.T, doc *T) {
-			require.NotNil(t, doc.Components.Schemas["TestSchema"].Value.Type)
-			require.Equal(t, "string", doc.Components.Schemas["TestSchema"].Value.Type)
+			require.NotNil(t, doc.Components.Schemas.Value("TestSchema").Value.Type)
+			require.Equal(t, "string", doc.Components.Schemas.Value("TestSchema").Value.Type)
 		},
 	},
 	{
@@ -733,7 +733,7 @@ var relativeDocRefsTestDataEntries = []refTestDataEntry{
 		contentTemplate: relativeResponseDocsRefTemplate,
 		testFunc: func(t *testing.T, doc *T) {
 			desc := "description"
-			require.Equal(t, &desc, doc.Components.Responses["TestResponse"].Value.Description)
+			require.Equal(t, &desc, doc.Components.Responses.Value("TestResponse").Value.Description)
 		},
 	},
 	{
@@ -792,9 +792,9 @@ var relativeDocRefsTestDataEntries = []refTestDataEntry{
 		name:            "PathRef",
 		contentTemplate: relativePathDocsRefTemplate,
 		testFunc: func(t *testing.T, doc *T) {
-			require.NotNil(t, doc.Paths["/pets"])
-			require.NotNil(t, doc.Paths["/pets"].Get.Responses["200"])
-			require.NotNil(t, doc.Paths["/pets"].Get.Responses["200"].Value.Content["application/json"])
+			require.NotNil(t, doc.Paths.Value("/pets"))
+			require.NotNil(t, doc.Paths.Value("/pets").Get.Responses.Value("200"))
+			require.NotNil(t, doc.Paths.Value("/pets").Get.Responses.Value("200").Value.Content["application/json"])
 		},
 	},
 }
@@ -914,40 +914,40 @@ func TestLoadSpecWithRelativeDocumentRefs2(t *testing.T) {
 
 	// path in nested directory
 	// check parameter
-	nestedDirPath := doc.Paths["/pets/{id}"]
+	nestedDirPath := doc.Paths.Value("/pets/{id}")
 	require.Equal(t, "param", nestedDirPath.Patch.Parameters[0].Value.Name)
 	require.Equal(t, "path", nestedDirPath.Patch.Parameters[0].Value.In)
 	require.Equal(t, true, nestedDirPath.Patch.Parameters[0].Value.Required)
 
 	// check header
-	require.Equal(t, "header", nestedDirPath.Patch.Responses["200"].Value.Headers["X-Rate-Limit-Reset"].Value.Description)
-	require.Equal(t, "header1", nestedDirPath.Patch.Responses["200"].Value.Headers["X-Another"].Value.Description)
-	require.Equal(t, "header2", nestedDirPath.Patch.Responses["200"].Value.Headers["X-And-Another"].Value.Description)
+	require.Equal(t, "header", nestedDirPath.Patch.Responses.Value("200").Value.Headers["X-Rate-Limit-Reset"].Value.Description)
+	require.Equal(t, "header1", nestedDirPath.Patch.Responses.Value("200").Value.Headers["X-Another"].Value.Description)
+	require.Equal(t, "header2", nestedDirPath.Patch.Responses.Value("200").Value.Headers["X-And-Another"].Value.Description)
 
 	// check request body
 	require.Equal(t, "example request", nestedDirPath.Patch.RequestBody.Value.Description)
 
 	// check response schema and example
-	require.Equal(t, nestedDirPath.Patch.Responses["200"].Value.Content["application/json"].Schema.Value.Type, "string")
+	require.Equal(t, nestedDirPath.Patch.Responses.Value("200").Value.Content["application/json"].Schema.Value.Type, "string")
 	expectedExample := "hello"
-	require.Equal(t, expectedExample, nestedDirPath.Patch.Responses["200"].Value.Content["application/json"].Examples["CustomTestExample"].Value.Value)
+	require.Equal(t, expectedExample, nestedDirPath.Patch.Responses.Value("200").Value.Content["application/json"].Examples["CustomTestExample"].Value.Value)
 
 	// path in more nested directory
 	// check parameter
-	moreNestedDirPath := doc.Paths["/pets/{id}/{city}"]
+	moreNestedDirPath := doc.Paths.Value("/pets/{id}/{city}")
 	require.Equal(t, "param", moreNestedDirPath.Patch.Parameters[0].Value.Name)
 	require.Equal(t, "path", moreNestedDirPath.Patch.Parameters[0].Value.In)
 	require.Equal(t, true, moreNestedDirPath.Patch.Parameters[0].Value.Required)
 
 	// check header
-	require.Equal(t, "header", nestedDirPath.Patch.Responses["200"].Value.Headers["X-Rate-Limit-Reset"].Value.Description)
-	require.Equal(t, "header1", nestedDirPath.Patch.Responses["200"].Value.Headers["X-Another"].Value.Description)
-	require.Equal(t, "header2", nestedDirPath.Patch.Responses["200"].Value.Headers["X-And-Another"].Value.Description)
+	require.Equal(t, "header", nestedDirPath.Patch.Responses.Value("200").Value.Headers["X-Rate-Limit-Reset"].Value.Description)
+	require.Equal(t, "header1", nestedDirPath.Patch.Responses.Value("200").Value.Headers["X-Another"].Value.Description)
+	require.Equal(t, "header2", nestedDirPath.Patch.Responses.Value("200").Value.Headers["X-And-Another"].Value.Description)
 
 	// check request body
 	require.Equal(t, "example request", moreNestedDirPath.Patch.RequestBody.Value.Description)
 
 	// check response schema and example
-	require.Equal(t, "string", moreNestedDirPath.Patch.Responses["200"].Value.Content["application/json"].Schema.Value.Type)
-	require.Equal(t, moreNestedDirPath.Patch.Responses["200"].Value.Content["application/json"].Examples["CustomTestExample"].Value.Value, expectedExample)
+	require.Equal(t, "string", moreNestedDirPath.Patch.Responses.Value("200").Value.Content["application/json"].Schema.Value.Type)
+	require.Equal(t, moreNestedDirPath.Patch.Responses.Value("200").Value.Content["application/json"].Examples["CustomTestExample"].Value.Value, expectedExample)
 }
@@ -0,0 +1,126 @@
+package openapi3
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrRefNotAllowed is returned when the Sandbox of a Loader forbids reading
+// the document a reference points to.
+var ErrRefNotAllowed = errors.New("reference is not allowed")
+
+// Sandbox restricts where a Loader reads the documents external references point to,
+// e.g. so that services loading user-supplied specs are not exposed to server-side
+// request forgery or to reads of local files. The zero Sandbox allows no external document.
+// It does not restrict reading the spec passed to LoadFromFile or LoadFromURI.
+type Sandbox struct {
+	// AllowFiles allows reading local files, those within AllowedDirs only if it is set.
+	// Symbolic links are resolved first, so links within AllowedDirs to files elsewhere are not followed.
+	AllowFiles  bool
+	AllowedDirs []string
+
+	// AllowNetwork allows reading remote http and https documents, those from AllowedHosts
+	// only if it is set. Hosts may hold a port ("example.com:8080") or start with a wildcard
+	// matching subdomains ("*.example.com"). The redirects ReadFromHTTP follows are checked too.
+	AllowNetwork bool
+	AllowedHosts []string
+}
+
+// Check returns an error wrapping ErrRefNotAllowed unless the sandbox allows reading location.
+func (sandbox *Sandbox) Check(location *url.URL) error {
+	var allowed bool
+	switch {
+	case location.Host == "" && (location.Scheme == "" || location.Scheme == "file"):
+		allowed = sandbox.AllowFiles && sandbox.allowsFile(location.Path)
+	case location.Scheme == "http" || location.Scheme == "https":
+		allowed = sandbox.AllowNetwork && sandbox.allowsHost(location)
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %q", ErrRefNotAllowed, location.String())
+	}
+	return nil
+}
+
+func (sandbox *Sandbox) allowsFile(p string) bool {
+	if len(sandbox.AllowedDirs) == 0 {
+		return true
+	}
+	file, err := evalSymlinks(filepath.FromSlash(p))
+	if err != nil {
+		return false
+	}
+	for _, dir := range sandbox.AllowedDirs {
+		if dir, err = evalSymlinks(dir); err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// evalSymlinks returns the absolute path p stands for once symbolic links are resolved.
+// Only the existing part of p is resolved, so that missing files are reported as such.
+func evalSymlinks(p string) (string, error) {
+	p, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(p)
+	if err == nil {
+		return resolved, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+	dir, file := filepath.Split(p)
+	if dir = filepath.Clean(dir); dir == p {
+		return p, nil
+	}
+	if dir, err = evalSymlinks(dir); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, file), nil
+}
+
+// checkRedirect returns an http.Client CheckRedirect function which forbids redirects
+// to locations sandbox does not allow, before applying the policy of client.
+func (sandbox *Sandbox) checkRedirect(client *http.Client) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if err := sandbox.Check(req.URL); err != nil {
+			return err
+		}
+		if client.CheckRedirect != nil {
+			return client.CheckRedirect(req, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+}
+
+func (sandbox *Sandbox) allowsHost(location *url.URL) bool {
+	if len(sandbox.AllowedHosts) == 0 {
+		return true
+	}
+	host := strings.ToLower(location.Host)
+	hostname := strings.ToLower(location.Hostname())
+	for _, allowed := range sandbox.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		switch {
+		case allowed == host, allowed == hostname:
+			return true
+		case strings.HasPrefix(allowed, "*.") && strings.HasSuffix(hostname, allowed[1:]):
+			return true
+		}
+	}
+	return false
+}
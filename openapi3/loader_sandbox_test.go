@@ -0,0 +1,147 @@
+package openapi3
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandboxCheck(t *testing.T) {
+	for _, tc := range []struct {
+		sandbox  Sandbox
+		location string
+		allowed  bool
+	}{
+		{Sandbox{}, "schemas.yaml", false},
+		{Sandbox{}, "https://example.com/schemas.yaml", false},
+		{Sandbox{AllowFiles: true}, "schemas.yaml", true},
+		{Sandbox{AllowFiles: true}, "file:///etc/passwd", true},
+		{Sandbox{AllowFiles: true}, "https://example.com/schemas.yaml", false},
+		{Sandbox{AllowFiles: true, AllowedDirs: []string{"specs"}}, "specs/schemas.yaml", true},
+		{Sandbox{AllowFiles: true, AllowedDirs: []string{"specs"}}, "specs/../secrets.yaml", false},
+		{Sandbox{AllowFiles: true, AllowedDirs: []string{"specs"}}, "specs-old/schemas.yaml", false},
+		{Sandbox{AllowFiles: true, AllowedDirs: []string{"specs"}}, "/etc/passwd", false},
+		{Sandbox{AllowNetwork: true}, "https://example.com/schemas.yaml", true},
+		{Sandbox{AllowNetwork: true}, "ftp://example.com/schemas.yaml", false},
+		{Sandbox{AllowNetwork: true}, "schemas.yaml", false},
+		{Sandbox{AllowNetwork: true, AllowedHosts: []string{"example.com"}}, "http://EXAMPLE.com:8080/a.yaml", true},
+		{Sandbox{AllowNetwork: true, AllowedHosts: []string{"example.com:8080"}}, "http://example.com/a.yaml", false},
+		{Sandbox{AllowNetwork: true, AllowedHosts: []string{"*.example.com"}}, "https://api.example.com/a.yaml", true},
+		{Sandbox{AllowNetwork: true, AllowedHosts: []string{"*.example.com"}}, "https://example.com/a.yaml", false},
+		{Sandbox{AllowNetwork: true, AllowedHosts: []string{"example.com"}}, "http://169.254.169.254/latest", false},
+	} {
+		t.Run(tc.location, func(t *testing.T) {
+			location, err := url.Parse(tc.location)
+			require.NoError(t, err)
+			err = tc.sandbox.Check(location)
+			if tc.allowed {
+				require.NoError(t, err)
+			} else {
+				require.True(t, errors.Is(err, ErrRefNotAllowed), err)
+			}
+		})
+	}
+}
+
+func TestLoaderSandbox(t *testing.T) {
+	loader := NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.Sandbox = &Sandbox{AllowFiles: true, AllowedDirs: []string{"testdata/recursiveRef"}}
+	doc, err := loader.LoadFromFile("testdata/recursiveRef/openapi.yml")
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+
+	loader = NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.Sandbox = &Sandbox{AllowFiles: true, AllowedDirs: []string{"testdata/recursiveRef/components"}}
+	_, err = loader.LoadFromFile("testdata/recursiveRef/openapi.yml")
+	require.True(t, errors.Is(err, ErrRefNotAllowed), err)
+
+	loader = NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.Sandbox = &Sandbox{AllowFiles: true}
+	loader.ReadFromURIFunc = func(*Loader, *url.URL) ([]byte, error) {
+		t.Fatal("reading a document the sandbox forbids")
+		return nil, nil
+	}
+	_, err = loader.LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Test, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    Secret: {$ref: 'http://169.254.169.254/latest/meta-data#/Secret'}
+`))
+	require.EqualError(t, err, `error resolving reference "http://169.254.169.254/latest/meta-data#/Secret": reference is not allowed: "http://169.254.169.254/latest/meta-data"`)
+}
+
+func TestSandboxSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	specs := filepath.Join(dir, "specs")
+	require.NoError(t, os.Mkdir(specs, 0o700))
+	secret := filepath.Join(dir, "secret.yaml")
+	require.NoError(t, os.WriteFile(secret, []byte("password: hunter2\n"), 0o600))
+	if err := os.Symlink(secret, filepath.Join(specs, "schemas.yaml")); err != nil {
+		t.Skip("symbolic links are not supported:", err)
+	}
+	require.NoError(t, os.Symlink(specs, filepath.Join(dir, "linked")))
+
+	sandbox := &Sandbox{AllowFiles: true, AllowedDirs: []string{specs}}
+	for location, allowed := range map[string]bool{
+		filepath.Join(specs, "schemas.yaml"):   false,
+		filepath.Join(specs, "missing.yaml"):   true,
+		filepath.Join(dir, "linked", "a.yaml"): true,
+	} {
+		err := sandbox.Check(&url.URL{Path: filepath.ToSlash(location)})
+		if allowed {
+			require.NoError(t, err, location)
+		} else {
+			require.True(t, errors.Is(err, ErrRefNotAllowed), location)
+		}
+	}
+}
+
+func TestSandboxRedirects(t *testing.T) {
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("following a redirect the sandbox forbids")
+	}))
+	defer internal.Close()
+	public := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/internal":
+			http.Redirect(w, r, internal.URL+"/latest/meta-data", http.StatusFound)
+		case "/moved":
+			http.Redirect(w, r, "/schemas.yaml", http.StatusMovedPermanently)
+		default:
+			_, _ = w.Write([]byte("Pet: {type: object}\n"))
+		}
+	}))
+	defer public.Close()
+	publicURL, err := url.Parse(public.URL)
+	require.NoError(t, err)
+	internalURL, err := url.Parse(internal.URL)
+	require.NoError(t, err)
+	require.NotEqual(t, publicURL.Host, internalURL.Host)
+
+	loader := NewLoader()
+	loader.Sandbox = &Sandbox{AllowNetwork: true, AllowedHosts: []string{publicURL.Host}}
+	read := ReadFromHTTP(http.DefaultClient)
+
+	location, err := url.Parse(public.URL + "/moved")
+	require.NoError(t, err)
+	data, err := read(loader, location)
+	require.NoError(t, err)
+	require.Equal(t, "Pet: {type: object}\n", string(data))
+
+	location, err = url.Parse(public.URL + "/internal")
+	require.NoError(t, err)
+	_, err = read(loader, location)
+	require.True(t, errors.Is(err, ErrRefNotAllowed), err)
+	require.Nil(t, http.DefaultClient.CheckRedirect)
+}
@@ -58,9 +58,9 @@ paths:
 	doc, err := loader.LoadFromData(spec)
 	require.NoError(t, err)
 	require.Equal(t, "An API", doc.Info.Title)
-	require.Equal(t, 2, len(doc.Components.Schemas))
-	require.Equal(t, 1, len(doc.Paths))
-	def := doc.Paths["/items"].Put.Responses.Default().Value
+	require.Equal(t, 2, doc.Components.Schemas.Len())
+	require.Equal(t, 1, doc.Paths.Len())
+	def := doc.Paths.Value("/items").Put.Responses.Default().Value
 	desc := "unexpected error"
 	require.Equal(t, &desc, def.Description)
 	err = doc.Validate(loader.Context)
@@ -85,7 +85,7 @@ func TestResolveSchemaRef(t *testing.T) {
 	err = doc.Validate(loader.Context)
 	require.NoError(t, err)
 
-	refAVisited := doc.Components.Schemas["A"].Value.AllOf[0]
+	refAVisited := doc.Components.Schemas.Value("A").Value.AllOf[0]
 	require.Equal(t, "#/components/schemas/B", refAVisited.Ref)
 	require.NotNil(t, refAVisited.Value)
 }
@@ -128,7 +128,7 @@ paths:
 	err = doc.Validate(loader.Context)
 	require.NoError(t, err)
 
-	example := doc.Paths["/"].Get.Responses.Get(200).Value.Content.Get("application/json").Examples["test"]
+	example := doc.Paths.Value("/").Get.Responses.Get(200).Value.Content.Get("application/json").Examples["test"]
 	require.NotNil(t, example.Value)
 	require.Equal(t, example.Value.Value.(map[string]interface{})["error"].(bool), false)
 }
@@ -191,7 +191,7 @@ paths:
 	doc, err := loader.LoadFromData(spec)
 	require.NoError(t, err)
 
-	require.NotNil(t, doc.Paths["/"].Parameters[0].Value)
+	require.NotNil(t, doc.Paths.Value("/").Parameters[0].Value)
 }
 
 func TestLoadRequestExampleRef(t *testing.T) {
@@ -223,7 +223,7 @@ paths:
 	doc, err := loader.LoadFromData(spec)
 	require.NoError(t, err)
 
-	require.NotNil(t, doc.Paths["/"].Post.RequestBody.Value.Content.Get("application/json").Examples["test"])
+	require.NotNil(t, doc.Paths.Value("/").Post.RequestBody.Value.Content.Get("application/json").Examples["test"])
 }
 
 func createTestServer(t *testing.T, handler http.Handler) *httptest.Server {
@@ -249,7 +249,7 @@ func TestLoadFromRemoteURL(t *testing.T) {
 	doc, err := loader.LoadFromURI(url)
 	require.NoError(t, err)
 
-	require.Equal(t, "string", doc.Components.Schemas["TestSchema"].Value.Type)
+	require.Equal(t, "string", doc.Components.Schemas.Value("TestSchema").Value.Type)
 }
 
 func TestLoadWithReferenceInReference(t *testing.T) {
@@ -260,7 +260,7 @@ func TestLoadWithReferenceInReference(t *testing.T) {
 	require.NotNil(t, doc)
 	err = doc.Validate(loader.Context)
 	require.NoError(t, err)
-	require.Equal(t, "string", doc.Paths["/api/test/ref/in/ref"].Post.RequestBody.Value.Content["application/json"].Schema.Value.Properties["definition_reference"].Value.Type)
+	require.Equal(t, "string", doc.Paths.Value("/api/test/ref/in/ref").Post.RequestBody.Value.Content["application/json"].Schema.Value.Properties.Value("definition_reference").Value.Type)
 }
 
 func TestLoadWithRecursiveReferenceInLocalReferenceInParentSubdir(t *testing.T) {
@@ -271,7 +271,7 @@ func TestLoadWithRecursiveReferenceInLocalReferenceInParentSubdir(t *testing.T)
 	require.NotNil(t, doc)
 	err = doc.Validate(loader.Context)
 	require.NoError(t, err)
-	require.Equal(t, "object", doc.Paths["/api/test/ref/in/ref"].Post.RequestBody.Value.Content["application/json"].Schema.Value.Properties["definition_reference"].Value.Type)
+	require.Equal(t, "object", doc.Paths.Value("/api/test/ref/in/ref").Post.RequestBody.Value.Content["application/json"].Schema.Value.Properties.Value("definition_reference").Value.Type)
 }
 
 func TestLoadWithRecursiveReferenceInRefrerenceInLocalReference(t *testing.T) {
@@ -282,8 +282,8 @@ func TestLoadWithRecursiveReferenceInRefrerenceInLocalReference(t *testing.T) {
 	require.NotNil(t, doc)
 	err = doc.Validate(loader.Context)
 	require.NoError(t, err)
-	require.Equal(t, "integer", doc.Paths["/api/test/ref/in/ref"].Post.RequestBody.Value.Content["application/json"].Schema.Value.Properties["data"].Value.Properties["definition_reference"].Value.Properties["ref_prop_part"].Value.Properties["idPart"].Value.Type)
-	require.Equal(t, "int64", doc.Paths["/api/test/ref/in/ref"].Post.RequestBody.Value.Content["application/json"].Schema.Value.Properties["data"].Value.Properties["definition_reference"].Value.Properties["ref_prop_part"].Value.Properties["idPart"].Value.Format)
+	require.Equal(t, "integer", doc.Paths.Value("/api/test/ref/in/ref").Post.RequestBody.Value.Content["application/json"].Schema.Value.Properties.Value("data").Value.Properties.Value("definition_reference").Value.Properties.Value("ref_prop_part").Value.Properties.Value("idPart").Value.Type)
+	require.Equal(t, "int64", doc.Paths.Value("/api/test/ref/in/ref").Post.RequestBody.Value.Content["application/json"].Schema.Value.Properties.Value("data").Value.Properties.Value("definition_reference").Value.Properties.Value("ref_prop_part").Value.Properties.Value("idPart").Value.Format)
 }
 
 func TestLoadWithReferenceInReferenceInProperty(t *testing.T) {
@@ -294,7 +294,7 @@ func TestLoadWithReferenceInReferenceInProperty(t *testing.T) {
 	require.NotNil(t, doc)
 	err = doc.Validate(loader.Context)
 	require.NoError(t, err)
-	require.Equal(t, "Problem details", doc.Paths["/api/test/ref/in/ref/in/property"].Post.Responses["401"].Value.Content["application/json"].Schema.Value.Properties["error"].Value.Title)
+	require.Equal(t, "Problem details", doc.Paths.Value("/api/test/ref/in/ref/in/property").Post.Responses.Value("401").Value.Content["application/json"].Schema.Value.Properties.Value("error").Value.Title)
 }
 
 func TestLoadFileWithExternalSchemaRef(t *testing.T) {
@@ -302,7 +302,7 @@ func TestLoadFileWithExternalSchemaRef(t *testing.T) {
 	loader.IsExternalRefsAllowed = true
 	doc, err := loader.LoadFromFile("testdata/testref.openapi.json")
 	require.NoError(t, err)
-	require.NotNil(t, doc.Components.Schemas["AnotherTestSchema"].Value.Type)
+	require.NotNil(t, doc.Components.Schemas.Value("AnotherTestSchema").Value.Type)
 }
 
 func TestLoadFileWithExternalSchemaRefSingleComponent(t *testing.T) {
@@ -311,9 +311,9 @@ func TestLoadFileWithExternalSchemaRefSingleComponent(t *testing.T) {
 	doc, err := loader.LoadFromFile("testdata/testrefsinglecomponent.openapi.json")
 	require.NoError(t, err)
 
-	require.NotNil(t, doc.Components.Responses["SomeResponse"])
+	require.NotNil(t, doc.Components.Responses.Value("SomeResponse"))
 	desc := "this is a single response definition"
-	require.Equal(t, &desc, doc.Components.Responses["SomeResponse"].Value.Description)
+	require.Equal(t, &desc, doc.Components.Responses.Value("SomeResponse").Value.Description)
 }
 
 func TestLoadRequestResponseHeaderRef(t *testing.T) {
@@ -353,8 +353,8 @@ func TestLoadRequestResponseHeaderRef(t *testing.T) {
 	doc, err := loader.LoadFromData(spec)
 	require.NoError(t, err)
 
-	require.NotNil(t, doc.Paths["/test"].Post.Responses["default"].Value.Headers["X-TEST-HEADER"].Value.Description)
-	require.Equal(t, "testheader", doc.Paths["/test"].Post.Responses["default"].Value.Headers["X-TEST-HEADER"].Value.Description)
+	require.NotNil(t, doc.Paths.Value("/test").Post.Responses.Value("default").Value.Headers["X-TEST-HEADER"].Value.Description)
+	require.Equal(t, "testheader", doc.Paths.Value("/test").Post.Responses.Value("default").Value.Headers["X-TEST-HEADER"].Value.Description)
 }
 
 func TestLoadFromDataWithExternalRequestResponseHeaderRemoteRef(t *testing.T) {
@@ -393,8 +393,8 @@ func TestLoadFromDataWithExternalRequestResponseHeaderRemoteRef(t *testing.T) {
 	doc, err := loader.LoadFromDataWithPath(spec, &url.URL{Path: "testdata/testfilename.openapi.json"})
 	require.NoError(t, err)
 
-	require.NotNil(t, doc.Paths["/test"].Post.Responses["default"].Value.Headers["X-TEST-HEADER"].Value.Description)
-	require.Equal(t, "description", doc.Paths["/test"].Post.Responses["default"].Value.Headers["X-TEST-HEADER"].Value.Description)
+	require.NotNil(t, doc.Paths.Value("/test").Post.Responses.Value("default").Value.Headers["X-TEST-HEADER"].Value.Description)
+	require.Equal(t, "description", doc.Paths.Value("/test").Post.Responses.Value("default").Value.Headers["X-TEST-HEADER"].Value.Description)
 }
 
 func TestLoadYamlFile(t *testing.T) {
@@ -412,7 +412,7 @@ func TestLoadYamlFileWithExternalSchemaRef(t *testing.T) {
 	doc, err := loader.LoadFromFile("testdata/testref.openapi.yml")
 	require.NoError(t, err)
 
-	require.NotNil(t, doc.Components.Schemas["AnotherTestSchema"].Value.Type)
+	require.NotNil(t, doc.Components.Schemas.Value("AnotherTestSchema").Value.Type)
 }
 
 func TestLoadYamlFileWithExternalPathRef(t *testing.T) {
@@ -421,8 +421,8 @@ func TestLoadYamlFileWithExternalPathRef(t *testing.T) {
 	doc, err := loader.LoadFromFile("testdata/pathref.openapi.yml")
 	require.NoError(t, err)
 
-	require.NotNil(t, doc.Paths["/test"].Get.Responses["200"].Value.Content["application/json"].Schema.Value.Type)
-	require.Equal(t, "string", doc.Paths["/test"].Get.Responses["200"].Value.Content["application/json"].Schema.Value.Type)
+	require.NotNil(t, doc.Paths.Value("/test").Get.Responses.Value("200").Value.Content["application/json"].Schema.Value.Type)
+	require.Equal(t, "string", doc.Paths.Value("/test").Get.Responses.Value("200").Value.Content["application/json"].Schema.Value.Type)
 }
 
 func TestResolveResponseLinkRef(t *testing.T) {
@@ -443,7 +443,7 @@ paths:
     get:
       operationId: getUserById,
       parameters:
-        - name: id,
+        - name: id
           in: path
           required: true
           schema:
@@ -464,7 +464,7 @@ paths:
 	err = doc.Validate(loader.Context)
 	require.NoError(t, err)
 
-	response := doc.Paths[`/users/{id}`].Get.Responses.Get(200).Value
+	response := doc.Paths.Value(`/users/{id}`).Get.Responses.Get(200).Value
 	link := response.Links[`father`].Value
 	require.NotNil(t, link)
 	require.Equal(t, "getUserById", link.OperationID)
@@ -477,7 +477,7 @@ func TestLinksFromOAISpec(t *testing.T) {
 	require.NoError(t, err)
 	err = doc.Validate(loader.Context)
 	require.NoError(t, err)
-	response := doc.Paths[`/2.0/repositories/{username}/{slug}`].Get.Responses.Get(200).Value
+	response := doc.Paths.Value(`/2.0/repositories/{username}/{slug}`).Get.Responses.Get(200).Value
 	link := response.Links[`repositoryPullRequests`].Value
 	require.Equal(t, map[string]interface{}{
 		"username": "$response.body#/owner/username",
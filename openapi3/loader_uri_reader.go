@@ -6,7 +6,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"path"
 	"path/filepath"
+	"sync"
 )
 
 // ReadFromURIFunc defines a function which reads the contents of a resource
@@ -25,7 +27,7 @@ func ReadFromURIs(readers ...ReadFromURIFunc) ReadFromURIFunc {
 	return func(loader *Loader, url *url.URL) ([]byte, error) {
 		for i := range readers {
 			buf, err := readers[i](loader, url)
-			if err == ErrURINotSupported {
+			if errors.Is(err, ErrURINotSupported) {
 				continue
 			} else if err != nil {
 				return nil, err
@@ -43,6 +45,7 @@ var DefaultReadFromURI = URIMapCache(ReadFromURIs(ReadFromHTTP(http.DefaultClien
 // ReadFromHTTP returns a ReadFromURIFunc which uses the given http.Client to
 // read the contents from a remote HTTP URI. This client may be customized to
 // implement timeouts, RFC 7234 caching, etc.
+// When the loader has a Sandbox, redirects to locations it does not allow are not followed.
 func ReadFromHTTP(cl *http.Client) ReadFromURIFunc {
 	return func(loader *Loader, location *url.URL) ([]byte, error) {
 		if location.Scheme == "" || location.Host == "" {
@@ -52,18 +55,34 @@ func ReadFromHTTP(cl *http.Client) ReadFromURIFunc {
 		if err != nil {
 			return nil, err
 		}
-		resp, err := cl.Do(req)
+		client := cl
+		if loader != nil && loader.Sandbox != nil {
+			sandboxed := *cl
+			sandboxed.CheckRedirect = loader.Sandbox.checkRedirect(cl)
+			client = &sandboxed
+		}
+		resp, err := client.Do(req)
 		if err != nil {
 			return nil, err
 		}
 		defer resp.Body.Close()
 		if resp.StatusCode > 399 {
-			return nil, fmt.Errorf("error loading %q: request returned status code %d", location.String(), resp.StatusCode)
+			return nil, &HTTPStatusError{URL: location.String(), StatusCode: resp.StatusCode}
 		}
 		return ioutil.ReadAll(resp.Body)
 	}
 }
 
+// HTTPStatusError is returned when reading a document over HTTP fails with an error status code.
+type HTTPStatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (err *HTTPStatusError) Error() string {
+	return fmt.Sprintf("error loading %q: request returned status code %d", err.URL, err.StatusCode)
+}
+
 // ReadFromFile is a ReadFromURIFunc which reads local file URIs.
 func ReadFromFile(loader *Loader, location *url.URL) ([]byte, error) {
 	if location.Host != "" {
@@ -78,9 +97,10 @@ func ReadFromFile(loader *Loader, location *url.URL) ([]byte, error) {
 // URIMapCache returns a ReadFromURIFunc that caches the contents read from URI
 // locations in a simple map. This cache implementation is suitable for
 // short-lived processes such as command-line tools which process OpenAPI
-// documents.
+// documents. It is safe for concurrent use.
 func URIMapCache(reader ReadFromURIFunc) ReadFromURIFunc {
 	cache := map[string][]byte{}
+	var mu sync.Mutex
 	return func(loader *Loader, location *url.URL) (buf []byte, err error) {
 		if location.Scheme == "" || location.Scheme == "file" {
 			if !filepath.IsAbs(location.Path) {
@@ -91,14 +111,52 @@ func URIMapCache(reader ReadFromURIFunc) ReadFromURIFunc {
 			}
 		}
 		uri := location.String()
-		var ok bool
-		if buf, ok = cache[uri]; ok {
+		mu.Lock()
+		buf, ok := cache[uri]
+		mu.Unlock()
+		if ok {
 			return
 		}
 		if buf, err = reader(loader, location); err != nil {
 			return
 		}
+		mu.Lock()
 		cache[uri] = buf
+		mu.Unlock()
 		return
 	}
 }
+
+// ReadFromMap returns a ReadFromURIFunc which reads documents from memory,
+// by URI, rather than from files or the network.
+// Keys are either absolute URIs, such as "https://example.com/openapi.yaml",
+// or file paths, such as "specs/openapi.yaml". File paths are cleaned,
+// so "./specs/../specs/openapi.yaml" reads the same document.
+// Reading a document which is missing returns an error wrapping ErrURINotSupported,
+// so ReadFromURIs may fall back to other readers.
+func ReadFromMap(documents map[string][]byte) ReadFromURIFunc {
+	byURI := make(map[string][]byte, len(documents))
+	for uri, data := range documents {
+		location, err := url.Parse(uri)
+		if err != nil {
+			byURI[uri] = data
+			continue
+		}
+		byURI[documentKey(location)] = data
+	}
+	return func(loader *Loader, location *url.URL) ([]byte, error) {
+		if data, ok := byURI[documentKey(location)]; ok {
+			return data, nil
+		}
+		return nil, fmt.Errorf("%w: no document at %q", ErrURINotSupported, location.String())
+	}
+}
+
+func documentKey(location *url.URL) string {
+	if location.Host == "" && (location.Scheme == "" || location.Scheme == "file") {
+		return path.Clean(location.Path)
+	}
+	u := *location
+	u.Fragment = ""
+	return u.String()
+}
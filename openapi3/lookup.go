@@ -0,0 +1,165 @@
+package openapi3
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// ResolveRefString returns the value a local reference such as "#/components/schemas/Pet"
+// points to in doc, e.g. a *SchemaRef. The reference may point to any value of the document,
+// e.g. "#/components/schemas/Pet/properties/name" or "#/paths/~1pets/get".
+func (doc *T) ResolveRefString(ref string) (interface{}, error) {
+	parsedURL, err := url.Parse(ref)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse reference: %q: %v", ref, err)
+	}
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("expected a reference local to the document, got %q", ref)
+	}
+	fragment := parsedURL.Fragment
+	if !strings.HasPrefix(fragment, "/") {
+		return nil, fmt.Errorf("expected fragment prefix '#/' in URI %q", ref)
+	}
+
+	var cursor interface{} = doc
+	for _, pathPart := range strings.Split(fragment[1:], "/") {
+		pathPart = unescapeRefString(pathPart)
+		if cursor, err = drillIntoField(cursor, pathPart); err != nil {
+			e := failedToResolveRefFragmentPart(ref, pathPart)
+			return nil, fmt.Errorf("%s: %w", e, err)
+		}
+		if v := reflect.ValueOf(cursor); cursor == nil || (v.Kind() == reflect.Ptr && v.IsNil()) {
+			return nil, failedToResolveRefFragmentPart(ref, pathPart)
+		}
+	}
+	return cursor, nil
+}
+
+// SchemaByRef returns the schema a local reference such as "#/components/schemas/Pet" points to.
+func (doc *T) SchemaByRef(ref string) (*Schema, error) {
+	v, err := doc.ResolveRefString(ref)
+	if err != nil {
+		return nil, err
+	}
+	switch v := v.(type) {
+	case *SchemaRef:
+		if v.Value != nil {
+			return v.Value, nil
+		}
+	case *Schema:
+		return v, nil
+	}
+	return nil, fmt.Errorf("%q is not a schema", ref)
+}
+
+// ParameterByRef returns the parameter a local reference such as "#/components/parameters/id" points to.
+func (doc *T) ParameterByRef(ref string) (*Parameter, error) {
+	v, err := doc.ResolveRefString(ref)
+	if err != nil {
+		return nil, err
+	}
+	switch v := v.(type) {
+	case *ParameterRef:
+		if v.Value != nil {
+			return v.Value, nil
+		}
+	case *Parameter:
+		return v, nil
+	}
+	return nil, fmt.Errorf("%q is not a parameter", ref)
+}
+
+// RequestBodyByRef returns the request body a local reference such as
+// "#/components/requestBodies/Pet" points to.
+func (doc *T) RequestBodyByRef(ref string) (*RequestBody, error) {
+	v, err := doc.ResolveRefString(ref)
+	if err != nil {
+		return nil, err
+	}
+	switch v := v.(type) {
+	case *RequestBodyRef:
+		if v.Value != nil {
+			return v.Value, nil
+		}
+	case *RequestBody:
+		return v, nil
+	}
+	return nil, fmt.Errorf("%q is not a request body", ref)
+}
+
+// ResponseByRef returns the response a local reference such as "#/components/responses/NotFound" points to.
+func (doc *T) ResponseByRef(ref string) (*Response, error) {
+	v, err := doc.ResolveRefString(ref)
+	if err != nil {
+		return nil, err
+	}
+	switch v := v.(type) {
+	case *ResponseRef:
+		if v.Value != nil {
+			return v.Value, nil
+		}
+	case *Response:
+		return v, nil
+	}
+	return nil, fmt.Errorf("%q is not a response", ref)
+}
+
+// ComponentRef returns the local reference to the component of doc holding value,
+// e.g. "#/components/schemas/Pet" for the *Schema or *SchemaRef of the Pet schema.
+// The values of all kinds of components are looked up. It returns false when value
+// is not a component of doc.
+func (doc *T) ComponentRef(value interface{}) (string, bool) {
+	target := componentValue(reflect.ValueOf(value))
+	if !target.IsValid() || target.IsNil() {
+		return "", false
+	}
+	components := reflect.ValueOf(doc.Components)
+	componentsType := components.Type()
+	for i := 0; i < componentsType.NumField(); i++ {
+		field, _ := collectionItems(components.Field(i))
+		if field.Kind() != reflect.Map {
+			continue
+		}
+		kind := strings.Split(componentsType.Field(i).Tag.Get("json"), ",")[0]
+		for _, name := range componentNames(field.Interface()) {
+			v := componentValue(field.MapIndex(reflect.ValueOf(name)))
+			if v.IsValid() && v.Type() == target.Type() && v.Pointer() == target.Pointer() {
+				return "#" + jsonPointer("components", kind, name), true
+			}
+		}
+	}
+	return "", false
+}
+
+// SchemaName returns the name of the schema of components that is schema.
+// It returns false when schema is not a schema of components.
+func (doc *T) SchemaName(schema *Schema) (string, bool) {
+	if schema == nil {
+		return "", false
+	}
+	for _, name := range componentNames(doc.Components.Schemas) {
+		if schemaRef := doc.Components.Schemas.Value(name); schemaRef != nil && schemaRef.Value == schema {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// componentValue returns the pointer to the value of a component,
+// e.g. the Value of a *SchemaRef.
+func componentValue(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return reflect.Value{}
+	}
+	if v.Elem().Kind() == reflect.Struct {
+		if value := v.Elem().FieldByName("Value"); value.IsValid() && value.Kind() == reflect.Ptr {
+			return value
+		}
+	}
+	return v
+}
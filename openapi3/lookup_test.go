@@ -0,0 +1,94 @@
+package openapi3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentLookup(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets/{id}:
+    parameters:
+    - $ref: '#/components/parameters/id'
+    get:
+      responses:
+        '200':
+          description: Pet
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+        '404': {$ref: '#/components/responses/NotFound'}
+components:
+  parameters:
+    id: {name: id, in: path, required: true, schema: {type: integer}}
+  requestBodies:
+    Pet:
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/Pet'}
+  responses:
+    NotFound: {description: Not found}
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string}
+    a/b~c: {type: string}
+`
+	doc, err := NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	pet := doc.Components.Schemas.Value("Pet").Value
+
+	schema, err := doc.SchemaByRef("#/components/schemas/Pet")
+	require.NoError(t, err)
+	require.Same(t, pet, schema)
+	schema, err = doc.SchemaByRef("#/components/schemas/Pet/properties/name")
+	require.NoError(t, err)
+	require.Equal(t, "string", schema.Type)
+	schema, err = doc.SchemaByRef("#/components/schemas/a~1b~0c")
+	require.NoError(t, err)
+	require.Same(t, doc.Components.Schemas.Value("a/b~c").Value, schema)
+
+	parameter, err := doc.ParameterByRef("#/components/parameters/id")
+	require.NoError(t, err)
+	require.Equal(t, "id", parameter.Name)
+	requestBody, err := doc.RequestBodyByRef("#/components/requestBodies/Pet")
+	require.NoError(t, err)
+	require.Same(t, pet, requestBody.Content.Get("application/json").Schema.Value)
+	response, err := doc.ResponseByRef("#/components/responses/NotFound")
+	require.NoError(t, err)
+	require.Equal(t, "Not found", *response.Description)
+
+	v, err := doc.ResolveRefString("#/paths/~1pets~1{id}/get")
+	require.NoError(t, err)
+	require.Same(t, doc.Paths.Value("/pets/{id}").Get, v)
+
+	_, err = doc.SchemaByRef("#/components/schemas/Dog")
+	require.EqualError(t, err, `failed to resolve "Dog" in fragment in URI: "#/components/schemas/Dog": map key "Dog" not found`)
+	_, err = doc.SchemaByRef("#/components/parameters/id")
+	require.EqualError(t, err, `"#/components/parameters/id" is not a schema`)
+	_, err = doc.ResolveRefString("other.yaml#/components/schemas/Pet")
+	require.EqualError(t, err, `expected a reference local to the document, got "other.yaml#/components/schemas/Pet"`)
+
+	ref, ok := doc.ComponentRef(pet)
+	require.True(t, ok)
+	require.Equal(t, "#/components/schemas/Pet", ref)
+	ref, ok = doc.ComponentRef(doc.Components.Schemas.Value("a/b~c"))
+	require.True(t, ok)
+	require.Equal(t, "#/components/schemas/a~1b~0c", ref)
+	ref, ok = doc.ComponentRef(doc.Paths.Value("/pets/{id}").Parameters[0].Value)
+	require.True(t, ok)
+	require.Equal(t, "#/components/parameters/id", ref)
+	_, ok = doc.ComponentRef(pet.Properties.Value("name").Value)
+	require.False(t, ok)
+
+	name, ok := doc.SchemaName(doc.Paths.Value("/pets/{id}").Get.Responses.Get(200).Value.Content.Get("application/json").Schema.Value)
+	require.True(t, ok)
+	require.Equal(t, "Pet", name)
+	_, ok = doc.SchemaName(NewStringSchema())
+	require.False(t, ok)
+}
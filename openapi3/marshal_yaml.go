@@ -0,0 +1,246 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/getkin/kin-openapi/jsoninfo"
+)
+
+// The model types implement yaml.Marshaler of gopkg.in/yaml.v3 so that they are encoded
+// to YAML directly rather than through JSON:
+//
+//	var buf bytes.Buffer
+//	enc := yaml.NewEncoder(&buf)
+//	enc.SetIndent(2)
+//	err := enc.Encode(doc)
+//
+// Fields are encoded in the order they are declared, followed by extensions sorted by name.
+// Numbers are formatted as in JSON and raw JSON values, such as the extensions of
+// loaded documents, keep the order of their keys and the formatting of their numbers.
+
+var (
+	yamlMarshalerType = reflect.TypeOf((*yaml.Marshaler)(nil)).Elem()
+	rawMessageType    = reflect.TypeOf(json.RawMessage(nil))
+
+	// yamlFields caches the fields of types in declaration order, by type.
+	yamlFields sync.Map
+)
+
+// extensible is implemented by the types embedding ExtensionProps.
+type extensible interface {
+	extensions() map[string]interface{}
+}
+
+func (props *ExtensionProps) extensions() map[string]interface{} {
+	return props.Extensions
+}
+
+// marshalStrictStructYAML returns the YAML mapping of value, a pointer to a struct
+// encoded in JSON by jsoninfo.MarshalStrictStruct, with the same keys and values.
+func marshalStrictStructYAML(value interface{}) (*yaml.Node, error) {
+	reflection := reflect.ValueOf(value).Elem()
+	fields := yamlFieldsOf(reflection.Type())
+
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	keys := make(map[string]int, len(fields))
+	for _, field := range fields {
+		if !field.HasJSONTag {
+			continue
+		}
+		fieldValue := reflection.FieldByIndex(field.Index)
+		if field.JSONOmitEmpty && isEmptyYAMLValue(fieldValue) {
+			continue
+		}
+		valueNode, err := yamlNode(fieldValue)
+		if err != nil {
+			return nil, err
+		}
+		// As in JSON, the last of the multijson fields of a name that is set wins
+		if i, ok := keys[field.JSONName]; ok {
+			node.Content[i+1] = valueNode
+			continue
+		}
+		keys[field.JSONName] = len(node.Content)
+		node.Content = append(node.Content, yamlKeyNode(field.JSONName), valueNode)
+	}
+
+	if v, ok := value.(extensible); ok {
+		extensions := v.extensions()
+		for _, name := range componentNames(extensions) {
+			if _, ok := keys[name]; ok {
+				continue
+			}
+			valueNode, err := yamlNode(reflect.ValueOf(extensions[name]))
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, yamlKeyNode(name), valueNode)
+		}
+	}
+	return node, nil
+}
+
+func yamlFieldsOf(t reflect.Type) []jsoninfo.FieldInfo {
+	if fields, ok := yamlFields.Load(t); ok {
+		return fields.([]jsoninfo.FieldInfo)
+	}
+	fields := jsoninfo.AppendFields(nil, nil, t)
+	yamlFields.Store(t, fields)
+	return fields
+}
+
+// isEmptyYAMLValue tells whether v is omitted when its field is tagged omitempty,
+// following jsoninfo.
+func isEmptyYAMLValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if m, ok := v.Interface().(orderedMap); ok && !v.IsNil() {
+			return m.Len() == 0
+		}
+		return v.IsNil()
+	case reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	case reflect.Array, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	}
+	return false
+}
+
+func yamlNode(v reflect.Value) (*yaml.Node, error) {
+	if !v.IsValid() {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	if v.Type() == rawMessageType {
+		return rawJSONNode(v.Bytes())
+	}
+	if v.Kind() == reflect.Struct && v.CanAddr() && v.Addr().Type().Implements(yamlMarshalerType) {
+		v = v.Addr()
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+		}
+		if v.Type().Implements(yamlMarshalerType) {
+			marshaled, err := v.Interface().(yaml.Marshaler).MarshalYAML()
+			if err != nil {
+				return nil, err
+			}
+			if node, ok := marshaled.(*yaml.Node); ok {
+				return node, nil
+			}
+			return yamlNode(reflect.ValueOf(marshaled))
+		}
+		return yamlNode(v.Elem())
+	case reflect.Float32, reflect.Float64:
+		return floatNode(v.Float(), v.Type().Bits())
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		keys := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		return orderedYAMLNode(keys, func(key string) reflect.Value {
+			return v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+		})
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+		}
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for i := 0; i < v.Len(); i++ {
+			itemNode, err := yamlNode(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, itemNode)
+		}
+		return node, nil
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(v.Interface()); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// orderedYAMLNode returns the YAML mapping of the values of keys, in the order of keys.
+func orderedYAMLNode(keys []string, value func(key string) reflect.Value) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range keys {
+		valueNode, err := yamlNode(value(key))
+		if err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, yamlKeyNode(key), valueNode)
+	}
+	return node, nil
+}
+
+func yamlKeyNode(key string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+}
+
+// floatNode formats f as encoding/json does.
+func floatNode(f float64, bits int) (*yaml.Node, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, &json.UnsupportedValueError{Str: strconv.FormatFloat(f, 'g', -1, bits)}
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	s := strconv.FormatFloat(f, format, -1, bits)
+	tag := "!!int"
+	if strings.ContainsAny(s, ".e") {
+		tag = "!!float"
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: s}, nil
+}
+
+// rawJSONNode returns the YAML node of the JSON value data,
+// keeping the order of its keys and the formatting of its numbers.
+func rawJSONNode(data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	node := doc.Content[0]
+	resetYAMLStyle(node)
+	return node, nil
+}
+
+// resetYAMLStyle drops the flow and quoting styles of JSON from node
+// so that it is encoded like the rest of the document.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
@@ -0,0 +1,94 @@
+package openapi3
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMarshalYAML(t *testing.T) {
+	loader := NewLoader()
+	doc, err := loader.LoadFromData([]byte(`{
+  "openapi": "3.0.3",
+  "info": {"title": "API", "version": "1", "x-logo": {"url": "logo.png", "backgroundColor": "#fff", "ratio": 1.50}},
+  "paths": {},
+  "components": {
+    "schemas": {
+      "Price": {
+        "type": "number",
+        "minimum": 0.01,
+        "maximum": 1000000,
+        "multipleOf": 0.01,
+        "example": 19.99
+      },
+      "Tags": {"type": "object", "additionalProperties": false},
+      "Labels": {"type": "object", "additionalProperties": {"type": "string"}},
+      "Pet": {"$ref": "#/components/schemas/Labels"}
+    }
+  }
+}`))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	require.NoError(t, enc.Encode(doc))
+	require.Equal(t, `openapi: 3.0.3
+components:
+  schemas:
+    Labels:
+      type: object
+      additionalProperties:
+        type: string
+    Pet:
+      $ref: '#/components/schemas/Labels'
+    Price:
+      type: number
+      example: 19.99
+      minimum: 0.01
+      maximum: 1000000
+      multipleOf: 0.01
+    Tags:
+      type: object
+      additionalProperties: false
+info:
+  title: API
+  version: "1"
+  x-logo:
+    url: logo.png
+    backgroundColor: '#fff'
+    ratio: 1.50
+paths: {}
+`, buf.String())
+
+	doc2, err := loader.LoadFromData(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, doc.Components.Schemas.Value("Price").Value.MultipleOf, doc2.Components.Schemas.Value("Price").Value.MultipleOf)
+	require.Equal(t, false, *doc2.Components.Schemas.Value("Tags").Value.AdditionalPropertiesAllowed)
+}
+
+func TestMarshalYAMLWebhooks(t *testing.T) {
+	doc, err := NewLoader().LoadFromData([]byte(`
+openapi: 3.0.3
+info: {title: API, version: "1"}
+paths: {}
+x-webhooks:
+  newPet:
+    post:
+      responses: {'200': {description: OK}}
+`))
+	require.NoError(t, err)
+
+	data, err := yaml.Marshal(doc)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "\nx-webhooks:\n    newPet:\n")
+	require.NotContains(t, string(data), "\nwebhooks:")
+
+	doc.OpenAPI = "3.1.0"
+	data, err = yaml.Marshal(doc)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "\nwebhooks:\n    newPet:\n")
+	require.NotContains(t, string(data), "x-webhooks")
+}
@@ -69,6 +69,11 @@ func (mediaType *MediaType) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(mediaType)
 }
 
+// MarshalYAML returns the YAML encoding of MediaType.
+func (mediaType *MediaType) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(mediaType)
+}
+
 // UnmarshalJSON sets MediaType to a copy of data.
 func (mediaType *MediaType) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, mediaType)
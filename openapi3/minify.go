@@ -0,0 +1,207 @@
+package openapi3
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MinifyOption configures Minify.
+type MinifyOption func(*minifier)
+
+// KeepDescriptions makes Minify keep descriptions and summaries.
+func KeepDescriptions() MinifyOption {
+	return func(m *minifier) { m.keepDescriptions = true }
+}
+
+// KeepExamples makes Minify keep example and examples values.
+func KeepExamples() MinifyOption {
+	return func(m *minifier) { m.keepExamples = true }
+}
+
+// KeepExtensions makes Minify keep the x- extensions.
+func KeepExtensions() MinifyOption {
+	return func(m *minifier) { m.keepExtensions = true }
+}
+
+// KeepRefs makes Minify keep the references to trivial schemas.
+func KeepRefs() MinifyOption {
+	return func(m *minifier) { m.keepRefs = true }
+}
+
+// Minify reduces doc to a minimal canonical document, suitable for embedding
+// at runtime or for comparing documents semantically.
+//
+// It strips descriptions and summaries, examples and extensions (but for
+// ExtensionRequiredWhen, which validation relies on) from every object of
+// the document, and sorts the required properties of schemas.
+// References to trivial component schemas, which have no subschemas
+// (e.g. a string with a pattern), are replaced by their value,
+// and the components no longer referenced are removed.
+// Response descriptions, which are required, are emptied.
+//
+// Keys of maps are always sorted when marshaling, so two documents that only
+// differ by what was stripped marshal to the same bytes.
+func (doc *T) Minify(opts ...MinifyOption) {
+	m := &minifier{
+		visited: make(map[minifiedPointer]struct{}),
+		inlined: make(map[string]struct{}),
+		refs:    make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	m.walk(reflect.ValueOf(doc))
+
+	for _, name := range componentNames(m.inlined) {
+		if _, ok := m.refs[name]; !ok {
+			doc.Components.Schemas.Delete(name)
+		}
+	}
+}
+
+const componentSchemasPrefix = "#/components/schemas/"
+
+var (
+	typeOfExtensionProps = reflect.TypeOf(ExtensionProps{})
+	typeOfSchemaRef      = reflect.TypeOf(&SchemaRef{})
+	typeOfSchema         = reflect.TypeOf(&Schema{})
+	typeOfDiscriminator  = reflect.TypeOf(&Discriminator{})
+)
+
+type minifiedPointer struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+type minifier struct {
+	keepDescriptions bool
+	keepExamples     bool
+	keepExtensions   bool
+	keepRefs         bool
+
+	visited map[minifiedPointer]struct{}
+	// inlined holds the component schemas whose references were inlined,
+	// refs those still referenced.
+	inlined map[string]struct{}
+	refs    map[string]struct{}
+}
+
+func (m *minifier) walk(v reflect.Value) {
+	v, _ = collectionItems(v)
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		key := minifiedPointer{typ: v.Type(), ptr: v.Pointer()}
+		if _, ok := m.visited[key]; ok {
+			return
+		}
+		m.visited[key] = struct{}{}
+
+		switch v.Type() {
+		case typeOfSchemaRef:
+			m.schemaRef(v.Interface().(*SchemaRef))
+		case typeOfSchema:
+			sort.Strings(v.Interface().(*Schema).Required)
+		case typeOfDiscriminator:
+			for _, ref := range v.Interface().(*Discriminator).Mapping {
+				m.ref(ref)
+			}
+		}
+		m.walk(v.Elem())
+
+	case reflect.Struct:
+		m.walkStruct(v)
+
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			m.walk(v.MapIndex(key))
+		}
+
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			m.walk(v.Index(i))
+		}
+	}
+}
+
+func (m *minifier) walkStruct(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if value.CanSet() {
+			switch {
+			case field.Type == typeOfExtensionProps:
+				if !m.keepExtensions {
+					stripExtensions(value.Addr().Interface().(*ExtensionProps))
+				}
+				continue
+			case field.Name == "Description" || field.Name == "Summary":
+				if !m.keepDescriptions {
+					clearText(value)
+				}
+				continue
+			case field.Name == "Example" || field.Name == "Examples":
+				if !m.keepExamples {
+					value.Set(reflect.Zero(field.Type))
+					continue
+				}
+			}
+		}
+		m.walk(value)
+	}
+}
+
+func (m *minifier) schemaRef(s *SchemaRef) {
+	if !strings.HasPrefix(s.Ref, componentSchemasPrefix) {
+		return
+	}
+	if m.keepRefs || s.Value == nil || !isTrivialSchema(s.Value) {
+		m.ref(s.Ref)
+		return
+	}
+	m.inlined[strings.TrimPrefix(s.Ref, componentSchemasPrefix)] = struct{}{}
+	s.Ref = ""
+}
+
+func (m *minifier) ref(ref string) {
+	if strings.HasPrefix(ref, componentSchemasPrefix) {
+		m.refs[strings.TrimPrefix(ref, componentSchemasPrefix)] = struct{}{}
+	}
+}
+
+// stripExtensions removes the extensions of props but those validation relies on.
+func stripExtensions(props *ExtensionProps) {
+	for name := range props.Extensions {
+		if name != ExtensionRequiredWhen {
+			delete(props.Extensions, name)
+		}
+	}
+	if len(props.Extensions) == 0 {
+		props.Extensions = nil
+	}
+}
+
+// isTrivialSchema reports whether schema has no subschemas.
+func isTrivialSchema(schema *Schema) bool {
+	return len(schema.OneOf) == 0 && len(schema.AnyOf) == 0 && len(schema.AllOf) == 0 &&
+		schema.Not == nil && schema.Items == nil && schema.Properties.Len() == 0 &&
+		schema.AdditionalProperties == nil && schema.Discriminator == nil
+}
+
+// clearText empties a description or summary, keeping required ones present.
+func clearText(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("")
+	case reflect.Ptr:
+		if !v.IsNil() && v.Elem().Kind() == reflect.String {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+	}
+}
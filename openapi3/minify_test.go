@@ -0,0 +1,119 @@
+package openapi3
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinify(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+  description: Manages pets.
+  x-logo: logo.png
+tags:
+- name: pets
+  description: Pets operations
+paths:
+  /pets/{id}:
+    summary: A pet
+    parameters:
+    - name: id
+      in: path
+      required: true
+      description: The pet identifier
+      example: 42
+      schema: {$ref: '#/components/schemas/Id'}
+    get:
+      summary: Get a pet
+      x-internal: true
+      responses:
+        '200':
+          description: The pet
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+              examples:
+                rex: {value: {id: 1, name: Rex}}
+components:
+  schemas:
+    Id:
+      type: integer
+      description: An identifier
+      minimum: 1
+    Name:
+      type: string
+      example: Rex
+    Pet:
+      type: object
+      description: A pet
+      required: [name, id]
+      properties:
+        id: {$ref: '#/components/schemas/Id'}
+        name: {$ref: '#/components/schemas/Name'}
+        parent: {$ref: '#/components/schemas/Pet'}
+      x-go-type: Pet
+`
+	const minified = `{
+  "openapi": "3.0.0",
+  "info": {"title": "Pets", "version": "1.0.0"},
+  "tags": [{"name": "pets"}],
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [
+        {"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "minimum": 1}}
+      ],
+      "get": {
+        "responses": {
+          "200": {
+            "description": "",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "required": ["id", "name"],
+        "properties": {
+          "id": {"type": "integer", "minimum": 1},
+          "name": {"type": "string"},
+          "parent": {"$ref": "#/components/schemas/Pet"}
+        }
+      }
+    }
+  }
+}`
+
+	load := func() *T {
+		doc, err := NewLoader().LoadFromData([]byte(spec))
+		require.NoError(t, err)
+		return doc
+	}
+
+	doc := load()
+	doc.Minify()
+	require.NoError(t, doc.Validate(context.Background()))
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, minified, string(data))
+
+	// Only the required properties are sorted.
+	doc = load()
+	doc.Minify(KeepDescriptions(), KeepExamples(), KeepExtensions(), KeepRefs())
+	data, err = json.Marshal(doc)
+	require.NoError(t, err)
+	original := load()
+	original.Components.Schemas.Value("Pet").Value.Required = []string{"id", "name"}
+	expected, err := json.Marshal(original)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expected), string(data))
+}
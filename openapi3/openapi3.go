@@ -16,37 +16,60 @@ type T struct {
 	OpenAPI      string               `json:"openapi" yaml:"openapi"` // Required
 	Components   Components           `json:"components,omitempty" yaml:"components,omitempty"`
 	Info         *Info                `json:"info" yaml:"info"`   // Required
-	Paths        Paths                `json:"paths" yaml:"paths"` // Required
+	Paths        *Paths               `json:"paths" yaml:"paths"` // Required
 	Security     SecurityRequirements `json:"security,omitempty" yaml:"security,omitempty"`
 	Servers      Servers              `json:"servers,omitempty" yaml:"servers,omitempty"`
 	Tags         Tags                 `json:"tags,omitempty" yaml:"tags,omitempty"`
 	ExternalDocs *ExternalDocs        `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+	Webhooks     Webhooks             `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`
 
-	visited visitedComponent
+	visited      visitedComponent
+	operationIDs map[string]indexedOperation
 }
 
 // MarshalJSON returns the JSON encoding of T.
 func (doc *T) MarshalJSON() ([]byte, error) {
-	return jsoninfo.MarshalStrictStruct(doc)
+	return jsoninfo.MarshalStrictStruct(doc.withWebhooksExtension())
+}
+
+// MarshalYAML returns the YAML encoding of T.
+func (doc *T) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(doc.withWebhooksExtension())
 }
 
 // UnmarshalJSON sets T to a copy of data.
 func (doc *T) UnmarshalJSON(data []byte) error {
-	return jsoninfo.UnmarshalStrictStruct(data, doc)
+	if err := jsoninfo.UnmarshalStrictStruct(data, doc); err != nil {
+		return err
+	}
+	return doc.decodeWebhooksExtension()
 }
 
 func (doc *T) AddOperation(path string, method string, operation *Operation) {
 	if doc.Paths == nil {
-		doc.Paths = make(Paths)
+		doc.Paths = NewPaths()
 	}
-	pathItem := doc.Paths[path]
+	pathItem := doc.Paths.Value(path)
 	if pathItem == nil {
 		pathItem = &PathItem{}
-		doc.Paths[path] = pathItem
+		doc.Paths.Set(path, pathItem)
 	}
 	pathItem.SetOperation(method, operation)
 }
 
+// WalkOperations calls fn on each operation of doc, sorted by path then method.
+func (doc *T) WalkOperations(fn func(path string, method string, operation *Operation)) {
+	for _, path := range doc.Paths.InSortedOrder() {
+		pathItem := doc.Paths.Value(path)
+		if pathItem == nil {
+			continue
+		}
+		for _, method := range pathItem.Methods() {
+			fn(path, method, pathItem.GetOperation(method))
+		}
+	}
+}
+
 func (doc *T) AddServer(server *Server) {
 	doc.Servers = append(doc.Servers, server)
 }
@@ -86,6 +109,13 @@ func (doc *T) Validate(ctx context.Context, opts ...ValidationOption) error {
 		return wrap(errors.New("must be an object"))
 	}
 
+	wrap = func(e error) error { return fmt.Errorf("invalid webhooks: %w", e) }
+	if v := doc.Webhooks; v != nil {
+		if err := v.Validate(ctx); err != nil {
+			return wrap(err)
+		}
+	}
+
 	wrap = func(e error) error { return fmt.Errorf("invalid security: %w", e) }
 	if v := doc.Security; v != nil {
 		if err := v.Validate(ctx); err != nil {
@@ -271,8 +271,8 @@ func spec() *T {
 			Title:   "MyAPI",
 			Version: "0.1",
 		},
-		Paths: Paths{
-			"/hello": &PathItem{
+		Paths: NewPaths().
+			With("/hello", &PathItem{
 				Post: &Operation{
 					Parameters: Parameters{
 						{
@@ -284,12 +284,11 @@ func spec() *T {
 						Ref:   "#/components/requestBodies/someRequestBody",
 						Value: requestBody,
 					},
-					Responses: Responses{
-						"200": &ResponseRef{
+					Responses: (&Responses{}).
+						With("200", &ResponseRef{
 							Ref:   "#/components/responses/someResponse",
 							Value: response,
-						},
-					},
+						}),
 				},
 				Parameters: Parameters{
 					{
@@ -297,8 +296,7 @@ func spec() *T {
 						Value: parameter,
 					},
 				},
-			},
-		},
+			}),
 		Components: Components{
 			Parameters: map[string]*ParameterRef{
 				"someParameter": {
@@ -310,16 +308,14 @@ func spec() *T {
 					Value: requestBody,
 				},
 			},
-			Responses: map[string]*ResponseRef{
-				"someResponse": {
+			Responses: (&Responses{}).
+				With("someResponse", &ResponseRef{
 					Value: response,
-				},
-			},
-			Schemas: map[string]*SchemaRef{
-				"someSchema": {
+				}),
+			Schemas: NewSchemas().
+				With("someSchema", &SchemaRef{
 					Value: schema,
-				},
-			},
+				}),
 			Headers: map[string]*HeaderRef{
 				"someHeader": {
 					Ref: "#/components/headers/otherHeader",
@@ -468,3 +464,49 @@ components:
 		})
 	}
 }
+
+func TestWebhooks(t *testing.T) {
+	const spec = `
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths: {}
+x-webhooks:
+  newPet:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        '200': {description: OK}
+components:
+  schemas:
+    Pet: {type: object}
+`
+	loader := NewLoader()
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	require.NotContains(t, doc.Extensions, "x-webhooks")
+	schema := doc.Webhooks["newPet"].Post.RequestBody.Value.Content.Get("application/json").Schema
+	require.Same(t, doc.Components.Schemas.Value("Pet").Value, schema.Value)
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"x-webhooks":{"newPet":`)
+	require.NotContains(t, string(data), `"webhooks"`)
+	require.NotContains(t, doc.Extensions, "x-webhooks")
+	doc2, err := loader.LoadFromData(data)
+	require.NoError(t, err)
+	require.Contains(t, doc2.Webhooks, "newPet")
+
+	// OpenAPI 3.1 documents have a webhooks field
+	doc.OpenAPI = "3.1.0"
+	data, err = json.Marshal(doc)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"webhooks":{"newPet":`)
+	require.NotContains(t, string(data), `x-webhooks`)
+
+	doc.Webhooks["newPet"].Post.Responses = nil
+	require.EqualError(t, doc.Validate(loader.Context), `invalid webhooks: webhook "newPet": invalid operation POST: value of responses must be an object`)
+}
@@ -0,0 +1,14 @@
+// Package openapi3test helps testing hand-written OpenAPI 3 documents against golden files:
+// a document is loaded, validated and marshaled canonically, then compared to its golden file.
+//
+//	func TestSpec(t *testing.T) {
+//		openapi3test.CheckGolden(t, "api.yaml", "testdata/api.golden.yaml")
+//	}
+//
+// Golden files are written rather than compared when the Update option is given
+// or the UPDATE_GOLDEN environment variable is set, e.g. with
+//
+//	UPDATE_GOLDEN=1 go test ./...
+//
+// Mismatches are reported with a line diff of the golden file and the canonical document.
+package openapi3test
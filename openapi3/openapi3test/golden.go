@@ -0,0 +1,150 @@
+package openapi3test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// UpdateEnv is the environment variable which, when set to a non-empty value,
+// makes golden files be written rather than compared.
+const UpdateEnv = "UPDATE_GOLDEN"
+
+type options struct {
+	loader            *openapi3.Loader
+	validationOptions []openapi3.ValidationOption
+	update            bool
+}
+
+// Option configures the loading, validation and comparison of documents.
+type Option func(*options)
+
+// WithLoader loads documents with loader rather than with a new openapi3.Loader.
+func WithLoader(loader *openapi3.Loader) Option {
+	return func(o *options) {
+		o.loader = loader
+	}
+}
+
+// WithValidationOptions validates documents with opts.
+func WithValidationOptions(opts ...openapi3.ValidationOption) Option {
+	return func(o *options) {
+		o.validationOptions = append(o.validationOptions, opts...)
+	}
+}
+
+// Update writes golden files with the canonical documents instead of comparing them.
+func Update() Option {
+	return func(o *options) {
+		o.update = true
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{update: os.Getenv(UpdateEnv) != ""}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.loader == nil {
+		o.loader = openapi3.NewLoader()
+	}
+	return o
+}
+
+// Load loads the document at path and validates it, failing t on error.
+func Load(t testing.TB, path string, opts ...Option) *openapi3.T {
+	t.Helper()
+	o := newOptions(opts)
+	doc, err := o.loader.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("loading %s: %v", path, err)
+	}
+	if err := doc.Validate(o.loader.Context, o.validationOptions...); err != nil {
+		t.Fatalf("validating %s: %v", path, err)
+	}
+	return doc
+}
+
+// Marshal returns the canonical encoding of doc: JSON indented by two spaces
+// when format is "json", YAML indented by two spaces otherwise.
+// Fields are in declaration order and maps, including extensions, are sorted by key,
+// so that documents are encoded identically across runs.
+func Marshal(doc *openapi3.T, format string) ([]byte, error) {
+	if format == "json" {
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatOf returns the format of the golden file at path, by extension.
+func formatOf(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return "json"
+	}
+	return "yaml"
+}
+
+// AssertGolden marshals doc canonically, in JSON if goldenPath ends with ".json"
+// and in YAML otherwise, and compares it to the golden file at goldenPath.
+// t fails with a diff when they differ.
+// When updating, the golden file and its directory are written instead.
+func AssertGolden(t testing.TB, doc *openapi3.T, goldenPath string, opts ...Option) {
+	t.Helper()
+	o := newOptions(opts)
+	got, err := Marshal(doc, formatOf(goldenPath))
+	if err != nil {
+		t.Fatalf("marshaling document: %v", err)
+	}
+
+	if o.update {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+			t.Fatalf("updating %s: %v", goldenPath, err)
+		}
+		if err := os.WriteFile(goldenPath, got, 0o644); err != nil {
+			t.Fatalf("updating %s: %v", goldenPath, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("golden file %s does not exist, set %s=1 to create it", goldenPath, UpdateEnv)
+	}
+	if err != nil {
+		t.Fatalf("reading %s: %v", goldenPath, err)
+	}
+	if d := Diff(want, got); d != "" {
+		t.Errorf("document does not match golden file %s (-golden +got), set %s=1 to update it:\n%s", goldenPath, UpdateEnv, d)
+	}
+}
+
+// CheckGolden loads and validates the document at specPath, then compares it
+// to the golden file at goldenPath as AssertGolden does.
+// It returns the document.
+func CheckGolden(t testing.TB, specPath, goldenPath string, opts ...Option) *openapi3.T {
+	t.Helper()
+	doc := Load(t, specPath, opts...)
+	AssertGolden(t, doc, goldenPath, opts...)
+	return doc
+}
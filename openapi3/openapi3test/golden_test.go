@@ -0,0 +1,72 @@
+package openapi3test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recorder records the errors of a test instead of failing it.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCheckGolden(t *testing.T) {
+	CheckGolden(t, "testdata/pets.yaml", "testdata/pets.golden.yaml")
+	CheckGolden(t, "testdata/pets.yaml", "testdata/pets.golden.json")
+}
+
+func TestAssertGoldenUpdate(t *testing.T) {
+	doc := Load(t, "testdata/pets.yaml")
+	golden := filepath.Join(t.TempDir(), "golden", "pets.yaml")
+	AssertGolden(t, doc, golden, Update())
+
+	data, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	want, err := os.ReadFile("testdata/pets.golden.yaml")
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(data))
+}
+
+func TestAssertGoldenMismatch(t *testing.T) {
+	doc := Load(t, "testdata/pets.yaml")
+	doc.Info.Title = "Pet Store"
+	r := &recorder{TB: t}
+	AssertGolden(r, doc, "testdata/pets.golden.yaml")
+	require.Len(t, r.errors, 1)
+	require.Contains(t, r.errors[0], "document does not match golden file testdata/pets.golden.yaml")
+	require.Contains(t, r.errors[0], "-  title: Pets\n+  title: Pet Store\n")
+}
+
+func TestDiff(t *testing.T) {
+	require.Equal(t, "", Diff([]byte("a\nb\n"), []byte("a\nb\n")))
+	require.Equal(t, "\\ No newline at end of file\n", Diff([]byte("a\nb\n"), []byte("a\nb")))
+
+	want := []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n")
+	got := []byte("1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n14\n15\n16\n")
+	require.Equal(t, `@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -10,6 +10,6 @@
+ 10
+ 11
+ 12
+-13
+ 14
+ 15
++16
+`, Diff(want, got))
+}
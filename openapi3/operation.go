@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strconv"
 
 	"github.com/go-openapi/jsonpointer"
 
@@ -35,7 +34,7 @@ type Operation struct {
 	RequestBody *RequestBodyRef `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
 
 	// Responses.
-	Responses Responses `json:"responses" yaml:"responses"` // Required
+	Responses *Responses `json:"responses" yaml:"responses"` // Required
 
 	// Optional callbacks
 	Callbacks Callbacks `json:"callbacks,omitempty" yaml:"callbacks,omitempty"`
@@ -53,8 +52,10 @@ type Operation struct {
 
 var _ jsonpointer.JSONPointable = (*Operation)(nil)
 
-func NewOperation() *Operation {
-	return &Operation{}
+// NewOperation returns an operation set with opts.
+// It has no responses unless they are set with WithResponse.
+func NewOperation(opts ...OperationOption) *Operation {
+	return newOperation(&Operation{}, opts)
 }
 
 // MarshalJSON returns the JSON encoding of Operation.
@@ -62,6 +63,11 @@ func (operation *Operation) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(operation)
 }
 
+// MarshalYAML returns the YAML encoding of Operation.
+func (operation *Operation) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(operation)
+}
+
 // UnmarshalJSON sets Operation to a copy of data.
 func (operation *Operation) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, operation)
@@ -117,13 +123,9 @@ func (operation *Operation) AddResponse(status int, response *Response) {
 		responses = NewResponses()
 		operation.Responses = responses
 	}
-	code := "default"
-	if status != 0 {
-		code = strconv.FormatInt(int64(status), 10)
-	}
-	responses[code] = &ResponseRef{
+	responses.Set(statusKey(status), &ResponseRef{
 		Value: response,
-	}
+	})
 }
 
 // Validate returns an error if Operation does not comply with the OpenAPI spec.
@@ -0,0 +1,125 @@
+package openapi3
+
+import (
+	"fmt"
+)
+
+type indexedOperation struct {
+	path      string
+	method    string
+	operation *Operation
+}
+
+// OperationByID returns the operation of doc whose operationId is id, with its path
+// and method, or a nil operation when there is no such operation.
+//
+// Operations are looked up in an index built when doc is loaded, or by IndexOperations.
+// When the index is found stale, e.g. after operations are added, the operation
+// is looked up in the paths of doc instead, without updating the index, so
+// OperationByID can be called concurrently as long as doc is not modified.
+func (doc *T) OperationByID(id string) (path string, method string, operation *Operation) {
+	entry, ok := doc.operationIDs[id]
+	if !ok || !entry.current(doc.Paths, id) {
+		index, _ := doc.Paths.operationIndex()
+		if entry, ok = index[id]; !ok {
+			return "", "", nil
+		}
+	}
+	return entry.path, entry.method, entry.operation
+}
+
+// IndexOperations builds the index of operations by operationId used by OperationByID,
+// e.g. after operations of doc are modified. Like other modifications of doc,
+// this is not safe for concurrent use.
+// It returns an error when operations share an operationId; the index then
+// holds the operation whose path, then method, sorts first.
+func (doc *T) IndexOperations() error {
+	var errs []error
+	if doc.operationIDs, errs = doc.Paths.operationIndex(); len(errs) != 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+func (entry indexedOperation) current(paths *Paths, id string) bool {
+	pathItem := paths.Value(entry.path)
+	return pathItem != nil &&
+		pathItem.GetOperation(entry.method) == entry.operation &&
+		entry.operation.OperationID == id
+}
+
+// operationIndex returns the operations of paths by operationId, and an error
+// for each operation whose operationId is already used by another one.
+func (paths *Paths) operationIndex() (map[string]indexedOperation, []error) {
+	var errs []error
+	index := make(map[string]indexedOperation)
+	for _, urlPath := range paths.InSortedOrder() {
+		pathItem := paths.Value(urlPath)
+		if pathItem == nil {
+			continue
+		}
+		for _, httpMethod := range pathItem.Methods() {
+			operation := pathItem.GetOperation(httpMethod)
+			if operation.OperationID == "" {
+				continue
+			}
+			if dup, ok := index[operation.OperationID]; ok {
+				endpoint, endpointDup := httpMethod+" "+urlPath, dup.method+" "+dup.path
+				if endpoint > endpointDup { // For make error message a bit more deterministic. May be useful for tests.
+					endpoint, endpointDup = endpointDup, endpoint
+				}
+				errs = append(errs, fmt.Errorf("operations %q and %q have the same operation id %q",
+					endpoint, endpointDup, operation.OperationID))
+				continue
+			}
+			index[operation.OperationID] = indexedOperation{
+				path:      urlPath,
+				method:    httpMethod,
+				operation: operation,
+			}
+		}
+	}
+	return index, errs
+}
+
+// duplicateParameters returns an error for each parameter of parameters
+// with the same name and location as a previous one.
+func duplicateParameters(parameters Parameters, owner string) []error {
+	var errs []error
+	seen := make(map[[2]string]int)
+	for i, parameterRef := range parameters {
+		if parameterRef == nil || parameterRef.Value == nil {
+			continue
+		}
+		key := [2]string{parameterRef.Value.In, parameterRef.Value.Name}
+		if j, ok := seen[key]; ok {
+			errs = append(errs, fmt.Errorf("parameters %d and %d of %s are both %q in %s",
+				j, i, owner, key[1], key[0]))
+			continue
+		}
+		seen[key] = i
+	}
+	return errs
+}
+
+// validateUniqueness returns an error for each operationId shared by operations,
+// and for each parameter defined twice by a path or an operation.
+func (paths *Paths) validateUniqueness() error {
+	_, errs := paths.operationIndex()
+	for _, urlPath := range paths.InSortedOrder() {
+		pathItem := paths.Value(urlPath)
+		if pathItem == nil {
+			continue
+		}
+		errs = append(errs, duplicateParameters(pathItem.Parameters, fmt.Sprintf("path %q", urlPath))...)
+		for _, httpMethod := range pathItem.Methods() {
+			operation := pathItem.GetOperation(httpMethod)
+			owner := fmt.Sprintf("operation %q", httpMethod+" "+urlPath)
+			errs = append(errs, duplicateParameters(operation.Parameters, owner)...)
+		}
+	}
+	if len(errs) != 0 {
+		return MultiError(errs)
+	}
+	return nil
+}
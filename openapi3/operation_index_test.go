@@ -0,0 +1,101 @@
+package openapi3
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationByID(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses: {'200': {description: Pets}}
+    post:
+      operationId: createPet
+      responses: {'201': {description: Created}}
+  /pets/{id}:
+    parameters:
+    - {name: id, in: path, required: true, schema: {type: integer}}
+    delete:
+      operationId: createPet
+      responses: {'204': {description: Deleted}}
+`
+	doc, err := NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	require.Len(t, doc.operationIDs, 2)
+
+	path, method, operation := doc.OperationByID("listPets")
+	require.Equal(t, "/pets", path)
+	require.Equal(t, http.MethodGet, method)
+	require.Same(t, doc.Paths.Value("/pets").Get, operation)
+
+	// The duplicate that sorts first is indexed
+	path, method, operation = doc.OperationByID("createPet")
+	require.Equal(t, "/pets", path)
+	require.Equal(t, http.MethodPost, method)
+	require.Same(t, doc.Paths.Value("/pets").Post, operation)
+	require.EqualError(t, doc.IndexOperations(),
+		`operations "DELETE /pets/{id}" and "POST /pets" have the same operation id "createPet"`)
+	require.Error(t, doc.Validate(context.Background()))
+
+	_, _, operation = doc.OperationByID("getPet")
+	require.Nil(t, operation)
+
+	// Operations are looked up in paths once the index is stale
+	doc.Paths.Value("/pets/{id}").Delete.OperationID = "deletePet"
+	doc.AddOperation("/pets/{id}", http.MethodGet, &Operation{
+		OperationID: "getPet",
+		Responses:   (&Responses{}).With("200", &ResponseRef{Value: NewResponse().WithDescription("Pet")}),
+	})
+	require.NoError(t, doc.Validate(context.Background()))
+	path, method, operation = doc.OperationByID("getPet")
+	require.Equal(t, "/pets/{id}", path)
+	require.Equal(t, http.MethodGet, method)
+	require.Same(t, doc.Paths.Value("/pets/{id}").Get, operation)
+
+	doc.Paths.Value("/pets").Get.OperationID = "findPets"
+	_, _, operation = doc.OperationByID("listPets")
+	require.Nil(t, operation)
+	_, _, operation = doc.OperationByID("findPets")
+	require.Same(t, doc.Paths.Value("/pets").Get, operation)
+	require.NoError(t, doc.IndexOperations())
+	require.Same(t, doc.Paths.Value("/pets").Get, doc.operationIDs["findPets"].operation)
+}
+
+func TestOperationByIDConcurrently(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses: {'200': {description: Pets}}
+`
+	doc, err := NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	doc.Paths.Value("/pets").Get.OperationID = "findPets"
+
+	// Neither validating nor looking up operations modifies doc
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, doc.Validate(context.Background()))
+			_, _, operation := doc.OperationByID("findPets")
+			assert.NotNil(t, operation)
+		}()
+	}
+	wg.Wait()
+	require.Contains(t, doc.operationIDs, "listPets")
+}
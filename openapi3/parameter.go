@@ -118,33 +118,33 @@ const (
 	ParameterInCookie = "cookie"
 )
 
-func NewPathParameter(name string) *Parameter {
-	return &Parameter{
+func NewPathParameter(name string, opts ...ParameterOption) *Parameter {
+	return newParameter(&Parameter{
 		Name:     name,
 		In:       ParameterInPath,
 		Required: true,
-	}
+	}, opts)
 }
 
-func NewQueryParameter(name string) *Parameter {
-	return &Parameter{
+func NewQueryParameter(name string, opts ...ParameterOption) *Parameter {
+	return newParameter(&Parameter{
 		Name: name,
 		In:   ParameterInQuery,
-	}
+	}, opts)
 }
 
-func NewHeaderParameter(name string) *Parameter {
-	return &Parameter{
+func NewHeaderParameter(name string, opts ...ParameterOption) *Parameter {
+	return newParameter(&Parameter{
 		Name: name,
 		In:   ParameterInHeader,
-	}
+	}, opts)
 }
 
-func NewCookieParameter(name string) *Parameter {
-	return &Parameter{
+func NewCookieParameter(name string, opts ...ParameterOption) *Parameter {
+	return newParameter(&Parameter{
 		Name: name,
 		In:   ParameterInCookie,
-	}
+	}, opts)
 }
 
 func (parameter *Parameter) WithDescription(value string) *Parameter {
@@ -173,6 +173,11 @@ func (parameter *Parameter) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(parameter)
 }
 
+// MarshalYAML returns the YAML encoding of Parameter.
+func (parameter *Parameter) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(parameter)
+}
+
 // UnmarshalJSON sets Parameter to a copy of data.
 func (parameter *Parameter) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, parameter)
@@ -35,6 +35,11 @@ func (pathItem *PathItem) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(pathItem)
 }
 
+// MarshalYAML returns the YAML encoding of PathItem.
+func (pathItem *PathItem) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(pathItem)
+}
+
 // UnmarshalJSON sets PathItem to a copy of data.
 func (pathItem *PathItem) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, pathItem)
@@ -72,6 +77,17 @@ func (pathItem *PathItem) Operations() map[string]*Operation {
 	return operations
 }
 
+// Methods returns the sorted HTTP methods of the operations of pathItem, e.g. ["GET", "POST"].
+func (pathItem *PathItem) Methods() []string {
+	operations := pathItem.Operations()
+	methods := make([]string, 0, len(operations))
+	for method := range operations {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
 func (pathItem *PathItem) GetOperation(method string) *Operation {
 	switch method {
 	case http.MethodConnect:
@@ -9,28 +9,111 @@ import (
 
 // Paths is specified by OpenAPI/Swagger standard version 3.
 // See https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md#paths-object
-type Paths map[string]*PathItem
+//
+// Its items are accessed with Value, Set and Delete. Its zero value is empty paths.
+type Paths struct {
+	m map[string]*PathItem
+	// order holds the keys of m in order, or is nil when they are sorted (see InOrder).
+	order []string
+}
+
+// NewPaths returns empty paths, which With sets items of.
+func NewPaths() *Paths {
+	return &Paths{}
+}
+
+// With sets the item of path and returns paths.
+func (paths *Paths) With(path string, pathItem *PathItem) *Paths {
+	paths.Set(path, pathItem)
+	return paths
+}
+
+// Value returns the item of path, or nil.
+func (paths *Paths) Value(path string) *PathItem {
+	if paths == nil {
+		return nil
+	}
+	return paths.m[path]
+}
+
+// Set sets the item of path.
+func (paths *Paths) Set(path string, pathItem *PathItem) {
+	_, exists := paths.m[path]
+	if paths.m == nil {
+		paths.m = make(map[string]*PathItem)
+	}
+	paths.m[path] = pathItem
+	paths.order = orderedWith(paths.order, path, exists)
+}
+
+// Delete removes path from paths.
+func (paths *Paths) Delete(path string) {
+	if paths != nil {
+		delete(paths.m, path)
+		paths.order = orderedWithout(paths.order, path)
+	}
+}
+
+// Len returns the number of paths.
+func (paths *Paths) Len() int {
+	if paths == nil {
+		return 0
+	}
+	return len(paths.m)
+}
+
+// Map returns a copy of the items of paths, by path.
+func (paths *Paths) Map() map[string]*PathItem {
+	m := make(map[string]*PathItem, paths.Len())
+	if paths != nil {
+		for path, pathItem := range paths.m {
+			m[path] = pathItem
+		}
+	}
+	return m
+}
+
+// items returns the items of paths, which must not be modified, or nil.
+func (paths *Paths) items() map[string]*PathItem {
+	if paths == nil {
+		return nil
+	}
+	return paths.m
+}
+
+// JSONLookup implements github.com/go-openapi/jsonpointer#JSONPointable
+func (paths Paths) JSONLookup(token string) (interface{}, error) {
+	pathItem := paths.Value(token)
+	if pathItem == nil {
+		return nil, fmt.Errorf("object has no field %q", token)
+	}
+	return pathItem, nil
+}
 
 // Validate returns an error if Paths does not comply with the OpenAPI spec.
-func (paths Paths) Validate(ctx context.Context, opts ...ValidationOption) error {
+func (paths *Paths) Validate(ctx context.Context, opts ...ValidationOption) error {
 	ctx = WithValidationOptions(ctx, opts...)
 
-	normalizedPaths := make(map[string]string, len(paths))
-
-	keys := make([]string, 0, len(paths))
-	for key := range paths {
-		keys = append(keys, key)
+	uniquenessValidation := getValidationOptions(ctx).uniquenessValidationEnabled
+	if uniquenessValidation {
+		// Report every duplicate of paths at once, before the first one fails validation.
+		// Duplicates elsewhere, e.g. in callbacks, are reported by Parameters.Validate.
+		if err := paths.validateUniqueness(); err != nil {
+			return err
+		}
 	}
-	sort.Strings(keys)
-	for _, path := range keys {
-		pathItem := paths[path]
+
+	normalizedPaths := make(map[string]string, paths.Len())
+
+	for _, path := range paths.InSortedOrder() {
+		pathItem := paths.Value(path)
 		if path == "" || path[0] != '/' {
 			return fmt.Errorf("path %q does not start with a forward slash (/)", path)
 		}
 
 		if pathItem == nil {
 			pathItem = &PathItem{}
-			paths[path] = pathItem
+			paths.Set(path, pathItem)
 		}
 
 		normalizedPath, _, varsInPath := normalizeTemplatedPath(path)
@@ -39,61 +122,39 @@ func (paths Paths) Validate(ctx context.Context, opts ...ValidationOption) error
 		}
 		normalizedPaths[path] = path
 
-		var commonParams []string
-		for _, parameterRef := range pathItem.Parameters {
-			if parameterRef != nil {
-				if parameter := parameterRef.Value; parameter != nil && parameter.In == ParameterInPath {
-					commonParams = append(commonParams, parameter.Name)
-				}
-			}
-		}
-		operations := pathItem.Operations()
-		methods := make([]string, 0, len(operations))
-		for method := range operations {
-			methods = append(methods, method)
+		templateNames := make(map[string]struct{}, len(varsInPath))
+		for v := range varsInPath {
+			templateNames[pathVariableName(v)] = struct{}{}
 		}
-		sort.Strings(methods)
-		for _, method := range methods {
-			operation := operations[method]
-			var setParams []string
-			for _, parameterRef := range operation.Parameters {
-				if parameterRef != nil {
-					if parameter := parameterRef.Value; parameter != nil && parameter.In == ParameterInPath {
-						setParams = append(setParams, parameter.Name)
-					}
-				}
+		commonParams := pathParameterNames(pathItem.Parameters)
+		for _, method := range pathItem.Methods() {
+			operation := pathItem.GetOperation(method)
+			defined := pathParameterNames(operation.Parameters)
+			for name := range commonParams {
+				defined[name] = struct{}{}
 			}
-			if expected := len(setParams) + len(commonParams); expected != len(varsInPath) {
-				expected -= len(varsInPath)
-				if expected < 0 {
-					expected *= -1
-				}
-				missing := make(map[string]struct{}, expected)
-				definedParams := append(setParams, commonParams...)
-				for _, name := range definedParams {
-					if _, ok := varsInPath[name]; !ok {
-						missing[name] = struct{}{}
-					}
+			var missing, undeclared []string
+			for name := range templateNames {
+				if _, ok := defined[name]; !ok {
+					missing = append(missing, name)
 				}
-				for name := range varsInPath {
-					got := false
-					for _, othername := range definedParams {
-						if othername == name {
-							got = true
-							break
-						}
-					}
-					if !got {
-						missing[name] = struct{}{}
-					}
+			}
+			for name := range defined {
+				if _, ok := templateNames[name]; !ok {
+					undeclared = append(undeclared, name)
 				}
+			}
+			if len(missing) != 0 || len(undeclared) != 0 {
+				sort.Strings(missing)
+				sort.Strings(undeclared)
+				var details []string
 				if len(missing) != 0 {
-					missings := make([]string, 0, len(missing))
-					for name := range missing {
-						missings = append(missings, name)
-					}
-					return fmt.Errorf("operation %s %s must define exactly all path parameters (missing: %v)", method, path, missings)
+					details = append(details, fmt.Sprintf("missing: %v", missing))
 				}
+				if len(undeclared) != 0 {
+					details = append(details, fmt.Sprintf("not in path: %v", undeclared))
+				}
+				return fmt.Errorf("operation %s %s must define exactly all path parameters (%s)", method, path, strings.Join(details, ", "))
 			}
 		}
 
@@ -102,27 +163,42 @@ func (paths Paths) Validate(ctx context.Context, opts ...ValidationOption) error
 		}
 	}
 
-	if err := paths.validateUniqueOperationIDs(); err != nil {
-		return err
+	if !uniquenessValidation {
+		if err := paths.validateUniqueOperationIDs(); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// InSortedOrder returns paths sorted alphabetically, e.g. to iterate over them deterministically.
+func (paths *Paths) InSortedOrder() []string {
+	if paths.Len() == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(paths.m))
+	for key := range paths.m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // InMatchingOrder returns paths in the order they are matched against URLs.
 // See https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md#paths-object
 // When matching URLs, concrete (non-templated) paths would be matched
 // before their templated counterparts.
-func (paths Paths) InMatchingOrder() []string {
+func (paths *Paths) InMatchingOrder() []string {
 	// NOTE: sorting by number of variables ASC then by descending lexicographical
 	// order seems to be a good heuristic.
-	if paths == nil {
+	if paths.Len() == 0 {
 		return nil
 	}
 
 	vars := make(map[int][]string)
 	max := 0
-	for path := range paths {
+	for path := range paths.m {
 		count := strings.Count(path, "}")
 		vars[count] = append(vars[count], path)
 		if count > max {
@@ -130,7 +206,7 @@ func (paths Paths) InMatchingOrder() []string {
 		}
 	}
 
-	ordered := make([]string, 0, len(paths))
+	ordered := make([]string, 0, len(paths.m))
 	for c := 0; c <= max; c++ {
 		if ps, ok := vars[c]; ok {
 			sort.Sort(sort.Reverse(sort.StringSlice(ps)))
@@ -146,21 +222,19 @@ func (paths Paths) InMatchingOrder() []string {
 //
 // For example:
 //
-//	paths := openapi3.Paths {
-//	  "/person/{personName}": &openapi3.PathItem{},
-//	}
-//	pathItem := path.Find("/person/{name}")
+//	paths := openapi3.NewPaths().With("/person/{personName}", &openapi3.PathItem{})
+//	pathItem := paths.Find("/person/{name}")
 //
 // would return the correct path item.
-func (paths Paths) Find(key string) *PathItem {
+func (paths *Paths) Find(key string) *PathItem {
 	// Try directly access the map
-	pathItem := paths[key]
-	if pathItem != nil {
+	pathItem := paths.Value(key)
+	if pathItem != nil || paths == nil {
 		return pathItem
 	}
 
 	normalizedPath, expected, _ := normalizeTemplatedPath(key)
-	for path, pathItem := range paths {
+	for path, pathItem := range paths.m {
 		pathNormalized, got, _ := normalizeTemplatedPath(path)
 		if got == expected && pathNormalized == normalizedPath {
 			return pathItem
@@ -169,28 +243,34 @@ func (paths Paths) Find(key string) *PathItem {
 	return nil
 }
 
-func (paths Paths) validateUniqueOperationIDs() error {
-	operationIDs := make(map[string]string)
-	for urlPath, pathItem := range paths {
-		if pathItem == nil {
-			continue
-		}
-		for httpMethod, operation := range pathItem.Operations() {
-			if operation == nil || operation.OperationID == "" {
-				continue
-			}
-			endpoint := httpMethod + " " + urlPath
-			if endpointDup, ok := operationIDs[operation.OperationID]; ok {
-				if endpoint > endpointDup { // For make error message a bit more deterministic. May be useful for tests.
-					endpoint, endpointDup = endpointDup, endpoint
-				}
-				return fmt.Errorf("operations %q and %q have the same operation id %q",
-					endpoint, endpointDup, operation.OperationID)
+func (paths *Paths) validateUniqueOperationIDs() error {
+	if _, errs := paths.operationIndex(); len(errs) != 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// pathVariableName returns the name of a variable of a path template, without the pattern
+// routers may accept after it, e.g. "rest" for "rest:.*" (gorilla/mux) or "rest.*".
+func pathVariableName(v string) string {
+	if i := strings.IndexByte(v, ':'); i >= 0 {
+		v = v[:i]
+	}
+	v = strings.TrimSuffix(v, "*")
+	return strings.TrimSuffix(v, ".")
+}
+
+// pathParameterNames returns the names of the parameters in path.
+func pathParameterNames(parameters Parameters) map[string]struct{} {
+	names := make(map[string]struct{})
+	for _, parameterRef := range parameters {
+		if parameterRef != nil {
+			if parameter := parameterRef.Value; parameter != nil && parameter.In == ParameterInPath {
+				names[parameter.Name] = struct{}{}
 			}
-			operationIDs[operation.OperationID] = endpoint
 		}
 	}
-	return nil
+	return names
 }
 
 func normalizeTemplatedPath(path string) (string, uint, map[string]struct{}) {
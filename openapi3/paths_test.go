@@ -92,3 +92,157 @@ paths:
 		})
 	}
 }
+
+func TestWalkOperations(t *testing.T) {
+	doc := &T{}
+	doc.AddOperation("/pets/{id}", "GET", &Operation{OperationID: "getPet"})
+	doc.AddOperation("/pets", "POST", &Operation{OperationID: "createPet"})
+	doc.AddOperation("/pets/{id}", "DELETE", &Operation{OperationID: "deletePet"})
+	doc.AddOperation("/pets", "GET", &Operation{OperationID: "listPets"})
+	doc.Paths.Set("/empty", nil)
+
+	require.Equal(t, []string{"/empty", "/pets", "/pets/{id}"}, doc.Paths.InSortedOrder())
+	require.Equal(t, []string{"GET", "POST"}, doc.Paths.Value("/pets").Methods())
+
+	var visited []string
+	for i := 0; i < 3; i++ {
+		visited = visited[:0]
+		doc.WalkOperations(func(path, method string, operation *Operation) {
+			visited = append(visited, method+" "+path+" "+operation.OperationID)
+		})
+		require.Equal(t, []string{
+			"GET /pets listPets",
+			"POST /pets createPet",
+			"DELETE /pets/{id} deletePet",
+			"GET /pets/{id} getPet",
+		}, visited)
+	}
+}
+
+func TestPathsValidateUniqueness(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    parameters:
+    - {name: limit, in: query, schema: {type: integer}}
+    - {name: limit, in: query, schema: {type: integer}}
+    get:
+      operationId: listPets
+      parameters:
+      - {name: sort, in: query, schema: {type: string}}
+      - {name: sort, in: header, schema: {type: string}}
+      - {name: sort, in: query, schema: {type: string}}
+      responses: {'200': {description: Pets}}
+    post:
+      operationId: createPet
+      responses: {'201': {description: Created}}
+  /pets/{id}:
+    parameters:
+    - {name: id, in: path, required: true, schema: {type: integer}}
+    get:
+      operationId: listPets
+      responses: {'200': {description: Pet}}
+    delete:
+      operationId: createPet
+      responses: {'204': {description: Deleted}}
+`
+	doc, err := NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+
+	err = doc.Validate(context.Background())
+	require.EqualError(t, err, `invalid paths: invalid path /pets: invalid operation GET: more than one "query" parameter has name "sort"`)
+
+	err = doc.Validate(context.Background(), EnableUniquenessValidation())
+	require.Error(t, err)
+	var me MultiError
+	require.ErrorAs(t, err, &me)
+	var messages []string
+	for _, e := range me {
+		messages = append(messages, e.Error())
+	}
+	require.Equal(t, []string{
+		`operations "DELETE /pets/{id}" and "POST /pets" have the same operation id "createPet"`,
+		`operations "GET /pets" and "GET /pets/{id}" have the same operation id "listPets"`,
+		`parameters 0 and 1 of path "/pets" are both "limit" in query`,
+		`parameters 0 and 2 of operation "GET /pets" are both "sort" in query`,
+	}, messages)
+}
+
+func TestPathsValidateUniquenessOutsidePaths(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths: {}
+components:
+  callbacks:
+    created:
+      '{$request.body#/callbackUrl}':
+        post:
+          parameters:
+          - {name: id, in: query, schema: {type: string}}
+          - {name: id, in: query, schema: {type: string}}
+          responses: {'200': {description: OK}}
+x-webhooks:
+  newPet:
+    post:
+      parameters:
+      - {name: id, in: header, schema: {type: string}}
+      - {name: id, in: header, schema: {type: string}}
+      responses: {'200': {description: OK}}
+`
+	doc, err := NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	err = doc.Validate(context.Background(), EnableUniquenessValidation())
+	require.EqualError(t, err, `invalid components: callback "created": invalid operation POST: more than one "query" parameter has name "id"`)
+
+	delete(doc.Components.Callbacks, "created")
+	err = doc.Validate(context.Background(), EnableUniquenessValidation())
+	require.EqualError(t, err, `invalid webhooks: webhook "newPet": invalid operation POST: more than one "header" parameter has name "id"`)
+}
+
+func TestPathsValidatePathParameters(t *testing.T) {
+	responses := (&Responses{}).With("200", &ResponseRef{Value: NewResponse().WithDescription("OK")})
+	tests := []struct {
+		path    string
+		common  []string
+		params  []string
+		wantErr string
+	}{
+		{path: "/pets/{id}", params: []string{"id"}},
+		{path: "/pets/{id}", common: []string{"id"}},
+		{path: "/files/{rest:.*}", params: []string{"rest"}},
+		{
+			path:    "/pets/{id}",
+			params:  []string{"petId"},
+			wantErr: `operation GET /pets/{id} must define exactly all path parameters (missing: [id], not in path: [petId])`,
+		},
+		{
+			path:    "/pets/{id}",
+			common:  []string{"id"},
+			params:  []string{"ownerId"},
+			wantErr: `operation GET /pets/{id} must define exactly all path parameters (not in path: [ownerId])`,
+		},
+		{
+			path:    "/owners/{ownerId}/pets/{id}",
+			params:  []string{"id"},
+			wantErr: `operation GET /owners/{ownerId}/pets/{id} must define exactly all path parameters (missing: [ownerId])`,
+		},
+	}
+	for _, tt := range tests {
+		pathItem := &PathItem{Get: &Operation{Responses: responses}}
+		for _, name := range tt.common {
+			pathItem.Parameters = append(pathItem.Parameters, &ParameterRef{Value: NewPathParameter(name).WithSchema(NewStringSchema())})
+		}
+		for _, name := range tt.params {
+			pathItem.Get.Parameters = append(pathItem.Get.Parameters, &ParameterRef{Value: NewPathParameter(name).WithSchema(NewStringSchema())})
+		}
+		err := NewPaths().With(tt.path, pathItem).Validate(context.Background())
+		if tt.wantErr == "" {
+			require.NoError(t, err, tt.path)
+		} else {
+			require.EqualError(t, err, tt.wantErr)
+		}
+	}
+}
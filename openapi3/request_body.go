@@ -99,6 +99,11 @@ func (requestBody *RequestBody) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(requestBody)
 }
 
+// MarshalYAML returns the YAML encoding of RequestBody.
+func (requestBody *RequestBody) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(requestBody)
+}
+
 // UnmarshalJSON sets RequestBody to a copy of data.
 func (requestBody *RequestBody) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, requestBody)
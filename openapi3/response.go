@@ -14,39 +14,161 @@ import (
 
 // Responses is specified by OpenAPI/Swagger 3.0 standard.
 // See https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md#responses-object
-type Responses map[string]*ResponseRef
+//
+// Its responses are accessed with Value, Set and Delete. Its zero value is empty responses.
+type Responses struct {
+	m map[string]*ResponseRef
+	// order holds the keys of m in order, or is nil when they are sorted (see Paths.InOrder).
+	order []string
+}
 
 var _ jsonpointer.JSONPointable = (*Responses)(nil)
 
-func NewResponses() Responses {
-	r := make(Responses)
-	r["default"] = &ResponseRef{Value: NewResponse().WithDescription("")}
-	return r
+// NewResponses returns responses holding a default response, which With sets other responses of.
+func NewResponses() *Responses {
+	return (&Responses{}).With("default", &ResponseRef{Value: NewResponse().WithDescription("")})
+}
+
+// With sets the response of key, a status code, a range of status codes (e.g. "2XX")
+// or "default", and returns responses.
+func (responses *Responses) With(key string, response *ResponseRef) *Responses {
+	responses.Set(key, response)
+	return responses
+}
+
+// Value returns the response of key, or nil.
+func (responses *Responses) Value(key string) *ResponseRef {
+	if responses == nil {
+		return nil
+	}
+	return responses.m[key]
+}
+
+// Set sets the response of key.
+func (responses *Responses) Set(key string, response *ResponseRef) {
+	_, exists := responses.m[key]
+	if responses.m == nil {
+		responses.m = make(map[string]*ResponseRef)
+	}
+	responses.m[key] = response
+	responses.order = orderedWith(responses.order, key, exists)
+}
+
+// Delete removes the response of key.
+func (responses *Responses) Delete(key string) {
+	if responses != nil {
+		delete(responses.m, key)
+		responses.order = orderedWithout(responses.order, key)
+	}
+}
+
+// Len returns the number of responses.
+func (responses *Responses) Len() int {
+	if responses == nil {
+		return 0
+	}
+	return len(responses.m)
+}
+
+// Map returns a copy of the responses, by key.
+func (responses *Responses) Map() map[string]*ResponseRef {
+	m := make(map[string]*ResponseRef, responses.Len())
+	if responses != nil {
+		for key, response := range responses.m {
+			m[key] = response
+		}
+	}
+	return m
+}
+
+// InSortedOrder returns the keys of responses sorted alphabetically, e.g. to iterate over them deterministically.
+func (responses *Responses) InSortedOrder() []string {
+	return sortedKeys(nil, responses.Len(), func(add func(string)) {
+		for key := range responses.items() {
+			add(key)
+		}
+	})
+}
+
+// items returns the items of responses, which must not be modified, or nil.
+func (responses *Responses) items() map[string]*ResponseRef {
+	if responses == nil {
+		return nil
+	}
+	return responses.m
 }
 
-func (responses Responses) Default() *ResponseRef {
-	return responses["default"]
+func (responses *Responses) Default() *ResponseRef {
+	return responses.Value("default")
 }
 
-func (responses Responses) Get(status int) *ResponseRef {
-	return responses[strconv.FormatInt(int64(status), 10)]
+func (responses *Responses) Get(status int) *ResponseRef {
+	return responses.Value(strconv.FormatInt(int64(status), 10))
+}
+
+// Match returns the response describing status, along with its key,
+// as resolved by the specification: the response of the exact status code,
+// else that of its range (e.g. "2XX", case insensitive), else the default response.
+// It returns an empty key and nil when there is none.
+func (responses *Responses) Match(status int) (string, *ResponseRef) {
+	key := strconv.FormatInt(int64(status), 10)
+	if response := responses.Value(key); response != nil {
+		return key, response
+	}
+	if status >= 100 && status < 600 {
+		for _, key := range []string{key[:1] + "XX", key[:1] + "xx"} {
+			if response := responses.Value(key); response != nil {
+				return key, response
+			}
+		}
+	}
+	if response := responses.Default(); response != nil {
+		return "default", response
+	}
+	return "", nil
+}
+
+// StatusCodes returns the sorted status codes responses describe other than with
+// their default response: the exact status codes, and all the codes of ranges
+// (e.g. 200 to 299 for "2XX"). Any status code is described when there is a default response.
+func (responses *Responses) StatusCodes() []int {
+	described := make(map[int]struct{}, responses.Len())
+	for key := range responses.items() {
+		if len(key) != 3 {
+			continue
+		}
+		if status, err := strconv.Atoi(key); err == nil {
+			if status >= 100 && status < 600 {
+				described[status] = struct{}{}
+			}
+			continue
+		}
+		if first := key[0]; first >= '1' && first <= '5' && (key[1:] == "XX" || key[1:] == "xx") {
+			start := int(first-'0') * 100
+			for status := start; status < start+100; status++ {
+				described[status] = struct{}{}
+			}
+		}
+	}
+
+	codes := make([]int, 0, len(described))
+	for status := range described {
+		codes = append(codes, status)
+	}
+	sort.Ints(codes)
+	return codes
 }
 
 // Validate returns an error if Responses does not comply with the OpenAPI spec.
-func (responses Responses) Validate(ctx context.Context, opts ...ValidationOption) error {
+func (responses *Responses) Validate(ctx context.Context, opts ...ValidationOption) error {
 	ctx = WithValidationOptions(ctx, opts...)
 
-	if len(responses) == 0 {
+	if responses.Len() == 0 {
 		return errors.New("the responses object MUST contain at least one response code")
 	}
 
-	keys := make([]string, 0, len(responses))
-	for key := range responses {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
-	for _, key := range keys {
-		v := responses[key]
+	for _, key := range responses.InSortedOrder() {
+		v := responses.m[key]
 		if err := v.Validate(ctx); err != nil {
 			return err
 		}
@@ -56,8 +178,8 @@ func (responses Responses) Validate(ctx context.Context, opts ...ValidationOptio
 
 // JSONLookup implements github.com/go-openapi/jsonpointer#JSONPointable
 func (responses Responses) JSONLookup(token string) (interface{}, error) {
-	ref, ok := responses[token]
-	if ok == false {
+	ref := responses.Value(token)
+	if ref == nil {
 		return nil, fmt.Errorf("invalid token reference: %q", token)
 	}
 
@@ -107,6 +229,11 @@ func (response *Response) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(response)
 }
 
+// MarshalYAML returns the YAML encoding of Response.
+func (response *Response) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(response)
+}
+
 // UnmarshalJSON sets Response to a copy of data.
 func (response *Response) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, response)
@@ -0,0 +1,49 @@
+package openapi3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponsesMatch(t *testing.T) {
+	responses := (&Responses{}).
+		With("200", &ResponseRef{Ref: "200"}).
+		With("2XX", &ResponseRef{Ref: "2XX"}).
+		With("4xx", &ResponseRef{Ref: "4xx"}).
+		With("default", &ResponseRef{Ref: "default"})
+	for status, want := range map[int]string{
+		200: "200",
+		204: "2XX",
+		404: "4xx",
+		301: "default",
+		99:  "default",
+		600: "default",
+	} {
+		key, response := responses.Match(status)
+		require.Equal(t, want, key, status)
+		require.Equal(t, want, response.Ref, status)
+	}
+
+	responses.Delete("default")
+	key, response := responses.Match(500)
+	require.Empty(t, key)
+	require.Nil(t, response)
+}
+
+func TestResponsesStatusCodes(t *testing.T) {
+	responses := (&Responses{}).
+		With("200", &ResponseRef{}).
+		With("404", &ResponseRef{}).
+		With("4xx", &ResponseRef{}).
+		With("default", &ResponseRef{}).
+		With("600", &ResponseRef{}).
+		With("6XX", &ResponseRef{})
+	codes := responses.StatusCodes()
+	require.Len(t, codes, 101)
+	require.Equal(t, 200, codes[0])
+	require.Equal(t, 400, codes[1])
+	require.Equal(t, 499, codes[100])
+
+	require.Empty(t, (&Responses{}).With("default", &ResponseRef{}).StatusCodes())
+}
@@ -0,0 +1,329 @@
+package openapi3
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ExtensionInternal is the default extension flagging, when true, the internal-only
+// paths, operations, parameters and schema properties Sanitize removes.
+const ExtensionInternal = "x-internal"
+
+// SanitizeOption configures Sanitize.
+type SanitizeOption func(*sanitizer)
+
+// StripExtensions makes Sanitize strip only the extensions with the given names,
+// rather than all of them. Names ending with "*" match the extensions starting
+// with what precedes it, e.g. "x-internal-*".
+func StripExtensions(names ...string) SanitizeOption {
+	return func(s *sanitizer) { s.extensions = names }
+}
+
+// InternalExtension sets the extension flagging internal-only parts of documents.
+// It defaults to ExtensionInternal.
+func InternalExtension(name string) SanitizeOption {
+	return func(s *sanitizer) { s.internal = name }
+}
+
+// Sanitize turns doc, once loaded, into the external-safe document to publish.
+//
+// It removes the paths, operations, webhooks, callbacks, parameters and schema properties
+// flagged internal-only by InternalExtension, then the components and tags only they used,
+// and strips every extension (or those of StripExtensions) from every object of the document.
+// Paths and webhooks which only had internal operations are removed too.
+func (doc *T) Sanitize(opts ...SanitizeOption) {
+	s := &sanitizer{internal: ExtensionInternal}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	// Internal parts are all removed before extensions are stripped,
+	// as they are flagged by an extension
+	refs, tags := usedComponents(doc)
+	s.visited = make(map[minifiedPointer]struct{})
+	s.walk(reflect.ValueOf(doc))
+	s.strip, s.visited = true, make(map[minifiedPointer]struct{})
+	s.walk(reflect.ValueOf(doc))
+	stillRefs, stillTags := usedComponents(doc)
+
+	for ref := range refs {
+		if _, ok := stillRefs[ref]; !ok {
+			deleteComponent(&doc.Components, ref)
+		}
+	}
+	kept := doc.Tags[:0]
+	for _, tag := range doc.Tags {
+		if tag != nil {
+			if _, ok := tags[tag.Name]; ok {
+				if _, ok := stillTags[tag.Name]; !ok {
+					continue
+				}
+			}
+		}
+		kept = append(kept, tag)
+	}
+	doc.Tags = kept
+}
+
+var (
+	typeOfPathItem     = reflect.TypeOf(&PathItem{})
+	typeOfParameterRef = reflect.TypeOf(&ParameterRef{})
+)
+
+type sanitizer struct {
+	extensions []string
+	internal   string
+
+	// strip is whether the walk strips extensions, or else removes internal parts
+	strip   bool
+	visited map[minifiedPointer]struct{}
+}
+
+func (s *sanitizer) walk(v reflect.Value) {
+	v, collection := collectionItems(v)
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		key := minifiedPointer{typ: v.Type(), ptr: v.Pointer()}
+		if _, ok := s.visited[key]; ok {
+			return
+		}
+		s.visited[key] = struct{}{}
+
+		if !s.strip {
+			switch v.Type() {
+			case typeOfPathItem:
+				s.pathItem(v.Interface().(*PathItem))
+			case typeOfSchema:
+				s.schema(v.Interface().(*Schema))
+			}
+		}
+		s.walk(v.Elem())
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field, value := t.Field(i), v.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if field.Type == typeOfExtensionProps {
+				if s.strip && value.CanSet() {
+					s.stripExtensions(value.Addr().Interface().(*ExtensionProps))
+				}
+				continue
+			}
+			s.walk(value)
+		}
+
+	case reflect.Map:
+		pathItems := !s.strip && v.Type().Elem() == typeOfPathItem
+		for _, key := range v.MapKeys() {
+			value := v.MapIndex(key)
+			if pathItems && !value.IsNil() {
+				pathItem := value.Interface().(*PathItem)
+				if s.isInternal(pathItem.ExtensionProps) {
+					deleteMapIndex(v, collection, key)
+					continue
+				}
+				operations := len(pathItem.Operations())
+				s.walk(value)
+				if operations > 0 && len(pathItem.Operations()) == 0 {
+					deleteMapIndex(v, collection, key)
+				}
+				continue
+			}
+			s.walk(value)
+		}
+
+	case reflect.Slice:
+		if !s.strip && v.Type().Elem() == typeOfParameterRef && v.CanSet() {
+			s.parameters(v)
+		}
+		for i := 0; i < v.Len(); i++ {
+			s.walk(v.Index(i))
+		}
+	}
+}
+
+// pathItem removes the internal operations of pathItem.
+func (s *sanitizer) pathItem(pathItem *PathItem) {
+	for _, method := range pathItem.Methods() {
+		if operation := pathItem.GetOperation(method); s.isInternal(operation.ExtensionProps) {
+			pathItem.SetOperation(method, nil)
+		}
+	}
+}
+
+// schema removes the internal properties of schema.
+func (s *sanitizer) schema(schema *Schema) {
+	for name, property := range schema.Properties.Map() {
+		if property == nil || property.Value == nil || !s.isInternal(property.Value.ExtensionProps) {
+			continue
+		}
+		schema.Properties.Delete(name)
+		required := schema.Required[:0]
+		for _, r := range schema.Required {
+			if r != name {
+				required = append(required, r)
+			}
+		}
+		schema.Required = required
+	}
+}
+
+// parameters removes the internal parameters of v, a slice of *ParameterRef.
+func (s *sanitizer) parameters(v reflect.Value) {
+	kept := 0
+	for i := 0; i < v.Len(); i++ {
+		p := v.Index(i).Interface().(*ParameterRef)
+		if p != nil && p.Value != nil && s.isInternal(p.Value.ExtensionProps) {
+			continue
+		}
+		v.Index(kept).Set(v.Index(i))
+		kept++
+	}
+	v.SetLen(kept)
+}
+
+func (s *sanitizer) isInternal(props ExtensionProps) bool {
+	var internal bool
+	_, err := props.DecodeExtension(s.internal, &internal)
+	return err == nil && internal
+}
+
+// stripExtensions removes the extensions of props which the sanitizer strips.
+func (s *sanitizer) stripExtensions(props *ExtensionProps) {
+	for name := range props.Extensions {
+		if s.strips(name) {
+			delete(props.Extensions, name)
+		}
+	}
+	if len(props.Extensions) == 0 {
+		props.Extensions = nil
+	}
+}
+
+func (s *sanitizer) strips(name string) bool {
+	if s.extensions == nil {
+		return true
+	}
+	for _, pattern := range s.extensions {
+		if prefix := strings.TrimSuffix(pattern, "*"); prefix != pattern {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// usedComponents returns the references to components reachable from the parts of doc
+// but its components, and the tags of their operations.
+func usedComponents(doc *T) (map[string]struct{}, map[string]struct{}) {
+	c := &componentsCollector{
+		visited: make(map[minifiedPointer]struct{}),
+		refs:    make(map[string]struct{}),
+		tags:    make(map[string]struct{}),
+	}
+	v := reflect.ValueOf(doc).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.PkgPath == "" && field.Name != "Components" {
+			c.walk(v.Field(i))
+		}
+	}
+	return c.refs, c.tags
+}
+
+type componentsCollector struct {
+	visited    map[minifiedPointer]struct{}
+	refs, tags map[string]struct{}
+}
+
+func (c *componentsCollector) walk(v reflect.Value) {
+	v, _ = collectionItems(v)
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		key := minifiedPointer{typ: v.Type(), ptr: v.Pointer()}
+		if _, ok := c.visited[key]; ok {
+			return
+		}
+		c.visited[key] = struct{}{}
+
+		switch value := v.Interface().(type) {
+		case *Operation:
+			for _, tag := range value.Tags {
+				c.tags[tag] = struct{}{}
+			}
+		case *Discriminator:
+			for _, ref := range value.Mapping {
+				c.ref(ref)
+			}
+		}
+		c.walk(v.Elem())
+
+	case reflect.Struct:
+		if ref := v.FieldByName("Ref"); ref.IsValid() && ref.Kind() == reflect.String {
+			c.ref(ref.String())
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath == "" && t.Field(i).Type != typeOfExtensionProps {
+				c.walk(v.Field(i))
+			}
+		}
+
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			c.walk(v.MapIndex(key))
+		}
+
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			c.walk(v.Index(i))
+		}
+	}
+}
+
+func (c *componentsCollector) ref(ref string) {
+	if strings.HasPrefix(ref, "#/components/") {
+		c.refs[ref] = struct{}{}
+	}
+}
+
+// deleteComponent removes the component ref refers to from components,
+// e.g. "#/components/schemas/Pet".
+func deleteComponent(components *Components, ref string) {
+	parts := strings.SplitN(strings.TrimPrefix(ref, "#/components/"), "/", 2)
+	if len(parts) != 2 {
+		return
+	}
+	name := unescapeRefString(parts[1])
+	v := reflect.ValueOf(components).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if tag := strings.SplitN(t.Field(i).Tag.Get("json"), ",", 2)[0]; tag == parts[0] {
+			if m, collection := collectionItems(v.Field(i)); m.Kind() == reflect.Map && !m.IsNil() {
+				deleteMapIndex(m, collection, reflect.ValueOf(name))
+			}
+			return
+		}
+	}
+}
+
+// deleteMapIndex removes key from v, a map, or from collection when v holds its items.
+func deleteMapIndex(v reflect.Value, collection orderedMap, key reflect.Value) {
+	if collection != nil {
+		collection.Delete(key.String())
+		return
+	}
+	v.SetMapIndex(key, reflect.Value{})
+}
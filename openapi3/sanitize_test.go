@@ -0,0 +1,128 @@
+package openapi3
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitize(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+  x-logo: logo.png
+tags:
+- name: pets
+- name: admin
+- name: unused
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      x-owner: team-pets
+      parameters:
+      - {name: limit, in: query, schema: {type: integer}}
+      - {name: debug, in: query, x-internal: true, schema: {$ref: '#/components/schemas/Debug'}}
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+    delete:
+      tags: [admin]
+      x-internal: true
+      responses:
+        '204': {$ref: '#/components/responses/Purged'}
+  /admin/stats:
+    x-internal: true
+    get:
+      tags: [admin]
+      responses:
+        '200':
+          description: Stats
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Stats'}
+  /admin/jobs:
+    post:
+      x-internal: true
+      responses:
+        '202': {description: Started}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name, owner]
+      properties:
+        name: {type: string, x-go-name: Name}
+        owner: {$ref: '#/components/schemas/Owner'}
+    Owner:
+      type: object
+      x-internal: true
+      properties:
+        id: {type: string}
+    Stats:
+      type: object
+      properties:
+        count: {$ref: '#/components/schemas/Count'}
+    Count: {type: integer}
+    Debug: {type: boolean}
+    Unused: {type: string}
+  responses:
+    Purged: {description: Purged}
+`
+	load := func() *T {
+		doc, err := NewLoader().LoadFromData([]byte(spec))
+		require.NoError(t, err)
+		return doc
+	}
+
+	doc := load()
+	doc.Sanitize()
+	require.NoError(t, doc.Validate(context.Background()))
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "openapi": "3.0.0",
+  "info": {"title": "Pets", "version": "1.0.0"},
+  "tags": [{"name": "pets"}, {"name": "unused"}],
+  "paths": {
+    "/pets": {
+      "get": {
+        "tags": ["pets"],
+        "parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer"}}],
+        "responses": {
+          "200": {
+            "description": "Pets",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "required": ["name"],
+        "properties": {"name": {"type": "string"}}
+      },
+      "Unused": {"type": "string"}
+    }
+  }
+}`, string(data))
+
+	doc = load()
+	doc.Sanitize(StripExtensions("x-go-*", "x-owner"), InternalExtension("x-private"))
+	require.Equal(t, 3, doc.Paths.Len())
+	require.NotNil(t, doc.Paths.Value("/pets").Delete)
+	require.Nil(t, doc.Paths.Value("/pets").Get.Extensions)
+	require.Equal(t, map[string]interface{}{"x-internal": json.RawMessage("true")}, doc.Paths.Value("/pets").Delete.Extensions)
+	require.Nil(t, doc.Components.Schemas.Value("Pet").Value.Properties.Value("name").Value.Extensions)
+	require.NotNil(t, doc.Info.Extensions["x-logo"])
+}
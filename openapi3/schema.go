@@ -8,11 +8,11 @@ import (
 	"fmt"
 	"math"
 	"math/big"
-	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"unicode/utf16"
 
 	"github.com/go-openapi/jsonpointer"
@@ -34,10 +34,15 @@ const (
 	formatMaxInt32 = float64(math.MaxInt32)
 	formatMinInt64 = float64(math.MinInt64)
 	formatMaxInt64 = float64(math.MaxInt64)
+
+	// maxExactInteger is the largest integer float64 values tell apart from their successor
+	maxExactInteger = float64(1<<53 - 1)
 )
 
 var (
 	// SchemaErrorDetailsDisabled disables printing of details about schema errors.
+	// It sets the verbosity of the errors whose Verbosity is SchemaErrorVerbosityDefault
+	// to SchemaErrorWithPointer rather than SchemaErrorFull.
 	SchemaErrorDetailsDisabled = false
 
 	errSchema = errors.New("input does not match the schema")
@@ -71,14 +76,94 @@ func Uint64Ptr(value uint64) *uint64 {
 	return &value
 }
 
-type Schemas map[string]*SchemaRef
+// Schemas are schemas by name, such as the schemas of components and the properties of schemas.
+//
+// Its schemas are accessed with Value, Set and Delete. Its zero value is empty schemas.
+type Schemas struct {
+	m map[string]*SchemaRef
+	// order holds the keys of m in order, or is nil when they are sorted (see Paths.InOrder).
+	order []string
+}
 
 var _ jsonpointer.JSONPointable = (*Schemas)(nil)
 
+// NewSchemas returns empty schemas, which With sets schemas of.
+func NewSchemas() *Schemas {
+	return &Schemas{}
+}
+
+// With sets the schema of name and returns schemas.
+func (schemas *Schemas) With(name string, schema *SchemaRef) *Schemas {
+	schemas.Set(name, schema)
+	return schemas
+}
+
+// Value returns the schema of name, or nil.
+func (schemas *Schemas) Value(name string) *SchemaRef {
+	if schemas == nil {
+		return nil
+	}
+	return schemas.m[name]
+}
+
+// Set sets the schema of name.
+func (schemas *Schemas) Set(name string, schema *SchemaRef) {
+	_, exists := schemas.m[name]
+	if schemas.m == nil {
+		schemas.m = make(map[string]*SchemaRef)
+	}
+	schemas.m[name] = schema
+	schemas.order = orderedWith(schemas.order, name, exists)
+}
+
+// Delete removes the schema of name.
+func (schemas *Schemas) Delete(name string) {
+	if schemas != nil {
+		delete(schemas.m, name)
+		schemas.order = orderedWithout(schemas.order, name)
+	}
+}
+
+// Len returns the number of schemas.
+func (schemas *Schemas) Len() int {
+	if schemas == nil {
+		return 0
+	}
+	return len(schemas.m)
+}
+
+// Map returns a copy of the schemas, by name.
+func (schemas *Schemas) Map() map[string]*SchemaRef {
+	m := make(map[string]*SchemaRef, schemas.Len())
+	if schemas != nil {
+		for name, schema := range schemas.m {
+			m[name] = schema
+		}
+	}
+	return m
+}
+
+// InSortedOrder returns the names of schemas sorted alphabetically, e.g. to iterate over them deterministically.
+func (schemas *Schemas) InSortedOrder() []string {
+	return sortedKeys(nil, schemas.Len(), func(add func(string)) {
+		for key := range schemas.items() {
+			add(key)
+		}
+	})
+}
+
+// items returns the items of schemas, which must not be modified, or nil.
+func (schemas *Schemas) items() map[string]*SchemaRef {
+	if schemas == nil {
+		return nil
+	}
+	return schemas.m
+}
+
 // JSONLookup implements github.com/go-openapi/jsonpointer#JSONPointable
-func (s Schemas) JSONLookup(token string) (interface{}, error) {
-	ref, ok := s[token]
-	if ref == nil || ok == false {
+func (schemas Schemas) JSONLookup(token string) (interface{}, error) {
+	ref := schemas.Value(token)
+	if ref == nil {
 		return nil, fmt.Errorf("object has no field %q", token)
 	}
 
@@ -125,6 +210,7 @@ type Schema struct {
 	Format       string        `json:"format,omitempty" yaml:"format,omitempty"`
 	Description  string        `json:"description,omitempty" yaml:"description,omitempty"`
 	Enum         []interface{} `json:"enum,omitempty" yaml:"enum,omitempty"`
+	enumIndex    atomic.Value
 	Default      interface{}   `json:"default,omitempty" yaml:"default,omitempty"`
 	Example      interface{}   `json:"example,omitempty" yaml:"example,omitempty"`
 	ExternalDocs *ExternalDocs `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
@@ -132,8 +218,14 @@ type Schema struct {
 	// Array-related, here for struct compactness
 	UniqueItems bool `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty"`
 	// Number-related, here for struct compactness
-	ExclusiveMin bool `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
-	ExclusiveMax bool `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	// ExclusiveMin and ExclusiveMax are the OpenAPI 3.0 form of exclusiveMinimum and exclusiveMaximum,
+	// which make Min and Max exclusive bounds, while ExclusiveMinValue and ExclusiveMaxValue
+	// are their OpenAPI 3.1 (JSON Schema) form, exclusive bounds of their own.
+	// See Minimum and Maximum.
+	ExclusiveMin      bool     `multijson:"exclusiveMinimum,omitempty" json:"-" yaml:"-"` // In this order...
+	ExclusiveMinValue *float64 `multijson:"exclusiveMinimum,omitempty" json:"-" yaml:"-"` // ...for multijson
+	ExclusiveMax      bool     `multijson:"exclusiveMaximum,omitempty" json:"-" yaml:"-"` // In this order...
+	ExclusiveMaxValue *float64 `multijson:"exclusiveMaximum,omitempty" json:"-" yaml:"-"` // ...for multijson
 	// Properties
 	Nullable        bool `json:"nullable,omitempty" yaml:"nullable,omitempty"`
 	ReadOnly        bool `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
@@ -153,6 +245,11 @@ type Schema struct {
 	Pattern         string  `json:"pattern,omitempty" yaml:"pattern,omitempty"`
 	compiledPattern *regexp.Regexp
 
+	// String content, from JSON Schema as used by OpenAPI 3.1 (see EnableContentValidation)
+	ContentEncoding  string     `json:"contentEncoding,omitempty" yaml:"contentEncoding,omitempty"`
+	ContentMediaType string     `json:"contentMediaType,omitempty" yaml:"contentMediaType,omitempty"`
+	ContentSchema    *SchemaRef `json:"contentSchema,omitempty" yaml:"contentSchema,omitempty"`
+
 	// Array
 	MinItems uint64     `json:"minItems,omitempty" yaml:"minItems,omitempty"`
 	MaxItems *uint64    `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
@@ -160,18 +257,21 @@ type Schema struct {
 
 	// Object
 	Required                    []string       `json:"required,omitempty" yaml:"required,omitempty"`
-	Properties                  Schemas        `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Properties                  *Schemas       `json:"properties,omitempty" yaml:"properties,omitempty"`
 	MinProps                    uint64         `json:"minProperties,omitempty" yaml:"minProperties,omitempty"`
 	MaxProps                    *uint64        `json:"maxProperties,omitempty" yaml:"maxProperties,omitempty"`
 	AdditionalPropertiesAllowed *bool          `multijson:"additionalProperties,omitempty" json:"-" yaml:"-"` // In this order...
 	AdditionalProperties        *SchemaRef     `multijson:"additionalProperties,omitempty" json:"-" yaml:"-"` // ...for multijson
 	Discriminator               *Discriminator `json:"discriminator,omitempty" yaml:"discriminator,omitempty"`
+	requiredWhen                atomic.Value
+	validatorNames              atomic.Value
+	validators                  []SchemaValidatorFunc
 }
 
 var _ jsonpointer.JSONPointable = (*Schema)(nil)
 
-func NewSchema() *Schema {
-	return &Schema{}
+func NewSchema(opts ...SchemaOption) *Schema {
+	return newSchema(&Schema{}, opts)
 }
 
 // MarshalJSON returns the JSON encoding of Schema.
@@ -179,6 +279,11 @@ func (schema *Schema) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(schema)
 }
 
+// MarshalYAML returns the YAML encoding of Schema.
+func (schema *Schema) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(schema)
+}
+
 // UnmarshalJSON sets Schema to a copy of data.
 func (schema *Schema) UnmarshalJSON(data []byte) error {
 	err := jsoninfo.UnmarshalStrictStruct(data, schema)
@@ -201,6 +306,17 @@ func (schema Schema) JSONLookup(token string) (interface{}, error) {
 			}
 			return schema.AdditionalProperties.Value, nil
 		}
+	case "contentEncoding":
+		return schema.ContentEncoding, nil
+	case "contentMediaType":
+		return schema.ContentMediaType, nil
+	case "contentSchema":
+		if schema.ContentSchema != nil {
+			if schema.ContentSchema.Ref != "" {
+				return &Ref{Ref: schema.ContentSchema.Ref}, nil
+			}
+			return schema.ContentSchema.Value, nil
+		}
 	case "not":
 		if schema.Not != nil {
 			if schema.Not.Ref != "" {
@@ -245,6 +361,10 @@ func (schema Schema) JSONLookup(token string) (interface{}, error) {
 		return schema.ExclusiveMin, nil
 	case "exclusiveMax":
 		return schema.ExclusiveMax, nil
+	case "exclusiveMinValue":
+		return schema.ExclusiveMinValue, nil
+	case "exclusiveMaxValue":
+		return schema.ExclusiveMaxValue, nil
 	case "nullable":
 		return schema.Nullable, nil
 	case "readOnly":
@@ -325,76 +445,76 @@ func NewAllOfSchema(schemas ...*Schema) *Schema {
 	}
 }
 
-func NewBoolSchema() *Schema {
-	return &Schema{
+func NewBoolSchema(opts ...SchemaOption) *Schema {
+	return newSchema(&Schema{
 		Type: TypeBoolean,
-	}
+	}, opts)
 }
 
-func NewFloat64Schema() *Schema {
-	return &Schema{
+func NewFloat64Schema(opts ...SchemaOption) *Schema {
+	return newSchema(&Schema{
 		Type: TypeNumber,
-	}
+	}, opts)
 }
 
-func NewIntegerSchema() *Schema {
-	return &Schema{
+func NewIntegerSchema(opts ...SchemaOption) *Schema {
+	return newSchema(&Schema{
 		Type: TypeInteger,
-	}
+	}, opts)
 }
 
-func NewInt32Schema() *Schema {
-	return &Schema{
+func NewInt32Schema(opts ...SchemaOption) *Schema {
+	return newSchema(&Schema{
 		Type:   TypeInteger,
 		Format: "int32",
-	}
+	}, opts)
 }
 
-func NewInt64Schema() *Schema {
-	return &Schema{
+func NewInt64Schema(opts ...SchemaOption) *Schema {
+	return newSchema(&Schema{
 		Type:   TypeInteger,
 		Format: "int64",
-	}
+	}, opts)
 }
 
-func NewStringSchema() *Schema {
-	return &Schema{
+func NewStringSchema(opts ...SchemaOption) *Schema {
+	return newSchema(&Schema{
 		Type: TypeString,
-	}
+	}, opts)
 }
 
-func NewDateTimeSchema() *Schema {
-	return &Schema{
+func NewDateTimeSchema(opts ...SchemaOption) *Schema {
+	return newSchema(&Schema{
 		Type:   TypeString,
 		Format: "date-time",
-	}
+	}, opts)
 }
 
-func NewUUIDSchema() *Schema {
-	return &Schema{
+func NewUUIDSchema(opts ...SchemaOption) *Schema {
+	return newSchema(&Schema{
 		Type:   TypeString,
 		Format: "uuid",
-	}
+	}, opts)
 }
 
-func NewBytesSchema() *Schema {
-	return &Schema{
+func NewBytesSchema(opts ...SchemaOption) *Schema {
+	return newSchema(&Schema{
 		Type:   TypeString,
 		Format: "byte",
-	}
+	}, opts)
 }
 
-func NewArraySchema() *Schema {
-	return &Schema{
+func NewArraySchema(opts ...SchemaOption) *Schema {
+	return newSchema(&Schema{
 		Type: TypeArray,
-	}
+	}, opts)
 }
 
-func NewObjectSchema() *Schema {
-	return &Schema{
+func NewObjectSchema(opts ...SchemaOption) *Schema {
+	return newSchema(&Schema{
 		Type:       TypeObject,
-		Properties: make(Schemas),
-	}
+		Properties: NewSchemas(),
+	}, opts)
 }
 
 func (schema *Schema) WithNullable() *Schema {
@@ -422,6 +542,66 @@ func (schema *Schema) WithExclusiveMax(value bool) *Schema {
 	return schema
 }
 
+func (schema *Schema) WithExclusiveMinValue(value float64) *Schema {
+	schema.ExclusiveMinValue = &value
+	return schema
+}
+
+func (schema *Schema) WithExclusiveMaxValue(value float64) *Schema {
+	schema.ExclusiveMaxValue = &value
+	return schema
+}
+
+// Minimum returns the lower bound of the numbers schema allows and whether it is exclusive,
+// whichever form of exclusiveMinimum schema uses. ok is false when schema sets no lower bound.
+func (schema *Schema) Minimum() (value float64, exclusive bool, ok bool) {
+	if v := schema.Min; v != nil {
+		value, exclusive, ok = *v, schema.ExclusiveMin, true
+	}
+	if v := schema.ExclusiveMinValue; v != nil && (!ok || *v >= value) {
+		value, exclusive, ok = *v, true, true
+	}
+	return
+}
+
+// Maximum returns the upper bound of the numbers schema allows and whether it is exclusive,
+// whichever form of exclusiveMaximum schema uses. ok is false when schema sets no upper bound.
+func (schema *Schema) Maximum() (value float64, exclusive bool, ok bool) {
+	if v := schema.Max; v != nil {
+		value, exclusive, ok = *v, schema.ExclusiveMax, true
+	}
+	if v := schema.ExclusiveMaxValue; v != nil && (!ok || *v <= value) {
+		value, exclusive, ok = *v, true, true
+	}
+	return
+}
+
+// convertExclusiveBounds rewrites exclusiveMinimum and exclusiveMaximum to their form
+// in the given OpenAPI version: booleans modifying minimum and maximum in 3.0,
+// numbers as of 3.1. The bounds schema allows are unchanged.
+func (schema *Schema) convertExclusiveBounds(version string) {
+	switch {
+	case strings.HasPrefix(version, "3.0"):
+		if schema.ExclusiveMinValue != nil {
+			min, exclusive, _ := schema.Minimum()
+			schema.Min, schema.ExclusiveMin, schema.ExclusiveMinValue = &min, exclusive, nil
+		}
+		if schema.ExclusiveMaxValue != nil {
+			max, exclusive, _ := schema.Maximum()
+			schema.Max, schema.ExclusiveMax, schema.ExclusiveMaxValue = &max, exclusive, nil
+		}
+	case strings.HasPrefix(version, "3."):
+		if schema.ExclusiveMin && schema.Min != nil {
+			min, _, _ := schema.Minimum()
+			schema.Min, schema.ExclusiveMin, schema.ExclusiveMinValue = nil, false, &min
+		}
+		if schema.ExclusiveMax && schema.Max != nil {
+			max, _, _ := schema.Maximum()
+			schema.Max, schema.ExclusiveMax, schema.ExclusiveMaxValue = nil, false, &max
+		}
+	}
+}
+
 func (schema *Schema) WithEnum(values ...interface{}) *Schema {
 	schema.Enum = values
 	return schema
@@ -515,19 +695,19 @@ func (schema *Schema) WithProperty(name string, propertySchema *Schema) *Schema
 func (schema *Schema) WithPropertyRef(name string, ref *SchemaRef) *Schema {
 	properties := schema.Properties
 	if properties == nil {
-		properties = make(Schemas)
+		properties = NewSchemas()
 		schema.Properties = properties
 	}
-	properties[name] = ref
+	properties.Set(name, ref)
 	return schema
 }
 
 func (schema *Schema) WithProperties(properties map[string]*Schema) *Schema {
-	result := make(Schemas, len(properties))
+	result := NewSchemas()
 	for k, v := range properties {
-		result[k] = &SchemaRef{
+		result.Set(k, &SchemaRef{
 			Value: v,
-		}
+		})
 	}
 	schema.Properties = result
 	return schema
@@ -566,9 +746,11 @@ func (schema *Schema) WithAdditionalProperties(v *Schema) *Schema {
 func (schema *Schema) IsEmpty() bool {
 	if schema.Type != "" || schema.Format != "" || len(schema.Enum) != 0 ||
 		schema.UniqueItems || schema.ExclusiveMin || schema.ExclusiveMax ||
+		schema.ExclusiveMinValue != nil || schema.ExclusiveMaxValue != nil ||
 		schema.Nullable || schema.ReadOnly || schema.WriteOnly || schema.AllowEmptyValue ||
 		schema.Min != nil || schema.Max != nil || schema.MultipleOf != nil ||
 		schema.MinLength != 0 || schema.MaxLength != nil || schema.Pattern != "" ||
+		schema.ContentEncoding != "" || schema.ContentMediaType != "" || schema.ContentSchema != nil ||
 		schema.MinItems != 0 || schema.MaxItems != nil ||
 		len(schema.Required) != 0 ||
 		schema.MinProps != 0 || schema.MaxProps != nil {
@@ -586,7 +768,7 @@ func (schema *Schema) IsEmpty() bool {
 	if items := schema.Items; items != nil && !items.Value.IsEmpty() {
 		return false
 	}
-	for _, s := range schema.Properties {
+	for _, s := range schema.Properties.items() {
 		if !s.Value.IsEmpty() {
 			return false
 		}
@@ -630,6 +812,9 @@ func (schema *Schema) validate(ctx context.Context, stack []*Schema) (err error)
 	}
 
 	for _, item := range schema.OneOf {
+		if item == nil {
+			return errors.New("oneOf must not contain null")
+		}
 		v := item.Value
 		if v == nil {
 			return foundUnresolvedRef(item.Ref)
@@ -640,6 +825,9 @@ func (schema *Schema) validate(ctx context.Context, stack []*Schema) (err error)
 	}
 
 	for _, item := range schema.AnyOf {
+		if item == nil {
+			return errors.New("anyOf must not contain null")
+		}
 		v := item.Value
 		if v == nil {
 			return foundUnresolvedRef(item.Ref)
@@ -650,6 +838,9 @@ func (schema *Schema) validate(ctx context.Context, stack []*Schema) (err error)
 	}
 
 	for _, item := range schema.AllOf {
+		if item == nil {
+			return errors.New("allOf must not contain null")
+		}
 		v := item.Value
 		if v == nil {
 			return foundUnresolvedRef(item.Ref)
@@ -669,6 +860,16 @@ func (schema *Schema) validate(ctx context.Context, stack []*Schema) (err error)
 		}
 	}
 
+	if ref := schema.ContentSchema; ref != nil {
+		v := ref.Value
+		if v == nil {
+			return foundUnresolvedRef(ref.Ref)
+		}
+		if err = v.validate(ctx, stack); err != nil {
+			return
+		}
+	}
+
 	schemaType := schema.Type
 	switch schemaType {
 	case "":
@@ -739,13 +940,16 @@ func (schema *Schema) validate(ctx context.Context, stack []*Schema) (err error)
 		}
 	}
 
-	properties := make([]string, 0, len(schema.Properties))
-	for name := range schema.Properties {
+	properties := make([]string, 0, schema.Properties.Len())
+	for name := range schema.Properties.items() {
 		properties = append(properties, name)
 	}
 	sort.Strings(properties)
 	for _, name := range properties {
-		ref := schema.Properties[name]
+		ref := schema.Properties.Value(name)
+		if ref == nil {
+			return fmt.Errorf("property %q must not be null", name)
+		}
 		v := ref.Value
 		if v == nil {
 			return foundUnresolvedRef(ref.Ref)
@@ -765,6 +969,14 @@ func (schema *Schema) validate(ctx context.Context, stack []*Schema) (err error)
 		}
 	}
 
+	if _, err = schema.requiredWhenRules(); err != nil {
+		return
+	}
+
+	if err = schema.validateValidatorNames(); err != nil {
+		return
+	}
+
 	if v := schema.ExternalDocs; v != nil {
 		if err = v.Validate(ctx); err != nil {
 			return fmt.Errorf("invalid external docs: %w", err)
@@ -818,10 +1030,43 @@ func (schema *Schema) IsMatchingJSONObject(value map[string]interface{}) bool {
 
 func (schema *Schema) VisitJSON(value interface{}, opts ...SchemaValidationOption) error {
 	settings := newSchemaValidationSettings(opts...)
-	return schema.visitJSON(settings, value)
+	return schema.visitRootJSON(settings, value, schema.Title)
+}
+
+// VisitJSON validates value against the schema of ref like Schema.VisitJSON,
+// naming the schema errors after the component ref references (see SchemaError.SchemaName).
+func (ref *SchemaRef) VisitJSON(value interface{}, opts ...SchemaValidationOption) error {
+	if ref.Value == nil {
+		return foundUnresolvedRef(ref.Ref)
+	}
+	settings := newSchemaValidationSettings(opts...)
+	return ref.Value.visitRootJSON(settings, value, schemaRefName(ref))
+}
+
+// visitRootJSON validates value against schema, the root of validation named name,
+// then completes the schema errors.
+func (schema *Schema) visitRootJSON(settings *schemaValidationSettings, value interface{}, name string) error {
+	err := schema.visitJSON(settings, value)
+	if err == nil {
+		return nil
+	}
+	if name != "" {
+		setSchemaErrorName(err, name)
+	}
+	if settings.errorVerbosity != SchemaErrorVerbosityDefault {
+		setSchemaErrorVerbosity(err, settings.errorVerbosity)
+	}
+	return err
 }
 
 func (schema *Schema) visitJSON(settings *schemaValidationSettings, value interface{}) (err error) {
+	if err = schema.visitJSONValue(settings, value); err != nil {
+		return
+	}
+	return schema.visitValidators(settings, value)
+}
+
+func (schema *Schema) visitJSONValue(settings *schemaValidationSettings, value interface{}) (err error) {
 	switch value := value.(type) {
 	case nil:
 		return schema.visitJSONNull(settings)
@@ -880,10 +1125,8 @@ func (schema *Schema) visitJSON(settings *schemaValidationSettings, value interf
 
 func (schema *Schema) visitSetOperations(settings *schemaValidationSettings, value interface{}) (err error) {
 	if enum := schema.Enum; len(enum) != 0 {
-		for _, v := range enum {
-			if reflect.DeepEqual(v, value) {
-				return
-			}
+		if schema.isInEnum(value) {
+			return
 		}
 		if settings.failfast {
 			return errSchema
@@ -977,7 +1220,7 @@ func (schema *Schema) visitSetOperations(settings *schemaValidationSettings, val
 			}
 
 			if err := v.visitJSON(settings, tempValue); err != nil {
-				validationErrors = append(validationErrors, err)
+				validationErrors = append(validationErrors, markSchemaErrorName(err, item))
 				continue
 			}
 
@@ -1061,7 +1304,7 @@ func (schema *Schema) visitSetOperations(settings *schemaValidationSettings, val
 				Value:                 value,
 				Schema:                schema,
 				SchemaField:           "allOf",
-				Origin:                err,
+				Origin:                markSchemaErrorName(err, item),
 				customizeMessageError: settings.customizeMessageError,
 			}
 		}
@@ -1101,6 +1344,34 @@ func (schema *Schema) visitJSONBoolean(settings *schemaValidationSettings, value
 	return
 }
 
+// isMultipleOf tells whether value is a multiple of divisor, the decimal numbers
+// as written in JSON, rather than their nearest binary floating-point values:
+// 0.07 is a multiple of 0.01 although 0.07/0.01 is not an integer in float64 arithmetic.
+func isMultipleOf(value, divisor float64) bool {
+	if divisor == 0 || math.IsInf(value, 0) || math.IsInf(divisor, 0) {
+		return false
+	}
+	if value == math.Trunc(value) && divisor == math.Trunc(divisor) {
+		return math.Mod(value, divisor) == 0
+	}
+	v, ok := new(big.Rat).SetString(strconv.FormatFloat(value, 'g', -1, 64))
+	if !ok {
+		return false
+	}
+	d, ok := new(big.Rat).SetString(strconv.FormatFloat(divisor, 'g', -1, 64))
+	if !ok {
+		return false
+	}
+	return v.Quo(v, d).IsInt()
+}
+
+func integerFormatReason(format string, settings *schemaValidationSettings) string {
+	if format == "int64" && settings.strictIntegerFormats {
+		return "number must be an int64 represented exactly, between -(2^53-1) and 2^53-1"
+	}
+	return fmt.Sprintf("number must be an %s", format)
+}
+
 func (schema *Schema) VisitJSONNumber(value float64) error {
 	settings := newSchemaValidationSettings()
 	return schema.visitJSONNumber(settings, value)
@@ -1141,6 +1412,10 @@ func (schema *Schema) visitJSONNumber(settings *schemaValidationSettings, value
 		case "int64":
 			formatMin = formatMinInt64
 			formatMax = formatMaxInt64
+			if settings.strictIntegerFormats {
+				formatMin = -maxExactInteger
+				formatMax = maxExactInteger
+			}
 		default:
 			if settings.formatValidationEnabled {
 				return unsupportedFormat(schema.Format)
@@ -1154,7 +1429,7 @@ func (schema *Schema) visitJSONNumber(settings *schemaValidationSettings, value
 				Value:                 value,
 				Schema:                schema,
 				SchemaField:           "format",
-				Reason:                fmt.Sprintf("number must be an %s", schema.Format),
+				Reason:                integerFormatReason(schema.Format, settings),
 				customizeMessageError: settings.customizeMessageError,
 			}
 			if !settings.multiError {
@@ -1200,6 +1475,42 @@ func (schema *Schema) visitJSONNumber(settings *schemaValidationSettings, value
 		me = append(me, err)
 	}
 
+	// "exclusiveMinimum", OpenAPI 3.1 form
+	if v := schema.ExclusiveMinValue; v != nil && !(*v < value) {
+		if settings.failfast {
+			return errSchema
+		}
+		err := &SchemaError{
+			Value:                 value,
+			Schema:                schema,
+			SchemaField:           "exclusiveMinimum",
+			Reason:                fmt.Sprintf("number must be more than %g", *v),
+			customizeMessageError: settings.customizeMessageError,
+		}
+		if !settings.multiError {
+			return err
+		}
+		me = append(me, err)
+	}
+
+	// "exclusiveMaximum", OpenAPI 3.1 form
+	if v := schema.ExclusiveMaxValue; v != nil && !(*v > value) {
+		if settings.failfast {
+			return errSchema
+		}
+		err := &SchemaError{
+			Value:                 value,
+			Schema:                schema,
+			SchemaField:           "exclusiveMaximum",
+			Reason:                fmt.Sprintf("number must be less than %g", *v),
+			customizeMessageError: settings.customizeMessageError,
+		}
+		if !settings.multiError {
+			return err
+		}
+		me = append(me, err)
+	}
+
 	// "minimum"
 	if v := schema.Min; v != nil && !(*v <= value) {
 		if settings.failfast {
@@ -1240,7 +1551,7 @@ func (schema *Schema) visitJSONNumber(settings *schemaValidationSettings, value
 	if v := schema.MultipleOf; v != nil {
 		// "A numeric instance is valid only if division by this keyword's
 		//    value results in an integer."
-		if bigFloat := big.NewFloat(value / *v); !bigFloat.IsInt() {
+		if !isMultipleOf(value, *v) {
 			if settings.failfast {
 				return errSchema
 			}
@@ -1382,6 +1693,16 @@ func (schema *Schema) visitJSONString(settings *schemaValidationSettings, value
 
 	}
 
+	// "contentEncoding", "contentMediaType" and "contentSchema"
+	if settings.maxContentSize > 0 {
+		if err := schema.visitContent(settings, value); err != nil {
+			if !settings.multiError {
+				return err
+			}
+			me = append(me, err)
+		}
+	}
+
 	if len(me) > 0 {
 		return me
 	}
@@ -1468,7 +1789,7 @@ func (schema *Schema) visitJSONArray(settings *schemaValidationSettings, value [
 		}
 		for i, item := range value {
 			if err := itemSchema.visitJSON(settings, item); err != nil {
-				err = markSchemaErrorIndex(err, i)
+				err = markSchemaErrorIndex(markSchemaErrorName(err, itemSchemaRef), i)
 				if !settings.multiError {
 					return err
 				}
@@ -1501,13 +1822,13 @@ func (schema *Schema) visitJSONObject(settings *schemaValidationSettings, value
 	var me MultiError
 
 	if settings.asreq || settings.asrep {
-		properties := make([]string, 0, len(schema.Properties))
-		for propName := range schema.Properties {
+		properties := make([]string, 0, schema.Properties.Len())
+		for propName := range schema.Properties.items() {
 			properties = append(properties, propName)
 		}
 		sort.Strings(properties)
 		for _, propName := range properties {
-			propSchema := schema.Properties[propName]
+			propSchema := schema.Properties.Value(propName)
 			reqRO := settings.asreq && propSchema.Value.ReadOnly
 			repWO := settings.asrep && propSchema.Value.WriteOnly
 
@@ -1583,7 +1904,7 @@ func (schema *Schema) visitJSONObject(settings *schemaValidationSettings, value
 	for _, k := range keys {
 		v := value[k]
 		if properties != nil {
-			propertyRef := properties[k]
+			propertyRef := properties.Value(k)
 			if propertyRef != nil {
 				p := propertyRef.Value
 				if p == nil {
@@ -1593,7 +1914,7 @@ func (schema *Schema) visitJSONObject(settings *schemaValidationSettings, value
 					if settings.failfast {
 						return errSchema
 					}
-					err = markSchemaErrorKey(err, k)
+					err = markSchemaErrorKey(markSchemaErrorName(err, propertyRef), k)
 					if !settings.multiError {
 						return err
 					}
@@ -1613,7 +1934,7 @@ func (schema *Schema) visitJSONObject(settings *schemaValidationSettings, value
 					if settings.failfast {
 						return errSchema
 					}
-					err = markSchemaErrorKey(err, k)
+					err = markSchemaErrorKey(markSchemaErrorName(err, schema.AdditionalProperties), k)
 					if !settings.multiError {
 						return err
 					}
@@ -1642,13 +1963,31 @@ func (schema *Schema) visitJSONObject(settings *schemaValidationSettings, value
 		me = append(me, err)
 	}
 
+	// "x-required-when"
+	for _, missing := range schema.missingRequiredWhen(settings, value) {
+		if settings.failfast {
+			return errSchema
+		}
+		err := markSchemaErrorKey(&SchemaError{
+			Value:                 value,
+			Schema:                schema,
+			SchemaField:           ExtensionRequiredWhen,
+			Reason:                fmt.Sprintf("property %q is missing when %s", missing.property, missing.condition),
+			customizeMessageError: settings.customizeMessageError,
+		}, missing.property)
+		if !settings.multiError {
+			return err
+		}
+		me = append(me, err)
+	}
+
 	// "required"
 	for _, k := range schema.Required {
 		if _, ok := value[k]; !ok {
-			if s := schema.Properties[k]; s != nil && s.Value.ReadOnly && settings.asreq {
+			if s := schema.Properties.Value(k); s != nil && s.Value.ReadOnly && settings.asreq {
 				continue
 			}
-			if s := schema.Properties[k]; s != nil && s.Value.WriteOnly && settings.asrep {
+			if s := schema.Properties.Value(k); s != nil && s.Value.WriteOnly && settings.asrep {
 				continue
 			}
 			if settings.failfast {
@@ -1688,6 +2027,42 @@ func (schema *Schema) expectedType(settings *schemaValidationSettings, typ strin
 	}
 }
 
+// CompilePatterns compiles the patterns of the schema and of its subschemas ahead of validation,
+// which compiles them when first used otherwise.
+// It returns the error of the first pattern that does not compile.
+func (schema *Schema) CompilePatterns() error {
+	return schema.compilePatterns(make(map[*Schema]bool))
+}
+
+func (schema *Schema) compilePatterns(visited map[*Schema]bool) error {
+	if visited[schema] {
+		return nil
+	}
+	visited[schema] = true
+	if schema.Pattern != "" && schema.compiledPattern == nil {
+		if err := schema.compilePattern(); err != nil {
+			return err
+		}
+	}
+	subschemas := make([]*SchemaRef, 0, len(schema.OneOf)+len(schema.AnyOf)+len(schema.AllOf)+schema.Properties.Len()+4)
+	subschemas = append(subschemas, schema.OneOf...)
+	subschemas = append(subschemas, schema.AnyOf...)
+	subschemas = append(subschemas, schema.AllOf...)
+	subschemas = append(subschemas, schema.Not, schema.Items, schema.AdditionalProperties, schema.ContentSchema)
+	for _, name := range componentNames(schema.Properties) {
+		subschemas = append(subschemas, schema.Properties.Value(name))
+	}
+	for _, ref := range subschemas {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		if err := ref.Value.compilePatterns(visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (schema *Schema) compilePattern() (err error) {
 	if schema.compiledPattern, err = regexp.Compile(schema.Pattern); err != nil {
 		return &SchemaError{
@@ -1700,15 +2075,57 @@ func (schema *Schema) compilePattern() (err error) {
 }
 
 type SchemaError struct {
-	Value                 interface{}
-	reversePath           []string
-	Schema                *Schema
-	SchemaField           string
-	Reason                string
-	Origin                error
+	Value       interface{}
+	reversePath []string
+	Schema      *Schema
+	SchemaField string
+	Reason      string
+	Origin      error
+	// SchemaName names the schema the value does not match, or else the nearest schema
+	// enclosing it that has a name: the name of the component it references (e.g. "PizzaOrder"
+	// for "#/components/schemas/PizzaOrder"), or else its title.
+	SchemaName string
+	// Verbosity sets how much Error prints. See SetSchemaErrorVerbosity.
+	Verbosity             SchemaErrorVerbosity
 	customizeMessageError func(err *SchemaError) string
 }
 
+// SchemaErrorVerbosity sets how much of a SchemaError its Error method prints.
+type SchemaErrorVerbosity int
+
+const (
+	// SchemaErrorVerbosityDefault is SchemaErrorFull,
+	// or SchemaErrorWithPointer when SchemaErrorDetailsDisabled is set.
+	SchemaErrorVerbosityDefault SchemaErrorVerbosity = iota
+	// SchemaErrorShort prints the reason of the error only, e.g. "value must be a string".
+	SchemaErrorShort
+	// SchemaErrorWithPointer prefixes the reason with where the value is,
+	// e.g. `Error at "/pets/0/name": value must be a string`.
+	SchemaErrorWithPointer
+	// SchemaErrorFull also prints the schema and the value, for debugging.
+	SchemaErrorFull
+)
+
+// setSchemaErrorVerbosity sets the verbosity of the schema errors err is made of
+// that do not set theirs.
+func setSchemaErrorVerbosity(err error, verbosity SchemaErrorVerbosity) {
+	switch err := err.(type) {
+	case *SchemaError:
+		if err.Verbosity == SchemaErrorVerbosityDefault {
+			err.Verbosity = verbosity
+		}
+		if err.Origin != nil {
+			setSchemaErrorVerbosity(err.Origin, verbosity)
+		}
+	case MultiError:
+		for _, e := range err {
+			setSchemaErrorVerbosity(e, verbosity)
+		}
+	case multiErrorForOneOf:
+		setSchemaErrorVerbosity(MultiError(err), verbosity)
+	}
+}
+
 var _ interface{ Unwrap() error } = SchemaError{}
 
 func markSchemaErrorKey(err error, key string) error {
@@ -1731,6 +2148,49 @@ func markSchemaErrorKey(err error, key string) error {
 	return err
 }
 
+// markSchemaErrorName sets the SchemaName of the schema errors of err that do not have one
+// to the name of ref, which err comes from.
+func markSchemaErrorName(err error, ref *SchemaRef) error {
+	name := schemaRefName(ref)
+	if name != "" {
+		setSchemaErrorName(err, name)
+	}
+	return err
+}
+
+// schemaRefName returns the name of the component ref references, or else the title of its schema.
+func schemaRefName(ref *SchemaRef) string {
+	if ref.Ref != "" {
+		return DefaultRefNameResolver(ref.Ref)
+	}
+	if ref.Value != nil {
+		return ref.Value.Title
+	}
+	return ""
+}
+
+func setSchemaErrorName(err error, name string) {
+	switch e := err.(type) {
+	case *SchemaError:
+		if e.SchemaName == "" {
+			e.SchemaName = name
+		}
+		if e.Origin != nil {
+			setSchemaErrorName(e.Origin, name)
+		}
+	case MultiError:
+		for _, e := range e {
+			setSchemaErrorName(e, name)
+		}
+	case multiErrorForOneOf:
+		setSchemaErrorName(MultiError(e), name)
+	default:
+		if err := errors.Unwrap(err); err != nil {
+			setSchemaErrorName(err, name)
+		}
+	}
+}
+
 func markSchemaErrorIndex(err error, index int) error {
 	return markSchemaErrorKey(err, strconv.FormatInt(int64(index), 10))
 }
@@ -1751,9 +2211,17 @@ func (err *SchemaError) Error() string {
 		}
 	}
 
+	verbosity := err.Verbosity
+	if verbosity == SchemaErrorVerbosityDefault {
+		verbosity = SchemaErrorFull
+		if SchemaErrorDetailsDisabled {
+			verbosity = SchemaErrorWithPointer
+		}
+	}
+
 	buf := bytes.NewBuffer(make([]byte, 0, 256))
 
-	if len(err.reversePath) > 0 {
+	if len(err.reversePath) > 0 && verbosity >= SchemaErrorWithPointer {
 		buf.WriteString(`Error at "`)
 		reversePath := err.reversePath
 		for i := len(reversePath) - 1; i >= 0; i-- {
@@ -1778,7 +2246,7 @@ func (err *SchemaError) Error() string {
 		buf.WriteString(reason)
 	}
 
-	if !SchemaErrorDetailsDisabled {
+	if verbosity >= SchemaErrorFull {
 		buf.WriteString("\nSchema:\n  ")
 		encoder := json.NewEncoder(buf)
 		encoder.SetIndent("  ", "  ")
@@ -1798,16 +2266,41 @@ func (err SchemaError) Unwrap() error {
 	return err.Origin
 }
 
-func isSliceOfUniqueItems(xs []interface{}) bool {
-	s := len(xs)
-	m := make(map[string]struct{}, s)
-	for _, x := range xs {
-		// The input slice is coverted from a JSON string, there shall
-		// have no error when covert it back.
-		key, _ := json.Marshal(&x)
-		m[string(key)] = struct{}{}
+// SchemaErrorCode is the code of SchemaError JSON encodings.
+const SchemaErrorCode = "schema_mismatch"
+
+// MarshalJSON encodes the error for API responses, with stable fields:
+// code (SchemaErrorCode), keyword (SchemaField), pointer (to the invalid value), schema (SchemaName) and detail.
+// Unlike Error it never includes the schema nor the value.
+func (err *SchemaError) MarshalJSON() ([]byte, error) {
+	detail := err.detail()
+	var pointer string
+	if path := err.JSONPointer(); len(path) != 0 {
+		pointer = jsonPointer(path...)
+	}
+	return json.Marshal(struct {
+		Code    string `json:"code"`
+		Keyword string `json:"keyword,omitempty"`
+		Pointer string `json:"pointer,omitempty"`
+		Schema  string `json:"schema,omitempty"`
+		Detail  string `json:"detail"`
+	}{SchemaErrorCode, err.SchemaField, pointer, err.SchemaName, detail})
+}
+
+// detail returns the reason of err, or of its origin, as Error prints it at SchemaErrorShort verbosity.
+func (err *SchemaError) detail() string {
+	if err.customizeMessageError != nil {
+		if msg := err.customizeMessageError(err); msg != "" {
+			return msg
+		}
+	}
+	if err.Origin != nil {
+		return ErrorDetail(err.Origin)
+	}
+	if err.Reason == "" {
+		return fmt.Sprintf("doesn't match schema %q", err.SchemaField)
 	}
-	return s == len(m)
+	return err.Reason
 }
 
 // SliceUniqueItemsChecker is an function used to check if an given slice
@@ -0,0 +1,101 @@
+package openapi3
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// visitContent validates the content embedded in value, a string of schema,
+// as set by EnableContentValidation.
+func (schema *Schema) visitContent(settings *schemaValidationSettings, value string) error {
+	encoding := strings.ToLower(schema.ContentEncoding)
+	if encoding == "" && schema.Format == "byte" {
+		encoding = "base64"
+	}
+	isJSON := isJSONMediaType(schema.ContentMediaType) || schema.ContentSchema != nil
+	if encoding == "" && !isJSON {
+		return nil
+	}
+
+	fail := func(field, reason string, origin error) error {
+		if settings.failfast {
+			return errSchema
+		}
+		return &SchemaError{
+			Value:                 value,
+			Schema:                schema,
+			SchemaField:           field,
+			Reason:                reason,
+			Origin:                origin,
+			customizeMessageError: settings.customizeMessageError,
+		}
+	}
+
+	content := []byte(value)
+	switch encoding {
+	case "":
+	case "base64", "base64url":
+		if base64.StdEncoding.DecodedLen(len(value)) > settings.maxContentSize {
+			return fail("contentEncoding", fmt.Sprintf("decoded content exceeds %d bytes", settings.maxContentSize), nil)
+		}
+		var err error
+		if content, err = decodeBase64(value); err != nil {
+			return fail("contentEncoding", fmt.Sprintf("string is not valid %s: %v", encoding, err), nil)
+		}
+	default:
+		// Other encodings are not decoded
+		return nil
+	}
+	if len(content) > settings.maxContentSize {
+		return fail("contentMediaType", fmt.Sprintf("content exceeds %d bytes", settings.maxContentSize), nil)
+	}
+	if !isJSON {
+		return nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(content, &v); err != nil {
+		return fail("contentMediaType", fmt.Sprintf("content is not valid JSON: %v", err), nil)
+	}
+	ref := schema.ContentSchema
+	if ref == nil {
+		return nil
+	}
+	if ref.Value == nil {
+		return foundUnresolvedRef(ref.Ref)
+	}
+	if err := ref.Value.visitJSON(settings, v); err != nil {
+		if err == errSchema {
+			return err
+		}
+		return fail("contentSchema", "", err)
+	}
+	return nil
+}
+
+// decodeBase64 decodes s, encoded in base64 with or without padding,
+// with the standard or the URL-safe alphabet.
+func decodeBase64(s string) ([]byte, error) {
+	enc := base64.StdEncoding
+	if strings.ContainsAny(s, "-_") {
+		enc = base64.URLEncoding
+	}
+	if !strings.HasSuffix(s, "=") && len(s)%4 != 0 {
+		enc = enc.WithPadding(base64.NoPadding)
+	}
+	return enc.DecodeString(s)
+}
+
+func isJSONMediaType(contentType string) bool {
+	if contentType == "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,70 @@
+package openapi3
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaContentValidation(t *testing.T) {
+	const spec = `
+openapi: 3.0.3
+info: {title: Payments, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    Payment:
+      type: object
+      required: [amount]
+      properties:
+        amount: {type: integer, minimum: 1}
+    Signed:
+      type: object
+      properties:
+        payload:
+          type: string
+          contentEncoding: base64
+          contentMediaType: application/json
+          contentSchema: {$ref: '#/components/schemas/Payment'}
+        raw: {type: string, format: byte}
+        document: {type: string, contentMediaType: application/json}
+`
+	loader := NewLoader()
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	schema := doc.Components.Schemas.Value("Signed").Value
+	require.Same(t, doc.Components.Schemas.Value("Payment").Value, schema.Properties.Value("payload").Value.ContentSchema.Value)
+
+	encode := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+	invalid := map[string]interface{}{"payload": encode(`{"amount": 0}`)}
+	require.NoError(t, schema.VisitJSON(invalid), "content is not validated by default")
+
+	opt := EnableContentValidation(64)
+	for _, value := range []map[string]interface{}{
+		{"payload": encode(`{"amount": 10}`)},
+		{"payload": base64.RawURLEncoding.EncodeToString([]byte(`{"amount": 10}`))},
+		{"raw": encode("not JSON")},
+		{"document": `{"a": 1}`},
+	} {
+		require.NoError(t, schema.VisitJSON(value, opt), "%v", value)
+	}
+
+	SchemaErrorDetailsDisabled = true
+	defer func() { SchemaErrorDetailsDisabled = false }()
+	for _, tt := range []struct {
+		value   map[string]interface{}
+		message string
+	}{
+		{value: invalid, message: `Error at "/payload": Error at "/amount": number must be at least 1`},
+		{value: map[string]interface{}{"payload": encode(`{}`)}, message: `Error at "/payload": Error at "/amount": property "amount" is missing`},
+		{value: map[string]interface{}{"payload": encode(`{"amount":`)}, message: `Error at "/payload": content is not valid JSON: unexpected end of JSON input`},
+		{value: map[string]interface{}{"payload": "%%%"}, message: `Error at "/payload": string is not valid base64: illegal base64 data at input byte 0`},
+		{value: map[string]interface{}{"raw": encode(string(make([]byte, 65)))}, message: `Error at "/raw": decoded content exceeds 64 bytes`},
+		{value: map[string]interface{}{"document": `{`}, message: `Error at "/document": content is not valid JSON: unexpected end of JSON input`},
+	} {
+		err := schema.VisitJSON(tt.value, opt)
+		require.EqualError(t, err, tt.message)
+	}
+}
@@ -0,0 +1,179 @@
+package openapi3
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// GenerateExample returns a value conforming to schema.
+// The schema's example, default or first enum value is used when set,
+// otherwise a value is built from its type, format and constraints.
+// Pattern constraints are not taken into account.
+//
+// VisitAsRequest omits readOnly properties and VisitAsResponse omits writeOnly properties.
+// Recursive schemas are cut short by omitting optional properties
+// and leaving out array items.
+func (schema *Schema) GenerateExample(opts ...SchemaValidationOption) interface{} {
+	settings := newSchemaValidationSettings(opts...)
+	return schema.generateExample(settings, make(map[*Schema]struct{}))
+}
+
+func (schema *Schema) generateExample(settings *schemaValidationSettings, visited map[*Schema]struct{}) interface{} {
+	if schema == nil {
+		return nil
+	}
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) != 0:
+		return schema.Enum[0]
+	}
+
+	if _, ok := visited[schema]; ok {
+		return nil
+	}
+	visited[schema] = struct{}{}
+	defer delete(visited, schema)
+
+	if len(schema.AllOf) != 0 {
+		merged := make(map[string]interface{})
+		for _, v := range schema.AllOf {
+			value := v.Value.generateExample(settings, visited)
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				return value
+			}
+			for k, v := range m {
+				merged[k] = v
+			}
+		}
+		if schema.Properties.Len() == 0 {
+			return merged
+		}
+		for k, v := range schema.generateObjectExample(settings, visited) {
+			merged[k] = v
+		}
+		return merged
+	}
+	for _, alternatives := range []SchemaRefs{schema.OneOf, schema.AnyOf} {
+		if len(alternatives) != 0 && alternatives[0] != nil {
+			return alternatives[0].Value.generateExample(settings, visited)
+		}
+	}
+
+	switch schema.Type {
+	case TypeBoolean:
+		return true
+	case TypeInteger:
+		return math.Round(schema.generateNumberExample(1))
+	case TypeNumber:
+		return schema.generateNumberExample(0.5)
+	case TypeString:
+		return schema.generateStringExample()
+	case TypeArray:
+		if schema.Items == nil {
+			return []interface{}{}
+		}
+		item := schema.Items.Value.generateExample(settings, visited)
+		if item == nil {
+			return []interface{}{}
+		}
+		n := schema.MinItems
+		if n == 0 || schema.UniqueItems {
+			n = 1
+		}
+		items := make([]interface{}, 0, n)
+		for i := uint64(0); i < n; i++ {
+			items = append(items, item)
+		}
+		return items
+	case TypeObject, "":
+		if schema.Type == "" && schema.Properties.Len() == 0 {
+			return nil
+		}
+		return schema.generateObjectExample(settings, visited)
+	}
+	return nil
+}
+
+func (schema *Schema) generateObjectExample(settings *schemaValidationSettings, visited map[*Schema]struct{}) map[string]interface{} {
+	required := make(map[string]struct{}, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = struct{}{}
+	}
+	names := make([]string, 0, schema.Properties.Len())
+	for name := range schema.Properties.items() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	value := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		property := schema.Properties.Value(name).Value
+		if property == nil ||
+			(settings.asreq && property.ReadOnly) ||
+			(settings.asrep && property.WriteOnly) {
+			continue
+		}
+		v := property.generateExample(settings, visited)
+		if _, ok := required[name]; v == nil && !ok {
+			continue
+		}
+		value[name] = v
+	}
+	return value
+}
+
+func (schema *Schema) generateNumberExample(step float64) float64 {
+	var value float64
+	min, exclusiveMin, hasMin := schema.Minimum()
+	max, exclusiveMax, hasMax := schema.Maximum()
+	switch {
+	case hasMin:
+		value = min
+		if exclusiveMin {
+			value += step
+		}
+	case hasMax && max < 0:
+		value = max
+		if exclusiveMax {
+			value -= step
+		}
+	}
+	if hasMin && hasMax && value >= max {
+		value = (min + max) / 2
+	}
+	if m := schema.MultipleOf; m != nil && *m > 0 {
+		value = math.Ceil(value / *m) * *m
+	}
+	return value
+}
+
+var formatExamples = map[string]string{
+	"byte":      "ZXhhbXBsZQ==",
+	"date":      "2017-07-21",
+	"date-time": "2017-07-21T17:32:28Z",
+	"email":     "user@example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"uri":       "https://example.com",
+	"uuid":      "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+}
+
+func (schema *Schema) generateStringExample() string {
+	value, ok := formatExamples[schema.Format]
+	if !ok {
+		value = "string"
+	}
+	if n := int(schema.MinLength); len(value) < n {
+		value += strings.Repeat("x", n-len(value))
+	}
+	if max := schema.MaxLength; max != nil && uint64(len(value)) > *max {
+		value = value[:*max]
+	}
+	return value
+}
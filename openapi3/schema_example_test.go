@@ -0,0 +1,69 @@
+package openapi3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaGenerateExample(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.3
+info: {title: Example, version: '1.0'}
+paths: {}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id, name, kind]
+      properties:
+        id: {type: integer, format: int64, minimum: 1, readOnly: true}
+        name: {type: string, minLength: 10}
+        kind: {type: string, enum: [cat, dog]}
+        born: {type: string, format: date}
+        weight: {type: number, minimum: 0, exclusiveMinimum: true, maximum: 0.4}
+        tags: {type: array, minItems: 2, items: {type: string, maxLength: 3}}
+        secret: {type: string, writeOnly: true}
+        owner: {$ref: '#/components/schemas/Person'}
+        extra:
+          allOf:
+          - {type: object, properties: {a: {type: boolean}}}
+          - {type: object, properties: {b: {type: integer, multipleOf: 5, minimum: 7}}}
+    Person:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string, example: Alice}
+        pets: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+        friend: {$ref: '#/components/schemas/Person'}
+`)
+	doc, err := NewLoader().LoadFromData(spec)
+	require.NoError(t, err)
+	schema := doc.Components.Schemas.Value("Pet").Value
+
+	value := schema.GenerateExample()
+	require.Equal(t, map[string]interface{}{
+		"id":     float64(1),
+		"name":   "stringxxxx",
+		"kind":   "cat",
+		"born":   "2017-07-21",
+		"weight": 0.2,
+		"tags":   []interface{}{"str", "str"},
+		"secret": "string",
+		"owner":  map[string]interface{}{"name": "Alice", "pets": []interface{}{}},
+		"extra":  map[string]interface{}{"a": true, "b": float64(10)},
+	}, value)
+	require.NoError(t, schema.VisitJSON(value))
+
+	value = schema.GenerateExample(VisitAsRequest())
+	require.NotContains(t, value, "id")
+	require.Contains(t, value, "secret")
+
+	value = schema.GenerateExample(VisitAsResponse())
+	require.Contains(t, value, "id")
+	require.NotContains(t, value, "secret")
+
+	err = doc.Validate(context.Background())
+	require.NoError(t, err)
+}
@@ -0,0 +1,282 @@
+package openapi3
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VisitGoValue validates a Go value against the schema as encoding/json would encode it,
+// without encoding it: structs are visited as objects according to their json tags
+// (including "-", "omitempty", "string" and embedded structs), byte slices as base64 strings,
+// time.Time values as RFC 3339 strings and values implementing encoding.TextMarshaler as strings.
+// Values implementing json.Marshaler are encoded and decoded back, and so are not cheaper.
+//
+// Defaults set with the DefaultsSet option are not set in value.
+func (schema *Schema) VisitGoValue(value interface{}, opts ...SchemaValidationOption) error {
+	v, err := goValueToJSON(reflect.ValueOf(value))
+	if err != nil {
+		return err
+	}
+	return schema.VisitJSON(v, opts...)
+}
+
+// textMarshaler is encoding.TextMarshaler
+type textMarshaler interface {
+	MarshalText() ([]byte, error)
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*textMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// goValueToJSON converts v to the values json.Unmarshal decodes into interface{},
+// but for integers which are int64 values.
+func goValueToJSON(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+
+	t := v.Type()
+	switch {
+	case t == timeType:
+		return v.Interface().(time.Time).Format(time.RFC3339Nano), nil
+	case t.Implements(jsonMarshalerType):
+		return marshalerToJSON(v.Interface().(json.Marshaler))
+	case v.CanAddr() && reflect.PtrTo(t).Implements(jsonMarshalerType):
+		return marshalerToJSON(v.Addr().Interface().(json.Marshaler))
+	case t.Implements(textMarshalerType):
+		return textMarshalerToJSON(v.Interface().(textMarshaler))
+	case v.CanAddr() && reflect.PtrTo(t).Implements(textMarshalerType):
+		return textMarshalerToJSON(v.Addr().Interface().(textMarshaler))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := v.Uint(); u <= math.MaxInt64 {
+			return int64(u), nil
+		}
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Ptr, reflect.Interface:
+		return goValueToJSON(v.Elem())
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+		return sliceToJSON(v)
+	case reflect.Array:
+		return sliceToJSON(v)
+	case reflect.Map:
+		return mapToJSON(v)
+	case reflect.Struct:
+		return structToJSON(v)
+	}
+	return nil, fmt.Errorf("unsupported Go type %s", t)
+}
+
+func marshalerToJSON(m json.Marshaler) (interface{}, error) {
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func textMarshalerToJSON(m textMarshaler) (interface{}, error) {
+	text, err := m.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
+
+func sliceToJSON(v reflect.Value) (interface{}, error) {
+	items := make([]interface{}, v.Len())
+	for i := range items {
+		item, err := goValueToJSON(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+func mapToJSON(v reflect.Value) (interface{}, error) {
+	m := make(map[string]interface{}, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		k := iter.Key()
+		var key string
+		switch {
+		case k.Kind() == reflect.String:
+			key = k.String()
+		case k.Type().Implements(textMarshalerType):
+			text, err := k.Interface().(textMarshaler).MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			key = string(text)
+		case k.Kind() >= reflect.Int && k.Kind() <= reflect.Int64:
+			key = strconv.FormatInt(k.Int(), 10)
+		case k.Kind() >= reflect.Uint && k.Kind() <= reflect.Uintptr:
+			key = strconv.FormatUint(k.Uint(), 10)
+		default:
+			return nil, fmt.Errorf("unsupported Go map key type %s", k.Type())
+		}
+		value, err := goValueToJSON(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		m[key] = value
+	}
+	return m, nil
+}
+
+func structToJSON(v reflect.Value) (interface{}, error) {
+	fields := structFieldsOf(v.Type())
+	m := make(map[string]interface{}, len(fields))
+fields:
+	for _, field := range fields {
+		fv := v
+		for _, i := range field.index {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue fields
+				}
+				fv = fv.Elem()
+			}
+			fv = fv.Field(i)
+		}
+		if field.omitEmpty && isEmptyGoValue(fv) {
+			continue
+		}
+		value, err := goValueToJSON(fv)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.name, err)
+		}
+		if field.quoted {
+			switch value.(type) {
+			case bool, int64, float64:
+				value = fmt.Sprint(value)
+			case string:
+				value = strconv.Quote(value.(string))
+			}
+		}
+		m[field.name] = value
+	}
+	return m, nil
+}
+
+// goField is a struct field as encoding/json encodes it.
+type goField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	quoted    bool
+}
+
+var goFieldsCache sync.Map // map[reflect.Type][]goField
+
+func structFieldsOf(t reflect.Type) []goField {
+	if fields, ok := goFieldsCache.Load(t); ok {
+		return fields.([]goField)
+	}
+	fields := appendStructFields(nil, t, nil, make(map[string]int))
+	goFieldsCache.Store(t, fields)
+	return fields
+}
+
+// appendStructFields appends the fields of t to fields, those of embedded structs included,
+// unless shallower fields have the same names.
+func appendStructFields(fields []goField, t reflect.Type, index []int, depths map[string]int) []goField {
+	var embedded []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if i := strings.IndexByte(tag, ','); i >= 0 {
+			name, options = tag[:i], tag[i:]
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			embedded = append(embedded, f)
+			continue
+		}
+		if f.PkgPath != "" {
+			// Unexported
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, ok := depths[name]; ok {
+			continue
+		}
+		depths[name] = len(index)
+		fields = append(fields, goField{
+			name:      name,
+			index:     append(append([]int(nil), index...), i),
+			omitEmpty: strings.Contains(options, ",omitempty"),
+			quoted:    strings.Contains(options, ",string"),
+		})
+	}
+	for _, f := range embedded {
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		fields = appendStructFields(fields, ft, append(append([]int(nil), index...), f.Index...), depths)
+	}
+	return fields
+}
+
+func isEmptyGoValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type goValueOwner struct {
+	ID int `json:"id"`
+}
+
+type goValueBase struct {
+	Kind    string    `json:"kind"`
+	Created time.Time `json:"created"`
+}
+
+type goValuePet struct {
+	goValueBase
+	*goValueOwner
+	Name     string            `json:"name"`
+	Nickname string            `json:"nickname,omitempty"`
+	Age      uint8             `json:"age,string"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Photo    []byte            `json:"photo,omitempty"`
+	IP       net.IP            `json:"ip,omitempty"`
+	Extra    json.RawMessage   `json:"extra,omitempty"`
+	Secret   string            `json:"-"`
+	Weight   float64
+	internal int
+}
+
+func TestSchemaVisitGoValue(t *testing.T) {
+	var schema Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"required": ["kind", "name", "tags", "id", "Weight"],
+		"additionalProperties": false,
+		"properties": {
+			"kind": {"type": "string", "enum": ["dog", "cat"]},
+			"created": {"type": "string", "format": "date-time"},
+			"id": {"type": "integer", "minimum": 1},
+			"name": {"type": "string", "maxLength": 5},
+			"nickname": {"type": "string"},
+			"age": {"type": "string", "pattern": "^[0-9]+$"},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+			"photo": {"type": "string", "format": "byte"},
+			"ip": {"type": "string", "format": "ipv4"},
+			"extra": {"type": "object"},
+			"Weight": {"type": "number", "maximum": 100}
+		}
+	}`), &schema))
+
+	pet := goValuePet{
+		goValueBase:  goValueBase{Kind: "dog", Created: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)},
+		goValueOwner: &goValueOwner{ID: 1},
+		Name:         "Rex",
+		Age:          3,
+		Tags:         []string{"good"},
+		Photo:        []byte("png"),
+		IP:           net.IPv4(127, 0, 0, 1),
+		Extra:        json.RawMessage(`{"a": 1}`),
+		Secret:       "not validated",
+		Weight:       12.5,
+		internal:     1,
+	}
+	require.NoError(t, schema.VisitGoValue(pet))
+	require.NoError(t, schema.VisitGoValue(&pet))
+
+	// The same value encoded in JSON validates the same
+	data, err := json.Marshal(pet)
+	require.NoError(t, err)
+	var value interface{}
+	require.NoError(t, json.Unmarshal(data, &value))
+	require.NoError(t, schema.VisitJSON(value))
+	converted, err := goValueToJSON(reflect.ValueOf(pet))
+	require.NoError(t, err)
+	require.Equal(t, len(value.(map[string]interface{})), len(converted.(map[string]interface{})))
+
+	invalid := pet
+	invalid.Name = "Rexxxxx"
+	require.ErrorContains(t, schema.VisitGoValue(invalid), `Error at "/name": maximum string length is 5`)
+
+	invalid = pet
+	invalid.goValueOwner = nil
+	require.ErrorContains(t, schema.VisitGoValue(invalid), `property "id" is missing`)
+
+	invalid = pet
+	invalid.Tags = nil
+	require.ErrorContains(t, schema.VisitGoValue(invalid), `Error at "/tags": Value is not nullable`)
+
+	require.EqualError(t, schema.VisitGoValue(map[bool]int{true: 1}), "unsupported Go map key type bool")
+	require.EqualError(t, schema.VisitGoValue(struct{ C chan int }{}), "field C: unsupported Go type chan int")
+}
@@ -31,7 +31,7 @@ openapi: "3.0.1"
 
 	s, err := NewLoader().LoadFromData(spec)
 	require.NoError(t, err)
-	err = s.Components.Schemas["Server"].Value.VisitJSON(map[string]interface{}{
+	err = s.Components.Schemas.Value("Server").Value.VisitJSON(map[string]interface{}{
 		"name":    "kin-openapi",
 		"address": "127.0.0.1",
 	})
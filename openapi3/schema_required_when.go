@@ -0,0 +1,150 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ExtensionRequiredWhen is the schema extension requiring properties of objects
+// depending on the values of other properties, for OpenAPI 3.0 documents
+// which cannot use if/then/else.
+//
+// Its value is a list of rules, each made of the properties it requires
+// and of the values other properties must equal for it to apply:
+//
+//	type: object
+//	properties:
+//	  kind: {type: string, enum: [person, business]}
+//	  vatNumber: {type: string}
+//	x-required-when:
+//	- when: {kind: business}
+//	  required: [vatNumber]
+//
+// A rule applies when all the properties of its when are present and equal
+// to the given values. The missing properties are reported as SchemaErrors
+// of SchemaField "x-required-when", like those of "required".
+const ExtensionRequiredWhen = "x-required-when"
+
+type requiredWhenRule struct {
+	When     map[string]interface{} `json:"when"`
+	Required []string               `json:"required"`
+}
+
+// requiredWhenRules caches the rules decoded from the extension,
+// along with the value they were decoded from to tell when they are stale.
+type requiredWhenRules struct {
+	extension interface{}
+	rules     []requiredWhenRule
+}
+
+// requiredWhenRules returns the rules of the ExtensionRequiredWhen extension of schema.
+func (schema *Schema) requiredWhenRules() ([]requiredWhenRule, error) {
+	extension, ok := schema.Extensions[ExtensionRequiredWhen]
+	if !ok {
+		return nil, nil
+	}
+	if cached, _ := schema.requiredWhen.Load().(*requiredWhenRules); cached != nil && sameExtensionValue(cached.extension, extension) {
+		return cached.rules, nil
+	}
+
+	var rules []requiredWhenRule
+	if _, err := schema.DecodeExtension(ExtensionRequiredWhen, &rules); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ExtensionRequiredWhen, err)
+	}
+	for i, rule := range rules {
+		if len(rule.When) == 0 {
+			return nil, fmt.Errorf("invalid %s: rule %d: when must not be empty", ExtensionRequiredWhen, i)
+		}
+		if len(rule.Required) == 0 {
+			return nil, fmt.Errorf("invalid %s: rule %d: required must not be empty", ExtensionRequiredWhen, i)
+		}
+	}
+	// Concurrent validations may decode the rules more than once, which is harmless
+	schema.requiredWhen.Store(&requiredWhenRules{extension: extension, rules: rules})
+	return rules, nil
+}
+
+// sameExtensionValue reports whether a and b are the same raw JSON value,
+// other values not being cached.
+func sameExtensionValue(a, b interface{}) bool {
+	x, ok := a.(json.RawMessage)
+	if !ok {
+		return false
+	}
+	y, ok := b.(json.RawMessage)
+	return ok && len(x) == len(y) && len(x) != 0 && &x[0] == &y[0]
+}
+
+type missingRequiredWhen struct {
+	property  string
+	condition string
+}
+
+// missingRequiredWhen returns the properties of value required by the rules
+// of the ExtensionRequiredWhen extension of schema that are missing.
+// Invalid rules, reported when validating the document, are ignored.
+func (schema *Schema) missingRequiredWhen(settings *schemaValidationSettings, value map[string]interface{}) []missingRequiredWhen {
+	rules, err := schema.requiredWhenRules()
+	if err != nil || len(rules) == 0 {
+		return nil
+	}
+	var missing []missingRequiredWhen
+	for _, rule := range rules {
+		if !rule.applies(value) {
+			continue
+		}
+		condition := ""
+		for _, k := range rule.Required {
+			if _, ok := value[k]; ok {
+				continue
+			}
+			if s := schema.Properties.Value(k); s != nil && s.Value != nil &&
+				(s.Value.ReadOnly && settings.asreq || s.Value.WriteOnly && settings.asrep) {
+				continue
+			}
+			if condition == "" {
+				condition = rule.condition()
+			}
+			missing = append(missing, missingRequiredWhen{property: k, condition: condition})
+		}
+	}
+	return missing
+}
+
+func (rule requiredWhenRule) applies(value map[string]interface{}) bool {
+	for k, expected := range rule.When {
+		v, ok := value[k]
+		if !ok || !reflect.DeepEqual(normalizeRequiredWhenValue(v), expected) {
+			return false
+		}
+	}
+	return true
+}
+
+// condition describes when the rule applies, e.g. `"kind" is "business"`.
+func (rule requiredWhenRule) condition() string {
+	conditions := make([]string, 0, len(rule.When))
+	for _, k := range componentNames(rule.When) {
+		data, _ := json.Marshal(rule.When[k])
+		conditions = append(conditions, fmt.Sprintf("%q is %s", k, data))
+	}
+	return strings.Join(conditions, " and ")
+}
+
+// normalizeRequiredWhenValue converts the numbers of value, which may be
+// decoded as integers from parameters, to the float64 values of the rules.
+func normalizeRequiredWhenValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	}
+	return value
+}
@@ -0,0 +1,89 @@
+package openapi3
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaRequiredWhen(t *testing.T) {
+	loader := NewLoader()
+	doc, err := loader.LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Customers, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    Customer:
+      type: object
+      properties:
+        kind: {type: string, enum: [person, business]}
+        country: {type: string}
+        vatNumber: {type: string}
+        birthDate: {type: string}
+        id: {type: integer, readOnly: true}
+      x-required-when:
+      - when: {kind: business, country: FR}
+        required: [vatNumber, id]
+      - when: {kind: person}
+        required: [birthDate]
+`))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+	schema := doc.Components.Schemas.Value("Customer").Value
+
+	for _, value := range []map[string]interface{}{
+		{"kind": "person", "birthDate": "1970-01-01"},
+		{"kind": "business", "country": "DE"},
+		{"kind": "business", "country": "FR", "vatNumber": "FR1", "id": 1.0},
+		{"country": "FR"},
+	} {
+		require.NoError(t, schema.VisitJSON(value), value)
+	}
+
+	err = schema.VisitJSON(map[string]interface{}{"kind": "person"})
+	var schemaErr *SchemaError
+	require.True(t, errors.As(err, &schemaErr))
+	require.Equal(t, ExtensionRequiredWhen, schemaErr.SchemaField)
+	require.Equal(t, []string{"birthDate"}, schemaErr.JSONPointer())
+	require.Equal(t, `property "birthDate" is missing when "kind" is "person"`, schemaErr.Reason)
+
+	value := map[string]interface{}{"kind": "business", "country": "FR"}
+	err = schema.VisitJSON(value, MultiErrors())
+	var me MultiError
+	require.True(t, errors.As(err, &me))
+	require.Len(t, me, 2)
+	require.Contains(t, me[0].Error(), `property "vatNumber" is missing when "country" is "FR" and "kind" is "business"`)
+	require.Contains(t, me[1].Error(), `property "id" is missing`)
+
+	// readOnly properties are not required in requests
+	err = schema.VisitJSON(value, MultiErrors(), VisitAsRequest())
+	require.True(t, errors.As(err, &me))
+	require.Len(t, me, 1)
+
+	require.False(t, schema.IsMatching(value))
+}
+
+func TestSchemaRequiredWhenInvalid(t *testing.T) {
+	for _, rules := range []string{
+		`{kind: business}`,
+		`[{required: [vatNumber]}]`,
+		`[{when: {kind: business}}]`,
+	} {
+		doc, err := NewLoader().LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Customers, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    Customer:
+      type: object
+      x-required-when: ` + rules + `
+`))
+		require.NoError(t, err)
+		err = doc.Validate(context.Background())
+		require.ErrorContains(t, err, "invalid x-required-when", rules)
+	}
+}
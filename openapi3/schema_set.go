@@ -0,0 +1,127 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// enumIndex makes checking membership in an enum take constant time
+// for values of primitive types.
+type enumIndex struct {
+	// enum is the enum the index was built from, to tell when it is stale
+	enum []interface{}
+	// primitives are the values of enum of a comparable type
+	primitives map[interface{}]struct{}
+	// others are the remaining values, compared with reflect.DeepEqual
+	others []interface{}
+}
+
+// minIndexedEnumSize is the size from which enums are indexed:
+// shorter ones are searched through faster than they are indexed.
+const minIndexedEnumSize = 8
+
+func newEnumIndex(enum []interface{}) *enumIndex {
+	index := &enumIndex{
+		enum:       enum,
+		primitives: make(map[interface{}]struct{}, len(enum)),
+	}
+	for _, v := range enum {
+		if isPrimitiveValue(v) {
+			index.primitives[v] = struct{}{}
+		} else {
+			index.others = append(index.others, v)
+		}
+	}
+	return index
+}
+
+// contains reports whether value is reflect.DeepEqual to a value of the enum.
+func (index *enumIndex) contains(value interface{}) bool {
+	if isPrimitiveValue(value) {
+		_, ok := index.primitives[value]
+		return ok
+	}
+	for _, v := range index.others {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (index *enumIndex) isFor(enum []interface{}) bool {
+	return len(index.enum) == len(enum) && &index.enum[0] == &enum[0]
+}
+
+// isInEnum reports whether value is reflect.DeepEqual to a value of the enum of the schema.
+func (schema *Schema) isInEnum(value interface{}) bool {
+	enum := schema.Enum
+	if len(enum) < minIndexedEnumSize {
+		for _, v := range enum {
+			if reflect.DeepEqual(v, value) {
+				return true
+			}
+		}
+		return false
+	}
+	index, _ := schema.enumIndex.Load().(*enumIndex)
+	if index == nil || !index.isFor(enum) {
+		// Concurrent validations may build the index more than once, which is harmless
+		index = newEnumIndex(enum)
+		schema.enumIndex.Store(index)
+	}
+	return index.contains(value)
+}
+
+// isPrimitiveValue reports whether v is of a type values of which are equal,
+// as per reflect.DeepEqual, exactly when they are ==.
+func isPrimitiveValue(v interface{}) bool {
+	switch v.(type) {
+	case nil, bool, string, float64, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return true
+	}
+	return false
+}
+
+// jsonKey is the JSON encoding of a composite value, used as a key of a set of values.
+type jsonKey string
+
+// uniqueItemKey returns a key for x that is the same for values with the same JSON encoding.
+func uniqueItemKey(x interface{}) interface{} {
+	switch x := x.(type) {
+	case nil, bool, string, float64:
+		return x
+	case int:
+		return intKey(int64(x), x)
+	case int32:
+		return float64(x)
+	case int64:
+		return intKey(x, x)
+	}
+	return marshalKey(x)
+}
+
+// intKey returns the key of an integer i, converted to a float64 when this does not lose precision
+// so that it matches the same number decoded from JSON.
+func intKey(i int64, x interface{}) interface{} {
+	if f := float64(i); f < 1<<63 && int64(f) == i {
+		return f
+	}
+	return marshalKey(x)
+}
+
+func marshalKey(x interface{}) jsonKey {
+	// The input slice is converted from a JSON string: there shall be no error
+	// when converting it back.
+	key, _ := json.Marshal(x)
+	return jsonKey(key)
+}
+
+func isSliceOfUniqueItems(xs []interface{}) bool {
+	s := len(xs)
+	m := make(map[interface{}]struct{}, s)
+	for _, x := range xs {
+		m[uniqueItemKey(x)] = struct{}{}
+	}
+	return s == len(m)
+}
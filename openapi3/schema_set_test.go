@@ -0,0 +1,81 @@
+package openapi3
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaEnumIndex(t *testing.T) {
+	enum := []interface{}{nil, true, "a", float64(1), int64(2), map[string]interface{}{"k": "v"}, []interface{}{"x"}}
+	for i := 0; i < minIndexedEnumSize; i++ {
+		enum = append(enum, fmt.Sprintf("v%d", i))
+	}
+	schema := NewSchema().WithEnum(enum...)
+
+	for _, value := range []interface{}{nil, true, "a", "v7", float64(1), int64(2), map[string]interface{}{"k": "v"}, []interface{}{"x"}} {
+		require.True(t, schema.isInEnum(value), "%#v", value)
+	}
+	for _, value := range []interface{}{false, "b", float64(2), int64(1), 1, map[string]interface{}{"k": "w"}, []interface{}{}} {
+		require.False(t, schema.isInEnum(value), "%#v", value)
+	}
+
+	// The index follows changes of the enum
+	schema.Enum = append([]interface{}{"b"}, enum...)
+	require.True(t, schema.isInEnum("b"))
+	schema.Enum = enum[1:]
+	require.False(t, schema.isInEnum(nil))
+	require.Error(t, NewStringSchema().WithEnum(enum[1:]...).VisitJSON("b"))
+}
+
+func TestIsSliceOfUniqueItems(t *testing.T) {
+	tests := []struct {
+		items  []interface{}
+		unique bool
+	}{
+		{items: []interface{}{}, unique: true},
+		{items: []interface{}{"1", float64(1), true, nil, map[string]interface{}{}, []interface{}{}}, unique: true},
+		{items: []interface{}{"a", "a"}},
+		{items: []interface{}{nil, nil}},
+		{items: []interface{}{float64(1), 1}},
+		{items: []interface{}{int64(1 << 60), float64(1 << 60)}},
+		{items: []interface{}{int64(1<<60 + 1), float64(1 << 60)}, unique: true},
+		{items: []interface{}{
+			map[string]interface{}{"a": float64(1), "b": []interface{}{"c"}},
+			map[string]interface{}{"b": []interface{}{"c"}, "a": float64(1)},
+		}},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.unique, isSliceOfUniqueItems(tt.items), "%#v", tt.items)
+	}
+}
+
+func BenchmarkSchemaEnum(b *testing.B) {
+	enum := make([]interface{}, 1000)
+	for i := range enum {
+		enum[i] = fmt.Sprintf("value%d", i)
+	}
+	schema := NewStringSchema().WithEnum(enum...)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := schema.VisitJSON("value999"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkIsSliceOfUniqueItems(b *testing.B) {
+	items := make([]interface{}, 1000)
+	for i := range items {
+		items[i] = float64(i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !isSliceOfUniqueItems(items) {
+			b.Fatal("items are unique")
+		}
+	}
+}
@@ -469,9 +469,8 @@ var schemaExamples = []schemaExample{
 			UniqueItems: true,
 			Items: (&Schema{
 				Type: "object",
-				Properties: Schemas{
-					"key1": NewFloat64Schema().NewRef(),
-				},
+				Properties: NewSchemas().
+					With("key1", NewFloat64Schema().NewRef()),
 			}).NewRef(),
 		},
 		Serialization: map[string]interface{}{
@@ -526,13 +525,12 @@ var schemaExamples = []schemaExample{
 			UniqueItems: true,
 			Items: (&Schema{
 				Type: "object",
-				Properties: Schemas{
-					"key1": (&Schema{
+				Properties: NewSchemas().
+					With("key1", (&Schema{
 						Type:        "array",
 						UniqueItems: true,
 						Items:       NewFloat64Schema().NewRef(),
-					}).NewRef(),
-				},
+					}).NewRef()),
 			}).NewRef(),
 		},
 		Serialization: map[string]interface{}{
@@ -659,9 +657,8 @@ var schemaExamples = []schemaExample{
 				UniqueItems: true,
 				Items: (&Schema{
 					Type: "object",
-					Properties: Schemas{
-						"key1": NewFloat64Schema().NewRef(),
-					},
+					Properties: NewSchemas().
+						With("key1", NewFloat64Schema().NewRef()),
 				}).NewRef(),
 			}).NewRef(),
 		},
@@ -758,9 +755,8 @@ var schemaExamples = []schemaExample{
 		Schema: &Schema{
 			Type:     "object",
 			MaxProps: Uint64Ptr(2),
-			Properties: Schemas{
-				"numberProperty": NewFloat64Schema().NewRef(),
-			},
+			Properties: NewSchemas().
+				With("numberProperty", NewFloat64Schema().NewRef()),
 		},
 		Serialization: map[string]interface{}{
 			"type":          "object",
@@ -1297,7 +1293,7 @@ components:
 	s, err := NewLoader().LoadFromData([]byte(api))
 	require.NoError(t, err)
 	require.NotNil(t, s)
-	err = s.Components.Schemas["Test"].Value.VisitJSON(data)
+	err = s.Components.Schemas.Value("Test").Value.VisitJSON(data)
 	require.NotNil(t, err)
 	require.NotEqual(t, errSchema, err)
 	require.Contains(t, err.Error(), `Error at "/ownerName": Doesn't match schema "not"`)
@@ -1354,3 +1350,227 @@ enum:
 	err = schema.VisitJSON(map[string]interface{}{"d": "e"})
 	require.Error(t, err)
 }
+
+func TestSchemaCompilePatterns(t *testing.T) {
+	item := NewStringSchema().WithPattern("^[a-z]+$")
+	schema := NewObjectSchema().
+		WithProperty("name", NewStringSchema().WithPattern("^[A-Z]")).
+		WithProperty("tags", NewArraySchema().WithItems(item))
+	schema.AllOf = SchemaRefs{schema.NewRef()}
+
+	require.NoError(t, schema.CompilePatterns())
+	require.NotNil(t, schema.Properties.Value("name").Value.compiledPattern)
+	require.NotNil(t, item.compiledPattern)
+
+	schema.Properties.Set("id", NewStringSchema().WithPattern("[").NewRef())
+	err := schema.CompilePatterns()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `cannot compile pattern "["`)
+}
+
+func TestSchemaStrictIntegerFormats(t *testing.T) {
+	int64Schema := NewInt64Schema()
+	int32Schema := NewInt32Schema()
+
+	var value interface{}
+	require.NoError(t, json.Unmarshal([]byte(`9007199254740993`), &value))
+	require.NoError(t, int64Schema.VisitJSON(value))
+	err := int64Schema.VisitJSON(value, EnableStrictIntegerFormats())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "number must be an int64 represented exactly, between -(2^53-1) and 2^53-1")
+
+	for _, valid := range []float64{0, 1<<53 - 1, -(1<<53 - 1)} {
+		require.NoError(t, int64Schema.VisitJSON(valid, EnableStrictIntegerFormats()), valid)
+	}
+	for _, invalid := range []float64{1 << 53, -(1 << 53), 1 << 63} {
+		require.Error(t, int64Schema.VisitJSON(invalid, EnableStrictIntegerFormats()), invalid)
+	}
+
+	// int32 ranges are enforced regardless
+	require.Error(t, int32Schema.VisitJSON(float64(1<<31)))
+	require.Error(t, int32Schema.VisitJSON(float64(1<<31), EnableStrictIntegerFormats()))
+	require.NoError(t, int32Schema.VisitJSON(float64(1<<31-1), EnableStrictIntegerFormats()))
+}
+
+func TestSchemaMultipleOfDecimals(t *testing.T) {
+	cents := NewFloat64Schema().WithMin(0)
+	cents.MultipleOf = Float64Ptr(0.01)
+	for _, valid := range []float64{0, 0.07, 0.1, 0.29, 1.15, 19.99, 1234567.89, 100} {
+		require.NoError(t, cents.VisitJSON(valid), valid)
+	}
+	for _, invalid := range []float64{0.001, 0.075, 19.999, 1e-7} {
+		require.Error(t, cents.VisitJSON(invalid), invalid)
+	}
+
+	tenths := NewFloat64Schema()
+	tenths.MultipleOf = Float64Ptr(0.1)
+	require.NoError(t, tenths.VisitJSON(0.3))
+	require.NoError(t, tenths.VisitJSON(-0.7))
+	require.Error(t, tenths.VisitJSON(0.35))
+
+	threes := NewIntegerSchema()
+	threes.MultipleOf = Float64Ptr(3)
+	require.NoError(t, threes.VisitJSON(float64(9)))
+	require.NoError(t, threes.VisitJSON(float64(1<<53-2)))
+	require.Error(t, threes.VisitJSON(float64(10)))
+
+	require.True(t, isMultipleOf(1e21, 0.5))
+	require.False(t, isMultipleOf(1, 0))
+}
+
+func TestSchemaNumericExclusiveBounds(t *testing.T) {
+	var schema Schema
+	require.NoError(t, schema.UnmarshalJSON([]byte(`{"type":"number","exclusiveMinimum":0,"exclusiveMaximum":10}`)))
+	require.False(t, schema.ExclusiveMin)
+	require.Equal(t, Float64Ptr(0), schema.ExclusiveMinValue)
+	require.Equal(t, Float64Ptr(10), schema.ExclusiveMaxValue)
+	require.NoError(t, schema.VisitJSON(float64(5)))
+	err := schema.VisitJSON(float64(0))
+	require.IsType(t, &SchemaError{}, err)
+	require.Equal(t, "exclusiveMinimum", err.(*SchemaError).SchemaField)
+	require.Equal(t, "number must be more than 0", err.(*SchemaError).Reason)
+	require.Error(t, schema.VisitJSON(float64(10)))
+
+	data, err := schema.MarshalJSON()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type":"number","exclusiveMinimum":0,"exclusiveMaximum":10}`, string(data))
+
+	schema = Schema{}
+	require.NoError(t, schema.UnmarshalJSON([]byte(`{"type":"number","minimum":0,"exclusiveMinimum":true}`)))
+	require.True(t, schema.ExclusiveMin)
+	require.Nil(t, schema.ExclusiveMinValue)
+	min, exclusive, ok := schema.Minimum()
+	require.True(t, ok)
+	require.True(t, exclusive)
+	require.Equal(t, float64(0), min)
+	_, _, ok = schema.Maximum()
+	require.False(t, ok)
+
+	// The stricter of minimum and exclusiveMinimum applies
+	min, exclusive, _ = NewFloat64Schema().WithMin(5).WithExclusiveMinValue(1).Minimum()
+	require.Equal(t, float64(5), min)
+	require.False(t, exclusive)
+
+	for _, c := range []struct {
+		version, schema, expected string
+	}{
+		{"3.0.3", `{"exclusiveMinimum":0,"maximum":100,"exclusiveMaximum":50}`, `{"minimum":0,"exclusiveMinimum":true,"maximum":50,"exclusiveMaximum":true}`},
+		{"3.0.3", `{"minimum":5,"exclusiveMinimum":1}`, `{"minimum":5}`},
+		{"3.0.3", `{"minimum":0,"exclusiveMinimum":true}`, `{"minimum":0,"exclusiveMinimum":true}`},
+		{"3.1.0", `{"minimum":0,"exclusiveMinimum":true,"maximum":100}`, `{"exclusiveMinimum":0,"maximum":100}`},
+		{"3.1.0", `{"exclusiveMaximum":50}`, `{"exclusiveMaximum":50}`},
+	} {
+		t.Run(c.version+" "+c.schema, func(t *testing.T) {
+			doc, err := NewLoader().LoadFromData([]byte(`{"openapi":"` + c.version + `","info":{"title":"API","version":"1"},"paths":{},` +
+				`"components":{"schemas":{"n":` + c.schema + `}}}`))
+			require.NoError(t, err)
+			data, err := doc.Components.Schemas.Value("n").Value.MarshalJSON()
+			require.NoError(t, err)
+			require.JSONEq(t, c.expected, string(data))
+		})
+	}
+}
+
+func TestSchemaErrorVerbosity(t *testing.T) {
+	schema := NewObjectSchema().WithProperty("name", NewStringSchema()).WithProperty("age", NewIntegerSchema())
+	value := map[string]interface{}{"name": 42.0}
+
+	err := schema.VisitJSON(value, SetSchemaErrorVerbosity(SchemaErrorShort))
+	require.EqualError(t, err, "field must be set to string or not be present")
+	err = schema.VisitJSON(value, SetSchemaErrorVerbosity(SchemaErrorWithPointer))
+	require.EqualError(t, err, `Error at "/name": field must be set to string or not be present`)
+	err = schema.VisitJSON(value, SetSchemaErrorVerbosity(SchemaErrorFull))
+	require.Contains(t, err.Error(), `Error at "/name": field must be set to string or not be present`+"\nSchema:\n")
+
+	// Errors may be given their own verbosity
+	err = schema.VisitJSON(value)
+	require.Contains(t, err.Error(), "\nSchema:\n")
+	err.(*SchemaError).Verbosity = SchemaErrorShort
+	require.EqualError(t, err, "field must be set to string or not be present")
+
+	// and it applies to all errors
+	err = schema.VisitJSON(map[string]interface{}{"name": 42.0, "age": "1"},
+		MultiErrors(), SetSchemaErrorVerbosity(SchemaErrorWithPointer))
+	require.IsType(t, MultiError{}, err)
+	require.Len(t, err.(MultiError), 2)
+	for _, e := range err.(MultiError) {
+		require.NotContains(t, e.Error(), "\nSchema:\n")
+	}
+}
+
+func TestSchemaErrorSchemaName(t *testing.T) {
+	doc, err := NewLoader().LoadFromData([]byte(`
+openapi: 3.0.3
+info: {title: Pizzas, version: "1"}
+paths: {}
+components:
+  schemas:
+    PizzaOrder:
+      type: object
+      properties:
+        size: {type: string, enum: [small, large]}
+        toppings:
+          type: array
+          items: {$ref: "#/components/schemas/Topping"}
+        crust: {title: Crust, type: object, properties: {thin: {type: boolean}}}
+    Topping:
+      type: object
+      properties:
+        name: {type: string}
+`))
+	require.NoError(t, err)
+	// As referenced by a request body
+	order := NewSchemaRef("#/components/schemas/PizzaOrder", doc.Components.Schemas.Value("PizzaOrder").Value)
+
+	err = order.VisitJSON(map[string]interface{}{"size": "medium"})
+	require.IsType(t, &SchemaError{}, err)
+	require.Equal(t, "PizzaOrder", err.(*SchemaError).SchemaName)
+	data, err := json.Marshal(err)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"schema":"PizzaOrder"`)
+
+	err = order.VisitJSON(map[string]interface{}{"toppings": []interface{}{map[string]interface{}{"name": 1.0}}})
+	require.IsType(t, &SchemaError{}, err)
+	require.Equal(t, "Topping", err.(*SchemaError).SchemaName)
+
+	err = order.VisitJSON(map[string]interface{}{"crust": map[string]interface{}{"thin": "yes"}})
+	require.IsType(t, &SchemaError{}, err)
+	require.Equal(t, "Crust", err.(*SchemaError).SchemaName)
+
+	// Schemas know not their component names
+	err = order.Value.VisitJSON(map[string]interface{}{"size": "medium"})
+	require.Equal(t, "", err.(*SchemaError).SchemaName)
+}
+
+func TestSchemaValidateNullSubschemas(t *testing.T) {
+	for data, expected := range map[string]string{
+		`{"oneOf": [null]}`:            "oneOf must not contain null",
+		`{"anyOf": [{}, null]}`:        "anyOf must not contain null",
+		`{"allOf": [null]}`:            "allOf must not contain null",
+		`{"properties": {"a": null}}`:  `property "a" must not be null`,
+		`{"properties": {"a": {}}}`:    "",
+		`{"allOf": [{"type": "int"}]}`: `unsupported 'type' value "int"`,
+	} {
+		var schema Schema
+		require.NoError(t, json.Unmarshal([]byte(data), &schema))
+		err := schema.Validate(context.Background())
+		if expected == "" {
+			require.NoError(t, err, data)
+		} else {
+			require.EqualError(t, err, expected, data)
+		}
+	}
+}
+
+func TestSchemaErrorMarshalJSONOrigin(t *testing.T) {
+	schema := NewAllOfSchema(NewObjectSchema().WithProperty("name", NewStringSchema()))
+	err := schema.VisitJSON(map[string]interface{}{"name": true}, SetSchemaErrorVerbosity(SchemaErrorFull))
+	require.Error(t, err)
+	require.NotNil(t, err.(*SchemaError).Origin)
+	require.Contains(t, err.Error(), "\nSchema:\n")
+
+	data, err := json.Marshal(err)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "Schema:")
+	require.JSONEq(t, `{"code": "schema_mismatch", "keyword": "allOf", "detail": "field must be set to string or not be present"}`, string(data))
+}
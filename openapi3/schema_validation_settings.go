@@ -13,11 +13,14 @@ type schemaValidationSettings struct {
 	asreq, asrep              bool // exclusive (XOR) fields
 	formatValidationEnabled   bool
 	patternValidationDisabled bool
+	maxContentSize            int
+	strictIntegerFormats      bool
 
 	onceSettingDefaults sync.Once
 	defaultsSet         func()
 
 	customizeMessageError func(err *SchemaError) string
+	errorVerbosity        SchemaErrorVerbosity
 }
 
 // FailFast returns schema validation errors quicker.
@@ -47,6 +50,29 @@ func DisablePatternValidation() SchemaValidationOption {
 	return func(s *schemaValidationSettings) { s.patternValidationDisabled = true }
 }
 
+// DefaultMaxContentSize is the size EnableContentValidation limits embedded content to by default.
+const DefaultMaxContentSize = 1 << 20
+
+// EnableContentValidation makes validation decode the content embedded in strings
+// whose schema has contentEncoding (e.g. "base64") or format "byte",
+// then validate it against contentSchema, once parsed as JSON when contentMediaType is
+// a JSON media type or contentSchema is set.
+// Content larger than maxSize bytes once decoded (DefaultMaxContentSize when not positive) is invalid.
+func EnableContentValidation(maxSize int) SchemaValidationOption {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxContentSize
+	}
+	return func(s *schemaValidationSettings) { s.maxContentSize = maxSize }
+}
+
+// EnableStrictIntegerFormats makes integers of format int64 invalid unless they are
+// between -(2^53-1) and 2^53-1, as JSON numbers beyond that are decoded to float64 values
+// (here and by most JSON parsers, such as JavaScript's) which are not the exact integers sent.
+// Integers of format int32 are invalid outside of their range regardless.
+func EnableStrictIntegerFormats() SchemaValidationOption {
+	return func(s *schemaValidationSettings) { s.strictIntegerFormats = true }
+}
+
 // DefaultsSet executes the given callback (once) IFF schema validation set default values.
 func DefaultsSet(f func()) SchemaValidationOption {
 	return func(s *schemaValidationSettings) { s.defaultsSet = f }
@@ -58,6 +84,12 @@ func SetSchemaErrorMessageCustomizer(f func(err *SchemaError) string) SchemaVali
 	return func(s *schemaValidationSettings) { s.customizeMessageError = f }
 }
 
+// SetSchemaErrorVerbosity sets the verbosity of the schema errors validation returns,
+// e.g. SchemaErrorShort for messages meant for end users.
+func SetSchemaErrorVerbosity(verbosity SchemaErrorVerbosity) SchemaValidationOption {
+	return func(s *schemaValidationSettings) { s.errorVerbosity = verbosity }
+}
+
 func newSchemaValidationSettings(opts ...SchemaValidationOption) *schemaValidationSettings {
 	settings := &schemaValidationSettings{}
 	for _, opt := range opts {
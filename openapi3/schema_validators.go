@@ -0,0 +1,145 @@
+package openapi3
+
+import (
+	"fmt"
+)
+
+// ExtensionValidate is the schema extension naming the validators, defined
+// with DefineSchemaValidator, that values of the schema must pass, e.g.:
+//
+//	type: string
+//	x-validate: iban
+//
+// Its value is the name of a validator or a list of names.
+const ExtensionValidate = "x-validate"
+
+// SchemaValidatorFunc checks a value against rules JSON Schema cannot express,
+// such as business rules, once it matches the rest of its schema.
+// The errors it returns, or each error of a returned MultiError, are reported
+// as SchemaErrors of SchemaField "x-validate" with the error as Origin.
+type SchemaValidatorFunc func(value interface{}) error
+
+var schemaValidators = make(map[string]SchemaValidatorFunc)
+
+// DefineSchemaValidator defines the validator the ExtensionValidate extension
+// refers to by name.
+// Like DefineStringFormat, it is meant to be called before loading documents,
+// e.g. in an init function.
+func DefineSchemaValidator(name string, validator SchemaValidatorFunc) {
+	schemaValidators[name] = validator
+}
+
+// WithValidator adds a validator values of the schema must pass,
+// wherever the schema is used (e.g. through references to a component).
+// Unlike those ExtensionValidate names, validators added this way are not part
+// of the document: they are not marshaled.
+func (schema *Schema) WithValidator(validator SchemaValidatorFunc) *Schema {
+	schema.validators = append(schema.validators, validator)
+	return schema
+}
+
+// AddComponentValidator adds validator to the component schema of the given name.
+// See Schema.WithValidator.
+func (doc *T) AddComponentValidator(name string, validator SchemaValidatorFunc) error {
+	ref := doc.Components.Schemas.Value(name)
+	if ref == nil {
+		return fmt.Errorf("schema %q not found in components", name)
+	}
+	if ref.Value == nil {
+		return foundUnresolvedRef(ref.Ref)
+	}
+	ref.Value.WithValidator(validator)
+	return nil
+}
+
+// validatorNames caches the names decoded from the extension,
+// along with the value they were decoded from to tell when they are stale.
+type validatorNames struct {
+	extension interface{}
+	names     []string
+}
+
+// extensionValidatorNames returns the names of the ExtensionValidate extension of schema.
+func (schema *Schema) extensionValidatorNames() ([]string, error) {
+	extension, ok := schema.Extensions[ExtensionValidate]
+	if !ok {
+		return nil, nil
+	}
+	if cached, _ := schema.validatorNames.Load().(*validatorNames); cached != nil && sameExtensionValue(cached.extension, extension) {
+		return cached.names, nil
+	}
+
+	var names []string
+	var name string
+	if _, err := schema.DecodeExtension(ExtensionValidate, &name); err == nil {
+		names = []string{name}
+	} else if _, err := schema.DecodeExtension(ExtensionValidate, &names); err != nil {
+		return nil, fmt.Errorf("invalid %s: must be a name or a list of names", ExtensionValidate)
+	}
+	// Concurrent validations may decode the names more than once, which is harmless
+	schema.validatorNames.Store(&validatorNames{extension: extension, names: names})
+	return names, nil
+}
+
+func (schema *Schema) validateValidatorNames() error {
+	names, err := schema.extensionValidatorNames()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if _, ok := schemaValidators[name]; !ok {
+			return fmt.Errorf("invalid %s: validator %q is not defined", ExtensionValidate, name)
+		}
+	}
+	return nil
+}
+
+// visitValidators runs the validators of schema on value.
+// Validators the extension names which are not defined, reported when validating
+// the document, are ignored.
+func (schema *Schema) visitValidators(settings *schemaValidationSettings, value interface{}) error {
+	validators := schema.validators
+	if _, ok := schema.Extensions[ExtensionValidate]; ok {
+		names, _ := schema.extensionValidatorNames()
+		validators = make([]SchemaValidatorFunc, 0, len(names)+len(schema.validators))
+		for _, name := range names {
+			if validator := schemaValidators[name]; validator != nil {
+				validators = append(validators, validator)
+			}
+		}
+		validators = append(validators, schema.validators...)
+	}
+
+	var me MultiError
+	for _, validator := range validators {
+		err := validator(value)
+		if err == nil {
+			continue
+		}
+		if settings.failfast {
+			return errSchema
+		}
+		errs, ok := err.(MultiError)
+		if !ok {
+			errs = MultiError{err}
+		}
+		for _, err := range errs {
+			schemaErr := &SchemaError{
+				Value:                 value,
+				Schema:                schema,
+				SchemaField:           ExtensionValidate,
+				Reason:                err.Error(),
+				Origin:                err,
+				customizeMessageError: settings.customizeMessageError,
+			}
+			if !settings.multiError {
+				return schemaErr
+			}
+			me = append(me, schemaErr)
+		}
+	}
+	if len(me) > 0 {
+		return me
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package openapi3
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaValidators(t *testing.T) {
+	errIBAN := errors.New("invalid IBAN checksum")
+	DefineSchemaValidator("iban", func(value interface{}) error {
+		if s, ok := value.(string); ok && !strings.HasSuffix(s, "00") {
+			return errIBAN
+		}
+		return nil
+	})
+	defer delete(schemaValidators, "iban")
+
+	doc, err := NewLoader().LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Payments, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    IBAN:
+      type: string
+      pattern: '^[A-Z]{2}[0-9]+$'
+      x-validate: iban
+    Transfer:
+      type: object
+      properties:
+        from: {$ref: '#/components/schemas/IBAN'}
+        to: {$ref: '#/components/schemas/IBAN'}
+        amount: {type: number}
+`))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+
+	require.NoError(t, doc.AddComponentValidator("Transfer", func(value interface{}) error {
+		transfer := value.(map[string]interface{})
+		if transfer["from"] == transfer["to"] {
+			return MultiError{errors.New("accounts must differ"), errors.New("nothing to transfer")}
+		}
+		return nil
+	}))
+	require.EqualError(t, doc.AddComponentValidator("Unknown", nil), `schema "Unknown" not found in components`)
+
+	schema := doc.Components.Schemas.Value("Transfer").Value
+	require.NoError(t, schema.VisitJSON(map[string]interface{}{"from": "FR00", "to": "DE00", "amount": 1.0}))
+
+	// Validators only run on values otherwise valid
+	err = schema.VisitJSON(map[string]interface{}{"from": "fr01"})
+	var schemaErr *SchemaError
+	require.True(t, errors.As(err, &schemaErr))
+	require.Equal(t, "pattern", schemaErr.SchemaField)
+
+	err = schema.VisitJSON(map[string]interface{}{"from": "FR01"})
+	require.True(t, errors.As(err, &schemaErr))
+	require.Equal(t, ExtensionValidate, schemaErr.SchemaField)
+	require.Equal(t, []string{"from"}, schemaErr.JSONPointer())
+	require.ErrorIs(t, err, errIBAN)
+
+	err = schema.VisitJSON(map[string]interface{}{"from": "FR00", "to": "FR00"}, MultiErrors())
+	var me MultiError
+	require.True(t, errors.As(err, &me))
+	require.Len(t, me, 2)
+	require.Contains(t, me[1].Error(), "nothing to transfer")
+
+	require.False(t, schema.IsMatching(map[string]interface{}{"from": "FR00", "to": "FR00"}))
+
+	for _, extension := range []string{`nope`, `[iban, nope]`, `{name: iban}`} {
+		doc, err := NewLoader().LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Payments, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    IBAN:
+      type: string
+      x-validate: ` + extension + `
+`))
+		require.NoError(t, err)
+		require.ErrorContains(t, doc.Validate(context.Background()), "invalid x-validate", extension)
+	}
+}
@@ -0,0 +1,138 @@
+package openapi3
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+)
+
+// SkipValue is returned by a WalkFunc so that Schema.Walk does not walk
+// the properties or items of the value it returned.
+var SkipValue = errors.New("skip this value")
+
+// RemoveValue is returned by a WalkFunc so that Schema.Walk removes the value
+// from the object or array holding it.
+var RemoveValue = errors.New("remove this value")
+
+// WalkFunc is called by Schema.Walk with the JSON pointer of each value,
+// the schema governing it (nil if none does) and the value itself.
+// The value it returns replaces the value, and is walked in turn.
+// A non-nil error other than SkipValue and RemoveValue stops the walk.
+type WalkFunc func(pointer string, schema *Schema, value interface{}) (interface{}, error)
+
+// Walk traverses value, as decoded from JSON, together with the schema governing each of
+// its parts, calling fn on the way down: on value then on its properties, by name, and items.
+// It returns value with the replacements fn made, which maps and slices hold in place.
+// Redaction, filling defaults, coercing types or stripping readOnly properties
+// may be built on it.
+//
+// The schema of a property is the one of the first of the schema, its allOf subschemas,
+// then the anyOf and oneOf subschemas value is valid against, that declares it,
+// or else additionalProperties. Items are governed likewise.
+func (schema *Schema) Walk(value interface{}, fn WalkFunc) (interface{}, error) {
+	value, err := walkValue(schema, value, "", fn)
+	if err == RemoveValue {
+		return nil, nil
+	}
+	return value, err
+}
+
+func walkValue(schema *Schema, value interface{}, pointer string, fn WalkFunc) (interface{}, error) {
+	value, err := fn(pointer, schema, value)
+	switch err {
+	case nil:
+	case SkipValue:
+		return value, nil
+	default:
+		return value, err
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			propertySchema := walkedPropertySchema(schema, value, name)
+			v, err := walkValue(propertySchema, value[name], pointer+jsonPointer(name), fn)
+			if err == RemoveValue {
+				delete(value, name)
+				continue
+			}
+			if err != nil {
+				return value, err
+			}
+			value[name] = v
+		}
+		return value, nil
+	case []interface{}:
+		itemSchema := walkedItemSchema(schema, value)
+		items := value[:0]
+		for i, item := range value {
+			v, err := walkValue(itemSchema, item, pointer+"/"+strconv.Itoa(i), fn)
+			if err == RemoveValue {
+				continue
+			}
+			if err != nil {
+				return value, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	}
+	return value, nil
+}
+
+func walkedPropertySchema(schema *Schema, object map[string]interface{}, name string) *Schema {
+	if schema == nil {
+		return nil
+	}
+	if property := schema.Properties.Value(name); property != nil {
+		return property.Value
+	}
+	for _, sub := range walkedSubschemas(schema, object) {
+		if property := walkedPropertySchema(sub, object, name); property != nil {
+			return property
+		}
+	}
+	if additionalProperties := schema.AdditionalProperties; additionalProperties != nil {
+		return additionalProperties.Value
+	}
+	return nil
+}
+
+func walkedItemSchema(schema *Schema, array []interface{}) *Schema {
+	if schema == nil {
+		return nil
+	}
+	if items := schema.Items; items != nil {
+		return items.Value
+	}
+	for _, sub := range walkedSubschemas(schema, array) {
+		if items := walkedItemSchema(sub, array); items != nil {
+			return items
+		}
+	}
+	return nil
+}
+
+// walkedSubschemas returns the allOf subschemas of schema, then its anyOf and oneOf
+// subschemas value is valid against.
+func walkedSubschemas(schema *Schema, value interface{}) []*Schema {
+	var subschemas []*Schema
+	for _, ref := range schema.AllOf {
+		if ref != nil && ref.Value != nil {
+			subschemas = append(subschemas, ref.Value)
+		}
+	}
+	for _, refs := range []SchemaRefs{schema.AnyOf, schema.OneOf} {
+		for _, ref := range refs {
+			if ref != nil && ref.Value != nil && ref.Value.VisitJSON(value) == nil {
+				subschemas = append(subschemas, ref.Value)
+			}
+		}
+	}
+	return subschemas
+}
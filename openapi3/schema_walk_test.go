@@ -0,0 +1,124 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaWalk(t *testing.T) {
+	var schema Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer", "readOnly": true},
+			"password": {"type": "string", "format": "password"},
+			"age": {"type": "integer"},
+			"status": {"type": "string", "default": "active"},
+			"owners": {
+				"type": "array",
+				"items": {
+					"allOf": [
+						{"type": "object", "properties": {"name": {"type": "string"}}},
+						{"type": "object", "properties": {"token": {"type": "string", "writeOnly": true}}}
+					]
+				}
+			},
+			"contact": {
+				"oneOf": [
+					{"type": "object", "required": ["email"], "properties": {"email": {"type": "string", "format": "password"}}},
+					{"type": "object", "required": ["phone"], "properties": {"phone": {"type": "string"}}}
+				]
+			}
+		},
+		"additionalProperties": {"type": "string", "format": "password"}
+	}`), &schema))
+
+	var value interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": 1,
+		"password": "hunter2",
+		"age": "42",
+		"owners": [{"name": "a", "token": "t"}, {"name": "b"}],
+		"contact": {"email": "a@example.com"},
+		"a/b": "secret"
+	}`), &value))
+
+	var pointers []string
+	value, err := schema.Walk(value, func(pointer string, schema *Schema, value interface{}) (interface{}, error) {
+		pointers = append(pointers, pointer)
+		switch {
+		case schema == nil:
+		case schema.ReadOnly, schema.WriteOnly:
+			return nil, RemoveValue
+		case schema.Format == "password":
+			return "***", nil
+		case schema.Type == "integer":
+			if s, ok := value.(string); ok {
+				if n, err := strconv.Atoi(s); err == nil {
+					return float64(n), nil
+				}
+			}
+		case schema.Type == "object":
+			if object, ok := value.(map[string]interface{}); ok {
+				for name, property := range schema.Properties.Map() {
+					if _, ok := object[name]; !ok && property.Value.Default != nil {
+						object[name] = property.Value.Default
+					}
+				}
+			}
+		}
+		return value, nil
+	})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"password": "***",
+		"age": 42,
+		"status": "active",
+		"owners": [{"name": "a"}, {"name": "b"}],
+		"contact": {"email": "***"},
+		"a/b": "***"
+	}`, string(data))
+	require.Equal(t, []string{
+		"",
+		"/a~1b",
+		"/age",
+		"/contact",
+		"/contact/email",
+		"/id",
+		"/owners",
+		"/owners/0",
+		"/owners/0/name",
+		"/owners/0/token",
+		"/owners/1",
+		"/owners/1/name",
+		"/password",
+		"/status",
+	}, pointers)
+
+	pointers = nil
+	_, err = schema.Walk(map[string]interface{}{"owners": []interface{}{map[string]interface{}{}}}, func(pointer string, schema *Schema, value interface{}) (interface{}, error) {
+		pointers = append(pointers, pointer)
+		if pointer == "/owners" {
+			return value, SkipValue
+		}
+		return value, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "/owners"}, pointers)
+
+	failure := errors.New("failure")
+	_, err = schema.Walk(map[string]interface{}{"age": 1}, func(pointer string, schema *Schema, value interface{}) (interface{}, error) {
+		if pointer == "/age" {
+			return value, failure
+		}
+		return value, nil
+	})
+	require.Equal(t, failure, err)
+}
@@ -75,6 +75,11 @@ func (ss *SecurityScheme) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(ss)
 }
 
+// MarshalYAML returns the YAML encoding of SecurityScheme.
+func (ss *SecurityScheme) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(ss)
+}
+
 // UnmarshalJSON sets SecurityScheme to a copy of data.
 func (ss *SecurityScheme) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, ss)
@@ -201,6 +206,11 @@ func (flows *OAuthFlows) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(flows)
 }
 
+// MarshalYAML returns the YAML encoding of OAuthFlows.
+func (flows *OAuthFlows) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(flows)
+}
+
 // UnmarshalJSON sets OAuthFlows to a copy of data.
 func (flows *OAuthFlows) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, flows)
@@ -249,6 +259,11 @@ func (flow *OAuthFlow) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(flow)
 }
 
+// MarshalYAML returns the YAML encoding of OAuthFlow.
+func (flow *OAuthFlow) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(flow)
+}
+
 // UnmarshalJSON sets OAuthFlow to a copy of data.
 func (flow *OAuthFlow) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, flow)
@@ -88,6 +88,11 @@ func (server *Server) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(server)
 }
 
+// MarshalYAML returns the YAML encoding of Server.
+func (server *Server) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(server)
+}
+
 // UnmarshalJSON sets Server to a copy of data.
 func (server *Server) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, server)
@@ -112,6 +117,59 @@ func (server Server) ParameterNames() ([]string, error) {
 	return params, nil
 }
 
+// ResolveURL returns the URL of the server with its variables substituted
+// with the values of vars or else with their default values.
+// It returns an error if vars sets an unknown variable or a value that is not
+// one of the enum values of its variable.
+func (server *Server) ResolveURL(vars map[string]string) (string, error) {
+	names, err := server.ParameterNames()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range componentNames(vars) {
+		if _, ok := server.Variables[name]; !ok {
+			return "", fmt.Errorf("server %q has no variable %q", server.URL, name)
+		}
+	}
+
+	uri := server.URL
+	for _, name := range names {
+		value, ok := vars[name]
+		svar := server.Variables[name]
+		if !ok {
+			if svar == nil {
+				return "", fmt.Errorf("server %q has undeclared variable %q", server.URL, name)
+			}
+			value = svar.Default
+		}
+		if err := svar.validateValue(value); err != nil {
+			return "", fmt.Errorf("invalid value of server variable %q: %w", name, err)
+		}
+		uri = strings.Replace(uri, "{"+name+"}", value, 1)
+	}
+	return uri, nil
+}
+
+// ParseURL matches rawURL, without query string, with the URL of the server.
+// It returns the values of the variables of the server in rawURL
+// and the path that remains after the URL of the server.
+// Values are not checked against the enum values of the variables.
+func (server *Server) ParseURL(rawURL string) (vars map[string]string, remainingPath string, ok bool) {
+	values, remainingPath, ok := server.MatchRawURL(rawURL)
+	if !ok {
+		return nil, "", false
+	}
+	names, err := server.ParameterNames()
+	if err != nil || len(names) != len(values) {
+		return nil, "", false
+	}
+	vars = make(map[string]string, len(names))
+	for i, name := range names {
+		vars[name] = values[i]
+	}
+	return vars, remainingPath, true
+}
+
 func (server Server) MatchRawURL(input string) ([]string, string, bool) {
 	pattern := server.URL
 	var params []string
@@ -213,11 +271,29 @@ func (serverVariable *ServerVariable) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(serverVariable)
 }
 
+// MarshalYAML returns the YAML encoding of ServerVariable.
+func (serverVariable *ServerVariable) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(serverVariable)
+}
+
 // UnmarshalJSON sets ServerVariable to a copy of data.
 func (serverVariable *ServerVariable) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, serverVariable)
 }
 
+// validateValue returns an error if value is not one of the enum values of the variable, if any.
+func (serverVariable *ServerVariable) validateValue(value string) error {
+	if serverVariable == nil || len(serverVariable.Enum) == 0 {
+		return nil
+	}
+	for _, v := range serverVariable.Enum {
+		if v == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("value %q is not one of the allowed values %q", value, serverVariable.Enum)
+}
+
 // Validate returns an error if ServerVariable does not comply with the OpenAPI spec.
 func (serverVariable *ServerVariable) Validate(ctx context.Context, opts ...ValidationOption) error {
 	// ctx = WithValidationOptions(ctx, opts...)
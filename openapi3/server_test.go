@@ -201,3 +201,38 @@ func TestServersBasePath(t *testing.T) {
 		})
 	}
 }
+
+func TestServerResolveURL(t *testing.T) {
+	server := &Server{
+		URL: "{scheme}://{region}.example.com/{version}",
+		Variables: map[string]*ServerVariable{
+			"scheme":  {Default: "https", Enum: []string{"http", "https"}},
+			"region":  {Default: "eu"},
+			"version": {Default: "v1", Enum: []string{"v1", "v2"}},
+		},
+	}
+
+	uri, err := server.ResolveURL(nil)
+	require.NoError(t, err)
+	require.Equal(t, "https://eu.example.com/v1", uri)
+
+	uri, err = server.ResolveURL(map[string]string{"region": "us", "version": "v2"})
+	require.NoError(t, err)
+	require.Equal(t, "https://us.example.com/v2", uri)
+
+	_, err = server.ResolveURL(map[string]string{"version": "v3"})
+	require.EqualError(t, err, `invalid value of server variable "version": value "v3" is not one of the allowed values ["v1" "v2"]`)
+	_, err = server.ResolveURL(map[string]string{"port": "80"})
+	require.EqualError(t, err, `server "{scheme}://{region}.example.com/{version}" has no variable "port"`)
+
+	vars, remaining, ok := server.ParseURL("http://us.example.com/v2/pets/1")
+	require.True(t, ok)
+	require.Equal(t, map[string]string{"scheme": "http", "region": "us", "version": "v2"}, vars)
+	require.Equal(t, "/pets/1", remaining)
+	uri, err = server.ResolveURL(vars)
+	require.NoError(t, err)
+	require.Equal(t, "http://us.example.com/v2", uri)
+
+	_, _, ok = server.ParseURL("https://example.org/v1")
+	require.False(t, ok)
+}
@@ -46,6 +46,11 @@ func (t *Tag) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(t)
 }
 
+// MarshalYAML returns the YAML encoding of Tag.
+func (t *Tag) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(t)
+}
+
 // UnmarshalJSON sets Tag to a copy of data.
 func (t *Tag) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, t)
@@ -12,6 +12,7 @@ type ValidationOptions struct {
 	schemaDefaultsValidationDisabled                 bool
 	schemaFormatValidationEnabled                    bool
 	schemaPatternValidationDisabled                  bool
+	uniquenessValidationEnabled                      bool
 }
 
 type validationOptionsKey struct{}
@@ -79,6 +80,24 @@ func DisableExamplesValidation() ValidationOption {
 	}
 }
 
+// EnableUniquenessValidation makes Validate report every operationId shared by operations,
+// and every parameter defined twice (same name and location) by a path or an operation,
+// each with both of its locations.
+// By default, only the first operationId shared by operations is reported.
+func EnableUniquenessValidation() ValidationOption {
+	return func(options *ValidationOptions) {
+		options.uniquenessValidationEnabled = true
+	}
+}
+
+// DisableUniquenessValidation does the opposite of EnableUniquenessValidation.
+// By default, only the first operationId shared by operations is reported.
+func DisableUniquenessValidation() ValidationOption {
+	return func(options *ValidationOptions) {
+		options.uniquenessValidationEnabled = false
+	}
+}
+
 // WithValidationOptions allows adding validation options to a context object that can be used when validationg any OpenAPI type.
 func WithValidationOptions(ctx context.Context, opts ...ValidationOption) context.Context {
 	if len(opts) == 0 {
@@ -0,0 +1,69 @@
+package openapi3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// extensionWebhooks is the extension OpenAPI 3.0 documents describe webhooks with,
+// as the webhooks field was only introduced by OpenAPI 3.1.
+const extensionWebhooks = "x-webhooks"
+
+// Webhooks maps the names of the webhooks an API may call to the requests it sends.
+// See https://spec.openapis.org/oas/v3.1.0#fixed-fields
+//
+// When decoding a document, webhooks are read from the "webhooks" field
+// or else from the "x-webhooks" extension.
+// OpenAPI 3.0 documents encode them with the "x-webhooks" extension.
+type Webhooks map[string]*PathItem
+
+// Validate returns an error if Webhooks does not comply with the OpenAPI spec.
+func (webhooks Webhooks) Validate(ctx context.Context, opts ...ValidationOption) error {
+	ctx = WithValidationOptions(ctx, opts...)
+
+	for _, name := range componentNames(webhooks) {
+		pathItem := webhooks[name]
+		if pathItem == nil {
+			return fmt.Errorf("webhook %q: %w", name, errors.New("value MUST be an object"))
+		}
+		if err := pathItem.Validate(ctx); err != nil {
+			return fmt.Errorf("webhook %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// decodeWebhooksExtension moves webhooks described with the "x-webhooks" extension to doc.Webhooks.
+func (doc *T) decodeWebhooksExtension() error {
+	if doc.Webhooks != nil {
+		return nil
+	}
+	var webhooks Webhooks
+	ok, err := doc.DecodeExtension(extensionWebhooks, &webhooks)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", extensionWebhooks, err)
+	}
+	if ok {
+		doc.Webhooks = webhooks
+		delete(doc.Extensions, extensionWebhooks)
+	}
+	return nil
+}
+
+// withWebhooksExtension returns doc, or a shallow copy of doc describing its webhooks
+// with the "x-webhooks" extension if it is an OpenAPI 3.0 document, which has no webhooks field.
+func (doc *T) withWebhooksExtension() *T {
+	if doc.Webhooks == nil || !strings.HasPrefix(doc.OpenAPI, "3.0") {
+		return doc
+	}
+	c := *doc
+	c.Extensions = make(map[string]interface{}, len(doc.Extensions)+1)
+	for k, v := range doc.Extensions {
+		c.Extensions[k] = v
+	}
+	c.Extensions[extensionWebhooks] = doc.Webhooks
+	c.Webhooks = nil
+	return &c
+}
@@ -23,6 +23,11 @@ func (xml *XML) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(xml)
 }
 
+// MarshalYAML returns the YAML encoding of XML.
+func (xml *XML) MarshalYAML() (interface{}, error) {
+	return marshalStrictStructYAML(xml)
+}
+
 // UnmarshalJSON sets XML to a copy of data.
 func (xml *XML) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalStrictStruct(data, xml)
@@ -0,0 +1,12 @@
+// Package openapi31conv converts OpenAPI 3.0 documents to OpenAPI 3.1 and back.
+//
+// As openapi3 only models OpenAPI 3.0, OpenAPI 3.1 documents are handled as JSON.
+// The following differences are converted, in both directions:
+//   - nullable schemas and type arrays including "null"
+//   - boolean and numeric exclusiveMinimum and exclusiveMaximum
+//   - the schema example and examples keywords
+//   - the "x-webhooks" extension and webhooks
+//   - single value enums and const (from 3.1 only)
+//
+// Converting a 3.0 document to 3.1 and back yields an equivalent document.
+package openapi31conv
@@ -0,0 +1,259 @@
+package openapi31conv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/invopop/yaml"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	// VersionV30 is the version set by FromV31 and Downgrade.
+	VersionV30 = "3.0.3"
+	// VersionV31 is the version set by ToV31 and Upgrade.
+	VersionV31 = "3.1.0"
+
+	extWebhooks = "x-webhooks"
+)
+
+// ToV31 converts an OpenAPI 3.0 document to an OpenAPI 3.1 JSON document.
+func ToV31(doc *openapi3.T) ([]byte, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return Upgrade(data)
+}
+
+// FromV31 converts an OpenAPI 3.1 JSON or YAML document to OpenAPI 3.0.
+// References are resolved as by openapi3.Loader.LoadFromData.
+func FromV31(data []byte) (*openapi3.T, error) {
+	data, err := Downgrade(data)
+	if err != nil {
+		return nil, err
+	}
+	return openapi3.NewLoader().LoadFromData(data)
+}
+
+// Upgrade converts an OpenAPI 3.0 JSON or YAML document to an OpenAPI 3.1 JSON document.
+func Upgrade(data []byte) ([]byte, error) {
+	doc, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if v, _ := doc["openapi"].(string); !strings.HasPrefix(v, "3.0") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, expected 3.0", v)
+	}
+	doc["openapi"] = VersionV31
+	if webhooks, ok := doc[extWebhooks]; ok {
+		delete(doc, extWebhooks)
+		doc["webhooks"] = webhooks
+	}
+	walk(doc, false, schemaToV31)
+	return json.Marshal(doc)
+}
+
+// Downgrade converts an OpenAPI 3.1 JSON or YAML document to an OpenAPI 3.0 JSON document.
+func Downgrade(data []byte) ([]byte, error) {
+	doc, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if v, _ := doc["openapi"].(string); !strings.HasPrefix(v, "3.1") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, expected 3.1", v)
+	}
+	doc["openapi"] = VersionV30
+	if webhooks, ok := doc["webhooks"]; ok {
+		delete(doc, "webhooks")
+		doc[extWebhooks] = webhooks
+	}
+	walk(doc, false, schemaFromV31)
+	return json.Marshal(doc)
+}
+
+func decode(data []byte) (map[string]interface{}, error) {
+	if !json.Valid(data) {
+		var err error
+		if data, err = yaml.YAMLToJSON(data); err != nil {
+			return nil, err
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// walk calls convert on every schema found in node, children first.
+// Values that are data rather than OpenAPI objects (examples, defaults, enums...) are skipped.
+func walk(node interface{}, isSchema bool, convert func(map[string]interface{})) {
+	switch node := node.(type) {
+	case []interface{}:
+		for _, v := range node {
+			walk(v, isSchema, convert)
+		}
+	case map[string]interface{}:
+		if !isSchema {
+			for k, v := range node {
+				switch k {
+				case "example", "examples", "value":
+				case "schema":
+					walk(v, true, convert)
+				case "schemas":
+					walkSchemaMap(v, convert)
+				default:
+					walk(v, false, convert)
+				}
+			}
+			return
+		}
+		for k, v := range node {
+			switch k {
+			case "items", "additionalProperties", "not", "if", "then", "else",
+				"contains", "propertyNames", "unevaluatedItems", "unevaluatedProperties",
+				"allOf", "anyOf", "oneOf", "prefixItems":
+				walk(v, true, convert)
+			case "properties", "patternProperties", "$defs", "dependentSchemas":
+				walkSchemaMap(v, convert)
+			}
+		}
+		convert(node)
+	}
+}
+
+func walkSchemaMap(node interface{}, convert func(map[string]interface{})) {
+	if m, ok := node.(map[string]interface{}); ok {
+		for _, v := range m {
+			walk(v, true, convert)
+		}
+	}
+}
+
+func schemaToV31(schema map[string]interface{}) {
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		if typ, ok := schema["type"].(string); ok {
+			schema["type"] = []interface{}{typ, "null"}
+		}
+		if enum, ok := schema["enum"].([]interface{}); ok && len(removeNull(enum)) == len(enum) {
+			schema["enum"] = append(enum, nil)
+		}
+	}
+	delete(schema, "nullable")
+
+	exclusiveToV31(schema, "exclusiveMinimum", "minimum")
+	exclusiveToV31(schema, "exclusiveMaximum", "maximum")
+
+	if example, ok := schema["example"]; ok {
+		delete(schema, "example")
+		schema["examples"] = []interface{}{example}
+	}
+}
+
+func exclusiveToV31(schema map[string]interface{}, exclusiveKey, boundKey string) {
+	exclusive, ok := schema[exclusiveKey].(bool)
+	if !ok {
+		return
+	}
+	delete(schema, exclusiveKey)
+	if bound, ok := schema[boundKey]; ok && exclusive {
+		delete(schema, boundKey)
+		schema[exclusiveKey] = bound
+	}
+}
+
+func schemaFromV31(schema map[string]interface{}) {
+	if types, ok := schema["type"].([]interface{}); ok {
+		var nonNull []interface{}
+		nullable := false
+		for _, typ := range types {
+			if typ == "null" {
+				nullable = true
+			} else {
+				nonNull = append(nonNull, typ)
+			}
+		}
+		switch len(nonNull) {
+		case 0:
+			delete(schema, "type")
+		case 1:
+			schema["type"] = nonNull[0]
+		default:
+			// OpenAPI 3.0 has no type unions
+			delete(schema, "type")
+			anyOf := make([]interface{}, 0, len(nonNull))
+			for _, typ := range nonNull {
+				anyOf = append(anyOf, map[string]interface{}{"type": typ})
+			}
+			schema["anyOf"] = anyOf
+		}
+		if nullable {
+			schema["nullable"] = true
+			if enum, ok := schema["enum"].([]interface{}); ok {
+				schema["enum"] = removeNull(enum)
+			}
+		}
+	}
+
+	exclusiveFromV31(schema, "exclusiveMinimum", "minimum")
+	exclusiveFromV31(schema, "exclusiveMaximum", "maximum")
+
+	if examples, ok := schema["examples"].([]interface{}); ok {
+		delete(schema, "examples")
+		if len(examples) != 0 {
+			schema["example"] = examples[0]
+		}
+	}
+
+	if value, ok := schema["const"]; ok {
+		delete(schema, "const")
+		schema["enum"] = []interface{}{value}
+	}
+}
+
+func exclusiveFromV31(schema map[string]interface{}, exclusiveKey, boundKey string) {
+	bound, ok := schema[exclusiveKey].(json.Number)
+	if !ok {
+		return
+	}
+	// The exclusive bound replaces the inclusive one, as it is at least as strict
+	// unless the inclusive one is stricter.
+	if inclusive, ok := schema[boundKey].(json.Number); ok && stricter(boundKey, inclusive, bound) {
+		delete(schema, exclusiveKey)
+		return
+	}
+	schema[boundKey] = bound
+	schema[exclusiveKey] = true
+}
+
+// stricter reports whether the inclusive bound excludes the exclusive one.
+func stricter(boundKey string, inclusive, exclusive json.Number) bool {
+	i, err := inclusive.Float64()
+	if err != nil {
+		return false
+	}
+	e, err := exclusive.Float64()
+	if err != nil {
+		return false
+	}
+	if boundKey == "minimum" {
+		return i > e
+	}
+	return i < e
+}
+
+func removeNull(values []interface{}) []interface{} {
+	result := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		if v != nil {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,157 @@
+package openapi31conv
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const specV30 = `
+openapi: 3.0.3
+info: {title: Example, version: '1.0'}
+paths:
+  /pets:
+    get:
+      parameters:
+      - name: limit
+        in: query
+        schema: {type: integer, minimum: 0, exclusiveMinimum: true, maximum: 100}
+        example: 10
+      responses:
+        default:
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+              example: {name: Fido, tag: null}
+x-webhooks:
+  newPet:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        '200': {description: OK}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string, example: Fido}
+        tag: {type: string, nullable: true}
+        kind: {type: string, enum: [cat, dog], nullable: true}
+        weight: {type: number, maximum: 50, exclusiveMaximum: true}
+`
+
+const specV31 = `{
+  "openapi": "3.1.0",
+  "info": {"title": "Example", "version": "1.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [{
+          "name": "limit",
+          "in": "query",
+          "schema": {"type": "integer", "exclusiveMinimum": 0, "maximum": 100},
+          "example": 10
+        }],
+        "responses": {
+          "default": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {"$ref": "#/components/schemas/Pet"},
+                "example": {"name": "Fido", "tag": null}
+              }
+            }
+          }
+        }
+      }
+    }
+  },
+  "webhooks": {
+    "newPet": {
+      "post": {
+        "requestBody": {
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}
+        },
+        "responses": {"200": {"description": "OK"}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string", "examples": ["Fido"]},
+          "tag": {"type": ["string", "null"]},
+          "kind": {"type": ["string", "null"], "enum": ["cat", "dog", null]},
+          "weight": {"type": "number", "exclusiveMaximum": 50}
+        }
+      }
+    }
+  }
+}`
+
+func TestUpgrade(t *testing.T) {
+	data, err := Upgrade([]byte(specV30))
+	require.NoError(t, err)
+	require.JSONEq(t, specV31, string(data))
+}
+
+func TestDowngrade(t *testing.T) {
+	data, err := Downgrade([]byte(specV31))
+	require.NoError(t, err)
+	expected, err := decode([]byte(specV30))
+	require.NoError(t, err)
+	expectedData, err := json.Marshal(expected)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedData), string(data))
+}
+
+func TestRoundTrip(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(specV30))
+	require.NoError(t, err)
+
+	data, err := ToV31(doc)
+	require.NoError(t, err)
+	doc2, err := FromV31(data)
+	require.NoError(t, err)
+	err = doc2.Validate(context.Background())
+	require.NoError(t, err)
+
+	expected, err := json.Marshal(doc)
+	require.NoError(t, err)
+	actual, err := json.Marshal(doc2)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expected), string(actual))
+}
+
+func TestDowngradeTypeUnions(t *testing.T) {
+	data, err := Downgrade([]byte(`{
+  "openapi": "3.1.0",
+  "components": {"schemas": {
+    "A": {"type": ["string", "integer", "null"]},
+    "B": {"const": "b", "examples": []},
+    "C": {"minimum": 5, "exclusiveMinimum": 1}
+  }}
+}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "openapi": "3.0.3",
+  "components": {"schemas": {
+    "A": {"anyOf": [{"type": "string"}, {"type": "integer"}], "nullable": true},
+    "B": {"enum": ["b"]},
+    "C": {"minimum": 5}
+  }}
+}`, string(data))
+
+	_, err = Downgrade([]byte(`{"openapi": "3.0.3"}`))
+	require.EqualError(t, err, `unsupported OpenAPI version "3.0.3", expected 3.1`)
+}
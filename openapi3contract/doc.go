@@ -0,0 +1,9 @@
+// Package openapi3contract verifies that an HTTP server fulfills the contract of an OpenAPIv3 document.
+//
+// A Verifier sends requests to a server, running at a base URL or as an http.Handler,
+// and validates its responses with openapi3filter. Requests are generated from
+// the examples and schemas of each operation (see openapi3fuzz) and can be
+// complemented with user-supplied ones, such as requests for existing resources.
+//
+// This is provider verification in the style of Pact, with the document as the contract.
+package openapi3contract
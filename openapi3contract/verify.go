@@ -0,0 +1,337 @@
+package openapi3contract
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/openapi3fuzz"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// Stage tells which step of the verification of a request failed.
+type Stage string
+
+const (
+	// StageSend is for requests that could not be sent or got no response.
+	StageSend Stage = "send"
+	// StageRoute is for user-supplied requests that match no operation.
+	StageRoute Stage = "route"
+	// StageResponse is for responses that do not conform to their operation.
+	StageResponse Stage = "response"
+)
+
+// Violation is a request whose verification failed.
+type Violation struct {
+	Method string
+	URL    string
+	// Status is the status code of the response, if any.
+	Status int
+	Stage  Stage
+	Err    error
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("%s %s (%d): %s: %v", v.Method, v.URL, v.Status, v.Stage, v.Err)
+}
+
+func (v *Violation) Unwrap() error {
+	return v.Err
+}
+
+// OperationReport sums up the verification of the requests sent to an operation.
+type OperationReport struct {
+	Method      string
+	Path        string
+	OperationID string
+	// Requests is the count of requests sent to the operation.
+	Requests   int
+	Violations []*Violation
+}
+
+// Report is the result of Verify.
+type Report struct {
+	// Operations holds all operations of the document, sorted by path then method.
+	Operations []*OperationReport
+	// Unmatched holds the violations of user-supplied requests that were not routed to an operation.
+	Unmatched []*Violation
+}
+
+// Valid reports whether all responses conformed to the document.
+func (report *Report) Valid() bool {
+	return len(report.Violations()) == 0
+}
+
+// Violations returns all violations, unmatched requests first.
+func (report *Report) Violations() []*Violation {
+	violations := append([]*Violation(nil), report.Unmatched...)
+	for _, operation := range report.Operations {
+		violations = append(violations, operation.Violations...)
+	}
+	return violations
+}
+
+// Untested returns the operations no request was sent to.
+func (report *Report) Untested() []*OperationReport {
+	var untested []*OperationReport
+	for _, operation := range report.Operations {
+		if operation.Requests == 0 {
+			untested = append(untested, operation)
+		}
+	}
+	return untested
+}
+
+// Option allows tweaking a Verifier
+type Option func(*Verifier)
+
+// WithBaseURL sets the URL of the server, e.g. "http://localhost:8080/api".
+// It defaults to the URL of the first server of the document, with the default values of its variables.
+func WithBaseURL(baseURL string) Option {
+	return func(v *Verifier) { v.baseURL = baseURL }
+}
+
+// WithHandler verifies handler in process instead of sending requests over the network.
+func WithHandler(handler http.Handler) Option {
+	return func(v *Verifier) { v.handler = handler }
+}
+
+// WithClient sets the client sending requests. It defaults to http.DefaultClient.
+func WithClient(client *http.Client) Option {
+	return func(v *Verifier) { v.client = client }
+}
+
+// WithRequests adds user-supplied requests, routed to their operation by their URL.
+// Requests with a body should have a GetBody function, as set by http.NewRequest.
+func WithRequests(requests ...*http.Request) Option {
+	return func(v *Verifier) { v.requests = append(v.requests, requests...) }
+}
+
+// WithoutGeneratedRequests only sends the user-supplied requests.
+func WithoutGeneratedRequests() Option {
+	return func(v *Verifier) { v.skipGenerated = true }
+}
+
+// WithRequestEditor sets a function called on each request before it is sent,
+// e.g. to set credentials.
+func WithRequestEditor(edit func(*http.Request) error) Option {
+	return func(v *Verifier) { v.edit = edit }
+}
+
+// WithValidationOptions sets the options of response validation.
+// By default, all errors of a response are reported (MultiError)
+// and undocumented status codes are violations (IncludeResponseStatus).
+func WithValidationOptions(options *openapi3filter.Options) Option {
+	return func(v *Verifier) { v.options = options }
+}
+
+// Verifier sends requests to a server and validates its responses.
+type Verifier struct {
+	doc           *openapi3.T
+	router        routers.Router
+	baseURL       string
+	handler       http.Handler
+	client        *http.Client
+	requests      []*http.Request
+	skipGenerated bool
+	edit          func(*http.Request) error
+	options       *openapi3filter.Options
+}
+
+// NewVerifier returns a Verifier of the operations of doc, which should be valid.
+//
+// Requests are routed relative to the base URL, so the servers of path items are ignored.
+func NewVerifier(doc *openapi3.T, opts ...Option) (*Verifier, error) {
+	v := &Verifier{doc: doc}
+	for _, opt := range opts {
+		opt(v)
+	}
+	if v.baseURL == "" && len(doc.Servers) != 0 {
+		baseURL, err := doc.Servers[0].ResolveURL(nil)
+		if err != nil {
+			return nil, err
+		}
+		v.baseURL = baseURL
+	}
+	if v.baseURL == "" {
+		v.baseURL = "http://localhost"
+	}
+	v.baseURL = strings.TrimSuffix(v.baseURL, "/")
+	if v.client == nil {
+		v.client = http.DefaultClient
+	}
+	if v.options == nil {
+		v.options = &openapi3filter.Options{
+			MultiError:            true,
+			IncludeResponseStatus: true,
+			AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+		}
+	}
+
+	routed := *doc
+	routed.Servers = openapi3.Servers{{URL: v.baseURL}}
+	routed.Paths = openapi3.NewPaths()
+	for path, pathItem := range doc.Paths.Map() {
+		if len(pathItem.Servers) != 0 {
+			item := *pathItem
+			item.Servers = nil
+			pathItem = &item
+		}
+		routed.Paths.Set(path, pathItem)
+	}
+	router, err := gorillamux.NewRouter(&routed)
+	if err != nil {
+		return nil, err
+	}
+	v.router = router
+	return v, nil
+}
+
+// Verify sends a valid request generated for each operation, then the user-supplied requests,
+// and validates the responses.
+func (v *Verifier) Verify(ctx context.Context) (*Report, error) {
+	requests := v.requests
+	if !v.skipGenerated {
+		cases, err := openapi3fuzz.Generate(v.doc,
+			openapi3fuzz.WithBaseURL(v.baseURL),
+			openapi3fuzz.WithKinds(openapi3fuzz.Valid))
+		if err != nil {
+			return nil, err
+		}
+		generated := make([]*http.Request, 0, len(cases)+len(requests))
+		for _, c := range cases {
+			generated = append(generated, c.Request)
+		}
+		requests = append(generated, requests...)
+	}
+
+	report := &Report{}
+	operations := make(map[string]*OperationReport)
+	v.doc.WalkOperations(func(path, method string, o *openapi3.Operation) {
+		operation := &OperationReport{
+			Method:      method,
+			Path:        path,
+			OperationID: o.OperationID,
+		}
+		operations[method+" "+path] = operation
+		report.Operations = append(report.Operations, operation)
+	})
+
+	for _, request := range requests {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		req, err := cloneRequest(ctx, request)
+		violation := &Violation{Method: request.Method, URL: request.URL.String()}
+		if err != nil {
+			violation.Stage, violation.Err = StageSend, err
+			report.Unmatched = append(report.Unmatched, violation)
+			continue
+		}
+		route, pathParams, err := v.router.FindRoute(req)
+		if err != nil {
+			violation.Stage, violation.Err = StageRoute, err
+			report.Unmatched = append(report.Unmatched, violation)
+			continue
+		}
+		operation := operations[route.Method+" "+route.Path]
+		operation.Requests++
+
+		if v.edit != nil {
+			if err := v.edit(req); err != nil {
+				violation.Stage, violation.Err = StageSend, err
+				operation.Violations = append(operation.Violations, violation)
+				continue
+			}
+		}
+		status, header, body, err := v.send(req)
+		if err != nil {
+			violation.Stage, violation.Err = StageSend, err
+			operation.Violations = append(operation.Violations, violation)
+			continue
+		}
+		violation.Status = status
+
+		err = openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    v.options,
+			},
+			Status:  status,
+			Header:  header,
+			Body:    ioutil.NopCloser(bytes.NewReader(body)),
+			Options: v.options,
+		})
+		if err != nil {
+			violation.Stage, violation.Err = StageResponse, err
+			operation.Violations = append(operation.Violations, violation)
+		}
+	}
+	return report, nil
+}
+
+func (v *Verifier) send(req *http.Request) (int, http.Header, []byte, error) {
+	if v.handler != nil {
+		recorder := httptest.NewRecorder()
+		v.handler.ServeHTTP(recorder, req)
+		response := recorder.Result()
+		return response.StatusCode, response.Header, recorder.Body.Bytes(), nil
+	}
+	response, err := v.client.Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return response.StatusCode, nil, nil, err
+	}
+	return response.StatusCode, response.Header, body, nil
+}
+
+// cloneRequest returns a copy of req with a fresh body, so requests can be verified more than once.
+func cloneRequest(ctx context.Context, req *http.Request) (*http.Request, error) {
+	clone := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// TestingT is the subset of testing.TB used by Assert.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Assert verifies the server described by opts and reports each violation as a test error.
+func Assert(t TestingT, doc *openapi3.T, opts ...Option) *Report {
+	t.Helper()
+	v, err := NewVerifier(doc, opts...)
+	if err != nil {
+		t.Errorf("openapi3contract: %v", err)
+		return nil
+	}
+	report, err := v.Verify(context.Background())
+	if err != nil {
+		t.Errorf("openapi3contract: %v", err)
+		return nil
+	}
+	for _, violation := range report.Violations() {
+		t.Errorf("%v", violation)
+	}
+	return report
+}
@@ -0,0 +1,176 @@
+package openapi3contract_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3contract"
+)
+
+const spec = `
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+servers: [{url: "http://example.com/api"}]
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: "#/components/schemas/Pet"}
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/Pet"}
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+  /pets/{id}:
+    get:
+      parameters:
+      - {name: id, in: path, required: true, schema: {type: integer}}
+      responses:
+        "200":
+          description: Pet
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+        "404": {description: Not found}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id: {type: integer, readOnly: true}
+        name: {type: string}
+`
+
+// pets implements the document, except that it answers GET /pets/{id} with an id string.
+func pets() http.Handler {
+	mux := http.NewServeMux()
+	write := func(w http.ResponseWriter, status int, value interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(value)
+	}
+	mux.HandleFunc("/api/pets", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			write(w, http.StatusOK, []interface{}{map[string]interface{}{"id": 1, "name": "Tom"}})
+		case http.MethodPost:
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			var pet map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&pet); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			pet["id"] = 2
+			write(w, http.StatusCreated, pet)
+		}
+	})
+	mux.HandleFunc("/api/pets/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/api/pets/")
+		if id != "1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		write(w, http.StatusOK, map[string]interface{}{"id": id, "name": "Tom"})
+	})
+	return mux
+}
+
+func TestVerifyHandler(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+
+	unknown, err := http.NewRequest(http.MethodGet, "http://example.com/api/owners", nil)
+	require.NoError(t, err)
+	existing, err := http.NewRequest(http.MethodGet, "http://example.com/api/pets/1", nil)
+	require.NoError(t, err)
+
+	v, err := openapi3contract.NewVerifier(doc,
+		openapi3contract.WithHandler(pets()),
+		openapi3contract.WithRequests(unknown, existing),
+		openapi3contract.WithRequestEditor(func(r *http.Request) error {
+			r.Header.Set("Authorization", "Bearer token")
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	report, err := v.Verify(context.Background())
+	require.NoError(t, err)
+
+	require.False(t, report.Valid())
+	var summary []string
+	for _, operation := range report.Operations {
+		summary = append(summary, fmt.Sprintf("%s %s: %d requests, %d violations", operation.Method, operation.Path, operation.Requests, len(operation.Violations)))
+	}
+	require.Equal(t, []string{
+		"GET /pets: 1 requests, 0 violations",
+		"POST /pets: 1 requests, 0 violations",
+		"GET /pets/{id}: 2 requests, 1 violations",
+	}, summary)
+	require.Empty(t, report.Untested())
+
+	violations := report.Violations()
+	require.Len(t, violations, 2)
+	require.Equal(t, openapi3contract.StageRoute, violations[0].Stage)
+	require.Equal(t, "http://example.com/api/owners", violations[0].URL)
+	require.Equal(t, openapi3contract.StageResponse, violations[1].Stage)
+	require.Equal(t, "http://example.com/api/pets/1", violations[1].URL)
+	require.Equal(t, http.StatusOK, violations[1].Status)
+	require.Contains(t, violations[1].Error(), `Error at "/id": field must be set to integer or not be present`)
+}
+
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertServer(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	server := httptest.NewServer(pets())
+	defer server.Close()
+
+	// Without credentials, creating a pet gets an undocumented status code
+	r := &recorder{}
+	report := openapi3contract.Assert(r, doc, openapi3contract.WithBaseURL(server.URL+"/api"))
+	require.NotNil(t, report)
+	require.Len(t, r.errors, 1)
+	require.Contains(t, r.errors[0], "POST "+server.URL+"/api/pets (401): response: status is not supported")
+
+	r = &recorder{}
+	report = openapi3contract.Assert(r, doc,
+		openapi3contract.WithBaseURL(server.URL+"/api"),
+		openapi3contract.WithoutGeneratedRequests(),
+	)
+	require.Empty(t, r.errors)
+	require.Len(t, report.Untested(), 3)
+}
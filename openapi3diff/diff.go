@@ -0,0 +1,307 @@
+package openapi3diff
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ChangeType identifies the kind of a change.
+type ChangeType string
+
+// Types of changes
+const (
+	PathAdded           ChangeType = "path-added"
+	PathRemoved         ChangeType = "path-removed"
+	OperationAdded      ChangeType = "operation-added"
+	OperationRemoved    ChangeType = "operation-removed"
+	ParameterAdded      ChangeType = "parameter-added"
+	ParameterRemoved    ChangeType = "parameter-removed"
+	ParameterRequired   ChangeType = "parameter-became-required"
+	ParameterOptional   ChangeType = "parameter-became-optional"
+	RequestBodyAdded    ChangeType = "request-body-added"
+	RequestBodyRemoved  ChangeType = "request-body-removed"
+	RequestBodyRequired ChangeType = "request-body-became-required"
+	RequestBodyOptional ChangeType = "request-body-became-optional"
+	MediaTypeAdded      ChangeType = "media-type-added"
+	MediaTypeRemoved    ChangeType = "media-type-removed"
+	ResponseAdded       ChangeType = "response-added"
+	ResponseRemoved     ChangeType = "response-removed"
+	TypeChanged         ChangeType = "type-changed"
+	FormatChanged       ChangeType = "format-changed"
+	EnumValueAdded      ChangeType = "enum-value-added"
+	EnumValueRemoved    ChangeType = "enum-value-removed"
+	PropertyAdded       ChangeType = "property-added"
+	PropertyRemoved     ChangeType = "property-removed"
+	PropertyRequired    ChangeType = "property-became-required"
+	PropertyOptional    ChangeType = "property-became-optional"
+	NullableAdded       ChangeType = "nullable-added"
+	NullableRemoved     ChangeType = "nullable-removed"
+	ConstraintNarrowed  ChangeType = "constraint-narrowed"
+	ConstraintWidened   ChangeType = "constraint-widened"
+)
+
+// Change is a difference between two revisions of a document.
+type Change struct {
+	Type     ChangeType `json:"type"`
+	Breaking bool       `json:"breaking"`
+	// Location is a JSON pointer to the changed part of the new revision,
+	// or of the base revision for removals.
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+func (change *Change) String() string {
+	breaking := ""
+	if change.Breaking {
+		breaking = " (breaking)"
+	}
+	return fmt.Sprintf("%s: %s%s", change.Location, change.Message, breaking)
+}
+
+// Changes are sorted by location.
+type Changes []*Change
+
+// Breaking returns the breaking changes.
+func (changes Changes) Breaking() Changes {
+	var breaking Changes
+	for _, change := range changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+// Compare returns the changes from base to revision.
+//
+// Paths are matched regardless of the names of their parameters,
+// so that renaming `/pets/{id}` to `/pets/{petId}` is not seen as a path replacement.
+// Schemas are compared in depth, following references.
+func Compare(base, revision *openapi3.T) Changes {
+	d := &differ{visited: make(map[visit]bool)}
+	d.comparePaths(base.Paths, revision.Paths)
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		return d.changes[i].Location < d.changes[j].Location
+	})
+	return d.changes
+}
+
+// direction tells whether a schema describes data sent by clients or by servers.
+type direction int
+
+const (
+	request direction = iota
+	response
+)
+
+type visit struct {
+	base, revision *openapi3.Schema
+	direction      direction
+}
+
+type differ struct {
+	changes Changes
+	visited map[visit]bool
+}
+
+func (d *differ) add(typ ChangeType, breaking bool, location, format string, args ...interface{}) {
+	d.changes = append(d.changes, &Change{
+		Type:     typ,
+		Breaking: breaking,
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// addNarrowing records a change restricting the values of a schema:
+// it breaks requests. Widening changes break responses.
+func (d *differ) addNarrowing(narrowing bool, dir direction, typ ChangeType, location, format string, args ...interface{}) {
+	d.add(typ, narrowing == (dir == request), location, format, args...)
+}
+
+var pathParameterPattern = regexp.MustCompile(`\{[^}]*\}`)
+
+func normalizePath(path string) string {
+	return pathParameterPattern.ReplaceAllString(path, "{}")
+}
+
+func (d *differ) comparePaths(base, revision *openapi3.Paths) {
+	revisionPaths := make(map[string]string, revision.Len())
+	for _, path := range revision.InSortedOrder() {
+		revisionPaths[normalizePath(path)] = path
+	}
+	basePaths := make(map[string]bool, base.Len())
+	for _, path := range base.InSortedOrder() {
+		basePaths[normalizePath(path)] = true
+		revisionPath, ok := revisionPaths[normalizePath(path)]
+		if !ok {
+			d.add(PathRemoved, true, pointer("paths", path), "path %s removed", path)
+			continue
+		}
+		d.comparePathItems(path, revisionPath, base.Value(path), revision.Value(revisionPath))
+	}
+	for _, path := range revision.InSortedOrder() {
+		if !basePaths[normalizePath(path)] {
+			d.add(PathAdded, false, pointer("paths", path), "path %s added", path)
+		}
+	}
+}
+
+func (d *differ) comparePathItems(basePath, path string, base, revision *openapi3.PathItem) {
+	for _, method := range base.Methods() {
+		location := pointer("paths", path, strings.ToLower(method))
+		operation := revision.GetOperation(method)
+		if operation == nil {
+			d.add(OperationRemoved, true, pointer("paths", basePath, strings.ToLower(method)), "operation %s %s removed", method, basePath)
+			continue
+		}
+		d.compareOperations(location, basePath, path, base, revision, base.GetOperation(method), operation)
+	}
+	for _, method := range revision.Methods() {
+		if base.GetOperation(method) == nil {
+			d.add(OperationAdded, false, pointer("paths", path, strings.ToLower(method)), "operation %s %s added", method, path)
+		}
+	}
+}
+
+func (d *differ) compareOperations(location, basePath, path string, basePathItem, pathItem *openapi3.PathItem, base, revision *openapi3.Operation) {
+	baseParameters := mergeParameters(basePath, basePathItem.Parameters, base.Parameters)
+	revisionParameters := mergeParameters(path, pathItem.Parameters, revision.Parameters)
+	for _, key := range sortedParameterKeys(baseParameters) {
+		baseParameter := baseParameters[key]
+		parameter, ok := revisionParameters[key]
+		if !ok {
+			d.add(ParameterRemoved, false, location, "%s parameter %q removed", baseParameter.In, baseParameter.Name)
+			continue
+		}
+		if parameter.Required != baseParameter.Required {
+			if parameter.Required {
+				d.add(ParameterRequired, true, location, "%s parameter %q became required", parameter.In, parameter.Name)
+			} else {
+				d.add(ParameterOptional, false, location, "%s parameter %q became optional", parameter.In, parameter.Name)
+			}
+		}
+		d.compareSchemas(location+pointer("parameters", parameter.In, parameter.Name), baseParameter.Schema, parameter.Schema, request)
+	}
+	for _, key := range sortedParameterKeys(revisionParameters) {
+		if _, ok := baseParameters[key]; !ok {
+			parameter := revisionParameters[key]
+			d.add(ParameterAdded, parameter.Required, location, "%s parameter %q added", parameter.In, parameter.Name)
+		}
+	}
+
+	d.compareRequestBodies(location+pointer("requestBody"), base.RequestBody, revision.RequestBody)
+
+	for _, status := range base.Responses.InSortedOrder() {
+		responseLocation := location + pointer("responses", status)
+		revisionResponse := revision.Responses.Value(status)
+		if revisionResponse == nil || revisionResponse.Value == nil {
+			d.add(ResponseRemoved, true, responseLocation, "response %s removed", status)
+			continue
+		}
+		if baseResponse := base.Responses.Value(status); baseResponse != nil && baseResponse.Value != nil {
+			d.compareContents(responseLocation, baseResponse.Value.Content, revisionResponse.Value.Content, response)
+		}
+	}
+	for _, status := range revision.Responses.InSortedOrder() {
+		if base.Responses.Value(status) == nil {
+			d.add(ResponseAdded, false, location+pointer("responses", status), "response %s added", status)
+		}
+	}
+}
+
+// mergeParameters returns the parameters of an operation, keyed by location and name.
+// Path parameters are keyed by position, as they may be renamed along with the path.
+func mergeParameters(path string, pathItemParameters, operationParameters openapi3.Parameters) map[string]*openapi3.Parameter {
+	variables := pathParameterPattern.FindAllString(path, -1)
+	parameters := make(map[string]*openapi3.Parameter)
+	for _, list := range []openapi3.Parameters{pathItemParameters, operationParameters} {
+		for _, parameterRef := range list {
+			p := parameterRef.Value
+			if p == nil {
+				continue
+			}
+			key := p.In + " " + p.Name
+			switch p.In {
+			case openapi3.ParameterInHeader:
+				key = strings.ToLower(key)
+			case openapi3.ParameterInPath:
+				for i, variable := range variables {
+					if variable == "{"+p.Name+"}" {
+						key = fmt.Sprintf("%s #%d", p.In, i)
+					}
+				}
+			}
+			parameters[key] = p
+		}
+	}
+	return parameters
+}
+
+func sortedParameterKeys(parameters map[string]*openapi3.Parameter) []string {
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (d *differ) compareRequestBodies(location string, base, revision *openapi3.RequestBodyRef) {
+	var baseBody, body *openapi3.RequestBody
+	if base != nil {
+		baseBody = base.Value
+	}
+	if revision != nil {
+		body = revision.Value
+	}
+	switch {
+	case baseBody == nil && body == nil:
+	case baseBody == nil:
+		d.add(RequestBodyAdded, body.Required, location, "request body added")
+	case body == nil:
+		d.add(RequestBodyRemoved, true, location, "request body removed")
+	default:
+		if body.Required != baseBody.Required {
+			if body.Required {
+				d.add(RequestBodyRequired, true, location, "request body became required")
+			} else {
+				d.add(RequestBodyOptional, false, location, "request body became optional")
+			}
+		}
+		d.compareContents(location, baseBody.Content, body.Content, request)
+	}
+}
+
+func (d *differ) compareContents(location string, base, revision openapi3.Content, dir direction) {
+	for _, mediaType := range base.MediaTypes() {
+		contentLocation := location + pointer("content", mediaType)
+		revisionMediaType := revision[mediaType]
+		if revisionMediaType == nil {
+			d.add(MediaTypeRemoved, true, contentLocation, "media type %s removed", mediaType)
+			continue
+		}
+		if base[mediaType] != nil {
+			d.compareSchemas(contentLocation+pointer("schema"), base[mediaType].Schema, revisionMediaType.Schema, dir)
+		}
+	}
+	for _, mediaType := range revision.MediaTypes() {
+		if _, ok := base[mediaType]; !ok {
+			d.add(MediaTypeAdded, false, location+pointer("content", mediaType), "media type %s added", mediaType)
+		}
+	}
+}
+
+// pointer returns the JSON pointer made of the given reference tokens.
+func pointer(tokens ...string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1))
+	}
+	return b.String()
+}
@@ -0,0 +1,169 @@
+package openapi3diff_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3diff"
+)
+
+const base = `
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      parameters:
+      - {name: limit, in: query, schema: {type: integer, maximum: 100}}
+      - {name: kind, in: query, schema: {type: string, enum: [cat, dog, fish]}}
+      responses:
+        "200":
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: "#/components/schemas/Pet"}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/NewPet"}
+      responses:
+        "201": {description: Created}
+  /pets/{id}:
+    get:
+      parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        "200": {description: Pet}
+        "404": {description: Not found}
+    delete:
+      parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        "204": {description: Deleted}
+  /stores:
+    get:
+      responses:
+        "200": {description: Stores}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        tag: {type: string}
+        status: {type: string, enum: [available, sold]}
+        parent: {$ref: "#/components/schemas/Pet"}
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string, maxLength: 50}
+        tag: {type: string}
+`
+
+const revision = `
+openapi: 3.0.3
+info: {title: Pets, version: 2.0.0}
+paths:
+  /pets:
+    get:
+      parameters:
+      - {name: limit, in: query, schema: {type: integer, maximum: 200}}
+      - {name: kind, in: query, schema: {type: string, enum: [cat, dog]}}
+      - {name: owner, in: query, required: true, schema: {type: string}}
+      responses:
+        "200":
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: "#/components/schemas/Pet"}
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/NewPet"}
+      responses:
+        "201": {description: Created}
+        "400": {description: Invalid}
+  /pets/{petId}:
+    get:
+      parameters:
+      - {name: petId, in: path, required: true, schema: {type: string}}
+      responses:
+        "200": {description: Pet}
+        "404": {description: Not found}
+  /owners:
+    get:
+      responses:
+        "200": {description: Owners}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        status: {type: string, enum: [available, sold, pending]}
+        age: {type: integer}
+        parent: {$ref: "#/components/schemas/Pet"}
+    NewPet:
+      type: object
+      required: [name, age]
+      properties:
+        name: {type: string, maxLength: 100}
+        tag: {type: string}
+        age: {type: integer}
+`
+
+func load(t *testing.T, data string) *openapi3.T {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(data))
+	require.NoError(t, err)
+	return doc
+}
+
+func TestCompare(t *testing.T) {
+	changes := openapi3diff.Compare(load(t, base), load(t, revision))
+
+	var lines []string
+	for _, change := range changes {
+		lines = append(lines, change.String())
+	}
+	require.Equal(t, []string{
+		`/paths/~1owners: path /owners added`,
+		`/paths/~1pets/get: query parameter "owner" added (breaking)`,
+		`/paths/~1pets/get/parameters/query/kind: enum value "fish" removed (breaking)`,
+		`/paths/~1pets/get/parameters/query/limit: maximum raised from 100`,
+		`/paths/~1pets/get/responses/200/content/application~1json/schema/items/properties/age: property "age" added`,
+		`/paths/~1pets/get/responses/200/content/application~1json/schema/items/properties/status: enum value "pending" added (breaking)`,
+		`/paths/~1pets/get/responses/200/content/application~1json/schema/items/properties/tag: property "tag" removed (breaking)`,
+		`/paths/~1pets/post/requestBody: request body became required (breaking)`,
+		`/paths/~1pets/post/requestBody/content/application~1json/schema/properties/age: property "age" added`,
+		`/paths/~1pets/post/requestBody/content/application~1json/schema/properties/age: property "age" became required (breaking)`,
+		`/paths/~1pets/post/requestBody/content/application~1json/schema/properties/name: maxLength raised from 50`,
+		`/paths/~1pets/post/responses/400: response 400 added`,
+		`/paths/~1pets~1{id}/delete: operation DELETE /pets/{id} removed (breaking)`,
+		`/paths/~1stores: path /stores removed (breaking)`,
+	}, lines)
+	require.Len(t, changes.Breaking(), 8)
+
+	data, err := json.Marshal(changes[2])
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "enum-value-removed",
+		"breaking": true,
+		"location": "/paths/~1pets/get/parameters/query/kind",
+		"message": "enum value \"fish\" removed"
+	}`, string(data))
+
+	require.Empty(t, openapi3diff.Compare(load(t, base), load(t, base)))
+}
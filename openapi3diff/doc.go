@@ -0,0 +1,10 @@
+// Package openapi3diff compares two revisions of an OpenAPIv3 document
+// and classifies their differences as breaking or not for existing clients.
+//
+// A change is breaking when a request accepted by the base revision may be rejected
+// by the new one (e.g. a removed operation, a new required parameter or a narrowed enum)
+// or when a response of the new revision may not be understood by clients
+// of the base one (e.g. a removed property or a new enum value).
+//
+// Changes are plain values that marshal to JSON, for use in compatibility gates.
+package openapi3diff
@@ -0,0 +1,205 @@
+package openapi3diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func (d *differ) compareSchemas(location string, baseRef, revisionRef *openapi3.SchemaRef, dir direction) {
+	if baseRef == nil || revisionRef == nil || baseRef.Value == nil || revisionRef.Value == nil {
+		return
+	}
+	base, revision := baseRef.Value, revisionRef.Value
+	key := visit{base: base, revision: revision, direction: dir}
+	if d.visited[key] {
+		return
+	}
+	d.visited[key] = true
+
+	if base.Type != revision.Type {
+		switch {
+		case base.Type == "":
+			d.addNarrowing(true, dir, TypeChanged, location, "type restricted to %s", revision.Type)
+		case revision.Type == "":
+			d.addNarrowing(false, dir, TypeChanged, location, "type %s no longer enforced", base.Type)
+		case base.Type == openapi3.TypeInteger && revision.Type == openapi3.TypeNumber:
+			d.addNarrowing(false, dir, TypeChanged, location, "type changed from %s to %s", base.Type, revision.Type)
+		case base.Type == openapi3.TypeNumber && revision.Type == openapi3.TypeInteger:
+			d.addNarrowing(true, dir, TypeChanged, location, "type changed from %s to %s", base.Type, revision.Type)
+		default:
+			d.add(TypeChanged, true, location, "type changed from %s to %s", base.Type, revision.Type)
+		}
+		// Other keywords are meaningless across types
+		return
+	}
+	if base.Format != revision.Format {
+		d.add(FormatChanged, base.Format != "" || dir == request, location, "format changed from %q to %q", base.Format, revision.Format)
+	}
+
+	if base.Nullable != revision.Nullable {
+		if revision.Nullable {
+			d.addNarrowing(false, dir, NullableAdded, location, "became nullable")
+		} else {
+			d.addNarrowing(true, dir, NullableRemoved, location, "no longer nullable")
+		}
+	}
+
+	d.compareEnums(location, base.Enum, revision.Enum, dir)
+
+	d.compareMin(location, "minimum", base.Min, revision.Min, dir)
+	d.compareMax(location, "maximum", base.Max, revision.Max, dir)
+	d.compareMin(location, "minLength", nonZeroUint64(base.MinLength), nonZeroUint64(revision.MinLength), dir)
+	d.compareMax(location, "maxLength", optionalUint64(base.MaxLength), optionalUint64(revision.MaxLength), dir)
+	d.compareMin(location, "minItems", nonZeroUint64(base.MinItems), nonZeroUint64(revision.MinItems), dir)
+	d.compareMax(location, "maxItems", optionalUint64(base.MaxItems), optionalUint64(revision.MaxItems), dir)
+	if base.Pattern != revision.Pattern {
+		switch {
+		case base.Pattern == "":
+			d.addNarrowing(true, dir, ConstraintNarrowed, location, "pattern %q added", revision.Pattern)
+		case revision.Pattern == "":
+			d.addNarrowing(false, dir, ConstraintWidened, location, "pattern %q removed", base.Pattern)
+		default:
+			d.add(ConstraintNarrowed, true, location, "pattern changed from %q to %q", base.Pattern, revision.Pattern)
+		}
+	}
+
+	baseRequired, revisionRequired := stringSet(base.Required), stringSet(revision.Required)
+	for _, name := range base.Properties.InSortedOrder() {
+		propertyLocation := location + pointer("properties", name)
+		property := revision.Properties.Value(name)
+		if property == nil {
+			// Clients may still send it, unless additional properties are forbidden
+			forbidden := revision.AdditionalPropertiesAllowed != nil && !*revision.AdditionalPropertiesAllowed
+			d.add(PropertyRemoved, dir == response || forbidden, propertyLocation, "property %q removed", name)
+			continue
+		}
+		d.compareSchemas(propertyLocation, base.Properties.Value(name), property, dir)
+	}
+	for _, name := range revision.Properties.InSortedOrder() {
+		if base.Properties.Value(name) == nil {
+			d.add(PropertyAdded, false, location+pointer("properties", name), "property %q added", name)
+		}
+	}
+	for _, name := range sortedSet(revisionRequired) {
+		if !baseRequired[name] {
+			d.addNarrowing(true, dir, PropertyRequired, location+pointer("properties", name), "property %q became required", name)
+		}
+	}
+	for _, name := range sortedSet(baseRequired) {
+		if !revisionRequired[name] && revision.Properties.Value(name) != nil {
+			d.addNarrowing(false, dir, PropertyOptional, location+pointer("properties", name), "property %q became optional", name)
+		}
+	}
+
+	d.compareSchemas(location+pointer("items"), base.Items, revision.Items, dir)
+	d.compareSchemas(location+pointer("additionalProperties"), base.AdditionalProperties, revision.AdditionalProperties, dir)
+	// Subschemas are compared when their count is unchanged
+	d.compareSchemaLists(location, "allOf", base.AllOf, revision.AllOf, dir)
+	d.compareSchemaLists(location, "anyOf", base.AnyOf, revision.AnyOf, dir)
+	d.compareSchemaLists(location, "oneOf", base.OneOf, revision.OneOf, dir)
+}
+
+func (d *differ) compareSchemaLists(location, keyword string, base, revision openapi3.SchemaRefs, dir direction) {
+	if len(base) != len(revision) {
+		return
+	}
+	for i := range base {
+		d.compareSchemas(location+pointer(keyword, fmt.Sprint(i)), base[i], revision[i], dir)
+	}
+}
+
+func (d *differ) compareEnums(location string, base, revision []interface{}, dir direction) {
+	if len(base) == 0 && len(revision) == 0 {
+		return
+	}
+	if len(base) == 0 {
+		d.addNarrowing(true, dir, EnumValueRemoved, location, "values restricted to an enum")
+		return
+	}
+	if len(revision) == 0 {
+		d.addNarrowing(false, dir, EnumValueAdded, location, "enum removed")
+		return
+	}
+	baseValues, revisionValues := enumSet(base), enumSet(revision)
+	for _, value := range sortedSet(baseValues) {
+		if !revisionValues[value] {
+			d.addNarrowing(true, dir, EnumValueRemoved, location, "enum value %s removed", value)
+		}
+	}
+	for _, value := range sortedSet(revisionValues) {
+		if !baseValues[value] {
+			d.addNarrowing(false, dir, EnumValueAdded, location, "enum value %s added", value)
+		}
+	}
+}
+
+func (d *differ) compareMin(location, keyword string, base, revision *float64, dir direction) {
+	switch {
+	case base == nil && revision == nil:
+	case base == nil || (revision != nil && *revision > *base):
+		d.addNarrowing(true, dir, ConstraintNarrowed, location, "%s raised to %v", keyword, *revision)
+	case revision == nil || *revision < *base:
+		d.addNarrowing(false, dir, ConstraintWidened, location, "%s lowered from %v", keyword, *base)
+	}
+}
+
+func (d *differ) compareMax(location, keyword string, base, revision *float64, dir direction) {
+	switch {
+	case base == nil && revision == nil:
+	case base == nil || (revision != nil && *revision < *base):
+		d.addNarrowing(true, dir, ConstraintNarrowed, location, "%s lowered to %v", keyword, *revision)
+	case revision == nil || *revision > *base:
+		d.addNarrowing(false, dir, ConstraintWidened, location, "%s raised from %v", keyword, *base)
+	}
+}
+
+// nonZeroUint64 returns a pointer to v as a float, or nil for the default value 0.
+func nonZeroUint64(v uint64) *float64 {
+	if v == 0 {
+		return nil
+	}
+	f := float64(v)
+	return &f
+}
+
+func optionalUint64(v *uint64) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// enumSet returns the JSON encodings of values.
+func enumSet(values []interface{}) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		set[string(data)] = true
+	}
+	return set
+}
+
+// sortedSet returns the sorted members of set.
+func sortedSet(set map[string]bool) []string {
+	members := make([]string, 0, len(set))
+	for member := range set {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+	return members
+}
@@ -0,0 +1,14 @@
+// Package openapi3docs serves interactive documentation for an OpenAPIv3 document
+// with Swagger UI, Redoc or Stoplight Elements:
+//
+//	handler, err := openapi3docs.NewHandler(doc, openapi3docs.WithUI(openapi3docs.Redoc))
+//	...
+//	http.Handle("/docs/", http.StripPrefix("/docs", handler))
+//
+// The handler serves the document at paths ending with openapi.json and openapi.yaml,
+// the scripts and styles of the viewer under assets/, and a page embedding the document at other paths.
+// The assets are the copies embedded in the package by default, fetched at pinned versions
+// by go generate (see the assets directory), so the browsers showing the pages need no network access.
+// WithAssets serves other copies. WithCDN loads the assets from a CDN instead, WithAssetsURL from elsewhere,
+// and WithAssetsIntegrity has browsers check them against Subresource Integrity hashes.
+package openapi3docs
@@ -0,0 +1,47 @@
+//go:build ignore
+// +build ignore
+
+// gen_assets fetches the assets of the viewers from DefaultAssetsURLs into the assets directory,
+// where they are embedded from.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/getkin/kin-openapi/openapi3docs"
+)
+
+func main() {
+	for _, ui := range openapi3docs.UIs {
+		dir := filepath.Join("assets", string(ui))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			log.Fatal(err)
+		}
+		for _, name := range openapi3docs.AssetFiles[ui] {
+			if err := fetch(openapi3docs.DefaultAssetsURLs[ui]+"/"+name, filepath.Join(dir, name)); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+}
+
+func fetch(url, file string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0o644)
+}
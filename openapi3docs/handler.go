@@ -0,0 +1,287 @@
+package openapi3docs
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// UI is a viewer of OpenAPI documents.
+type UI string
+
+const (
+	// SwaggerUI is Swagger UI (https://github.com/swagger-api/swagger-ui).
+	SwaggerUI UI = "swagger-ui"
+	// Redoc is Redoc (https://github.com/Redocly/redoc).
+	Redoc UI = "redoc"
+	// StoplightElements is Stoplight Elements (https://github.com/stoplightio/elements).
+	StoplightElements UI = "elements"
+)
+
+// UIs lists the supported viewers.
+var UIs = []UI{SwaggerUI, Redoc, StoplightElements}
+
+// DefaultAssetsURLs are the URLs of the assets of the viewers on the unpkg CDN, by viewer,
+// used with WithCDN. Browsers showing the pages then need network access to unpkg.com.
+var DefaultAssetsURLs = map[UI]string{
+	SwaggerUI:         "https://unpkg.com/swagger-ui-dist@5.17.14",
+	Redoc:             "https://unpkg.com/redoc@2.1.5/bundles",
+	StoplightElements: "https://unpkg.com/@stoplight/elements@8.3.4",
+}
+
+// AssetFiles lists the scripts and styles of each viewer, as found in the directories
+// of DefaultAssetsURLs.
+var AssetFiles = map[UI][]string{
+	SwaggerUI:         {"swagger-ui-bundle.js", "swagger-ui.css"},
+	Redoc:             {"redoc.standalone.js"},
+	StoplightElements: {"styles.min.css", "web-components.min.js"},
+}
+
+// Paths of the document and of the directory of the assets relative to the root of the handler.
+const (
+	JSONPath   = "openapi.json"
+	YAMLPath   = "openapi.yaml"
+	AssetsPath = "assets"
+)
+
+//go:generate go run gen_assets.go
+
+// embeddedAssets holds a copy of the assets of each viewer, under assets/<UI>.
+// The copies are fetched from DefaultAssetsURLs by go generate.
+//
+//go:embed assets
+var embeddedAssets embed.FS
+
+var pageTemplates = map[UI]*template.Template{
+	SwaggerUI: template.Must(template.New(string(SwaggerUI)).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="{{.AssetsURL}}/swagger-ui.css"{{with index .Integrity "swagger-ui.css"}} integrity="{{.}}" crossorigin="anonymous"{{end}}>
+</head>
+<body>
+<div id="docs"></div>
+<script src="{{.AssetsURL}}/swagger-ui-bundle.js"{{with index .Integrity "swagger-ui-bundle.js"}} integrity="{{.}}" crossorigin="anonymous"{{end}}></script>
+<script>
+SwaggerUIBundle({spec: {{.Spec}}, dom_id: "#docs"});
+</script>
+</body>
+</html>
+`)),
+	Redoc: template.Must(template.New(string(Redoc)).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+</head>
+<body>
+<div id="docs"></div>
+<script src="{{.AssetsURL}}/redoc.standalone.js"{{with index .Integrity "redoc.standalone.js"}} integrity="{{.}}" crossorigin="anonymous"{{end}}></script>
+<script>
+Redoc.init({{.Spec}}, {}, document.getElementById("docs"));
+</script>
+</body>
+</html>
+`)),
+	StoplightElements: template.Must(template.New(string(StoplightElements)).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="{{.AssetsURL}}/styles.min.css"{{with index .Integrity "styles.min.css"}} integrity="{{.}}" crossorigin="anonymous"{{end}}>
+<script src="{{.AssetsURL}}/web-components.min.js"{{with index .Integrity "web-components.min.js"}} integrity="{{.}}" crossorigin="anonymous"{{end}}></script>
+</head>
+<body>
+<elements-api id="docs" router="hash" layout="sidebar"></elements-api>
+<script>
+document.getElementById("docs").apiDescriptionDocument = {{.Spec}};
+</script>
+</body>
+</html>
+`)),
+}
+
+// Option allows tweaking a Handler
+type Option func(*Handler)
+
+// WithUI sets the viewer of the document. It defaults to SwaggerUI.
+func WithUI(ui UI) Option {
+	return func(h *Handler) {
+		h.ui = ui
+	}
+}
+
+// WithTitle sets the title of the page. It defaults to the title of the document.
+func WithTitle(title string) Option {
+	return func(h *Handler) {
+		h.title = title
+	}
+}
+
+// WithAssets sets the file system holding the scripts and styles of the viewer (see AssetFiles)
+// at its root. The handler serves them under AssetsPath. It defaults to the copies embedded
+// in the package.
+func WithAssets(fsys fs.FS) Option {
+	return func(h *Handler) {
+		h.assets = fsys
+	}
+}
+
+// WithAssetsURL sets the URL of the directory holding the scripts and styles of the viewer,
+// i.e. the dist directory of swagger-ui-dist, the bundles directory of redoc
+// or the root of @stoplight/elements. The handler then serves no assets.
+// An empty URL keeps the assets served by the handler.
+func WithAssetsURL(url string) Option {
+	return func(h *Handler) {
+		h.assetsURL = url
+	}
+}
+
+// WithCDN loads the scripts and styles of the viewer from DefaultAssetsURLs
+// rather than serving them, as WithAssetsURL does.
+func WithCDN() Option {
+	return func(h *Handler) {
+		h.cdn = true
+	}
+}
+
+// WithAssetsIntegrity sets the Subresource Integrity hashes of the scripts and styles
+// of the viewer, by file name (e.g. "swagger-ui-bundle.js": "sha384-..."), so that browsers
+// refuse assets which do not match them. The pages use no hashes by default.
+func WithAssetsIntegrity(integrity map[string]string) Option {
+	return func(h *Handler) {
+		h.assetsIntegrity = integrity
+	}
+}
+
+// Handler is an http.Handler serving the documentation of a document.
+type Handler struct {
+	ui              UI
+	title           string
+	assets          fs.FS
+	assetsURL       string
+	cdn             bool
+	assetsIntegrity map[string]string
+
+	page    []byte
+	jsonDoc []byte
+	yamlDoc []byte
+}
+
+// NewHandler returns a Handler for doc, which is encoded once and for all.
+func NewHandler(doc *openapi3.T, opts ...Option) (*Handler, error) {
+	h := &Handler{ui: SwaggerUI}
+	if doc.Info != nil {
+		h.title = doc.Info.Title
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	tmpl, ok := pageTemplates[h.ui]
+	if !ok {
+		return nil, fmt.Errorf("unsupported UI %q", h.ui)
+	}
+	if h.assetsURL == "" && h.cdn {
+		h.assetsURL = DefaultAssetsURLs[h.ui]
+	}
+	if h.assetsURL == "" {
+		if h.assets == nil {
+			assets, err := fs.Sub(embeddedAssets, path.Join("assets", string(h.ui)))
+			if err != nil {
+				return nil, err
+			}
+			h.assets = assets
+		}
+		for _, name := range AssetFiles[h.ui] {
+			if _, err := fs.Stat(h.assets, name); err != nil {
+				return nil, fmt.Errorf("missing asset %s of %s (run go generate in openapi3docs to embed them, or use WithAssets or WithCDN): %w", name, h.ui, err)
+			}
+		}
+		// Relative to the page, which is served at the root of the handler
+		h.assetsURL = AssetsPath
+	} else {
+		h.assets = nil
+	}
+	h.assetsURL = strings.TrimSuffix(h.assetsURL, "/")
+
+	var err error
+	// json.Marshal escapes <, > and &, so that the document can be embedded in scripts
+	if h.jsonDoc, err = json.Marshal(doc); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err = enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err = enc.Close(); err != nil {
+		return nil, err
+	}
+	h.yamlDoc = buf.Bytes()
+
+	buf.Reset()
+	if err = tmpl.Execute(&buf, struct {
+		Title     string
+		AssetsURL string
+		Integrity map[string]string
+		Spec      template.JS
+	}{h.title, h.assetsURL, h.assetsIntegrity, template.JS(h.jsonDoc)}); err != nil {
+		return nil, err
+	}
+	h.page = buf.Bytes()
+	return h, nil
+}
+
+// ServeHTTP serves the document at paths ending with JSONPath or YAMLPath, the assets
+// of the viewer at /AssetsPath/<file> unless they are loaded from an URL, and the page
+// at other paths. The page embeds the document and loads the assets it serves relatively,
+// so it is to be served at the root of the handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	switch urlPath := r.URL.Path; {
+	case strings.HasSuffix(urlPath, JSONPath):
+		h.write(w, r, "application/json", h.jsonDoc)
+	case strings.HasSuffix(urlPath, YAMLPath):
+		h.write(w, r, "application/yaml", h.yamlDoc)
+	case h.assets != nil && strings.HasPrefix(urlPath, "/"+AssetsPath+"/"):
+		name := strings.TrimPrefix(urlPath, "/"+AssetsPath+"/")
+		data, err := fs.ReadFile(h.assets, name)
+		if !fs.ValidPath(name) || err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		h.write(w, r, mime.TypeByExtension(path.Ext(name)), data)
+	default:
+		h.write(w, r, "text/html; charset=utf-8", h.page)
+	}
+}
+
+func (h *Handler) write(w http.ResponseWriter, r *http.Request, contentType string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,114 @@
+package openapi3docs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const spec = `
+openapi: 3.0.0
+info:
+  title: Pets </script>
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        '200': {description: OK}
+`
+
+func serve(t *testing.T, handler http.Handler, method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandler(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+
+	for _, ui := range UIs {
+		t.Run(string(ui), func(t *testing.T) {
+			assets := fstest.MapFS{}
+			for _, name := range AssetFiles[ui] {
+				assets[name] = &fstest.MapFile{Data: []byte("/* " + name + " */")}
+			}
+			handler, err := NewHandler(doc, WithUI(ui), WithAssets(assets))
+			require.NoError(t, err)
+			mux := http.NewServeMux()
+			mux.Handle("/docs/", http.StripPrefix("/docs", handler))
+
+			w := serve(t, mux, http.MethodGet, "/docs/")
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+			page := w.Body.String()
+			require.Contains(t, page, "<title>Pets &lt;/script&gt;</title>")
+			for _, name := range AssetFiles[ui] {
+				require.Contains(t, page, `"assets/`+name+`"`)
+
+				w = serve(t, mux, http.MethodGet, "/docs/assets/"+name)
+				require.Equal(t, http.StatusOK, w.Code)
+				require.Regexp(t, `^text/(javascript|css); charset=utf-8$`, w.Header().Get("Content-Type"))
+				require.Equal(t, "/* "+name+" */", w.Body.String())
+			}
+			// The document is embedded without closing the script
+			require.Contains(t, page, `"title":"Pets \u003c/script\u003e"`)
+			require.NotContains(t, page, "Pets </script>")
+
+			w = serve(t, mux, http.MethodGet, "/docs/assets/missing.js")
+			require.Equal(t, http.StatusNotFound, w.Code)
+
+			w = serve(t, mux, http.MethodGet, "/docs/openapi.json")
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			require.Contains(t, w.Body.String(), `"openapi":"3.0.0"`)
+
+			w = serve(t, mux, http.MethodHead, "/docs/openapi.yaml")
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+			require.NotEqual(t, "0", w.Header().Get("Content-Length"))
+			require.Empty(t, w.Body.String())
+
+			w = serve(t, mux, http.MethodPost, "/docs/")
+			require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		})
+	}
+}
+
+func TestHandlerOptions(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+
+	handler, err := NewHandler(doc, WithUI(Redoc), WithTitle("API"), WithAssetsURL("/static/redoc/"))
+	require.NoError(t, err)
+	page := serve(t, handler, http.MethodGet, "/").Body.String()
+	require.Contains(t, page, "<title>API</title>")
+	require.Contains(t, page, `<script src="/static/redoc/redoc.standalone.js"></script>`)
+	require.NotContains(t, page, "integrity")
+
+	handler, err = NewHandler(doc, WithCDN(), WithAssetsIntegrity(map[string]string{
+		"swagger-ui-bundle.js": "sha384-abc",
+		"swagger-ui.css":       `sha384-"def"`,
+	}))
+	require.NoError(t, err)
+	page = serve(t, handler, http.MethodGet, "/").Body.String()
+	require.Contains(t, page, `<script src="`+DefaultAssetsURLs[SwaggerUI]+`/swagger-ui-bundle.js" integrity="sha384-abc" crossorigin="anonymous"></script>`)
+	require.Contains(t, page, `/swagger-ui.css" integrity="sha384-&#34;def&#34;" crossorigin="anonymous">`)
+
+	// The viewer loads the assets from the CDN, instead of the handler
+	w := serve(t, handler, http.MethodGet, "/assets/swagger-ui.css")
+	require.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+
+	_, err = NewHandler(doc, WithAssets(fstest.MapFS{}))
+	require.EqualError(t, err, "missing asset swagger-ui-bundle.js of swagger-ui (run go generate in openapi3docs to embed them, or use WithAssets or WithCDN): open swagger-ui-bundle.js: file does not exist")
+
+	_, err = NewHandler(doc, WithUI("rapidoc"))
+	require.EqualError(t, err, `unsupported UI "rapidoc"`)
+}
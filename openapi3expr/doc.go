@@ -0,0 +1,19 @@
+// Package openapi3expr evaluates OpenAPIv3 runtime expressions
+// against a request and its response.
+//
+// Runtime expressions tell where the values used by callbacks and links are taken from.
+// See https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md#runtime-expressions
+//
+//	$url
+//	$method
+//	$statusCode
+//	$request.header.X-Request-Id
+//	$request.query.limit
+//	$request.path.id
+//	$response.body#/items/0/id
+//
+// Expressions are parsed with Parse and evaluated with Expression.Evaluate,
+// or embedded between braces in strings such as callback URLs and expanded with Expand:
+//
+//	{$request.body#/callbackUrl}?event={$method}
+package openapi3expr
@@ -0,0 +1,265 @@
+package openapi3expr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+)
+
+// Sources of values
+const (
+	SourceURL        = "url"
+	SourceMethod     = "method"
+	SourceStatusCode = "statusCode"
+	SourceRequest    = "request"
+	SourceResponse   = "response"
+)
+
+// Locations of values in a request or a response
+const (
+	LocationHeader = "header"
+	LocationQuery  = "query"
+	LocationPath   = "path"
+	LocationBody   = "body"
+)
+
+// ErrUndefined is returned when evaluating an expression referencing a missing value.
+var ErrUndefined = errors.New("value is undefined")
+
+// Expression is a parsed runtime expression.
+type Expression struct {
+	// Source is one of the Source constants.
+	Source string
+	// Location is one of the Location constants, when Source is SourceRequest or SourceResponse.
+	Location string
+	// Name is the name of the header, query or path parameter referenced.
+	Name string
+	// Pointer is the JSON pointer into the body referenced, "" for the whole body.
+	Pointer string
+
+	raw string
+}
+
+// String returns the expression as parsed.
+func (expr *Expression) String() string {
+	return expr.raw
+}
+
+// Parse parses a runtime expression such as "$request.path.id".
+func Parse(s string) (*Expression, error) {
+	expr := &Expression{raw: s}
+	fail := func(reason string) (*Expression, error) {
+		return nil, fmt.Errorf("invalid runtime expression %q: %s", s, reason)
+	}
+	if !strings.HasPrefix(s, "$") {
+		return fail(`must start with "$"`)
+	}
+	source := s[1:]
+	rest := ""
+	if i := strings.IndexByte(source, '.'); i >= 0 {
+		source, rest = source[:i], source[i+1:]
+	}
+	expr.Source = source
+	switch source {
+	case SourceURL, SourceMethod, SourceStatusCode:
+		if s != "$"+source {
+			return fail("unexpected " + strconv.Quote(s[len(source)+1:]))
+		}
+		return expr, nil
+	case SourceRequest, SourceResponse:
+	default:
+		return fail("unknown source " + strconv.Quote(source))
+	}
+
+	if strings.HasPrefix(rest, LocationBody) {
+		expr.Location = LocationBody
+		switch pointer := rest[len(LocationBody):]; {
+		case pointer == "":
+		case strings.HasPrefix(pointer, "#"):
+			expr.Pointer = pointer[1:]
+			if expr.Pointer != "" && !strings.HasPrefix(expr.Pointer, "/") {
+				return fail(`JSON pointer must start with "/"`)
+			}
+			if _, err := jsonpointer.New(expr.Pointer); err != nil {
+				return fail(err.Error())
+			}
+		default:
+			return fail("unexpected " + strconv.Quote(pointer))
+		}
+		return expr, nil
+	}
+
+	i := strings.IndexByte(rest, '.')
+	if i < 0 {
+		return fail("missing location")
+	}
+	expr.Location, expr.Name = rest[:i], rest[i+1:]
+	switch expr.Location {
+	case LocationHeader:
+	case LocationQuery, LocationPath:
+		if source == SourceResponse {
+			return fail("responses have no " + expr.Location + " parameters")
+		}
+	default:
+		return fail("unknown location " + strconv.Quote(expr.Location))
+	}
+	if expr.Name == "" {
+		return fail("missing name")
+	}
+	return expr, nil
+}
+
+// Values holds what expressions are evaluated against.
+type Values struct {
+	Request *http.Request
+	// PathParams are the path parameters of Request, as returned by routers.
+	PathParams map[string]string
+	// RequestBody is the body of Request, which is not read.
+	RequestBody []byte
+
+	Response *http.Response
+	// ResponseBody is the body of Response, which is not read.
+	ResponseBody []byte
+}
+
+// Evaluate returns the value expr references.
+// Body values are decoded from JSON, other values are strings
+// but for $statusCode, which is an int.
+// It returns an error wrapping ErrUndefined when the value does not exist.
+func (expr *Expression) Evaluate(values *Values) (interface{}, error) {
+	v, err := expr.evaluate(values)
+	if err != nil {
+		return nil, fmt.Errorf("evaluating %s: %w", expr.raw, err)
+	}
+	return v, nil
+}
+
+func (expr *Expression) evaluate(values *Values) (interface{}, error) {
+	req, resp := values.Request, values.Response
+	switch expr.Source {
+	case SourceURL:
+		if req == nil || req.URL == nil {
+			return nil, ErrUndefined
+		}
+		return requestURL(req), nil
+	case SourceMethod:
+		if req == nil {
+			return nil, ErrUndefined
+		}
+		return req.Method, nil
+	case SourceStatusCode:
+		if resp == nil {
+			return nil, ErrUndefined
+		}
+		return resp.StatusCode, nil
+	}
+
+	var header http.Header
+	var body []byte
+	if expr.Source == SourceRequest {
+		if req == nil {
+			return nil, ErrUndefined
+		}
+		header, body = req.Header, values.RequestBody
+	} else {
+		if resp == nil {
+			return nil, ErrUndefined
+		}
+		header, body = resp.Header, values.ResponseBody
+	}
+
+	switch expr.Location {
+	case LocationHeader:
+		if vs := header.Values(expr.Name); len(vs) != 0 {
+			return vs[0], nil
+		}
+	case LocationQuery:
+		if vs, ok := req.URL.Query()[expr.Name]; ok && len(vs) != 0 {
+			return vs[0], nil
+		}
+	case LocationPath:
+		if v, ok := values.PathParams[expr.Name]; ok {
+			return v, nil
+		}
+	case LocationBody:
+		if len(body) == 0 {
+			return nil, ErrUndefined
+		}
+		var doc interface{}
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return nil, err
+		}
+		pointer, err := jsonpointer.New(expr.Pointer)
+		if err != nil {
+			return nil, err
+		}
+		v, _, err := pointer.Get(doc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUndefined, err)
+		}
+		return v, nil
+	}
+	return nil, ErrUndefined
+}
+
+func requestURL(req *http.Request) string {
+	if req.URL.IsAbs() {
+		return req.URL.String()
+	}
+	u := *req.URL
+	u.Host = req.Host
+	u.Scheme = "http"
+	if req.TLS != nil {
+		u.Scheme = "https"
+	}
+	return u.String()
+}
+
+// Evaluate parses then evaluates the runtime expression s.
+func Evaluate(s string, values *Values) (interface{}, error) {
+	expr, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return expr.Evaluate(values)
+}
+
+// Expand replaces the runtime expressions between braces in s with their values,
+// strings as they are and other values encoded in JSON.
+// For instance "{$request.body#/url}?id={$response.body#/id}" may expand to
+// "https://example.com/hook?id=42".
+func Expand(s string, values *Values) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "{$")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		j := strings.IndexByte(s[i:], '}')
+		if j < 0 {
+			return "", fmt.Errorf("unterminated runtime expression in %q", s)
+		}
+		b.WriteString(s[:i])
+		v, err := Evaluate(s[i+1:i+j], values)
+		if err != nil {
+			return "", err
+		}
+		switch v := v.(type) {
+		case string:
+			b.WriteString(v)
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return "", err
+			}
+			b.Write(data)
+		}
+		s = s[i+j+1:]
+	}
+}
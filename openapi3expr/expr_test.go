@@ -0,0 +1,108 @@
+package openapi3expr_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3expr"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr string
+		want openapi3expr.Expression
+		err  string
+	}{
+		{expr: "$url", want: openapi3expr.Expression{Source: "url"}},
+		{expr: "$statusCode", want: openapi3expr.Expression{Source: "statusCode"}},
+		{expr: "$request.header.X-Id", want: openapi3expr.Expression{Source: "request", Location: "header", Name: "X-Id"}},
+		{expr: "$request.path.id", want: openapi3expr.Expression{Source: "request", Location: "path", Name: "id"}},
+		{expr: "$response.body", want: openapi3expr.Expression{Source: "response", Location: "body"}},
+		{expr: "$response.body#/a~1b/0", want: openapi3expr.Expression{Source: "response", Location: "body", Pointer: "/a~1b/0"}},
+		{expr: "url", err: `invalid runtime expression "url": must start with "$"`},
+		{expr: "$methods", err: `invalid runtime expression "$methods": unknown source "methods"`},
+		{expr: "$method.x", err: `invalid runtime expression "$method.x": unexpected ".x"`},
+		{expr: "$request.cookie.x", err: `invalid runtime expression "$request.cookie.x": unknown location "cookie"`},
+		{expr: "$request.query", err: `invalid runtime expression "$request.query": missing location`},
+		{expr: "$request.header.", err: `invalid runtime expression "$request.header.": missing name`},
+		{expr: "$response.query.x", err: `invalid runtime expression "$response.query.x": responses have no query parameters`},
+		{expr: "$request.body#a", err: `invalid runtime expression "$request.body#a": JSON pointer must start with "/"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := openapi3expr.Parse(tt.expr)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expr, expr.String())
+			require.Equal(t, tt.want.Source, expr.Source)
+			require.Equal(t, tt.want.Location, expr.Location)
+			require.Equal(t, tt.want.Name, expr.Name)
+			require.Equal(t, tt.want.Pointer, expr.Pointer)
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/pets/7?tag=a&tag=b", nil)
+	req.Header.Set("X-Request-Id", "r1")
+	values := &openapi3expr.Values{
+		Request:      req,
+		PathParams:   map[string]string{"id": "7"},
+		RequestBody:  []byte(`{"callbackUrl": "https://client.example.com/hook", "tags": ["x", "y"]}`),
+		Response:     &http.Response{StatusCode: http.StatusCreated, Header: http.Header{"Location": {"/pets/7"}}},
+		ResponseBody: []byte(`{"id": 7, "a/b": {"c": true}}`),
+	}
+
+	for expr, want := range map[string]interface{}{
+		"$url":                         "http://example.com/pets/7?tag=a&tag=b",
+		"$method":                      http.MethodPost,
+		"$statusCode":                  http.StatusCreated,
+		"$request.header.x-request-id": "r1",
+		"$request.query.tag":           "a",
+		"$request.path.id":             "7",
+		"$request.body#/tags/1":        "y",
+		"$response.header.Location":    "/pets/7",
+		"$response.body#/id":           float64(7),
+		"$response.body#/a~1b":         map[string]interface{}{"c": true},
+		"$response.body":               map[string]interface{}{"id": float64(7), "a/b": map[string]interface{}{"c": true}},
+	} {
+		v, err := openapi3expr.Evaluate(expr, values)
+		require.NoError(t, err, expr)
+		require.Equal(t, want, v, expr)
+	}
+
+	for _, expr := range []string{"$request.header.X-Other", "$request.query.limit", "$request.path.name", "$response.body#/name"} {
+		_, err := openapi3expr.Evaluate(expr, values)
+		require.True(t, errors.Is(err, openapi3expr.ErrUndefined), expr)
+	}
+	_, err := openapi3expr.Evaluate("$statusCode", &openapi3expr.Values{Request: req})
+	require.EqualError(t, err, "evaluating $statusCode: value is undefined")
+}
+
+func TestExpand(t *testing.T) {
+	values := &openapi3expr.Values{
+		Request:      httptest.NewRequest(http.MethodPost, "/subscribe", nil),
+		RequestBody:  []byte(`{"callbackUrl": "https://client.example.com/hook"}`),
+		ResponseBody: []byte(`{"id": 42}`),
+		Response:     &http.Response{StatusCode: http.StatusOK},
+	}
+	s, err := openapi3expr.Expand("{$request.body#/callbackUrl}?id={$response.body#/id}&method={$method}", values)
+	require.NoError(t, err)
+	require.Equal(t, "https://client.example.com/hook?id=42&method=POST", s)
+
+	s, err = openapi3expr.Expand("no expressions {here}", values)
+	require.NoError(t, err)
+	require.Equal(t, "no expressions {here}", s)
+
+	_, err = openapi3expr.Expand("{$request.body#/url", values)
+	require.EqualError(t, err, `unterminated runtime expression in "{$request.body#/url"`)
+	_, err = openapi3expr.Expand("{$request.body#/url}", values)
+	require.Error(t, err)
+}
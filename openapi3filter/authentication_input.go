@@ -11,6 +11,17 @@ type AuthenticationInput struct {
 	SecuritySchemeName     string
 	SecurityScheme         *openapi3.SecurityScheme
 	Scopes                 []string
+
+	principal interface{}
+}
+
+// SetPrincipal records who the request was authenticated as with the security scheme,
+// e.g. a user or the claims of a token, once the AuthenticationFunc checked the credentials.
+// When the security requirement the scheme belongs to is met, the principal is returned by
+// RequestValidationInput.Principal and passed on by Validator.Middleware in request contexts
+// (see PrincipalFromContext).
+func (input *AuthenticationInput) SetPrincipal(principal interface{}) {
+	input.principal = principal
 }
 
 func (input *AuthenticationInput) NewError(err error) error {
@@ -25,5 +36,6 @@ func (input *AuthenticationInput) NewError(err error) error {
 		Input:  input.RequestValidationInput,
 		Reason: "authorization failed",
 		Err:    err,
+		kind:   ErrAuthenticationFailed,
 	}
 }
@@ -0,0 +1,47 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// maxPooledBufferSize caps the capacity of the buffers put back in bodyBufferPool,
+// so that a few large bodies do not keep memory allocated.
+const maxPooledBufferSize = 1 << 20
+
+// maxPreallocatedBodySize caps the size of the buffers allocated after the
+// Content-Length of a request, which clients may lie about.
+const maxPreallocatedBodySize = 4 << 20
+
+var bodyBufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+func getBodyBuffer() *bytes.Buffer {
+	return bodyBufferPool.Get().(*bytes.Buffer)
+}
+
+func putBodyBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	bodyBufferPool.Put(buf)
+}
+
+// readBody returns the content of r, whose size is size if known (i.e. not negative),
+// in a slice of its own that is allocated once.
+func readBody(r io.Reader, size int64) ([]byte, error) {
+	if size > 0 && size <= maxPreallocatedBodySize {
+		buf := bytes.NewBuffer(make([]byte, 0, size+bytes.MinRead))
+		_, err := buf.ReadFrom(r)
+		return buf.Bytes(), err
+	}
+	buf := getBodyBuffer()
+	defer putBodyBuffer(buf)
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), buf.Bytes()...), nil
+}
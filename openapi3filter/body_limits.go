@@ -0,0 +1,98 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrBodyTooComplex is returned when a JSON request body exceeds Options.BodyLimits.
+var ErrBodyTooComplex = errors.New("body is too complex")
+
+// BodyLimits limits the complexity of JSON request bodies (of media type application/json
+// or +json). Bodies are checked while they are scanned, before they are decoded and
+// validated against their schema, so that hostile payloads are rejected cheaply.
+// Zero fields are not limited.
+type BodyLimits struct {
+	// MaxDepth limits the nesting depth of arrays and objects, e.g. 2 for {"a": [1]}.
+	MaxDepth int
+	// MaxKeys limits the total number of object members in the body.
+	MaxKeys int
+	// MaxArrayLength limits the number of items of each array.
+	MaxArrayLength int
+	// MaxStringLength limits the length, in bytes, of strings and object member names.
+	MaxStringLength int
+}
+
+// appliesTo reports whether bodies of mediaType are checked.
+func (limits *BodyLimits) appliesTo(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// check returns an error matching ErrBodyTooComplex if body exceeds the limits.
+// Malformed bodies are left for their decoder to report.
+func (limits *BodyLimits) check(body []byte) error {
+	type container struct {
+		object bool
+		// items is the number of items of an array, or of values of an object
+		items int
+		// key is whether the next token of an object is a member name
+		key bool
+	}
+	var stack []*container
+	keys := 0
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+
+		if delim, ok := token.(json.Delim); ok && (delim == '}' || delim == ']') {
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return nil
+			}
+			continue
+		}
+
+		if n := len(stack); n > 0 {
+			parent := stack[n-1]
+			if parent.object && parent.key {
+				// A member name
+				parent.key = false
+				if keys++; limits.MaxKeys > 0 && keys > limits.MaxKeys {
+					return fmt.Errorf("%w: it has more than %d object members", ErrBodyTooComplex, limits.MaxKeys)
+				}
+				if s := token.(string); limits.MaxStringLength > 0 && len(s) > limits.MaxStringLength {
+					return fmt.Errorf("%w: member name of %d bytes exceeds %d bytes", ErrBodyTooComplex, len(s), limits.MaxStringLength)
+				}
+				continue
+			}
+			parent.key = parent.object
+			if parent.items++; !parent.object && limits.MaxArrayLength > 0 && parent.items > limits.MaxArrayLength {
+				return fmt.Errorf("%w: array has more than %d items", ErrBodyTooComplex, limits.MaxArrayLength)
+			}
+		}
+
+		switch token := token.(type) {
+		case json.Delim:
+			stack = append(stack, &container{object: token == '{', key: token == '{'})
+			if limits.MaxDepth > 0 && len(stack) > limits.MaxDepth {
+				return fmt.Errorf("%w: nesting depth exceeds %d", ErrBodyTooComplex, limits.MaxDepth)
+			}
+		case string:
+			if limits.MaxStringLength > 0 && len(token) > limits.MaxStringLength {
+				return fmt.Errorf("%w: string of %d bytes exceeds %d bytes", ErrBodyTooComplex, len(token), limits.MaxStringLength)
+			}
+		}
+		if len(stack) == 0 {
+			// A scalar body
+			return nil
+		}
+	}
+}
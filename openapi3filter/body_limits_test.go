@@ -0,0 +1,76 @@
+package openapi3filter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestBodyLimitsCheck(t *testing.T) {
+	limits := &BodyLimits{MaxDepth: 3, MaxKeys: 4, MaxArrayLength: 3, MaxStringLength: 5}
+	for _, tc := range []struct {
+		body string
+		err  string
+	}{
+		{body: `{"a": [1, 2, 3], "b": {"c": "abcde"}}`},
+		{body: `"abcde"`},
+		{body: `[[["a"]], [[]]]`},
+		{body: `{"a": [[[1]]]}`, err: "nesting depth exceeds 3"},
+		{body: `{"a": {"b": 1, "c": 2}, "d": {"e": 3}}`, err: "it has more than 4 object members"},
+		{body: `[{"a": 1}, [1, 2, 3, 4]]`, err: "array has more than 3 items"},
+		{body: `[1, 2, 3, 4]`, err: "array has more than 3 items"},
+		{body: `{"a": "abcdef"}`, err: "string of 6 bytes exceeds 5 bytes"},
+		{body: `{"abcdef": 1}`, err: "member name of 6 bytes exceeds 5 bytes"},
+		{body: `"abcdef"`, err: "string of 6 bytes exceeds 5 bytes"},
+		// Malformed bodies are reported by their decoder
+		{body: `{"a": [1, 2`},
+		{body: `[1, 2, 3] [1, 2, 3, 4]`},
+	} {
+		t.Run(tc.body, func(t *testing.T) {
+			err := limits.check([]byte(tc.body))
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, errors.Is(err, ErrBodyTooComplex))
+			require.EqualError(t, err, "body is too complex: "+tc.err)
+		})
+	}
+}
+
+func TestValidateRequestBodyLimits(t *testing.T) {
+	requestBody := openapi3.NewRequestBody().WithJSONSchema(openapi3.NewObjectSchema())
+	validate := func(body, contentType string, options *Options) error {
+		req, err := http.NewRequest(http.MethodPost, "http://example.com/pets", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		input := &RequestValidationInput{Request: req, Options: options}
+		return ValidateRequestBody(context.Background(), input, requestBody)
+	}
+	deep := strings.Repeat(`{"a":`, 100) + "1" + strings.Repeat("}", 100)
+	options := &Options{BodyLimits: &BodyLimits{MaxDepth: 32}}
+
+	require.NoError(t, validate(deep, "application/json", nil))
+
+	err := validate(deep, "application/json; charset=utf-8", options)
+	require.True(t, errors.Is(err, ErrBodyTooComplex))
+	require.True(t, errors.Is(err, ErrDecodingFailed))
+	require.EqualError(t, err, "request body has an error: body is too complex: nesting depth exceeds 32")
+	class := ClassifyError(err)
+	require.Equal(t, http.StatusRequestEntityTooLarge, class.Status)
+	require.Equal(t, ErrorCodeBodyTooComplex, class.Code)
+
+	requestBody.Content["application/merge-patch+json"] = requestBody.Content["application/json"]
+	RegisterBodyDecoder("application/merge-patch+json", jsonBodyDecoder)
+	defer UnregisterBodyDecoder("application/merge-patch+json")
+	err = validate(deep, "application/merge-patch+json", options)
+	require.True(t, errors.Is(err, ErrBodyTooComplex))
+
+	require.NoError(t, validate(`{"a": {"b": 1}}`, "application/json", options))
+}
@@ -0,0 +1,131 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxDecompressedBodySize is the size limit, in bytes, of decompressed request bodies
+// when Options.MaxDecompressedBodySize is not set.
+const DefaultMaxDecompressedBodySize = 32 << 20
+
+// ErrUnsupportedContentEncoding is returned when a request body is encoded with a
+// Content-Encoding for which no ContentDecoder is registered.
+var ErrUnsupportedContentEncoding = errors.New("unsupported content encoding")
+
+// ErrDecompressedBodyTooLarge is returned when a decompressed request body exceeds
+// Options.MaxDecompressedBodySize.
+var ErrDecompressedBodyTooLarge = errors.New("decompressed body is too large")
+
+// ContentDecoder returns a reader of the decoded content of r,
+// for a Content-Encoding such as gzip.
+type ContentDecoder func(r io.Reader) (io.ReadCloser, error)
+
+var contentDecoders = map[string]ContentDecoder{
+	"gzip":    gzipDecoder,
+	"x-gzip":  gzipDecoder,
+	"deflate": deflateDecoder,
+}
+
+// RegisteredContentDecoder returns the registered decoder for the given content encoding.
+//
+// If no decoder was registered for the given content encoding, nil is returned.
+// This call is not thread-safe: content decoders should not be created/destroyed by multiple goroutines.
+func RegisteredContentDecoder(encoding string) ContentDecoder {
+	return contentDecoders[strings.ToLower(encoding)]
+}
+
+// RegisterContentDecoder registers a request body's decoder for a content encoding,
+// e.g. "br" with a Brotli implementation. Decoders for gzip and deflate are registered by default.
+//
+// If a decoder for the specified content encoding already exists, the function replaces
+// it with the specified decoder.
+// This call is not thread-safe: content decoders should not be created/destroyed by multiple goroutines.
+func RegisterContentDecoder(encoding string, decoder ContentDecoder) {
+	if encoding == "" {
+		panic("encoding is empty")
+	}
+	if decoder == nil {
+		panic("decoder is not defined")
+	}
+	contentDecoders[strings.ToLower(encoding)] = decoder
+}
+
+// UnregisterContentDecoder dissociates a decoder from a content encoding.
+//
+// Validating request bodies with this content encoding will result in an error.
+// This call is not thread-safe: content decoders should not be created/destroyed by multiple goroutines.
+func UnregisterContentDecoder(encoding string) {
+	if encoding == "" {
+		panic("encoding is empty")
+	}
+	delete(contentDecoders, strings.ToLower(encoding))
+}
+
+func gzipDecoder(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// deflateDecoder reads zlib streams, as HTTP mandates, as well as the raw deflate
+// streams some clients send instead.
+func deflateDecoder(r io.Reader) (io.ReadCloser, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err == zlib.ErrHeader {
+		return flate.NewReader(bytes.NewReader(data)), nil
+	}
+	return zr, err
+}
+
+var headerCE = http.CanonicalHeaderKey("Content-Encoding")
+
+// decompressBody undoes the content encodings listed in header, in reverse order,
+// and returns the decoded body, or data as is when the body is not encoded.
+func decompressBody(data []byte, header http.Header, maxSize int64) ([]byte, bool, error) {
+	var encodings []string
+	for _, value := range header.Values(headerCE) {
+		for _, encoding := range strings.Split(value, ",") {
+			if encoding = strings.TrimSpace(encoding); encoding != "" && !strings.EqualFold(encoding, "identity") {
+				encodings = append(encodings, encoding)
+			}
+		}
+	}
+	if len(encodings) == 0 {
+		return data, false, nil
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultMaxDecompressedBodySize
+	}
+
+	for i := len(encodings) - 1; i >= 0; i-- {
+		decoder := RegisteredContentDecoder(encodings[i])
+		if decoder == nil {
+			return nil, false, fmt.Errorf("%w %q", ErrUnsupportedContentEncoding, encodings[i])
+		}
+		r, err := decoder(bytes.NewReader(data))
+		if err != nil {
+			return nil, false, err
+		}
+		// Read one more byte than allowed to tell a body of the maximal size from a larger one
+		data, err = ioutil.ReadAll(io.LimitReader(r, maxSize+1))
+		r.Close()
+		if err != nil {
+			return nil, false, err
+		}
+		if int64(len(data)) > maxSize {
+			return nil, false, fmt.Errorf("%w: limit is %d bytes", ErrDecompressedBodyTooLarge, maxSize)
+		}
+	}
+	return data, true, nil
+}
@@ -0,0 +1,151 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestValidateRequestBodyContentEncoding(t *testing.T) {
+	schema := openapi3.NewObjectSchema().
+		WithProperty("name", openapi3.NewStringSchema()).
+		WithProperty("kind", openapi3.NewStringSchema().WithDefault("cat"))
+	schema.Required = []string{"name"}
+	requestBody := openapi3.NewRequestBody().WithJSONSchema(schema)
+	compress := func(encoding, data string) []byte {
+		var buf bytes.Buffer
+		var w io.WriteCloser
+		switch encoding {
+		case "gzip":
+			w = gzip.NewWriter(&buf)
+		case "deflate":
+			w = zlib.NewWriter(&buf)
+		case "raw-deflate":
+			w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+		}
+		_, err := w.Write([]byte(data))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+	validate := func(encoding string, body []byte, options *Options) (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, "http://example.com/pets", bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Encoding", encoding)
+		input := &RequestValidationInput{Request: req, Options: options}
+		return req, ValidateRequestBody(context.Background(), input, requestBody)
+	}
+
+	t.Run("gzip", func(t *testing.T) {
+		body := compress("gzip", `{"name":"Rex","kind":"dog"}`)
+		req, err := validate("gzip", body, nil)
+		require.NoError(t, err)
+		// The request keeps its encoded body
+		data, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.Equal(t, body, data)
+		require.Equal(t, "gzip", req.Header.Get("Content-Encoding"))
+	})
+
+	t.Run("invalid gzip content", func(t *testing.T) {
+		_, err := validate("gzip", compress("gzip", `{}`), nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `property "name" is missing`)
+	})
+
+	t.Run("deflate", func(t *testing.T) {
+		_, err := validate("deflate", compress("deflate", `{"name":"Rex","kind":"dog"}`), nil)
+		require.NoError(t, err)
+		_, err = validate("deflate", compress("raw-deflate", `{"name":"Rex","kind":"dog"}`), nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("several encodings", func(t *testing.T) {
+		body := compress("gzip", string(compress("deflate", `{"name":"Rex","kind":"dog"}`)))
+		_, err := validate("deflate, gzip", body, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("defaults are set in the decompressed body", func(t *testing.T) {
+		req, err := validate("gzip", compress("gzip", `{"name":"Rex"}`), nil)
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name":"Rex","kind":"cat"}`, string(data))
+		require.Empty(t, req.Header.Get("Content-Encoding"))
+	})
+
+	t.Run("corrupted body", func(t *testing.T) {
+		_, err := validate("gzip", []byte(`{"name":"Rex"}`), nil)
+		require.EqualError(t, err, "request body has an error: failed to decompress request body: gzip: invalid header")
+	})
+
+	t.Run("unsupported encoding", func(t *testing.T) {
+		_, err := validate("br", []byte("..."), nil)
+		require.True(t, errors.Is(err, ErrUnsupportedContentEncoding))
+		require.Equal(t, &ValidationError{
+			Status: http.StatusUnsupportedMediaType,
+			Title:  `unsupported content encoding "br"`,
+		}, convertError(err))
+	})
+
+	t.Run("body without declared content", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "http://example.com/pets", strings.NewReader("..."))
+		require.NoError(t, err)
+		req.Header.Set("Content-Encoding", "br")
+		input := &RequestValidationInput{Request: req}
+		require.NoError(t, ValidateRequestBody(context.Background(), input, openapi3.NewRequestBody()))
+		data, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.Equal(t, "...", string(data))
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		body := compress("gzip", `{"name":"`+strings.Repeat("x", 100)+`"}`)
+		_, err := validate("gzip", body, &Options{MaxDecompressedBodySize: 100})
+		require.True(t, errors.Is(err, ErrDecompressedBodyTooLarge))
+		require.Equal(t, &ValidationError{
+			Status: http.StatusRequestEntityTooLarge,
+			Title:  "decompressed body is too large: limit is 100 bytes",
+		}, convertError(err))
+	})
+
+	t.Run("registered encoding", func(t *testing.T) {
+		require.Nil(t, RegisteredContentDecoder("rot13"))
+		RegisterContentDecoder("rot13", func(r io.Reader) (io.ReadCloser, error) {
+			data, err := ioutil.ReadAll(r)
+			if err != nil {
+				return nil, err
+			}
+			return ioutil.NopCloser(strings.NewReader(strings.Map(rot13, string(data)))), nil
+		})
+		defer UnregisterContentDecoder("rot13")
+		require.NotNil(t, RegisteredContentDecoder("ROT13"))
+
+		_, err := validate("rot13", []byte(strings.Map(rot13, `{"name":"Rex"}`)), nil)
+		require.NoError(t, err)
+	})
+}
+
+func rot13(r rune) rune {
+	switch {
+	case r >= 'a' && r <= 'z':
+		return 'a' + (r-'a'+13)%26
+	case r >= 'A' && r <= 'Z':
+		return 'A' + (r-'A'+13)%26
+	}
+	return r
+}
@@ -0,0 +1,233 @@
+package openapi3filter
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+const (
+	headerOrigin                        = "Origin"
+	headerAccessControlRequestMethod    = "Access-Control-Request-Method"
+	headerAccessControlRequestHeaders   = "Access-Control-Request-Headers"
+	headerAccessControlAllowOrigin      = "Access-Control-Allow-Origin"
+	headerAccessControlAllowMethods     = "Access-Control-Allow-Methods"
+	headerAccessControlAllowHeaders     = "Access-Control-Allow-Headers"
+	headerAccessControlAllowCredentials = "Access-Control-Allow-Credentials"
+	headerAccessControlExposeHeaders    = "Access-Control-Expose-Headers"
+	headerAccessControlMaxAge           = "Access-Control-Max-Age"
+)
+
+// CORSOptions configures how a Validator treats cross-origin requests (see CORS).
+type CORSOptions struct {
+	// Set HandlePreflight so the Validator answers preflight requests itself,
+	// with the methods of the requested path and the request headers of the requested
+	// operation (its header parameters and Content-Type when it has a request body)
+	// besides AllowedHeaders. Otherwise preflight requests are passed on to the handler
+	// without being validated.
+	HandlePreflight bool
+
+	// AllowedOrigins lists the origins allowed to make requests, "*" allowing any.
+	// Any origin is allowed when empty.
+	AllowedOrigins []string
+
+	// AllowedHeaders lists the request headers allowed besides those of operations,
+	// e.g. "Authorization".
+	AllowedHeaders []string
+
+	// ExposedHeaders lists the response headers that responses to allowed origins
+	// must expose in their Access-Control-Expose-Headers header.
+	ExposedHeaders []string
+
+	// Set AllowCredentials so responses to allowed origins must allow credentials,
+	// with an Access-Control-Allow-Credentials header, and name the origin rather than "*".
+	AllowCredentials bool
+
+	// MaxAge is how long the answers to preflight requests may be cached.
+	// It is not sent when zero.
+	MaxAge time.Duration
+}
+
+// CORS makes the Validator aware of CORS (Cross-Origin Resource Sharing).
+//
+// Preflight requests, which are OPTIONS requests with an Origin and an
+// Access-Control-Request-Method header, are not validated: they are either
+// answered by the Validator or passed on to the handler, see CORSOptions.HandlePreflight.
+//
+// The CORS headers of responses to requests with an Origin header are validated
+// against options: responses to allowed origins must allow them, and the others
+// must not. Failures match ErrCORSViolation and are handled as other response
+// validation failures.
+func CORS(options CORSOptions) ValidatorOption {
+	return func(v *Validator) {
+		v.cors = &options
+	}
+}
+
+// isPreflightRequest reports whether r is a CORS preflight request.
+func isPreflightRequest(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get(headerOrigin) != "" &&
+		r.Header.Get(headerAccessControlRequestMethod) != ""
+}
+
+// preflight handles the preflight request r.
+func (v *Validator) preflight(w http.ResponseWriter, r *http.Request, h http.Handler) {
+	if !v.cors.HandlePreflight {
+		h.ServeHTTP(w, r)
+		return
+	}
+
+	header := w.Header()
+	header.Add("Vary", headerOrigin)
+	header.Add("Vary", headerAccessControlRequestMethod)
+	header.Add("Vary", headerAccessControlRequestHeaders)
+
+	origin := r.Header.Get(headerOrigin)
+	if !v.cors.allowsOrigin(origin) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	// Find the route of the request to come
+	requested := r.Clone(r.Context())
+	requested.Method = strings.ToUpper(r.Header.Get(headerAccessControlRequestMethod))
+	route, _, err := v.router.FindRoute(requested)
+	if err != nil {
+		v.fail(r, "", ErrCodeCannotFindRoute, "validation error: failed to find route for preflight of "+r.URL.String(), err)
+		v.errFunc(w, http.StatusNotFound, ErrCodeCannotFindRoute, err)
+		return
+	}
+
+	methods := route.Allow
+	if len(methods) == 0 {
+		methods = []string{route.Method}
+	}
+	header.Set(headerAccessControlAllowOrigin, v.cors.allowOriginValue(origin))
+	header.Set(headerAccessControlAllowMethods, strings.Join(methods, ", "))
+	if headers := v.cors.allowedRequestHeaders(route); len(headers) != 0 {
+		header.Set(headerAccessControlAllowHeaders, strings.Join(headers, ", "))
+	}
+	if v.cors.AllowCredentials {
+		header.Set(headerAccessControlAllowCredentials, "true")
+	}
+	if v.cors.MaxAge > 0 {
+		header.Set(headerAccessControlMaxAge, strconv.Itoa(int(v.cors.MaxAge/time.Second)))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (o *CORSOptions) allowsOrigin(origin string) bool {
+	if len(o.AllowedOrigins) == 0 {
+		return true
+	}
+	for _, allowed := range o.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowOriginValue returns the Access-Control-Allow-Origin value answering origin.
+func (o *CORSOptions) allowOriginValue(origin string) string {
+	if o.AllowCredentials {
+		return origin
+	}
+	if len(o.AllowedOrigins) == 0 {
+		return "*"
+	}
+	for _, allowed := range o.AllowedOrigins {
+		if allowed == "*" {
+			return "*"
+		}
+	}
+	return origin
+}
+
+// allowedRequestHeaders returns the sorted request headers of route and AllowedHeaders.
+func (o *CORSOptions) allowedRequestHeaders(route *routers.Route) []string {
+	names := make(map[string]struct{})
+	for _, name := range o.AllowedHeaders {
+		names[http.CanonicalHeaderKey(name)] = struct{}{}
+	}
+	var parameters openapi3.Parameters
+	if route.PathItem != nil {
+		parameters = append(parameters, route.PathItem.Parameters...)
+	}
+	if operation := route.Operation; operation != nil {
+		parameters = append(parameters, operation.Parameters...)
+		if operation.RequestBody != nil {
+			names[headerCT] = struct{}{}
+		}
+	}
+	for _, parameter := range parameters {
+		if p := parameter.Value; p != nil && p.In == openapi3.ParameterInHeader {
+			names[http.CanonicalHeaderKey(p.Name)] = struct{}{}
+		}
+	}
+
+	headers := make([]string, 0, len(names))
+	for name := range names {
+		headers = append(headers, name)
+	}
+	sort.Strings(headers)
+	return headers
+}
+
+// validateResponse checks the CORS headers of the response of input,
+// when its request has an Origin header.
+func (o *CORSOptions) validateResponse(input *ResponseValidationInput) error {
+	origin := input.RequestValidationInput.Request.Header.Get(headerOrigin)
+	if origin == "" {
+		return nil
+	}
+	fail := func(reason string) error {
+		return &ResponseError{Input: input, Reason: reason, kind: ErrCORSViolation}
+	}
+
+	allowOrigin := input.Header.Get(headerAccessControlAllowOrigin)
+	if !o.allowsOrigin(origin) {
+		if allowOrigin == "*" || strings.EqualFold(allowOrigin, origin) {
+			return fail(fmt.Sprintf("response header %s allows origin %q which is not allowed", headerAccessControlAllowOrigin, origin))
+		}
+		return nil
+	}
+
+	switch {
+	case allowOrigin == "":
+		return fail(fmt.Sprintf("response header %s is missing for origin %q", headerAccessControlAllowOrigin, origin))
+	case allowOrigin != "*" && !strings.EqualFold(allowOrigin, origin):
+		return fail(fmt.Sprintf("response header %s has unexpected value: %q", headerAccessControlAllowOrigin, allowOrigin))
+	}
+	if o.AllowCredentials {
+		if allowOrigin == "*" {
+			return fail(fmt.Sprintf("response header %s must name the origin when allowing credentials", headerAccessControlAllowOrigin))
+		}
+		if input.Header.Get(headerAccessControlAllowCredentials) != "true" {
+			return fail(fmt.Sprintf("response header %s must be %q", headerAccessControlAllowCredentials, "true"))
+		}
+	}
+
+	exposed := make(map[string]struct{})
+	for _, value := range input.Header.Values(headerAccessControlExposeHeaders) {
+		for _, name := range strings.Split(value, ",") {
+			exposed[http.CanonicalHeaderKey(strings.TrimSpace(name))] = struct{}{}
+		}
+	}
+	if _, ok := exposed["*"]; ok && !o.AllowCredentials {
+		return nil
+	}
+	for _, name := range o.ExposedHeaders {
+		if _, ok := exposed[http.CanonicalHeaderKey(name)]; !ok {
+			return fail(fmt.Sprintf("response header %s does not expose %q", headerAccessControlExposeHeaders, name))
+		}
+	}
+	return nil
+}
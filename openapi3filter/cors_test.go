@@ -0,0 +1,191 @@
+package openapi3filter_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+const corsSpec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      parameters:
+      - {name: x-request-id, in: header, schema: {type: string}}
+      responses: {'200': {description: Pets}}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {type: object}
+      responses: {'201': {description: Created}}
+`
+
+func TestCORSPreflight(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(corsSpec))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	called := false
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	})
+	preflight := func(v *openapi3filter.Validator, origin, method, path string) *httptest.ResponseRecorder {
+		called = false
+		r := httptest.NewRequest(http.MethodOptions, path, nil)
+		r.Header.Set("Origin", origin)
+		r.Header.Set("Access-Control-Request-Method", method)
+		w := httptest.NewRecorder()
+		v.Middleware(handler).ServeHTTP(w, r)
+		return w
+	}
+
+	// Passed on to the handler, although no OPTIONS operation is declared
+	v := openapi3filter.NewValidator(router, openapi3filter.CORS(openapi3filter.CORSOptions{}))
+	w := preflight(v, "https://app.example.com", http.MethodPost, "/pets")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.True(t, called)
+
+	v = openapi3filter.NewValidator(router, openapi3filter.CORS(openapi3filter.CORSOptions{
+		HandlePreflight:  true,
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedHeaders:   []string{"authorization"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}))
+
+	w = preflight(v, "https://app.example.com", http.MethodPost, "/pets")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.False(t, called)
+	require.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "GET, POST", w.Header().Get("Access-Control-Allow-Methods"))
+	require.Equal(t, "Authorization, Content-Type", w.Header().Get("Access-Control-Allow-Headers"))
+	require.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	require.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+
+	w = preflight(v, "https://app.example.com", http.MethodGet, "/pets")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Equal(t, "Authorization, X-Request-Id", w.Header().Get("Access-Control-Allow-Headers"))
+
+	w = preflight(v, "https://evil.example.com", http.MethodGet, "/pets")
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	w = preflight(v, "https://app.example.com", http.MethodDelete, "/pets")
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	require.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	require.False(t, called)
+}
+
+func TestCORSResponseHeaders(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(corsSpec))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	options := openapi3filter.CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		ExposedHeaders:   []string{"X-Total-Count"},
+		AllowCredentials: true,
+	}
+
+	for _, tc := range []struct {
+		name   string
+		origin string
+		header map[string]string
+		err    string
+	}{
+		{
+			name: "same origin",
+		},
+		{
+			name:   "allowed",
+			origin: "https://app.example.com",
+			header: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "x-total-count, ETag",
+			},
+		},
+		{
+			name:   "missing allow origin",
+			origin: "https://app.example.com",
+			err:    `response header Access-Control-Allow-Origin is missing for origin "https://app.example.com"`,
+		},
+		{
+			name:   "wildcard with credentials",
+			origin: "https://app.example.com",
+			header: map[string]string{"Access-Control-Allow-Origin": "*"},
+			err:    `response header Access-Control-Allow-Origin must name the origin when allowing credentials`,
+		},
+		{
+			name:   "missing credentials",
+			origin: "https://app.example.com",
+			header: map[string]string{"Access-Control-Allow-Origin": "https://app.example.com"},
+			err:    `response header Access-Control-Allow-Credentials must be "true"`,
+		},
+		{
+			name:   "missing exposed header",
+			origin: "https://app.example.com",
+			header: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Credentials": "true",
+			},
+			err: `response header Access-Control-Expose-Headers does not expose "X-Total-Count"`,
+		},
+		{
+			name:   "disallowed origin",
+			origin: "https://evil.example.com",
+		},
+		{
+			name:   "disallowed origin allowed",
+			origin: "https://evil.example.com",
+			header: map[string]string{"Access-Control-Allow-Origin": "https://evil.example.com"},
+			err:    `response header Access-Control-Allow-Origin allows origin "https://evil.example.com" which is not allowed`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var violation error
+			v := openapi3filter.NewValidator(router,
+				openapi3filter.CORS(options),
+				openapi3filter.Strict(true),
+				openapi3filter.OnLog(func(string, error) {}),
+				openapi3filter.OnViolation(func(_ *http.Request, _ openapi3filter.ErrCode, err error) { violation = err }),
+			)
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for name, value := range tc.header {
+					w.Header().Set(name, value)
+				}
+				w.WriteHeader(http.StatusOK)
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/pets", nil)
+			if tc.origin != "" {
+				r.Header.Set("Origin", tc.origin)
+			}
+			w := httptest.NewRecorder()
+			v.Middleware(handler).ServeHTTP(w, r)
+
+			if tc.err == "" {
+				require.NoError(t, violation)
+				require.Equal(t, http.StatusOK, w.Code)
+				return
+			}
+			require.EqualError(t, violation, tc.err)
+			require.True(t, errors.Is(violation, openapi3filter.ErrCORSViolation))
+			require.Equal(t, http.StatusInternalServerError, w.Code)
+		})
+	}
+}
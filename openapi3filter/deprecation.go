@@ -0,0 +1,98 @@
+package openapi3filter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// DeprecationFunc is called by request validation when a request uses
+// a deprecated operation, parameter or body property.
+// With Options.Concurrency above 1 it may be called concurrently.
+type DeprecationFunc func(ctx context.Context, warning *DeprecationWarning)
+
+// DeprecationWarning describes the usage of a deprecated part of the API by a request.
+// It does not make the request invalid.
+type DeprecationWarning struct {
+	Input *RequestValidationInput
+	// Parameter is the deprecated parameter set by the request, if any.
+	Parameter *openapi3.Parameter
+	// Property is the JSON pointer of the deprecated property set in the body of the request, if any.
+	// Otherwise, when Parameter is nil too, the operation of the request is deprecated.
+	Property string
+}
+
+func (w *DeprecationWarning) String() string {
+	var what string
+	switch {
+	case w.Parameter != nil:
+		what = fmt.Sprintf("parameter %q in %s", w.Parameter.Name, w.Parameter.In)
+	case w.Property != "":
+		what = fmt.Sprintf("request body property %q", w.Property)
+	default:
+		what = "operation"
+	}
+	if route := w.Input.Route; route != nil {
+		return fmt.Sprintf("%s of %s %s is deprecated", what, route.Method, route.Path)
+	}
+	return what + " is deprecated"
+}
+
+// deprecatedProperties returns the JSON pointers of the properties set in value
+// which schema marks as deprecated, in order.
+func deprecatedProperties(schema *openapi3.Schema, value interface{}) []string {
+	found := make(map[string]struct{})
+	findDeprecatedProperties(schema, value, "", found)
+	pointers := make([]string, 0, len(found))
+	for pointer := range found {
+		pointers = append(pointers, pointer)
+	}
+	sort.Strings(pointers)
+	return pointers
+}
+
+func findDeprecatedProperties(schema *openapi3.Schema, value interface{}, pointer string, found map[string]struct{}) {
+	if schema == nil {
+		return
+	}
+	for _, refs := range []openapi3.SchemaRefs{schema.AllOf, schema.AnyOf, schema.OneOf} {
+		for _, ref := range refs {
+			if ref != nil {
+				findDeprecatedProperties(ref.Value, value, pointer, found)
+			}
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for name, v := range value {
+			var property *openapi3.SchemaRef
+			if property = schema.Properties.Value(name); property == nil {
+				property = schema.AdditionalProperties
+			}
+			if property == nil || property.Value == nil {
+				continue
+			}
+			propertyPointer := pointer + "/" + escapeJSONPointer(name)
+			if property.Value.Deprecated {
+				found[propertyPointer] = struct{}{}
+			}
+			findDeprecatedProperties(property.Value, v, propertyPointer, found)
+		}
+	case []interface{}:
+		if schema.Items == nil {
+			return
+		}
+		for i, v := range value {
+			findDeprecatedProperties(schema.Items.Value, v, pointer+"/"+strconv.Itoa(i), found)
+		}
+	}
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
@@ -0,0 +1,253 @@
+package openapi3filter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// ErrorClass is what the classify stage of an ErrorPipeline tells of an error.
+type ErrorClass struct {
+	// Status is the HTTP status code of the response to the error.
+	Status int
+	// Code is the ErrorCode constant of the category of the error, e.g. ErrorCodeSchemaMismatch.
+	Code string
+}
+
+// ErrorClassifier is the classify stage of an ErrorPipeline. See ClassifyError.
+type ErrorClassifier func(err error) ErrorClass
+
+// ErrorStructurer is the structure stage of an ErrorPipeline,
+// describing an error of the given class. See StructureError.
+type ErrorStructurer func(err error, class ErrorClass) *ValidationError
+
+// ErrorFormatter is the format stage of an ErrorPipeline, writing the response
+// of the given status code describing errs. See FormatErrorsJSON.
+type ErrorFormatter func(w http.ResponseWriter, status int, errs []*ValidationError)
+
+// ErrorPipeline encodes the errors of routing and validation as HTTP responses
+// in three stages, each of which can be replaced:
+//
+//  1. classify tells the status code and the category of each error,
+//  2. structure describes each error as a ValidationError,
+//  3. format writes the response describing all errors.
+//
+// The errors gathered with Options.MultiError, including the schema errors of a single
+// parameter or body, go through the first two stages one by one.
+// The status code of the response is the one shared by all errors,
+// or else 400 for client errors and 500 otherwise.
+//
+// Validator uses DefaultErrorPipeline unless OnErr is set.
+type ErrorPipeline struct {
+	classify  ErrorClassifier
+	structure ErrorStructurer
+	format    ErrorFormatter
+}
+
+// ErrorPipelineOption configures an ErrorPipeline.
+type ErrorPipelineOption func(*ErrorPipeline)
+
+// WithErrorClassifier replaces the classify stage, ClassifyError by default.
+func WithErrorClassifier(classify ErrorClassifier) ErrorPipelineOption {
+	return func(p *ErrorPipeline) { p.classify = classify }
+}
+
+// WithErrorStructurer replaces the structure stage, StructureError by default.
+func WithErrorStructurer(structure ErrorStructurer) ErrorPipelineOption {
+	return func(p *ErrorPipeline) { p.structure = structure }
+}
+
+// WithErrorFormatter replaces the format stage, FormatErrorsJSON by default.
+func WithErrorFormatter(format ErrorFormatter) ErrorPipelineOption {
+	return func(p *ErrorPipeline) { p.format = format }
+}
+
+// NewErrorPipeline returns an ErrorPipeline made of the default stages but those replaced by opts.
+func NewErrorPipeline(opts ...ErrorPipelineOption) *ErrorPipeline {
+	p := &ErrorPipeline{
+		classify:  ClassifyError,
+		structure: StructureError,
+		format:    FormatErrorsJSON,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// DefaultErrorPipeline is the ErrorPipeline made of the default stages.
+var DefaultErrorPipeline = NewErrorPipeline()
+
+// Errors returns the classified and structured errors of err, one for each error
+// gathered with Options.MultiError.
+func (p *ErrorPipeline) Errors(err error) []*ValidationError {
+	errs := flattenErrors(err, nil)
+	vErrs := make([]*ValidationError, 0, len(errs))
+	for _, err := range errs {
+		vErrs = append(vErrs, p.structure(err, p.classify(err)))
+	}
+	return vErrs
+}
+
+// Encode is an ErrorEncoder writing the response to err.
+func (p *ErrorPipeline) Encode(_ context.Context, err error, w http.ResponseWriter) {
+	vErrs := p.Errors(err)
+	p.format(w, CombinedStatus(vErrs), vErrs)
+}
+
+// WriteErr is an ErrFunc writing the response to err, for OnErr.
+// The status code of the response is the one the pipeline classifies the error with.
+func (p *ErrorPipeline) WriteErr(w http.ResponseWriter, _ int, _ ErrCode, err error) {
+	p.Encode(context.Background(), err, w)
+}
+
+// ClassifyError is the default classify stage of ErrorPipeline.
+//
+// The status codes of request errors are those of ValidationErrorEncoder, e.g. 422
+// for bodies that do not match their schema. Routing errors have status 404 or 405,
+// unmet security requirements status 401 and other errors status 500,
+// unless they implement StatusCoder.
+// The code is the most specific one of ErrorJSON, or else ErrorCodeInternal.
+func ClassifyError(err error) ErrorClass {
+	class := ErrorClass{Status: http.StatusInternalServerError, Code: ErrorCodeInternal}
+
+	switch e := err.(type) {
+	case *routers.RouteError:
+		class.Code = ErrorCodeRouteNotFound
+		if e.Error() == routers.ErrMethodNotAllowed.Error() {
+			class.Code = ErrorCodeMethodNotAllowed
+		}
+	case *RequestError, *ResponseError, *SecurityRequirementsError, *ParseError:
+		class.Code = causeJSON(err, ErrorCodeInternal).Code
+	}
+
+	var securityErr *SecurityRequirementsError
+	var statusCoder StatusCoder
+	if vErr := convertError(err); vErr != nil && vErr.Status != 0 {
+		class.Status = vErr.Status
+	} else if errors.As(err, &securityErr) {
+		class.Status = http.StatusUnauthorized
+	} else if _, ok := err.(*RequestError); ok {
+		class.Status = http.StatusBadRequest
+	} else if errors.As(err, &statusCoder) && statusCoder.StatusCode() != 0 {
+		class.Status = statusCoder.StatusCode()
+	}
+	return class
+}
+
+// StructureError is the default structure stage of ErrorPipeline.
+//
+// Request and routing errors are described as by ValidationErrorEncoder, other errors
+// by their message, with the status and the code of their class. The details of server
+// errors (of status 5xx), such as invalid responses, are not disclosed:
+// their title is the status text.
+func StructureError(err error, class ErrorClass) *ValidationError {
+	vErr, ok := err.(*ValidationError)
+	if ok {
+		copied := *vErr
+		vErr = &copied
+	} else if vErr = convertError(err); vErr == nil {
+		vErr = &ValidationError{Title: err.Error()}
+	}
+	vErr.Status = class.Status
+	if vErr.Code == "" {
+		vErr.Code = class.Code
+	}
+	if class.Status >= http.StatusInternalServerError {
+		vErr.Title, vErr.Detail, vErr.Source = http.StatusText(class.Status), "", nil
+	}
+	return vErr
+}
+
+// FormatErrorsJSON is the default format stage of ErrorPipeline,
+// writing a JSON object with the errors as its "errors" member.
+func FormatErrorsJSON(w http.ResponseWriter, status int, errs []*ValidationError) {
+	body, _ := json.Marshal(struct {
+		Errors []*ValidationError `json:"errors"`
+	}{errs})
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// FormatErrorsJSONAPI is a format stage of ErrorPipeline writing a JSON:API document.
+func FormatErrorsJSONAPI(w http.ResponseWriter, status int, errs []*ValidationError) {
+	objects := make([]*JSONAPIError, 0, len(errs))
+	for _, vErr := range errs {
+		objects = append(objects, toJSONAPIError(vErr))
+	}
+	body, _ := json.Marshal(struct {
+		Errors []*JSONAPIError `json:"errors"`
+	}{objects})
+	w.Header().Set("Content-Type", JSONAPIMediaType)
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// FormatErrorsText is a format stage of ErrorPipeline writing the title of each error,
+// followed by its detail if any, on a line of plain text.
+func FormatErrorsText(w http.ResponseWriter, status int, errs []*ValidationError) {
+	var b strings.Builder
+	for _, vErr := range errs {
+		b.WriteString(vErr.Title)
+		if vErr.Detail != "" {
+			b.WriteString(": ")
+			b.WriteString(vErr.Detail)
+		}
+		b.WriteString("\n")
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	w.Write([]byte(b.String()))
+}
+
+// CombinedStatus returns the status code of the response to errs, as passed
+// to the format stage of ErrorPipeline: the status code shared by all errors,
+// or else 400 for client errors and 500 otherwise.
+func CombinedStatus(errs []*ValidationError) int {
+	statuses := make([]int, 0, len(errs))
+	for _, vErr := range errs {
+		statuses = append(statuses, vErr.Status)
+	}
+	return combinedStatus(statuses)
+}
+
+// combinedStatus returns the status code shared by all statuses,
+// or else 400 for client errors and 500 otherwise.
+func combinedStatus(statuses []int) int {
+	status := 0
+	for _, s := range statuses {
+		switch {
+		case status == 0:
+			status = s
+		case status != s && status < 500 && s >= 400 && s < 500:
+			status = http.StatusBadRequest
+		case status != s:
+			status = http.StatusInternalServerError
+		}
+	}
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	return status
+}
+
+func toJSONAPIError(vErr *ValidationError) *JSONAPIError {
+	object := &JSONAPIError{
+		ID:     vErr.Id,
+		Code:   vErr.Code,
+		Title:  vErr.Title,
+		Detail: vErr.Detail,
+		Source: vErr.Source,
+	}
+	if vErr.Status != 0 {
+		object.Status = strconv.Itoa(vErr.Status)
+	}
+	return object
+}
@@ -0,0 +1,141 @@
+package openapi3filter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+func TestErrorPipeline(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      parameters:
+      - {name: limit, in: query, schema: {type: integer, maximum: 10}}
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+      responses:
+        "201": {description: Created}
+`
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	validate := func(method, target, body string) error {
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		r.Header.Set(headerCT, "application/json")
+		route, pathParams, err := router.FindRoute(r)
+		if err != nil {
+			return err
+		}
+		return ValidateRequest(context.Background(), &RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    &Options{MultiError: true},
+		})
+	}
+
+	t.Run("classify", func(t *testing.T) {
+		for _, test := range []struct {
+			err   error
+			class ErrorClass
+		}{
+			{routers.ErrPathNotFound, ErrorClass{http.StatusNotFound, ErrorCodeRouteNotFound}},
+			{routers.ErrMethodNotAllowed, ErrorClass{http.StatusMethodNotAllowed, ErrorCodeMethodNotAllowed}},
+			{&RequestError{Err: ErrInvalidRequired}, ErrorClass{http.StatusBadRequest, ErrorCodeMissingRequired}},
+			{&SecurityRequirementsError{Errors: []error{errors.New("no token")}}, ErrorClass{http.StatusUnauthorized, ErrorCodeSecurityRequirements}},
+			{&ResponseError{Reason: "status is not supported"}, ErrorClass{http.StatusInternalServerError, ErrorCodeInvalidResponse}},
+			{&ValidationError{Status: http.StatusTeapot}, ErrorClass{http.StatusTeapot, ErrorCodeInternal}},
+			{errors.New("database is down"), ErrorClass{http.StatusInternalServerError, ErrorCodeInternal}},
+		} {
+			require.Equal(t, test.class, ClassifyError(test.err), test.err.Error())
+		}
+	})
+
+	t.Run("default stages", func(t *testing.T) {
+		err := validate(http.MethodPost, "/pets?limit=20", `{}`)
+		require.Error(t, err)
+		require.Equal(t, []*ValidationError{{
+			Status: http.StatusBadRequest,
+			Code:   ErrorCodeSchemaMismatch,
+			Title:  "number must be at most 10",
+			Source: &ValidationErrorSource{Parameter: "limit"},
+		}, {
+			Status: http.StatusUnprocessableEntity,
+			Code:   ErrorCodeSchemaMismatch,
+			Title:  `property "name" is missing`,
+			Source: &ValidationErrorSource{Pointer: "/name"},
+		}}, DefaultErrorPipeline.Errors(err))
+
+		w := httptest.NewRecorder()
+		DefaultErrorPipeline.Encode(context.Background(), err, w)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		require.JSONEq(t, `{"errors": [
+			{"status": 400, "code": "schema_mismatch", "title": "number must be at most 10", "source": {"parameter": "limit"}},
+			{"status": 422, "code": "schema_mismatch", "title": "property \"name\" is missing", "source": {"pointer": "/name"}}
+		]}`, w.Body.String())
+
+		// Server errors are not disclosed
+		w = httptest.NewRecorder()
+		DefaultErrorPipeline.Encode(context.Background(), &ResponseError{Reason: "status is not supported"}, w)
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.JSONEq(t, `{"errors": [{"status": 500, "code": "invalid_response", "title": "Internal Server Error"}]}`, w.Body.String())
+	})
+
+	t.Run("replaced stages", func(t *testing.T) {
+		pipeline := NewErrorPipeline(
+			WithErrorClassifier(func(err error) ErrorClass {
+				class := ClassifyError(err)
+				if class.Status == http.StatusUnprocessableEntity {
+					class.Status = http.StatusBadRequest
+				}
+				return class
+			}),
+			WithErrorStructurer(func(err error, class ErrorClass) *ValidationError {
+				vErr := StructureError(err, class)
+				vErr.Id = "req-1"
+				return vErr
+			}),
+			WithErrorFormatter(FormatErrorsText),
+		)
+
+		err := validate(http.MethodPost, "/pets?limit=20", `{}`)
+		w := httptest.NewRecorder()
+		pipeline.Encode(context.Background(), err, w)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+		require.Equal(t, "number must be at most 10\nproperty \"name\" is missing\n", w.Body.String())
+		for _, vErr := range pipeline.Errors(err) {
+			require.Equal(t, "req-1", vErr.Id)
+		}
+
+		pipeline = NewErrorPipeline(WithErrorFormatter(FormatErrorsJSONAPI))
+		w = httptest.NewRecorder()
+		pipeline.WriteErr(w, http.StatusBadRequest, ErrCodeCannotFindRoute, validate(http.MethodGet, "/pets", ""))
+		require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		require.Equal(t, JSONAPIMediaType, w.Header().Get("Content-Type"))
+		require.JSONEq(t, `{"errors": [{"status": "405", "code": "method_not_allowed", "title": "method not allowed"}]}`, w.Body.String())
+	})
+}
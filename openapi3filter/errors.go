@@ -2,11 +2,44 @@ package openapi3filter
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
+// Sentinel errors of the main categories of validation failures, besides ErrInvalidRequired
+// and ErrInvalidEmptyValue. The errors returned by validation match them with errors.Is:
+//
+//	if errors.Is(err, openapi3filter.ErrSchemaMismatch) {
+//		// The value of a parameter, header or body does not match its schema
+//	}
+//
+// The details of errors are available with errors.As, e.g. as *openapi3.SchemaError,
+// *ParseError, *SecurityRequirementsError or *RequestError.
+var (
+	// ErrDecodingFailed is matched by errors about values that cannot be decoded,
+	// such as malformed parameters or bodies.
+	ErrDecodingFailed = errors.New("value could not be decoded")
+	// ErrSchemaMismatch is matched by errors about values that do not match their schema.
+	ErrSchemaMismatch = errors.New("value does not match its schema")
+	// ErrContentTypeNotDeclared is matched by errors about bodies whose content type is not declared.
+	ErrContentTypeNotDeclared = errors.New("content type is not declared")
+	// ErrBodyReadFailed is matched by errors about bodies that cannot be read.
+	ErrBodyReadFailed = errors.New("body could not be read")
+	// ErrStatusNotDeclared is matched by errors about responses whose status is not declared
+	// (see Options.IncludeResponseStatus).
+	ErrStatusNotDeclared = errors.New("response status is not declared")
+	// ErrAuthenticationFailed is matched by errors returned by AuthenticationInput.NewError.
+	ErrAuthenticationFailed = errors.New("authentication failed")
+	// ErrSecurityRequirementsFailed is matched by SecurityRequirementsError.
+	ErrSecurityRequirementsFailed = errors.New("security requirements failed")
+	// ErrCORSViolation is matched by errors about responses whose CORS headers
+	// do not match the CORSOptions of a Validator.
+	ErrCORSViolation = errors.New("CORS headers do not match the options")
+)
+
 var _ error = &RequestError{}
 
 // RequestError is returned by ValidateRequest when request does not match OpenAPI spec
@@ -16,6 +49,9 @@ type RequestError struct {
 	RequestBody *openapi3.RequestBody
 	Reason      string
 	Err         error
+
+	// kind is the sentinel error of the category of the error, if any
+	kind error
 }
 
 var _ interface{ Unwrap() error } = RequestError{}
@@ -42,6 +78,23 @@ func (err RequestError) Unwrap() error {
 	return err.Err
 }
 
+// Is reports whether target is the sentinel error of the category of err, e.g. ErrSchemaMismatch.
+func (err *RequestError) Is(target error) bool {
+	return err.kind != nil && err.kind == target
+}
+
+// MarshalJSON encodes the error for API responses (see ErrorJSON).
+func (err *RequestError) MarshalJSON() ([]byte, error) {
+	object := &ErrorJSON{Code: ErrorCodeInvalidRequest, Detail: err.Reason}
+	if v := err.Parameter; v != nil {
+		object.In, object.Name = v.In, v.Name
+	} else if err.RequestBody != nil {
+		object.In = "body"
+	}
+	object.setCauses(err.Err)
+	return json.Marshal(object)
+}
+
 var _ error = &ResponseError{}
 
 // ResponseError is returned by ValidateResponse when response does not match OpenAPI spec
@@ -49,6 +102,9 @@ type ResponseError struct {
 	Input  *ResponseValidationInput
 	Reason string
 	Err    error
+
+	// kind is the sentinel error of the category of the error, if any
+	kind error
 }
 
 var _ interface{ Unwrap() error } = ResponseError{}
@@ -69,6 +125,18 @@ func (err ResponseError) Unwrap() error {
 	return err.Err
 }
 
+// Is reports whether target is the sentinel error of the category of err, e.g. ErrSchemaMismatch.
+func (err *ResponseError) Is(target error) bool {
+	return err.kind != nil && err.kind == target
+}
+
+// MarshalJSON encodes the error for API responses (see ErrorJSON).
+func (err *ResponseError) MarshalJSON() ([]byte, error) {
+	object := &ErrorJSON{Code: ErrorCodeInvalidResponse, Detail: err.Reason}
+	object.setCauses(err.Err)
+	return json.Marshal(object)
+}
+
 var _ error = &SecurityRequirementsError{}
 
 // SecurityRequirementsError is returned by ValidateSecurityRequirements
@@ -90,3 +158,118 @@ func (err *SecurityRequirementsError) Error() string {
 
 	return buff.String()
 }
+
+// Is reports whether target is ErrSecurityRequirementsFailed or matches
+// any of the errors of the requirements that are not met.
+func (err *SecurityRequirementsError) Is(target error) bool {
+	if target == ErrSecurityRequirementsFailed {
+		return true
+	}
+	for _, e := range err.Errors {
+		if errors.Is(e, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As sets target to the first error of the requirements that are not met
+// which matches it, like errors.As.
+func (err *SecurityRequirementsError) As(target interface{}) bool {
+	for _, e := range err.Errors {
+		if errors.As(e, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON encodes the error for API responses (see ErrorJSON),
+// with the errors of the requirements that are not met as causes.
+func (err *SecurityRequirementsError) MarshalJSON() ([]byte, error) {
+	object := &ErrorJSON{Code: ErrorCodeSecurityRequirements, Detail: "security requirements failed"}
+	for _, e := range err.Errors {
+		object.Errors = append(object.Errors, causeJSON(e, ErrorCodeSecurityRequirements))
+	}
+	return json.Marshal(object)
+}
+
+// Codes of the JSON encodings of errors
+const (
+	ErrorCodeInvalidRequest       = "invalid_request"
+	ErrorCodeInvalidResponse      = "invalid_response"
+	ErrorCodeMissingRequired      = "missing_required"
+	ErrorCodeEmptyValue           = "empty_value"
+	ErrorCodeInvalidFormat        = "invalid_format"
+	ErrorCodeBodyTooComplex       = "body_too_complex"
+	ErrorCodeSchemaMismatch       = openapi3.SchemaErrorCode
+	ErrorCodeSecurityRequirements = "security_requirements"
+
+	// Codes ClassifyError gives to routing and other errors, besides those above
+	ErrorCodeRouteNotFound    = "route_not_found"
+	ErrorCodeMethodNotAllowed = "method_not_allowed"
+	ErrorCodeInternal         = "internal_error"
+)
+
+// ErrorJSON is the JSON encoding of RequestError, ResponseError, SecurityRequirementsError
+// and ParseError, whose fields are stable so they can be embedded in response bodies.
+type ErrorJSON struct {
+	// Code is one of the ErrorCode constants, the most specific one known.
+	Code string `json:"code"`
+	// In is the location of the invalid parameter ("query", "header", "path" or "cookie"),
+	// or "body".
+	In string `json:"in,omitempty"`
+	// Name is the name of the invalid parameter.
+	Name string `json:"name,omitempty"`
+	// Keyword is the schema keyword the value violates, e.g. "maxLength".
+	Keyword string `json:"keyword,omitempty"`
+	// Pointer is the JSON pointer to the invalid value within the parameter or body.
+	Pointer string `json:"pointer,omitempty"`
+	// Schema names the schema the value does not match. See openapi3.SchemaError.SchemaName.
+	Schema string `json:"schema,omitempty"`
+	Detail string `json:"detail"`
+	// Errors are the causes of the error, when there are several.
+	Errors []*ErrorJSON `json:"errors,omitempty"`
+}
+
+// setCauses merges the single cause err into object, or sets the causes of a multi-error.
+func (object *ErrorJSON) setCauses(err error) {
+	if err == nil {
+		return
+	}
+	if me, ok := err.(openapi3.MultiError); ok && len(me) != 1 {
+		for _, e := range me {
+			cause := causeJSON(e, object.Code)
+			cause.In, cause.Name = object.In, object.Name
+			object.Errors = append(object.Errors, cause)
+		}
+		return
+	} else if ok {
+		err = me[0]
+	}
+	cause := causeJSON(err, object.Code)
+	object.Code, object.Keyword, object.Pointer = cause.Code, cause.Keyword, cause.Pointer
+	object.Schema = cause.Schema
+	object.Detail, object.Errors = cause.Detail, cause.Errors
+}
+
+// causeJSON returns the JSON encoding of err, with code unless a more specific one is known.
+func causeJSON(err error, code string) *ErrorJSON {
+	switch {
+	case errors.Is(err, ErrInvalidRequired):
+		return &ErrorJSON{Code: ErrorCodeMissingRequired, Detail: err.Error()}
+	case errors.Is(err, ErrInvalidEmptyValue):
+		return &ErrorJSON{Code: ErrorCodeEmptyValue, Detail: err.Error()}
+	case errors.Is(err, ErrBodyTooComplex):
+		return &ErrorJSON{Code: ErrorCodeBodyTooComplex, Detail: err.Error()}
+	}
+	if m, ok := err.(json.Marshaler); ok {
+		if data, e := m.MarshalJSON(); e == nil {
+			var object ErrorJSON
+			if e = json.Unmarshal(data, &object); e == nil && object.Code != "" {
+				return &object
+			}
+		}
+	}
+	return &ErrorJSON{Code: code, Detail: openapi3.ErrorDetail(err)}
+}
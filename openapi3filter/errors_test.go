@@ -0,0 +1,158 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestErrorsMarshalJSON(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      parameters:
+      - {name: limit, in: query, required: true, schema: {type: integer, maximum: 10}}
+      - {name: ids, in: query, schema: {type: array, items: {type: integer}}}
+      - {name: X-Trace, in: header, required: true, schema: {type: string}}
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name: {type: string, maxLength: 3}
+                tags: {type: array, items: {type: string}}
+      responses:
+        '200': {description: OK}
+`
+	router := setupTestRouter(t, spec)
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/pets?limit=11&ids=1&ids=x", bytes.NewReader([]byte(`{"name": "Rexxx", "tags": ["a", 1]}`)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	route, pathParams, err := router.FindRoute(req)
+	require.NoError(t, err)
+	err = ValidateRequest(context.Background(), &RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    &Options{MultiError: true},
+	})
+	require.Error(t, err)
+
+	data, err := json.Marshal(err)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"code": "schema_mismatch", "in": "query", "name": "limit", "keyword": "maximum", "detail": "number must be at most 10"},
+		{"code": "invalid_format", "in": "query", "name": "ids", "pointer": "/1", "detail": "value x: an invalid integer: invalid syntax"},
+		{"code": "missing_required", "in": "header", "name": "X-Trace", "detail": "value is required but missing"},
+		{"code": "invalid_request", "in": "body", "detail": "doesn't match schema", "errors": [
+			{"code": "schema_mismatch", "in": "body", "keyword": "maxLength", "pointer": "/name", "detail": "maximum string length is 3"},
+			{"code": "schema_mismatch", "in": "body", "keyword": "type", "pointer": "/tags/1", "detail": "field must be set to string or not be present"}
+		]}
+	]`, string(data))
+}
+
+func TestSecurityRequirementsErrorMarshalJSON(t *testing.T) {
+	err := &SecurityRequirementsError{
+		SecurityRequirements: openapi3.SecurityRequirements{{"apiKey": {}}},
+		Errors:               []error{errors.New("missing API key")},
+	}
+	data, e := json.Marshal(err)
+	require.NoError(t, e)
+	require.JSONEq(t, `{"code": "security_requirements", "detail": "security requirements failed", "errors": [
+		{"code": "security_requirements", "detail": "missing API key"}
+	]}`, string(data))
+}
+
+func TestErrorsIsAs(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      parameters:
+      - {name: limit, in: query, schema: {type: integer, maximum: 10}}
+      - {name: ids, in: query, schema: {type: array, items: {type: integer}}}
+      requestBody:
+        content:
+          application/json:
+            schema: {type: object, properties: {name: {type: string}}}
+      responses:
+        '200': {description: OK}
+`
+	router := setupTestRouter(t, spec)
+	validate := func(query, contentType, body string) error {
+		req, err := http.NewRequest(http.MethodPost, "http://example.com/pets?"+query, bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		route, pathParams, err := router.FindRoute(req)
+		require.NoError(t, err)
+		err = ValidateRequest(context.Background(), &RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+		})
+		require.Error(t, err)
+		return fmt.Errorf("wrapped: %w", err)
+	}
+
+	err := validate("limit=11", "application/json", `{}`)
+	require.True(t, errors.Is(err, ErrSchemaMismatch))
+	require.False(t, errors.Is(err, ErrDecodingFailed))
+	var requestErr *RequestError
+	require.True(t, errors.As(err, &requestErr))
+	require.Equal(t, "limit", requestErr.Parameter.Name)
+	var schemaErr *openapi3.SchemaError
+	require.True(t, errors.As(err, &schemaErr))
+	require.Equal(t, "maximum", schemaErr.SchemaField)
+
+	err = validate("ids=x", "application/json", `{}`)
+	require.True(t, errors.Is(err, ErrDecodingFailed))
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+
+	err = validate("", "application/json", `{"name": 1}`)
+	require.True(t, errors.Is(err, ErrSchemaMismatch))
+	require.True(t, errors.As(err, &schemaErr))
+	require.Equal(t, "type", schemaErr.SchemaField)
+
+	err = validate("", "application/json", `{`)
+	require.True(t, errors.Is(err, ErrDecodingFailed))
+
+	err = validate("", "text/plain", `name`)
+	require.True(t, errors.Is(err, ErrContentTypeNotDeclared))
+
+	authErr := errors.New("missing API key")
+	err = fmt.Errorf("wrapped: %w", &SecurityRequirementsError{
+		SecurityRequirements: openapi3.SecurityRequirements{{"apiKey": {}}},
+		Errors:               []error{(&AuthenticationInput{}).NewError(authErr)},
+	})
+	require.True(t, errors.Is(err, ErrSecurityRequirementsFailed))
+	require.True(t, errors.Is(err, ErrAuthenticationFailed))
+	require.True(t, errors.Is(err, authErr))
+	require.True(t, errors.As(err, &requestErr))
+	require.Equal(t, "authorization failed", requestErr.Reason)
+}
+
+func TestCauseJSONSchemaErrorDetail(t *testing.T) {
+	schema := openapi3.NewObjectSchema().WithProperty("name", openapi3.NewStringSchema())
+	schemaErr := schema.VisitJSON(map[string]interface{}{"name": 42.0}, openapi3.SetSchemaErrorVerbosity(openapi3.SchemaErrorFull))
+	require.Contains(t, schemaErr.Error(), "\nSchema:\n")
+
+	cause := causeJSON(fmt.Errorf("decoding: %w", openapi3.MultiError{schemaErr}), ErrorCodeInvalidRequest)
+	require.Equal(t, ErrorCodeInvalidRequest, cause.Code)
+	require.Equal(t, "decoding: field must be set to string or not be present", cause.Detail)
+	require.NotContains(t, cause.Detail, "Schema:")
+}
@@ -0,0 +1,19 @@
+// Package fielderrors converts validation errors to field errors shaped as those of
+// github.com/go-playground/validator, for codebases already rendering validator.FieldError
+// values to users, so that both sources of validation errors share one response format.
+//
+// FieldError mirrors validator.FieldError, but for Translate, without depending
+// on the validator library:
+//
+//	if err := openapi3filter.ValidateRequest(ctx, input); err != nil {
+//		for _, fe := range fielderrors.FromError(err) {
+//			fmt.Println(fe.Namespace(), fe.Tag(), fe.Param()) // e.g. "body.pet.name max 20"
+//		}
+//	}
+//
+// The namespace of a field starts with the location of the value ("query", "header",
+// "path", "cookie" or "body"), followed by the name of the parameter and the path
+// of the value within it, e.g. "query.ids[1]" or "body.pet.tags[0]".
+// Schema keywords are mapped to the validator tags with the same meaning, e.g. maxLength
+// to "max" and minimum to "gte", while the others are tags as is, e.g. "pattern".
+package fielderrors
@@ -0,0 +1,256 @@
+package fielderrors
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// FieldError mirrors the validator.FieldError interface, but for Translate.
+type FieldError interface {
+	// Tag returns the validation tag that failed, e.g. "max".
+	Tag() string
+	// ActualTag returns the schema keyword the value violates, e.g. "maxLength",
+	// or Tag if the error is not about a schema keyword.
+	ActualTag() string
+	// Namespace returns the namespace of the field, e.g. "body.pet.name".
+	Namespace() string
+	// StructNamespace returns the namespace of the field, as Namespace.
+	StructNamespace() string
+	// Field returns the name of the field, e.g. "name" or "tags[0]".
+	Field() string
+	// StructField returns the name of the field, as Field.
+	StructField() string
+	// Value returns the invalid value, or nil if it is missing.
+	Value() interface{}
+	// Param returns the parameter of the tag, e.g. "20" for "max", if any.
+	Param() string
+	// Kind returns the kind of the value.
+	Kind() reflect.Kind
+	// Type returns the type of the value, or nil if it is missing.
+	Type() reflect.Type
+	// Error returns the message of the error, as validator.FieldError does.
+	Error() string
+}
+
+// ValidationErrors mirrors validator.ValidationErrors.
+type ValidationErrors []FieldError
+
+// Error returns the messages of the errors, one per line.
+func (ve ValidationErrors) Error() string {
+	messages := make([]string, 0, len(ve))
+	for _, fe := range ve {
+		messages = append(messages, fe.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+type fieldError struct {
+	tag       string
+	actualTag string
+	namespace string
+	field     string
+	value     interface{}
+	param     string
+}
+
+var _ FieldError = (*fieldError)(nil)
+
+func (fe *fieldError) Tag() string             { return fe.tag }
+func (fe *fieldError) ActualTag() string       { return fe.actualTag }
+func (fe *fieldError) Namespace() string       { return fe.namespace }
+func (fe *fieldError) StructNamespace() string { return fe.namespace }
+func (fe *fieldError) Field() string           { return fe.field }
+func (fe *fieldError) StructField() string     { return fe.field }
+func (fe *fieldError) Value() interface{}      { return fe.value }
+func (fe *fieldError) Param() string           { return fe.param }
+
+func (fe *fieldError) Kind() reflect.Kind {
+	if fe.value == nil {
+		return reflect.Invalid
+	}
+	return reflect.TypeOf(fe.value).Kind()
+}
+
+func (fe *fieldError) Type() reflect.Type {
+	if fe.value == nil {
+		return nil
+	}
+	return reflect.TypeOf(fe.value)
+}
+
+func (fe *fieldError) Error() string {
+	return fmt.Sprintf("Key: '%s' Error:Field validation for '%s' failed on the '%s' tag", fe.namespace, fe.field, fe.tag)
+}
+
+// FromError returns the field errors of err, as returned by openapi3filter.ValidateRequest:
+// one for each error about a parameter or the request body. Other errors, such as
+// routing or security errors, have no field: FromError returns nil when err has none.
+func FromError(err error) ValidationErrors {
+	var fieldErrs ValidationErrors
+	for _, err := range flatten(err, nil) {
+		reqErr, ok := err.(*openapi3filter.RequestError)
+		if !ok || errors.Is(reqErr, openapi3filter.ErrContentTypeNotDeclared) {
+			continue
+		}
+		var location []string
+		switch {
+		case reqErr.Parameter != nil:
+			location = []string{reqErr.Parameter.In, reqErr.Parameter.Name}
+		case reqErr.RequestBody != nil:
+			location = []string{"body"}
+		default:
+			continue
+		}
+		fieldErrs = append(fieldErrs, newFieldError(location, reqErr))
+	}
+	return fieldErrs
+}
+
+// flatten appends the errors err is made of to errs, with a RequestError
+// for each of the errors of a RequestError made of several ones.
+func flatten(err error, errs []error) []error {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		for _, err := range e {
+			errs = flatten(err, errs)
+		}
+		return errs
+	case *openapi3filter.RequestError:
+		if me, ok := e.Err.(openapi3.MultiError); ok {
+			for _, err := range me {
+				single := *e
+				single.Err = err
+				errs = flatten(&single, errs)
+			}
+			return errs
+		}
+	}
+	return append(errs, err)
+}
+
+func newFieldError(location []string, reqErr *openapi3filter.RequestError) *fieldError {
+	fe := &fieldError{tag: "invalid"}
+	var schemaErr *openapi3.SchemaError
+	var parseErr *openapi3filter.ParseError
+	switch {
+	case errors.Is(reqErr.Err, openapi3filter.ErrInvalidRequired), errors.Is(reqErr.Err, openapi3filter.ErrInvalidEmptyValue):
+		fe.tag = "required"
+	case errors.As(reqErr.Err, &schemaErr):
+		fe.tag, fe.param = schemaTag(schemaErr)
+		fe.actualTag = schemaErr.SchemaField
+		if schemaErr.SchemaField != "required" {
+			fe.value = schemaErr.Value
+		}
+		location = append(location, schemaErr.JSONPointer()...)
+	case errors.As(reqErr.Err, &parseErr):
+		fe.tag, fe.value = "type", parseErr.Value
+		if p := reqErr.Parameter; p != nil && p.Schema != nil && p.Schema.Value != nil {
+			fe.param = p.Schema.Value.Type
+		}
+		for _, token := range parseErr.Path() {
+			location = append(location, fmt.Sprint(token))
+		}
+	}
+	if fe.actualTag == "" {
+		fe.actualTag = fe.tag
+	}
+	fe.namespace, fe.field = namespace(location)
+	return fe
+}
+
+// schemaTag returns the validator tag of the keyword err is about, and its parameter.
+func schemaTag(err *openapi3.SchemaError) (string, string) {
+	schema := err.Schema
+	if schema == nil {
+		return err.SchemaField, ""
+	}
+	switch err.SchemaField {
+	case "required":
+		return "required", ""
+	case "minLength":
+		return "min", strconv.FormatUint(schema.MinLength, 10)
+	case "maxLength":
+		return "max", uint64Param(schema.MaxLength)
+	case "minItems":
+		return "min", strconv.FormatUint(schema.MinItems, 10)
+	case "maxItems":
+		return "max", uint64Param(schema.MaxItems)
+	case "minProperties":
+		return "min", strconv.FormatUint(schema.MinProps, 10)
+	case "maxProperties":
+		return "max", uint64Param(schema.MaxProps)
+	case "minimum":
+		return "gte", float64Param(schema.Min)
+	case "exclusiveMinimum":
+		return "gt", float64Param(schema.Min)
+	case "maximum":
+		return "lte", float64Param(schema.Max)
+	case "exclusiveMaximum":
+		return "lt", float64Param(schema.Max)
+	case "uniqueItems":
+		return "unique", ""
+	case "enum":
+		values := make([]string, 0, len(schema.Enum))
+		for _, value := range schema.Enum {
+			values = append(values, fmt.Sprint(value))
+		}
+		return "oneof", strings.Join(values, " ")
+	case "format":
+		switch schema.Format {
+		case "email", "uuid", "uri", "hostname", "ipv4", "ipv6":
+			return schema.Format, ""
+		case "date-time":
+			return "datetime", "2006-01-02T15:04:05Z07:00"
+		case "date":
+			return "datetime", "2006-01-02"
+		}
+		return schema.Format, ""
+	case "pattern":
+		return "pattern", schema.Pattern
+	case "type":
+		return "type", schema.Type
+	case "multipleOf":
+		return "multipleOf", float64Param(schema.MultipleOf)
+	}
+	return err.SchemaField, ""
+}
+
+func uint64Param(v *uint64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatUint(*v, 10)
+}
+
+func float64Param(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+// namespace returns the namespace of the field at path, e.g. "body.pet.tags[1]"
+// for ["body", "pet", "tags", "1"], and the name of the field, e.g. "tags[1]".
+func namespace(path []string) (string, string) {
+	var b strings.Builder
+	field := 0
+	for i, token := range path {
+		if _, err := strconv.Atoi(token); err == nil && i != 0 {
+			b.WriteString("[" + token + "]")
+			continue
+		}
+		if i != 0 {
+			b.WriteString(".")
+		}
+		field = b.Len()
+		b.WriteString(token)
+	}
+	ns := b.String()
+	return ns, ns[field:]
+}
@@ -0,0 +1,115 @@
+package fielderrors_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/openapi3filter/fielderrors"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      parameters:
+      - {name: limit, in: query, schema: {type: integer, maximum: 10}}
+      - {name: X-Request-Id, in: header, required: true, schema: {type: string, pattern: '^[0-9a-f]+$'}}
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [pet]
+              properties:
+                pet:
+                  type: object
+                  required: [name]
+                  properties:
+                    name: {type: string, maxLength: 5}
+                    kind: {type: string, enum: [cat, dog]}
+                    tags: {type: array, items: {type: string, minLength: 2}}
+      responses:
+        '201': {description: Created}
+`
+
+func TestFromError(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	validate := func(target string, header http.Header, body string) error {
+		r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		r.Header = header
+		r.Header.Set("Content-Type", "application/json")
+		route, pathParams, err := router.FindRoute(r)
+		if err != nil {
+			return err
+		}
+		return openapi3filter.ValidateRequest(context.Background(), &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    &openapi3filter.Options{MultiError: true},
+		})
+	}
+	type fieldError struct {
+		namespace, field, tag, actualTag, param string
+		value                                   interface{}
+		kind                                    reflect.Kind
+	}
+	fieldErrors := func(err error) []fieldError {
+		var fes []fieldError
+		for _, fe := range fielderrors.FromError(err) {
+			require.Equal(t, fe.Namespace(), fe.StructNamespace())
+			require.Equal(t, fe.Field(), fe.StructField())
+			fes = append(fes, fieldError{
+				namespace: fe.Namespace(),
+				field:     fe.Field(),
+				tag:       fe.Tag(),
+				actualTag: fe.ActualTag(),
+				param:     fe.Param(),
+				value:     fe.Value(),
+				kind:      fe.Kind(),
+			})
+		}
+		return fes
+	}
+
+	err = validate("/pets?limit=20", http.Header{"X-Request-Id": {"nope"}},
+		`{"pet": {"name": "Garfield", "kind": "fish", "tags": ["ok", "x"]}}`)
+	require.Equal(t, []fieldError{
+		{namespace: "query.limit", field: "limit", tag: "lte", actualTag: "maximum", param: "10", value: float64(20), kind: reflect.Float64},
+		{namespace: "header.X-Request-Id", field: "X-Request-Id", tag: "pattern", actualTag: "pattern", param: "^[0-9a-f]+$", value: "nope", kind: reflect.String},
+		{namespace: "body.pet.kind", field: "kind", tag: "oneof", actualTag: "enum", param: "cat dog", value: "fish", kind: reflect.String},
+		{namespace: "body.pet.name", field: "name", tag: "max", actualTag: "maxLength", param: "5", value: "Garfield", kind: reflect.String},
+		{namespace: "body.pet.tags[1]", field: "tags[1]", tag: "min", actualTag: "minLength", param: "2", value: "x", kind: reflect.String},
+	}, fieldErrors(err))
+
+	err = validate("/pets?limit=ten", http.Header{}, `{"pet": {}}`)
+	require.Equal(t, []fieldError{
+		{namespace: "query.limit", field: "limit", tag: "type", actualTag: "type", param: "integer", value: "ten", kind: reflect.String},
+		{namespace: "header.X-Request-Id", field: "X-Request-Id", tag: "required", actualTag: "required"},
+		{namespace: "body.pet.name", field: "name", tag: "required", actualTag: "required"},
+	}, fieldErrors(err))
+
+	fes := fielderrors.FromError(err)
+	require.EqualError(t, fes[1], "Key: 'header.X-Request-Id' Error:Field validation for 'X-Request-Id' failed on the 'required' tag")
+	require.Equal(t, fes[0].Error()+"\n"+fes[1].Error()+"\n"+fes[2].Error(), fes.Error())
+	require.Nil(t, fes[1].Type())
+
+	// Routing errors have no field
+	require.Nil(t, fielderrors.FromError(validate("/cats", http.Header{}, `{}`)))
+}
@@ -0,0 +1,168 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Extensions of the Encoding objects of multipart/form-data request bodies
+// that constrain the files uploaded as the properties they describe,
+// i.e. properties of type string and format binary (or arrays of those):
+//
+//	requestBody:
+//	  content:
+//	    multipart/form-data:
+//	      schema:
+//	        type: object
+//	        properties:
+//	          avatar: {type: string, format: binary}
+//	      encoding:
+//	        avatar:
+//	          contentType: image/png, image/jpeg
+//	          x-max-size: 1048576
+//	          x-filename-required: true
+//
+// The contentType of these Encoding objects lists the allowed content types of the files,
+// which may end with a wildcard (e.g. "image/*").
+const (
+	// ExtMaxFileSize sets the maximum size of files, in bytes.
+	// It takes precedence over Options.MaxFileSize.
+	ExtMaxFileSize = "x-max-size"
+	// ExtFilenameRequired makes files without a filename invalid.
+	ExtFilenameRequired = "x-filename-required"
+)
+
+// ErrFileTooLarge is returned when a file uploaded with multipart/form-data exceeds its maximum size.
+var ErrFileTooLarge = errors.New("file is too large")
+
+// ErrFileContentType is returned when a file uploaded with multipart/form-data has a content type
+// its Encoding object does not allow.
+var ErrFileContentType = errors.New("file content type is not allowed")
+
+// ErrFilenameRequired is returned when a file uploaded with multipart/form-data lacks a filename
+// while its Encoding object requires one.
+var ErrFilenameRequired = errors.New("file name is required")
+
+// fileConstraints are the constraints on the files of a property.
+type fileConstraints struct {
+	contentTypes     []string
+	maxSize          int64
+	filenameRequired bool
+}
+
+func (c *fileConstraints) allowsContentType(contentType string) bool {
+	if len(c.contentTypes) == 0 {
+		return true
+	}
+	mediaType := strings.ToLower(strings.TrimSpace(parseMediaType(contentType)))
+	for _, allowed := range c.contentTypes {
+		switch {
+		case allowed == "*/*", allowed == mediaType:
+			return true
+		case strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mediaType, allowed[:len(allowed)-1]):
+			return true
+		}
+	}
+	return false
+}
+
+// fileConstraintsOf returns the constraints on the files of the properties of the schema
+// of mediaType, by property name.
+func fileConstraintsOf(mediaType *openapi3.MediaType, options *Options) (map[string]*fileConstraints, error) {
+	schema := mediaType.Schema.Value
+	var constraints map[string]*fileConstraints
+	for name, property := range schema.Properties.Map() {
+		if property == nil || !isFileSchema(property.Value) {
+			continue
+		}
+		c := &fileConstraints{maxSize: options.MaxFileSize}
+		if enc := mediaType.Encoding[name]; enc != nil {
+			for _, contentType := range strings.Split(enc.ContentType, ",") {
+				if contentType = strings.ToLower(strings.TrimSpace(contentType)); contentType != "" {
+					c.contentTypes = append(c.contentTypes, strings.TrimSpace(parseMediaType(contentType)))
+				}
+			}
+			if _, err := enc.DecodeExtension(ExtMaxFileSize, &c.maxSize); err != nil {
+				return nil, fmt.Errorf("invalid %s of %s: %w", ExtMaxFileSize, name, err)
+			}
+			if _, err := enc.DecodeExtension(ExtFilenameRequired, &c.filenameRequired); err != nil {
+				return nil, fmt.Errorf("invalid %s of %s: %w", ExtFilenameRequired, name, err)
+			}
+		}
+		if len(c.contentTypes) == 0 && c.maxSize <= 0 && !c.filenameRequired {
+			continue
+		}
+		if constraints == nil {
+			constraints = make(map[string]*fileConstraints)
+		}
+		constraints[name] = c
+	}
+	return constraints, nil
+}
+
+func isFileSchema(schema *openapi3.Schema) bool {
+	if schema == nil {
+		return false
+	}
+	if schema.Type == "array" && schema.Items != nil {
+		schema = schema.Items.Value
+	}
+	return schema != nil && schema.Type == "string" && schema.Format == "binary"
+}
+
+// validateFileParts checks the files of the multipart/form-data body against the constraints
+// set by the Encoding objects of mediaType and by options.
+// It returns an error naming the form field of the first invalid file.
+func validateFileParts(body []byte, contentType string, mediaType *openapi3.MediaType, options *Options) error {
+	constraints, err := fileConstraintsOf(mediaType, options)
+	if err != nil || len(constraints) == 0 {
+		return err
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return err
+	}
+	mr := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := part.FormName()
+		c := constraints[name]
+		if c == nil {
+			continue
+		}
+
+		if c.filenameRequired && part.FileName() == "" {
+			return fmt.Errorf("part %s: %w", name, ErrFilenameRequired)
+		}
+		partContentType := part.Header.Get(headerCT)
+		if partContentType == "" {
+			partContentType = "application/octet-stream"
+		}
+		if !c.allowsContentType(partContentType) {
+			return fmt.Errorf("part %s: %w: %q is not one of %q", name, ErrFileContentType, partContentType, c.contentTypes)
+		}
+		if c.maxSize > 0 {
+			size, err := io.Copy(io.Discard, io.LimitReader(part, c.maxSize+1))
+			if err != nil {
+				return err
+			}
+			if size > c.maxSize {
+				return fmt.Errorf("part %s: %w: it exceeds %d bytes", name, ErrFileTooLarge, c.maxSize)
+			}
+		}
+	}
+}
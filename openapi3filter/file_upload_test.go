@@ -0,0 +1,126 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRequestBodyFileUploads(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              properties:
+                name: {type: string}
+                avatar: {type: string, format: binary}
+                photos: {type: array, items: {type: string, format: binary}}
+            encoding:
+              avatar:
+                contentType: image/png, image/jpeg
+                x-max-size: 8
+                x-filename-required: true
+              photos:
+                contentType: image/*
+      responses:
+        '200': {description: OK}
+`
+	router := setupTestRouter(t, spec)
+
+	type file struct {
+		field, filename, contentType, content string
+	}
+	validate := func(options *Options, files ...file) error {
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		require.NoError(t, w.WriteField("name", "Rex"))
+		for _, f := range files {
+			h := make(textproto.MIMEHeader)
+			disposition := `form-data; name="` + f.field + `"`
+			if f.filename != "" {
+				disposition += `; filename="` + f.filename + `"`
+			}
+			h.Set("Content-Disposition", disposition)
+			if f.contentType != "" {
+				h.Set("Content-Type", f.contentType)
+			}
+			part, err := w.CreatePart(h)
+			require.NoError(t, err)
+			_, err = part.Write([]byte(f.content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, w.Close())
+
+		req, err := http.NewRequest(http.MethodPost, "http://example.com/pets", &body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		route, pathParams, err := router.FindRoute(req)
+		require.NoError(t, err)
+		return ValidateRequest(context.Background(), &RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		})
+	}
+
+	avatar := file{field: "avatar", filename: "rex.png", contentType: "image/png", content: "png"}
+	photo := file{field: "photos", filename: "1.gif", contentType: "image/gif", content: strings.Repeat("gif", 10)}
+	require.NoError(t, validate(nil, avatar, photo, photo))
+
+	for _, tt := range []struct {
+		file    file
+		options *Options
+		want    error
+		message string
+	}{
+		{
+			file:    file{field: "avatar", filename: "rex.png", contentType: "image/png", content: "123456789"},
+			want:    ErrFileTooLarge,
+			message: "request body has an error: invalid file upload: part avatar: file is too large: it exceeds 8 bytes",
+		},
+		{
+			file:    file{field: "avatar", contentType: "image/png", content: "png"},
+			want:    ErrFilenameRequired,
+			message: "request body has an error: invalid file upload: part avatar: file name is required",
+		},
+		{
+			file:    file{field: "avatar", filename: "rex.gif", contentType: "image/gif", content: "gif"},
+			want:    ErrFileContentType,
+			message: `request body has an error: invalid file upload: part avatar: file content type is not allowed: "image/gif" is not one of ["image/png" "image/jpeg"]`,
+		},
+		{
+			file:    file{field: "photos", filename: "1.txt", content: "txt"},
+			want:    ErrFileContentType,
+			message: `request body has an error: invalid file upload: part photos: file content type is not allowed: "application/octet-stream" is not one of ["image/*"]`,
+		},
+		{
+			file:    photo,
+			options: &Options{MaxFileSize: 16},
+			want:    ErrFileTooLarge,
+			message: "request body has an error: invalid file upload: part photos: file is too large: it exceeds 16 bytes",
+		},
+	} {
+		err := validate(tt.options, avatar, tt.file)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, tt.want), err.Error())
+		require.EqualError(t, err, tt.message)
+	}
+
+	// The extension takes precedence over the option
+	require.NoError(t, validate(&Options{MaxFileSize: 2}, file{field: "avatar", filename: "rex.png", contentType: "image/png", content: "12345678"}))
+}
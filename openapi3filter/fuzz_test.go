@@ -0,0 +1,115 @@
+//go:build go1.18
+// +build go1.18
+
+package openapi3filter
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var fuzzSchemas = []*openapi3.SchemaRef{
+	openapi3.NewIntegerSchema().NewRef(),
+	openapi3.NewFloat64Schema().NewRef(),
+	openapi3.NewBoolSchema().NewRef(),
+	openapi3.NewStringSchema().NewRef(),
+	openapi3.NewArraySchema().WithItems(openapi3.NewIntegerSchema()).NewRef(),
+	openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema()).NewRef(),
+	openapi3.NewObjectSchema().
+		WithProperty("a", openapi3.NewIntegerSchema()).
+		WithProperty("b", openapi3.NewStringSchema()).NewRef(),
+	openapi3.NewObjectSchema().WithAdditionalProperties(openapi3.NewFloat64Schema()).NewRef(),
+	openapi3.NewAllOfSchema(openapi3.NewIntegerSchema(), openapi3.NewFloat64Schema()).NewRef(),
+	openapi3.NewOneOfSchema(openapi3.NewIntegerSchema(), openapi3.NewBoolSchema()).NewRef(),
+}
+
+var (
+	fuzzLocations = []string{openapi3.ParameterInPath, openapi3.ParameterInQuery, openapi3.ParameterInHeader, openapi3.ParameterInCookie}
+	fuzzStyles    = []string{"", "simple", "label", "matrix", "form", "spaceDelimited", "pipeDelimited", "deepObject"}
+)
+
+// FuzzDecodeParameter checks that decoding and validating any raw value of a parameter,
+// whatever its location, style and schema, does not panic:
+//
+//	go test ./openapi3filter -run '^$' -fuzz FuzzDecodeParameter
+func FuzzDecodeParameter(f *testing.F) {
+	f.Add(uint8(0), uint8(1), false, uint8(4), "1,2,3")
+	f.Add(uint8(0), uint8(2), true, uint8(6), ".a=1.b=x")
+	f.Add(uint8(0), uint8(3), true, uint8(5), ";p=a;p=b")
+	f.Add(uint8(1), uint8(4), true, uint8(6), "a=1&b=x")
+	f.Add(uint8(1), uint8(7), true, uint8(6), "p[a]=1&p[b]=%zz")
+	f.Add(uint8(1), uint8(5), false, uint8(4), "p=1%202%20x")
+	f.Add(uint8(1), uint8(4), false, uint8(0), "p=1e400")
+	f.Add(uint8(2), uint8(1), true, uint8(7), "a=1,b=\xff")
+	f.Add(uint8(3), uint8(4), false, uint8(6), "p=a,1,b")
+	f.Fuzz(func(t *testing.T, in, style uint8, explode bool, schema uint8, raw string) {
+		param := &openapi3.Parameter{
+			Name:    "p",
+			In:      fuzzLocations[int(in)%len(fuzzLocations)],
+			Style:   fuzzStyles[int(style)%len(fuzzStyles)],
+			Explode: &explode,
+			Schema:  fuzzSchemas[int(schema)%len(fuzzSchemas)],
+		}
+		if param.In == openapi3.ParameterInPath {
+			param.Required = true
+		}
+		if err := param.Validate(context.Background()); err != nil {
+			return
+		}
+
+		req, err := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		input := &RequestValidationInput{Request: req}
+		switch param.In {
+		case openapi3.ParameterInPath:
+			input.PathParams = map[string]string{"p": raw}
+		case openapi3.ParameterInQuery:
+			req.URL.RawQuery = raw
+		case openapi3.ParameterInHeader:
+			req.Header["P"] = []string{raw}
+		case openapi3.ParameterInCookie:
+			req.Header["Cookie"] = []string{raw}
+		}
+		value, found, err := decodeStyledParameter(param, input)
+		if err != nil || !found {
+			return
+		}
+		_ = param.Schema.Value.VisitJSON(value)
+	})
+}
+
+// FuzzDecodeBody checks that decoding and validating any body, whatever its content type,
+// does not panic:
+//
+//	go test ./openapi3filter -run '^$' -fuzz FuzzDecodeBody
+func FuzzDecodeBody(f *testing.F) {
+	f.Add("application/json", []byte(`{"name": "a", "tags": ["b"], "count": 1}`))
+	f.Add("application/json", []byte(strings.Repeat(`{"a":`, 1000)))
+	f.Add("application/yaml", []byte("name: a\ntags: [b]\ncount: !!float 1e400\n"))
+	f.Add("application/x-www-form-urlencoded", []byte("name=a&tags=b&tags=c&count=%ff"))
+	f.Add("multipart/form-data; boundary=b", []byte("--b\r\nContent-Disposition: form-data; name=\"tags\"\r\n\r\nx\r\n--b--\r\n"))
+	f.Add("multipart/form-data; boundary=b", []byte("--b\r\nContent-Disposition: form-data; name=\"file\"; filename=\"f\"\r\n\r\n\x00\r\n--b--\r\n"))
+	f.Add("text/plain; charset=utf-8", []byte("\xff"))
+	f.Add("application/octet-stream", []byte{0})
+	schema := openapi3.NewObjectSchema().
+		WithProperty("name", openapi3.NewStringSchema()).
+		WithProperty("tags", openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema())).
+		WithProperty("count", openapi3.NewIntegerSchema()).
+		WithProperty("file", openapi3.NewStringSchema().WithFormat("binary")).
+		NewRef()
+	f.Fuzz(func(t *testing.T, contentType string, body []byte) {
+		header := http.Header{headerCT: []string{contentType}}
+		_, value, err := decodeBody(bytes.NewReader(body), header, schema, nil)
+		if err != nil {
+			return
+		}
+		_ = schema.Value.VisitJSON(value)
+	})
+}
@@ -0,0 +1,15 @@
+// Package grpcstatus converts validation errors to gRPC statuses,
+// for services fronting gRPC backends with an OpenAPI edge.
+//
+// Statuses mirror google.rpc.Status, and their details google.rpc.BadRequest,
+// without depending on the gRPC libraries: they marshal to the JSON mapping
+// of those messages, as served by gRPC-HTTP transcoding, and convert field by field
+// to the types of google.golang.org/genproto/googleapis/rpc/errdetails.
+//
+//	pipeline := openapi3filter.NewErrorPipeline(openapi3filter.WithErrorFormatter(grpcstatus.FormatErrors))
+//	validator := openapi3filter.NewValidator(router, openapi3filter.OnErr(pipeline.WriteErr))
+//
+// Each error about a parameter or a property of the request body becomes
+// a field violation of a BadRequest detail, e.g. the field "pet.tags[1]"
+// for the body property "/pet/tags/1".
+package grpcstatus
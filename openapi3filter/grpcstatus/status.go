@@ -0,0 +1,236 @@
+package grpcstatus
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// Code is a gRPC status code, as google.rpc.Code.
+type Code int32
+
+// The gRPC status codes.
+const (
+	OK                 Code = 0
+	Canceled           Code = 1
+	Unknown            Code = 2
+	InvalidArgument    Code = 3
+	DeadlineExceeded   Code = 4
+	NotFound           Code = 5
+	AlreadyExists      Code = 6
+	PermissionDenied   Code = 7
+	ResourceExhausted  Code = 8
+	FailedPrecondition Code = 9
+	Aborted            Code = 10
+	OutOfRange         Code = 11
+	Unimplemented      Code = 12
+	Internal           Code = 13
+	Unavailable        Code = 14
+	DataLoss           Code = 15
+	Unauthenticated    Code = 16
+)
+
+var codeNames = [...]string{
+	"OK",
+	"CANCELLED",
+	"UNKNOWN",
+	"INVALID_ARGUMENT",
+	"DEADLINE_EXCEEDED",
+	"NOT_FOUND",
+	"ALREADY_EXISTS",
+	"PERMISSION_DENIED",
+	"RESOURCE_EXHAUSTED",
+	"FAILED_PRECONDITION",
+	"ABORTED",
+	"OUT_OF_RANGE",
+	"UNIMPLEMENTED",
+	"INTERNAL",
+	"UNAVAILABLE",
+	"DATA_LOSS",
+	"UNAUTHENTICATED",
+}
+
+// String returns the name of the code, e.g. "INVALID_ARGUMENT".
+func (c Code) String() string {
+	if c >= 0 && int(c) < len(codeNames) {
+		return codeNames[c]
+	}
+	return "Code(" + strconv.Itoa(int(c)) + ")"
+}
+
+// CodeFromHTTPStatus returns the gRPC status code of an HTTP status code,
+// following the mapping documented by google.rpc.Code.
+func CodeFromHTTPStatus(status int) Code {
+	switch status {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent:
+		return OK
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return InvalidArgument
+	case http.StatusUnauthorized:
+		return Unauthenticated
+	case http.StatusForbidden:
+		return PermissionDenied
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return Unimplemented
+	case http.StatusConflict:
+		return Aborted
+	case http.StatusPreconditionFailed:
+		return FailedPrecondition
+	case http.StatusRequestedRangeNotSatisfiable:
+		return OutOfRange
+	case http.StatusTooManyRequests:
+		return ResourceExhausted
+	case 499:
+		return Canceled
+	case http.StatusServiceUnavailable:
+		return Unavailable
+	case http.StatusGatewayTimeout:
+		return DeadlineExceeded
+	}
+	switch {
+	case status >= 400 && status < 500:
+		return InvalidArgument
+	case status >= 500 && status < 600:
+		return Internal
+	}
+	return Unknown
+}
+
+// Status mirrors google.rpc.Status.
+type Status struct {
+	Code    Code     `json:"code"`
+	Message string   `json:"message,omitempty"`
+	Details []Detail `json:"details,omitempty"`
+}
+
+// Detail is a detail of a Status, marshaled as a google.protobuf.Any.
+type Detail interface {
+	// TypeURL returns the type URL of the detail,
+	// e.g. "type.googleapis.com/google.rpc.BadRequest".
+	TypeURL() string
+}
+
+// BadRequest mirrors google.rpc.BadRequest.
+type BadRequest struct {
+	FieldViolations []*FieldViolation `json:"fieldViolations"`
+}
+
+// FieldViolation mirrors google.rpc.BadRequest.FieldViolation.
+type FieldViolation struct {
+	// Field is the path to the field, e.g. "pet.tags[1]".
+	Field string `json:"field"`
+	// Description describes why the field is bad.
+	Description string `json:"description"`
+	// Reason is the code of the violation, e.g. "SCHEMA_MISMATCH".
+	Reason string `json:"reason,omitempty"`
+}
+
+var _ Detail = (*BadRequest)(nil)
+
+// TypeURL implements Detail.
+func (*BadRequest) TypeURL() string { return "type.googleapis.com/google.rpc.BadRequest" }
+
+// MarshalJSON adds the type URL to the JSON object of the BadRequest.
+func (d *BadRequest) MarshalJSON() ([]byte, error) {
+	type badRequest BadRequest
+	return json.Marshal(struct {
+		Type string `json:"@type"`
+		*badRequest
+	}{d.TypeURL(), (*badRequest)(d)})
+}
+
+// Err returns the Status as an error.
+func (s *Status) Err() error {
+	if s.Code == OK {
+		return nil
+	}
+	return &Error{Status: s}
+}
+
+// Error is an error holding a non-OK Status.
+type Error struct {
+	Status *Status
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("rpc error: code = %s desc = %s", e.Status.Code, e.Status.Message)
+}
+
+// FromError returns the Status of err, as classified and structured by
+// openapi3filter.DefaultErrorPipeline.
+func FromError(err error) *Status {
+	if err == nil {
+		return &Status{Code: OK}
+	}
+	vErrs := openapi3filter.DefaultErrorPipeline.Errors(err)
+	return FromValidationErrors(openapi3filter.CombinedStatus(vErrs), vErrs)
+}
+
+// FromValidationErrors returns the Status of errs, whose combined HTTP status is status:
+// its code is that of status, its message the titles of the errors, and a BadRequest
+// detail holds the violations of the client errors about parameters or body properties.
+func FromValidationErrors(status int, errs []*openapi3filter.ValidationError) *Status {
+	s := &Status{Code: CodeFromHTTPStatus(status)}
+	messages := make([]string, 0, len(errs))
+	var violations []*FieldViolation
+	for _, vErr := range errs {
+		message := vErr.Title
+		if vErr.Detail != "" {
+			message += ": " + vErr.Detail
+		}
+		messages = append(messages, message)
+		if vErr.Status >= 400 && vErr.Status < 500 && vErr.Source != nil {
+			if field := fieldPath(vErr.Source); field != "" {
+				violations = append(violations, &FieldViolation{
+					Field:       field,
+					Description: message,
+					Reason:      strings.ToUpper(vErr.Code),
+				})
+			}
+		}
+	}
+	s.Message = strings.Join(messages, "; ")
+	if len(violations) != 0 {
+		s.Details = append(s.Details, &BadRequest{FieldViolations: violations})
+	}
+	return s
+}
+
+// FormatErrors is an openapi3filter.ErrorFormatter writing the JSON of the Status of errs.
+// The status code of the response is left as is.
+func FormatErrors(w http.ResponseWriter, status int, errs []*openapi3filter.ValidationError) {
+	body, _ := json.Marshal(FromValidationErrors(status, errs))
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// fieldPath returns the path of the field of source, e.g. "pet.tags[1]"
+// for the JSON pointer "/pet/tags/1".
+func fieldPath(source *openapi3filter.ValidationErrorSource) string {
+	if source.Parameter != "" {
+		return source.Parameter
+	}
+	var b strings.Builder
+	for _, token := range strings.Split(strings.TrimPrefix(source.Pointer, "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		if _, err := strconv.Atoi(token); err == nil && b.Len() != 0 {
+			b.WriteString("[" + token + "]")
+			continue
+		}
+		if b.Len() != 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(token)
+	}
+	return b.String()
+}
@@ -0,0 +1,115 @@
+package grpcstatus_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/openapi3filter/grpcstatus"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      parameters:
+      - {name: limit, in: query, schema: {type: integer, maximum: 10}}
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                pet:
+                  type: object
+                  properties:
+                    tags: {type: array, items: {type: string}}
+      responses:
+        '201': {description: Created}
+`
+
+func TestFromError(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	validate := func(method, target, body string) error {
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		route, pathParams, err := router.FindRoute(r)
+		if err != nil {
+			return err
+		}
+		return openapi3filter.ValidateRequest(context.Background(), &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    &openapi3filter.Options{MultiError: true},
+		})
+	}
+
+	status := grpcstatus.FromError(nil)
+	require.Equal(t, grpcstatus.OK, status.Code)
+	require.NoError(t, status.Err())
+
+	status = grpcstatus.FromError(validate(http.MethodPost, "/pets?limit=20", `{"pet":{"tags":["cat",1]}}`))
+	require.Equal(t, grpcstatus.InvalidArgument, status.Code)
+	require.Len(t, status.Details, 1)
+	violations := status.Details[0].(*grpcstatus.BadRequest).FieldViolations
+	require.Len(t, violations, 2)
+	require.Equal(t, "limit", violations[0].Field)
+	require.Equal(t, "pet.tags[1]", violations[1].Field)
+	require.Equal(t, "INVALID_ARGUMENT", status.Code.String())
+	require.True(t, strings.HasPrefix(status.Err().Error(), "rpc error: code = INVALID_ARGUMENT desc = "))
+
+	status = grpcstatus.FromError(validate(http.MethodGet, "/cats", ""))
+	require.Equal(t, grpcstatus.NotFound, status.Code)
+	require.Empty(t, status.Details)
+
+	status = grpcstatus.FromError(validate(http.MethodGet, "/pets", ""))
+	require.Equal(t, grpcstatus.Unimplemented, status.Code)
+
+	status = grpcstatus.FromError(errors.New("boom"))
+	require.Equal(t, grpcstatus.Internal, status.Code)
+	require.Equal(t, "Internal Server Error", status.Message)
+}
+
+func TestFormatErrors(t *testing.T) {
+	pipeline := openapi3filter.NewErrorPipeline(openapi3filter.WithErrorFormatter(grpcstatus.FormatErrors))
+	w := httptest.NewRecorder()
+	pipeline.Encode(context.Background(), &openapi3filter.ValidationError{
+		Status: http.StatusUnprocessableEntity,
+		Code:   openapi3filter.ErrorCodeSchemaMismatch,
+		Title:  `property "name" is missing`,
+		Source: &openapi3filter.ValidationErrorSource{Pointer: "/name"},
+	}, w)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Equal(t, map[string]interface{}{
+		"code":    float64(3),
+		"message": `property "name" is missing`,
+		"details": []interface{}{map[string]interface{}{
+			"@type": "type.googleapis.com/google.rpc.BadRequest",
+			"fieldViolations": []interface{}{map[string]interface{}{
+				"field":       "name",
+				"description": `property "name" is missing`,
+				"reason":      "SCHEMA_MISMATCH",
+			}},
+		}},
+	}, body)
+}
@@ -0,0 +1,88 @@
+package openapi3filter
+
+import (
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// Instrumentation observes the validation performed by a Validator,
+// e.g. to export metrics. Implementations must be safe for concurrent use.
+//
+// operation is the operationId of the route, or its method and path when the
+// operation has no ID, and is empty when no route matched the request.
+type Instrumentation interface {
+	// ValidationFailed is called for each validation error, those of multi-errors
+	// being reported one by one. location is one of the Location constants and code
+	// is the most specific code of the error, one of the ErrorCode constants
+	// (e.g. ErrorCodeSchemaMismatch), as ClassifyError tells.
+	ValidationFailed(operation, location, code string)
+	// ValidationDuration is called with the time taken by the validation of a request
+	// (with phase "request") or of a response (with phase "response").
+	ValidationDuration(operation, phase string, duration time.Duration)
+}
+
+// Locations of validation errors, as reported to Instrumentation.
+const (
+	LocationRoute    = "route"
+	LocationSecurity = "security"
+	LocationPath     = openapi3.ParameterInPath
+	LocationQuery    = openapi3.ParameterInQuery
+	LocationHeader   = openapi3.ParameterInHeader
+	LocationCookie   = openapi3.ParameterInCookie
+	LocationBody     = "body"
+	LocationResponse = "response"
+	// LocationOther is for errors of an unknown type.
+	LocationOther = "other"
+)
+
+// WithInstrumentation sets the Instrumentation observing validations.
+func WithInstrumentation(instrumentation Instrumentation) ValidatorOption {
+	return func(v *Validator) {
+		v.instrumentation = instrumentation
+	}
+}
+
+func routeOperation(route *routers.Route) string {
+	if route.Operation != nil && route.Operation.OperationID != "" {
+		return route.Operation.OperationID
+	}
+	return route.Method + " " + route.Path
+}
+
+// observeFailures reports each of the errors gathered in err.
+func (v *Validator) observeFailures(operation string, err error) {
+	if v.instrumentation == nil {
+		return
+	}
+	for _, err := range flattenErrors(err, nil) {
+		v.instrumentation.ValidationFailed(operation, errorLocation(err), ClassifyError(err).Code)
+	}
+}
+
+func (v *Validator) observeDuration(operation, phase string, start time.Time) {
+	if v.instrumentation != nil {
+		v.instrumentation.ValidationDuration(operation, phase, time.Since(start))
+	}
+}
+
+func errorLocation(err error) string {
+	switch e := err.(type) {
+	case *routers.RouteError:
+		return LocationRoute
+	case *SecurityRequirementsError:
+		return LocationSecurity
+	case *ResponseError:
+		return LocationResponse
+	case *RequestError:
+		if e.Parameter != nil {
+			return e.Parameter.In
+		}
+		if e.RequestBody != nil || e.Err == nil {
+			// Other reasons relate to the Content-Type of the body
+			return LocationBody
+		}
+	}
+	return LocationOther
+}
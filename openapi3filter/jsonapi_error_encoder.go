@@ -0,0 +1,106 @@
+package openapi3filter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// JSONAPIMediaType is the media type of JSON:API documents.
+const JSONAPIMediaType = "application/vnd.api+json"
+
+// JSONAPIError is a JSON:API error object.
+// See https://jsonapi.org/format/#error-objects
+type JSONAPIError struct {
+	ID string `json:"id,omitempty" yaml:"id,omitempty"`
+	// The HTTP status code applicable to this problem, expressed as a string value.
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
+	Code   string `json:"code,omitempty" yaml:"code,omitempty"`
+	Title  string `json:"title,omitempty" yaml:"title,omitempty"`
+	Detail string `json:"detail,omitempty" yaml:"detail,omitempty"`
+	// Source points to the request body member (pointer) or query parameter (parameter) at fault.
+	Source *ValidationErrorSource `json:"source,omitempty" yaml:"source,omitempty"`
+}
+
+// JSONAPIErrors converts the errors of routers and ValidateRequest to JSON:API error objects.
+//
+// Errors are converted as by ValidationErrorEncoder. The errors gathered with
+// Options.MultiError, including the schema errors of a single parameter or body,
+// become one error object each. Unmet security requirements have status 401
+// and other errors status 500, unless they implement StatusCoder.
+func JSONAPIErrors(err error) []*JSONAPIError {
+	errs := flattenErrors(err, nil)
+	objects := make([]*JSONAPIError, 0, len(errs))
+	for _, err := range errs {
+		objects = append(objects, newJSONAPIError(err))
+	}
+	return objects
+}
+
+// JSONAPIErrorEncoder is an ErrorEncoder writing a JSON:API document
+// with the error objects returned by JSONAPIErrors.
+//
+// The status code of the response is the one shared by all errors,
+// or else 400 for client errors and 500 otherwise.
+func JSONAPIErrorEncoder(_ context.Context, err error, w http.ResponseWriter) {
+	objects := JSONAPIErrors(err)
+	statuses := make([]int, 0, len(objects))
+	for _, object := range objects {
+		s, _ := strconv.Atoi(object.Status)
+		statuses = append(statuses, s)
+	}
+	status := combinedStatus(statuses)
+
+	body, marshalErr := json.Marshal(struct {
+		Errors []*JSONAPIError `json:"errors"`
+	}{objects})
+	if marshalErr != nil {
+		DefaultErrorEncoder(context.Background(), err, w)
+		return
+	}
+	w.Header().Set("Content-Type", JSONAPIMediaType)
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// flattenErrors appends the errors of multi-errors to errs.
+func flattenErrors(err error, errs []error) []error {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		for _, err := range e {
+			errs = flattenErrors(err, errs)
+		}
+		return errs
+	case *RequestError:
+		if me, ok := e.Err.(openapi3.MultiError); ok {
+			for _, err := range me {
+				single := *e
+				single.Err = err
+				errs = flattenErrors(&single, errs)
+			}
+			return errs
+		}
+	}
+	return append(errs, err)
+}
+
+func newJSONAPIError(err error) *JSONAPIError {
+	vErr, ok := err.(*ValidationError)
+	if !ok {
+		vErr = convertError(err)
+	}
+	if vErr == nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(*SecurityRequirementsError); ok {
+			status = http.StatusUnauthorized
+		} else if sc, ok := err.(StatusCoder); ok {
+			status = sc.StatusCode()
+		}
+		vErr = &ValidationError{Status: status, Title: err.Error()}
+	}
+
+	return toJSONAPIError(vErr)
+}
@@ -0,0 +1,99 @@
+package openapi3filter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+func TestJSONAPIErrorEncoder(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      parameters:
+      - {name: kind, in: query, required: true, schema: {type: string, enum: [cat, dog]}}
+      - {name: limit, in: query, schema: {type: integer, maximum: 10}}
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+                age: {type: integer, minimum: 0}
+      responses:
+        "201": {description: Created}
+`
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	validate := func(target, body string) error {
+		r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		r.Header.Set(headerCT, "application/json")
+		route, pathParams, err := router.FindRoute(r)
+		if err != nil {
+			return err
+		}
+		return ValidateRequest(context.Background(), &RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    &Options{MultiError: true},
+		})
+	}
+
+	err = validate("/pets?kind=bird&limit=20", `{"age": -1}`)
+	require.Error(t, err)
+	objects := JSONAPIErrors(err)
+	require.Len(t, objects, 4)
+	require.Equal(t, &JSONAPIError{
+		Status: "400",
+		Title:  "value \"bird\" is not one of the allowed values",
+		Detail: "value bird at / must be one of: cat, dog",
+		Source: &ValidationErrorSource{Parameter: "kind"},
+	}, objects[0])
+	require.Equal(t, "400", objects[1].Status)
+	require.Equal(t, &ValidationErrorSource{Parameter: "limit"}, objects[1].Source)
+	require.Equal(t, "422", objects[2].Status)
+	require.Equal(t, &ValidationErrorSource{Pointer: "/age"}, objects[2].Source)
+	require.Equal(t, "422", objects[3].Status)
+	require.Equal(t, &ValidationErrorSource{Pointer: "/name"}, objects[3].Source)
+
+	w := httptest.NewRecorder()
+	JSONAPIErrorEncoder(context.Background(), err, w)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, JSONAPIMediaType, w.Header().Get("Content-Type"))
+	require.Contains(t, w.Body.String(), `{"errors":[{"status":"400",`)
+
+	// A single status code is kept
+	err = validate("/pets?kind=cat", `{"age": -1}`)
+	require.Error(t, err)
+	w = httptest.NewRecorder()
+	JSONAPIErrorEncoder(context.Background(), err, w)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.JSONEq(t, `{"errors": [
+		{"status": "422", "title": "number must be at least 0", "source": {"pointer": "/age"}},
+		{"status": "422", "title": "property \"name\" is missing", "source": {"pointer": "/name"}}
+	]}`, w.Body.String())
+
+	err = validate("/owners", `{}`)
+	require.Error(t, err)
+	w = httptest.NewRecorder()
+	JSONAPIErrorEncoder(context.Background(), err, w)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.JSONEq(t, `{"errors": [{"status": "404", "title": "no matching operation was found"}]}`, w.Body.String())
+}
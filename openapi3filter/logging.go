@@ -0,0 +1,26 @@
+package openapi3filter
+
+import (
+	"context"
+
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// logFailures logs each of the errors gathered in err, which failed the validation
+// of a request or response of route, with its operation, location and code.
+// Failed security requirements are logged as authentication failures.
+func logFailures(ctx context.Context, logger routers.Logger, msg string, route *routers.Route, err error) {
+	for _, err := range flattenErrors(err, nil) {
+		location := errorLocation(err)
+		message := msg
+		if location == LocationSecurity {
+			message = "authentication failed"
+		}
+		logger.WarnContext(ctx, message,
+			"operation", routeOperation(route),
+			"location", location,
+			"code", ClassifyError(err).Code,
+			"error", err.Error(),
+		)
+	}
+}
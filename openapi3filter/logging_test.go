@@ -0,0 +1,99 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+type recordingLogger struct {
+	records []string
+}
+
+func (l *recordingLogger) InfoContext(ctx context.Context, msg string, args ...interface{}) {
+	l.record("INFO", msg, args)
+}
+
+func (l *recordingLogger) WarnContext(ctx context.Context, msg string, args ...interface{}) {
+	l.record("WARN", msg, args)
+}
+
+func (l *recordingLogger) record(level, msg string, args []interface{}) {
+	record := level + " " + msg
+	for i := 0; i+1 < len(args); i += 2 {
+		record += fmt.Sprintf(" %v=%v", args[i], args[i+1])
+	}
+	l.records = append(l.records, record)
+}
+
+func TestOptionsLogger(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+components:
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-API-Key}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      security: [{apiKey: []}]
+      parameters:
+      - {name: limit, in: query, schema: {type: integer, maximum: 10}}
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema: {type: array}
+`))
+	require.NoError(t, err)
+
+	logger := &recordingLogger{}
+	router, err := gorillamux.NewRouter(doc, gorillamux.WithLogger(logger))
+	require.NoError(t, err)
+	v := openapi3filter.NewValidator(router,
+		openapi3filter.OnLog(func(string, error) {}),
+		openapi3filter.ValidationOptions(openapi3filter.Options{
+			MultiError: true,
+			Logger:     logger,
+			AuthenticationFunc: func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+				if input.RequestValidationInput.Request.Header.Get("X-API-Key") == "" {
+					return errors.New("missing key")
+				}
+				return nil
+			},
+		}),
+	)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	serve := func(target string, header http.Header) {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		for name, values := range header {
+			r.Header[name] = values
+		}
+		v.Middleware(handler).ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	serve("/cats", nil)
+	serve("/pets?limit=20", nil)
+	serve("/pets", http.Header{"X-Api-Key": {"secret"}})
+
+	require.Len(t, logger.records, 4)
+	require.Equal(t, "INFO route not found method=GET path=/cats error=no matching operation was found", logger.records[0])
+	require.True(t, strings.HasPrefix(logger.records[1], "WARN authentication failed operation=listPets location=security code=security_requirements error="), logger.records[1])
+	require.True(t, strings.HasPrefix(logger.records[2], "WARN request validation failed operation=listPets location=query code=schema_mismatch error="), logger.records[2])
+	require.True(t, strings.HasPrefix(logger.records[3], "WARN response validation failed operation=listPets location=response code=schema_mismatch error="), logger.records[3])
+}
@@ -2,10 +2,14 @@ package openapi3filter
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/routers"
 )
@@ -17,6 +21,11 @@ type Validator struct {
 	logFunc LogFunc
 	strict  bool
 	options Options
+
+	instrumentation Instrumentation
+	violationFunc   ViolationFunc
+	warningFunc     WarningFunc
+	cors            *CORSOptions
 }
 
 // ErrFunc handles errors that may occur during validation.
@@ -25,6 +34,14 @@ type ErrFunc func(w http.ResponseWriter, status int, code ErrCode, err error)
 // LogFunc handles log messages that may occur during validation.
 type LogFunc func(message string, err error)
 
+// ViolationFunc is called with each request, or response to the request,
+// that fails validation.
+type ViolationFunc func(req *http.Request, code ErrCode, err error)
+
+// WarningFunc is called with each warning about a request,
+// which does not make the request invalid (see ValidateRequestResult).
+type WarningFunc func(req *http.Request, warning *Warning)
+
 // ErrCode is used for classification of different types of errors that may
 // occur during validation. These may be used to write an appropriate response
 // in ErrFunc.
@@ -42,29 +59,34 @@ const (
 	// ErrCodeResponseInvalid happens when the wrapped handler response does
 	// not conform to the OpenAPI 3 specification.
 	ErrCodeResponseInvalid = iota
+	// ErrCodeOperationFailed happens when an OperationFunc served by NewOperationServer
+	// returns an error.
+	ErrCodeOperationFailed = iota
 )
 
-func (e ErrCode) responseText() string {
+// String returns the name of the code, e.g. "request_invalid".
+func (e ErrCode) String() string {
 	switch e {
 	case ErrCodeOK:
-		return "OK"
+		return "ok"
 	case ErrCodeCannotFindRoute:
-		return "not found"
+		return "cannot_find_route"
 	case ErrCodeRequestInvalid:
-		return "bad request"
-	default:
-		return "server error"
+		return "request_invalid"
+	case ErrCodeResponseInvalid:
+		return "response_invalid"
+	case ErrCodeOperationFailed:
+		return "operation_failed"
 	}
+	return fmt.Sprintf("ErrCode(%d)", int(e))
 }
 
 // NewValidator returns a new response validation middlware, using the given
 // routes from an OpenAPI 3 specification.
 func NewValidator(router routers.Router, options ...ValidatorOption) *Validator {
 	v := &Validator{
-		router: router,
-		errFunc: func(w http.ResponseWriter, status int, code ErrCode, _ error) {
-			http.Error(w, code.responseText(), status)
-		},
+		router:  router,
+		errFunc: DefaultErrorPipeline.WriteErr,
 		logFunc: func(message string, err error) {
 			log.Printf("%s: %v", message, err)
 		},
@@ -81,8 +103,9 @@ type ValidatorOption func(*Validator)
 
 // OnErr provides a callback that handles writing an HTTP response on a
 // validation error. This allows customization of error responses without
-// prescribing a particular form. This callback is only called on response
-// validator errors in Strict mode.
+// prescribing a particular form, e.g. with the WriteErr method of an ErrorPipeline
+// of other stages. This callback is only called on response validator errors
+// in Strict mode. It defaults to DefaultErrorPipeline.WriteErr.
 func OnErr(f ErrFunc) ValidatorOption {
 	return func(v *Validator) {
 		v.errFunc = f
@@ -98,6 +121,22 @@ func OnLog(f LogFunc) ValidatorOption {
 	}
 }
 
+// OnViolation provides a callback that is called on each validation failure,
+// e.g. to record them. It is called in addition to the LogFunc.
+func OnViolation(f ViolationFunc) ValidatorOption {
+	return func(v *Validator) {
+		v.violationFunc = f
+	}
+}
+
+// OnWarning provides a callback that is called on each warning about requests,
+// e.g. to log the usage of deprecated parameters.
+func OnWarning(f WarningFunc) ValidatorOption {
+	return func(v *Validator) {
+		v.warningFunc = f
+	}
+}
+
 // Strict, if set, causes an internal server error to be sent if the wrapped
 // handler response fails response validation. If not set, the response is sent
 // and the error is only logged.
@@ -116,26 +155,65 @@ func ValidationOptions(options Options) ValidatorOption {
 
 // Middleware returns an http.Handler which wraps the given handler with
 // request and response validation.
+//
+// OPTIONS requests the router routes implicitly (see gorillamux.WithImplicitOPTIONS)
+// are answered with a 204 No Content response listing the methods of the path
+// in its Allow header, without calling the handler.
+// See CORS for the handling of cross-origin requests.
 func (v *Validator) Middleware(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if v.cors != nil && isPreflightRequest(r) {
+			v.preflight(w, r, h)
+			return
+		}
+
+		_, span := startSpan(r.Context(), v.options.Tracer, SpanFindRoute, nil)
 		route, pathParams, err := v.router.FindRoute(r)
+		setRouteAttributes(span, route)
+		endSpan(span, err)
 		if err != nil {
-			v.logFunc("validation error: failed to find route for "+r.URL.String(), err)
+			v.fail(r, "", ErrCodeCannotFindRoute, "validation error: failed to find route for "+r.URL.String(), err)
 			v.errFunc(w, http.StatusNotFound, ErrCodeCannotFindRoute, err)
 			return
 		}
+		if route.Implicit && route.Method == http.MethodOptions {
+			// Answer OPTIONS requests the router routes implicitly with the methods of the path
+			w.Header().Set("Allow", strings.Join(route.Allow, ", "))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
 		requestValidationInput := &RequestValidationInput{
 			Request:    r,
 			PathParams: pathParams,
 			Route:      route,
 			Options:    &v.options,
 		}
-		if err = ValidateRequest(r.Context(), requestValidationInput); err != nil {
-			v.logFunc("invalid request", err)
+		operation := routeOperation(route)
+		start := time.Now()
+		if v.warningFunc != nil {
+			result := ValidateRequestResult(r.Context(), requestValidationInput)
+			for _, warning := range result.Warnings {
+				v.warningFunc(r, warning)
+			}
+			err = result.Err()
+		} else {
+			err = ValidateRequest(r.Context(), requestValidationInput)
+		}
+		v.observeDuration(operation, "request", start)
+		if err != nil {
+			v.fail(r, operation, ErrCodeRequestInvalid, "invalid request", err)
 			v.errFunc(w, http.StatusBadRequest, ErrCodeRequestInvalid, err)
 			return
 		}
 
+		r = r.WithContext(context.WithValue(r.Context(), routeKey{}, &routeContext{route: route, pathParams: pathParams, input: requestValidationInput}))
+		if body, ok := requestValidationInput.BodyBytes(); ok {
+			r = r.WithContext(context.WithValue(r.Context(), requestBodyKey{}, body))
+		}
+		if principal := requestValidationInput.Principal(); principal != nil {
+			r = r.WithContext(ContextWithPrincipal(r.Context(), principal))
+		}
+
 		var wr responseWrapper
 		if v.strict {
 			wr = &strictResponseWrapper{w: w}
@@ -145,14 +223,21 @@ func (v *Validator) Middleware(h http.Handler) http.Handler {
 
 		h.ServeHTTP(wr, r)
 
-		if err = ValidateResponse(r.Context(), &ResponseValidationInput{
+		start = time.Now()
+		responseValidationInput := &ResponseValidationInput{
 			RequestValidationInput: requestValidationInput,
 			Status:                 wr.statusCode(),
 			Header:                 wr.Header(),
 			Body:                   ioutil.NopCloser(bytes.NewBuffer(wr.bodyContents())),
 			Options:                &v.options,
-		}); err != nil {
-			v.logFunc("invalid response", err)
+		}
+		err = ValidateResponse(r.Context(), responseValidationInput)
+		if err == nil && v.cors != nil {
+			err = v.cors.validateResponse(responseValidationInput)
+		}
+		v.observeDuration(operation, "response", start)
+		if err != nil {
+			v.fail(r, operation, ErrCodeResponseInvalid, "invalid response", err)
 			if v.strict {
 				v.errFunc(w, http.StatusInternalServerError, ErrCodeResponseInvalid, err)
 			}
@@ -165,6 +250,15 @@ func (v *Validator) Middleware(h http.Handler) http.Handler {
 	})
 }
 
+// fail logs, observes and reports a validation failure.
+func (v *Validator) fail(r *http.Request, operation string, code ErrCode, message string, err error) {
+	v.logFunc(message, err)
+	v.observeFailures(operation, err)
+	if v.violationFunc != nil {
+		v.violationFunc(r, code, err)
+	}
+}
+
 type responseWrapper interface {
 	http.ResponseWriter
 
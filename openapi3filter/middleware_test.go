@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -219,7 +220,7 @@ func TestValidator(t *testing.T) {
 			path:   "/test?version=1",
 		},
 		response: testResponse{
-			404, "not found\n",
+			405, `{"errors":[{"status":405,"code":"method_not_allowed","title":"method not allowed"}]}`,
 		},
 		strict: true,
 	}, {
@@ -230,7 +231,7 @@ func TestValidator(t *testing.T) {
 			path:   "/test/42?version=1",
 		},
 		response: testResponse{
-			404, "not found\n",
+			405, `{"errors":[{"status":405,"code":"method_not_allowed","title":"method not allowed"}]}`,
 		},
 		strict: true,
 	}, {
@@ -241,7 +242,7 @@ func TestValidator(t *testing.T) {
 			path:   "/test/42",
 		},
 		response: testResponse{
-			400, "bad request\n",
+			400, `{"errors":[{"status":400,"code":"missing_required","title":"parameter \"version\" in query is required"}]}`,
 		},
 		strict: true,
 	}, {
@@ -254,7 +255,7 @@ func TestValidator(t *testing.T) {
 			contentType: "application/json",
 		},
 		response: testResponse{
-			400, "bad request\n",
+			422, `{"errors":[{"status":422,"code":"schema_mismatch","title":"field must be set to number or not be present","source":{"pointer":"/actual"}}]}`,
 		},
 		strict: true,
 	}, {
@@ -267,7 +268,7 @@ func TestValidator(t *testing.T) {
 			contentType: "application/json",
 		},
 		response: testResponse{
-			400, "bad request\n",
+			422, `{"errors":[{"status":422,"code":"schema_mismatch","title":"property \"actual\" is missing","source":{"pointer":"/actual"}}]}`,
 		},
 		strict: true,
 	}, {
@@ -280,7 +281,7 @@ func TestValidator(t *testing.T) {
 			contentType: "application/json",
 		},
 		response: testResponse{
-			400, "bad request\n",
+			422, `{"errors":[{"status":422,"code":"schema_mismatch","title":"property \"ideal\" is unsupported"}]}`,
 		},
 		strict: true,
 	}, {
@@ -309,7 +310,7 @@ func TestValidator(t *testing.T) {
 			path:   "/test/42?version=1",
 		},
 		response: testResponse{
-			500, "server error\n",
+			500, `{"errors":[{"status":500,"code":"schema_mismatch","title":"Internal Server Error"}]}`,
 		},
 		strict: true,
 	}, {
@@ -531,3 +532,117 @@ paths:
 	// 500 {"message":"Internal Server Error","status":500}
 	// 500 {"message":"Internal Server Error","status":500}
 }
+
+func TestValidatorRequestBodyContext(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(validatorSpec))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	const contents = `{"name":"foo","expected":9,"actual":10}`
+	var body []byte
+	var ok bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok = openapi3filter.RequestBodyFromContext(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(validatorOkResponse))
+	})
+	v := openapi3filter.NewValidator(router)
+	r := httptest.NewRequest(http.MethodPost, "/test?version=1", strings.NewReader(contents))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	v.Middleware(handler).ServeHTTP(w, r)
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.True(t, ok)
+	require.Equal(t, contents, string(body))
+}
+
+func TestValidatorPrincipalContext(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+components:
+  securitySchemes:
+    bearer: {type: http, scheme: bearer}
+    apiKey: {type: apiKey, in: header, name: X-API-Key}
+paths:
+  /pets:
+    get:
+      security:
+      - bearer: []
+      - apiKey: []
+      responses:
+        '200': {description: OK}
+`
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	authenticate := func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+		// Failed requirements do not set principals
+		input.SetPrincipal("nobody")
+		if input.SecuritySchemeName != "apiKey" {
+			return errors.New("unsupported")
+		}
+		key := input.RequestValidationInput.Request.Header.Get("X-API-Key")
+		if key == "" {
+			return errors.New("missing API key")
+		}
+		input.SetPrincipal("user:" + key)
+		return nil
+	}
+
+	var principal interface{}
+	var ok bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok = openapi3filter.PrincipalFromContext(r.Context())
+	})
+	v := openapi3filter.NewValidator(router, openapi3filter.ValidationOptions(openapi3filter.Options{
+		AuthenticationFunc: authenticate,
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/pets", nil)
+	r.Header.Set("X-API-Key", "alice")
+	w := httptest.NewRecorder()
+	v.Middleware(handler).ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.True(t, ok)
+	require.Equal(t, "user:alice", principal)
+
+	route, pathParams, err := router.FindRoute(r)
+	require.NoError(t, err)
+	input := &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    &openapi3filter.Options{AuthenticationFunc: authenticate},
+	}
+	require.NoError(t, openapi3filter.ValidateRequest(context.Background(), input))
+	require.Equal(t, "user:alice", input.Principal())
+	require.Equal(t, map[string]interface{}{"apiKey": "user:alice"}, input.Principals())
+
+	r.Header.Del("X-API-Key")
+	require.Error(t, openapi3filter.ValidateRequest(context.Background(), input))
+	require.Nil(t, input.Principal())
+}
+
+func TestValidatorImplicitMethods(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(validatorSpec))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc, gorillamux.WithImplicitHEAD(), gorillamux.WithImplicitOPTIONS())
+	require.NoError(t, err)
+
+	called := false
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	v := openapi3filter.NewValidator(router)
+
+	r := httptest.NewRequest(http.MethodOptions, "/test?version=1", nil)
+	w := httptest.NewRecorder()
+	v.Middleware(handler).ServeHTTP(w, r)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Equal(t, "OPTIONS, POST", w.Header().Get("Allow"))
+	require.False(t, called)
+}
@@ -0,0 +1,111 @@
+package openapi3filter
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// NormalizedRequest is the canonical view of a validated request, which handlers
+// can trust instead of parsing the request again.
+type NormalizedRequest struct {
+	Route *routers.Route
+	// PathParams are the raw values of the path parameters, percent-decoded.
+	PathParams map[string]string
+	// Path, Query, Header and Cookie are the values of the parameters of the operation,
+	// decoded according to their style and schema and validated, with their default values
+	// when the request does not set them, by name. Strings of path parameters are
+	// percent-decoded. Integers are int64 values, or int32 values with format int32.
+	Path   map[string]interface{}
+	Query  map[string]interface{}
+	Header map[string]interface{}
+	Cookie map[string]interface{}
+	// Headers are all the headers of the request, with leading and trailing whitespace
+	// trimmed from their values.
+	Headers http.Header
+	// Body is the request body decoded according to its content type and validated,
+	// with its default values, or nil if the request has no body.
+	Body interface{}
+	// Principal is the principal the request was authenticated as, if any
+	// (see AuthenticationInput.SetPrincipal).
+	Principal interface{}
+}
+
+// Normalized returns the canonical view of the request once validated by ValidateRequest.
+func (input *RequestValidationInput) Normalized() *NormalizedRequest {
+	r := input.Request
+	req := &NormalizedRequest{
+		Route:      input.Route,
+		PathParams: make(map[string]string, len(input.PathParams)),
+		Path:       input.ParameterValues(openapi3.ParameterInPath),
+		Query:      input.ParameterValues(openapi3.ParameterInQuery),
+		Header:     input.ParameterValues(openapi3.ParameterInHeader),
+		Cookie:     input.ParameterValues(openapi3.ParameterInCookie),
+		Headers:    make(http.Header, len(r.Header)),
+		Principal:  input.Principal(),
+	}
+	escapedPath := r.URL.EscapedPath()
+	for name, value := range input.PathParams {
+		req.PathParams[name] = unescapePathValue(escapedPath, value)
+	}
+	for name, value := range req.Path {
+		req.Path[name] = unescapePathValues(escapedPath, value)
+	}
+	for name, values := range r.Header {
+		trimmed := make([]string, 0, len(values))
+		for _, value := range values {
+			trimmed = append(trimmed, strings.TrimSpace(value))
+		}
+		req.Headers[name] = trimmed
+	}
+	req.Body, _ = input.BodyValue()
+	return req
+}
+
+// NormalizedRequestFromContext returns the canonical view of the request,
+// as set by Validator.Middleware in the context of the requests it passes on.
+func NormalizedRequestFromContext(ctx context.Context) (*NormalizedRequest, bool) {
+	rc, ok := ctx.Value(routeKey{}).(*routeContext)
+	if !ok {
+		return nil, false
+	}
+	return rc.input.Normalized(), true
+}
+
+// unescapePathValue percent-decodes a value of a path parameter.
+// As routers match either the escaped or the unescaped path, values that are not
+// part of the escaped path are already decoded.
+func unescapePathValue(escapedPath, value string) string {
+	if !strings.Contains(value, "%") || !strings.Contains(escapedPath, value) {
+		return value
+	}
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
+
+// unescapePathValues percent-decodes the strings of a decoded value of a path parameter.
+func unescapePathValues(escapedPath string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return unescapePathValue(escapedPath, v)
+	case []interface{}:
+		values := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			values = append(values, unescapePathValues(escapedPath, item))
+		}
+		return values
+	case map[string]interface{}:
+		values := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			values[unescapePathValue(escapedPath, k)] = unescapePathValues(escapedPath, item)
+		}
+		return values
+	}
+	return value
+}
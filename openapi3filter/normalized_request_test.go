@@ -0,0 +1,70 @@
+package openapi3filter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+func TestNormalizedRequest(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info: {title: Files, version: 1.0.0}
+paths:
+  /files/{name}/{path}:
+    put:
+      parameters:
+      - {name: name, in: path, required: true, schema: {type: string}}
+      - {name: path, in: path, required: true, schema: {type: array, items: {type: string}}}
+      - {name: tags, in: query, explode: false, schema: {type: array, items: {type: string}}}
+      - {name: limit, in: query, schema: {type: integer, format: int32, default: 10}}
+      - {name: X-Owner, in: header, schema: {type: string}}
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                size: {type: integer}
+                public: {type: boolean, default: false}
+      responses:
+        '204': {description: Updated}
+`
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	var normalized *openapi3filter.NormalizedRequest
+	handler := openapi3filter.NewValidator(router).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ok bool
+		normalized, ok = openapi3filter.NormalizedRequestFromContext(r.Context())
+		require.True(t, ok)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	r := httptest.NewRequest(http.MethodPut, "/files/my%20notes/a%2Cb,c?tags=x,y", strings.NewReader(`{"size": 3}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header["X-Owner"] = []string{"  alice "}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+
+	require.Equal(t, "/files/{name}/{path}", normalized.Route.Path)
+	require.Equal(t, map[string]string{"name": "my notes", "path": "a,b,c"}, normalized.PathParams)
+	require.Equal(t, map[string]interface{}{"name": "my notes", "path": []interface{}{"a,b", "c"}}, normalized.Path)
+	require.Equal(t, map[string]interface{}{"tags": []interface{}{"x", "y"}, "limit": int32(10)}, normalized.Query)
+	require.Equal(t, "alice", normalized.Headers.Get("X-Owner"))
+	require.Equal(t, map[string]interface{}{"size": 3.0, "public": false}, normalized.Body)
+
+	_, ok := openapi3filter.NormalizedRequestFromContext(r.Context())
+	require.False(t, ok)
+}
@@ -0,0 +1,12 @@
+// Package openapi3filtertest provides an httptest.Server validating the requests
+// it receives and the responses it sends against an OpenAPI 3 document,
+// so that integration tests also check the contract of the served API:
+//
+//	func TestPets(t *testing.T) {
+//		srv := openapi3filtertest.NewServer(t, router, handler)
+//		resp, err := http.Get(srv.URL + "/pets")
+//		...
+//	}
+//
+// Violations are reported as test errors once the test and its subtests complete.
+package openapi3filtertest
@@ -0,0 +1,92 @@
+package openapi3filtertest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// Violation is a request received, or a response sent, that fails validation.
+type Violation struct {
+	Method string
+	// Path is the path of the request URL, e.g. "/pets/42".
+	Path string
+	Code openapi3filter.ErrCode
+	Err  error
+}
+
+// Error implements the error interface.
+func (v *Violation) Error() string {
+	return fmt.Sprintf("%s %s: %s: %v", v.Method, v.Path, v.Code, v.Err)
+}
+
+// Unwrap returns the validation error.
+func (v *Violation) Unwrap() error {
+	return v.Err
+}
+
+// Server is an httptest.Server serving a handler through an openapi3filter.Validator.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	violations []*Violation
+}
+
+// NewServer starts a Server serving handler, with request and response validation
+// against the routes of router. Invalid requests are answered with a 400 Bad Request
+// without reaching handler; invalid responses are sent as is unless
+// openapi3filter.Strict is given.
+//
+// The server is closed when t and its subtests complete, and t then fails
+// with each violation. Validation messages are logged with t.Log.
+func NewServer(t testing.TB, router routers.Router, handler http.Handler, options ...openapi3filter.ValidatorOption) *Server {
+	t.Helper()
+	s := &Server{}
+	options = append([]openapi3filter.ValidatorOption{
+		openapi3filter.OnLog(func(message string, err error) {
+			t.Logf("%s: %v", message, err)
+		}),
+	}, options...)
+	options = append(options, openapi3filter.OnViolation(s.record))
+	validator := openapi3filter.NewValidator(router, options...)
+	s.Server = httptest.NewServer(validator.Middleware(handler))
+	t.Cleanup(func() {
+		s.Close()
+		for _, violation := range s.Violations() {
+			t.Errorf("OpenAPI violation: %v", violation)
+		}
+	})
+	return s
+}
+
+func (s *Server) record(req *http.Request, code openapi3filter.ErrCode, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.violations = append(s.violations, &Violation{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Code:   code,
+		Err:    err,
+	})
+}
+
+// Violations returns the violations found so far, in the order they were found.
+func (s *Server) Violations() []*Violation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Violation(nil), s.violations...)
+}
+
+// Reset forgets the violations found so far, e.g. once a test has asserted
+// the expected ones.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.violations = nil
+}
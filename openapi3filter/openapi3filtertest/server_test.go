@@ -0,0 +1,113 @@
+package openapi3filtertest_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/openapi3filter/openapi3filtertest"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+      - {name: id, in: path, required: true, schema: {type: integer}}
+      responses:
+        '200':
+          description: Pet
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [name]
+                properties:
+                  name: {type: string}
+`
+
+// recordingT records the errors and cleanups of a test instead of running them.
+type recordingT struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (t *recordingT) Helper()                     {}
+func (t *recordingT) Logf(string, ...interface{}) {}
+func (t *recordingT) Cleanup(f func())            { t.cleanups = append(t.cleanups, f) }
+func (t *recordingT) Errorf(f string, a ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(f, a...))
+}
+
+func (t *recordingT) end() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+func TestServer(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/2") {
+			w.Write([]byte(`{}`))
+			return
+		}
+		w.Write([]byte(`{"name":"Rex"}`))
+	})
+	get := func(srv *openapi3filtertest.Server, path string) int {
+		resp, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("valid traffic", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		srv := openapi3filtertest.NewServer(rt, router, handler)
+		require.Equal(t, http.StatusOK, get(srv, "/pets/1"))
+		rt.end()
+		require.Empty(t, rt.errors)
+	})
+
+	t.Run("violations fail the test", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		srv := openapi3filtertest.NewServer(rt, router, handler)
+		require.Equal(t, http.StatusNotFound, get(srv, "/pets/rex"))
+		require.Equal(t, http.StatusOK, get(srv, "/pets/2"))
+
+		violations := srv.Violations()
+		require.Len(t, violations, 2)
+		require.Equal(t, "GET", violations[0].Method)
+		require.Equal(t, "/pets/rex", violations[0].Path)
+		require.Equal(t, openapi3filter.ErrCode(openapi3filter.ErrCodeRequestInvalid), violations[0].Code)
+		require.Equal(t, openapi3filter.ErrCode(openapi3filter.ErrCodeResponseInvalid), violations[1].Code)
+		require.Empty(t, rt.errors)
+
+		rt.end()
+		require.Len(t, rt.errors, 2)
+		require.True(t, strings.HasPrefix(rt.errors[1], "OpenAPI violation: GET /pets/2: response_invalid: "), rt.errors[1])
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		srv := openapi3filtertest.NewServer(rt, router, handler)
+		require.Equal(t, http.StatusNotFound, get(srv, "/dogs"))
+		require.Len(t, srv.Violations(), 1)
+		srv.Reset()
+		rt.end()
+		require.Empty(t, rt.errors)
+	})
+}
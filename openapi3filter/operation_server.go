@@ -0,0 +1,128 @@
+package openapi3filter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// OperationRequest is a request to an operation, validated.
+type OperationRequest struct {
+	*http.Request
+	Route *routers.Route
+	// Path, Query, Header and Cookie are the values of the parameters of the operation,
+	// as in NormalizedRequest.
+	Path   map[string]interface{}
+	Query  map[string]interface{}
+	Header map[string]interface{}
+	Cookie map[string]interface{}
+	// Body is the request body decoded according to its content type and validated,
+	// with its default values, or nil if the request has no body.
+	Body interface{}
+	// Principal is the principal the request was authenticated as, if any
+	// (see AuthenticationInput.SetPrincipal).
+	Principal interface{}
+}
+
+// DecodeBody stores the body of the request in the value pointed to by v,
+// as json.Unmarshal does.
+func (req *OperationRequest) DecodeBody(v interface{}) error {
+	data, err := json.Marshal(req.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// OperationResponse is the response of an operation.
+type OperationResponse struct {
+	// Status is the status code of the response, which defaults to 200.
+	Status int
+	Header http.Header
+	// Body is encoded with the body encoder of the Content-Type of Header, which defaults to
+	// application/json (see RegisterBodyEncoder), unless it is a []byte written as is.
+	// A nil Body writes no body.
+	Body interface{}
+}
+
+// OperationFunc handles the requests to an operation. Errors are written by the ErrFunc
+// of the server (see OnErr) with ErrCodeOperationFailed and the status code ClassifyError
+// tells, i.e. that of errors implementing StatusCoder or else 500.
+type OperationFunc func(ctx context.Context, req *OperationRequest) (*OperationResponse, error)
+
+// OperationFuncs are the functions handling the operations of a document, by operationId.
+type OperationFuncs map[string]OperationFunc
+
+// NewOperationServer returns an http.Handler serving the operations of doc with funcs,
+// wired by operationId as NewOperationsHandler does: functions receive requests
+// once validated, with their parameters and bodies decoded, and their responses are validated
+// before being written. Invalid responses are replaced with 500 Internal Server Error
+// responses, unless Strict(false) is given.
+func NewOperationServer(doc *openapi3.T, router routers.Router, funcs OperationFuncs, options ...ValidatorOption) (http.Handler, error) {
+	v := NewValidator(router, append([]ValidatorOption{Strict(true)}, options...)...)
+	handlers := make(OperationHandlers, len(funcs))
+	for id, fn := range funcs {
+		handlers[id] = operationHandler(v, fn)
+	}
+	return newOperationsHandler(doc, v, handlers)
+}
+
+func operationHandler(v *Validator, fn OperationFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		normalized, _ := NormalizedRequestFromContext(r.Context())
+		req := &OperationRequest{
+			Request:   r,
+			Route:     normalized.Route,
+			Path:      normalized.Path,
+			Query:     normalized.Query,
+			Header:    normalized.Header,
+			Cookie:    normalized.Cookie,
+			Body:      normalized.Body,
+			Principal: normalized.Principal,
+		}
+
+		resp, err := fn(r.Context(), req)
+		if err == nil {
+			err = writeOperationResponse(w, resp)
+		}
+		if err != nil {
+			v.logFunc("operation failed", err)
+			v.errFunc(w, ClassifyError(err).Status, ErrCodeOperationFailed, err)
+		}
+	})
+}
+
+func writeOperationResponse(w http.ResponseWriter, resp *OperationResponse) error {
+	if resp == nil {
+		resp = &OperationResponse{}
+	}
+	var body []byte
+	switch v := resp.Body.(type) {
+	case nil:
+	case []byte:
+		body = v
+	default:
+		contentType := resp.Header.Get(headerCT)
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		var err error
+		if body, err = encodeBody(v, parseMediaType(contentType)); err != nil {
+			return err
+		}
+		w.Header().Set(headerCT, contentType)
+	}
+	for name, values := range resp.Header {
+		w.Header()[name] = values
+	}
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, err := w.Write(body)
+	return err
+}
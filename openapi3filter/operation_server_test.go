@@ -0,0 +1,154 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+type notFoundError struct{}
+
+func (notFoundError) Error() string   { return "pet not found" }
+func (notFoundError) StatusCode() int { return http.StatusNotFound }
+
+func TestNewOperationServer(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      operationId: createPet
+      parameters:
+      - {name: dryRun, in: query, schema: {type: boolean, default: false}}
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+                tags: {type: array, items: {type: string}, default: []}
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id, name]
+                properties:
+                  id: {type: integer}
+                  name: {type: string}
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+      - {name: id, in: path, required: true, schema: {type: integer}}
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema: {type: object, required: [id], properties: {id: {type: integer}}}
+        '404': {description: Not found}
+`
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	handler, err := openapi3filter.NewOperationServer(doc, router, openapi3filter.OperationFuncs{
+		"createPet": func(ctx context.Context, req *openapi3filter.OperationRequest) (*openapi3filter.OperationResponse, error) {
+			var pet struct {
+				Name string   `json:"name"`
+				Tags []string `json:"tags"`
+			}
+			if err := req.DecodeBody(&pet); err != nil {
+				return nil, err
+			}
+			require.Equal(t, false, req.Query["dryRun"])
+			require.Equal(t, []string{}, pet.Tags)
+			return &openapi3filter.OperationResponse{
+				Status: http.StatusCreated,
+				Body:   map[string]interface{}{"id": 1, "name": pet.Name},
+			}, nil
+		},
+		"getPet": func(ctx context.Context, req *openapi3filter.OperationRequest) (*openapi3filter.OperationResponse, error) {
+			switch req.Path["id"] {
+			case int64(1):
+				return &openapi3filter.OperationResponse{Body: map[string]interface{}{"id": 1}}, nil
+			case int64(2):
+				// The response does not match its schema
+				return &openapi3filter.OperationResponse{Body: map[string]interface{}{"id": "two"}}, nil
+			case int64(3):
+				return nil, errors.New("database is down")
+			}
+			return nil, notFoundError{}
+		},
+	})
+	require.NoError(t, err)
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	for _, test := range []struct {
+		method, path, body string
+		status             int
+		responseBody       string
+	}{
+		{http.MethodPost, "/pets", `{"name": "Rex"}`, http.StatusCreated, `{"id":1,"name":"Rex"}`},
+		{http.MethodPost, "/pets", `{"tags": []}`, http.StatusUnprocessableEntity, ""},
+		{http.MethodGet, "/pets/1", "", http.StatusOK, `{"id":1}`},
+		{http.MethodGet, "/pets/2", "", http.StatusInternalServerError, ""},
+		// The details of server errors are not disclosed
+		{http.MethodGet, "/pets/3", "", http.StatusInternalServerError, `{"errors":[{"status":500,"code":"internal_error","title":"Internal Server Error"}]}`},
+		{http.MethodGet, "/pets/4", "", http.StatusNotFound, `{"errors":[{"status":404,"code":"internal_error","title":"pet not found"}]}`},
+	} {
+		req, err := http.NewRequest(test.method, srv.URL+test.path, strings.NewReader(test.body))
+		require.NoError(t, err)
+		if test.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err)
+		require.Equal(t, test.status, resp.StatusCode, test.path)
+		if test.responseBody != "" {
+			require.Equal(t, test.responseBody, string(body), test.path)
+		}
+	}
+
+	// Errors of the functions go through the ErrFunc of the server
+	var codes []openapi3filter.ErrCode
+	handler, err = openapi3filter.NewOperationServer(doc, router, openapi3filter.OperationFuncs{
+		"createPet": func(ctx context.Context, req *openapi3filter.OperationRequest) (*openapi3filter.OperationResponse, error) {
+			return nil, errors.New("not implemented")
+		},
+		"getPet": func(ctx context.Context, req *openapi3filter.OperationRequest) (*openapi3filter.OperationResponse, error) {
+			return nil, notFoundError{}
+		},
+	}, openapi3filter.OnErr(func(w http.ResponseWriter, status int, code openapi3filter.ErrCode, err error) {
+		codes = append(codes, code)
+		http.Error(w, err.Error(), status)
+	}))
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pets/4", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, "pet not found\n", w.Body.String())
+	require.Equal(t, []openapi3filter.ErrCode{openapi3filter.ErrCodeOperationFailed}, codes)
+}
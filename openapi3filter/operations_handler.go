@@ -0,0 +1,75 @@
+package openapi3filter
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// OperationHandlers are the handlers of the operations of a document, by operationId.
+type OperationHandlers map[string]http.Handler
+
+// NewOperationsHandler returns an http.Handler serving the operations of doc with handlers,
+// wired by operationId: requests are routed with router, validated by a Validator
+// configured with options, then passed on to the handler of their operation,
+// which gets the route of the request and its path parameters with RouteFromContext.
+//
+//	handler, err := openapi3filter.NewOperationsHandler(doc, router, openapi3filter.OperationHandlers{
+//		"listPets": http.HandlerFunc(listPets),
+//		"getPet":   http.HandlerFunc(getPet),
+//	})
+//
+// It returns an openapi3.MultiError of the operations of doc which have no operationId
+// or no handler and of the handlers which match no operation,
+// so that a spec and its server cannot get out of sync unnoticed.
+//
+// Requests the Validator passes on without a route, such as CORS preflight requests
+// it does not handle itself, are answered with 404 Not Found.
+func NewOperationsHandler(doc *openapi3.T, router routers.Router, handlers OperationHandlers, options ...ValidatorOption) (http.Handler, error) {
+	return newOperationsHandler(doc, NewValidator(router, options...), handlers)
+}
+
+func newOperationsHandler(doc *openapi3.T, v *Validator, handlers OperationHandlers) (http.Handler, error) {
+	var errs openapi3.MultiError
+	operationIDs := make(map[string]struct{})
+	doc.WalkOperations(func(path, method string, operation *openapi3.Operation) {
+		id := operation.OperationID
+		switch {
+		case id == "":
+			errs = append(errs, fmt.Errorf("operation %s %s has no operationId", method, path))
+		case handlers[id] == nil:
+			errs = append(errs, fmt.Errorf("operation %q (%s %s) has no handler", id, method, path))
+		}
+		if id != "" {
+			operationIDs[id] = struct{}{}
+		}
+	})
+	ids := make([]string, 0, len(handlers))
+	for id := range handlers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if _, ok := operationIDs[id]; !ok {
+			errs = append(errs, fmt.Errorf("handler %q matches no operation", id))
+		}
+	}
+	if len(errs) != 0 {
+		return nil, errs
+	}
+
+	dispatch := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Requests reach dispatch without a route when the Validator passes them on
+		// unvalidated, i.e. CORS preflight requests when CORSOptions.HandlePreflight is unset.
+		route, _, ok := RouteFromContext(r.Context())
+		if !ok || route.Operation == nil || handlers[route.Operation.OperationID] == nil {
+			http.NotFound(w, r)
+			return
+		}
+		handlers[route.Operation.OperationID].ServeHTTP(w, r)
+	})
+	return v.Middleware(dispatch), nil
+}
@@ -0,0 +1,125 @@
+package openapi3filter_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+const operationsSpec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {type: string}}
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+      - {name: id, in: path, required: true, schema: {type: integer}}
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema: {type: string}
+`
+
+func TestNewOperationsHandler(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(operationsSpec))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	handler, err := openapi3filter.NewOperationsHandler(doc, router, openapi3filter.OperationHandlers{
+		"listPets": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `["Rex"]`)
+		}),
+		"getPet": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, ok := openapi3filter.RouteFromContext(r.Context())
+			require.True(t, ok)
+			require.Equal(t, "/pets/{id}", route.Path)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `"pet %s"`, pathParams["id"])
+		}),
+	})
+	require.NoError(t, err)
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	for _, test := range []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/pets", http.StatusOK, `["Rex"]`},
+		{"/pets/42", http.StatusOK, `"pet 42"`},
+		{"/pets/rex", http.StatusNotFound, ""},
+		{"/owners", http.StatusNotFound, ""},
+	} {
+		resp, err := http.Get(srv.URL + test.path)
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err)
+		require.Equal(t, test.status, resp.StatusCode, test.path)
+		if test.body != "" {
+			require.Equal(t, test.body, string(body), test.path)
+		}
+	}
+}
+
+func TestNewOperationsHandlerMismatch(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(strings.Replace(operationsSpec, "operationId: listPets", "", 1)))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	noop := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	_, err = openapi3filter.NewOperationsHandler(doc, router, openapi3filter.OperationHandlers{
+		"listPets":  noop,
+		"createPet": noop,
+	})
+	require.EqualError(t, err, `operation GET /pets has no operationId | `+
+		`operation "getPet" (GET /pets/{id}) has no handler | `+
+		`handler "createPet" matches no operation | `+
+		`handler "listPets" matches no operation`)
+}
+
+func TestNewOperationsHandlerPreflightPassthrough(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(operationsSpec))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	noop := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler, err := openapi3filter.NewOperationsHandler(doc, router, openapi3filter.OperationHandlers{
+		"listPets": noop,
+		"getPet":   noop,
+	}, openapi3filter.CORS(openapi3filter.CORSOptions{}))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodOptions, "/pets", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	w := httptest.NewRecorder()
+	require.NotPanics(t, func() { handler.ServeHTTP(w, req) })
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
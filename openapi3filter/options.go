@@ -1,6 +1,9 @@
 package openapi3filter
 
-import "github.com/getkin/kin-openapi/openapi3"
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
 
 // DefaultOptions do not set an AuthenticationFunc.
 // A spec with security schemes defined will not pass validation
@@ -21,6 +24,57 @@ type Options struct {
 
 	MultiError bool
 
+	// MaxDecompressedBodySize limits the size, in bytes, of request bodies once
+	// decompressed according to their Content-Encoding (see RegisterContentDecoder).
+	// It defaults to DefaultMaxDecompressedBodySize.
+	MaxDecompressedBodySize int64
+
+	// BodyLimits, when set, limits the nesting depth, number of object members, array
+	// lengths and string lengths of JSON request bodies, which are rejected with
+	// ErrBodyTooComplex before they are decoded.
+	BodyLimits *BodyLimits
+
+	// MaxFileSize limits the size, in bytes, of the files uploaded as properties of type string
+	// and format binary of multipart/form-data request bodies, unless their Encoding object
+	// sets ExtMaxFileSize. It is not limited when zero.
+	MaxFileSize int64
+
+	// Set MaxEmbeddedContentSize so validation decodes the content embedded in strings,
+	// such as base64 encoded JSON, and validates it against the contentSchema of their schema.
+	// It limits the size, in bytes, of decoded content. See openapi3.EnableContentValidation.
+	MaxEmbeddedContentSize int
+
+	// Set StrictIntegerFormats so values of format int64 beyond what JSON numbers
+	// represent exactly are invalid. See openapi3.EnableStrictIntegerFormats.
+	StrictIntegerFormats bool
+
+	// SchemaErrorVerbosity sets how much the schema errors of validation print.
+	// See openapi3.SetSchemaErrorVerbosity.
+	SchemaErrorVerbosity openapi3.SchemaErrorVerbosity
+
+	// ValidateRequest fails on values of query parameters without allowReserved that hold
+	// reserved characters which are not percent-encoded (e.g. "?path=/a/b" rather than
+	// "?path=%2Fa%2Fb"). Set AllowUnencodedReserved to accept them, for clients that leave
+	// some of them as is (e.g. ":" in dates).
+	AllowUnencodedReserved bool
+
+	// ResponseHeaderChecks validates standard response headers on the responses of all
+	// operations, e.g. StandardHeaderChecks(), besides the headers the responses declare.
+	ResponseHeaderChecks []ResponseHeaderCheck
+
+	// Logger, when set, logs each validation failure of requests and responses,
+	// and failed security requirements as authentication failures, at the warn level
+	// with the operation, location (see Instrumentation) and code (see ClassifyError)
+	// of the failure. Route misses are logged by routers, see gorillamux.WithLogger.
+	Logger routers.Logger
+
+	// DeprecationFunc, when set, is called when requests use deprecated operations,
+	// parameters or body properties, which does not make them invalid.
+	DeprecationFunc DeprecationFunc
+
+	// warningFunc is set by ValidateRequestResult to collect warnings.
+	warningFunc func(warning *Warning)
+
 	// See NoopAuthenticationFunc
 	AuthenticationFunc AuthenticationFunc
 
@@ -28,6 +82,23 @@ type Options struct {
 	// request. If true, then they are not set
 	SkipSettingDefaults bool
 
+	// Set SkipRestoringRequestBody so ValidateRequest consumes the request body
+	// rather than putting it back in the request once read, when it is not read
+	// after validation. Its buffer is then reused by later validations, and
+	// default values are not set in the body.
+	SkipRestoringRequestBody bool
+
+	// Set Concurrency above 1 so ValidateRequest validates path parameters, query
+	// parameters and the body of requests concurrently, with at most Concurrency
+	// goroutines, once header and cookie parameters are validated. Errors are
+	// reported in the same order as without concurrency, but all parts are validated
+	// even when MultiError is not set. Body decoders, ParamDecoder and Tracer must
+	// then be safe for concurrent use.
+	Concurrency int
+
+	// Tracer, when set, starts spans around validation steps. See Tracer.
+	Tracer Tracer
+
 	customSchemaErrorFunc CustomSchemaErrorFunc
 }
 
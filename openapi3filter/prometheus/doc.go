@@ -0,0 +1,46 @@
+// Package prometheus implements openapi3filter.Instrumentation with Prometheus metrics.
+//
+// Metrics are exposed in the Prometheus text format by the Metrics handler,
+// without depending on the Prometheus client library:
+//
+//	metrics := prometheus.New()
+//	validator := openapi3filter.NewValidator(router, openapi3filter.WithInstrumentation(metrics))
+//	http.Handle("/metrics", metrics)
+//
+// The exported metrics are:
+//   - openapi_validation_failures_total, a counter of validation errors
+//     labeled by operation, location and code;
+//   - openapi_validation_duration_seconds, a histogram of validation latencies
+//     labeled by operation and phase (request or response).
+//
+// Codes are those of openapi3filter.ErrorJSON, e.g. "schema_mismatch" or "missing_required".
+//
+// Applications already registering metrics with the Prometheus client library
+// (github.com/prometheus/client_golang) implement openapi3filter.Instrumentation
+// with its collectors instead, so that they are served by its registry:
+//
+//	type instrumentation struct {
+//		failures  *prometheus.CounterVec
+//		durations *prometheus.HistogramVec
+//	}
+//
+//	func (i *instrumentation) ValidationFailed(operation, location, code string) {
+//		i.failures.WithLabelValues(operation, location, code).Inc()
+//	}
+//
+//	func (i *instrumentation) ValidationDuration(operation, phase string, duration time.Duration) {
+//		i.durations.WithLabelValues(operation, phase).Observe(duration.Seconds())
+//	}
+//
+//	validator := openapi3filter.NewValidator(router, openapi3filter.WithInstrumentation(&instrumentation{
+//		failures: promauto.NewCounterVec(prometheus.CounterOpts{
+//			Name: "openapi_validation_failures_total",
+//			Help: "Count of validation errors.",
+//		}, []string{"operation", "location", "code"}),
+//		durations: promauto.NewHistogramVec(prometheus.HistogramOpts{
+//			Name:    "openapi_validation_duration_seconds",
+//			Help:    "Time taken by the validation of requests and responses.",
+//			Buckets: prometheus.DefBuckets,
+//		}, []string{"operation", "phase"}),
+//	}))
+package prometheus
@@ -0,0 +1,225 @@
+package prometheus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the buckets of the duration histogram.
+var DefaultBuckets = []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1}
+
+// ContentType is the media type of the Prometheus text format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Option allows tweaking Metrics
+type Option func(*Metrics)
+
+// WithNamespace sets the prefix of metric names. It defaults to "openapi".
+func WithNamespace(namespace string) Option {
+	return func(m *Metrics) { m.namespace = namespace }
+}
+
+// WithBuckets sets the upper bounds, in seconds, of the buckets of the duration histogram.
+func WithBuckets(buckets ...float64) Option {
+	return func(m *Metrics) {
+		m.buckets = append([]float64(nil), buckets...)
+		sort.Float64s(m.buckets)
+	}
+}
+
+// Metrics counts validation failures and measures validation latencies.
+// It is safe for concurrent use: each series is updated atomically,
+// so observations do not contend with each other nor with WriteTo.
+type Metrics struct {
+	namespace string
+	buckets   []float64
+
+	failures  sync.Map // failureKey -> *uint64
+	durations sync.Map // durationKey -> *histogram
+}
+
+type failureKey struct {
+	operation, location, code string
+}
+
+type durationKey struct {
+	operation, phase string
+}
+
+// histogram is updated with atomic operations only.
+type histogram struct {
+	count   uint64
+	sumBits uint64   // math.Float64bits of the sum
+	counts  []uint64 // per bucket, not cumulative
+}
+
+func (h *histogram) observe(buckets []float64, seconds float64) {
+	for i, bound := range buckets {
+		if seconds <= bound {
+			atomic.AddUint64(&h.counts[i], 1)
+			break
+		}
+	}
+	for {
+		old := atomic.LoadUint64(&h.sumBits)
+		sum := math.Float64bits(math.Float64frombits(old) + seconds)
+		if atomic.CompareAndSwapUint64(&h.sumBits, old, sum) {
+			break
+		}
+	}
+	atomic.AddUint64(&h.count, 1)
+}
+
+var (
+	_ openapi3filter.Instrumentation = (*Metrics)(nil)
+	_ http.Handler                   = (*Metrics)(nil)
+)
+
+// New returns empty Metrics.
+func New(opts ...Option) *Metrics {
+	m := &Metrics{
+		namespace: "openapi",
+		buckets:   DefaultBuckets,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// ValidationFailed implements openapi3filter.Instrumentation.
+func (m *Metrics) ValidationFailed(operation, location, code string) {
+	key := failureKey{operation: operation, location: location, code: code}
+	counter, ok := m.failures.Load(key)
+	if !ok {
+		counter, _ = m.failures.LoadOrStore(key, new(uint64))
+	}
+	atomic.AddUint64(counter.(*uint64), 1)
+}
+
+// ValidationDuration implements openapi3filter.Instrumentation.
+func (m *Metrics) ValidationDuration(operation, phase string, duration time.Duration) {
+	key := durationKey{operation: operation, phase: phase}
+	h, ok := m.durations.Load(key)
+	if !ok {
+		h, _ = m.durations.LoadOrStore(key, &histogram{counts: make([]uint64, len(m.buckets))})
+	}
+	h.(*histogram).observe(m.buckets, duration.Seconds())
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	m.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text format, sorted by labels.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	failures := m.namespace + "_validation_failures_total"
+	fmt.Fprintf(cw, "# HELP %s Count of validation errors.\n", failures)
+	fmt.Fprintf(cw, "# TYPE %s counter\n", failures)
+	var failureKeys []failureKey
+	m.failures.Range(func(key, _ interface{}) bool {
+		failureKeys = append(failureKeys, key.(failureKey))
+		return true
+	})
+	sort.Slice(failureKeys, func(i, j int) bool {
+		a, b := failureKeys[i], failureKeys[j]
+		if a.operation != b.operation {
+			return a.operation < b.operation
+		}
+		if a.location != b.location {
+			return a.location < b.location
+		}
+		return a.code < b.code
+	})
+	for _, key := range failureKeys {
+		counter, _ := m.failures.Load(key)
+		fmt.Fprintf(cw, "%s{%s} %d\n", failures,
+			labels("operation", key.operation, "location", key.location, "code", key.code), atomic.LoadUint64(counter.(*uint64)))
+	}
+
+	durations := m.namespace + "_validation_duration_seconds"
+	fmt.Fprintf(cw, "# HELP %s Time taken by the validation of requests and responses.\n", durations)
+	fmt.Fprintf(cw, "# TYPE %s histogram\n", durations)
+	var durationKeys []durationKey
+	m.durations.Range(func(key, _ interface{}) bool {
+		durationKeys = append(durationKeys, key.(durationKey))
+		return true
+	})
+	sort.Slice(durationKeys, func(i, j int) bool {
+		a, b := durationKeys[i], durationKeys[j]
+		if a.operation != b.operation {
+			return a.operation < b.operation
+		}
+		return a.phase < b.phase
+	})
+	for _, key := range durationKeys {
+		value, _ := m.durations.Load(key)
+		h := value.(*histogram)
+		count := atomic.LoadUint64(&h.count)
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += atomic.LoadUint64(&h.counts[i])
+			fmt.Fprintf(cw, "%s_bucket{%s} %d\n", durations,
+				labels("operation", key.operation, "phase", key.phase, "le", formatFloat(bound)), cumulative)
+		}
+		if count < cumulative {
+			// Observations made meanwhile are in the buckets but not in count yet
+			count = cumulative
+		}
+		fmt.Fprintf(cw, "%s_bucket{%s} %d\n", durations,
+			labels("operation", key.operation, "phase", key.phase, "le", "+Inf"), count)
+		sum := math.Float64frombits(atomic.LoadUint64(&h.sumBits))
+		fmt.Fprintf(cw, "%s_sum{%s} %s\n", durations, labels("operation", key.operation, "phase", key.phase), formatFloat(sum))
+		fmt.Fprintf(cw, "%s_count{%s} %d\n", durations, labels("operation", key.operation, "phase", key.phase), count)
+	}
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// labels formats name and value pairs as Prometheus labels.
+func labels(pairs ...string) string {
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, pairs[i]+`="`+labelValueEscaper.Replace(pairs[i+1])+`"`)
+	}
+	return strings.Join(parts, ",")
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
@@ -0,0 +1,130 @@
+package prometheus_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/openapi3filter/prometheus"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      operationId: createPet
+      parameters:
+      - {name: limit, in: query, schema: {type: integer, maximum: 10}}
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id: {type: integer}
+`
+
+func TestMetrics(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	metrics := prometheus.New(prometheus.WithBuckets(60, 30))
+	validator := openapi3filter.NewValidator(router,
+		openapi3filter.WithInstrumentation(metrics),
+		openapi3filter.ValidationOptions(openapi3filter.Options{MultiError: true}),
+		openapi3filter.OnLog(func(string, error) {}),
+	)
+	handler := validator.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "1"}`))
+	}))
+
+	for _, target := range []string{"/pets", "/pets?limit=20", "/pets?limit=x", "/owners"} {
+		r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(`{}`))
+		r.Header.Set("Content-Type", "application/json")
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader(`{"name": "Tom"}`))
+	r.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	w := httptest.NewRecorder()
+	metrics.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, prometheus.ContentType, w.Header().Get("Content-Type"))
+
+	// Durations vary, so only their counts are checked
+	var lines []string
+	for _, line := range strings.Split(w.Body.String(), "\n") {
+		if !strings.HasPrefix(line, "openapi_validation_duration_seconds_sum") {
+			lines = append(lines, line)
+		}
+	}
+	require.Equal(t, []string{
+		"# HELP openapi_validation_failures_total Count of validation errors.",
+		"# TYPE openapi_validation_failures_total counter",
+		`openapi_validation_failures_total{operation="",location="route",code="route_not_found"} 1`,
+		`openapi_validation_failures_total{operation="createPet",location="body",code="schema_mismatch"} 3`,
+		`openapi_validation_failures_total{operation="createPet",location="query",code="invalid_format"} 1`,
+		`openapi_validation_failures_total{operation="createPet",location="query",code="schema_mismatch"} 1`,
+		`openapi_validation_failures_total{operation="createPet",location="response",code="schema_mismatch"} 1`,
+		"# HELP openapi_validation_duration_seconds Time taken by the validation of requests and responses.",
+		"# TYPE openapi_validation_duration_seconds histogram",
+		`openapi_validation_duration_seconds_bucket{operation="createPet",phase="request",le="30"} 4`,
+		`openapi_validation_duration_seconds_bucket{operation="createPet",phase="request",le="60"} 4`,
+		`openapi_validation_duration_seconds_bucket{operation="createPet",phase="request",le="+Inf"} 4`,
+		`openapi_validation_duration_seconds_count{operation="createPet",phase="request"} 4`,
+		`openapi_validation_duration_seconds_bucket{operation="createPet",phase="response",le="30"} 1`,
+		`openapi_validation_duration_seconds_bucket{operation="createPet",phase="response",le="60"} 1`,
+		`openapi_validation_duration_seconds_bucket{operation="createPet",phase="response",le="+Inf"} 1`,
+		`openapi_validation_duration_seconds_count{operation="createPet",phase="response"} 1`,
+		"",
+	}, lines)
+}
+
+func TestMetricsConcurrent(t *testing.T) {
+	metrics := prometheus.New()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				metrics.ValidationFailed("createPet", openapi3filter.LocationBody, openapi3filter.ErrorCodeSchemaMismatch)
+				metrics.ValidationDuration("createPet", "request", time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var b strings.Builder
+	_, err := metrics.WriteTo(&b)
+	require.NoError(t, err)
+	require.Contains(t, b.String(), `openapi_validation_failures_total{operation="createPet",location="body",code="schema_mismatch"} 8000`+"\n")
+	require.Contains(t, b.String(), `openapi_validation_duration_seconds_count{operation="createPet",phase="request"} 8000`+"\n")
+	require.Contains(t, b.String(), `openapi_validation_duration_seconds_sum{operation="createPet",phase="request"} 8`)
+}
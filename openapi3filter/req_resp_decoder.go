@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"mime"
 	"mime/multipart"
 	"net/http"
@@ -76,6 +77,20 @@ func (e *ParseError) innerError() string {
 	return strings.Join(msg, ": ")
 }
 
+// MarshalJSON encodes the error for API responses (see ErrorJSON).
+func (e *ParseError) MarshalJSON() ([]byte, error) {
+	object := &ErrorJSON{Code: ErrorCodeInvalidFormat, Detail: e.innerError()}
+	if p := e.Path(); len(p) > 0 {
+		var b strings.Builder
+		for _, v := range p {
+			b.WriteByte('/')
+			b.WriteString(escapeJSONPointer(fmt.Sprintf("%v", v)))
+		}
+		object.Pointer = b.String()
+	}
+	return json.Marshal(object)
+}
+
 // RootCause returns a root cause of ParseError.
 func (e *ParseError) RootCause() error {
 	if v, ok := e.Cause.(*ParseError); ok {
@@ -244,6 +259,28 @@ func decodeStyledParameter(param *openapi3.Parameter, input *RequestValidationIn
 		if len(input.GetQueryParams()) == 0 {
 			return nil, false, nil
 		}
+		if param.AllowReserved {
+			// Reserved characters are data: split values on delimiters before unescaping them
+			dec = &urlValuesDecoder{values: rawQueryValues(input.Request.URL.RawQuery), unescape: unescapeReserved}
+			break
+		}
+		options := input.Options
+		if options == nil {
+			options = DefaultOptions
+		}
+		if !options.AllowUnencodedReserved || options.warningFunc != nil {
+			if err := checkReservedQueryValues(param, sm, input.Request.URL.RawQuery); err != nil {
+				if !options.AllowUnencodedReserved {
+					return nil, false, err
+				}
+				options.warningFunc(&Warning{
+					Kind:      WarningCoercion,
+					Input:     input,
+					Parameter: param,
+					Message:   fmt.Sprintf("parameter %q in query accepted leniently: %v", param.Name, err),
+				})
+			}
+		}
 		dec = &urlValuesDecoder{values: input.GetQueryParams()}
 	case openapi3.ParameterInHeader:
 		dec = &headerParamDecoder{header: input.Request.Header}
@@ -491,6 +528,50 @@ func cutPrefix(raw, prefix string) (string, error) {
 // urlValuesDecoder decodes values of query parameters.
 type urlValuesDecoder struct {
 	values url.Values
+	// unescape, when set, decodes values that are still percent-encoded,
+	// once they are split into items and properties.
+	unescape func(string) (string, error)
+}
+
+func (d *urlValuesDecoder) unescapeValue(v string) (string, error) {
+	if d.unescape == nil {
+		return v, nil
+	}
+	u, err := d.unescape(v)
+	if err != nil {
+		return "", &ParseError{Kind: KindInvalidFormat, Value: v, Cause: err}
+	}
+	return u, nil
+}
+
+func (d *urlValuesDecoder) unescapeValues(values []string) ([]string, error) {
+	if d.unescape == nil {
+		return values, nil
+	}
+	unescaped := make([]string, 0, len(values))
+	for _, v := range values {
+		u, err := d.unescapeValue(v)
+		if err != nil {
+			return nil, err
+		}
+		unescaped = append(unescaped, u)
+	}
+	return unescaped, nil
+}
+
+func (d *urlValuesDecoder) unescapeProps(props map[string]string) (map[string]string, error) {
+	if d.unescape == nil || props == nil {
+		return props, nil
+	}
+	unescaped := make(map[string]string, len(props))
+	for name, v := range props {
+		u, err := d.unescapeValue(v)
+		if err != nil {
+			return nil, err
+		}
+		unescaped[name] = u
+	}
+	return unescaped, nil
 }
 
 func (d *urlValuesDecoder) DecodePrimitive(param string, sm *openapi3.SerializationMethod, schema *openapi3.SchemaRef) (interface{}, bool, error) {
@@ -504,10 +585,14 @@ func (d *urlValuesDecoder) DecodePrimitive(param string, sm *openapi3.Serializat
 		return nil, ok, nil
 	}
 
+	value, err := d.unescapeValue(values[0])
+	if err != nil {
+		return nil, ok, err
+	}
 	if schema.Value.Type == "" && schema.Value.Pattern != "" {
-		return values[0], ok, nil
+		return value, ok, nil
 	}
-	val, err := parsePrimitive(values[0], schema)
+	val, err := parsePrimitive(value, schema)
 	return val, ok, err
 }
 
@@ -528,10 +613,23 @@ func (d *urlValuesDecoder) DecodeArray(param string, sm *openapi3.SerializationM
 			delim = ","
 		case "spaceDelimited":
 			delim = " "
+			if d.unescape != nil {
+				delim = "%20"
+			}
 		case "pipeDelimited":
 			delim = "|"
 		}
-		values = strings.Split(values[0], delim)
+		// Items of every occurrence of the parameter are gathered, so that
+		// the schema (e.g. maxItems) is checked against all of them.
+		var items []string
+		for _, v := range values {
+			items = append(items, strings.Split(v, delim)...)
+		}
+		values = items
+	}
+	values, err := d.unescapeValues(values)
+	if err != nil {
+		return nil, ok, err
 	}
 	val, err := d.parseArray(values, sm, schema)
 	return val, ok, err
@@ -552,13 +650,14 @@ func (d *urlValuesDecoder) parseArray(raw []string, sm *openapi3.SerializationMe
 			return nil, fmt.Errorf("item %d: %w", i, err)
 		}
 
-		// If the items are nil, then the array is nil. There shouldn't be case where some values are actual primitive
-		// values and some are nil values.
-		if item == nil {
-			return nil, nil
-		}
 		value = append(value, item)
 	}
+	// A single empty value is an empty parameter (see allowEmptyValue). Other empty
+	// items are kept, so that the array is validated with them (e.g. against minItems
+	// or the nullability of items) rather than skipped.
+	if len(value) == 1 && value[0] == nil {
+		return nil, nil
+	}
 	return value, nil
 }
 
@@ -663,6 +762,9 @@ func (d *urlValuesDecoder) DecodeObject(param string, sm *openapi3.Serialization
 	}
 
 	props, err := propsFn(d.values)
+	if err == nil {
+		props, err = d.unescapeProps(props)
+	}
 	if err != nil {
 		return nil, false, err
 	}
@@ -672,7 +774,7 @@ func (d *urlValuesDecoder) DecodeObject(param string, sm *openapi3.Serialization
 
 	// check the props
 	found := false
-	for propName := range schema.Value.Properties {
+	for propName := range schema.Value.Properties.Map() {
 		if _, ok := props[propName]; ok {
 			found = true
 			break
@@ -682,6 +784,65 @@ func (d *urlValuesDecoder) DecodeObject(param string, sm *openapi3.Serialization
 	return val, found, err
 }
 
+// queryReservedCharacters are the reserved characters of RFC 3986 that must be percent-encoded
+// in values of query parameters without allowReserved. Those delimiting query parameters
+// and their values ("&", "=" and ","), as well as "+" standing for a space, are left out.
+const queryReservedCharacters = ":/?#[]@!$'()*;"
+
+// rawQueryValues parses a query string like url.ParseQuery but leaves values percent-encoded.
+func rawQueryValues(rawQuery string) url.Values {
+	values := make(url.Values)
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		key, value := pair, ""
+		if i := strings.Index(pair, "="); i >= 0 {
+			key, value = pair[:i], pair[i+1:]
+		}
+		if key, err := url.QueryUnescape(key); err == nil {
+			values[key] = append(values[key], value)
+		}
+	}
+	return values
+}
+
+// unescapeReserved decodes a value of a query parameter with allowReserved,
+// in which "+" is a reserved character rather than a space.
+func unescapeReserved(v string) (string, error) {
+	return url.PathUnescape(v)
+}
+
+// checkReservedQueryValues returns a ParseError when a value of a query parameter
+// without allowReserved contains a reserved character that is not percent-encoded.
+func checkReservedQueryValues(param *openapi3.Parameter, sm *openapi3.SerializationMethod, rawQuery string) error {
+	if !strings.ContainsAny(rawQuery, queryReservedCharacters) {
+		return nil
+	}
+	var properties *openapi3.Schemas
+	if param.Schema != nil && param.Schema.Value != nil && param.Schema.Value.Type == "object" && sm.Style == "form" && sm.Explode {
+		properties = param.Schema.Value.Properties
+	}
+	for key, values := range rawQueryValues(rawQuery) {
+		owned := key == param.Name ||
+			(sm.Style == "deepObject" && strings.HasPrefix(key, param.Name+"[")) ||
+			properties.Value(key) != nil
+		if !owned {
+			continue
+		}
+		for _, value := range values {
+			if i := strings.IndexAny(value, queryReservedCharacters); i >= 0 {
+				return &ParseError{
+					Kind:   KindInvalidFormat,
+					Value:  value,
+					Reason: fmt.Sprintf("reserved character %q must be percent-encoded as allowReserved is not set", value[i]),
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // headerParamDecoder decodes values of header parameters.
 type headerParamDecoder struct {
 	header http.Header
@@ -849,7 +1010,7 @@ func propsFromString(src, propDelim, valueDelim string) (map[string]string, erro
 // The function returns an error when an error happened while parse object's properties.
 func makeObject(props map[string]string, schema *openapi3.SchemaRef) (map[string]interface{}, error) {
 	obj := make(map[string]interface{})
-	for propName, propSchema := range schema.Value.Properties {
+	for propName, propSchema := range schema.Value.Properties.Map() {
 		value, err := parsePrimitive(props[propName], propSchema)
 		if err != nil {
 			if v, ok := err.(*ParseError); ok {
@@ -876,19 +1037,44 @@ func parseArray(raw []string, schemaRef *openapi3.SchemaRef) ([]interface{}, err
 			return nil, fmt.Errorf("item %d: %w", i, err)
 		}
 
-		// If the items are nil, then the array is nil. There shouldn't be case where some values are actual primitive
-		// values and some are nil values.
-		if item == nil {
-			return nil, nil
-		}
 		value = append(value, item)
 	}
+	// A single empty value is an empty parameter (see allowEmptyValue). Other empty
+	// items are kept, so that the array is validated with them (e.g. against minItems
+	// or the nullability of items) rather than skipped.
+	if len(value) == 1 && value[0] == nil {
+		return nil, nil
+	}
 	return value, nil
 }
 
 // parsePrimitive returns a value that is created by parsing a source string to a primitive type
 // that is specified by a schema. The function returns nil when the source string is empty.
 // The function panics when a schema has a non-primitive type.
+// decodedDefault returns the default value of a parameter as its value would be decoded,
+// i.e. with integers as int64 values, or int32 values with format int32, rather than float64 ones.
+func decodedDefault(value interface{}, schema *openapi3.Schema) interface{} {
+	switch v := value.(type) {
+	case float64:
+		if schema.Type == openapi3.TypeInteger && v == math.Trunc(v) {
+			if schema.Format == "int32" {
+				return int32(v)
+			}
+			return int64(v)
+		}
+	case []interface{}:
+		if schema.Items == nil || schema.Items.Value == nil {
+			return value
+		}
+		values := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			values = append(values, decodedDefault(item, schema.Items.Value))
+		}
+		return values
+	}
+	return value
+}
+
 func parsePrimitive(raw string, schema *openapi3.SchemaRef) (interface{}, error) {
 	if raw == "" {
 		return nil, nil
@@ -1046,7 +1232,7 @@ func urlencodedBodyDecoder(body io.Reader, header http.Header, schema *openapi3.
 	if schema.Value.Type != "object" {
 		return nil, errors.New("unsupported schema of request body")
 	}
-	for propName, propSchema := range schema.Value.Properties {
+	for propName, propSchema := range schema.Value.Properties.Map() {
 		switch propSchema.Value.Type {
 		case "object":
 			return nil, fmt.Errorf("unsupported schema of request body's property %q", propName)
@@ -1071,7 +1257,7 @@ func urlencodedBodyDecoder(body io.Reader, header http.Header, schema *openapi3.
 	// Make an object value from form values.
 	obj := make(map[string]interface{})
 	dec := &urlValuesDecoder{values: values}
-	for name, prop := range schema.Value.Properties {
+	for name, prop := range schema.Value.Properties.Map() {
 		var (
 			value interface{}
 			enc   *openapi3.Encoding
@@ -1123,9 +1309,8 @@ func multipartBodyDecoder(body io.Reader, header http.Header, schema *openapi3.S
 		// If the property's schema has type "array" it is means that the form contains a few parts with the same name.
 		// Every such part has a type that is defined by an items schema in the property's schema.
 		var valueSchema *openapi3.SchemaRef
-		var exists bool
-		valueSchema, exists = schema.Value.Properties[name]
-		if !exists {
+		valueSchema = schema.Value.Properties.Value(name)
+		if valueSchema == nil {
 			anyProperties := schema.Value.AdditionalPropertiesAllowed
 			if anyProperties != nil {
 				switch *anyProperties {
@@ -1140,8 +1325,8 @@ func multipartBodyDecoder(body io.Reader, header http.Header, schema *openapi3.S
 			if schema.Value.AdditionalProperties == nil {
 				return nil, &ParseError{Kind: KindOther, Cause: fmt.Errorf("part %s: undefined", name)}
 			}
-			valueSchema, exists = schema.Value.AdditionalProperties.Value.Properties[name]
-			if !exists {
+			valueSchema = schema.Value.AdditionalProperties.Value.Properties.Value(name)
+			if valueSchema == nil {
 				return nil, &ParseError{Kind: KindOther, Cause: fmt.Errorf("part %s: undefined", name)}
 			}
 		}
@@ -1150,7 +1335,13 @@ func multipartBodyDecoder(body io.Reader, header http.Header, schema *openapi3.S
 		}
 
 		var value interface{}
-		if _, value, err = decodeBody(part, http.Header(part.Header), valueSchema, subEncFn); err != nil {
+		if _, ok := bodyDecoders[parseMediaType(part.Header.Get(headerCT))]; !ok && isFileSchema(valueSchema.Value) {
+			// Files of any content type are read as is, unless a decoder is registered for it
+			value, err = FileBodyDecoder(part, http.Header(part.Header), valueSchema, subEncFn)
+		} else {
+			_, value, err = decodeBody(part, http.Header(part.Header), valueSchema, subEncFn)
+		}
+		if err != nil {
 			if v, ok := err.(*ParseError); ok {
 				return nil, &ParseError{path: []interface{}{name}, Cause: v}
 			}
@@ -1160,11 +1351,11 @@ func multipartBodyDecoder(body io.Reader, header http.Header, schema *openapi3.S
 	}
 
 	allTheProperties := make(map[string]*openapi3.SchemaRef)
-	for k, v := range schema.Value.Properties {
+	for k, v := range schema.Value.Properties.Map() {
 		allTheProperties[k] = v
 	}
 	if schema.Value.AdditionalProperties != nil {
-		for k, v := range schema.Value.AdditionalProperties.Value.Properties {
+		for k, v := range schema.Value.AdditionalProperties.Value.Properties.Map() {
 			allTheProperties[k] = v
 		}
 	}
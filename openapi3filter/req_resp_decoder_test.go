@@ -30,14 +30,14 @@ func TestDecodeParameter(t *testing.T) {
 			return &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "array", Items: items}}
 		}
 		objectOf = func(args ...interface{}) *openapi3.SchemaRef {
-			s := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "object", Properties: make(map[string]*openapi3.SchemaRef)}}
+			s := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "object", Properties: openapi3.NewSchemas()}}
 			if len(args)%2 != 0 {
 				panic("invalid arguments. must be an even number of arguments")
 			}
 			for i := 0; i < len(args)/2; i++ {
 				propName := args[i*2].(string)
 				propSchema := args[i*2+1].(*openapi3.SchemaRef)
-				s.Value.Properties[propName] = propSchema
+				s.Value.Properties.Set(propName, propSchema)
 			}
 			return s
 		}
@@ -1078,6 +1078,104 @@ func TestDecodeParameter(t *testing.T) {
 	}
 }
 
+func TestDecodeQueryParameterAllowReserved(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+	testCases := []struct {
+		name      string
+		param     *openapi3.Parameter
+		query     string
+		allow     bool
+		want      interface{}
+		wantFound bool
+		wantErr   string
+	}{
+		{
+			name:      "reserved characters are kept",
+			param:     &openapi3.Parameter{Name: "q", In: "query", AllowReserved: true, Schema: openapi3.NewStringSchema().NewRef()},
+			query:     "q=a+b/c%2Bd%20e",
+			want:      "a+b/c+d e",
+			wantFound: true,
+		},
+		{
+			name:      "without allowReserved, plus is a space",
+			param:     &openapi3.Parameter{Name: "q", In: "query", Schema: openapi3.NewStringSchema().NewRef()},
+			query:     "q=a+b%2Fc%2Bd",
+			want:      "a b/c+d",
+			wantFound: true,
+		},
+		{
+			name:      "encoded delimiters are data",
+			param:     &openapi3.Parameter{Name: "q", In: "query", AllowReserved: true, Explode: boolPtr(false), Schema: openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema()).NewRef()},
+			query:     "q=a%2Cb,c/d",
+			want:      []interface{}{"a,b", "c/d"},
+			wantFound: true,
+		},
+		{
+			name:      "space delimited",
+			param:     &openapi3.Parameter{Name: "q", In: "query", AllowReserved: true, Style: "spaceDelimited", Explode: boolPtr(false), Schema: openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema()).NewRef()},
+			query:     "q=a+b%20c",
+			want:      []interface{}{"a+b", "c"},
+			wantFound: true,
+		},
+		{
+			name:      "deep object",
+			param:     &openapi3.Parameter{Name: "q", In: "query", AllowReserved: true, Style: "deepObject", Explode: boolPtr(true), Schema: openapi3.NewObjectSchema().WithProperty("a", openapi3.NewStringSchema()).NewRef()},
+			query:     "q%5Ba%5D=x+y",
+			want:      map[string]interface{}{"a": "x+y"},
+			wantFound: true,
+		},
+		{
+			name:    "invalid escape",
+			param:   &openapi3.Parameter{Name: "q", In: "query", AllowReserved: true, Schema: openapi3.NewStringSchema().NewRef()},
+			query:   "x=1&q=a%zz",
+			wantErr: `value a%zz: invalid URL escape "%zz"`,
+		},
+		{
+			name:    "unencoded reserved characters are rejected by default",
+			param:   &openapi3.Parameter{Name: "q", In: "query", Schema: openapi3.NewStringSchema().NewRef()},
+			query:   "other=/&q=12:00",
+			wantErr: `value 12:00: reserved character ':' must be percent-encoded as allowReserved is not set`,
+		},
+		{
+			name:      "unencoded reserved characters are accepted when allowed",
+			param:     &openapi3.Parameter{Name: "q", In: "query", Schema: openapi3.NewStringSchema().NewRef()},
+			query:     "q=12:00",
+			allow:     true,
+			want:      "12:00",
+			wantFound: true,
+		},
+		{
+			name:      "encoded reserved characters are accepted",
+			param:     &openapi3.Parameter{Name: "q", In: "query", Schema: openapi3.NewStringSchema().NewRef()},
+			query:     "other=/&q=12%3A00",
+			want:      "12:00",
+			wantFound: true,
+		},
+		{
+			name:    "unencoded reserved characters of exploded objects are rejected",
+			param:   &openapi3.Parameter{Name: "q", In: "query", Schema: openapi3.NewObjectSchema().WithProperty("a", openapi3.NewStringSchema()).NewRef()},
+			query:   "a=(x)",
+			wantErr: `value (x): reserved character '(' must be percent-encoded as allowReserved is not set`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "http://example.com/?"+tc.query, nil)
+			require.NoError(t, err)
+			input := &RequestValidationInput{Request: req, Options: &Options{AllowUnencodedReserved: tc.allow}}
+
+			got, found, err := decodeStyledParameter(tc.param, input)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantFound, found)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestDecodeBody(t *testing.T) {
 	boolPtr := func(b bool) *bool { return &b }
 
@@ -0,0 +1,182 @@
+package openapi3filter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ResponseHeaderCheck validates a standard response header on the responses of all
+// operations, without the header being declared on them (see Options.ResponseHeaderChecks).
+// Headers declared on the matched response are validated against their declaration instead.
+type ResponseHeaderCheck struct {
+	// Name is the name of the header.
+	Name string
+
+	// Required reports whether the response of input must have the header.
+	// The header is optional when Required is nil.
+	Required func(input *ResponseValidationInput) bool
+
+	// Validate checks each value of the header, when present.
+	Validate func(value string) error
+}
+
+// RateLimitHeaderChecks returns the checks of the RateLimit-Limit, RateLimit-Remaining
+// and RateLimit-Reset headers of rate limited APIs: their values are non-negative
+// integers, possibly followed by parameters (e.g. "100, 100;w=60"), and responses
+// with any of them must have all of them.
+func RateLimitHeaderChecks() []ResponseHeaderCheck {
+	names := []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset"}
+	required := func(input *ResponseValidationInput) bool {
+		for _, name := range names {
+			if input.Header.Get(name) != "" {
+				return true
+			}
+		}
+		return false
+	}
+	checks := make([]ResponseHeaderCheck, 0, len(names))
+	for _, name := range names {
+		checks = append(checks, ResponseHeaderCheck{
+			Name:     name,
+			Required: required,
+			Validate: validateRateLimitValue,
+		})
+	}
+	return checks
+}
+
+// RetryAfterHeaderChecks returns the check of the Retry-After header,
+// whose value is an HTTP date or a number of seconds.
+func RetryAfterHeaderChecks() []ResponseHeaderCheck {
+	return []ResponseHeaderCheck{{
+		Name:     "Retry-After",
+		Validate: validateRetryAfterValue,
+	}}
+}
+
+// DeprecationHeaderChecks returns the checks of the Deprecation and Sunset headers.
+// The Deprecation header, which responses of deprecated operations must have, is either
+// a structured date (e.g. "@1688169599"), an HTTP date or "true", as in earlier drafts.
+// The Sunset header is an HTTP date.
+func DeprecationHeaderChecks() []ResponseHeaderCheck {
+	return []ResponseHeaderCheck{
+		{
+			Name: "Deprecation",
+			Required: func(input *ResponseValidationInput) bool {
+				route := input.RequestValidationInput.Route
+				return route != nil && route.Operation != nil && route.Operation.Deprecated
+			},
+			Validate: validateDeprecationValue,
+		},
+		{
+			Name:     "Sunset",
+			Validate: validateHTTPDate,
+		},
+	}
+}
+
+// StandardHeaderChecks returns the rate limit, Retry-After and deprecation header checks.
+func StandardHeaderChecks() []ResponseHeaderCheck {
+	checks := RateLimitHeaderChecks()
+	checks = append(checks, RetryAfterHeaderChecks()...)
+	return append(checks, DeprecationHeaderChecks()...)
+}
+
+func validateRateLimitValue(value string) error {
+	item := value
+	if i := strings.IndexAny(item, ",;"); i >= 0 {
+		item = item[:i]
+	}
+	return validateNonNegativeInteger(strings.TrimSpace(item))
+}
+
+func validateRetryAfterValue(value string) error {
+	if validateNonNegativeInteger(value) == nil {
+		return nil
+	}
+	if validateHTTPDate(value) == nil {
+		return nil
+	}
+	return errors.New("must be an HTTP date or a number of seconds")
+}
+
+func validateDeprecationValue(value string) error {
+	if value == "true" {
+		return nil
+	}
+	if strings.HasPrefix(value, "@") {
+		if _, err := strconv.ParseInt(value[1:], 10, 64); err != nil {
+			return errors.New("must be a date such as @1688169599")
+		}
+		return nil
+	}
+	if validateHTTPDate(value) == nil {
+		return nil
+	}
+	return errors.New("must be a date such as @1688169599")
+}
+
+func validateNonNegativeInteger(value string) error {
+	if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+		return errors.New("must be a non-negative integer")
+	}
+	return nil
+}
+
+func validateHTTPDate(value string) error {
+	if _, err := http.ParseTime(value); err != nil {
+		return errors.New("must be an HTTP date")
+	}
+	return nil
+}
+
+// validateResponseHeaderChecks runs checks on the headers of the response of input
+// that its matched response does not declare.
+func validateResponseHeaderChecks(input *ResponseValidationInput, checks []ResponseHeaderCheck) error {
+	if len(checks) == 0 {
+		return nil
+	}
+	var declared map[string]struct{}
+	if route := input.RequestValidationInput.Route; route != nil && route.Operation != nil {
+		if _, responseRef := route.Operation.Responses.Match(input.Status); responseRef != nil && responseRef.Value != nil {
+			declared = make(map[string]struct{}, len(responseRef.Value.Headers))
+			for name := range responseRef.Value.Headers {
+				declared[http.CanonicalHeaderKey(name)] = struct{}{}
+			}
+		}
+	}
+
+	for _, check := range checks {
+		if _, ok := declared[http.CanonicalHeaderKey(check.Name)]; ok {
+			continue
+		}
+		values := input.Header.Values(check.Name)
+		if len(values) == 0 {
+			if check.Required != nil && check.Required(input) {
+				return &ResponseError{
+					Input:  input,
+					Reason: fmt.Sprintf("response header %q missing", check.Name),
+					kind:   ErrInvalidRequired,
+				}
+			}
+			continue
+		}
+		if check.Validate == nil {
+			continue
+		}
+		for _, value := range values {
+			if err := check.Validate(value); err != nil {
+				return &ResponseError{
+					Input:  input,
+					Reason: fmt.Sprintf("response header %q has invalid value %q", check.Name, value),
+					Err:    err,
+					kind:   ErrSchemaMismatch,
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package openapi3filter
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+func TestResponseHeaderChecks(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: Pets
+          headers:
+            Retry-After:
+              schema: {type: string, enum: [soon]}
+        '429': {description: Too many requests}
+  /cats:
+    get:
+      deprecated: true
+      responses: {'200': {description: Cats}}
+`))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		status int
+		header map[string]string
+		err    string
+		kind   error
+	}{
+		{
+			name: "no headers",
+			path: "/pets",
+		},
+		{
+			name:   "rate limited",
+			path:   "/pets",
+			status: http.StatusTooManyRequests,
+			header: map[string]string{
+				"RateLimit-Limit":     "100, 100;w=60",
+				"RateLimit-Remaining": "0",
+				"RateLimit-Reset":     "42",
+				"Retry-After":         "Wed, 21 Oct 2015 07:28:00 GMT",
+			},
+		},
+		{
+			name:   "incomplete rate limit",
+			path:   "/pets",
+			header: map[string]string{"RateLimit-Limit": "100"},
+			err:    `response header "RateLimit-Remaining" missing`,
+			kind:   ErrInvalidRequired,
+		},
+		{
+			name: "invalid rate limit",
+			path: "/pets",
+			header: map[string]string{
+				"RateLimit-Limit":     "100",
+				"RateLimit-Remaining": "-1",
+				"RateLimit-Reset":     "42",
+			},
+			err:  `response header "RateLimit-Remaining" has invalid value "-1": must be a non-negative integer`,
+			kind: ErrSchemaMismatch,
+		},
+		{
+			name:   "invalid retry after",
+			path:   "/pets",
+			status: http.StatusTooManyRequests,
+			header: map[string]string{"Retry-After": "soon"},
+			err:    `response header "Retry-After" has invalid value "soon": must be an HTTP date or a number of seconds`,
+			kind:   ErrSchemaMismatch,
+		},
+		{
+			name:   "declared retry after",
+			path:   "/pets",
+			header: map[string]string{"Retry-After": "soon"},
+		},
+		{
+			name:   "deprecated",
+			path:   "/cats",
+			header: map[string]string{"Deprecation": "@1688169599", "Sunset": "Wed, 21 Oct 2026 07:28:00 GMT"},
+		},
+		{
+			name: "missing deprecation",
+			path: "/cats",
+			err:  `response header "Deprecation" missing`,
+			kind: ErrInvalidRequired,
+		},
+		{
+			name:   "invalid sunset",
+			path:   "/cats",
+			header: map[string]string{"Deprecation": "true", "Sunset": "2026-10-21"},
+			err:    `response header "Sunset" has invalid value "2026-10-21": must be an HTTP date`,
+			kind:   ErrSchemaMismatch,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			require.NoError(t, err)
+			route, pathParams, err := router.FindRoute(req)
+			require.NoError(t, err)
+
+			status := tc.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			header := http.Header{}
+			for name, value := range tc.header {
+				header.Set(name, value)
+			}
+			err = ValidateResponse(context.Background(), &ResponseValidationInput{
+				RequestValidationInput: &RequestValidationInput{
+					Request:    req,
+					PathParams: pathParams,
+					Route:      route,
+				},
+				Status:  status,
+				Header:  header,
+				Body:    ioutil.NopCloser(strings.NewReader("")),
+				Options: &Options{ResponseHeaderChecks: StandardHeaderChecks()},
+			})
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+			require.True(t, errors.Is(err, tc.kind))
+		})
+	}
+}
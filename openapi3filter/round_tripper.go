@@ -0,0 +1,91 @@
+package openapi3filter
+
+import (
+	"net/http"
+	"time"
+)
+
+// RoundTripper returns an http.RoundTripper which validates the requests sent
+// through next, and the responses received, e.g. to let an API client catch
+// its own bugs. When next is nil, http.DefaultTransport is used.
+//
+// Validation failures are logged and reported to the ViolationFunc. In Strict
+// mode the failure is also returned as the error of the round trip: invalid
+// requests are then not sent at all.
+//
+// Requests are validated without setting default values, and the request
+// sent through next is a copy of the given one so as not to modify it.
+func (v *Validator) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &validatingRoundTripper{v: v, next: next}
+}
+
+type validatingRoundTripper struct {
+	v    *Validator
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *validatingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	v := rt.v
+	req = req.Clone(req.Context())
+	route, pathParams, err := v.router.FindRoute(req)
+	if err != nil {
+		v.fail(req, "", ErrCodeCannotFindRoute, "validation error: failed to find route for "+req.URL.String(), err)
+		if v.strict {
+			closeRequestBody(req)
+			return nil, err
+		}
+		return rt.next.RoundTrip(req)
+	}
+
+	options := v.options
+	options.SkipSettingDefaults = true
+	requestValidationInput := &RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    &options,
+	}
+	operation := routeOperation(route)
+	start := time.Now()
+	err = ValidateRequest(req.Context(), requestValidationInput)
+	v.observeDuration(operation, "request", start)
+	if err != nil {
+		v.fail(req, operation, ErrCodeRequestInvalid, "invalid request", err)
+		if v.strict {
+			closeRequestBody(req)
+			return nil, err
+		}
+	}
+
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	start = time.Now()
+	responseValidationInput, err := NewResponseValidationInput(requestValidationInput, resp)
+	if err != nil {
+		// The body could not be read: the response is unusable anyway
+		return nil, err
+	}
+	err = ValidateResponse(req.Context(), responseValidationInput)
+	v.observeDuration(operation, "response", start)
+	if err != nil {
+		v.fail(req, operation, ErrCodeResponseInvalid, "invalid response", err)
+		if v.strict {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
@@ -0,0 +1,137 @@
+package openapi3filter_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+const roundTripperSpec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+                kind: {type: string, default: cat}
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id: {type: integer}
+`
+
+func TestRoundTripper(t *testing.T) {
+	var received []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received = append(received, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if strings.Contains(string(body), "Bad") {
+			w.Write([]byte(`{"id":"one"}`))
+			return
+		}
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer srv.Close()
+
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(roundTripperSpec))
+	require.NoError(t, err)
+	doc.Servers = openapi3.Servers{{URL: srv.URL}}
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	type violation struct {
+		code openapi3filter.ErrCode
+		err  error
+	}
+	newClient := func(options ...openapi3filter.ValidatorOption) (*http.Client, *[]violation) {
+		var violations []violation
+		options = append(options,
+			openapi3filter.OnLog(func(string, error) {}),
+			openapi3filter.OnViolation(func(_ *http.Request, code openapi3filter.ErrCode, err error) {
+				violations = append(violations, violation{code, err})
+			}))
+		v := openapi3filter.NewValidator(router, options...)
+		return &http.Client{Transport: v.RoundTripper(nil)}, &violations
+	}
+	post := func(client *http.Client, path, body string) (*http.Response, error) {
+		return client.Post(srv.URL+path, "application/json", strings.NewReader(body))
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		received = nil
+		client, violations := newClient()
+		resp, err := post(client, "/pets", `{"name":"Rex"}`)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, `{"id":1}`, string(body))
+		require.Empty(t, *violations)
+		// Defaults are not set in the sent request
+		require.Equal(t, []string{`{"name":"Rex"}`}, received)
+	})
+
+	t.Run("violations are recorded", func(t *testing.T) {
+		received = nil
+		client, violations := newClient()
+		resp, err := post(client, "/pets", `{}`)
+		require.NoError(t, err)
+		resp.Body.Close()
+		resp, err = post(client, "/pets", `{"name":"Bad"}`)
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, `{"id":"one"}`, string(body))
+		resp, err = post(client, "/dogs", `{}`)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		require.Len(t, *violations, 3)
+		require.Equal(t, openapi3filter.ErrCode(openapi3filter.ErrCodeRequestInvalid), (*violations)[0].code)
+		require.Contains(t, (*violations)[0].err.Error(), `property "name" is missing`)
+		require.Equal(t, openapi3filter.ErrCode(openapi3filter.ErrCodeResponseInvalid), (*violations)[1].code)
+		require.Equal(t, openapi3filter.ErrCode(openapi3filter.ErrCodeCannotFindRoute), (*violations)[2].code)
+		require.Len(t, received, 3)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		received = nil
+		client, violations := newClient(openapi3filter.Strict(true))
+		_, err := post(client, "/pets", `{}`)
+		var requestErr *openapi3filter.RequestError
+		require.True(t, errors.As(err, &requestErr))
+		require.Empty(t, received)
+
+		_, err = post(client, "/pets", `{"name":"Bad"}`)
+		var responseErr *openapi3filter.ResponseError
+		require.True(t, errors.As(err, &responseErr))
+		require.Len(t, received, 1)
+		require.Len(t, *violations, 2)
+	})
+}
@@ -0,0 +1,76 @@
+package openapi3filter
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+func init() {
+	routers.RegisterRouteCompiler(func(route *routers.Route) {
+		if route.Operation != nil {
+			compiledRouteOf(route)
+		}
+	})
+}
+
+type compiledRouteKey struct{}
+
+// compiledRoute holds what validating the requests of a route needs,
+// resolved from its operation and path item.
+type compiledRoute struct {
+	security *openapi3.SecurityRequirements
+	// parameters are those of the path item not overridden by the operation,
+	// followed by those of the operation.
+	parameters []*openapi3.Parameter
+}
+
+// compiledRouteOf returns the compiledRoute of route, which routers compute
+// when they are constructed.
+func compiledRouteOf(route *routers.Route) *compiledRoute {
+	return route.Cached(compiledRouteKey{}, func() interface{} { return compileRoute(route) }).(*compiledRoute)
+}
+
+func compileRoute(route *routers.Route) *compiledRoute {
+	operation := route.Operation
+	c := &compiledRoute{security: operation.Security}
+	// If there aren't any security requirements for the operation
+	if c.security == nil && route.Spec != nil {
+		// Use the global security requirements.
+		c.security = &route.Spec.Security
+	}
+
+	operationParameters := operation.Parameters
+	if route.PathItem != nil {
+		for _, parameterRef := range route.PathItem.Parameters {
+			parameter := parameterRef.Value
+			if operationParameters.GetByInAndName(parameter.In, parameter.Name) != nil {
+				continue
+			}
+			c.parameters = append(c.parameters, parameter)
+		}
+	}
+	for _, parameterRef := range operationParameters {
+		c.parameters = append(c.parameters, parameterRef.Value)
+	}
+
+	// Compile the patterns of the schemas now rather than when validating the first request.
+	// Errors are left to be reported by validation.
+	for _, parameter := range c.parameters {
+		if parameter.Schema != nil && parameter.Schema.Value != nil {
+			_ = parameter.Schema.Value.CompilePatterns()
+		}
+		compileContentPatterns(parameter.Content)
+	}
+	if requestBody := operation.RequestBody; requestBody != nil && requestBody.Value != nil {
+		compileContentPatterns(requestBody.Value.Content)
+	}
+	return c
+}
+
+func compileContentPatterns(content openapi3.Content) {
+	for _, mediaType := range content {
+		if mediaType != nil && mediaType.Schema != nil && mediaType.Schema.Value != nil {
+			_ = mediaType.Schema.Value.CompilePatterns()
+		}
+	}
+}
@@ -0,0 +1,17 @@
+// Package tenants validates the requests of many APIs, such as the APIs of the customers
+// of a gateway, with a registry mapping hosts or base paths to their documents,
+// routers and validation options.
+//
+// Tenants are loaded lazily, on the first request to their API, then cached until
+// they are evicted, either to keep at most a number of them or once they expired:
+//
+//	registry := tenants.New(tenants.Host(), func(ctx context.Context, key string) (*tenants.Tenant, error) {
+//		doc, err := loadDocumentOf(ctx, key)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return tenants.NewTenant(doc, nil)
+//	}, tenants.WithMaxTenants(1000))
+//
+//	input, err := registry.ValidateRequest(req)
+package tenants
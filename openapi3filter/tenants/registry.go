@@ -0,0 +1,276 @@
+package tenants
+
+import (
+	"container/list"
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// ErrTenantNotFound is returned when a request is for no tenant of the registry.
+// LoadFunc should return it for unknown keys.
+var ErrTenantNotFound error = &routers.RouteError{Reason: "no matching tenant was found"}
+
+// Tenant is the document of an API, along with its router and validation options.
+type Tenant struct {
+	Doc    *openapi3.T
+	Router routers.Router
+	// Options are the options of the validation of the requests of the tenant.
+	// openapi3filter.DefaultOptions are used when nil.
+	Options *openapi3filter.Options
+}
+
+// NewTenant returns the Tenant of doc, with a gorillamux router.
+func NewTenant(doc *openapi3.T, options *openapi3filter.Options) (*Tenant, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &Tenant{Doc: doc, Router: router, Options: options}, nil
+}
+
+// LoadFunc loads the tenant of key, or returns ErrTenantNotFound.
+// It is called once at a time per key.
+type LoadFunc func(ctx context.Context, key string) (*Tenant, error)
+
+// KeyFunc returns the key of the tenant a request is for, or "" if it is unknown,
+// along with the request to route with the router of that tenant.
+type KeyFunc func(req *http.Request) (key string, routed *http.Request)
+
+// Host returns a KeyFunc keying tenants by the host of requests, without its port,
+// e.g. "acme.api.example.com".
+func Host() KeyFunc {
+	return func(req *http.Request) (string, *http.Request) {
+		host := req.Host
+		if host == "" {
+			host = req.URL.Host
+		}
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		return strings.ToLower(host), req
+	}
+}
+
+// BasePath returns a KeyFunc keying tenants by the first segment of the path of requests,
+// e.g. "acme" for "/acme/pets", which are routed without it, e.g. as "/pets".
+func BasePath() KeyFunc {
+	return func(req *http.Request) (string, *http.Request) {
+		path := strings.TrimPrefix(req.URL.Path, "/")
+		key, rest := path, "/"
+		if i := strings.IndexByte(path, '/'); i >= 0 {
+			key, rest = path[:i], path[i:]
+		}
+		if key == "" {
+			return "", req
+		}
+		routed := new(http.Request)
+		*routed = *req
+		u := *req.URL
+		u.Path = rest
+		u.RawPath = ""
+		if prefix := "/" + key; strings.HasPrefix(req.URL.RawPath, prefix+"/") {
+			u.RawPath = strings.TrimPrefix(req.URL.RawPath, prefix)
+		}
+		routed.URL = &u
+		return key, routed
+	}
+}
+
+// Option allows tweaking a Registry.
+type Option func(*Registry)
+
+// WithMaxTenants makes the registry keep at most max tenants, evicting the least
+// recently used ones. It is not limited by default.
+func WithMaxTenants(max int) Option {
+	return func(r *Registry) { r.maxTenants = max }
+}
+
+// WithTTL makes the registry load tenants again once they were loaded for ttl.
+// They do not expire by default.
+func WithTTL(ttl time.Duration) Option {
+	return func(r *Registry) { r.ttl = ttl }
+}
+
+// Registry maps the keys of tenants to their documents, routers and options.
+// It is safe for concurrent use.
+type Registry struct {
+	key        KeyFunc
+	load       LoadFunc
+	maxTenants int
+	ttl        time.Duration
+
+	mu sync.Mutex
+	// entries holds the elements of lru by key.
+	entries map[string]*list.Element
+	// lru holds the entries, most recently used first.
+	lru *list.List
+}
+
+type entry struct {
+	key string
+	// ready is closed once tenant or err is set.
+	ready    chan struct{}
+	tenant   *Tenant
+	err      error
+	loadedAt time.Time
+}
+
+var _ routers.Router = (*Registry)(nil)
+
+// New returns an empty Registry, where key tells which tenant a request is for,
+// and load loads the tenants of keys.
+func New(key KeyFunc, load LoadFunc, opts ...Option) *Registry {
+	r := &Registry{
+		key:     key,
+		load:    load,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Get returns the tenant of key, loading it if it is not loaded yet,
+// or if it expired. Errors of loads are returned and not cached.
+func (r *Registry) Get(ctx context.Context, key string) (*Tenant, error) {
+	r.mu.Lock()
+	if elem, ok := r.entries[key]; ok {
+		e := elem.Value.(*entry)
+		select {
+		case <-e.ready:
+			if r.ttl > 0 && time.Since(e.loadedAt) >= r.ttl {
+				r.remove(elem)
+				break
+			}
+			r.lru.MoveToFront(elem)
+			r.mu.Unlock()
+			return e.tenant, nil
+		default:
+			// Being loaded
+			r.mu.Unlock()
+			select {
+			case <-e.ready:
+				return e.tenant, e.err
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	e := &entry{key: key, ready: make(chan struct{})}
+	r.entries[key] = r.lru.PushFront(e)
+	r.mu.Unlock()
+
+	e.tenant, e.err = r.load(ctx, key)
+	e.loadedAt = time.Now()
+
+	r.mu.Lock()
+	if e.err != nil {
+		if elem, ok := r.entries[key]; ok && elem.Value == e {
+			r.remove(elem)
+		}
+	} else {
+		r.evict()
+	}
+	close(e.ready)
+	r.mu.Unlock()
+	return e.tenant, e.err
+}
+
+// Store sets the tenant of key, e.g. once its document changed.
+func (r *Registry) Store(key string, tenant *Tenant) {
+	e := &entry{key: key, ready: make(chan struct{}), tenant: tenant, loadedAt: time.Now()}
+	close(e.ready)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if elem, ok := r.entries[key]; ok {
+		r.remove(elem)
+	}
+	r.entries[key] = r.lru.PushFront(e)
+	r.evict()
+}
+
+// Evict removes the tenant of key, which is loaded again by the next request to it.
+func (r *Registry) Evict(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if elem, ok := r.entries[key]; ok {
+		r.remove(elem)
+	}
+}
+
+// Len returns the number of tenants of the registry, loaded or being loaded.
+func (r *Registry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lru.Len()
+}
+
+// remove removes elem from the registry, whose lock is held.
+func (r *Registry) remove(elem *list.Element) {
+	r.lru.Remove(elem)
+	delete(r.entries, elem.Value.(*entry).key)
+}
+
+// evict removes the least recently used tenants beyond maxTenants,
+// but for those being loaded. The lock of the registry is held.
+func (r *Registry) evict() {
+	if r.maxTenants <= 0 {
+		return
+	}
+	for elem := r.lru.Back(); elem != nil && r.lru.Len() > r.maxTenants; {
+		prev := elem.Prev()
+		select {
+		case <-elem.Value.(*entry).ready:
+			r.remove(elem)
+		default:
+		}
+		elem = prev
+	}
+}
+
+// FindRoute matches req with the router of the tenant its KeyFunc returns.
+func (r *Registry) FindRoute(req *http.Request) (*routers.Route, map[string]string, error) {
+	_, route, pathParams, err := r.findRoute(req)
+	return route, pathParams, err
+}
+
+func (r *Registry) findRoute(req *http.Request) (*Tenant, *routers.Route, map[string]string, error) {
+	key, routed := r.key(req)
+	if key == "" {
+		return nil, nil, nil, ErrTenantNotFound
+	}
+	tenant, err := r.Get(req.Context(), key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	route, pathParams, err := tenant.Router.FindRoute(routed)
+	return tenant, route, pathParams, err
+}
+
+// ValidateRequest routes req with the router of its tenant, then validates it
+// with the options of that tenant (see openapi3filter.ValidateRequest).
+// The returned input is nil when req could not be routed.
+func (r *Registry) ValidateRequest(req *http.Request) (*openapi3filter.RequestValidationInput, error) {
+	tenant, route, pathParams, err := r.findRoute(req)
+	if err != nil {
+		return nil, err
+	}
+	input := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    tenant.Options,
+	}
+	return input, openapi3filter.ValidateRequest(req.Context(), input)
+}
@@ -0,0 +1,172 @@
+package tenants
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, maximum: %MAX%}}
+      responses:
+        '200': {description: Pets}
+`
+
+type loader struct {
+	mu    sync.Mutex
+	loads []string
+}
+
+func (l *loader) load(t *testing.T) LoadFunc {
+	return func(ctx context.Context, key string) (*Tenant, error) {
+		l.mu.Lock()
+		l.loads = append(l.loads, key)
+		l.mu.Unlock()
+		max := map[string]string{"acme": "10", "globex": "100", "initech": "1000"}[key]
+		if max == "" {
+			return nil, ErrTenantNotFound
+		}
+		doc, err := openapi3.NewLoader().LoadFromData([]byte(strings.Replace(spec, "%MAX%", max, 1)))
+		require.NoError(t, err)
+		return NewTenant(doc, nil)
+	}
+}
+
+func TestRegistryValidateRequest(t *testing.T) {
+	l := &loader{}
+	registry := New(BasePath(), l.load(t))
+
+	input, err := registry.ValidateRequest(httptest.NewRequest(http.MethodGet, "/acme/pets?limit=5", nil))
+	require.NoError(t, err)
+	require.Equal(t, "/pets", input.Route.Path)
+
+	_, err = registry.ValidateRequest(httptest.NewRequest(http.MethodGet, "/acme/pets?limit=50", nil))
+	require.Error(t, err)
+	_, err = registry.ValidateRequest(httptest.NewRequest(http.MethodGet, "/globex/pets?limit=50", nil))
+	require.NoError(t, err)
+
+	_, err = registry.ValidateRequest(httptest.NewRequest(http.MethodGet, "/umbrella/pets", nil))
+	require.Equal(t, ErrTenantNotFound, err)
+	require.Equal(t, http.StatusNotFound, openapi3filter.ClassifyError(err).Status)
+	_, err = registry.ValidateRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, ErrTenantNotFound, err)
+	_, err = registry.ValidateRequest(httptest.NewRequest(http.MethodGet, "/acme/cats", nil))
+	require.Equal(t, routers.ErrPathNotFound, err)
+
+	// Tenants are loaded once, and failed loads are retried
+	_, err = registry.ValidateRequest(httptest.NewRequest(http.MethodGet, "/umbrella/pets", nil))
+	require.Equal(t, ErrTenantNotFound, err)
+	require.Equal(t, []string{"acme", "globex", "umbrella", "umbrella"}, l.loads)
+	require.Equal(t, 2, registry.Len())
+
+	// The registry is a router too
+	validator := openapi3filter.NewValidator(registry)
+	w := httptest.NewRecorder()
+	validator.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/acme/pets?limit=50", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRegistryEviction(t *testing.T) {
+	l := &loader{}
+	registry := New(Host(), l.load(t), WithMaxTenants(2))
+	get := func(key string) {
+		_, err := registry.Get(context.Background(), key)
+		require.NoError(t, err)
+	}
+
+	get("acme")
+	get("globex")
+	get("acme")
+	get("initech") // evicts globex, the least recently used
+	require.Equal(t, 2, registry.Len())
+	get("acme")
+	get("globex")
+	require.Equal(t, []string{"acme", "globex", "initech", "globex"}, l.loads)
+
+	registry.Evict("globex")
+	get("globex")
+	require.Equal(t, "globex", l.loads[len(l.loads)-1])
+
+	tenant, err := registry.Get(context.Background(), "acme")
+	require.NoError(t, err)
+	registry.Store("umbrella", tenant)
+	loads := len(l.loads)
+	get("umbrella")
+	require.Len(t, l.loads, loads)
+
+	// Keyed by host
+	_, err = registry.ValidateRequest(httptest.NewRequest(http.MethodGet, "http://Umbrella:8080/pets", nil))
+	require.NoError(t, err)
+}
+
+func TestRegistryTTL(t *testing.T) {
+	l := &loader{}
+	registry := New(Host(), l.load(t), WithTTL(time.Millisecond))
+	_, err := registry.Get(context.Background(), "acme")
+	require.NoError(t, err)
+	time.Sleep(2 * time.Millisecond)
+	_, err = registry.Get(context.Background(), "acme")
+	require.NoError(t, err)
+	require.Equal(t, []string{"acme", "acme"}, l.loads)
+}
+
+func TestRegistryConcurrentLoads(t *testing.T) {
+	release := make(chan struct{})
+	loads := 0
+	registry := New(Host(), func(ctx context.Context, key string) (*Tenant, error) {
+		loads++
+		<-release
+		return nil, errors.New("unavailable")
+	})
+
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	started := make(chan struct{})
+	go func() {
+		defer close(started)
+		for registry.Len() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, errs[0] = registry.Get(context.Background(), "acme")
+	}()
+	<-started
+	for i := 1; i < len(errs); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = registry.Get(context.Background(), "acme")
+		}(i)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, 1, loads)
+	for _, err := range errs {
+		require.EqualError(t, err, "unavailable")
+	}
+	require.Equal(t, 0, registry.Len())
+}
@@ -0,0 +1,105 @@
+package openapi3filter
+
+import (
+	"context"
+
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// Tracer starts the spans emitted around routing and validation when set in Options.
+//
+// Its methods mirror those of OpenTelemetry's trace.Tracer and trace.Span,
+// so that an adapter is a few lines long:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, openapi3filter.Span) {
+//		ctx, span := t.Tracer.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) SetAttribute(key string, value interface{}) {
+//		s.Span.SetAttributes(attribute.String(key, fmt.Sprint(value)))
+//	}
+//
+//	func (s otelSpan) RecordError(err error) {
+//		s.Span.RecordError(err)
+//		s.Span.SetStatus(codes.Error, err.Error())
+//	}
+//
+//	func (s otelSpan) End() { s.Span.End() }
+type Tracer interface {
+	// Start starts a span as a child of the span of ctx, if any,
+	// and returns a context holding the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is an operation traced by a Tracer.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// Names of the spans started by Tracer
+const (
+	SpanFindRoute                    = "openapi3filter.FindRoute"
+	SpanValidateRequest              = "openapi3filter.ValidateRequest"
+	SpanValidateSecurityRequirements = "openapi3filter.ValidateSecurityRequirements"
+	SpanDecodeRequestBody            = "openapi3filter.DecodeRequestBody"
+	SpanValidateResponse             = "openapi3filter.ValidateResponse"
+)
+
+// Attributes set on spans
+const (
+	// AttributeOperationID is the operationId of the route.
+	AttributeOperationID = "openapi.operation_id"
+	// AttributeRoute is the method and path template of the route, e.g. "GET /pets/{id}".
+	AttributeRoute = "openapi.route"
+	// AttributeErrorLocation is the location of the (first) error, see LocationRoute and others.
+	AttributeErrorLocation = "openapi.error.location"
+	// AttributeErrorStatusCode is the HTTP status code ValidationErrorEncoder responds with for the (first) error.
+	AttributeErrorStatusCode = "openapi.error.status_code"
+)
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, interface{}) {}
+func (noopSpan) RecordError(error)                {}
+func (noopSpan) End()                             {}
+
+// startSpan starts a span with the attributes of route, which may be nil.
+func startSpan(ctx context.Context, tracer Tracer, name string, route *routers.Route) (context.Context, Span) {
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+	ctx, span := tracer.Start(ctx, name)
+	setRouteAttributes(span, route)
+	return ctx, span
+}
+
+func setRouteAttributes(span Span, route *routers.Route) {
+	if route == nil {
+		return
+	}
+	span.SetAttribute(AttributeRoute, route.Method+" "+route.Path)
+	if route.Operation != nil && route.Operation.OperationID != "" {
+		span.SetAttribute(AttributeOperationID, route.Operation.OperationID)
+	}
+}
+
+// endSpan records err, if any, and ends span.
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		if errs := flattenErrors(err, nil); len(errs) != 0 {
+			span.SetAttribute(AttributeErrorLocation, errorLocation(errs[0]))
+			if vErr := convertError(errs[0]); vErr != nil && vErr.Status != 0 {
+				span.SetAttribute(AttributeErrorStatusCode, vErr.Status)
+			}
+		}
+	}
+	span.End()
+}
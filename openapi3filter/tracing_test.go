@@ -0,0 +1,124 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+type spanKey struct{}
+
+// recordingTracer records the spans ended, as "parent > name {attributes}".
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []string
+}
+
+type recordingSpan struct {
+	tracer     *recordingTracer
+	name       string
+	parent     string
+	attributes map[string]interface{}
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, openapi3filter.Span) {
+	span := &recordingSpan{tracer: t, name: name, attributes: make(map[string]interface{})}
+	if parent, ok := ctx.Value(spanKey{}).(*recordingSpan); ok {
+		span.parent = parent.name
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (s *recordingSpan) SetAttribute(key string, value interface{}) { s.attributes[key] = value }
+
+func (s *recordingSpan) RecordError(err error) { s.attributes["error"] = true }
+
+func (s *recordingSpan) End() {
+	keys := make([]string, 0, len(s.attributes))
+	for key := range s.attributes {
+		keys = append(keys, fmt.Sprintf("%s=%v", key, s.attributes[key]))
+	}
+	sort.Strings(keys)
+	name := s.name
+	if s.parent != "" {
+		name = s.parent + " > " + name
+	}
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.tracer.spans = append(s.tracer.spans, name+" {"+strings.Join(keys, " ")+"}")
+}
+
+func TestTracer(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      operationId: createPet
+      security: [{apiKey: []}]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+      responses:
+        "201": {description: Created}
+components:
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-API-Key}
+`
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	tracer := &recordingTracer{}
+	validator := openapi3filter.NewValidator(router,
+		openapi3filter.ValidationOptions(openapi3filter.Options{
+			Tracer:             tracer,
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		}),
+		openapi3filter.OnLog(func(string, error) {}),
+	)
+	handler := validator.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	for _, body := range []string{`{"name": "Tom"}`, `{}`} {
+		r := httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/owners", nil))
+
+	require.Equal(t, []string{
+		"openapi3filter.FindRoute {openapi.operation_id=createPet openapi.route=POST /pets}",
+		"openapi3filter.ValidateRequest > openapi3filter.ValidateSecurityRequirements {openapi.operation_id=createPet openapi.route=POST /pets}",
+		"openapi3filter.ValidateRequest > openapi3filter.DecodeRequestBody {openapi.operation_id=createPet openapi.route=POST /pets}",
+		"openapi3filter.ValidateRequest {openapi.operation_id=createPet openapi.route=POST /pets}",
+		"openapi3filter.ValidateResponse {openapi.operation_id=createPet openapi.route=POST /pets}",
+
+		"openapi3filter.FindRoute {openapi.operation_id=createPet openapi.route=POST /pets}",
+		"openapi3filter.ValidateRequest > openapi3filter.ValidateSecurityRequirements {openapi.operation_id=createPet openapi.route=POST /pets}",
+		"openapi3filter.ValidateRequest > openapi3filter.DecodeRequestBody {openapi.operation_id=createPet openapi.route=POST /pets}",
+		"openapi3filter.ValidateRequest {error=true openapi.error.location=body openapi.error.status_code=422 openapi.operation_id=createPet openapi.route=POST /pets}",
+
+		"openapi3filter.FindRoute {error=true openapi.error.location=route openapi.error.status_code=404}",
+	}, tracer.spans)
+}
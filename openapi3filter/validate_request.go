@@ -6,9 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"sort"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
@@ -37,36 +37,23 @@ func ValidateRequest(ctx context.Context, input *RequestValidationInput) (err er
 		options = DefaultOptions
 	}
 	route := input.Route
+	ctx, span := startSpan(ctx, options.Tracer, SpanValidateRequest, route)
+	defer func() {
+		endSpan(span, err)
+		if err != nil && options.Logger != nil {
+			logFailures(ctx, options.Logger, "request validation failed", route, err)
+		}
+	}()
 	operation := route.Operation
-	operationParameters := operation.Parameters
-	pathItemParameters := route.PathItem.Parameters
+	compiled := compiledRouteOf(route)
 
-	// Security
-	security := operation.Security
-	// If there aren't any security requirements for the operation
-	if security == nil {
-		// Use the global security requirements.
-		security = &route.Spec.Security
-	}
-	if security != nil {
-		if err = ValidateSecurityRequirements(ctx, input, *security); err != nil && !options.MultiError {
-			return
-		}
-		if err != nil {
-			me = append(me, err)
-		}
+	if operation.Deprecated && options.DeprecationFunc != nil {
+		options.DeprecationFunc(ctx, &DeprecationWarning{Input: input})
 	}
 
-	// For each parameter of the PathItem
-	for _, parameterRef := range pathItemParameters {
-		parameter := parameterRef.Value
-		if operationParameters != nil {
-			if override := operationParameters.GetByInAndName(parameter.In, parameter.Name); override != nil {
-				continue
-			}
-		}
-
-		if err = ValidateParameter(ctx, input, parameter); err != nil && !options.MultiError {
+	// Security
+	if security := compiled.security; security != nil {
+		if err = ValidateSecurityRequirements(ctx, input, *security); err != nil && !options.MultiError {
 			return
 		}
 		if err != nil {
@@ -74,20 +61,21 @@ func ValidateRequest(ctx context.Context, input *RequestValidationInput) (err er
 		}
 	}
 
-	// For each parameter of the Operation
-	for _, parameter := range operationParameters {
-		if err = ValidateParameter(ctx, input, parameter.Value); err != nil && !options.MultiError {
-			return
-		}
-		if err != nil {
-			me = append(me, err)
-		}
+	checks := make([]requestCheck, 0, len(compiled.parameters)+1)
+	for _, parameter := range compiled.parameters {
+		checks = append(checks, parameterCheck(ctx, input, parameter))
 	}
 
 	// RequestBody
 	requestBody := operation.RequestBody
 	if requestBody != nil && !options.ExcludeRequestBody {
-		if err = ValidateRequestBody(ctx, input, requestBody.Value); err != nil && !options.MultiError {
+		checks = append(checks, requestCheck{check: func() error {
+			return ValidateRequestBody(ctx, input, requestBody.Value)
+		}})
+	}
+
+	for _, err = range runRequestChecks(input, checks, options.Concurrency, !options.MultiError) {
+		if err != nil && !options.MultiError {
 			return
 		}
 		if err != nil {
@@ -95,16 +83,32 @@ func ValidateRequest(ctx context.Context, input *RequestValidationInput) (err er
 		}
 	}
 
+	if options.warningFunc != nil {
+		warnUnknownQueryParameters(input, compiled.parameters)
+	}
+
 	if len(me) > 0 {
 		return me
 	}
 	return
 }
 
+// ValidateRequestSecurity validates the request of input against the security requirements
+// of its operation only, or those of the spec if the operation sets none, ignoring its
+// parameters and body. It suits authentication middlewares: the principal set by the
+// AuthenticationFunc and the requirement met are then available from input.
+func ValidateRequestSecurity(ctx context.Context, input *RequestValidationInput) error {
+	security := compiledRouteOf(input.Route).security
+	if security == nil {
+		return nil
+	}
+	return ValidateSecurityRequirements(ctx, input, *security)
+}
+
 // ValidateParameter validates a parameter's value by JSON schema.
 // The function returns RequestError with a ParseError cause when unable to parse a value.
 // The function returns RequestError with ErrInvalidRequired cause when a value of a required parameter is not defined.
-// The function returns RequestError with ErrInvalidEmptyValue cause when a value of a required parameter is not defined.
+// The function returns RequestError with ErrInvalidEmptyValue cause when a value is empty while allowEmptyValue is not set.
 // The function returns RequestError with a openapi3.SchemaError cause when a value is invalid by JSON schema.
 func ValidateParameter(ctx context.Context, input *RequestValidationInput, parameter *openapi3.Parameter) error {
 	if parameter.Schema == nil && parameter.Content == nil {
@@ -124,21 +128,37 @@ func ValidateParameter(ctx context.Context, input *RequestValidationInput, param
 	var found bool
 	var schema *openapi3.Schema
 
+	// An empty query parameter (e.g. "?bip=" or "?bip") is checked against allowEmptyValue
+	// before decoding, as decoders tell it apart from a missing value inconsistently.
+	if parameter.In == openapi3.ParameterInQuery && parameter.Content == nil && isEmptyQueryParameter(parameter, input) {
+		if !parameter.AllowEmptyValue {
+			return &RequestError{Input: input, Parameter: parameter, Reason: ErrInvalidEmptyValue.Error(), Err: ErrInvalidEmptyValue}
+		}
+		if parameter.Deprecated && options.DeprecationFunc != nil {
+			options.DeprecationFunc(ctx, &DeprecationWarning{Input: input, Parameter: parameter})
+		}
+		return nil
+	}
+
 	// Validation will ensure that we either have content or schema.
 	if parameter.Content != nil {
 		if value, schema, found, err = decodeContentParameter(parameter, input); err != nil {
-			return &RequestError{Input: input, Parameter: parameter, Err: err}
+			return &RequestError{Input: input, Parameter: parameter, Err: err, kind: ErrDecodingFailed}
 		}
 	} else {
 		if value, found, err = decodeStyledParameter(parameter, input); err != nil {
-			return &RequestError{Input: input, Parameter: parameter, Err: err}
+			return &RequestError{Input: input, Parameter: parameter, Err: err, kind: ErrDecodingFailed}
 		}
 		schema = parameter.Schema.Value
 	}
 
+	if found && parameter.Deprecated && options.DeprecationFunc != nil {
+		options.DeprecationFunc(ctx, &DeprecationWarning{Input: input, Parameter: parameter})
+	}
+
 	// Set default value if needed
 	if value == nil && schema != nil && schema.Default != nil {
-		value = schema.Default
+		value = decodedDefault(schema.Default, schema)
 		req := input.Request
 		switch parameter.In {
 		case openapi3.ParameterInPath:
@@ -171,6 +191,7 @@ func ValidateParameter(ctx context.Context, input *RequestValidationInput, param
 	}
 	if schema == nil {
 		// A parameter's schema is not defined so skip validation of a parameter's value.
+		input.setParameterValue(parameter.In, parameter.Name, value)
 		return nil
 	}
 
@@ -182,16 +203,51 @@ func ValidateParameter(ctx context.Context, input *RequestValidationInput, param
 	if options.customSchemaErrorFunc != nil {
 		opts = append(opts, openapi3.SetSchemaErrorMessageCustomizer(options.customSchemaErrorFunc))
 	}
+	if options.MaxEmbeddedContentSize > 0 {
+		opts = append(opts, openapi3.EnableContentValidation(options.MaxEmbeddedContentSize))
+	}
+	if options.StrictIntegerFormats {
+		opts = append(opts, openapi3.EnableStrictIntegerFormats())
+	}
+	if options.SchemaErrorVerbosity != openapi3.SchemaErrorVerbosityDefault {
+		opts = append(opts, openapi3.SetSchemaErrorVerbosity(options.SchemaErrorVerbosity))
+	}
 	if err = schema.VisitJSON(value, opts...); err != nil {
-		return &RequestError{Input: input, Parameter: parameter, Err: err}
+		return &RequestError{Input: input, Parameter: parameter, Err: err, kind: ErrSchemaMismatch}
 	}
+	input.setParameterValue(parameter.In, parameter.Name, value)
 	return nil
 }
 
+// isEmptyQueryParameter tells whether all values of a query parameter are empty.
+// Exploded objects and deep objects are made of several query parameters
+// named after their properties, so they are never empty.
+func isEmptyQueryParameter(parameter *openapi3.Parameter, input *RequestValidationInput) bool {
+	values, ok := input.GetQueryParams()[parameter.Name]
+	if !ok {
+		return false
+	}
+	if sm, err := parameter.SerializationMethod(); err == nil && parameter.Schema != nil && parameter.Schema.Value != nil &&
+		parameter.Schema.Value.Type == "object" && (sm.Explode || sm.Style == openapi3.SerializationDeepObject) {
+		return false
+	}
+	for _, value := range values {
+		if value != "" {
+			return false
+		}
+	}
+	return true
+}
+
 const prefixInvalidCT = "header Content-Type has unexpected value"
 
 // ValidateRequestBody validates data of a request's body.
 //
+// The body is read once: it is then replaced in input.Request by a reader of the same
+// bytes, or of the bytes with default values set, and kept for input.BodyBytes and
+// later validations of the same input. Setting Options.SkipRestoringRequestBody
+// leaves the body consumed instead.
+//
 // The function returns RequestError with ErrInvalidRequired cause when a value is required but not defined.
 // The function returns RequestError with a openapi3.SchemaError cause when a value is invalid by JSON schema.
 func ValidateRequestBody(ctx context.Context, input *RequestValidationInput, requestBody *openapi3.RequestBody) error {
@@ -205,30 +261,46 @@ func ValidateRequestBody(ctx context.Context, input *RequestValidationInput, req
 		options = DefaultOptions
 	}
 
-	if req.Body != http.NoBody && req.Body != nil {
+	restoreBody := !options.SkipRestoringRequestBody
+	if input.bodyRead {
+		data = input.body
+	} else if req.Body != http.NoBody && req.Body != nil {
 		defer req.Body.Close()
 		var err error
-		if data, err = ioutil.ReadAll(req.Body); err != nil {
+		if restoreBody {
+			data, err = readBody(req.Body, req.ContentLength)
+		} else {
+			// The body is not referenced once validated: its buffer can be reused
+			buf := getBodyBuffer()
+			defer putBodyBuffer(buf)
+			_, err = buf.ReadFrom(req.Body)
+			data = buf.Bytes()
+		}
+		if err != nil {
 			return &RequestError{
 				Input:       input,
 				RequestBody: requestBody,
 				Reason:      "reading failed",
 				Err:         err,
+				kind:        ErrBodyReadFailed,
 			}
 		}
-		// Put the data back into the input
-		req.Body = nil
-		if req.GetBody != nil {
-			if req.Body, err = req.GetBody(); err != nil {
-				req.Body = nil
+		if restoreBody {
+			// Put the data back into the input
+			req.Body = nil
+			if req.GetBody != nil {
+				if req.Body, err = req.GetBody(); err != nil {
+					req.Body = nil
+				}
 			}
-		}
-		if req.Body == nil {
-			req.ContentLength = int64(len(data))
-			req.GetBody = func() (io.ReadCloser, error) {
-				return io.NopCloser(bytes.NewReader(data)), nil
+			if req.Body == nil {
+				req.ContentLength = int64(len(data))
+				req.GetBody = func() (io.ReadCloser, error) {
+					return io.NopCloser(bytes.NewReader(data)), nil
+				}
+				req.Body, _ = req.GetBody() // no error return
 			}
-			req.Body, _ = req.GetBody() // no error return
+			input.body, input.bodyRead = data, true
 		}
 	}
 
@@ -241,10 +313,23 @@ func ValidateRequestBody(ctx context.Context, input *RequestValidationInput, req
 
 	content := requestBody.Content
 	if len(content) == 0 {
-		// A request's body does not have declared content, so skip validation.
+		// A request's body does not have declared content, so skip validation
+		// and leave the body encoded, whatever its encoding.
 		return nil
 	}
 
+	// The body is decompressed for validation only: the request keeps its encoded body
+	body, decompressed, err := decompressBody(data, req.Header, options.MaxDecompressedBodySize)
+	if err != nil {
+		return &RequestError{
+			Input:       input,
+			RequestBody: requestBody,
+			Reason:      "failed to decompress request body",
+			Err:         err,
+			kind:        ErrDecodingFailed,
+		}
+	}
+
 	inputMIME := req.Header.Get(headerCT)
 	contentType := requestBody.Content.Get(inputMIME)
 	if contentType == nil {
@@ -252,6 +337,7 @@ func ValidateRequestBody(ctx context.Context, input *RequestValidationInput, req
 			Input:       input,
 			RequestBody: requestBody,
 			Reason:      fmt.Sprintf("%s %q", prefixInvalidCT, inputMIME),
+			kind:        ErrContentTypeNotDeclared,
 		}
 	}
 
@@ -260,21 +346,55 @@ func ValidateRequestBody(ctx context.Context, input *RequestValidationInput, req
 		return nil
 	}
 
+	if parseMediaType(inputMIME) == "multipart/form-data" {
+		if err := validateFileParts(body, inputMIME, contentType, options); err != nil {
+			return &RequestError{
+				Input:       input,
+				RequestBody: requestBody,
+				Reason:      "invalid file upload",
+				Err:         err,
+			}
+		}
+	}
+
+	if limits := options.BodyLimits; limits != nil && limits.appliesTo(parseMediaType(inputMIME)) {
+		if err := limits.check(body); err != nil {
+			return &RequestError{
+				Input:       input,
+				RequestBody: requestBody,
+				Err:         err,
+				kind:        ErrDecodingFailed,
+			}
+		}
+	}
+
 	encFn := func(name string) *openapi3.Encoding { return contentType.Encoding[name] }
-	mediaType, value, err := decodeBody(bytes.NewReader(data), req.Header, contentType.Schema, encFn)
+	_, span := startSpan(ctx, options.Tracer, SpanDecodeRequestBody, input.Route)
+	mediaType, value, err := decodeBody(bytes.NewReader(body), req.Header, contentType.Schema, encFn)
+	endSpan(span, err)
 	if err != nil {
 		return &RequestError{
 			Input:       input,
 			RequestBody: requestBody,
 			Reason:      "failed to decode request body",
 			Err:         err,
+			kind:        ErrDecodingFailed,
+		}
+	}
+
+	if options.DeprecationFunc != nil {
+		for _, pointer := range deprecatedProperties(contentType.Schema.Value, value) {
+			options.DeprecationFunc(ctx, &DeprecationWarning{Input: input, Property: pointer})
 		}
 	}
+	if options.warningFunc != nil {
+		warnUnknownProperties(input, contentType.Schema.Value, value)
+	}
 
 	defaultsSet := false
-	opts := make([]openapi3.SchemaValidationOption, 0, 3) // 3 potential opts here
+	opts := make([]openapi3.SchemaValidationOption, 0, 7) // 7 potential opts here
 	opts = append(opts, openapi3.VisitAsRequest())
-	if !options.SkipSettingDefaults {
+	if !options.SkipSettingDefaults && restoreBody {
 		opts = append(opts, openapi3.DefaultsSet(func() { defaultsSet = true }))
 	}
 	if options.MultiError {
@@ -283,9 +403,18 @@ func ValidateRequestBody(ctx context.Context, input *RequestValidationInput, req
 	if options.customSchemaErrorFunc != nil {
 		opts = append(opts, openapi3.SetSchemaErrorMessageCustomizer(options.customSchemaErrorFunc))
 	}
+	if options.MaxEmbeddedContentSize > 0 {
+		opts = append(opts, openapi3.EnableContentValidation(options.MaxEmbeddedContentSize))
+	}
+	if options.StrictIntegerFormats {
+		opts = append(opts, openapi3.EnableStrictIntegerFormats())
+	}
+	if options.SchemaErrorVerbosity != openapi3.SchemaErrorVerbosityDefault {
+		opts = append(opts, openapi3.SetSchemaErrorVerbosity(options.SchemaErrorVerbosity))
+	}
 
 	// Validate JSON with the schema
-	if err := contentType.Schema.Value.VisitJSON(value, opts...); err != nil {
+	if err := contentType.Schema.VisitJSON(value, opts...); err != nil {
 		schemaId := getSchemaIdentifier(contentType.Schema)
 		schemaId = prependSpaceIfNeeded(schemaId)
 		return &RequestError{
@@ -293,8 +422,12 @@ func ValidateRequestBody(ctx context.Context, input *RequestValidationInput, req
 			RequestBody: requestBody,
 			Reason:      fmt.Sprintf("doesn't match schema%s", schemaId),
 			Err:         err,
+			kind:        ErrSchemaMismatch,
 		}
 	}
+	input.valuesMu.Lock()
+	input.bodyValue, input.bodyDecoded = value, true
+	input.valuesMu.Unlock()
 
 	if defaultsSet {
 		var err error
@@ -310,11 +443,15 @@ func ValidateRequestBody(ctx context.Context, input *RequestValidationInput, req
 		if req.Body != nil {
 			req.Body.Close()
 		}
+		if decompressed {
+			req.Header.Del(headerCE)
+		}
 		req.ContentLength = int64(len(data))
 		req.GetBody = func() (io.ReadCloser, error) {
 			return io.NopCloser(bytes.NewReader(data)), nil
 		}
 		req.Body, _ = req.GetBody() // no error return
+		input.body = data
 	}
 
 	return nil
@@ -323,19 +460,29 @@ func ValidateRequestBody(ctx context.Context, input *RequestValidationInput, req
 // ValidateSecurityRequirements goes through multiple OpenAPI 3 security
 // requirements in order and returns nil on the first valid requirement.
 // If no requirement is met, errors are returned in order.
-func ValidateSecurityRequirements(ctx context.Context, input *RequestValidationInput, srs openapi3.SecurityRequirements) error {
+func ValidateSecurityRequirements(ctx context.Context, input *RequestValidationInput, srs openapi3.SecurityRequirements) (err error) {
 	if len(srs) == 0 {
 		return nil
 	}
+	options := input.Options
+	if options == nil {
+		options = DefaultOptions
+	}
+	ctx, span := startSpan(ctx, options.Tracer, SpanValidateSecurityRequirements, input.Route)
+	defer func() { endSpan(span, err) }()
+
+	input.principals, input.securityRequirement = nil, nil
+	authentications := make(map[string]*authentication)
 	var errs []error
 	for _, sr := range srs {
-		if err := validateSecurityRequirement(ctx, input, sr); err != nil {
+		if err := validateSecurityRequirement(ctx, input, sr, authentications); err != nil {
 			if len(errs) == 0 {
 				errs = make([]error, 0, len(srs))
 			}
 			errs = append(errs, err)
 			continue
 		}
+		input.securityRequirement = sr
 		return nil
 	}
 	return &SecurityRequirementsError{
@@ -344,8 +491,16 @@ func ValidateSecurityRequirements(ctx context.Context, input *RequestValidationI
 	}
 }
 
-// validateSecurityRequirement validates a single OpenAPI 3 security requirement
-func validateSecurityRequirement(ctx context.Context, input *RequestValidationInput, securityRequirement openapi3.SecurityRequirement) error {
+// authentication is the outcome of an AuthenticationFunc call.
+type authentication struct {
+	principal interface{}
+	err       error
+}
+
+// validateSecurityRequirement validates a single OpenAPI 3 security requirement.
+// The AuthenticationFunc is called once per scheme and scopes, as authentications
+// caches its outcomes across the requirements of the request.
+func validateSecurityRequirement(ctx context.Context, input *RequestValidationInput, securityRequirement openapi3.SecurityRequirement, authentications map[string]*authentication) error {
 	doc := input.Route.Spec
 	securitySchemes := doc.Components.SecuritySchemes
 
@@ -367,6 +522,7 @@ func validateSecurityRequirement(ctx context.Context, input *RequestValidationIn
 	}
 
 	// For each scheme for the requirement
+	var principals map[string]interface{}
 	for _, name := range names {
 		var securityScheme *openapi3.SecurityScheme
 		if securitySchemes != nil {
@@ -381,14 +537,29 @@ func validateSecurityRequirement(ctx context.Context, input *RequestValidationIn
 			}
 		}
 		scopes := securityRequirement[name]
-		if err := f(ctx, &AuthenticationInput{
-			RequestValidationInput: input,
-			SecuritySchemeName:     name,
-			SecurityScheme:         securityScheme,
-			Scopes:                 scopes,
-		}); err != nil {
+		key := name + "\x00" + strings.Join(scopes, " ")
+		result := authentications[key]
+		if result == nil {
+			authenticationInput := &AuthenticationInput{
+				RequestValidationInput: input,
+				SecuritySchemeName:     name,
+				SecurityScheme:         securityScheme,
+				Scopes:                 scopes,
+			}
+			err := f(ctx, authenticationInput)
+			result = &authentication{principal: authenticationInput.principal, err: err}
+			authentications[key] = result
+		}
+		if err := result.err; err != nil {
 			return err
 		}
+		if principal := result.principal; principal != nil {
+			if principals == nil {
+				principals = make(map[string]interface{}, len(names))
+			}
+			principals[name] = principal
+		}
 	}
+	input.principals = principals
 	return nil
 }
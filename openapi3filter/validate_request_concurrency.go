@@ -0,0 +1,76 @@
+package openapi3filter
+
+import (
+	"context"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// requestCheck validates a part of a request.
+type requestCheck struct {
+	// in is the location of the validated parameter, or "" for the body.
+	in    string
+	check func() error
+}
+
+func parameterCheck(ctx context.Context, input *RequestValidationInput, parameter *openapi3.Parameter) requestCheck {
+	return requestCheck{
+		in:    parameter.In,
+		check: func() error { return ValidateParameter(ctx, input, parameter) },
+	}
+}
+
+// runRequestChecks runs checks and returns their errors, in the order of checks.
+//
+// When concurrency is more than 1, the checks of path parameters, of query parameters
+// and of the body run concurrently with each other, as they modify distinct parts of
+// the request when setting default values. Header and cookie parameters, which share
+// the headers of the request with the body, are checked first.
+// Otherwise checks run in order, stopping at the first error if stopOnError is set.
+func runRequestChecks(input *RequestValidationInput, checks []requestCheck, concurrency int, stopOnError bool) []error {
+	errs := make([]error, len(checks))
+	if concurrency <= 1 {
+		for i, c := range checks {
+			if errs[i] = c.check(); errs[i] != nil && stopOnError {
+				return errs[:i+1]
+			}
+		}
+		return errs
+	}
+
+	// Cache query parameters before they are read concurrently
+	input.GetQueryParams()
+
+	groups := make(map[string][]int)
+	for i, c := range checks {
+		switch c.in {
+		case openapi3.ParameterInHeader, openapi3.ParameterInCookie:
+			errs[i] = c.check()
+		default:
+			groups[c.in] = append(groups[c.in], i)
+		}
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, in := range []string{openapi3.ParameterInPath, openapi3.ParameterInQuery, ""} {
+		indexes := groups[in]
+		if len(indexes) == 0 {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			for _, i := range indexes {
+				errs[i] = checks[i].check()
+			}
+		}()
+	}
+	wg.Wait()
+	return errs
+}
@@ -1,8 +1,10 @@
 package openapi3filter
 
 import (
+	"context"
 	"net/http"
 	"net/url"
+	"sync"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/routers"
@@ -26,6 +28,142 @@ type RequestValidationInput struct {
 	Route        *routers.Route
 	Options      *Options
 	ParamDecoder ContentParameterDecoder
+
+	// body holds the request body once read by ValidateRequestBody
+	body     []byte
+	bodyRead bool
+
+	// principals are set by the AuthenticationFunc of the security requirement met, by scheme name
+	principals map[string]interface{}
+	// securityRequirement is the security requirement met
+	securityRequirement openapi3.SecurityRequirement
+
+	// valuesMu guards the values decoded by validation, as parameters may be validated concurrently
+	valuesMu sync.Mutex
+	// params are the values of the parameters decoded by ValidateParameter, by location then name
+	params map[string]map[string]interface{}
+	// bodyValue is the value of the body decoded by ValidateRequestBody, if bodyDecoded
+	bodyValue   interface{}
+	bodyDecoded bool
+}
+
+// BodyBytes returns the request body as read by ValidateRequestBody, with the default
+// values it set if any, and whether it was read. The returned bytes must not be modified.
+//
+// The body is not kept when Options.SkipRestoringRequestBody is set.
+func (input *RequestValidationInput) BodyBytes() ([]byte, bool) {
+	return input.body, input.bodyRead
+}
+
+// ParameterValue returns the value of the parameter of the given location and name
+// as decoded and validated by ValidateParameter, with its default value if the request
+// does not set it, and whether it has a value.
+func (input *RequestValidationInput) ParameterValue(in, name string) (interface{}, bool) {
+	input.valuesMu.Lock()
+	defer input.valuesMu.Unlock()
+	value, ok := input.params[in][name]
+	return value, ok
+}
+
+// ParameterValues returns the values of the parameters of the given location
+// as decoded and validated by ValidateParameter, by name (see ParameterValue).
+func (input *RequestValidationInput) ParameterValues(in string) map[string]interface{} {
+	input.valuesMu.Lock()
+	defer input.valuesMu.Unlock()
+	values := make(map[string]interface{}, len(input.params[in]))
+	for name, value := range input.params[in] {
+		values[name] = value
+	}
+	return values
+}
+
+func (input *RequestValidationInput) setParameterValue(in, name string, value interface{}) {
+	input.valuesMu.Lock()
+	defer input.valuesMu.Unlock()
+	if input.params == nil {
+		input.params = make(map[string]map[string]interface{})
+	}
+	if input.params[in] == nil {
+		input.params[in] = make(map[string]interface{})
+	}
+	input.params[in][name] = value
+}
+
+// BodyValue returns the request body as decoded and validated by ValidateRequestBody,
+// with the default values it set if any, and whether it was decoded.
+func (input *RequestValidationInput) BodyValue() (interface{}, bool) {
+	input.valuesMu.Lock()
+	defer input.valuesMu.Unlock()
+	return input.bodyValue, input.bodyDecoded
+}
+
+type requestBodyKey struct{}
+
+// RequestBodyFromContext returns the request body read when validating the request,
+// as set by Validator.Middleware in the context of the requests it passes on.
+// The returned bytes must not be modified.
+func RequestBodyFromContext(ctx context.Context) ([]byte, bool) {
+	body, ok := ctx.Value(requestBodyKey{}).([]byte)
+	return body, ok
+}
+
+type routeKey struct{}
+
+// routeContext is the route of a request, its path parameters and its validation input.
+type routeContext struct {
+	route      *routers.Route
+	pathParams map[string]string
+	input      *RequestValidationInput
+}
+
+// RouteFromContext returns the route the request matched and its path parameters,
+// as set by Validator.Middleware in the context of the requests it passes on.
+func RouteFromContext(ctx context.Context) (*routers.Route, map[string]string, bool) {
+	rc, ok := ctx.Value(routeKey{}).(*routeContext)
+	if !ok {
+		return nil, nil, false
+	}
+	return rc.route, rc.pathParams, true
+}
+
+// Principal returns the principal set with AuthenticationInput.SetPrincipal for the
+// security requirement the request met, the one of the first scheme by name if several were set,
+// or nil.
+func (input *RequestValidationInput) Principal() interface{} {
+	var first string
+	for name := range input.principals {
+		if first == "" || name < first {
+			first = name
+		}
+	}
+	return input.principals[first]
+}
+
+// Principals returns the principals set with AuthenticationInput.SetPrincipal for the
+// security requirement the request met, by security scheme name.
+func (input *RequestValidationInput) Principals() map[string]interface{} {
+	return input.principals
+}
+
+// SecurityRequirement returns the security requirement of the operation the request met,
+// or nil if it has none or met none. An empty requirement makes security optional.
+func (input *RequestValidationInput) SecurityRequirement() openapi3.SecurityRequirement {
+	return input.securityRequirement
+}
+
+type principalKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx holding principal (see PrincipalFromContext).
+func ContextWithPrincipal(ctx context.Context, principal interface{}) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal the request was authenticated as,
+// as set by Validator.Middleware and ValidationHandler in the context of the requests
+// they pass on (see AuthenticationInput.SetPrincipal).
+func PrincipalFromContext(ctx context.Context) (interface{}, bool) {
+	principal := ctx.Value(principalKey{})
+	return principal, principal != nil
 }
 
 func (input *RequestValidationInput) GetQueryParams() url.Values {
@@ -3,6 +3,7 @@ package openapi3filter
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -221,3 +222,493 @@ components:
 		})
 	}
 }
+
+func TestValidateQueryParameterAllowEmptyValue(t *testing.T) {
+	objectSchema := &openapi3.Schema{
+		Type:       "object",
+		Properties: openapi3.NewSchemas().With("a", openapi3.NewStringSchema().NewRef()),
+	}
+	schemas := map[string]*openapi3.Schema{
+		"integer": openapi3.NewIntegerSchema(),
+		"string":  openapi3.NewStringSchema().WithMinLength(1),
+		"array":   openapi3.NewArraySchema().WithItems(openapi3.NewIntegerSchema()),
+		"object":  objectSchema,
+	}
+	explode := false
+	for name, schema := range schemas {
+		for _, query := range []string{"bip=", "bip", "bip=&bip="} {
+			for _, allowEmptyValue := range []bool{false, true} {
+				t.Run(fmt.Sprintf("%s %s allowEmptyValue=%v", name, query, allowEmptyValue), func(t *testing.T) {
+					parameter := &openapi3.Parameter{
+						Name:            "bip",
+						In:              openapi3.ParameterInQuery,
+						Required:        true,
+						AllowEmptyValue: allowEmptyValue,
+						Schema:          schema.NewRef(),
+					}
+					if name == "object" {
+						parameter.Explode = &explode
+					}
+					req, err := http.NewRequest(http.MethodGet, "http://example.com/?"+query, nil)
+					require.NoError(t, err)
+
+					err = ValidateParameter(context.Background(), &RequestValidationInput{Request: req}, parameter)
+					if allowEmptyValue {
+						require.NoError(t, err)
+						return
+					}
+					require.Error(t, err)
+					require.True(t, errors.Is(err, ErrInvalidEmptyValue))
+					require.EqualError(t, err, `parameter "bip" in query has an error: empty value is not allowed`)
+				})
+			}
+		}
+	}
+}
+
+func TestValidateQueryParameterArrayConstraints(t *testing.T) {
+	explode, noExplode := true, false
+	schema := openapi3.NewArraySchema().
+		WithItems(openapi3.NewIntegerSchema()).
+		WithMinItems(2).
+		WithMaxItems(3).
+		WithUniqueItems(true)
+	testCases := []struct {
+		style   string
+		explode *bool
+		query   string
+		wantErr string
+	}{
+		{style: "form", explode: &explode, query: "id=1&id=2"},
+		{style: "form", explode: &explode, query: "id=1", wantErr: "minimum number of items is 2"},
+		{style: "form", explode: &explode, query: "id=1&id=2&id=3&id=4", wantErr: "maximum number of items is 3"},
+		{style: "form", explode: &explode, query: "id=1&id=2&id=2", wantErr: "duplicate items found"},
+		{style: "form", explode: &explode, query: "id=1&id=&id=2", wantErr: `Error at "/1": Value is not nullable`},
+		{style: "form", explode: &noExplode, query: "id=1,2"},
+		{style: "form", explode: &noExplode, query: "id=1,2,2", wantErr: "duplicate items found"},
+		{style: "form", explode: &noExplode, query: "id=1,2&id=3,4", wantErr: "maximum number of items is 3"},
+		{style: "form", explode: &noExplode, query: "id=1,,2", wantErr: `Error at "/1": Value is not nullable`},
+		{style: "spaceDelimited", explode: &noExplode, query: "id=1%202%203%204", wantErr: "maximum number of items is 3"},
+		{style: "pipeDelimited", explode: &noExplode, query: "id=1|1", wantErr: "duplicate items found"},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s explode=%v %s", tc.style, *tc.explode, tc.query), func(t *testing.T) {
+			parameter := &openapi3.Parameter{
+				Name:    "id",
+				In:      openapi3.ParameterInQuery,
+				Style:   tc.style,
+				Explode: tc.explode,
+				Schema:  schema.NewRef(),
+			}
+			req, err := http.NewRequest(http.MethodGet, "http://example.com/?"+tc.query, nil)
+			require.NoError(t, err)
+
+			err = ValidateParameter(context.Background(), &RequestValidationInput{Request: req}, parameter)
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
+func TestValidateRequestBodyRestoration(t *testing.T) {
+	schema := openapi3.NewObjectSchema().
+		WithProperty("name", openapi3.NewStringSchema()).
+		WithProperty("kind", openapi3.NewStringSchema().WithDefault("cat"))
+	schema.Required = []string{"name"}
+	requestBody := openapi3.NewRequestBody().WithJSONSchema(schema)
+	validate := func(body string, contentLength int64, options *Options) (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, "http://example.com/pets", io.NopCloser(bytes.NewReader([]byte(body))))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.ContentLength = contentLength
+		input := &RequestValidationInput{Request: req, Options: options}
+		return req, ValidateRequestBody(context.Background(), input, requestBody)
+	}
+	readAll := func(req *http.Request) string {
+		data, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	for _, contentLength := range []int64{-1, 5, 14, 100} {
+		req, err := validate(`{"name":"Rex"}`, contentLength, nil)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name":"Rex","kind":"cat"}`, readAll(req))
+	}
+
+	options := &Options{SkipRestoringRequestBody: true}
+	for i := 0; i < 3; i++ {
+		req, err := validate(`{"name":"Rex"}`, -1, options)
+		require.NoError(t, err)
+		require.Empty(t, readAll(req))
+		_, err = validate(`{"kind":"dog"}`, -1, options)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `property "name" is missing`)
+	}
+}
+
+type countingReader struct {
+	r     io.Reader
+	reads int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.reads++
+	return r.r.Read(p)
+}
+
+func TestValidateRequestBodyReadOnce(t *testing.T) {
+	schema := openapi3.NewObjectSchema().
+		WithProperty("name", openapi3.NewStringSchema()).
+		WithProperty("kind", openapi3.NewStringSchema().WithDefault("cat"))
+	requestBody := openapi3.NewRequestBody().WithJSONSchema(schema)
+	body := &countingReader{r: bytes.NewReader([]byte(`{"name":"Rex"}`))}
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/pets", body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	input := &RequestValidationInput{Request: req}
+
+	_, ok := input.BodyBytes()
+	require.False(t, ok)
+	require.NoError(t, ValidateRequestBody(context.Background(), input, requestBody))
+	reads := body.reads
+	require.NoError(t, ValidateRequestBody(context.Background(), input, requestBody))
+	require.Equal(t, reads, body.reads)
+
+	data, ok := input.BodyBytes()
+	require.True(t, ok)
+	require.JSONEq(t, `{"name":"Rex","kind":"cat"}`, string(data))
+	restored, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	require.Equal(t, data, restored)
+}
+
+func TestValidateRequestConcurrency(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets/{id}:
+    parameters:
+    - {name: id, in: path, required: true, schema: {type: integer}}
+    - {name: X-Trace, in: header, schema: {type: string, default: none}}
+    put:
+      parameters:
+      - {name: limit, in: query, schema: {type: integer, maximum: 10}}
+      - {name: sort, in: query, schema: {type: string, default: name}}
+      - {name: X-Version, in: header, required: true, schema: {type: integer}}
+      - {name: session, in: cookie, schema: {type: string, default: anonymous}}
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+                kind: {type: string, default: cat}
+      responses:
+        '200': {description: OK}
+`
+	router := setupTestRouter(t, spec)
+	validate := func(url, version, body string, options *Options) (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if version != "" {
+			req.Header.Set("X-Version", version)
+		}
+		route, pathParams, err := router.FindRoute(req)
+		require.NoError(t, err)
+		return req, ValidateRequest(context.Background(), &RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		})
+	}
+
+	for _, multiError := range []bool{false, true} {
+		sequential := &Options{MultiError: multiError}
+		concurrent := &Options{MultiError: multiError, Concurrency: 4}
+
+		_, want := validate("http://example.com/pets/x?limit=20", "", `{}`, sequential)
+		require.Error(t, want)
+		for i := 0; i < 20; i++ {
+			_, err := validate("http://example.com/pets/x?limit=20", "", `{}`, concurrent)
+			require.Equal(t, want.Error(), err.Error())
+		}
+	}
+
+	req, err := validate("http://example.com/pets/1?limit=5", "2", `{"name":"Rex"}`, &Options{Concurrency: 2})
+	require.NoError(t, err)
+	require.Equal(t, "none", req.Header.Get("X-Trace"))
+	require.Equal(t, "name", req.URL.Query().Get("sort"))
+	cookie, err := req.Cookie("session")
+	require.NoError(t, err)
+	require.Equal(t, "anonymous", cookie.Value)
+	data, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"Rex","kind":"cat"}`, string(data))
+}
+
+func TestValidateRequestCompiledRoute(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+security: [{apiKey: []}]
+components:
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-Key}
+paths:
+  /pets/{id}:
+    parameters:
+    - {name: id, in: path, required: true, schema: {type: string, pattern: '^[a-z]+$'}}
+    - {name: limit, in: query, schema: {type: integer}}
+    get:
+      security: []
+      parameters:
+      - {name: limit, in: query, schema: {type: integer, maximum: 10}}
+      responses:
+        '200': {description: OK}
+`
+	router := setupTestRouter(t, spec)
+	req, err := http.NewRequest(http.MethodGet, "http://example.com/pets/rex?limit=20", nil)
+	require.NoError(t, err)
+	route, pathParams, err := router.FindRoute(req)
+	require.NoError(t, err)
+
+	compiled := route.Cached(compiledRouteKey{}, func() interface{} { return nil })
+	require.NotNil(t, compiled, "routes are compiled when the router is constructed")
+	require.Same(t, compiled, compiledRouteOf(route))
+	require.Equal(t, &openapi3.SecurityRequirements{}, compiledRouteOf(route).security)
+	parameters := compiledRouteOf(route).parameters
+	require.Len(t, parameters, 2)
+	require.Equal(t, "id", parameters[0].Name)
+	require.Same(t, route.Operation.Parameters[0].Value, parameters[1])
+
+	err = ValidateRequest(context.Background(), &RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `parameter "limit" in query has an error: number must be at most 10`)
+
+	// Routes not built by a router are resolved on each validation
+	handmade := &routers.Route{Spec: route.Spec, PathItem: route.PathItem, Operation: route.Operation}
+	require.NotSame(t, compiledRouteOf(handmade), compiledRouteOf(handmade))
+}
+
+func TestValidateRequestBodyEmbeddedContent(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info: {title: Payments, version: 1.0.0}
+paths:
+  /payments:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                payload:
+                  type: string
+                  format: byte
+                  contentSchema:
+                    type: object
+                    required: [amount]
+                    properties:
+                      amount: {type: integer, minimum: 1}
+      responses:
+        '200': {description: OK}
+`
+	router := setupTestRouter(t, spec)
+	validate := func(payload string, options *Options) error {
+		body := `{"payload":"` + base64.StdEncoding.EncodeToString([]byte(payload)) + `"}`
+		req, err := http.NewRequest(http.MethodPost, "http://example.com/payments", bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		route, pathParams, err := router.FindRoute(req)
+		require.NoError(t, err)
+		return ValidateRequest(context.Background(), &RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		})
+	}
+
+	require.NoError(t, validate(`{"amount": 0}`, nil))
+	options := &Options{MaxEmbeddedContentSize: 32}
+	require.NoError(t, validate(`{"amount": 10}`, options))
+	err := validate(`{"amount": 0}`, options)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Error at "/payload": Error at "/amount": number must be at least 1`)
+	err = validate(`{"amount": 10, "padding": "0123456789abcdef"}`, options)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Error at "/payload": decoded content exceeds 32 bytes`)
+}
+
+func TestValidateRequestDeprecations(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      deprecated: true
+      parameters:
+      - {name: legacy, in: query, deprecated: true, schema: {type: boolean}}
+      - {name: X-Old, in: header, deprecated: true, schema: {type: string}}
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name: {type: string}
+                tag: {type: string, deprecated: true}
+                owners:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      id: {type: integer}
+                      a/b: {type: integer, deprecated: true}
+      responses:
+        '200': {description: OK}
+`
+	router := setupTestRouter(t, spec)
+	validate := func(url, body string) []string {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		route, pathParams, err := router.FindRoute(req)
+		require.NoError(t, err)
+		var warnings []string
+		err = ValidateRequest(context.Background(), &RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &Options{DeprecationFunc: func(ctx context.Context, warning *DeprecationWarning) {
+				warnings = append(warnings, warning.String())
+			}},
+		})
+		require.NoError(t, err)
+		return warnings
+	}
+
+	require.Equal(t, []string{"operation of POST /pets is deprecated"}, validate("http://example.com/pets", `{"name": "Rex"}`))
+	require.Equal(t, []string{
+		"operation of POST /pets is deprecated",
+		`parameter "legacy" in query of POST /pets is deprecated`,
+		`request body property "/owners/1/a~1b" of POST /pets is deprecated`,
+		`request body property "/tag" of POST /pets is deprecated`,
+	}, validate("http://example.com/pets?legacy=true", `{"name": "Rex", "tag": "dog", "owners": [{"id": 1}, {"id": 2, "a/b": 3}]}`))
+}
+
+func TestValidateSecurityRequirementsCachesAuthentications(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+components:
+  securitySchemes:
+    bearer: {type: http, scheme: bearer}
+    apiKey: {type: apiKey, in: header, name: X-API-Key}
+paths:
+  /pets:
+    get:
+      security:
+      - {apiKey: [], bearer: []}
+      - bearer: []
+      - bearer: [admin]
+      - apiKey: []
+      responses:
+        '200': {description: OK}
+`
+	router := setupTestRouter(t, spec)
+	req, err := http.NewRequest(http.MethodGet, "http://example.com/pets", nil)
+	require.NoError(t, err)
+	route, pathParams, err := router.FindRoute(req)
+	require.NoError(t, err)
+
+	calls := make(map[string]int)
+	input := &RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &Options{AuthenticationFunc: func(ctx context.Context, input *AuthenticationInput) error {
+			calls[fmt.Sprintf("%s%v", input.SecuritySchemeName, input.Scopes)]++
+			if input.SecuritySchemeName == "bearer" {
+				return errors.New("missing token")
+			}
+			return nil
+		}},
+	}
+	require.NoError(t, ValidateRequest(context.Background(), input))
+	require.Equal(t, map[string]int{"apiKey[]": 1, "bearer[]": 1, "bearer[admin]": 1}, calls)
+	require.Equal(t, openapi3.SecurityRequirement{"apiKey": []string{}}, input.SecurityRequirement())
+}
+
+func TestValidateRequestSecurity(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+components:
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-API-Key}
+security:
+- apiKey: []
+paths:
+  /pets:
+    post:
+      parameters:
+      - {name: limit, in: query, required: true, schema: {type: integer}}
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {type: object}
+      responses:
+        '200': {description: OK}
+  /health:
+    get:
+      security: []
+      responses:
+        '200': {description: OK}
+`
+	router := setupTestRouter(t, spec)
+	validate := func(method, url, key string) error {
+		req, err := http.NewRequest(method, url, nil)
+		require.NoError(t, err)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		route, pathParams, err := router.FindRoute(req)
+		require.NoError(t, err)
+		return ValidateRequestSecurity(context.Background(), &RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &Options{AuthenticationFunc: func(ctx context.Context, input *AuthenticationInput) error {
+				if input.RequestValidationInput.Request.Header.Get("X-API-Key") != "secret" {
+					return errors.New("invalid API key")
+				}
+				return nil
+			}},
+		})
+	}
+
+	// Neither the missing parameter nor the missing body are reported
+	require.NoError(t, validate(http.MethodPost, "http://example.com/pets", "secret"))
+	err := validate(http.MethodPost, "http://example.com/pets", "guess")
+	require.IsType(t, &SecurityRequirementsError{}, err)
+	require.NoError(t, validate(http.MethodGet, "http://example.com/health", ""))
+}
@@ -17,9 +17,29 @@ import (
 // loaded OpenAPIv3 spec. If the input does not match the OpenAPIv3 spec, a
 // non-nil error will be returned.
 //
+// The response is validated against the response of its exact status code, else
+// of its status code range (e.g. "2XX"), else the default response. The key of that
+// response is set in input.MatchedResponse.
+//
 // Note: One can tune the behavior of uniqueItems: true verification
 // by registering a custom function with openapi3.RegisterArrayUniqueItemsChecker
-func ValidateResponse(ctx context.Context, input *ResponseValidationInput) error {
+func ValidateResponse(ctx context.Context, input *ResponseValidationInput) (err error) {
+	options := input.Options
+	if options == nil {
+		options = DefaultOptions
+	}
+	route := input.RequestValidationInput.Route
+	ctx, span := startSpan(ctx, options.Tracer, SpanValidateResponse, route)
+	defer func() {
+		endSpan(span, err)
+		if err != nil && options.Logger != nil {
+			logFailures(ctx, options.Logger, "response validation failed", route, err)
+		}
+	}()
+	return validateResponse(ctx, input)
+}
+
+func validateResponse(ctx context.Context, input *ResponseValidationInput) error {
 	req := input.RequestValidationInput.Request
 	switch req.Method {
 	case "HEAD":
@@ -42,34 +62,45 @@ func ValidateResponse(ctx context.Context, input *ResponseValidationInput) error
 		options = DefaultOptions
 	}
 
+	if err := validateResponseHeaderChecks(input, options.ResponseHeaderChecks); err != nil {
+		return err
+	}
+
 	// Find input for the current status
 	responses := route.Operation.Responses
-	if len(responses) == 0 {
+	if responses.Len() == 0 {
 		return nil
 	}
-	responseRef := responses.Get(status) // Response
-	if responseRef == nil {
-		responseRef = responses.Default() // Default input
-	}
+	var responseRef *openapi3.ResponseRef
+	input.MatchedResponse, responseRef = responses.Match(status)
 	if responseRef == nil {
 		// By default, status that is not documented is allowed.
 		if !options.IncludeResponseStatus {
 			return nil
 		}
-		return &ResponseError{Input: input, Reason: "status is not supported"}
+		return &ResponseError{Input: input, Reason: "status is not supported", kind: ErrStatusNotDeclared}
 	}
 	response := responseRef.Value
 	if response == nil {
 		return &ResponseError{Input: input, Reason: "response has not been resolved"}
 	}
 
-	opts := make([]openapi3.SchemaValidationOption, 0, 2)
+	opts := make([]openapi3.SchemaValidationOption, 0, 5)
 	if options.MultiError {
 		opts = append(opts, openapi3.MultiErrors())
 	}
 	if options.customSchemaErrorFunc != nil {
 		opts = append(opts, openapi3.SetSchemaErrorMessageCustomizer(options.customSchemaErrorFunc))
 	}
+	if options.MaxEmbeddedContentSize > 0 {
+		opts = append(opts, openapi3.EnableContentValidation(options.MaxEmbeddedContentSize))
+	}
+	if options.StrictIntegerFormats {
+		opts = append(opts, openapi3.EnableStrictIntegerFormats())
+	}
+	if options.SchemaErrorVerbosity != openapi3.SchemaErrorVerbosityDefault {
+		opts = append(opts, openapi3.SetSchemaErrorVerbosity(options.SchemaErrorVerbosity))
+	}
 
 	headers := make([]string, 0, len(response.Headers))
 	for k := range response.Headers {
@@ -102,6 +133,7 @@ func ValidateResponse(ctx context.Context, input *ResponseValidationInput) error
 		return &ResponseError{
 			Input:  input,
 			Reason: fmt.Sprintf("response header Content-Type has unexpected value: %q", inputMIME),
+			kind:   ErrContentTypeNotDeclared,
 		}
 	}
 
@@ -128,6 +160,7 @@ func ValidateResponse(ctx context.Context, input *ResponseValidationInput) error
 			Input:  input,
 			Reason: "failed to read response body",
 			Err:    err,
+			kind:   ErrBodyReadFailed,
 		}
 	}
 
@@ -141,17 +174,19 @@ func ValidateResponse(ctx context.Context, input *ResponseValidationInput) error
 			Input:  input,
 			Reason: "failed to decode response body",
 			Err:    err,
+			kind:   ErrDecodingFailed,
 		}
 	}
 
 	// Validate data with the schema.
-	if err := contentType.Schema.Value.VisitJSON(value, append(opts, openapi3.VisitAsResponse())...); err != nil {
+	if err := contentType.Schema.VisitJSON(value, append(opts, openapi3.VisitAsResponse())...); err != nil {
 		schemaId := getSchemaIdentifier(contentType.Schema)
 		schemaId = prependSpaceIfNeeded(schemaId)
 		return &ResponseError{
 			Input:  input,
 			Reason: fmt.Sprintf("response body doesn't match schema%s", schemaId),
 			Err:    err,
+			kind:   ErrSchemaMismatch,
 		}
 	}
 	return nil
@@ -177,6 +212,7 @@ func validateResponseHeader(headerName string, headerRef *openapi3.HeaderRef, in
 			Input:  input,
 			Reason: fmt.Sprintf("unable to decode header %q value", headerName),
 			Err:    err,
+			kind:   ErrDecodingFailed,
 		}
 	}
 
@@ -186,12 +222,14 @@ func validateResponseHeader(headerName string, headerRef *openapi3.HeaderRef, in
 				Input:  input,
 				Reason: fmt.Sprintf("response header %q doesn't match schema", headerName),
 				Err:    err,
+				kind:   ErrSchemaMismatch,
 			}
 		}
 	} else if headerRef.Value.Required {
 		return &ResponseError{
 			Input:  input,
 			Reason: fmt.Sprintf("response header %q missing", headerName),
+			kind:   ErrInvalidRequired,
 		}
 	}
 	return nil
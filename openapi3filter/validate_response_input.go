@@ -13,6 +13,36 @@ type ResponseValidationInput struct {
 	Header                 http.Header
 	Body                   io.ReadCloser
 	Options                *Options
+
+	// MatchedResponse is set by ValidateResponse to the key of the response
+	// the input was validated against (e.g. "200", "2XX" or "default").
+	// It is empty when no response describes the status.
+	MatchedResponse string
+}
+
+// NewResponseValidationInput returns the input validating resp, the response to the
+// request described by requestInput, with the options of requestInput.
+//
+// The body of resp is read and replaced by a reader of the same bytes,
+// so that it can still be read once validated.
+func NewResponseValidationInput(requestInput *RequestValidationInput, resp *http.Response) (*ResponseValidationInput, error) {
+	var body []byte
+	if resp.Body != nil && resp.Body != http.NoBody {
+		var err error
+		body, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	input := &ResponseValidationInput{
+		RequestValidationInput: requestInput,
+		Status:                 resp.StatusCode,
+		Header:                 resp.Header,
+		Options:                requestInput.Options,
+	}
+	return input.SetBodyBytes(body), nil
 }
 
 func (input *ResponseValidationInput) SetBodyBytes(value []byte) *ResponseValidationInput {
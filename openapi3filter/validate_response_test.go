@@ -1,6 +1,8 @@
 package openapi3filter
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -9,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
 )
 
 func Test_validateResponseHeader(t *testing.T) {
@@ -213,3 +216,115 @@ func newArraySchema(schema *openapi3.Schema) *openapi3.Schema {
 
 	return arraySchema
 }
+
+func TestValidateResponseStatusRanges(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema: {type: array}
+        2XX:
+          description: Other successes
+          content:
+            application/json:
+              schema: {type: object}
+        4xx:
+          description: Client errors
+          content:
+            application/json:
+              schema: {type: string}
+        default:
+          description: Other errors
+          content:
+            application/json:
+              schema: {type: integer}
+`))
+	require.NoError(t, err)
+	route := &routers.Route{Spec: doc, Path: "/pets", Method: http.MethodGet, Operation: doc.Paths.Value("/pets").Get}
+
+	for _, tc := range []struct {
+		status  int
+		body    string
+		matched string
+		wantErr bool
+	}{
+		{status: 200, body: `[]`, matched: "200"},
+		{status: 200, body: `{}`, matched: "200", wantErr: true},
+		{status: 201, body: `{}`, matched: "2XX"},
+		{status: 201, body: `[]`, matched: "2XX", wantErr: true},
+		{status: 404, body: `"not found"`, matched: "4xx"},
+		{status: 500, body: `42`, matched: "default"},
+		{status: 500, body: `"oops"`, matched: "default", wantErr: true},
+	} {
+		t.Run(fmt.Sprintf("%d %s", tc.status, tc.body), func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "http://example.com/pets", nil)
+			require.NoError(t, err)
+			input := &ResponseValidationInput{
+				RequestValidationInput: &RequestValidationInput{Request: req, Route: route},
+				Status:                 tc.status,
+				Header:                 http.Header{"Content-Type": []string{"application/json"}},
+			}
+			input.SetBodyBytes([]byte(tc.body))
+
+			err = ValidateResponse(context.Background(), input)
+			require.Equal(t, tc.matched, input.MatchedResponse)
+			if tc.wantErr {
+				require.Error(t, err)
+				require.Equal(t, tc.matched, err.(*ResponseError).Input.MatchedResponse)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewResponseValidationInput(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema: {type: array, items: {type: string}}
+`))
+	require.NoError(t, err)
+	route := &routers.Route{Spec: doc, Path: "/pets", Method: http.MethodGet, Operation: doc.Paths.Value("/pets").Get}
+	req, err := http.NewRequest(http.MethodGet, "http://example.com/pets", nil)
+	require.NoError(t, err)
+	requestInput := &RequestValidationInput{Request: req, Route: route, Options: &Options{MultiError: true}}
+
+	newResponse := func(body string) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+	}
+
+	resp := newResponse(`["Rex"]`)
+	input, err := NewResponseValidationInput(requestInput, resp)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, input.Status)
+	require.Same(t, requestInput.Options, input.Options)
+	require.NoError(t, ValidateResponse(context.Background(), input))
+	// The body is left for the caller to read
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, `["Rex"]`, string(body))
+
+	input, err = NewResponseValidationInput(requestInput, newResponse(`[1]`))
+	require.NoError(t, err)
+	require.Error(t, ValidateResponse(context.Background(), input))
+}
@@ -2,6 +2,7 @@ package openapi3filter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -11,22 +12,32 @@ import (
 )
 
 // ValidationErrorEncoder wraps a base ErrorEncoder to handle ValidationErrors
+//
+// Deprecated: Use an ErrorPipeline, whose classify and structure stages
+// convert errors the same way by default.
 type ValidationErrorEncoder struct {
 	Encoder ErrorEncoder
 }
 
 // Encode implements the ErrorEncoder interface for encoding ValidationErrors
 func (enc *ValidationErrorEncoder) Encode(ctx context.Context, err error, w http.ResponseWriter) {
-	if e, ok := err.(*routers.RouteError); ok {
-		cErr := convertRouteError(e)
+	if cErr := convertError(err); cErr != nil {
 		enc.Encoder(ctx, cErr, w)
 		return
 	}
+	enc.Encoder(ctx, err, w)
+}
+
+// convertError returns the ValidationError for a route or request error,
+// or nil for other errors.
+func convertError(err error) *ValidationError {
+	if e, ok := err.(*routers.RouteError); ok {
+		return convertRouteError(e)
+	}
 
 	e, ok := err.(*RequestError)
 	if !ok {
-		enc.Encoder(ctx, err, w)
-		return
+		return nil
 	}
 
 	var cErr *ValidationError
@@ -36,17 +47,16 @@ func (enc *ValidationErrorEncoder) Encode(ctx context.Context, err error, w http
 		cErr = convertErrInvalidRequired(e)
 	} else if e.Err == ErrInvalidEmptyValue {
 		cErr = convertErrInvalidEmptyValue(e)
+	} else if errors.Is(e.Err, ErrUnsupportedContentEncoding) {
+		cErr = &ValidationError{Status: http.StatusUnsupportedMediaType, Title: e.Err.Error()}
+	} else if errors.Is(e.Err, ErrDecompressedBodyTooLarge) || errors.Is(e.Err, ErrBodyTooComplex) {
+		cErr = &ValidationError{Status: http.StatusRequestEntityTooLarge, Title: e.Err.Error()}
 	} else if innerErr, ok := e.Err.(*ParseError); ok {
 		cErr = convertParseError(e, innerErr)
 	} else if innerErr, ok := e.Err.(*openapi3.SchemaError); ok {
 		cErr = convertSchemaError(e, innerErr)
 	}
-
-	if cErr != nil {
-		enc.Encoder(ctx, cErr, w)
-		return
-	}
-	enc.Encoder(ctx, err, w)
+	return cErr
 }
 
 func convertRouteError(e *routers.RouteError) *ValidationError {
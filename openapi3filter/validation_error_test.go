@@ -471,7 +471,7 @@ func TestValidationHandler_validateRequest(t *testing.T) {
 			h, err := buildValidationHandler(tt)
 			req.NoError(err)
 
-			err = h.validateRequest(tt.args.r)
+			_, err = h.validateRequest(tt.args.r)
 			req.Equal(tt.wantErr, err != nil)
 
 			if err != nil {
@@ -541,7 +541,7 @@ func TestValidationErrorEncoder(t *testing.T) {
 			h, err := buildValidationHandler(tt)
 			req.NoError(err)
 
-			err = h.validateRequest(tt.args.r)
+			_, err = h.validateRequest(tt.args.r)
 			req.Equal(tt.wantErr, err != nil)
 
 			if err != nil {
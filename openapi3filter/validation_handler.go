@@ -48,14 +48,15 @@ func (h *ValidationHandler) Load() error {
 		h.AuthenticationFunc = NoopAuthenticationFunc
 	}
 	if h.ErrorEncoder == nil {
-		h.ErrorEncoder = DefaultErrorEncoder
+		h.ErrorEncoder = DefaultErrorPipeline.Encode
 	}
 
 	return nil
 }
 
 func (h *ValidationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if handled := h.before(w, r); handled {
+	r, handled := h.before(w, r)
+	if handled {
 		return
 	}
 	// TODO: validateResponse
@@ -65,7 +66,8 @@ func (h *ValidationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 // Middleware implements gorilla/mux MiddlewareFunc
 func (h *ValidationHandler) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if handled := h.before(w, r); handled {
+		r, handled := h.before(w, r)
+		if handled {
 			return
 		}
 		// TODO: validateResponse
@@ -73,19 +75,25 @@ func (h *ValidationHandler) Middleware(next http.Handler) http.Handler {
 	})
 }
 
-func (h *ValidationHandler) before(w http.ResponseWriter, r *http.Request) (handled bool) {
-	if err := h.validateRequest(r); err != nil {
+// before validates the request and returns it, with the principal it was authenticated as
+// in its context if any, unless the request is invalid and the error response handled it.
+func (h *ValidationHandler) before(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	principal, err := h.validateRequest(r)
+	if err != nil {
 		h.ErrorEncoder(r.Context(), err, w)
-		return true
+		return r, true
+	}
+	if principal != nil {
+		r = r.WithContext(ContextWithPrincipal(r.Context(), principal))
 	}
-	return false
+	return r, false
 }
 
-func (h *ValidationHandler) validateRequest(r *http.Request) error {
+func (h *ValidationHandler) validateRequest(r *http.Request) (interface{}, error) {
 	// Find route
 	route, pathParams, err := h.router.FindRoute(r)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	options := &Options{
@@ -100,8 +108,8 @@ func (h *ValidationHandler) validateRequest(r *http.Request) error {
 		Options:    options,
 	}
 	if err = ValidateRequest(r.Context(), requestValidationInput); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return requestValidationInput.Principal(), nil
 }
@@ -0,0 +1,228 @@
+package openapi3filter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// WarningKind classifies warnings.
+type WarningKind string
+
+const (
+	// WarningDeprecated is the kind of warnings about the usage of deprecated operations,
+	// parameters or body properties (see DeprecationWarning).
+	WarningDeprecated WarningKind = "deprecated"
+	// WarningUnknownField is the kind of warnings about query parameters and body properties
+	// that are not declared but tolerated.
+	WarningUnknownField WarningKind = "unknown_field"
+	// WarningCoercion is the kind of warnings about values accepted leniently, such as query
+	// parameters holding reserved characters which are not percent-encoded
+	// (see Options.AllowUnencodedReserved).
+	WarningCoercion WarningKind = "coercion"
+)
+
+// Warning is a finding of request validation that does not make the request invalid.
+type Warning struct {
+	Kind  WarningKind
+	Input *RequestValidationInput
+	// Parameter is the parameter the warning is about, if any.
+	Parameter *openapi3.Parameter
+	// Property is the JSON pointer of the body property the warning is about, if any.
+	Property string
+	Message  string
+}
+
+func (w *Warning) String() string {
+	return w.Message
+}
+
+// ValidationResult separates the errors that make a request invalid
+// from the warnings it is tolerated with.
+type ValidationResult struct {
+	Errors   []error
+	Warnings []*Warning
+}
+
+// Err returns nil if the result has no errors, its error if it has one,
+// or an openapi3.MultiError of its errors.
+func (r *ValidationResult) Err() error {
+	switch len(r.Errors) {
+	case 0:
+		return nil
+	case 1:
+		return r.Errors[0]
+	default:
+		return openapi3.MultiError(r.Errors)
+	}
+}
+
+// ValidateRequestResult validates the request of input like ValidateRequest
+// and also reports what the request is tolerated with as warnings.
+// Warnings are in the order they are found, which varies with Options.Concurrency.
+// Options.DeprecationFunc is still called, with each deprecation warning.
+func ValidateRequestResult(ctx context.Context, input *RequestValidationInput) *ValidationResult {
+	result := &ValidationResult{}
+
+	var options Options
+	if input.Options != nil {
+		options = *input.Options
+	} else {
+		options = *DefaultOptions
+	}
+	var mu sync.Mutex
+	options.warningFunc = func(w *Warning) {
+		mu.Lock()
+		defer mu.Unlock()
+		result.Warnings = append(result.Warnings, w)
+	}
+	deprecationFunc := options.DeprecationFunc
+	options.DeprecationFunc = func(ctx context.Context, warning *DeprecationWarning) {
+		if deprecationFunc != nil {
+			deprecationFunc(ctx, warning)
+		}
+		options.warningFunc(&Warning{
+			Kind:      WarningDeprecated,
+			Input:     warning.Input,
+			Parameter: warning.Parameter,
+			Property:  warning.Property,
+			Message:   warning.String(),
+		})
+	}
+
+	saved := input.Options
+	input.Options = &options
+	err := ValidateRequest(ctx, input)
+	input.Options = saved
+
+	if me, ok := err.(openapi3.MultiError); ok {
+		result.Errors = me
+	} else if err != nil {
+		result.Errors = []error{err}
+	}
+	return result
+}
+
+// warnUnknownQueryParameters warns about the query parameters of the request
+// which are not among parameters.
+func warnUnknownQueryParameters(input *RequestValidationInput, parameters []*openapi3.Parameter) {
+	query := input.GetQueryParams()
+	if len(query) == 0 {
+		return
+	}
+	known := make(map[string]struct{}, len(parameters))
+	for _, parameter := range parameters {
+		if parameter.In != openapi3.ParameterInQuery {
+			continue
+		}
+		known[parameter.Name] = struct{}{}
+		// Properties of exploded form object parameters are query parameters of their own
+		if schema := parameter.Schema; schema != nil && schema.Value != nil && schema.Value.Type == "object" {
+			if sm, err := parameter.SerializationMethod(); err == nil && sm.Style == "form" && sm.Explode {
+				for name := range schema.Value.Properties.Map() {
+					known[name] = struct{}{}
+				}
+			}
+		}
+	}
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := known[name]; ok {
+			continue
+		}
+		// Properties of deepObject parameters, e.g. "filter[name]"
+		if i := strings.IndexByte(name, '['); i > 0 {
+			if _, ok := known[name[:i]]; ok {
+				continue
+			}
+		}
+		input.Options.warningFunc(&Warning{
+			Kind:    WarningUnknownField,
+			Input:   input,
+			Message: fmt.Sprintf("query parameter %q is not declared", name),
+		})
+	}
+}
+
+// warnUnknownProperties warns about the properties of value, the body of the request,
+// which schema does not declare but tolerates.
+func warnUnknownProperties(input *RequestValidationInput, schema *openapi3.Schema, value interface{}) {
+	for _, pointer := range unknownProperties(schema, value) {
+		input.Options.warningFunc(&Warning{
+			Kind:     WarningUnknownField,
+			Input:    input,
+			Property: pointer,
+			Message:  fmt.Sprintf("request body property %q is not declared", pointer),
+		})
+	}
+}
+
+// unknownProperties returns the JSON pointers of the properties set in value
+// which schema does not declare, in order.
+// Properties of objects whose schemas set additionalProperties are not unknown:
+// they are either allowed or invalid.
+func unknownProperties(schema *openapi3.Schema, value interface{}) []string {
+	var pointers []string
+	findUnknownProperties(schema, value, "", &pointers)
+	sort.Strings(pointers)
+	return pointers
+}
+
+func findUnknownProperties(schema *openapi3.Schema, value interface{}, pointer string, pointers *[]string) {
+	if schema == nil {
+		return
+	}
+	switch value := value.(type) {
+	case map[string]interface{}:
+		properties, closed := objectProperties(schema, make(map[string]*openapi3.Schema))
+		for name, v := range value {
+			propertyPointer := pointer + "/" + escapeJSONPointer(name)
+			property, ok := properties[name]
+			if !ok {
+				if !closed {
+					*pointers = append(*pointers, propertyPointer)
+				}
+				continue
+			}
+			findUnknownProperties(property, v, propertyPointer, pointers)
+		}
+	case []interface{}:
+		if schema.Items == nil {
+			return
+		}
+		for i, v := range value {
+			findUnknownProperties(schema.Items.Value, v, pointer+"/"+strconv.Itoa(i), pointers)
+		}
+	}
+}
+
+// objectProperties adds the properties schema and its allOf, anyOf and oneOf subschemas
+// declare to properties, and tells whether any of them sets additionalProperties.
+func objectProperties(schema *openapi3.Schema, properties map[string]*openapi3.Schema) (map[string]*openapi3.Schema, bool) {
+	closed := schema.AdditionalProperties != nil || schema.AdditionalPropertiesAllowed != nil
+	for name, property := range schema.Properties.Map() {
+		if _, ok := properties[name]; !ok && property != nil {
+			properties[name] = property.Value
+		}
+	}
+	for _, refs := range []openapi3.SchemaRefs{schema.AllOf, schema.AnyOf, schema.OneOf} {
+		for _, ref := range refs {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			if _, subClosed := objectProperties(ref.Value, properties); subClosed {
+				closed = true
+			}
+		}
+	}
+	return properties, closed
+}
@@ -0,0 +1,91 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRequestResult(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      parameters:
+      - {name: legacy, in: query, deprecated: true, schema: {type: boolean}}
+      - {name: path, in: query, schema: {type: string}}
+      - {name: filter, in: query, style: deepObject, schema: {type: object, properties: {kind: {type: string}}}}
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name: {type: string}
+                owner:
+                  allOf:
+                  - {type: object, properties: {id: {type: integer}}}
+                  - {type: object, properties: {email: {type: string}}}
+                labels:
+                  type: object
+                  additionalProperties: {type: string}
+      responses:
+        '200': {description: OK}
+`
+	router := setupTestRouter(t, spec)
+	validate := func(url, body string) (*ValidationResult, *RequestValidationInput) {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		route, pathParams, err := router.FindRoute(req)
+		require.NoError(t, err)
+		input := &RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    &Options{MultiError: true, AllowUnencodedReserved: true},
+		}
+		return ValidateRequestResult(context.Background(), input), input
+	}
+	messages := func(result *ValidationResult) map[WarningKind][]string {
+		found := make(map[WarningKind][]string)
+		for _, warning := range result.Warnings {
+			found[warning.Kind] = append(found[warning.Kind], warning.String())
+		}
+		return found
+	}
+
+	result, input := validate("http://example.com/pets?filter[kind]=dog", `{"name": "Rex", "labels": {"color": "brown"}}`)
+	require.NoError(t, result.Err())
+	require.Empty(t, result.Warnings)
+	require.Nil(t, input.Options.warningFunc)
+
+	result, _ = validate(
+		"http://example.com/pets?legacy=true&path=/a/b&page=2",
+		`{"name": "Rex", "age": 3, "owner": {"id": 1, "email": "a@example.com", "phone": "555"}}`,
+	)
+	require.NoError(t, result.Err())
+	require.Equal(t, map[WarningKind][]string{
+		WarningDeprecated: {`parameter "legacy" in query of POST /pets is deprecated`},
+		WarningCoercion: {
+			`parameter "path" in query accepted leniently: value /a/b: reserved character '/' must be percent-encoded as allowReserved is not set`,
+		},
+		WarningUnknownField: {
+			`request body property "/age" is not declared`,
+			`request body property "/owner/phone" is not declared`,
+			`query parameter "page" is not declared`,
+		},
+	}, messages(result))
+
+	result, _ = validate("http://example.com/pets?legacy=maybe&page=2", `{"name": 1}`)
+	require.Len(t, result.Errors, 2)
+	require.Error(t, result.Err())
+	require.Equal(t, map[WarningKind][]string{
+		WarningUnknownField: {`query parameter "page" is not declared`},
+	}, messages(result))
+}
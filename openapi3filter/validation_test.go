@@ -57,8 +57,8 @@ func TestFilter(t *testing.T) {
 				URL: "http://example.com/api/",
 			},
 		},
-		Paths: openapi3.Paths{
-			"/prefix/{pathArg}/suffix": &openapi3.PathItem{
+		Paths: openapi3.NewPaths().
+			With("/prefix/{pathArg}/suffix", &openapi3.PathItem{
 				Post: &openapi3.Operation{
 					Parameters: openapi3.Parameters{
 						{
@@ -137,9 +137,8 @@ func TestFilter(t *testing.T) {
 					},
 					Responses: openapi3.NewResponses(),
 				},
-			},
-
-			"/issue151": &openapi3.PathItem{
+			}).
+			With("/issue151", &openapi3.PathItem{
 				Get: &openapi3.Operation{
 					Responses: openapi3.NewResponses(),
 				},
@@ -153,8 +152,7 @@ func TestFilter(t *testing.T) {
 						},
 					},
 				},
-			},
-		},
+			}),
 	}
 
 	err := doc.Validate(context.Background())
@@ -249,14 +247,14 @@ func TestFilter(t *testing.T) {
 	// Test query parameter openapi3filter
 	req = ExampleRequest{
 		Method: "POST",
-		URL:    "http://example.com/api/prefix/v/suffix?queryArgAnyOf=ae&queryArgOneOf=ac&queryArgAllOf=2017-12-31T11:59:59",
+		URL:    "http://example.com/api/prefix/v/suffix?queryArgAnyOf=ae&queryArgOneOf=ac&queryArgAllOf=2017-12-31T11%3A59%3A59",
 	}
 	err = expect(req, resp)
 	require.NoError(t, err)
 
 	req = ExampleRequest{
 		Method: "POST",
-		URL:    "http://example.com/api/prefix/v/suffix?queryArgAnyOf=2017-12-31T11:59:59",
+		URL:    "http://example.com/api/prefix/v/suffix?queryArgAnyOf=2017-12-31T11%3A59%3A59",
 	}
 	err = expect(req, resp)
 	require.NoError(t, err)
@@ -277,7 +275,7 @@ func TestFilter(t *testing.T) {
 
 	req = ExampleRequest{
 		Method: "POST",
-		URL:    "http://example.com/api/prefix/v/suffix?queryArgOneOf=2017-12-31T11:59:59",
+		URL:    "http://example.com/api/prefix/v/suffix?queryArgOneOf=2017-12-31T11%3A59%3A59",
 	}
 	err = expect(req, resp)
 	require.IsType(t, &RequestError{}, err)
@@ -535,7 +533,7 @@ func TestRootSecurityRequirementsAreUsedIfNotProvidedAtTheOperationLevel(t *test
 			Title:   "MyAPI",
 			Version: "0.1",
 		},
-		Paths: map[string]*openapi3.PathItem{},
+		Paths: openapi3.NewPaths(),
 		Security: openapi3.SecurityRequirements{
 			{
 				securitySchemes[1].Name: {},
@@ -563,12 +561,12 @@ func TestRootSecurityRequirementsAreUsedIfNotProvidedAtTheOperationLevel(t *test
 			}
 			securityRequirements = tempS
 		}
-		doc.Paths[tc.name] = &openapi3.PathItem{
+		doc.Paths.Set(tc.name, &openapi3.PathItem{
 			Get: &openapi3.Operation{
 				Security:  securityRequirements,
 				Responses: openapi3.NewResponses(),
 			},
-		}
+		})
 	}
 
 	err := doc.Validate(context.Background())
@@ -669,7 +667,7 @@ func TestAnySecurityRequirementMet(t *testing.T) {
 			Title:   "MyAPI",
 			Version: "0.1",
 		},
-		Paths: map[string]*openapi3.PathItem{},
+		Paths: openapi3.NewPaths(),
 		Components: openapi3.Components{
 			SecuritySchemes: map[string]*openapi3.SecuritySchemeRef{},
 		},
@@ -694,12 +692,12 @@ func TestAnySecurityRequirementMet(t *testing.T) {
 		}
 
 		// Create the path with the security requirements
-		doc.Paths[tc.name] = &openapi3.PathItem{
+		doc.Paths.Set(tc.name, &openapi3.PathItem{
 			Get: &openapi3.Operation{
 				Security:  securityRequirements,
 				Responses: openapi3.NewResponses(),
 			},
-		}
+		})
 	}
 
 	err := doc.Validate(context.Background())
@@ -766,7 +764,7 @@ func TestAllSchemesMet(t *testing.T) {
 			Title:   "MyAPI",
 			Version: "0.1",
 		},
-		Paths: map[string]*openapi3.PathItem{},
+		Paths: openapi3.NewPaths(),
 		Components: openapi3.Components{
 			SecuritySchemes: map[string]*openapi3.SecuritySchemeRef{},
 		},
@@ -794,14 +792,14 @@ func TestAllSchemesMet(t *testing.T) {
 			}
 		}
 
-		doc.Paths[tc.name] = &openapi3.PathItem{
+		doc.Paths.Set(tc.name, &openapi3.PathItem{
 			Get: &openapi3.Operation{
 				Security: &openapi3.SecurityRequirements{
 					securityRequirement,
 				},
 				Responses: openapi3.NewResponses(),
 			},
-		}
+		})
 	}
 
 	err := doc.Validate(context.Background())
@@ -0,0 +1,48 @@
+package openapi3filter
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// ValidatorHandle holds the Validator in use by its Middleware, which can be swapped
+// at any time, e.g. by an admin endpoint once a new version of the document is loaded.
+//
+// Each request is validated by the Validator held when it started: in-flight requests
+// finish against the previous document, while new ones use the new one.
+// It is safe for concurrent use.
+type ValidatorHandle struct {
+	current atomic.Value // *Validator
+	// mu serializes swaps
+	mu sync.Mutex
+}
+
+// NewValidatorHandle returns a ValidatorHandle holding v.
+func NewValidatorHandle(v *Validator) *ValidatorHandle {
+	h := &ValidatorHandle{}
+	h.current.Store(v)
+	return h
+}
+
+// Load returns the Validator held.
+func (h *ValidatorHandle) Load() *Validator {
+	return h.current.Load().(*Validator)
+}
+
+// Swap holds v for the requests to come, and returns the Validator held until then.
+func (h *ValidatorHandle) Swap(v *Validator) *Validator {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	old := h.Load()
+	h.current.Store(v)
+	return old
+}
+
+// Middleware returns an http.Handler which wraps the given handler with request
+// and response validation by the Validator held when each request starts.
+func (h *ValidatorHandle) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.Load().Middleware(next).ServeHTTP(w, r)
+	})
+}
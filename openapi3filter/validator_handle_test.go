@@ -0,0 +1,74 @@
+package openapi3filter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+func TestValidatorHandle(t *testing.T) {
+	newValidator := func(schemaType string) *openapi3filter.Validator {
+		doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema: {type: ` + schemaType + `}
+`))
+		require.NoError(t, err)
+		router, err := gorillamux.NewRouter(doc)
+		require.NoError(t, err)
+		return openapi3filter.NewValidator(router, openapi3filter.Strict(true), openapi3filter.OnLog(func(string, error) {}))
+	}
+
+	arrays := newValidator("array")
+	handle := openapi3filter.NewValidatorHandle(arrays)
+	require.Same(t, arrays, handle.Load())
+
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := handle.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("wait") != "" {
+			close(started)
+			<-release
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+
+	inFlight := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(inFlight, httptest.NewRequest(http.MethodGet, "/pets?wait=1", nil))
+	}()
+	<-started
+
+	objects := newValidator("object")
+	require.Same(t, arrays, handle.Swap(objects))
+	require.Same(t, objects, handle.Load())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pets", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, `{}`, w.Body.String())
+
+	// The in-flight request is validated against the previous document
+	close(release)
+	<-done
+	require.Equal(t, http.StatusOK, inFlight.Code)
+	require.Equal(t, `[]`, inFlight.Body.String())
+}
@@ -0,0 +1,11 @@
+// Package openapi3fuzz generates test requests for the operations of an OpenAPIv3 document.
+//
+// For each operation, a valid request is built from the examples and schemas of its
+// parameters and JSON request body, then invalid variants are derived from it by
+// removing a required value, changing the type of a value, going out of the range
+// allowed by its schema or picking a value outside of its enum.
+//
+// Sending these requests to a server shows the gaps of its input validation:
+// valid requests should be accepted and invalid ones rejected with a 4xx status code.
+// Authentication is not taken care of, set credentials on the requests before sending them.
+package openapi3fuzz
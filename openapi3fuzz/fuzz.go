@@ -0,0 +1,404 @@
+package openapi3fuzz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Kind classifies the request of a Case.
+type Kind string
+
+// Kinds of cases
+const (
+	// Valid requests conform to the document.
+	Valid Kind = "valid"
+	// MissingRequired requests lack a required parameter, body or property.
+	MissingRequired Kind = "missing-required"
+	// WrongType requests have a value of another type than the one of its schema.
+	WrongType Kind = "wrong-type"
+	// OutOfRange requests have a value breaking a minimum, maximum, minLength or maxLength constraint.
+	OutOfRange Kind = "out-of-range"
+	// BadEnum requests have a value outside of the enum of its schema.
+	BadEnum Kind = "bad-enum"
+)
+
+// Case is a generated request for an operation.
+type Case struct {
+	Method string
+	// Path is the path template of the operation, e.g. "/pets/{id}".
+	Path        string
+	OperationID string
+	Kind        Kind
+	// Location is the invalid part of the request, e.g. `query limit` or `body /owner/name`.
+	// It is empty for valid requests.
+	Location    string
+	Description string
+	// Request can be sent once. Its GetBody function returns a new copy of the body.
+	Request *http.Request
+}
+
+// Valid tells whether the request conforms to the document.
+func (c *Case) Valid() bool {
+	return c.Kind == Valid
+}
+
+func (c *Case) String() string {
+	if c.Kind == Valid {
+		return fmt.Sprintf("%s %s: valid", c.Method, c.Path)
+	}
+	return fmt.Sprintf("%s %s: %s: %s", c.Method, c.Path, c.Kind, c.Description)
+}
+
+// Option allows tweaking the generated cases
+type Option func(*generator)
+
+// WithBaseURL sets the URL the paths of requests are relative to.
+// It defaults to the URL of the first server of the document, with the default values of its variables.
+func WithBaseURL(baseURL string) Option {
+	return func(g *generator) { g.baseURL = baseURL }
+}
+
+// WithKinds restricts the kinds of generated cases. All kinds are generated by default.
+func WithKinds(kinds ...Kind) Option {
+	return func(g *generator) {
+		g.kinds = make(map[Kind]bool, len(kinds))
+		for _, kind := range kinds {
+			g.kinds[kind] = true
+		}
+	}
+}
+
+type generator struct {
+	baseURL string
+	kinds   map[Kind]bool
+	cases   []*Case
+}
+
+// Generate returns the cases for the operations of doc, sorted by path and method.
+//
+// Only JSON request bodies are generated: operations requiring another kind of body are skipped.
+func Generate(doc *openapi3.T, opts ...Option) ([]*Case, error) {
+	g := &generator{}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.baseURL == "" && len(doc.Servers) != 0 {
+		baseURL, err := doc.Servers[0].ResolveURL(nil)
+		if err != nil {
+			return nil, err
+		}
+		g.baseURL = baseURL
+	}
+	if g.baseURL == "" {
+		g.baseURL = "http://localhost"
+	}
+	g.baseURL = strings.TrimSuffix(g.baseURL, "/")
+
+	var err error
+	doc.WalkOperations(func(path, method string, operation *openapi3.Operation) {
+		if err == nil {
+			if err = g.generateOperation(method, path, doc.Paths.Value(path), operation); err != nil {
+				err = fmt.Errorf("%s %s: %v", method, path, err)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return g.cases, nil
+}
+
+// input holds the values of a request, before serialization.
+type input struct {
+	parameters []*openapi3.Parameter
+	values     map[*openapi3.Parameter]interface{}
+	mediaType  string
+	body       interface{}
+	hasBody    bool
+}
+
+func (in *input) clone() *input {
+	values := make(map[*openapi3.Parameter]interface{}, len(in.values))
+	for p, v := range in.values {
+		values[p] = v
+	}
+	return &input{
+		parameters: in.parameters,
+		values:     values,
+		mediaType:  in.mediaType,
+		body:       cloneValue(in.body),
+		hasBody:    in.hasBody,
+	}
+}
+
+func (g *generator) generateOperation(method, path string, pathItem *openapi3.PathItem, operation *openapi3.Operation) error {
+	valid := &input{
+		parameters: mergeParameters(pathItem.Parameters, operation.Parameters),
+		values:     make(map[*openapi3.Parameter]interface{}),
+	}
+	for _, p := range valid.parameters {
+		if value, ok := parameterValue(p); ok {
+			valid.values[p] = value
+		}
+	}
+	var bodySchema *openapi3.Schema
+	if operation.RequestBody != nil && operation.RequestBody.Value != nil {
+		requestBody := operation.RequestBody.Value
+		mediaTypes := make([]string, 0, len(requestBody.Content))
+		for mediaType := range requestBody.Content {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+		sort.Strings(mediaTypes)
+		for _, mediaType := range mediaTypes {
+			if content := requestBody.Content[mediaType]; isJSON(mediaType) && content != nil {
+				valid.mediaType = mediaType
+				valid.body, valid.hasBody = mediaTypeValue(content)
+				if content.Schema != nil {
+					bodySchema = content.Schema.Value
+				}
+				break
+			}
+		}
+		if !valid.hasBody && requestBody.Required {
+			return nil
+		}
+	}
+
+	add := func(kind Kind, location, description string, in *input) error {
+		if g.kinds != nil && !g.kinds[kind] {
+			return nil
+		}
+		request, err := g.newRequest(method, path, in)
+		if err != nil {
+			return err
+		}
+		g.cases = append(g.cases, &Case{
+			Method:      method,
+			Path:        path,
+			OperationID: operation.OperationID,
+			Kind:        kind,
+			Location:    location,
+			Description: description,
+			Request:     request,
+		})
+		return nil
+	}
+
+	if err := add(Valid, "", "", valid); err != nil {
+		return err
+	}
+	for _, p := range valid.parameters {
+		location := p.In + " " + p.Name
+		if p.Required && p.In != openapi3.ParameterInPath {
+			in := valid.clone()
+			delete(in.values, p)
+			if err := add(MissingRequired, location, fmt.Sprintf("%s parameter %q is missing", p.In, p.Name), in); err != nil {
+				return err
+			}
+		}
+		if p.Schema == nil || p.Schema.Value == nil {
+			continue
+		}
+		for _, m := range invalidValues(p.Schema.Value, valid.values[p], true) {
+			in := valid.clone()
+			in.values[p] = m.value
+			if err := add(m.kind, location, fmt.Sprintf("%s parameter %q %s", p.In, p.Name, m.description), in); err != nil {
+				return err
+			}
+		}
+	}
+	if !valid.hasBody {
+		return nil
+	}
+	if operation.RequestBody.Value.Required {
+		in := valid.clone()
+		in.hasBody = false
+		if err := add(MissingRequired, "body", "request body is missing", in); err != nil {
+			return err
+		}
+	}
+	for _, m := range bodyMutations(bodySchema, valid.body, nil) {
+		in := valid.clone()
+		in.body = m.apply(in.body)
+		location := "body " + pointer(m.path)
+		description := "body property " + pointer(m.path) + " " + m.description
+		if len(m.path) == 0 {
+			location, description = "body", "body "+m.description
+		}
+		if err := add(m.kind, location, description, in); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeParameters returns the parameters of an operation, sorted by location and name.
+// Parameters of the operation override those of its path item.
+func mergeParameters(pathItemParameters, operationParameters openapi3.Parameters) []*openapi3.Parameter {
+	var parameters []*openapi3.Parameter
+	for _, list := range []openapi3.Parameters{operationParameters, pathItemParameters} {
+		for _, parameterRef := range list {
+			if p := parameterRef.Value; p != nil && !containsParameter(parameters, p.In, p.Name) {
+				parameters = append(parameters, p)
+			}
+		}
+	}
+	sort.Slice(parameters, func(i, j int) bool {
+		if parameters[i].In != parameters[j].In {
+			return parameters[i].In < parameters[j].In
+		}
+		return parameters[i].Name < parameters[j].Name
+	})
+	return parameters
+}
+
+func containsParameter(parameters []*openapi3.Parameter, in, name string) bool {
+	for _, p := range parameters {
+		if p.In == in && p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parameterValue returns the example of a parameter, or a value generated from its schema.
+func parameterValue(p *openapi3.Parameter) (interface{}, bool) {
+	if p.Example != nil {
+		return p.Example, true
+	}
+	if value, ok := firstExample(p.Examples); ok {
+		return value, true
+	}
+	if p.Schema != nil && p.Schema.Value != nil {
+		if value := p.Schema.Value.GenerateExample(openapi3.VisitAsRequest()); value != nil {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func mediaTypeValue(mediaType *openapi3.MediaType) (interface{}, bool) {
+	if mediaType.Example != nil {
+		return mediaType.Example, true
+	}
+	if value, ok := firstExample(mediaType.Examples); ok {
+		return value, true
+	}
+	if mediaType.Schema != nil && mediaType.Schema.Value != nil {
+		if value := mediaType.Schema.Value.GenerateExample(openapi3.VisitAsRequest()); value != nil {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// firstExample returns the value of the first example with a value, by name.
+func firstExample(examples openapi3.Examples) (interface{}, bool) {
+	names := make([]string, 0, len(examples))
+	for name, example := range examples {
+		if example != nil && example.Value != nil && example.Value.Value != nil {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, false
+	}
+	sort.Strings(names)
+	return examples[names[0]].Value.Value, true
+}
+
+func (g *generator) newRequest(method, path string, in *input) (*http.Request, error) {
+	query := make(url.Values)
+	header := make(http.Header)
+	var cookies []*http.Cookie
+	for _, p := range in.parameters {
+		value, ok := in.values[p]
+		if !ok {
+			continue
+		}
+		sm, err := p.SerializationMethod()
+		if err != nil {
+			return nil, err
+		}
+		switch p.In {
+		case openapi3.ParameterInPath:
+			path = strings.Replace(path, "{"+p.Name+"}", serializePath(p.Name, sm, value), -1)
+		case openapi3.ParameterInQuery:
+			serializeQuery(query, p.Name, sm, value)
+		case openapi3.ParameterInHeader:
+			header.Set(p.Name, serializeSimple(sm.Explode, value, url.QueryEscape))
+		case openapi3.ParameterInCookie:
+			cookies = append(cookies, &http.Cookie{Name: p.Name, Value: serializeSimple(false, value, url.QueryEscape)})
+		}
+	}
+
+	u := g.baseURL + path
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+	var body []byte
+	if in.hasBody {
+		data, err := json.Marshal(in.body)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+	request, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if !in.hasBody {
+		request.Body, request.GetBody, request.ContentLength = http.NoBody, nil, 0
+	} else {
+		request.Header.Set("Content-Type", in.mediaType)
+	}
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	for _, cookie := range cookies {
+		request.AddCookie(cookie)
+	}
+	return request, nil
+}
+
+func isJSON(mediaType string) bool {
+	mediaType = strings.ToLower(strings.TrimSpace(strings.SplitN(mediaType, ";", 2)[0]))
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// cloneValue returns a deep copy of a JSON value.
+func cloneValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			clone[k] = cloneValue(v)
+		}
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(value))
+		for i, v := range value {
+			clone[i] = cloneValue(v)
+		}
+		return clone
+	}
+	return value
+}
+
+// pointer returns the JSON pointer made of the given reference tokens.
+func pointer(tokens []string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1))
+	}
+	return b.String()
+}
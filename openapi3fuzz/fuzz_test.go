@@ -0,0 +1,144 @@
+package openapi3fuzz_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/openapi3fuzz"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+const spec = `
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+servers: [{url: "http://example.com/{base}", variables: {base: {default: api}}}]
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+      - {name: limit, in: query, schema: {type: integer, minimum: 1, maximum: 100}}
+      - {name: kind, in: query, schema: {type: string, enum: [cat, dog]}}
+      - {name: tags, in: query, schema: {type: array, items: {type: string}}, example: [a, b]}
+      - {name: X-Request-Id, in: header, required: true, schema: {type: string, minLength: 8, maxLength: 36}}
+      responses:
+        "200": {description: Pets}
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name, owner]
+              properties:
+                id: {type: integer, readOnly: true}
+                name: {type: string, minLength: 1}
+                owner:
+                  type: object
+                  required: [email]
+                  properties:
+                    email: {type: string, format: email}
+                tags:
+                  type: array
+                  maxItems: 2
+                  items: {type: string, enum: [small, large]}
+      responses:
+        "201": {description: Created}
+  /pets/{id}:
+    parameters:
+    - {name: id, in: path, required: true, schema: {type: integer, minimum: 1}}
+    delete:
+      operationId: deletePet
+      responses:
+        "204": {description: Deleted}
+`
+
+func TestGenerate(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	cases, err := openapi3fuzz.Generate(doc)
+	require.NoError(t, err)
+
+	var summary []string
+	for _, c := range cases {
+		summary = append(summary, c.String())
+
+		route, pathParams, err := router.FindRoute(c.Request)
+		require.NoError(t, err, c.String())
+		err = openapi3filter.ValidateRequest(context.Background(), &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+		})
+		if c.Valid() {
+			require.NoError(t, err, c.String())
+		} else {
+			require.Error(t, err, c.String())
+		}
+	}
+	require.Equal(t, []string{
+		"GET /pets: valid",
+		`GET /pets: missing-required: header parameter "X-Request-Id" is missing`,
+		`GET /pets: out-of-range: header parameter "X-Request-Id" is shorter than minLength 8`,
+		`GET /pets: out-of-range: header parameter "X-Request-Id" is longer than maxLength 36`,
+		`GET /pets: bad-enum: query parameter "kind" is not one of the enum values`,
+		`GET /pets: wrong-type: query parameter "limit" is not of type integer`,
+		`GET /pets: out-of-range: query parameter "limit" is below minimum 1`,
+		`GET /pets: out-of-range: query parameter "limit" is above maximum 100`,
+		"POST /pets: valid",
+		"POST /pets: missing-required: request body is missing",
+		"POST /pets: wrong-type: body is not of type object",
+		"POST /pets: missing-required: body property /name is missing",
+		"POST /pets: missing-required: body property /owner is missing",
+		"POST /pets: wrong-type: body property /name is not of type string",
+		"POST /pets: out-of-range: body property /name is shorter than minLength 1",
+		"POST /pets: wrong-type: body property /owner is not of type object",
+		"POST /pets: missing-required: body property /owner/email is missing",
+		"POST /pets: wrong-type: body property /owner/email is not of type string",
+		"POST /pets: wrong-type: body property /tags is not of type array",
+		"POST /pets: out-of-range: body property /tags has more items than maxItems 2",
+		"POST /pets: wrong-type: body property /tags/0 is not of type string",
+		"POST /pets: bad-enum: body property /tags/0 is not one of the enum values",
+		"DELETE /pets/{id}: valid",
+		`DELETE /pets/{id}: wrong-type: path parameter "id" is not of type integer`,
+		`DELETE /pets/{id}: out-of-range: path parameter "id" is below minimum 1`,
+	}, summary)
+
+	require.Equal(t, "http://example.com/api/pets?kind=cat&limit=1&tags=a&tags=b", cases[0].Request.URL.String())
+	require.Equal(t, "stringxx", cases[0].Request.Header.Get("X-Request-Id"))
+	require.Equal(t, "body /owner/email", cases[16].Location)
+	require.Equal(t, "createPet", cases[16].OperationID)
+}
+
+func TestGenerateWithOptions(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+
+	cases, err := openapi3fuzz.Generate(doc,
+		openapi3fuzz.WithBaseURL("http://localhost:8080/"),
+		openapi3fuzz.WithKinds(openapi3fuzz.Valid, openapi3fuzz.BadEnum),
+	)
+	require.NoError(t, err)
+	var urls []string
+	for _, c := range cases {
+		urls = append(urls, c.Request.Method+" "+c.Request.URL.String())
+	}
+	require.Equal(t, []string{
+		"GET http://localhost:8080/pets?kind=cat&limit=1&tags=a&tags=b",
+		"GET http://localhost:8080/pets?kind=invalid&limit=1&tags=a&tags=b",
+		"POST http://localhost:8080/pets",
+		"POST http://localhost:8080/pets",
+		"DELETE http://localhost:8080/pets/1",
+	}, urls)
+}
@@ -0,0 +1,214 @@
+package openapi3fuzz
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// invalidValue is a value breaking a schema.
+type invalidValue struct {
+	kind        Kind
+	value       interface{}
+	description string
+}
+
+// invalidValues returns values breaking schema, derived from the valid value.
+// Parameters are serialized as text so strings cannot be given a wrong type.
+func invalidValues(schema *openapi3.Schema, value interface{}, parameter bool) []invalidValue {
+	var values []invalidValue
+	add := func(kind Kind, value interface{}, format string, args ...interface{}) {
+		values = append(values, invalidValue{kind: kind, value: value, description: fmt.Sprintf(format, args...)})
+	}
+
+	if wrong, ok := wrongTypeValue(schema.Type, parameter); ok {
+		add(WrongType, wrong, "is not of type %s", schema.Type)
+	}
+
+	switch schema.Type {
+	case openapi3.TypeInteger, openapi3.TypeNumber:
+		if min, exclusive, ok := schema.Minimum(); ok {
+			below := min - 1
+			if exclusive {
+				below = min
+			}
+			add(OutOfRange, below, "is below minimum %v", min)
+		}
+		if max, exclusive, ok := schema.Maximum(); ok {
+			above := max + 1
+			if exclusive {
+				above = max
+			}
+			add(OutOfRange, above, "is above maximum %v", max)
+		}
+	case openapi3.TypeString:
+		if n := schema.MinLength; n > 0 {
+			add(OutOfRange, strings.Repeat("x", int(n-1)), "is shorter than minLength %d", n)
+		}
+		if max := schema.MaxLength; max != nil {
+			add(OutOfRange, strings.Repeat("x", int(*max+1)), "is longer than maxLength %d", *max)
+		}
+	case openapi3.TypeArray:
+		items, _ := value.([]interface{})
+		if n := schema.MinItems; n > 0 && uint64(len(items)) >= n {
+			add(OutOfRange, cloneValue(items[:n-1]), "has fewer items than minItems %d", n)
+		}
+		if max := schema.MaxItems; max != nil && len(items) != 0 {
+			longer := cloneValue(items).([]interface{})
+			for uint64(len(longer)) <= *max {
+				longer = append(longer, cloneValue(items[0]))
+			}
+			add(OutOfRange, longer, "has more items than maxItems %d", *max)
+		}
+	}
+
+	if len(schema.Enum) != 0 {
+		if outside, ok := outsideEnum(schema.Enum); ok {
+			add(BadEnum, outside, "is not one of the enum values")
+		}
+	}
+	return values
+}
+
+// wrongTypeValue returns a value whose type is not typ.
+func wrongTypeValue(typ string, parameter bool) (interface{}, bool) {
+	if parameter {
+		switch typ {
+		case openapi3.TypeInteger, openapi3.TypeNumber, openapi3.TypeBoolean:
+			return "x", true
+		}
+		return nil, false
+	}
+	switch typ {
+	case openapi3.TypeString:
+		return float64(1), true
+	case openapi3.TypeInteger, openapi3.TypeNumber:
+		return "1", true
+	case openapi3.TypeBoolean:
+		return "true", true
+	case openapi3.TypeObject:
+		return []interface{}{}, true
+	case openapi3.TypeArray:
+		return map[string]interface{}{}, true
+	}
+	return nil, false
+}
+
+// outsideEnum returns a value of the type of the enum values that is not one of them.
+func outsideEnum(enum []interface{}) (interface{}, bool) {
+	strs := make(map[string]bool, len(enum))
+	max := math.Inf(-1)
+	for _, v := range enum {
+		switch v := v.(type) {
+		case string:
+			strs[v] = true
+		case float64:
+			max = math.Max(max, v)
+		case int:
+			max = math.Max(max, float64(v))
+		}
+	}
+	switch {
+	case len(strs) != 0:
+		outside := "invalid"
+		for strs[outside] {
+			outside += "x"
+		}
+		return outside, true
+	case !math.IsInf(max, -1):
+		return math.Floor(max) + 1, true
+	}
+	return nil, false
+}
+
+// bodyMutation replaces or removes the value at a path of a body.
+type bodyMutation struct {
+	invalidValue
+	path   []string
+	remove bool
+}
+
+// bodyMutations returns the mutations of the valid value breaking schema,
+// looking into the properties, items and allOf subschemas of objects and arrays.
+func bodyMutations(schema *openapi3.Schema, value interface{}, path []string) []bodyMutation {
+	if schema == nil {
+		return nil
+	}
+	var mutations []bodyMutation
+	for _, v := range invalidValues(schema, value, false) {
+		mutations = append(mutations, bodyMutation{invalidValue: v, path: path})
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for _, name := range schema.Required {
+			if _, ok := value[name]; ok {
+				mutations = append(mutations, bodyMutation{
+					invalidValue: invalidValue{kind: MissingRequired, description: "is missing"},
+					path:         appendPath(path, name),
+					remove:       true,
+				})
+			}
+		}
+		for _, name := range schema.Properties.InSortedOrder() {
+			property := schema.Properties.Value(name)
+			if v, ok := value[name]; ok && property != nil {
+				mutations = append(mutations, bodyMutations(property.Value, v, appendPath(path, name))...)
+			}
+		}
+	case []interface{}:
+		if len(value) != 0 && schema.Items != nil {
+			mutations = append(mutations, bodyMutations(schema.Items.Value, value[0], appendPath(path, "0"))...)
+		}
+	}
+	for _, subschema := range schema.AllOf {
+		if subschema != nil {
+			mutations = append(mutations, bodyMutations(subschema.Value, value, path)...)
+		}
+	}
+	return mutations
+}
+
+func appendPath(path []string, token string) []string {
+	return append(append(make([]string, 0, len(path)+1), path...), token)
+}
+
+// apply returns body with the mutation applied. body is modified in place.
+func (m *bodyMutation) apply(body interface{}) interface{} {
+	if len(m.path) == 0 {
+		return m.value
+	}
+	parent := body
+	for _, token := range m.path[:len(m.path)-1] {
+		parent = child(parent, token)
+	}
+	last := m.path[len(m.path)-1]
+	switch parent := parent.(type) {
+	case map[string]interface{}:
+		if m.remove {
+			delete(parent, last)
+		} else {
+			parent[last] = m.value
+		}
+	case []interface{}:
+		if i, err := strconv.Atoi(last); err == nil && i < len(parent) {
+			parent[i] = m.value
+		}
+	}
+	return body
+}
+
+func child(value interface{}, token string) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return value[token]
+	case []interface{}:
+		if i, err := strconv.Atoi(token); err == nil && i < len(value) {
+			return value[i]
+		}
+	}
+	return nil
+}
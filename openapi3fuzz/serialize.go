@@ -0,0 +1,128 @@
+package openapi3fuzz
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// formatValue returns the text of a primitive value. Other values are encoded as JSON.
+func formatValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool, int, int64:
+		return fmt.Sprint(value)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// serializeSimple serializes a value with the simple style, escaping each part.
+func serializeSimple(explode bool, value interface{}, escape func(string) string) string {
+	switch value := value.(type) {
+	case []interface{}:
+		parts := make([]string, 0, len(value))
+		for _, v := range value {
+			parts = append(parts, escape(formatValue(v)))
+		}
+		return strings.Join(parts, ",")
+	case map[string]interface{}:
+		parts := make([]string, 0, 2*len(value))
+		for _, k := range sortedNames(value) {
+			if explode {
+				parts = append(parts, escape(k)+"="+escape(formatValue(value[k])))
+			} else {
+				parts = append(parts, escape(k), escape(formatValue(value[k])))
+			}
+		}
+		return strings.Join(parts, ",")
+	}
+	return escape(formatValue(value))
+}
+
+func serializePath(name string, sm *openapi3.SerializationMethod, value interface{}) string {
+	switch sm.Style {
+	case openapi3.SerializationLabel:
+		s := serializeSimple(sm.Explode, value, url.PathEscape)
+		if sm.Explode {
+			s = strings.Replace(s, ",", ".", -1)
+		}
+		return "." + s
+	case openapi3.SerializationMatrix:
+		switch v := value.(type) {
+		case []interface{}:
+			if sm.Explode {
+				var b strings.Builder
+				for _, item := range v {
+					b.WriteString(";" + name + "=" + url.PathEscape(formatValue(item)))
+				}
+				return b.String()
+			}
+		case map[string]interface{}:
+			if sm.Explode {
+				return ";" + strings.Replace(serializeSimple(true, value, url.PathEscape), ",", ";", -1)
+			}
+		}
+		return ";" + name + "=" + serializeSimple(false, value, url.PathEscape)
+	}
+	return serializeSimple(sm.Explode, value, url.PathEscape)
+}
+
+func serializeQuery(query url.Values, name string, sm *openapi3.SerializationMethod, value interface{}) {
+	switch value := value.(type) {
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			values = append(values, formatValue(v))
+		}
+		if sm.Explode {
+			query[name] = append(query[name], values...)
+			return
+		}
+		separator := ","
+		switch sm.Style {
+		case openapi3.SerializationSpaceDelimited:
+			separator = " "
+		case openapi3.SerializationPipeDelimited:
+			separator = "|"
+		}
+		query.Add(name, strings.Join(values, separator))
+	case map[string]interface{}:
+		for _, k := range sortedNames(value) {
+			switch {
+			case sm.Style == openapi3.SerializationDeepObject:
+				query.Add(name+"["+k+"]", formatValue(value[k]))
+			case sm.Explode:
+				query.Add(k, formatValue(value[k]))
+			}
+		}
+		if sm.Style != openapi3.SerializationDeepObject && !sm.Explode {
+			query.Add(name, serializeSimple(false, value, func(s string) string { return s }))
+		}
+	default:
+		query.Add(name, formatValue(value))
+	}
+}
+
+// sortedNames returns the sorted names of the properties of an object value.
+func sortedNames(value map[string]interface{}) []string {
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
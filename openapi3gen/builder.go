@@ -0,0 +1,251 @@
+package openapi3gen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Operation describes an operation registered with a DocumentBuilder.
+type Operation struct {
+	OperationID string
+	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+
+	// Parameters is a struct value whose fields describe the parameters of the operation.
+	// Fields are tagged with where the parameter is found and its name:
+	// `path:"id"`, `query:"limit"`, `header:"X-Request-ID"` or `cookie:"session"`.
+	// Path parameters are required. With UseValidationTags,
+	// constraints (and requiredness) are read from field tags.
+	Parameters interface{}
+
+	// Request is a value of the type of the request body, if any.
+	Request interface{}
+
+	// Responses maps status codes (or 0 for the default response)
+	// to a value of the type of the response body, or nil for responses without body.
+	Responses map[int]interface{}
+
+	// ContentType of the request and response bodies. Defaults to "application/json".
+	ContentType string
+}
+
+// DocumentBuilder builds a document from operations registered with Go types.
+//
+// Named struct types are defined once in the components schemas,
+// under the name of the type, and referenced wherever they are used.
+type DocumentBuilder struct {
+	doc       *openapi3.T
+	generator *Generator
+	err       error
+}
+
+// NewDocumentBuilder returns a builder of a document with the given info.
+// Options apply to the generation of all schemas.
+func NewDocumentBuilder(info *openapi3.Info, opts ...Option) *DocumentBuilder {
+	return &DocumentBuilder{
+		doc: &openapi3.T{
+			OpenAPI: "3.0.3",
+			Info:    info,
+			Paths:   openapi3.NewPaths(),
+		},
+		generator: NewGenerator(opts...),
+	}
+}
+
+// AddServer adds a server to the document.
+func (b *DocumentBuilder) AddServer(server *openapi3.Server) *DocumentBuilder {
+	b.doc.AddServer(server)
+	return b
+}
+
+// AddOperation registers an operation.
+// Errors are reported by Build.
+func (b *DocumentBuilder) AddOperation(method, path string, operation *Operation) *DocumentBuilder {
+	if b.err != nil {
+		return b
+	}
+	if err := b.addOperation(method, path, operation); err != nil {
+		b.err = fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	return b
+}
+
+func (b *DocumentBuilder) addOperation(method, path string, operation *Operation) error {
+	if pathItem := b.doc.Paths.Value(path); pathItem != nil && pathItem.GetOperation(method) != nil {
+		return fmt.Errorf("operation already registered")
+	}
+	op := openapi3.NewOperation()
+	op.OperationID = operation.OperationID
+	op.Summary = operation.Summary
+	op.Description = operation.Description
+	op.Tags = operation.Tags
+	op.Deprecated = operation.Deprecated
+
+	contentType := operation.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	if operation.Parameters != nil {
+		parameters, err := b.parameters(reflect.TypeOf(operation.Parameters))
+		if err != nil {
+			return err
+		}
+		op.Parameters = parameters
+	}
+
+	if operation.Request != nil {
+		schemaRef, err := b.schemaRef(operation.Request)
+		if err != nil {
+			return err
+		}
+		requestBody := openapi3.NewRequestBody().WithRequired(true).
+			WithContent(openapi3.Content{contentType: &openapi3.MediaType{Schema: schemaRef}})
+		op.RequestBody = &openapi3.RequestBodyRef{Value: requestBody}
+	}
+
+	codes := make([]int, 0, len(operation.Responses))
+	for code := range operation.Responses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	op.Responses = &openapi3.Responses{}
+	for _, code := range codes {
+		status, description := "default", "Default response"
+		if code != 0 {
+			status, description = strconv.Itoa(code), http.StatusText(code)
+		}
+		response := openapi3.NewResponse().WithDescription(description)
+		if value := operation.Responses[code]; value != nil {
+			schemaRef, err := b.schemaRef(value)
+			if err != nil {
+				return err
+			}
+			response.Content = openapi3.Content{contentType: &openapi3.MediaType{Schema: schemaRef}}
+		}
+		op.Responses.Set(status, &openapi3.ResponseRef{Value: response})
+	}
+	if op.Responses.Len() == 0 {
+		op.Responses.Set("default", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Default response")})
+	}
+
+	b.doc.AddOperation(path, method, op)
+	return nil
+}
+
+func (b *DocumentBuilder) schemaRef(value interface{}) (*openapi3.SchemaRef, error) {
+	return b.generator.GenerateSchemaRef(reflect.TypeOf(value))
+}
+
+func (b *DocumentBuilder) parameters(t reflect.Type) (openapi3.Parameters, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("parameters must be a struct, not %s", t)
+	}
+	var parameters openapi3.Parameters
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		var parameter *openapi3.Parameter
+		for _, in := range []string{openapi3.ParameterInPath, openapi3.ParameterInQuery, openapi3.ParameterInHeader, openapi3.ParameterInCookie} {
+			if name, ok := field.Tag.Lookup(in); ok {
+				parameter = &openapi3.Parameter{In: in, Name: name, Required: in == openapi3.ParameterInPath}
+				break
+			}
+		}
+		if parameter == nil {
+			continue
+		}
+		schemaRef, err := b.generator.GenerateSchemaRef(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q: %w", parameter.Name, err)
+		}
+		if b.generator.opts.useValidationTags {
+			var required bool
+			if schemaRef, required, err = b.generator.applyTagConstraints(schemaRef, field.Type, field.Tag); err != nil {
+				return nil, fmt.Errorf("parameter %q: %w", parameter.Name, err)
+			}
+			parameter.Required = parameter.Required || required
+		}
+		parameter.Schema = schemaRef
+		parameters = append(parameters, &openapi3.ParameterRef{Value: parameter})
+	}
+	return parameters, nil
+}
+
+// Build returns the document, after checking it is valid.
+// Named struct types are moved to the components schemas.
+// The builder should not be used anymore afterwards.
+func (b *DocumentBuilder) Build(ctx context.Context) (*openapi3.T, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	g := b.generator
+
+	// References are sorted so that names are assigned deterministically:
+	// types sharing a name are suffixed in lexical order of their package paths.
+	refs := make([]*openapi3.SchemaRef, 0, len(g.refTypes))
+	for ref := range g.refTypes {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		ti, tj := derefType(g.refTypes[refs[i]]), derefType(g.refTypes[refs[j]])
+		if ni, nj := typeName(ti), typeName(tj); ni != nj {
+			return ni < nj
+		}
+		if ti.PkgPath() != tj.PkgPath() {
+			return ti.PkgPath() < tj.PkgPath()
+		}
+		return g.refTypes[refs[i]].Kind() != reflect.Ptr && g.refTypes[refs[j]].Kind() == reflect.Ptr
+	})
+
+	schemas := openapi3.NewSchemas()
+	names := make(map[reflect.Type]string)
+	for _, ref := range refs {
+		t := derefType(g.refTypes[ref])
+		if t.Kind() != reflect.Struct || t.Name() == "" || t == timeType || ref.Value == nil || ref.Value.Type != "object" {
+			continue
+		}
+		name, ok := names[t]
+		if !ok {
+			base := typeName(t)
+			name = base
+			for i := 2; schemas.Value(name) != nil; i++ {
+				name = base + strconv.Itoa(i)
+			}
+			names[t] = name
+			schemas.Set(name, &openapi3.SchemaRef{Value: ref.Value})
+		}
+		ref.Ref = "#/components/schemas/" + name
+	}
+	for ref := range g.SchemaRefs {
+		if !strings.HasPrefix(ref.Ref, "#/components/schemas/") {
+			ref.Ref = ""
+		}
+	}
+	if schemas.Len() != 0 {
+		b.doc.Components.Schemas = schemas
+	}
+
+	if err := b.doc.Validate(ctx); err != nil {
+		return nil, err
+	}
+	return b.doc, nil
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,143 @@
+package openapi3gen_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+)
+
+type builderPet struct {
+	ID    int64         `json:"id"`
+	Name  string        `json:"name" validate:"required,min=1"`
+	Owner *builderOwner `json:"owner,omitempty" openapi:"readOnly,description=Who feeds it"`
+}
+
+type builderOwner struct {
+	Name string        `json:"name"`
+	Pets []*builderPet `json:"pets"`
+}
+
+type builderError struct {
+	Message string `json:"message"`
+}
+
+func TestDocumentBuilder(t *testing.T) {
+	type getPetParams struct {
+		ID      int64  `path:"id"`
+		Fields  string `query:"fields" validate:"required"`
+		TraceID string `header:"X-Trace-ID"`
+		Ignored string
+	}
+
+	b := openapi3gen.NewDocumentBuilder(&openapi3.Info{Title: "Pets", Version: "1.0"}, openapi3gen.UseValidationTags())
+	b.AddServer(&openapi3.Server{URL: "https://api.example.com"})
+	b.AddOperation(http.MethodGet, "/pets/{id}", &openapi3gen.Operation{
+		OperationID: "getPet",
+		Tags:        []string{"pets"},
+		Parameters:  getPetParams{},
+		Responses: map[int]interface{}{
+			http.StatusOK:       &builderPet{},
+			http.StatusNotFound: builderError{},
+		},
+	})
+	b.AddOperation(http.MethodPost, "/pets", &openapi3gen.Operation{
+		OperationID: "createPet",
+		Request:     builderPet{},
+		Responses: map[int]interface{}{
+			http.StatusCreated:   []builderPet{},
+			http.StatusNoContent: nil,
+			0:                    builderError{},
+		},
+	})
+
+	doc, err := b.Build(context.Background())
+	require.NoError(t, err)
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+{
+  "openapi": "3.0.3",
+  "info": {"title": "Pets", "version": "1.0"},
+  "servers": [{"url": "https://api.example.com"}],
+  "paths": {
+    "/pets": {
+      "post": {
+        "operationId": "createPet",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/builderPet"}}}
+        },
+        "responses": {
+          "201": {
+            "description": "Created",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/builderPet"}}}}
+          },
+          "204": {"description": "No Content"},
+          "default": {
+            "description": "Default response",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/builderError"}}}
+          }
+        }
+      }
+    },
+    "/pets/{id}": {
+      "get": {
+        "operationId": "getPet",
+        "tags": ["pets"],
+        "parameters": [
+          {"in": "path", "name": "id", "required": true, "schema": {"type": "integer", "format": "int64"}},
+          {"in": "query", "name": "fields", "required": true, "schema": {"type": "string"}},
+          {"in": "header", "name": "X-Trace-ID", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/builderPet"}}}
+          },
+          "404": {
+            "description": "Not Found",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/builderError"}}}
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "builderError": {
+        "type": "object",
+        "properties": {"message": {"type": "string"}}
+      },
+      "builderOwner": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "pets": {"type": "array", "items": {"$ref": "#/components/schemas/builderPet"}}
+        }
+      },
+      "builderPet": {
+        "type": "object",
+        "required": ["name"],
+        "properties": {
+          "id": {"type": "integer", "format": "int64"},
+          "name": {"type": "string", "minLength": 1},
+          "owner": {"allOf": [{"$ref": "#/components/schemas/builderOwner"}, {"readOnly": true, "description": "Who feeds it"}]}
+        }
+      }
+    }
+  }
+}`, string(data))
+
+	b = openapi3gen.NewDocumentBuilder(&openapi3.Info{Title: "Pets", Version: "1.0"})
+	b.AddOperation(http.MethodGet, "/pets", &openapi3gen.Operation{})
+	b.AddOperation(http.MethodGet, "/pets", &openapi3gen.Operation{})
+	_, err = b.Build(context.Background())
+	require.EqualError(t, err, "GET /pets: operation already registered")
+}
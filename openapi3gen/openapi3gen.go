@@ -2,6 +2,7 @@
 package openapi3gen
 
 import (
+	"encoding"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -38,8 +39,11 @@ type SchemaCustomizerFn func(name string, t reflect.Type, tag reflect.StructTag,
 
 type generatorOpt struct {
 	useAllExportedFields bool
+	useValidationTags    bool
 	throwErrorOnCycle    bool
+	refRecursiveRoot     bool
 	schemaCustomizer     SchemaCustomizerFn
+	marshalerFormats     map[reflect.Type]string
 }
 
 // UseAllExportedFields changes the default behavior of only
@@ -54,14 +58,41 @@ func ThrowErrorOnCycle() Option {
 	return func(x *generatorOpt) { x.throwErrorOnCycle = true }
 }
 
+// RefRecursiveRoot makes NewSchemaRefForValue return a reference to the component schema
+// describing the given value when its type is recursive, instead of an inline copy of that schema.
+func RefRecursiveRoot() Option {
+	return func(x *generatorOpt) { x.refRecursiveRoot = true }
+}
+
 // SchemaCustomizer allows customization of the schema that is generated
 // for a field, for example to support an additional tagging scheme
 func SchemaCustomizer(sc SchemaCustomizerFn) Option {
 	return func(x *generatorOpt) { x.schemaCustomizer = sc }
 }
 
+// MarshalerFormat describes the type of value, which implements json.Marshaler or
+// encoding.TextMarshaler, as a string of the given format (e.g. "uuid" or "decimal"), or of no format.
+//
+// Types implementing encoding.TextMarshaler only are described as strings anyway.
+// Types implementing json.Marshaler are described after the JSON encoding of their zero value
+// unless passed to MarshalerFormat: as a string, a number or a boolean, or else by their Go type,
+// as their MarshalJSON method may emit any value.
+// Use a SchemaCustomizer to describe them otherwise.
+func MarshalerFormat(value interface{}, format string) Option {
+	return func(x *generatorOpt) {
+		if x.marshalerFormats == nil {
+			x.marshalerFormats = make(map[reflect.Type]string)
+		}
+		t := reflect.TypeOf(value)
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		x.marshalerFormats[t] = format
+	}
+}
+
 // NewSchemaRefForValue is a shortcut for NewGenerator(...).NewSchemaRefForValue(...)
-func NewSchemaRefForValue(value interface{}, schemas openapi3.Schemas, opts ...Option) (*openapi3.SchemaRef, error) {
+func NewSchemaRefForValue(value interface{}, schemas *openapi3.Schemas, opts ...Option) (*openapi3.SchemaRef, error) {
 	g := NewGenerator(opts...)
 	return g.NewSchemaRefForValue(value, schemas)
 }
@@ -78,6 +109,12 @@ type Generator struct {
 
 	// componentSchemaRefs is a set of schemas that must be defined in the components to avoid cycles
 	componentSchemaRefs map[string]struct{}
+
+	// refTypes maps generated references to the Go type they describe
+	refTypes map[*openapi3.SchemaRef]reflect.Type
+
+	// inProgress holds the schemas of the struct types being generated, targets of cycle references
+	inProgress map[reflect.Type]*openapi3.Schema
 }
 
 func NewGenerator(opts ...Option) *Generator {
@@ -89,6 +126,8 @@ func NewGenerator(opts ...Option) *Generator {
 		Types:               make(map[reflect.Type]*openapi3.SchemaRef),
 		SchemaRefs:          make(map[*openapi3.SchemaRef]int),
 		componentSchemaRefs: make(map[string]struct{}),
+		refTypes:            make(map[*openapi3.SchemaRef]reflect.Type),
+		inProgress:          make(map[reflect.Type]*openapi3.Schema),
 		opts:                *gOpt,
 	}
 }
@@ -98,17 +137,21 @@ func (g *Generator) GenerateSchemaRef(t reflect.Type) (*openapi3.SchemaRef, erro
 	return g.generateSchemaRefFor(nil, t, "_root", "")
 }
 
-// NewSchemaRefForValue uses reflection on the given value to produce a SchemaRef, and updates a supplied map with any dependent component schemas if they lead to cycles
-func (g *Generator) NewSchemaRefForValue(value interface{}, schemas openapi3.Schemas) (*openapi3.SchemaRef, error) {
+// NewSchemaRefForValue uses reflection on the given value to produce a SchemaRef, and sets in supplied schemas any dependent component schemas if they lead to cycles
+func (g *Generator) NewSchemaRefForValue(value interface{}, schemas *openapi3.Schemas) (*openapi3.SchemaRef, error) {
 	ref, err := g.GenerateSchemaRef(reflect.TypeOf(value))
 	if err != nil {
 		return nil, err
 	}
+	var rootName string
+	if ref != nil {
+		rootName = ref.Ref
+	}
 	for ref := range g.SchemaRefs {
 		if _, ok := g.componentSchemaRefs[ref.Ref]; ok && schemas != nil {
-			schemas[ref.Ref] = &openapi3.SchemaRef{
+			schemas.Set(ref.Ref, &openapi3.SchemaRef{
 				Value: ref.Value,
-			}
+			})
 		}
 		if strings.HasPrefix(ref.Ref, "#/components/schemas/") {
 			ref.Value = nil
@@ -116,6 +159,9 @@ func (g *Generator) NewSchemaRefForValue(value interface{}, schemas openapi3.Sch
 			ref.Ref = ""
 		}
 	}
+	if _, ok := g.componentSchemaRefs[rootName]; ok && g.opts.refRecursiveRoot && schemas != nil {
+		return openapi3.NewSchemaRef("#/components/schemas/"+rootName, nil), nil
+	}
 	return ref, nil
 }
 
@@ -135,6 +181,7 @@ func (g *Generator) generateSchemaRefFor(parents []*jsoninfo.TypeInfo, t reflect
 	if ref != nil {
 		g.Types[t] = ref
 		g.SchemaRefs[ref]++
+		g.refTypes[ref] = t
 	}
 	return ref, nil
 }
@@ -196,6 +243,17 @@ func (g *Generator) generateWithoutSaving(parents []*jsoninfo.TypeInfo, t reflec
 
 	schema := &openapi3.Schema{}
 
+	if format, ok := g.opts.marshalerFormats[t]; ok || isTextMarshaler(t) {
+		// Such types are serialized as strings
+		schema.Type = "string"
+		schema.Format = format
+		return g.customize(name, t, tag, schema)
+	}
+	if typ := marshalerType(t); typ != "" {
+		schema.Type = typ
+		return g.customize(name, t, tag, schema)
+	}
+
 	switch t.Kind() {
 	case reflect.Func, reflect.Chan:
 		return nil, nil // ignore
@@ -292,6 +350,14 @@ func (g *Generator) generateWithoutSaving(parents []*jsoninfo.TypeInfo, t reflec
 			schema.Type = "string"
 			schema.Format = "date-time"
 		} else {
+			// Target of the references generated for cycles back to t
+			if previous, ok := g.inProgress[t]; ok {
+				defer func() { g.inProgress[t] = previous }()
+			} else {
+				defer delete(g.inProgress, t)
+			}
+			g.inProgress[t] = schema
+
 			for _, fieldInfo := range typeInfo.Fields {
 				// Only fields with JSON tag are considered (by default)
 				if !fieldInfo.HasJSONTag && !g.opts.useAllExportedFields {
@@ -322,9 +388,9 @@ func (g *Generator) generateWithoutSaving(parents []*jsoninfo.TypeInfo, t reflec
 					}
 				}
 
-				// extract the field tag if we have a customizer
+				// extract the field tag if we have a customizer or read constraints from it
 				var fieldTag reflect.StructTag
-				if g.opts.schemaCustomizer != nil {
+				if g.opts.schemaCustomizer != nil || g.opts.useValidationTags {
 					ff := getStructField(t, fieldInfo)
 					fieldTag = ff.Tag
 				}
@@ -337,6 +403,15 @@ func (g *Generator) generateWithoutSaving(parents []*jsoninfo.TypeInfo, t reflec
 						return nil, err
 					}
 				}
+				if ref != nil && g.opts.useValidationTags {
+					var required bool
+					if ref, required, err = g.applyTagConstraints(ref, fType, fieldTag); err != nil {
+						return nil, fmt.Errorf("field %s: %w", fieldName, err)
+					}
+					if required {
+						schema.Required = append(schema.Required, fieldName)
+					}
+				}
 				if ref != nil {
 					g.SchemaRefs[ref]++
 					schema.WithPropertyRef(fieldName, ref)
@@ -350,17 +425,68 @@ func (g *Generator) generateWithoutSaving(parents []*jsoninfo.TypeInfo, t reflec
 		}
 	}
 
+	return g.customize(name, t, tag, schema)
+}
+
+func (g *Generator) customize(name string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) (*openapi3.SchemaRef, error) {
 	if g.opts.schemaCustomizer != nil {
 		if err := g.opts.schemaCustomizer(name, t, tag, schema); err != nil {
 			return nil, err
 		}
 	}
 
-	return openapi3.NewSchemaRef(t.Name(), schema), nil
+	return openapi3.NewSchemaRef(typeName(t), schema), nil
+}
+
+// isTextMarshaler reports whether t or *t implements encoding.TextMarshaler but not json.Marshaler,
+// so that its values are serialized as strings, apart from types handled specifically.
+func isTextMarshaler(t reflect.Type) bool {
+	if t == timeType || t == rawMessageType {
+		return false
+	}
+	isText := false
+	for _, t := range []reflect.Type{t, reflect.PtrTo(t)} {
+		if t.Implements(jsonMarshalerType) {
+			return false
+		}
+		isText = isText || t.Implements(textMarshalerType)
+	}
+	return isText
+}
+
+// marshalerType returns the schema type of the JSON encoding of the zero value of t
+// if t or *t implements json.Marshaler and it is a string, a number or a boolean, or else "".
+func marshalerType(t reflect.Type) (typ string) {
+	if t == timeType || t == rawMessageType || !reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		return ""
+	}
+	defer func() {
+		// MarshalJSON methods may not expect zero values
+		if recover() != nil {
+			typ = ""
+		}
+	}()
+	data, err := reflect.New(t).Interface().(json.Marshaler).MarshalJSON()
+	if err != nil {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return ""
+	}
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return ""
 }
 
 func (g *Generator) generateCycleSchemaRef(t reflect.Type, schema *openapi3.Schema) *openapi3.SchemaRef {
-	var typeName string
+	var name string
 	switch t.Kind() {
 	case reflect.Ptr:
 		return g.generateCycleSchemaRef(t.Elem(), schema)
@@ -377,11 +503,16 @@ func (g *Generator) generateCycleSchemaRef(t reflect.Type, schema *openapi3.Sche
 		mapSchema.AdditionalProperties = ref
 		return openapi3.NewSchemaRef("", mapSchema)
 	default:
-		typeName = t.Name()
+		name = typeName(t)
+		if target := g.inProgress[t]; target != nil {
+			schema = target
+		}
 	}
 
-	g.componentSchemaRefs[typeName] = struct{}{}
-	return openapi3.NewSchemaRef(fmt.Sprintf("#/components/schemas/%s", typeName), schema)
+	g.componentSchemaRefs[name] = struct{}{}
+	ref := openapi3.NewSchemaRef(fmt.Sprintf("#/components/schemas/%s", name), schema)
+	g.refTypes[ref] = t
+	return ref
 }
 
 var RefSchemaRef = openapi3.NewSchemaRef("Ref",
@@ -391,6 +522,9 @@ var (
 	timeType       = reflect.TypeOf(time.Time{})
 	rawMessageType = reflect.TypeOf(json.RawMessage{})
 
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
 	zeroInt   = float64(0)
 	maxInt8   = float64(math.MaxInt8)
 	minInt8   = float64(math.MinInt8)
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
@@ -140,7 +141,7 @@ func ExampleThrowErrorOnCycle() {
 		} `json:"a"`
 	}
 
-	schemas := make(openapi3.Schemas)
+	schemas := openapi3.NewSchemas()
 	schemaRef, err := openapi3gen.NewSchemaRefForValue(&CyclicType0{}, schemas, openapi3gen.ThrowErrorOnCycle())
 	if schemaRef != nil || err == nil {
 		panic(`With option ThrowErrorOnCycle, an error is returned when a schema reference cycle is found`)
@@ -148,7 +149,7 @@ func ExampleThrowErrorOnCycle() {
 	if _, ok := err.(*openapi3gen.CycleError); !ok {
 		panic(`With option ThrowErrorOnCycle, an error of type CycleError is returned`)
 	}
-	if len(schemas) != 0 {
+	if schemas.Len() != 0 {
 		panic(`No references should have been collected at this point`)
 	}
 
@@ -208,11 +209,10 @@ func TestExportedNonTagged(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, &openapi3.SchemaRef{Value: &openapi3.Schema{
 		Type: "object",
-		Properties: map[string]*openapi3.SchemaRef{
-			"A":           {Value: &openapi3.Schema{Type: "string"}},
-			"another":     {Value: &openapi3.Schema{Type: "string"}},
-			"even_a_yaml": {Value: &openapi3.Schema{Type: "string"}},
-		}}}, schemaRef)
+		Properties: openapi3.NewSchemas().
+			With("A", &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string"}}).
+			With("another", &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string"}}).
+			With("even_a_yaml", &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string"}})}}, schemaRef)
 }
 
 func ExampleUseAllExportedFields() {
@@ -267,21 +267,21 @@ func ExampleGenerator_GenerateSchemaRef() {
 	}
 
 	var data []byte
-	if data, err = json.MarshalIndent(schemaRef.Value.Properties["Name"].Value, "", "  "); err != nil {
+	if data, err = json.MarshalIndent(schemaRef.Value.Properties.Value("Name").Value, "", "  "); err != nil {
 		panic(err)
 	}
-	fmt.Printf(`schemaRef.Value.Properties["Name"].Value: %s`, data)
+	fmt.Printf(`schemaRef.Value.Properties.Value("Name").Value: %s`, data)
 	fmt.Println()
-	if data, err = json.MarshalIndent(schemaRef.Value.Properties["ID"].Value, "", "  "); err != nil {
+	if data, err = json.MarshalIndent(schemaRef.Value.Properties.Value("ID").Value, "", "  "); err != nil {
 		panic(err)
 	}
-	fmt.Printf(`schemaRef.Value.Properties["ID"].Value: %s`, data)
+	fmt.Printf(`schemaRef.Value.Properties.Value("ID").Value: %s`, data)
 	fmt.Println()
 	// Output:
-	// schemaRef.Value.Properties["Name"].Value: {
+	// schemaRef.Value.Properties.Value("Name").Value: {
 	//   "type": "string"
 	// }
-	// schemaRef.Value.Properties["ID"].Value: {
+	// schemaRef.Value.Properties.Value("ID").Value: {
 	//   "type": "string"
 	// }
 }
@@ -308,12 +308,8 @@ func TestEmbeddedPointerStructs(t *testing.T) {
 	schemaRef, err := generator.GenerateSchemaRef(reflect.TypeOf(instance))
 	require.NoError(t, err)
 
-	var ok bool
-	_, ok = schemaRef.Value.Properties["Name"]
-	require.Equal(t, true, ok)
-
-	_, ok = schemaRef.Value.Properties["ID"]
-	require.Equal(t, true, ok)
+	require.NotNil(t, schemaRef.Value.Properties.Value("Name"))
+	require.NotNil(t, schemaRef.Value.Properties.Value("ID"))
 }
 
 // See: https://github.com/getkin/kin-openapi/issues/500
@@ -344,12 +340,8 @@ func TestEmbeddedPointerStructsWithSchemaCustomizer(t *testing.T) {
 	schemaRef, err := generator.GenerateSchemaRef(reflect.TypeOf(instance))
 	require.NoError(t, err)
 
-	var ok bool
-	_, ok = schemaRef.Value.Properties["Name"]
-	require.Equal(t, true, ok)
-
-	_, ok = schemaRef.Value.Properties["ID"]
-	require.Equal(t, true, ok)
+	require.NotNil(t, schemaRef.Value.Properties.Value("Name"))
+	require.NotNil(t, schemaRef.Value.Properties.Value("ID"))
 }
 
 func TestCyclicReferences(t *testing.T) {
@@ -370,16 +362,60 @@ func TestCyclicReferences(t *testing.T) {
 	schemaRef, err := generator.GenerateSchemaRef(reflect.TypeOf(instance))
 	require.NoError(t, err)
 
-	require.NotNil(t, schemaRef.Value.Properties["FieldCycle"])
-	require.Equal(t, "#/components/schemas/ObjectDiff", schemaRef.Value.Properties["FieldCycle"].Ref)
+	require.NotNil(t, schemaRef.Value.Properties.Value("FieldCycle"))
+	require.Equal(t, "#/components/schemas/ObjectDiff", schemaRef.Value.Properties.Value("FieldCycle").Ref)
 
-	require.NotNil(t, schemaRef.Value.Properties["SliceCycle"])
-	require.Equal(t, "array", schemaRef.Value.Properties["SliceCycle"].Value.Type)
-	require.Equal(t, "#/components/schemas/ObjectDiff", schemaRef.Value.Properties["SliceCycle"].Value.Items.Ref)
+	require.NotNil(t, schemaRef.Value.Properties.Value("SliceCycle"))
+	require.Equal(t, "array", schemaRef.Value.Properties.Value("SliceCycle").Value.Type)
+	require.Equal(t, "#/components/schemas/ObjectDiff", schemaRef.Value.Properties.Value("SliceCycle").Value.Items.Ref)
+
+	require.NotNil(t, schemaRef.Value.Properties.Value("MapCycle"))
+	require.Equal(t, "object", schemaRef.Value.Properties.Value("MapCycle").Value.Type)
+	require.Equal(t, "#/components/schemas/ObjectDiff", schemaRef.Value.Properties.Value("MapCycle").Value.AdditionalProperties.Ref)
+}
 
-	require.NotNil(t, schemaRef.Value.Properties["MapCycle"])
-	require.Equal(t, "object", schemaRef.Value.Properties["MapCycle"].Value.Type)
-	require.Equal(t, "#/components/schemas/ObjectDiff", schemaRef.Value.Properties["MapCycle"].Value.AdditionalProperties.Ref)
+type mutualA struct {
+	Name string   `json:"name"`
+	B    *mutualB `json:"b"`
+}
+
+type mutualB struct {
+	Count int       `json:"count"`
+	As    []mutualA `json:"as"`
+}
+
+func TestMutuallyRecursiveTypes(t *testing.T) {
+	g := openapi3gen.NewGenerator()
+	schemaRef, err := g.GenerateSchemaRef(reflect.TypeOf(mutualA{}))
+	require.NoError(t, err)
+	// The cycle reference describes the type it points to, not the enclosing one
+	cycle := schemaRef.Value.Properties.Value("b").Value.Properties.Value("as").Value.Items
+	require.Equal(t, "#/components/schemas/mutualA", cycle.Ref)
+	require.Same(t, schemaRef.Value, cycle.Value)
+
+	schemas := openapi3.NewSchemas()
+	schemaRef, err = openapi3gen.NewSchemaRefForValue(&mutualA{}, schemas, openapi3gen.RefRecursiveRoot())
+	require.NoError(t, err)
+	require.Equal(t, "#/components/schemas/mutualA", schemaRef.Ref)
+	require.Nil(t, schemaRef.Value)
+
+	data, err := json.Marshal(schemas)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"mutualA": {
+			"type": "object",
+			"properties": {
+				"name": {"type": "string"},
+				"b": {
+					"type": "object",
+					"properties": {
+						"count": {"type": "integer"},
+						"as": {"type": "array", "items": {"$ref": "#/components/schemas/mutualA"}}
+					}
+				}
+			}
+		}
+	}`, string(data))
 }
 
 func ExampleSchemaCustomizer() {
@@ -502,9 +538,8 @@ func TestSchemaCustomizerExcludeSchema(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, &openapi3.SchemaRef{Value: &openapi3.Schema{
 		Type: "object",
-		Properties: map[string]*openapi3.SchemaRef{
-			"Str": {Value: &openapi3.Schema{Type: "string"}},
-		}}}, schema)
+		Properties: openapi3.NewSchemas().
+			With("Str", &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string"}})}}, schema)
 
 	customizer = openapi3gen.SchemaCustomizer(func(name string, ft reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
 		return &openapi3gen.ExcludeSchemaSentinel{}
@@ -522,7 +557,7 @@ func ExampleNewSchemaRefForValue_recursive() {
 		Components []*RecursiveType `json:"children,omitempty"`
 	}
 
-	schemas := make(openapi3.Schemas)
+	schemas := openapi3.NewSchemas()
 	schemaRef, err := openapi3gen.NewSchemaRefForValue(&RecursiveType{}, schemas)
 	if err != nil {
 		panic(err)
@@ -581,3 +616,167 @@ func ExampleNewSchemaRefForValue_recursive() {
 	//   "type": "object"
 	// }
 }
+
+type validationNode struct {
+	Name   string          `json:"name"`
+	Parent *validationNode `json:"parent" openapi:"description=The parent"`
+}
+
+func TestUseValidationTags(t *testing.T) {
+	type Pet struct {
+		Name    string   `json:"name" validate:"required,min=1,max=20"`
+		Kind    string   `json:"kind" validate:"oneof=cat dog"`
+		Age     int      `json:"age" validate:"gte=0,lt=30"`
+		Email   string   `json:"email,omitempty" validate:"omitempty,email"`
+		Code    string   `json:"code" pattern:"^[A-Z]{2},[0-9]+$" minLength:"3"`
+		Tags    []string `json:"tags" validate:"max=5,dive,min=1"`
+		Weight  *float64 `json:"weight" openapi:"required,minimum=0.1,exclusiveMinimum,multipleOf=0.1"`
+		Size    int      `json:"size" openapi:"enum=1|2|3,readOnly"`
+		Comment string   `json:"comment"`
+	}
+
+	schemaRef, err := openapi3gen.NewSchemaRefForValue(&Pet{}, nil, openapi3gen.UseValidationTags())
+	require.NoError(t, err)
+	data, err := json.Marshal(schemaRef)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+{
+  "type": "object",
+  "required": ["name", "weight"],
+  "properties": {
+    "name": {"type": "string", "minLength": 1, "maxLength": 20},
+    "kind": {"type": "string", "enum": ["cat", "dog"]},
+    "age": {"type": "integer", "minimum": 0, "maximum": 30, "exclusiveMaximum": true},
+    "email": {"type": "string", "format": "email"},
+    "code": {"type": "string", "pattern": "^[A-Z]{2},[0-9]+$", "minLength": 3},
+    "tags": {"type": "array", "items": {"type": "string"}, "maxItems": 5},
+    "weight": {"type": "number", "format": "double", "minimum": 0.1, "exclusiveMinimum": true, "multipleOf": 0.1},
+    "size": {"type": "integer", "enum": [1, 2, 3], "readOnly": true},
+    "comment": {"type": "string"}
+  }
+}`, string(data))
+
+	// Constraints on references are added next to them
+	schemas := openapi3.NewSchemas()
+	schemaRef, err = openapi3gen.NewSchemaRefForValue(&validationNode{}, schemas, openapi3gen.UseValidationTags())
+	require.NoError(t, err)
+	data, err = json.Marshal(schemaRef)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+{
+  "type": "object",
+  "properties": {
+    "name": {"type": "string"},
+    "parent": {"allOf": [{"$ref": "#/components/schemas/validationNode"}, {"description": "The parent"}]}
+  }
+}`, string(data))
+	require.Equal(t, []string{"validationNode"}, schemas.InSortedOrder())
+
+	type Bad struct {
+		Field int `json:"field" openapi:"minimum=abc"`
+	}
+	_, err = openapi3gen.NewSchemaRefForValue(&Bad{}, nil, openapi3gen.UseValidationTags())
+	require.EqualError(t, err, `field field: openapi tag "minimum=abc": invalid number "abc"`)
+}
+
+type testUUID [16]byte
+
+func (u testUUID) MarshalText() ([]byte, error) {
+	return []byte("00000000-0000-0000-0000-000000000000"), nil
+}
+
+type testDecimal struct {
+	value int64
+	exp   int32
+}
+
+func (d *testDecimal) MarshalJSON() ([]byte, error) { return []byte(`"0"`), nil }
+
+type testLevel int
+
+func (l testLevel) MarshalText() ([]byte, error) { return []byte("info"), nil }
+
+type testPoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func (p testPoint) MarshalJSON() ([]byte, error) { return []byte(`{"x":0,"y":0}`), nil }
+
+type testRatio struct {
+	num, denom int64
+}
+
+func (r testRatio) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprint(float64(r.num) / float64(r.denom+1))), nil
+}
+
+type testPanicky struct {
+	value *int
+}
+
+func (p testPanicky) MarshalJSON() ([]byte, error) { return []byte(fmt.Sprint(*p.value)), nil }
+
+func TestMarshalers(t *testing.T) {
+	type Entry struct {
+		ID     testUUID     `json:"id"`
+		Amount testDecimal  `json:"amount"`
+		Fee    *testDecimal `json:"fee"`
+		Level  testLevel    `json:"level"`
+		Time   time.Time    `json:"time"`
+		Point  testPoint    `json:"point"`
+		Total  *big.Int     `json:"total"`
+	}
+
+	schemaRef, err := openapi3gen.NewSchemaRefForValue(&Entry{}, nil,
+		openapi3gen.MarshalerFormat(testUUID{}, "uuid"),
+		openapi3gen.MarshalerFormat(&testDecimal{}, "decimal"),
+		openapi3gen.SchemaCustomizer(func(name string, ft reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+			if ft == reflect.TypeOf(big.Int{}) {
+				*schema = *openapi3.NewIntegerSchema()
+			}
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	data, err := json.Marshal(schemaRef)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+{
+  "type": "object",
+  "properties": {
+    "id": {"type": "string", "format": "uuid"},
+    "amount": {"type": "string", "format": "decimal"},
+    "fee": {"type": "string", "format": "decimal"},
+    "level": {"type": "string"},
+    "time": {"type": "string", "format": "date-time"},
+    "point": {"type": "object", "properties": {"x": {"type": "integer"}, "y": {"type": "integer"}}},
+    "total": {"type": "integer"}
+  }
+}`, string(data))
+
+	// The kind of json.Marshaler types is that of the encoding of their zero value,
+	// any value if it cannot be encoded
+	type Inferred struct {
+		Amount testDecimal `json:"amount"`
+		Ratio  testRatio   `json:"ratio"`
+		Point  testPoint   `json:"point"`
+		Total  big.Int     `json:"total"`
+		Broken testPanicky `json:"broken"`
+	}
+	schemaRef, err = openapi3gen.NewSchemaRefForValue(&Inferred{}, nil)
+	require.NoError(t, err)
+	data, err = json.Marshal(schemaRef)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+{
+  "type": "object",
+  "properties": {
+    "amount": {"type": "string"},
+    "ratio": {"type": "number"},
+    "point": {"type": "object", "properties": {"x": {"type": "integer"}, "y": {"type": "integer"}}},
+    "total": {"type": "number"},
+    "broken": {}
+  }
+}`, string(data))
+}
@@ -0,0 +1,357 @@
+package openapi3gen
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// UseValidationTags makes the generator read constraints from struct field tags:
+//   - `validate:"..."` tags as used by github.com/go-playground/validator:
+//     required, min, max, len, gt, gte, lt, lte, oneof and the
+//     email, url, uri, uuid, hostname, ipv4, ipv6 and base64 formats.
+//     Rules after "dive" apply to elements and are ignored.
+//   - `minLength`, `maxLength`, `pattern`, `format`, `minimum`, `maximum`,
+//     `enum` (comma-separated) and `description` tags.
+//   - `openapi:"..."` tags, a comma-separated list of schema keywords,
+//     as flags (required, nullable, readOnly, writeOnly, deprecated, uniqueItems,
+//     exclusiveMinimum, exclusiveMaximum) or key=value pairs (format, pattern,
+//     minLength, maxLength, minimum, maximum, multipleOf, minItems, maxItems,
+//     enum with values separated by "|", description).
+//     Values cannot contain commas: use the standalone tags for such patterns.
+func UseValidationTags() Option {
+	return func(x *generatorOpt) { x.useValidationTags = true }
+}
+
+// tagConstraints collects the constraints of a struct field.
+type tagConstraints struct {
+	required bool
+	apply    []func(schema *openapi3.Schema) error
+}
+
+var validateFormats = map[string]string{
+	"base64":   "byte",
+	"email":    "email",
+	"hostname": "hostname",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"uri":      "uri",
+	"url":      "uri",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+}
+
+func parseTagConstraints(t reflect.Type, tag reflect.StructTag) (*tagConstraints, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	c := &tagConstraints{}
+
+	if v, ok := tag.Lookup("validate"); ok {
+	rules:
+		for _, rule := range strings.Split(v, ",") {
+			key, value := splitRule(rule, "=")
+			switch key {
+			case "dive":
+				break rules
+			case "required":
+				c.required = true
+			case "min", "gte":
+				if err := c.addBound(t, key, value, boundMin, false); err != nil {
+					return nil, err
+				}
+			case "max", "lte":
+				if err := c.addBound(t, key, value, boundMax, false); err != nil {
+					return nil, err
+				}
+			case "gt":
+				if err := c.addBound(t, key, value, boundMin, true); err != nil {
+					return nil, err
+				}
+			case "lt":
+				if err := c.addBound(t, key, value, boundMax, true); err != nil {
+					return nil, err
+				}
+			case "len":
+				if err := c.addBound(t, key, value, boundMin, false); err != nil {
+					return nil, err
+				}
+				if err := c.addBound(t, key, value, boundMax, false); err != nil {
+					return nil, err
+				}
+			case "oneof":
+				enum, err := parseEnum(t, strings.Fields(value))
+				if err != nil {
+					return nil, fmt.Errorf("validate tag %q: %w", rule, err)
+				}
+				c.add(func(schema *openapi3.Schema) error { schema.Enum = enum; return nil })
+			default:
+				if format, ok := validateFormats[key]; ok {
+					c.add(func(schema *openapi3.Schema) error { schema.Format = format; return nil })
+				}
+			}
+		}
+	}
+
+	for _, key := range []string{"minLength", "maxLength", "pattern", "format", "minimum", "maximum", "enum", "description"} {
+		if value, ok := tag.Lookup(key); ok {
+			var err error
+			if key == "enum" {
+				err = c.addKeyword(t, key, strings.Replace(value, ",", "|", -1))
+			} else {
+				err = c.addKeyword(t, key, value)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s tag: %w", key, err)
+			}
+		}
+	}
+
+	if v, ok := tag.Lookup("openapi"); ok {
+		for _, rule := range strings.Split(v, ",") {
+			key, value := splitRule(rule, "=")
+			if key == "required" {
+				c.required = true
+				continue
+			}
+			if err := c.addKeyword(t, key, value); err != nil {
+				return nil, fmt.Errorf("openapi tag %q: %w", rule, err)
+			}
+		}
+	}
+	return c, nil
+}
+
+func splitRule(rule, sep string) (string, string) {
+	kv := strings.SplitN(strings.TrimSpace(rule), sep, 2)
+	if len(kv) == 1 {
+		return kv[0], ""
+	}
+	return kv[0], kv[1]
+}
+
+func (c *tagConstraints) add(f func(schema *openapi3.Schema) error) {
+	c.apply = append(c.apply, f)
+}
+
+type boundKind int
+
+const (
+	boundMin boundKind = iota
+	boundMax
+)
+
+// addBound adds a validator-style bound, which applies to the length of strings,
+// the item count of arrays, the property count of maps and the value of numbers.
+func (c *tagConstraints) addBound(t reflect.Type, key, value string, kind boundKind, exclusive bool) error {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("validate tag %q: invalid number %q", key, value)
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		n := uint64(f)
+		if exclusive {
+			if kind == boundMin {
+				n++
+			} else if n > 0 {
+				n--
+			}
+		}
+		c.add(func(schema *openapi3.Schema) error {
+			switch {
+			case schema.Type == "string" && kind == boundMin:
+				schema.MinLength = n
+			case schema.Type == "string":
+				schema.MaxLength = &n
+			case schema.Type == "array" && kind == boundMin:
+				schema.MinItems = n
+			case schema.Type == "array":
+				schema.MaxItems = &n
+			case kind == boundMin:
+				schema.MinProps = n
+			default:
+				schema.MaxProps = &n
+			}
+			return nil
+		})
+	default:
+		c.add(func(schema *openapi3.Schema) error {
+			if kind == boundMin {
+				schema.Min, schema.ExclusiveMin = &f, exclusive
+			} else {
+				schema.Max, schema.ExclusiveMax = &f, exclusive
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+// addKeyword adds a constraint named after a schema keyword.
+func (c *tagConstraints) addKeyword(t reflect.Type, key, value string) error {
+	parseUint := func() (uint64, error) {
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid integer %q", value)
+		}
+		return n, nil
+	}
+	parseFloat := func() (float64, error) {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", value)
+		}
+		return f, nil
+	}
+
+	switch key {
+	case "nullable":
+		c.add(func(schema *openapi3.Schema) error { schema.Nullable = true; return nil })
+	case "readOnly":
+		c.add(func(schema *openapi3.Schema) error { schema.ReadOnly = true; return nil })
+	case "writeOnly":
+		c.add(func(schema *openapi3.Schema) error { schema.WriteOnly = true; return nil })
+	case "deprecated":
+		c.add(func(schema *openapi3.Schema) error { schema.Deprecated = true; return nil })
+	case "uniqueItems":
+		c.add(func(schema *openapi3.Schema) error { schema.UniqueItems = true; return nil })
+	case "exclusiveMinimum":
+		c.add(func(schema *openapi3.Schema) error { schema.ExclusiveMin = true; return nil })
+	case "exclusiveMaximum":
+		c.add(func(schema *openapi3.Schema) error { schema.ExclusiveMax = true; return nil })
+	case "format":
+		c.add(func(schema *openapi3.Schema) error { schema.Format = value; return nil })
+	case "pattern":
+		c.add(func(schema *openapi3.Schema) error { schema.Pattern = value; return nil })
+	case "description":
+		c.add(func(schema *openapi3.Schema) error { schema.Description = value; return nil })
+	case "minLength", "minItems":
+		n, err := parseUint()
+		if err != nil {
+			return err
+		}
+		c.add(func(schema *openapi3.Schema) error {
+			if key == "minLength" {
+				schema.MinLength = n
+			} else {
+				schema.MinItems = n
+			}
+			return nil
+		})
+	case "maxLength", "maxItems":
+		n, err := parseUint()
+		if err != nil {
+			return err
+		}
+		c.add(func(schema *openapi3.Schema) error {
+			if key == "maxLength" {
+				schema.MaxLength = &n
+			} else {
+				schema.MaxItems = &n
+			}
+			return nil
+		})
+	case "minimum", "maximum", "multipleOf":
+		f, err := parseFloat()
+		if err != nil {
+			return err
+		}
+		c.add(func(schema *openapi3.Schema) error {
+			switch key {
+			case "minimum":
+				schema.Min = &f
+			case "maximum":
+				schema.Max = &f
+			default:
+				schema.MultipleOf = &f
+			}
+			return nil
+		})
+	case "enum":
+		enum, err := parseEnum(t, strings.Split(value, "|"))
+		if err != nil {
+			return err
+		}
+		c.add(func(schema *openapi3.Schema) error { schema.Enum = enum; return nil })
+	case "":
+	default:
+		return fmt.Errorf("unsupported keyword %q", key)
+	}
+	return nil
+}
+
+// parseEnum converts enum values to the JSON type matching t.
+func parseEnum(t reflect.Type, values []string) ([]interface{}, error) {
+	enum := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		switch t.Kind() {
+		case reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid boolean %q", value)
+			}
+			enum = append(enum, b)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", value)
+			}
+			enum = append(enum, f)
+		default:
+			enum = append(enum, value)
+		}
+	}
+	return enum, nil
+}
+
+// applyTagConstraints returns a schema for a struct field with the constraints
+// read from its tag. The schema generated for the field type is copied so that
+// other uses of the type are left unconstrained, unless it is or may become a reference
+// to a component schema (a named struct type): the constraints are then added
+// next to it as allOf: [reference, constraints].
+func (g *Generator) applyTagConstraints(ref *openapi3.SchemaRef, t reflect.Type, tag reflect.StructTag) (*openapi3.SchemaRef, bool, error) {
+	c, err := parseTagConstraints(t, tag)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(c.apply) == 0 {
+		return ref, c.required, nil
+	}
+
+	t = derefType(t)
+	if strings.HasPrefix(ref.Ref, "#/components/schemas/") || ref.Value == nil ||
+		(t.Kind() == reflect.Struct && t.Name() != "" && t != timeType) {
+		constraints := &openapi3.Schema{Type: "object"}
+		if ref.Value != nil && ref.Value.Type != "" {
+			constraints.Type = ref.Value.Type
+		}
+		for _, apply := range c.apply {
+			if err := apply(constraints); err != nil {
+				return nil, false, err
+			}
+		}
+		// The type is that of the reference
+		constraints.Type = ""
+		if strings.HasPrefix(ref.Ref, "#/components/schemas/") {
+			// Unlike other schemas, references to cycles are only counted by their users
+			g.SchemaRefs[ref]++
+		}
+		return openapi3.NewSchemaRef("", &openapi3.Schema{
+			AllOf: openapi3.SchemaRefs{ref, openapi3.NewSchemaRef("", constraints)},
+		}), c.required, nil
+	}
+
+	schema := *ref.Value
+	for _, apply := range c.apply {
+		if err := apply(&schema); err != nil {
+			return nil, false, err
+		}
+	}
+	return openapi3.NewSchemaRef("", &schema), c.required, nil
+}
@@ -0,0 +1,107 @@
+package openapi3gen
+
+import (
+	"reflect"
+	"strings"
+)
+
+// typeName returns the name under which t is described in the components schemas.
+//
+// Instantiated generic types are named after their type arguments,
+// with package paths, pointers and separators left out, so that names are stable
+// and valid component names:
+// Page[github.com/org/pets.Pet] is named "Page_Pet",
+// Pair[string,*pets.Pet] is named "Pair_string_Pet"
+// and Page[[]pets.Pet] is named "Page_List_Pet".
+func typeName(t reflect.Type) string {
+	return genericTypeName(t.Name())
+}
+
+func genericTypeName(name string) string {
+	i := strings.IndexByte(name, '[')
+	if i < 0 || !strings.HasSuffix(name, "]") {
+		return sanitizeTypeName(name)
+	}
+	parts := []string{sanitizeTypeName(name[:i])}
+	for _, arg := range splitTypeArguments(name[i+1 : len(name)-1]) {
+		parts = append(parts, typeArgumentName(strings.TrimSpace(arg)))
+	}
+	return strings.Join(parts, "_")
+}
+
+func typeArgumentName(arg string) string {
+	switch {
+	case strings.HasPrefix(arg, "*"):
+		return typeArgumentName(arg[1:])
+	case strings.HasPrefix(arg, "map["):
+		if end := closingBracket(arg, len("map[")-1); end > 0 {
+			return "Map_" + typeArgumentName(arg[len("map["):end]) + "_" + typeArgumentName(arg[end+1:])
+		}
+	case strings.HasPrefix(arg, "["):
+		// Slice or array
+		if end := strings.IndexByte(arg, ']'); end > 0 {
+			return "List_" + typeArgumentName(arg[end+1:])
+		}
+	}
+
+	// Qualified identifier, itself possibly generic
+	base, args := arg, ""
+	if i := strings.IndexByte(arg, '['); i >= 0 {
+		base, args = arg[:i], arg[i:]
+	}
+	if i := strings.LastIndexByte(base, '/'); i >= 0 {
+		base = base[i+1:]
+	}
+	if i := strings.LastIndexByte(base, '.'); i >= 0 {
+		base = base[i+1:]
+	}
+	return genericTypeName(base + args)
+}
+
+// splitTypeArguments splits a comma-separated list of type arguments,
+// ignoring commas nested in brackets.
+func splitTypeArguments(s string) []string {
+	var args []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(args, s[start:])
+}
+
+// closingBracket returns the index of the bracket closing the one at index open, or -1.
+func closingBracket(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// sanitizeTypeName drops the characters not allowed in component names.
+func sanitizeTypeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9', r == '_', r == '.', r == '-':
+			return r
+		}
+		return -1
+	}, name)
+}
@@ -0,0 +1,29 @@
+package openapi3gen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenericTypeName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Pet":                                      "Pet",
+		"Page[github.com/org/pets.Pet]":            "Page_Pet",
+		"Page[*github.com/org/pets.Pet]":           "Page_Pet",
+		"Page[[]github.com/org/pets.Pet]":          "Page_List_Pet",
+		"Page[[4]int]":                             "Page_List_int",
+		"Pair[string,github.com/org/pets.Pet]":     "Pair_string_Pet",
+		"Pair[string, int]":                        "Pair_string_int",
+		"Page[map[string]github.com/org/pets.Pet]": "Page_Map_string_Pet",
+		"Page[github.com/org/api.Pair[string,github.com/org/pets.Pet]]": "Page_Pair_string_Pet",
+		"Page[map[github.com/org/pets.ID][]int]":                        "Page_Map_ID_List_int",
+		"Page[interface {}]":                                            "Page_interface",
+		"Page[gopkg.in/yaml.v3.Node]":                                   "Page_Node",
+	} {
+		require.Equal(t, expected, genericTypeName(name), name)
+	}
+
+	require.Equal(t, "Generator", typeName(reflect.TypeOf(Generator{})))
+}
@@ -0,0 +1,20 @@
+// Package openapi3graphql exports the component schemas of an OpenAPIv3 document,
+// and optionally its operations, as a GraphQL schema definition (SDL) skeleton:
+//
+//	data, err := openapi3graphql.Generate(doc, openapi3graphql.WithOperations())
+//
+// Object schemas become object types, with the properties of their allOf subschemas merged in,
+// string enums become enums, and oneOf and anyOf of object schemas become unions.
+// Required properties which are not nullable are non-null fields.
+// Inline object and enum schemas are declared after their parents, e.g. PetOwner
+// for the owner property of Pet. Schemas without a GraphQL equivalent, such as free-form
+// objects or unions of scalars, are mapped to a JSON scalar.
+//
+// With WithOperations, GET operations become fields of Query and other operations
+// fields of Mutation, named after their operationId. Parameters become arguments
+// and request bodies an input argument, whose objects are declared as input types
+// suffixed with Input. Fields return the JSON content of the first 2XX response, if any,
+// or Boolean.
+//
+// Declarations are sorted by name, so that the output is stable.
+package openapi3graphql
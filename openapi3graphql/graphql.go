@@ -0,0 +1,483 @@
+package openapi3graphql
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const componentSchemasPrefix = "#/components/schemas/"
+
+// jsonScalar is the scalar of values without a GraphQL equivalent.
+const jsonScalar = "JSON"
+
+// Option allows tweaking the generation
+type Option func(*generator)
+
+// WithOperations also exports the operations of the document as fields of Query and Mutation.
+func WithOperations() Option {
+	return func(g *generator) {
+		g.operations = true
+	}
+}
+
+type kind int
+
+const (
+	kindObject kind = iota + 1
+	kindInput
+	kindEnum
+	kindUnion
+	kindScalar
+)
+
+type generator struct {
+	operations bool
+
+	doc *openapi3.T
+	// kinds are the kinds of the declared types, by name
+	kinds map[string]kind
+	// declarations are the SDL of the declared types, by name
+	declarations map[string]string
+	// pending are the component schemas being declared, by name
+	pending map[string]bool
+}
+
+// Generate returns the GraphQL schema definition of the component schemas of doc.
+func Generate(doc *openapi3.T, opts ...Option) ([]byte, error) {
+	g := &generator{
+		doc:          doc,
+		kinds:        make(map[string]kind),
+		declarations: make(map[string]string),
+		pending:      make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	for _, name := range doc.Components.Schemas.InSortedOrder() {
+		ref := doc.Components.Schemas.Value(name)
+		if ref == nil || ref.Value == nil {
+			return nil, fmt.Errorf("schema %q is not resolved", name)
+		}
+		g.typeOf(openapi3.NewSchemaRef(componentSchemasPrefix+name, ref.Value), "", false)
+	}
+
+	var buf strings.Builder
+	buf.WriteString("# Code generated by kin-openapi")
+	if info := doc.Info; info != nil && info.Title != "" {
+		fmt.Fprintf(&buf, " from %s %s", info.Title, info.Version)
+	}
+	buf.WriteString(". DO NOT EDIT.\n")
+	if g.operations {
+		query, mutation, err := g.operationFields()
+		if err != nil {
+			return nil, err
+		}
+		for _, root := range []struct {
+			name   string
+			fields []string
+		}{{"Query", query}, {"Mutation", mutation}} {
+			if len(root.fields) != 0 {
+				fmt.Fprintf(&buf, "\ntype %s {\n%s}\n", root.name, strings.Join(root.fields, ""))
+			}
+		}
+	}
+	names := make([]string, 0, len(g.declarations))
+	for name := range g.declarations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		buf.WriteByte('\n')
+		buf.WriteString(g.declarations[name])
+	}
+	return []byte(buf.String()), nil
+}
+
+// TypeName returns the GraphQL name of the component schema of the given name:
+// the name with the characters not allowed in GraphQL names replaced with underscores.
+func TypeName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z'):
+		case '0' <= r && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+		default:
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+// pascalCase returns s in PascalCase, without the characters not allowed in GraphQL names.
+func pascalCase(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// enumValue returns the GraphQL enum value of value, in UPPER_SNAKE_CASE.
+func enumValue(value string) string {
+	var b strings.Builder
+	runes := []rune(value)
+	for i, r := range runes {
+		switch {
+		case r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if b.Len() != 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteByte('_')
+			}
+			continue
+		case unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]) && !strings.HasSuffix(b.String(), "_"):
+			// camelCase words
+			b.WriteByte('_')
+		case b.Len() == 0 && unicode.IsDigit(r):
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	if s := strings.TrimSuffix(b.String(), "_"); s != "" {
+		return s
+	}
+	return "EMPTY"
+}
+
+// typeOf returns the GraphQL type of the values of ref, without non-null marker,
+// declaring the types it needs. Inline types are named after name.
+func (g *generator) typeOf(ref *openapi3.SchemaRef, name string, input bool) string {
+	if ref == nil || ref.Value == nil {
+		return g.json()
+	}
+	if strings.HasPrefix(ref.Ref, componentSchemasPrefix) {
+		component := strings.TrimPrefix(ref.Ref, componentSchemasPrefix)
+		name = TypeName(component)
+		key := name
+		if input {
+			key += "Input"
+		}
+		if g.pending[key] {
+			// A recursive schema
+			if _, ok := g.kinds[key]; ok {
+				return key
+			}
+			if _, ok := g.kinds[name]; ok {
+				return name
+			}
+			return g.json()
+		}
+		g.pending[key] = true
+		defer delete(g.pending, key)
+	}
+	return g.schemaType(ref.Value, name, input)
+}
+
+func (g *generator) schemaType(schema *openapi3.Schema, name string, input bool) string {
+	if len(schema.Enum) != 0 {
+		return g.enum(schema, name)
+	}
+	if len(schema.OneOf) != 0 || len(schema.AnyOf) != 0 {
+		if input {
+			return g.json()
+		}
+		return g.union(schema, name)
+	}
+	switch schema.Type {
+	case "string":
+		if schema.Format == "uuid" {
+			return "ID"
+		}
+		return "String"
+	case "integer":
+		return "Int"
+	case "number":
+		return "Float"
+	case "boolean":
+		return "Boolean"
+	case "array":
+		item := g.typeOf(schema.Items, name+"Item", input)
+		if schema.Items != nil && schema.Items.Value != nil && !schema.Items.Value.Nullable {
+			item += "!"
+		}
+		return "[" + item + "]"
+	}
+	if len(schema.AllOf) != 0 || schema.Properties.Len() != 0 {
+		return g.object(schema, name, input)
+	}
+	return g.json()
+}
+
+func (g *generator) json() string {
+	g.declare(jsonScalar, kindScalar, "scalar JSON\n")
+	return jsonScalar
+}
+
+func (g *generator) declare(name string, k kind, declaration string) {
+	g.kinds[name] = k
+	g.declarations[name] = declaration
+}
+
+func (g *generator) enum(schema *openapi3.Schema, name string) string {
+	if k, ok := g.kinds[name]; ok && k == kindEnum {
+		return name
+	}
+	var b strings.Builder
+	b.WriteString(description(schema.Description, ""))
+	fmt.Fprintf(&b, "enum %s {\n", name)
+	used := make(map[string]bool)
+	for _, value := range schema.Enum {
+		s, ok := value.(string)
+		if !ok {
+			// Enums of other values have no GraphQL equivalent
+			if value == nil {
+				continue
+			}
+			return g.json()
+		}
+		v := enumValue(s)
+		for i := 2; used[v]; i++ {
+			v = fmt.Sprintf("%s_%d", enumValue(s), i)
+		}
+		used[v] = true
+		fmt.Fprintf(&b, "  %s\n", v)
+	}
+	b.WriteString("}\n")
+	g.declare(name, kindEnum, b.String())
+	return name
+}
+
+func (g *generator) union(schema *openapi3.Schema, name string) string {
+	refs := schema.OneOf
+	if len(refs) == 0 {
+		refs = schema.AnyOf
+	}
+	// Declare the union first, should its members refer to it
+	g.kinds[name] = kindUnion
+	members := make([]string, 0, len(refs))
+	for i, ref := range refs {
+		member := g.typeOf(ref, fmt.Sprintf("%sOption%d", name, i+1), false)
+		if g.kinds[member] != kindObject {
+			// Unions are made of object types only
+			delete(g.kinds, name)
+			return g.json()
+		}
+		members = append(members, member)
+	}
+	g.declare(name, kindUnion, description(schema.Description, "")+fmt.Sprintf("union %s = %s\n", name, strings.Join(members, " | ")))
+	return name
+}
+
+type field struct {
+	schema   *openapi3.SchemaRef
+	required bool
+}
+
+// collectFields adds the properties of schema and of its allOf subschemas to fields.
+func collectFields(schema *openapi3.Schema, fields map[string]*field, depth int) {
+	if depth > 32 {
+		return
+	}
+	for _, ref := range schema.AllOf {
+		if ref != nil && ref.Value != nil {
+			collectFields(ref.Value, fields, depth+1)
+		}
+	}
+	for name, ref := range schema.Properties.Map() {
+		if f, ok := fields[name]; ok {
+			f.schema = ref
+		} else {
+			fields[name] = &field{schema: ref}
+		}
+	}
+	for _, name := range schema.Required {
+		if f, ok := fields[name]; ok {
+			f.required = true
+		}
+	}
+}
+
+func (g *generator) object(schema *openapi3.Schema, name string, input bool) string {
+	k := kindObject
+	if input {
+		k = kindInput
+		name += "Input"
+	}
+	if existing, ok := g.kinds[name]; ok {
+		if existing == k {
+			return name
+		}
+		name += "Object"
+	}
+	fields := make(map[string]*field)
+	collectFields(schema, fields, 0)
+	if len(fields) == 0 {
+		return g.json()
+	}
+	// Declare the type first, should its fields refer to it
+	g.kinds[name] = k
+
+	base := strings.TrimSuffix(name, "Input")
+	var b strings.Builder
+	b.WriteString(description(schema.Description, ""))
+	keyword := "type"
+	if input {
+		keyword = "input"
+	}
+	fmt.Fprintf(&b, "%s %s {\n", keyword, name)
+	properties := make([]string, 0, len(fields))
+	for property := range fields {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+	for _, property := range properties {
+		f := fields[property]
+		t := g.typeOf(f.schema, base+pascalCase(property), input)
+		if f.required && f.schema != nil && f.schema.Value != nil && !f.schema.Value.Nullable {
+			t += "!"
+		}
+		if f.schema != nil && f.schema.Value != nil {
+			b.WriteString(description(f.schema.Value.Description, "  "))
+		}
+		fmt.Fprintf(&b, "  %s: %s", TypeName(property), t)
+		if f.schema != nil && f.schema.Value != nil && f.schema.Value.Deprecated && !input {
+			b.WriteString(" @deprecated")
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteString("}\n")
+	g.declare(name, k, b.String())
+	return name
+}
+
+// operationFields returns the fields of Query and Mutation for the operations of the document.
+func (g *generator) operationFields() (query, mutation []string, err error) {
+	for _, path := range g.doc.Paths.InSortedOrder() {
+		pathItem := g.doc.Paths.Value(path)
+		if pathItem == nil {
+			continue
+		}
+		for _, method := range pathItem.Methods() {
+			operation := pathItem.GetOperation(method)
+			if operation.OperationID == "" {
+				return nil, nil, fmt.Errorf("operation %s %s has no operationId", method, path)
+			}
+			f := g.operationField(pathItem, operation)
+			if method == http.MethodGet {
+				query = append(query, f)
+			} else {
+				mutation = append(mutation, f)
+			}
+		}
+	}
+	return query, mutation, nil
+}
+
+func (g *generator) operationField(pathItem *openapi3.PathItem, operation *openapi3.Operation) string {
+	base := pascalCase(operation.OperationID)
+
+	var args []string
+	parameters := make(map[string]*openapi3.Parameter)
+	for _, refs := range []openapi3.Parameters{pathItem.Parameters, operation.Parameters} {
+		for _, ref := range refs {
+			if ref != nil && ref.Value != nil {
+				parameters[ref.Value.In+" "+ref.Value.Name] = ref.Value
+			}
+		}
+	}
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parameter := parameters[key]
+		t := g.typeOf(parameter.Schema, base+pascalCase(parameter.Name), true)
+		if parameter.Required {
+			t += "!"
+		}
+		args = append(args, fmt.Sprintf("%s: %s", TypeName(parameter.Name), t))
+	}
+	if ref := operation.RequestBody; ref != nil && ref.Value != nil {
+		if mediaType := ref.Value.GetMediaType("application/json"); mediaType != nil {
+			t := g.typeOf(mediaType.Schema, base+"Body", true)
+			if ref.Value.Required {
+				t += "!"
+			}
+			args = append(args, "input: "+t)
+		}
+	}
+
+	result := "Boolean"
+	for _, code := range operation.Responses.InSortedOrder() {
+		ref := operation.Responses.Value(code)
+		if !strings.HasPrefix(code, "2") || ref == nil || ref.Value == nil {
+			continue
+		}
+		if mediaType := ref.Value.Content.Get("application/json"); mediaType != nil {
+			result = g.typeOf(mediaType.Schema, base+"Response", false)
+		}
+		break
+	}
+
+	var b strings.Builder
+	text := operation.Summary
+	if operation.Description != "" {
+		text = operation.Description
+	}
+	b.WriteString(description(text, "  "))
+	b.WriteString("  ")
+	b.WriteString(lowerFirst(base))
+	if len(args) != 0 {
+		fmt.Fprintf(&b, "(%s)", strings.Join(args, ", "))
+	}
+	fmt.Fprintf(&b, ": %s", result)
+	if operation.Deprecated {
+		b.WriteString(" @deprecated")
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return "_"
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// description returns the GraphQL description of text, as a block string indented by indent, or "".
+func description(text, indent string) string {
+	if text = strings.TrimSpace(text); text == "" {
+		return ""
+	}
+	text = strings.Replace(text, `"""`, `\"""`, -1)
+	var b strings.Builder
+	b.WriteString(indent + `"""` + "\n")
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(strings.TrimRight(indent+line, " "))
+		b.WriteByte('\n')
+	}
+	b.WriteString(indent + `"""` + "\n")
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package openapi3graphql
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestGenerate(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("testdata/pets.yaml")
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		golden string
+		opts   []Option
+	}{
+		{"testdata/pets.graphql", nil},
+		{"testdata/pets_operations.graphql", []Option{WithOperations()}},
+	} {
+		data, err := Generate(doc, test.opts...)
+		require.NoError(t, err)
+		expected, err := ioutil.ReadFile(test.golden)
+		require.NoError(t, err)
+		require.Equal(t, string(expected), string(data), test.golden)
+	}
+}
+
+func TestGenerateWithoutOperationID(t *testing.T) {
+	doc := &openapi3.T{Paths: openapi3.NewPaths().With("/pets", &openapi3.PathItem{Get: &openapi3.Operation{}})}
+	_, err := Generate(doc, WithOperations())
+	require.EqualError(t, err, "operation GET /pets has no operationId")
+}
+
+func TestEnumValue(t *testing.T) {
+	for value, expected := range map[string]string{
+		"dog":        "DOG",
+		"guinea-pig": "GUINEA_PIG",
+		"extraLarge": "EXTRA_LARGE",
+		"2fa":        "_2FA",
+		"--":         "EMPTY",
+		"a  b":       "A_B",
+	} {
+		require.Equal(t, expected, enumValue(value), value)
+	}
+}
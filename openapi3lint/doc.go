@@ -0,0 +1,8 @@
+// Package openapi3lint checks OpenAPIv3 documents against style and completeness rules
+// that go beyond the validity checks of openapi3.T.Validate.
+//
+// A Linter runs a set of rules (the built-in ones by default, see DefaultRules)
+// and returns findings telling which rule was broken, where (as a JSON pointer
+// into the document) and how seriously. Rules can be disabled, their severity
+// changed and custom rules added by implementing the Rule interface.
+package openapi3lint
@@ -0,0 +1,196 @@
+package openapi3lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Severity tells how serious a finding is.
+type Severity int
+
+const (
+	// SeverityInfo is for suggestions.
+	SeverityInfo Severity = iota
+	// SeverityWarning is for likely problems.
+	SeverityWarning
+	// SeverityError is for problems that should block a release of the document.
+	SeverityError
+)
+
+func (severity Severity) String() string {
+	switch severity {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(severity))
+}
+
+// MarshalText encodes the severity as its name.
+func (severity Severity) MarshalText() ([]byte, error) {
+	return []byte(severity.String()), nil
+}
+
+// UnmarshalText decodes a severity name.
+func (severity *Severity) UnmarshalText(text []byte) error {
+	for _, s := range []Severity{SeverityInfo, SeverityWarning, SeverityError} {
+		if s.String() == string(text) {
+			*severity = s
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity %q", text)
+}
+
+// Finding is a problem found by a rule.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	// Location is a JSON pointer to the offending part of the document, e.g. "/paths/~1pets/get".
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+func (finding *Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", finding.Severity, finding.Location, finding.Message, finding.Rule)
+}
+
+// Findings are sorted by location, then rule name.
+type Findings []*Finding
+
+// AtLeast returns the findings whose severity is at least the given one.
+func (findings Findings) AtLeast(severity Severity) Findings {
+	var filtered Findings
+	for _, finding := range findings {
+		if finding.Severity >= severity {
+			filtered = append(filtered, finding)
+		}
+	}
+	return filtered
+}
+
+// ReportFunc is called by rules for each problem they find.
+// location is a JSON pointer, as built by Pointer.
+type ReportFunc func(location, message string)
+
+// Rule is a check run on documents.
+type Rule interface {
+	// Name identifies the rule in findings and options.
+	Name() string
+	// Severity is the default severity of the findings of the rule.
+	Severity() Severity
+	// Check calls report for each problem found in doc.
+	Check(doc *openapi3.T, report ReportFunc)
+}
+
+// NewRule returns a Rule calling check.
+func NewRule(name string, severity Severity, check func(doc *openapi3.T, report ReportFunc)) Rule {
+	return &funcRule{name: name, severity: severity, check: check}
+}
+
+type funcRule struct {
+	name     string
+	severity Severity
+	check    func(doc *openapi3.T, report ReportFunc)
+}
+
+func (rule *funcRule) Name() string                             { return rule.name }
+func (rule *funcRule) Severity() Severity                       { return rule.severity }
+func (rule *funcRule) Check(doc *openapi3.T, report ReportFunc) { rule.check(doc, report) }
+
+// Option allows tweaking a Linter
+type Option func(*Linter)
+
+// WithRules adds rules to the linter.
+func WithRules(rules ...Rule) Option {
+	return func(l *Linter) { l.rules = append(l.rules, rules...) }
+}
+
+// WithoutDefaultRules removes the built-in rules from the linter,
+// leaving only the ones added with WithRules.
+func WithoutDefaultRules() Option {
+	return func(l *Linter) { l.noDefaultRules = true }
+}
+
+// DisableRules disables the rules with the given names.
+func DisableRules(names ...string) Option {
+	return func(l *Linter) {
+		for _, name := range names {
+			l.disabled[name] = true
+		}
+	}
+}
+
+// RuleSeverity overrides the severity of the findings of the rule named name.
+func RuleSeverity(name string, severity Severity) Option {
+	return func(l *Linter) { l.severities[name] = severity }
+}
+
+// Linter runs rules on documents.
+type Linter struct {
+	rules          []Rule
+	noDefaultRules bool
+	disabled       map[string]bool
+	severities     map[string]Severity
+}
+
+// New returns a Linter running the built-in rules, as configured by opts.
+func New(opts ...Option) *Linter {
+	l := &Linter{
+		disabled:   make(map[string]bool),
+		severities: make(map[string]Severity),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if !l.noDefaultRules {
+		l.rules = append(DefaultRules(), l.rules...)
+	}
+	return l
+}
+
+// Lint runs the enabled rules on doc.
+func (l *Linter) Lint(doc *openapi3.T) Findings {
+	var findings Findings
+	for _, rule := range l.rules {
+		name := rule.Name()
+		if l.disabled[name] {
+			continue
+		}
+		severity, ok := l.severities[name]
+		if !ok {
+			severity = rule.Severity()
+		}
+		rule.Check(doc, func(location, message string) {
+			findings = append(findings, &Finding{
+				Rule:     name,
+				Severity: severity,
+				Location: location,
+				Message:  message,
+			})
+		})
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Location != findings[j].Location {
+			return findings[i].Location < findings[j].Location
+		}
+		return findings[i].Rule < findings[j].Rule
+	})
+	return findings
+}
+
+// Pointer returns the JSON pointer made of the given reference tokens.
+func Pointer(tokens ...string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1))
+	}
+	return b.String()
+}
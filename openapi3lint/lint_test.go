@@ -0,0 +1,171 @@
+package openapi3lint_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3lint"
+)
+
+const spec = `
+openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.0.0
+tags:
+- name: pets
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      parameters:
+      - name: pageSize
+        in: query
+        description: Size of a page
+        schema: {type: integer}
+      - name: sort_order
+        in: query
+        description: Sort order
+        schema: {type: string}
+      - name: X-Request-ID
+        in: header
+        description: Request ID
+        schema: {type: string}
+      responses:
+        "200":
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: "#/components/schemas/Pet"}
+        "400":
+          $ref: "#/components/responses/Error"
+    post:
+      summary: Create a pet
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/Pet"}
+      responses:
+        "201":
+          description: Created
+components:
+  schemas:
+    Pet:
+      description: A pet
+      type: object
+      properties:
+        petId: {type: string}
+        displayName: {type: string}
+        birth_date: {type: string, format: date}
+        owner: {$ref: "#/components/schemas/Owner"}
+    Owner:
+      description: The owner of a pet
+      type: object
+      properties:
+        firstName: {type: string}
+        pets:
+          type: array
+          items: {$ref: "#/components/schemas/Owner"}
+    Error:
+      description: An error
+      type: object
+      properties:
+        message: {type: string}
+    Orphan:
+      type: object
+      properties:
+        self: {$ref: "#/components/schemas/Orphan"}
+  responses:
+    Error:
+      description: Error
+      content:
+        application/json:
+          schema: {$ref: "#/components/schemas/Error"}
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+    unused:
+      type: http
+      scheme: basic
+security:
+- apiKey: []
+`
+
+func loadSpec(t *testing.T) *openapi3.T {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	return doc
+}
+
+func TestLint(t *testing.T) {
+	findings := openapi3lint.New().Lint(loadSpec(t))
+
+	var lines []string
+	for _, finding := range findings {
+		lines = append(lines, finding.String())
+	}
+	require.Equal(t, []string{
+		`info: /components/schemas/Orphan: schema "Orphan" has no description (description-present)`,
+		`warning: /components/schemas/Orphan: component "Orphan" is never referenced (no-unused-components)`,
+		`warning: /components/schemas/Pet/properties/birth_date: property "birth_date" is snake_case while most are camelCase (consistent-naming)`,
+		`warning: /components/securitySchemes/unused: component "unused" is never referenced (no-unused-components)`,
+		`info: /info: document has no description (description-present)`,
+		`warning: /paths/~1pets/get/parameters/1: parameter "sort_order" is snake_case while most are camelCase (consistent-naming)`,
+		`error: /paths/~1pets/post: operation has no operationId (operation-operationId)`,
+		`warning: /paths/~1pets/post/responses: operation documents no 4xx response (operation-4xx-response)`,
+		`info: /tags/0: tag "pets" has no description (description-present)`,
+	}, lines)
+
+	require.Len(t, findings.AtLeast(openapi3lint.SeverityWarning), 6)
+	require.Len(t, findings.AtLeast(openapi3lint.SeverityError), 1)
+
+	data, err := json.Marshal(findings[1])
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"rule": "no-unused-components",
+		"severity": "warning",
+		"location": "/components/schemas/Orphan",
+		"message": "component \"Orphan\" is never referenced"
+	}`, string(data))
+}
+
+func TestLintOptions(t *testing.T) {
+	summaryLength := openapi3lint.NewRule("summary-length", openapi3lint.SeverityWarning, func(doc *openapi3.T, report openapi3lint.ReportFunc) {
+		for path, pathItem := range doc.Paths.Map() {
+			for method, operation := range pathItem.Operations() {
+				if len(operation.Summary) > 10 {
+					report(openapi3lint.Pointer("paths", path, strings.ToLower(method), "summary"), "summary is too long")
+				}
+			}
+		}
+	})
+
+	linter := openapi3lint.New(
+		openapi3lint.WithRules(summaryLength),
+		openapi3lint.DisableRules(openapi3lint.RuleDescriptions, openapi3lint.RuleNoUnusedComponents, openapi3lint.RuleConsistentNaming),
+		openapi3lint.RuleSeverity(openapi3lint.RuleOperation4xxResponse, openapi3lint.SeverityError),
+	)
+	findings := linter.Lint(loadSpec(t))
+	require.Equal(t, openapi3lint.Findings{
+		{Rule: "operation-operationId", Severity: openapi3lint.SeverityError, Location: "/paths/~1pets/post", Message: "operation has no operationId"},
+		{Rule: "operation-4xx-response", Severity: openapi3lint.SeverityError, Location: "/paths/~1pets/post/responses", Message: "operation documents no 4xx response"},
+		{Rule: "summary-length", Severity: openapi3lint.SeverityWarning, Location: "/paths/~1pets/post/summary", Message: "summary is too long"},
+	}, findings)
+
+	findings = openapi3lint.New(openapi3lint.WithoutDefaultRules(), openapi3lint.WithRules(summaryLength)).Lint(loadSpec(t))
+	require.Len(t, findings, 1)
+
+	var severity openapi3lint.Severity
+	require.NoError(t, severity.UnmarshalText([]byte("error")))
+	require.Equal(t, openapi3lint.SeverityError, severity)
+	require.EqualError(t, severity.UnmarshalText([]byte("fatal")), `unknown severity "fatal"`)
+}
@@ -0,0 +1,383 @@
+package openapi3lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Names of the built-in rules
+const (
+	// RuleOperationID requires an operationId on every operation.
+	RuleOperationID = "operation-operationId"
+	// RuleOperation4xxResponse requires every operation to document a 4xx response.
+	RuleOperation4xxResponse = "operation-4xx-response"
+	// RuleNoUnusedComponents reports components that nothing refers to.
+	RuleNoUnusedComponents = "no-unused-components"
+	// RuleConsistentNaming reports property, parameter and operation names
+	// not following the case style used by most of their kind.
+	RuleConsistentNaming = "consistent-naming"
+	// RuleDescriptions requires descriptions on the document, operations,
+	// parameters, tags and component schemas.
+	RuleDescriptions = "description-present"
+)
+
+// DefaultRules returns the built-in rules.
+func DefaultRules() []Rule {
+	return []Rule{
+		NewRule(RuleOperationID, SeverityError, checkOperationIDs),
+		NewRule(RuleOperation4xxResponse, SeverityWarning, checkOperation4xxResponses),
+		NewRule(RuleNoUnusedComponents, SeverityWarning, checkUnusedComponents),
+		NewRule(RuleConsistentNaming, SeverityWarning, checkConsistentNaming),
+		NewRule(RuleDescriptions, SeverityInfo, checkDescriptions),
+	}
+}
+
+// forEachOperation calls fn on operations sorted by path then method.
+func forEachOperation(doc *openapi3.T, fn func(path, method string, pathItem *openapi3.PathItem, operation *openapi3.Operation)) {
+	doc.WalkOperations(func(path, method string, operation *openapi3.Operation) {
+		fn(path, method, doc.Paths.Value(path), operation)
+	})
+}
+
+func operationPointer(path, method string) string {
+	return Pointer("paths", path, strings.ToLower(method))
+}
+
+func checkOperationIDs(doc *openapi3.T, report ReportFunc) {
+	forEachOperation(doc, func(path, method string, _ *openapi3.PathItem, operation *openapi3.Operation) {
+		if operation.OperationID == "" {
+			report(operationPointer(path, method), "operation has no operationId")
+		}
+	})
+}
+
+func checkOperation4xxResponses(doc *openapi3.T, report ReportFunc) {
+	forEachOperation(doc, func(path, method string, _ *openapi3.PathItem, operation *openapi3.Operation) {
+		for status := range operation.Responses.Map() {
+			if len(status) == 3 && status[0] == '4' {
+				return
+			}
+		}
+		report(Pointer("paths", path, strings.ToLower(method), "responses"), "operation documents no 4xx response")
+	})
+}
+
+func checkUnusedComponents(doc *openapi3.T, report ReportFunc) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return
+	}
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return
+	}
+	components, _ := root["components"].(map[string]interface{})
+	delete(root, "components")
+
+	var pending []string
+	addRef := func(ref string) {
+		if strings.HasPrefix(ref, "#/components/") {
+			pending = append(pending, strings.TrimPrefix(ref, "#"))
+		}
+	}
+	collectRefs(root, addRef)
+	addSecurity := func(requirements *openapi3.SecurityRequirements) {
+		if requirements == nil {
+			return
+		}
+		for _, requirement := range *requirements {
+			for name := range requirement {
+				pending = append(pending, Pointer("components", "securitySchemes", name))
+			}
+		}
+	}
+	addSecurity(&doc.Security)
+	forEachOperation(doc, func(_, _ string, _ *openapi3.PathItem, operation *openapi3.Operation) {
+		addSecurity(operation.Security)
+	})
+
+	used := make(map[string]bool)
+	for len(pending) != 0 {
+		pointer := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if used[pointer] {
+			continue
+		}
+		used[pointer] = true
+		tokens := strings.Split(strings.TrimPrefix(pointer, "/components/"), "/")
+		if len(tokens) < 2 {
+			continue
+		}
+		kind, _ := components[tokens[0]].(map[string]interface{})
+		collectRefs(kind[unescapeToken(tokens[1])], addRef)
+	}
+
+	kinds := make([]string, 0, len(components))
+	for kind := range components {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		values, _ := components[kind].(map[string]interface{})
+		names := make([]string, 0, len(values))
+		for name := range values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			pointer := Pointer("components", kind, name)
+			if !used[pointer] {
+				report(pointer, fmt.Sprintf("component %q is never referenced", name))
+			}
+		}
+	}
+}
+
+// collectRefs calls addRef with the $ref values and discriminator mappings found in v.
+func collectRefs(v interface{}, addRef func(string)) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			addRef(ref)
+		}
+		if discriminator, ok := v["discriminator"].(map[string]interface{}); ok {
+			mapping, _ := discriminator["mapping"].(map[string]interface{})
+			for _, value := range mapping {
+				if ref, ok := value.(string); ok {
+					addRef(ref)
+				}
+			}
+		}
+		for _, value := range v {
+			collectRefs(value, addRef)
+		}
+	case []interface{}:
+		for _, value := range v {
+			collectRefs(value, addRef)
+		}
+	}
+}
+
+func unescapeToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+}
+
+// Case styles of names
+const (
+	styleCamel  = "camelCase"
+	styleSnake  = "snake_case"
+	styleKebab  = "kebab-case"
+	stylePascal = "PascalCase"
+)
+
+// nameStyle returns the case style of name, or an empty string when it is ambiguous
+// (e.g. a single lowercase word) or mixed.
+func nameStyle(name string) string {
+	hasUpper := strings.IndexFunc(name, unicode.IsUpper) >= 0
+	hasUnderscore := strings.ContainsRune(name, '_')
+	hasDash := strings.ContainsRune(name, '-')
+	switch {
+	case name == "":
+		return ""
+	case hasUnderscore && !hasDash && !hasUpper:
+		return styleSnake
+	case hasDash && !hasUnderscore && !hasUpper:
+		return styleKebab
+	case hasUnderscore || hasDash || !hasUpper:
+		return ""
+	case unicode.IsUpper([]rune(name)[0]):
+		if strings.IndexFunc(name, unicode.IsLower) < 0 {
+			// An acronym or a constant
+			return ""
+		}
+		return stylePascal
+	default:
+		return styleCamel
+	}
+}
+
+type namedLocation struct {
+	name     string
+	location string
+}
+
+func checkConsistentNaming(doc *openapi3.T, report ReportFunc) {
+	var properties, parameters, operationIDs []namedLocation
+
+	visited := make(map[*openapi3.Schema]bool)
+	forEachSchema(doc, visited, func(location string, schema *openapi3.Schema) {
+		names := make([]string, 0, schema.Properties.Len())
+		for name := range schema.Properties.Map() {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			properties = append(properties, namedLocation{name: name, location: location + Pointer("properties", name)})
+		}
+	})
+	forEachParameter(doc, func(location string, parameter *openapi3.Parameter) {
+		if parameter.In != openapi3.ParameterInHeader {
+			parameters = append(parameters, namedLocation{name: parameter.Name, location: location})
+		}
+	})
+	forEachOperation(doc, func(path, method string, _ *openapi3.PathItem, operation *openapi3.Operation) {
+		if operation.OperationID != "" {
+			operationIDs = append(operationIDs, namedLocation{name: operation.OperationID, location: operationPointer(path, method)})
+		}
+	})
+
+	checkStyles("property", properties, report)
+	checkStyles("parameter", parameters, report)
+	checkStyles("operationId", operationIDs, report)
+}
+
+// checkStyles reports the names not following the style most of them follow.
+func checkStyles(kind string, names []namedLocation, report ReportFunc) {
+	counts := make(map[string]int)
+	for _, name := range names {
+		if style := nameStyle(name.name); style != "" {
+			counts[style]++
+		}
+	}
+	dominant := ""
+	for _, style := range []string{styleCamel, styleSnake, styleKebab, stylePascal} {
+		if counts[style] > counts[dominant] {
+			dominant = style
+		}
+	}
+	if len(counts) < 2 {
+		return
+	}
+	for _, name := range names {
+		if style := nameStyle(name.name); style != "" && style != dominant {
+			report(name.location, fmt.Sprintf("%s %q is %s while most are %s", kind, name.name, style, dominant))
+		}
+	}
+}
+
+func checkDescriptions(doc *openapi3.T, report ReportFunc) {
+	if doc.Info != nil && doc.Info.Description == "" {
+		report(Pointer("info"), "document has no description")
+	}
+	for i, tag := range doc.Tags {
+		if tag != nil && tag.Description == "" {
+			report(Pointer("tags", fmt.Sprint(i)), fmt.Sprintf("tag %q has no description", tag.Name))
+		}
+	}
+	forEachOperation(doc, func(path, method string, _ *openapi3.PathItem, operation *openapi3.Operation) {
+		if operation.Summary == "" && operation.Description == "" {
+			report(operationPointer(path, method), "operation has neither summary nor description")
+		}
+	})
+	forEachParameter(doc, func(location string, parameter *openapi3.Parameter) {
+		if parameter.Description == "" {
+			report(location, fmt.Sprintf("parameter %q has no description", parameter.Name))
+		}
+	})
+	for _, name := range doc.Components.Schemas.InSortedOrder() {
+		if schema := doc.Components.Schemas.Value(name).Value; schema != nil && schema.Description == "" {
+			report(Pointer("components", "schemas", name), fmt.Sprintf("schema %q has no description", name))
+		}
+	}
+}
+
+// forEachParameter calls fn on the parameters defined inline in path items and operations
+// and on the ones defined in components.
+func forEachParameter(doc *openapi3.T, fn func(location string, parameter *openapi3.Parameter)) {
+	visit := func(location string, parameters openapi3.Parameters) {
+		for i, parameterRef := range parameters {
+			if parameterRef != nil && parameterRef.Ref == "" && parameterRef.Value != nil {
+				fn(location+Pointer("parameters", fmt.Sprint(i)), parameterRef.Value)
+			}
+		}
+	}
+	visitedPaths := make(map[string]bool)
+	forEachOperation(doc, func(path, method string, pathItem *openapi3.PathItem, operation *openapi3.Operation) {
+		if !visitedPaths[path] {
+			visitedPaths[path] = true
+			visit(Pointer("paths", path), pathItem.Parameters)
+		}
+		visit(operationPointer(path, method), operation.Parameters)
+	})
+
+	names := make([]string, 0, len(doc.Components.Parameters))
+	for name := range doc.Components.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if parameterRef := doc.Components.Parameters[name]; parameterRef != nil && parameterRef.Value != nil {
+			fn(Pointer("components", "parameters", name), parameterRef.Value)
+		}
+	}
+}
+
+// forEachSchema calls fn once on each schema of the document, component schemas first.
+// Referenced schemas are visited where they are defined.
+func forEachSchema(doc *openapi3.T, visited map[*openapi3.Schema]bool, fn func(location string, schema *openapi3.Schema)) {
+	for _, name := range doc.Components.Schemas.InSortedOrder() {
+		walkSchema(Pointer("components", "schemas", name), doc.Components.Schemas.Value(name), true, visited, fn)
+	}
+	walkContent := func(location string, content openapi3.Content) {
+		mediaTypes := make([]string, 0, len(content))
+		for mediaType := range content {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+		sort.Strings(mediaTypes)
+		for _, mediaType := range mediaTypes {
+			if content[mediaType] != nil {
+				walkSchema(location+Pointer("content", mediaType, "schema"), content[mediaType].Schema, false, visited, fn)
+			}
+		}
+	}
+	forEachParameter(doc, func(location string, parameter *openapi3.Parameter) {
+		walkSchema(location+Pointer("schema"), parameter.Schema, false, visited, fn)
+		walkContent(location, parameter.Content)
+	})
+	forEachOperation(doc, func(path, method string, _ *openapi3.PathItem, operation *openapi3.Operation) {
+		location := operationPointer(path, method)
+		if body := operation.RequestBody; body != nil && body.Ref == "" && body.Value != nil {
+			walkContent(location+Pointer("requestBody"), body.Value.Content)
+		}
+		statuses := make([]string, 0, operation.Responses.Len())
+		for status := range operation.Responses.Map() {
+			statuses = append(statuses, status)
+		}
+		sort.Strings(statuses)
+		for _, status := range statuses {
+			if response := operation.Responses.Value(status); response != nil && response.Ref == "" && response.Value != nil {
+				walkContent(location+Pointer("responses", status), response.Value.Content)
+			}
+		}
+	})
+}
+
+func walkSchema(location string, ref *openapi3.SchemaRef, root bool, visited map[*openapi3.Schema]bool, fn func(location string, schema *openapi3.Schema)) {
+	if ref == nil || ref.Value == nil || (ref.Ref != "" && !root) || visited[ref.Value] {
+		return
+	}
+	schema := ref.Value
+	visited[schema] = true
+	fn(location, schema)
+
+	for _, name := range schema.Properties.InSortedOrder() {
+		walkSchema(location+Pointer("properties", name), schema.Properties.Value(name), false, visited, fn)
+	}
+	walkSchema(location+Pointer("items"), schema.Items, false, visited, fn)
+	walkSchema(location+Pointer("additionalProperties"), schema.AdditionalProperties, false, visited, fn)
+	walkSchema(location+Pointer("not"), schema.Not, false, visited, fn)
+	for i, ref := range schema.AllOf {
+		walkSchema(location+Pointer("allOf", fmt.Sprint(i)), ref, false, visited, fn)
+	}
+	for i, ref := range schema.AnyOf {
+		walkSchema(location+Pointer("anyOf", fmt.Sprint(i)), ref, false, visited, fn)
+	}
+	for i, ref := range schema.OneOf {
+		walkSchema(location+Pointer("oneOf", fmt.Sprint(i)), ref, false, visited, fn)
+	}
+}
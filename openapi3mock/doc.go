@@ -0,0 +1,14 @@
+// Package openapi3mock serves mock responses for the operations of an OpenAPIv3 document.
+//
+// Requests are routed and validated with openapi3filter, then answered
+// with the examples of the operation's responses, or with data generated from their schemas.
+//
+// Clients select responses with the Prefer header (as popularized by Prism):
+//   - `Prefer: code=404` picks the response of the given status code;
+//   - `Prefer: example=notFound` picks a named example;
+//   - `Prefer: dynamic=true` generates data from schemas even when examples exist.
+//
+// The Accept header selects the media type of the response.
+//
+// SelectExample exposes that selection, e.g. for documentation tooling.
+package openapi3mock
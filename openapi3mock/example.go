@@ -0,0 +1,157 @@
+package openapi3mock
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var (
+	// ErrNoResponse is matched by the errors of SelectExample when the operation
+	// has no response of the requested status code, or no success or default response.
+	ErrNoResponse = errors.New("no response")
+	// ErrNotAcceptable is matched by the errors of SelectExample when none of the
+	// media types of the selected response is acceptable.
+	ErrNotAcceptable = errors.New("no acceptable media type")
+	// ErrExampleNotFound is matched by the errors of SelectExample when the media type
+	// has no example of the requested name.
+	ErrExampleNotFound = errors.New("example not found")
+)
+
+// ExampleQuery describes the response example SelectExample returns.
+type ExampleQuery struct {
+	// Status is the status code of the response. When zero, the response of the
+	// lowest success status code is selected, or else the default response.
+	Status int
+	// Accept lists the acceptable media types, as the Accept header does.
+	// JSON is preferred when any media type is acceptable.
+	Accept string
+	// ExampleName is the name of the example of the media type.
+	// When empty, the first example is selected, in the order of names.
+	ExampleName string
+	// Set Dynamic so the value is generated from the schema of the media type
+	// even when it has examples.
+	Dynamic bool
+}
+
+// ExampleQueryFromRequest returns the query of the Prefer and Accept headers of r,
+// as described in the package documentation.
+func ExampleQueryFromRequest(r *http.Request) (ExampleQuery, error) {
+	prefer := parsePrefer(r.Header.Values("Prefer"))
+	query := ExampleQuery{
+		Accept:      r.Header.Get("Accept"),
+		ExampleName: prefer["example"],
+		Dynamic:     prefer["dynamic"] == "true",
+	}
+	if code := prefer["code"]; code != "" {
+		status, err := strconv.Atoi(code)
+		if err != nil || status < 100 || status > 599 {
+			return ExampleQuery{}, fmt.Errorf("invalid preferred status code %q", code)
+		}
+		query.Status = status
+	}
+	return query, nil
+}
+
+// Example is a response example selected by SelectExample.
+type Example struct {
+	// Status is the status code of the response.
+	Status int
+	// Response is the selected response of the operation.
+	Response *openapi3.Response
+	// MediaType is the selected media type, empty when the response has no content.
+	MediaType string
+	// Value is the example payload.
+	Value interface{}
+	// Generated is set when Value was generated from the schema of the media type.
+	Generated bool
+}
+
+// SelectExample returns the example of the response of operation that query describes:
+// the named example of its media type, or else the example of the media type,
+// or else the example of its schema, or else a value generated from its schema.
+// Errors match ErrNoResponse, ErrNotAcceptable or ErrExampleNotFound.
+func SelectExample(operation *openapi3.Operation, query ExampleQuery) (*Example, error) {
+	status, responseRef, err := selectResponse(operation.Responses, query.Status)
+	if err != nil {
+		return nil, err
+	}
+	example := &Example{Status: status, Response: responseRef.Value}
+
+	content := example.Response.Content
+	if len(content) == 0 {
+		return example, nil
+	}
+	example.MediaType = negotiate(content, query.Accept)
+	if example.MediaType == "" {
+		return nil, fmt.Errorf("%w: none of the media types %s is acceptable", ErrNotAcceptable, strings.Join(content.MediaTypes(), ", "))
+	}
+	if example.Value, example.Generated, err = mediaTypeValue(content[example.MediaType], query.ExampleName, query.Dynamic); err != nil {
+		return nil, err
+	}
+	return example, nil
+}
+
+// selectResponse returns the response matching the status code (see openapi3.Responses.Match),
+// or when zero the response of the lowest success status code, or else the default response.
+func selectResponse(responses *openapi3.Responses, status int) (int, *openapi3.ResponseRef, error) {
+	if status != 0 {
+		if _, response := responses.Match(status); response != nil && response.Value != nil {
+			return status, response, nil
+		}
+		return 0, nil, fmt.Errorf("%w for status code %d", ErrNoResponse, status)
+	}
+
+	for _, code := range responses.InSortedOrder() {
+		if response := responses.Value(code); response != nil && response.Value != nil && code[0] == '2' {
+			if status, err := strconv.Atoi(code); err == nil {
+				return status, response, nil
+			}
+			return http.StatusOK, response, nil
+		}
+	}
+	if response := responses.Default(); response != nil && response.Value != nil {
+		return http.StatusOK, response, nil
+	}
+	return 0, nil, fmt.Errorf("%w: no success or default response", ErrNoResponse)
+}
+
+// mediaTypeValue returns the named example, or else the example of the media type,
+// or else a value generated from its schema, which is reported.
+func mediaTypeValue(mediaType *openapi3.MediaType, exampleName string, dynamic bool) (interface{}, bool, error) {
+	if exampleName != "" {
+		example := mediaType.Examples[exampleName]
+		if example == nil || example.Value == nil {
+			return nil, false, fmt.Errorf("%w: no example named %q", ErrExampleNotFound, exampleName)
+		}
+		return example.Value.Value, false, nil
+	}
+	if !dynamic {
+		if mediaType.Example != nil {
+			return mediaType.Example, false, nil
+		}
+		names := make([]string, 0, len(mediaType.Examples))
+		for name := range mediaType.Examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if example := mediaType.Examples[name]; example != nil && example.Value != nil {
+				return example.Value.Value, false, nil
+			}
+		}
+	}
+	if mediaType.Schema != nil && mediaType.Schema.Value != nil {
+		schema := mediaType.Schema.Value
+		if schema.Example != nil && !dynamic {
+			return schema.Example, false, nil
+		}
+		return schema.GenerateExample(openapi3.VisitAsResponse()), true, nil
+	}
+	return nil, false, nil
+}
@@ -0,0 +1,167 @@
+package openapi3mock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// Option allows tweaking a Server
+type Option func(*Server)
+
+// WithRouter sets the router matching requests to operations.
+// It defaults to a gorillamux router for the document.
+func WithRouter(router routers.Router) Option {
+	return func(s *Server) { s.router = router }
+}
+
+// WithValidationOptions sets the options of request validation.
+// Authentication is skipped by default.
+func WithValidationOptions(options *openapi3filter.Options) Option {
+	return func(s *Server) { s.options = options }
+}
+
+// WithoutRequestValidation disables the validation of incoming requests.
+func WithoutRequestValidation() Option {
+	return func(s *Server) { s.skipValidation = true }
+}
+
+// WithErrorEncoder sets the encoder of routing and validation errors.
+// It defaults to the Encode method of openapi3filter.DefaultErrorPipeline.
+func WithErrorEncoder(encoder openapi3filter.ErrorEncoder) Option {
+	return func(s *Server) { s.errorEncoder = encoder }
+}
+
+// Server is an http.Handler answering the operations of a document with mock responses.
+type Server struct {
+	router         routers.Router
+	options        *openapi3filter.Options
+	skipValidation bool
+	errorEncoder   openapi3filter.ErrorEncoder
+}
+
+var _ http.Handler = (*Server)(nil)
+
+// NewServer returns a Server for doc, which should be valid.
+func NewServer(doc *openapi3.T, opts ...Option) (*Server, error) {
+	s := &Server{}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.router == nil {
+		router, err := gorillamux.NewRouter(doc)
+		if err != nil {
+			return nil, err
+		}
+		s.router = router
+	}
+	if s.options == nil {
+		s.options = &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc}
+	}
+	if s.errorEncoder == nil {
+		s.errorEncoder = openapi3filter.DefaultErrorPipeline.Encode
+	}
+	return s, nil
+}
+
+// ServeHTTP routes and validates the request then writes the selected mock response.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	route, pathParams, err := s.router.FindRoute(r)
+	if err != nil {
+		s.errorEncoder(ctx, err, w)
+		return
+	}
+	if !s.skipValidation {
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    s.options,
+		}
+		if err := openapi3filter.ValidateRequest(ctx, input); err != nil {
+			s.errorEncoder(ctx, err, w)
+			return
+		}
+	}
+
+	query, err := ExampleQueryFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+		return
+	}
+	example, err := SelectExample(route.Operation, query)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, ErrNoResponse):
+			status = http.StatusNotImplemented
+		case errors.Is(err, ErrNotAcceptable):
+			status = http.StatusNotAcceptable
+		case errors.Is(err, ErrExampleNotFound):
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	names := make([]string, 0, len(example.Response.Headers))
+	for name := range example.Response.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if headerRef := example.Response.Headers[name]; headerRef != nil && headerRef.Value != nil {
+			if value, ok := parameterValue(&headerRef.Value.Parameter); ok {
+				w.Header().Set(name, fmt.Sprint(value))
+			}
+		}
+	}
+
+	if example.MediaType == "" {
+		w.WriteHeader(example.Status)
+		return
+	}
+	body, err := encodeBody(example.MediaType, example.Value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", example.MediaType)
+	w.WriteHeader(example.Status)
+	w.Write(body)
+}
+
+// parameterValue returns the example of a header, or a value generated from its schema.
+func parameterValue(parameter *openapi3.Parameter) (interface{}, bool) {
+	if parameter.Example != nil {
+		return parameter.Example, true
+	}
+	if parameter.Schema != nil && parameter.Schema.Value != nil {
+		if parameter.Schema.Value.Example != nil {
+			return parameter.Schema.Value.Example, true
+		}
+		return parameter.Schema.Value.GenerateExample(openapi3.VisitAsResponse()), true
+	}
+	return nil, false
+}
+
+func encodeBody(contentType string, value interface{}) ([]byte, error) {
+	if s, ok := value.(string); ok && !isJSON(contentType) {
+		return []byte(s), nil
+	}
+	return json.Marshal(value)
+}
+
+func isJSON(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
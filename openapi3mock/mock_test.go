@@ -0,0 +1,202 @@
+package openapi3mock_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3mock"
+)
+
+const spec = `
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+servers: [{url: "http://example.com/api"}]
+paths:
+  /pets:
+    get:
+      parameters:
+      - {name: limit, in: query, schema: {type: integer, maximum: 10}}
+      responses:
+        "200":
+          description: Pets
+          headers:
+            X-Total-Count:
+              schema: {type: integer, example: 2}
+          content:
+            application/json:
+              examples:
+                cats:
+                  value: [{name: Tom}]
+                dogs:
+                  value: [{name: Rex}, {name: Laika}]
+            text/csv:
+              example: "name\nTom\n"
+        "404":
+          description: Not found
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [message]
+                properties:
+                  message: {type: string, example: no pets}
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id, name]
+                properties:
+                  id: {type: integer, minimum: 1}
+                  name: {type: string}
+                  secret: {type: string, writeOnly: true}
+  /pets/{id}:
+    delete:
+      parameters:
+      - {name: id, in: path, required: true, schema: {type: integer}}
+      responses:
+        "204": {description: Deleted}
+`
+
+func TestServer(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	server, err := openapi3mock.NewServer(doc)
+	require.NoError(t, err)
+
+	do := func(method, target, body string, header http.Header) (int, http.Header, string) {
+		var r *http.Request
+		if body == "" {
+			r = httptest.NewRequest(method, target, nil)
+		} else {
+			r = httptest.NewRequest(method, target, strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+		}
+		for name, values := range header {
+			r.Header[name] = values
+		}
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, r)
+		data, err := ioutil.ReadAll(w.Result().Body)
+		require.NoError(t, err)
+		return w.Code, w.Result().Header, string(data)
+	}
+
+	// First example by name, with a generated header
+	status, header, body := do(http.MethodGet, "http://example.com/api/pets", "", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "application/json", header.Get("Content-Type"))
+	require.Equal(t, "2", header.Get("X-Total-Count"))
+	require.JSONEq(t, `[{"name": "Tom"}]`, body)
+
+	// Named example
+	_, _, body = do(http.MethodGet, "http://example.com/api/pets", "", http.Header{"Prefer": {"example=dogs"}})
+	require.JSONEq(t, `[{"name": "Rex"}, {"name": "Laika"}]`, body)
+
+	// Accept header
+	status, header, body = do(http.MethodGet, "http://example.com/api/pets", "", http.Header{"Accept": {"text/html;q=0.9, text/*"}})
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "text/csv", header.Get("Content-Type"))
+	require.Equal(t, "name\nTom\n", body)
+	status, _, _ = do(http.MethodGet, "http://example.com/api/pets", "", http.Header{"Accept": {"application/xml"}})
+	require.Equal(t, http.StatusNotAcceptable, status)
+
+	// Preferred status code, with a body from the schema example
+	status, _, body = do(http.MethodGet, "http://example.com/api/pets", "", http.Header{"Prefer": {`code=404`}})
+	require.Equal(t, http.StatusNotFound, status)
+	require.JSONEq(t, `{"message": "no pets"}`, body)
+	status, _, _ = do(http.MethodGet, "http://example.com/api/pets", "", http.Header{"Prefer": {`code=500`}})
+	require.Equal(t, http.StatusNotImplemented, status)
+
+	// Generated body, without write-only properties
+	status, _, body = do(http.MethodPost, "http://example.com/api/pets", `{"name": "Tom"}`, nil)
+	require.Equal(t, http.StatusCreated, status)
+	require.JSONEq(t, `{"id": 1, "name": "string"}`, body)
+
+	// No content
+	status, _, body = do(http.MethodDelete, "http://example.com/api/pets/1", "", nil)
+	require.Equal(t, http.StatusNoContent, status)
+	require.Empty(t, body)
+
+	// Invalid requests
+	status, _, _ = do(http.MethodGet, "http://example.com/api/pets?limit=100", "", nil)
+	require.Equal(t, http.StatusBadRequest, status)
+	status, _, _ = do(http.MethodPost, "http://example.com/api/pets", `{}`, nil)
+	require.Equal(t, http.StatusUnprocessableEntity, status)
+	status, _, _ = do(http.MethodGet, "http://example.com/api/owners", "", nil)
+	require.Equal(t, http.StatusNotFound, status)
+	status, _, _ = do(http.MethodPut, "http://example.com/api/pets", "", nil)
+	require.Equal(t, http.StatusMethodNotAllowed, status)
+
+	server, err = openapi3mock.NewServer(doc, openapi3mock.WithoutRequestValidation())
+	require.NoError(t, err)
+	status, _, _ = do(http.MethodGet, "http://example.com/api/pets?limit=100", "", nil)
+	require.Equal(t, http.StatusOK, status)
+}
+
+func TestSelectExample(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	get := doc.Paths.Value("/pets").Get
+
+	example, err := openapi3mock.SelectExample(get, openapi3mock.ExampleQuery{})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, example.Status)
+	require.Equal(t, "application/json", example.MediaType)
+	require.Equal(t, []interface{}{map[string]interface{}{"name": "Tom"}}, example.Value)
+	require.False(t, example.Generated)
+
+	example, err = openapi3mock.SelectExample(get, openapi3mock.ExampleQuery{ExampleName: "dogs"})
+	require.NoError(t, err)
+	require.Len(t, example.Value, 2)
+
+	example, err = openapi3mock.SelectExample(get, openapi3mock.ExampleQuery{Accept: "text/*"})
+	require.NoError(t, err)
+	require.Equal(t, "text/csv", example.MediaType)
+	require.Equal(t, "name\nTom\n", example.Value)
+
+	example, err = openapi3mock.SelectExample(get, openapi3mock.ExampleQuery{Status: http.StatusNotFound, Dynamic: true})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, example.Status)
+	require.True(t, example.Generated)
+	require.Equal(t, map[string]interface{}{"message": "no pets"}, example.Value)
+
+	example, err = openapi3mock.SelectExample(doc.Paths.Value("/pets/{id}").Delete, openapi3mock.ExampleQuery{})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, example.Status)
+	require.Empty(t, example.MediaType)
+	require.Nil(t, example.Value)
+
+	_, err = openapi3mock.SelectExample(get, openapi3mock.ExampleQuery{Status: http.StatusInternalServerError})
+	require.True(t, errors.Is(err, openapi3mock.ErrNoResponse))
+	_, err = openapi3mock.SelectExample(get, openapi3mock.ExampleQuery{Accept: "application/xml"})
+	require.True(t, errors.Is(err, openapi3mock.ErrNotAcceptable))
+	_, err = openapi3mock.SelectExample(get, openapi3mock.ExampleQuery{ExampleName: "birds"})
+	require.True(t, errors.Is(err, openapi3mock.ErrExampleNotFound))
+
+	r := httptest.NewRequest(http.MethodGet, "/pets", nil)
+	r.Header.Set("Prefer", `code=404, example="not found", dynamic=true`)
+	r.Header.Set("Accept", "application/json")
+	query, err := openapi3mock.ExampleQueryFromRequest(r)
+	require.NoError(t, err)
+	require.Equal(t, openapi3mock.ExampleQuery{Status: 404, Accept: "application/json", ExampleName: "not found", Dynamic: true}, query)
+}
@@ -0,0 +1,95 @@
+package openapi3mock
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// parsePrefer returns the preferences of Prefer headers (RFC7240),
+// e.g. `code=404, example="not found"`, keyed by lowercase name.
+func parsePrefer(headers []string) map[string]string {
+	preferences := make(map[string]string)
+	for _, header := range headers {
+		for _, preference := range strings.Split(header, ",") {
+			// Parameters of a preference (after a semicolon) are ignored
+			preference = strings.TrimSpace(strings.SplitN(preference, ";", 2)[0])
+			if preference == "" {
+				continue
+			}
+			name, value := preference, ""
+			if i := strings.IndexByte(preference, '='); i >= 0 {
+				name, value = strings.TrimSpace(preference[:i]), strings.TrimSpace(preference[i+1:])
+				if unquoted, err := strconv.Unquote(value); err == nil {
+					value = unquoted
+				}
+			}
+			name = strings.ToLower(name)
+			if _, ok := preferences[name]; !ok {
+				preferences[name] = value
+			}
+		}
+	}
+	return preferences
+}
+
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// negotiate returns the media type of content best matching the Accept header,
+// or an empty string if none is acceptable.
+// JSON is preferred when several media types match equally.
+func negotiate(content openapi3.Content, accept string) string {
+	candidates := content.MediaTypes()
+	for i, candidate := range candidates {
+		if isJSON(candidate) {
+			candidates = append(append([]string{candidate}, candidates[:i]...), candidates[i+1:]...)
+			break
+		}
+	}
+	if strings.TrimSpace(accept) == "" {
+		return candidates[0]
+	}
+
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		r := mediaRange{q: 1}
+		typ := strings.ToLower(strings.TrimSpace(params[0]))
+		if i := strings.IndexByte(typ, '/'); i >= 0 {
+			r.typ, r.subtype = typ[:i], typ[i+1:]
+		} else {
+			r.typ, r.subtype = typ, "*"
+		}
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					r.q = q
+				}
+			}
+		}
+		if r.q > 0 {
+			ranges = append(ranges, r)
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	for _, r := range ranges {
+		for _, candidate := range candidates {
+			mediaType := strings.ToLower(strings.TrimSpace(strings.SplitN(candidate, ";", 2)[0]))
+			typ, subtype := mediaType, ""
+			if i := strings.IndexByte(mediaType, '/'); i >= 0 {
+				typ, subtype = mediaType[:i], mediaType[i+1:]
+			}
+			if (r.typ == "*" || r.typ == typ) && (r.subtype == "*" || r.subtype == subtype) {
+				return candidate
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,19 @@
+// Package openapi3reload keeps an OpenAPIv3 document, and the router and validator
+// built from it, up to date with its source, so long-running servers pick up
+// changes to the document without restarting.
+//
+// A Reloader watches the files of a local document, including those of its external
+// references, by polling their modification times, or re-fetches a remote document
+// with conditional requests (ETag and Last-Modified), but not the documents it
+// references. Changed documents are loaded and validated before the router and
+// validator in use are swapped atomically: invalid documents are reported and the
+// previous ones stay in use.
+//
+//	reloader, err := openapi3reload.New("openapi.yaml",
+//		openapi3reload.WithValidatorOptions(openapi3filter.Strict(true)))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	go reloader.Run(ctx)
+//	http.ListenAndServe(":8080", reloader.Middleware(handler))
+package openapi3reload
@@ -0,0 +1,342 @@
+package openapi3reload
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// DefaultInterval is the default interval between the checks of Run.
+const DefaultInterval = 5 * time.Second
+
+// Snapshot is a loaded document, along with the router and validator built from it.
+// It must not be modified.
+type Snapshot struct {
+	Doc       *openapi3.T
+	Router    routers.Router
+	Validator *openapi3filter.Validator
+	// LoadedAt is when the document was loaded.
+	LoadedAt time.Time
+}
+
+// Option allows tweaking a Reloader.
+type Option func(*Reloader)
+
+// WithLoader sets the function returning the Loader of each load, e.g. to set
+// its ReadFromURIFunc. It defaults to a Loader allowing external references.
+// Loaders must not cache the files they read (as openapi3.DefaultReadFromURI does),
+// which is the default when their ReadFromURIFunc is nil.
+func WithLoader(newLoader func() *openapi3.Loader) Option {
+	return func(r *Reloader) { r.newLoader = newLoader }
+}
+
+// WithRouter sets the function building the router of documents.
+// It defaults to gorillamux.NewRouter.
+func WithRouter(newRouter func(doc *openapi3.T) (routers.Router, error)) Option {
+	return func(r *Reloader) { r.newRouter = newRouter }
+}
+
+// WithValidatorOptions sets the options of the validators built for documents.
+func WithValidatorOptions(opts ...openapi3filter.ValidatorOption) Option {
+	return func(r *Reloader) { r.validatorOptions = opts }
+}
+
+// WithHTTPClient sets the client fetching remote documents. It defaults to http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(r *Reloader) { r.client = client }
+}
+
+// WithInterval sets the interval between the checks of Run. It defaults to DefaultInterval.
+func WithInterval(interval time.Duration) Option {
+	return func(r *Reloader) { r.interval = interval }
+}
+
+// OnReload sets a function called after each attempt to load a changed document,
+// but for the first one, with either the new snapshot or the error that kept
+// the previous one in use.
+func OnReload(f func(snapshot *Snapshot, err error)) Option {
+	return func(r *Reloader) { r.onReload = f }
+}
+
+// Reloader keeps a Snapshot of a document up to date with its source.
+// It is safe for concurrent use.
+type Reloader struct {
+	location         string
+	remote           bool
+	newLoader        func() *openapi3.Loader
+	newRouter        func(doc *openapi3.T) (routers.Router, error)
+	validatorOptions []openapi3filter.ValidatorOption
+	client           *http.Client
+	interval         time.Duration
+	onReload         func(snapshot *Snapshot, err error)
+
+	current atomic.Value // *Snapshot
+	handle  *openapi3filter.ValidatorHandle
+
+	// mu serializes the checks and loads, which update the fields below.
+	mu sync.Mutex
+	// files holds the stamps of the local files read by the last load.
+	files map[string]fileStamp
+	// etag, lastModified and digest describe the remote document of the last load.
+	etag, lastModified string
+	digest             [sha256.Size]byte
+}
+
+type fileStamp struct {
+	modTime int64
+	size    int64
+}
+
+// New returns a Reloader of the document at location, a file path
+// or an HTTP(S) URL, once loaded.
+func New(location string, opts ...Option) (*Reloader, error) {
+	r := &Reloader{
+		location: location,
+		remote:   strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://"),
+		newLoader: func() *openapi3.Loader {
+			loader := openapi3.NewLoader()
+			loader.IsExternalRefsAllowed = true
+			return loader
+		},
+		newRouter: func(doc *openapi3.T) (routers.Router, error) { return gorillamux.NewRouter(doc) },
+		client:    http.DefaultClient,
+		interval:  DefaultInterval,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var data []byte
+	if r.remote {
+		var err error
+		if data, _, err = r.fetch(context.Background(), false); err != nil {
+			return nil, err
+		}
+	}
+	snapshot, err := r.load(context.Background(), data)
+	if err != nil {
+		return nil, err
+	}
+	r.current.Store(snapshot)
+	r.handle = openapi3filter.NewValidatorHandle(snapshot.Validator)
+	return r, nil
+}
+
+// Snapshot returns the snapshot in use.
+func (r *Reloader) Snapshot() *Snapshot {
+	return r.current.Load().(*Snapshot)
+}
+
+// Middleware returns an http.Handler validating requests and responses of h
+// with the validator of the snapshot in use (see openapi3filter.ValidatorHandle).
+func (r *Reloader) Middleware(h http.Handler) http.Handler {
+	return r.handle.Middleware(h)
+}
+
+// Run checks the document every interval until ctx is done (see Check).
+// Errors are reported to the OnReload function.
+func (r *Reloader) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			var reloadErr *reloadError
+			if _, err := r.Check(ctx); err != nil && !errors.As(err, &reloadErr) && r.onReload != nil {
+				r.onReload(nil, err)
+			}
+		}
+	}
+}
+
+// Check loads the document if it changed since it was last loaded,
+// and reports whether a new snapshot is in use.
+func (r *Reloader) Check(ctx context.Context) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var data []byte
+	if r.remote {
+		var changed bool
+		var err error
+		if data, changed, err = r.fetch(ctx, true); err != nil || !changed {
+			return false, err
+		}
+	} else if !r.filesChanged() {
+		return false, nil
+	}
+	return r.reload(ctx, data)
+}
+
+// Reload loads the document, whether it changed or not,
+// and reports whether a new snapshot is in use.
+func (r *Reloader) Reload(ctx context.Context) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var data []byte
+	if r.remote {
+		var err error
+		if data, _, err = r.fetch(ctx, false); err != nil {
+			return false, err
+		}
+	}
+	return r.reload(ctx, data)
+}
+
+// reloadError is an error of load, already reported to the OnReload function.
+type reloadError struct {
+	err error
+}
+
+func (e *reloadError) Error() string { return e.err.Error() }
+
+func (e *reloadError) Unwrap() error { return e.err }
+
+func (r *Reloader) reload(ctx context.Context, data []byte) (bool, error) {
+	snapshot, err := r.load(ctx, data)
+	if err == nil {
+		r.current.Store(snapshot)
+		r.handle.Swap(snapshot.Validator)
+	}
+	if r.onReload != nil {
+		r.onReload(snapshot, err)
+	}
+	if err != nil {
+		return false, &reloadError{err: err}
+	}
+	return true, nil
+}
+
+// load loads, validates and builds a snapshot of the document,
+// whose data is given when it is remote.
+func (r *Reloader) load(ctx context.Context, data []byte) (*Snapshot, error) {
+	loader := r.newLoader()
+	if loader.Context == nil {
+		loader.Context = ctx
+	}
+	read := loader.ReadFromURIFunc
+	if read == nil {
+		read = openapi3.ReadFromURIs(openapi3.ReadFromHTTP(r.client), openapi3.ReadFromFile)
+	}
+	files := make(map[string]fileStamp)
+	var mu sync.Mutex
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, location *url.URL) ([]byte, error) {
+		if location.Host == "" && (location.Scheme == "" || location.Scheme == "file") {
+			// Stamped before reading, so that changes while reading are not missed
+			if stamp, err := statFile(location.Path); err == nil {
+				mu.Lock()
+				files[location.Path] = stamp
+				mu.Unlock()
+			}
+		}
+		return read(loader, location)
+	}
+
+	var doc *openapi3.T
+	var err error
+	if r.remote {
+		var location *url.URL
+		if location, err = url.Parse(r.location); err == nil {
+			doc, err = loader.LoadFromDataWithPath(data, location)
+		}
+	} else {
+		doc, err = loader.LoadFromFile(r.location)
+	}
+	// Failed loads are not retried until files change again
+	r.files = files
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", r.location, err)
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("validating %s: %w", r.location, err)
+	}
+	router, err := r.newRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("routing %s: %w", r.location, err)
+	}
+	return &Snapshot{
+		Doc:       doc,
+		Router:    router,
+		Validator: openapi3filter.NewValidator(router, r.validatorOptions...),
+		LoadedAt:  time.Now(),
+	}, nil
+}
+
+// fetch gets the remote document, and reports whether it changed since the last fetch.
+// Unless conditional, the document is always fetched.
+func (r *Reloader) fetch(ctx context.Context, conditional bool) ([]byte, bool, error) {
+	req, err := http.NewRequest(http.MethodGet, r.location, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req = req.WithContext(ctx)
+	if conditional {
+		if r.etag != "" {
+			req.Header.Set("If-None-Match", r.etag)
+		}
+		if r.lastModified != "" {
+			req.Header.Set("If-Modified-Since", r.lastModified)
+		}
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, false, nil
+	}
+	if resp.StatusCode > 399 {
+		return nil, false, fmt.Errorf("error loading %q: request returned status code %d", r.location, resp.StatusCode)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	r.etag, r.lastModified = resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	digest := sha256.Sum256(data)
+	changed := digest != r.digest
+	r.digest = digest
+	return data, changed, nil
+}
+
+// filesChanged reports whether any of the files read by the last load changed.
+func (r *Reloader) filesChanged() bool {
+	if len(r.files) == 0 {
+		return true
+	}
+	for path, stamp := range r.files {
+		if current, err := statFile(path); err != nil || current != stamp {
+			return true
+		}
+	}
+	return false
+}
+
+func statFile(path string) (fileStamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{modTime: info.ModTime().UnixNano(), size: info.Size()}, nil
+}
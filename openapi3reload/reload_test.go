@@ -0,0 +1,166 @@
+package openapi3reload_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3reload"
+)
+
+const root = `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema: {$ref: 'schemas.yaml#/Pets'}
+`
+
+func pets(maxItems int) string {
+	return "Pets: {type: array, items: {type: string}, maxItems: " + strconv.Itoa(maxItems) + "}\n"
+}
+
+func TestReloaderFiles(t *testing.T) {
+	dir := t.TempDir()
+	rootPath, schemasPath := filepath.Join(dir, "openapi.yaml"), filepath.Join(dir, "schemas.yaml")
+	require.NoError(t, ioutil.WriteFile(rootPath, []byte(root), 0o644))
+	write := func(data string, modTime time.Time) {
+		require.NoError(t, ioutil.WriteFile(schemasPath, []byte(data), 0o644))
+		require.NoError(t, os.Chtimes(schemasPath, modTime, modTime))
+	}
+	start := time.Now().Add(-time.Hour)
+	write(pets(1), start)
+
+	var reloads []error
+	reloader, err := openapi3reload.New(rootPath, openapi3reload.OnReload(func(snapshot *openapi3reload.Snapshot, err error) {
+		require.Equal(t, err == nil, snapshot != nil)
+		reloads = append(reloads, err)
+	}))
+	require.NoError(t, err)
+	maxItems := func() uint64 {
+		schema := reloader.Snapshot().Doc.Paths.Value("/pets").Get.Responses.Value("200").Value.Content["application/json"].Schema.Value
+		return *schema.MaxItems
+	}
+	require.Equal(t, uint64(1), maxItems())
+
+	reloaded, err := reloader.Check(context.Background())
+	require.NoError(t, err)
+	require.False(t, reloaded)
+
+	// Referenced files are watched too
+	write(pets(2), start.Add(time.Minute))
+	reloaded, err = reloader.Check(context.Background())
+	require.NoError(t, err)
+	require.True(t, reloaded)
+	require.Equal(t, uint64(2), maxItems())
+
+	// Invalid documents are reported and not used, once
+	write("Pets: {type: array, items: {type: string}, maxItems: -1}\n", start.Add(2*time.Minute))
+	reloaded, err = reloader.Check(context.Background())
+	require.Error(t, err)
+	require.False(t, reloaded)
+	require.Equal(t, uint64(2), maxItems())
+	reloaded, err = reloader.Check(context.Background())
+	require.NoError(t, err)
+	require.False(t, reloaded)
+
+	reloaded, err = reloader.Reload(context.Background())
+	require.Error(t, err)
+	require.False(t, reloaded)
+
+	write(pets(3), start.Add(3*time.Minute))
+	reloaded, err = reloader.Check(context.Background())
+	require.NoError(t, err)
+	require.True(t, reloaded)
+	require.Equal(t, uint64(3), maxItems())
+
+	require.Len(t, reloads, 4)
+	require.NoError(t, reloads[0])
+	require.Error(t, reloads[1])
+	require.Error(t, reloads[2])
+	require.NoError(t, reloads[3])
+}
+
+func TestReloaderURL(t *testing.T) {
+	var mu sync.Mutex
+	version, fetches := 1, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/openapi.yaml":
+			fetches++
+			etag := `"` + strconv.Itoa(version) + `"`
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			w.Write([]byte(root))
+		case "/schemas.yaml":
+			w.Write([]byte(pets(version)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	reloader, err := openapi3reload.New(server.URL + "/openapi.yaml")
+	require.NoError(t, err)
+
+	reloaded, err := reloader.Check(context.Background())
+	require.NoError(t, err)
+	require.False(t, reloaded)
+	require.Equal(t, 2, fetches)
+
+	mu.Lock()
+	version = 2
+	mu.Unlock()
+	// The root document is not changed, only its ETag
+	reloaded, err = reloader.Check(context.Background())
+	require.NoError(t, err)
+	require.False(t, reloaded)
+
+	reloaded, err = reloader.Reload(context.Background())
+	require.NoError(t, err)
+	require.True(t, reloaded)
+	schema := reloader.Snapshot().Doc.Paths.Value("/pets").Get.Responses.Value("200").Value.Content["application/json"].Schema.Value
+	require.Equal(t, uint64(2), *schema.MaxItems)
+}
+
+func TestReloaderMiddleware(t *testing.T) {
+	dir := t.TempDir()
+	rootPath := filepath.Join(dir, "openapi.yaml")
+	require.NoError(t, ioutil.WriteFile(rootPath, []byte(root), 0o644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "schemas.yaml"), []byte(pets(1)), 0o644))
+
+	reloader, err := openapi3reload.New(rootPath)
+	require.NoError(t, err)
+	handler := reloader.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pets", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/cats", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
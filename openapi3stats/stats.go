@@ -0,0 +1,272 @@
+package openapi3stats
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Stats is the inventory of a document.
+type Stats struct {
+	// Operations is the number of operations.
+	Operations int `json:"operations"`
+	// OperationsByMethod counts operations by their uppercase HTTP method.
+	OperationsByMethod map[string]int `json:"operationsByMethod"`
+	// OperationsByTag counts operations by tag. Operations without tags are not counted.
+	OperationsByTag map[string]int `json:"operationsByTag"`
+	// Untagged is the number of operations without tags.
+	Untagged int `json:"untagged"`
+	// Schemas lists the schemas of components, most referenced first.
+	Schemas []*SchemaUsage `json:"schemas"`
+	// UnusedComponents are JSON pointers to the components that are not reachable from
+	// the paths or security requirements of the document, e.g. "/components/schemas/Orphan".
+	UnusedComponents []string `json:"unusedComponents"`
+	// Deprecated are JSON pointers to the deprecated operations, parameters, headers and schemas.
+	Deprecated []string          `json:"deprecated"`
+	Security   *SecurityCoverage `json:"security"`
+}
+
+// SchemaUsage tells how many times a schema of components is referenced.
+type SchemaUsage struct {
+	Name string `json:"name"`
+	// References counts the $ref and discriminator mappings to the schema, anywhere in the document.
+	References int `json:"references"`
+}
+
+// SecurityCoverage tells which operations require authentication.
+type SecurityCoverage struct {
+	// Secured is the number of operations that every security requirement protects.
+	Secured int `json:"secured"`
+	// Unsecured are JSON pointers to the operations without security requirements
+	// or with an empty (anonymous) alternative, e.g. "/paths/~1pets/get".
+	Unsecured []string `json:"unsecured"`
+	// OperationsByScheme counts the operations each security scheme protects.
+	OperationsByScheme map[string]int `json:"operationsByScheme"`
+}
+
+// Analyze returns the inventory of doc.
+func Analyze(doc *openapi3.T) *Stats {
+	stats := &Stats{
+		OperationsByMethod: make(map[string]int),
+		OperationsByTag:    make(map[string]int),
+		Schemas:            []*SchemaUsage{},
+		UnusedComponents:   []string{},
+		Deprecated:         []string{},
+		Security: &SecurityCoverage{
+			Unsecured:          []string{},
+			OperationsByScheme: make(map[string]int),
+		},
+	}
+
+	doc.WalkOperations(func(path, method string, operation *openapi3.Operation) {
+		stats.Operations++
+		stats.OperationsByMethod[method]++
+		for _, tag := range operation.Tags {
+			stats.OperationsByTag[tag]++
+		}
+		if len(operation.Tags) == 0 {
+			stats.Untagged++
+		}
+
+		requirements := doc.Security
+		if operation.Security != nil {
+			requirements = *operation.Security
+		}
+		secured := len(requirements) != 0
+		schemes := make(map[string]bool)
+		for _, requirement := range requirements {
+			if len(requirement) == 0 {
+				secured = false
+			}
+			for scheme := range requirement {
+				schemes[scheme] = true
+			}
+		}
+		if secured {
+			stats.Security.Secured++
+		} else {
+			stats.Security.Unsecured = append(stats.Security.Unsecured, pointer("paths", path, strings.ToLower(method)))
+		}
+		for scheme := range schemes {
+			stats.Security.OperationsByScheme[scheme]++
+		}
+	})
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return stats
+	}
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return stats
+	}
+
+	collectDeprecated(root, "", false, &stats.Deprecated)
+	sort.Strings(stats.Deprecated)
+
+	references := make(map[string]int)
+	collectRefs(root, func(ref string) { references[strings.TrimPrefix(ref, "#")]++ })
+	for _, name := range doc.Components.Schemas.InSortedOrder() {
+		stats.Schemas = append(stats.Schemas, &SchemaUsage{
+			Name:       name,
+			References: references[pointer("components", "schemas", name)],
+		})
+	}
+	sort.SliceStable(stats.Schemas, func(i, j int) bool {
+		return stats.Schemas[i].References > stats.Schemas[j].References
+	})
+
+	stats.UnusedComponents = unusedComponents(doc, root)
+	return stats
+}
+
+// unusedComponents returns pointers to the components not reachable from
+// the paths and security requirements of doc, whose JSON form is root.
+func unusedComponents(doc *openapi3.T, root map[string]interface{}) []string {
+	components, _ := root["components"].(map[string]interface{})
+	rest := copyWithout(root, "components")
+
+	var pending []string
+	addRef := func(ref string) {
+		if strings.HasPrefix(ref, "#/components/") {
+			pending = append(pending, strings.TrimPrefix(ref, "#"))
+		}
+	}
+	collectRefs(rest, addRef)
+	addSecurity := func(requirements openapi3.SecurityRequirements) {
+		for _, requirement := range requirements {
+			for name := range requirement {
+				pending = append(pending, pointer("components", "securitySchemes", name))
+			}
+		}
+	}
+	addSecurity(doc.Security)
+	doc.WalkOperations(func(_, _ string, operation *openapi3.Operation) {
+		if operation.Security != nil {
+			addSecurity(*operation.Security)
+		}
+	})
+
+	used := make(map[string]bool)
+	for len(pending) != 0 {
+		ptr := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if used[ptr] {
+			continue
+		}
+		used[ptr] = true
+		tokens := strings.Split(strings.TrimPrefix(ptr, "/components/"), "/")
+		if len(tokens) < 2 {
+			continue
+		}
+		kind, _ := components[tokens[0]].(map[string]interface{})
+		collectRefs(kind[unescapeToken(tokens[1])], addRef)
+	}
+
+	unused := []string{}
+	for _, kind := range sortedNames(components) {
+		values, _ := components[kind].(map[string]interface{})
+		for _, name := range sortedNames(values) {
+			if ptr := pointer("components", kind, name); !used[ptr] {
+				unused = append(unused, ptr)
+			}
+		}
+	}
+	return unused
+}
+
+// collectDeprecated appends to deprecated the pointers to the objects of v marked as deprecated.
+// Example, default and enum values are not inspected.
+// names tells v is a map of names (e.g. properties) rather than an object.
+func collectDeprecated(v interface{}, location string, names bool, deprecated *[]string) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if d, ok := v["deprecated"].(bool); ok && d && !names {
+			*deprecated = append(*deprecated, location)
+		}
+		for key, value := range v {
+			switch {
+			case names:
+			case key == "example" || key == "default" || key == "enum":
+				continue
+			case key == "examples":
+				if examples, ok := value.(map[string]interface{}); ok {
+					for name, example := range examples {
+						if example, ok := example.(map[string]interface{}); ok {
+							example = copyWithout(example, "value")
+							collectDeprecated(example, location+pointer(key, name), false, deprecated)
+						}
+					}
+					continue
+				}
+			}
+			collectDeprecated(value, location+pointer(key), !names && key == "properties", deprecated)
+		}
+	case []interface{}:
+		for i, value := range v {
+			collectDeprecated(value, location+pointer(strconv.Itoa(i)), false, deprecated)
+		}
+	}
+}
+
+func copyWithout(m map[string]interface{}, key string) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if k != key {
+			c[k] = v
+		}
+	}
+	return c
+}
+
+// collectRefs calls addRef with the $ref values and discriminator mappings found in v.
+func collectRefs(v interface{}, addRef func(string)) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			addRef(ref)
+		}
+		if discriminator, ok := v["discriminator"].(map[string]interface{}); ok {
+			mapping, _ := discriminator["mapping"].(map[string]interface{})
+			for _, value := range mapping {
+				if ref, ok := value.(string); ok {
+					addRef(ref)
+				}
+			}
+		}
+		for _, value := range v {
+			collectRefs(value, addRef)
+		}
+	case []interface{}:
+		for _, value := range v {
+			collectRefs(value, addRef)
+		}
+	}
+}
+
+// sortedNames returns the sorted names of a JSON object.
+func sortedNames(value map[string]interface{}) []string {
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// pointer returns the JSON pointer made of the given reference tokens.
+func pointer(tokens ...string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1))
+	}
+	return b.String()
+}
+
+func unescapeToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+}
@@ -0,0 +1,115 @@
+package openapi3stats_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3stats"
+)
+
+const spec = `
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+security:
+- apiKey: []
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      security:
+      - {}
+      - apiKey: []
+      parameters:
+      - name: sort
+        in: query
+        deprecated: true
+        schema: {type: string}
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Pet'}
+    post:
+      tags: [pets, admin]
+      security:
+      - oauth: [write]
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+            examples:
+              rex:
+                value: {name: Rex, deprecated: true}
+      responses:
+        '201': {description: Created}
+  /pets/{id}:
+    delete:
+      deprecated: true
+      parameters:
+      - {$ref: '#/components/parameters/id'}
+      responses:
+        '204': {description: Deleted}
+components:
+  parameters:
+    id: {name: id, in: path, required: true, schema: {type: integer}}
+    limit: {name: limit, in: query, schema: {type: integer}}
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string}
+        deprecated: {type: boolean}
+        owner: {$ref: '#/components/schemas/Owner'}
+        tag: {type: string, deprecated: true}
+    Owner: {type: object}
+    Orphan: {type: object, deprecated: true}
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-API-Key}
+    oauth:
+      type: oauth2
+      flows:
+        clientCredentials: {tokenUrl: 'https://example.com/token', scopes: {write: Write}}
+    basic: {type: http, scheme: basic}
+`
+
+func TestAnalyze(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+
+	stats := openapi3stats.Analyze(doc)
+	require.Equal(t, 3, stats.Operations)
+	require.Equal(t, map[string]int{"GET": 1, "POST": 1, "DELETE": 1}, stats.OperationsByMethod)
+	require.Equal(t, map[string]int{"pets": 2, "admin": 1}, stats.OperationsByTag)
+	require.Equal(t, 1, stats.Untagged)
+	require.Equal(t, []*openapi3stats.SchemaUsage{
+		{Name: "Pet", References: 2},
+		{Name: "Owner", References: 1},
+		{Name: "Orphan", References: 0},
+	}, stats.Schemas)
+	require.Equal(t, []string{
+		"/components/parameters/limit",
+		"/components/schemas/Orphan",
+		"/components/securitySchemes/basic",
+	}, stats.UnusedComponents)
+	require.Equal(t, []string{
+		"/components/schemas/Orphan",
+		"/components/schemas/Pet/properties/tag",
+		"/paths/~1pets/get/parameters/0",
+		"/paths/~1pets~1{id}/delete",
+	}, stats.Deprecated)
+	require.Equal(t, &openapi3stats.SecurityCoverage{
+		Secured:            2,
+		Unsecured:          []string{"/paths/~1pets/get"},
+		OperationsByScheme: map[string]int{"apiKey": 2, "oauth": 1},
+	}, stats.Security)
+
+	data, err := json.Marshal(stats)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"unusedComponents":["/components/parameters/limit",`)
+}
@@ -0,0 +1,10 @@
+// Package openapi3ts generates TypeScript declarations from the component schemas
+// of an OpenAPIv3 document, so that web clients share the types of a Go server:
+//
+//	data, err := openapi3ts.Generate(doc)
+//
+// Objects become interfaces, enums become unions of literals (or TypeScript enums
+// with WithEnums), nullable schemas become unions with null, allOf becomes an intersection
+// and oneOf and anyOf become unions. References to component schemas become references
+// to their declarations. Declarations and properties are sorted by name.
+package openapi3ts
@@ -0,0 +1,47 @@
+// Code generated by kin-openapi from Pets 1.0.0. DO NOT EDIT.
+
+export type _2fa_method = "sms" | "totp";
+
+export interface Error {
+  code?: number | null;
+  message: string;
+  [key: string]: unknown;
+}
+
+export type Kind = "dog" | "cat" | "guinea-pig";
+
+export type Matrix = (number | "NaN" | null)[][];
+
+export type NewPet = Pet & {
+  code?: string;
+};
+
+export interface Owner {
+  address?: {
+    city?: string;
+  };
+  name?: string;
+}
+
+/**
+ * A pet of the store.
+ */
+export interface Pet {
+  attributes?: {
+    [key: string]: string;
+  };
+  readonly id: number;
+  kind: Kind;
+  /**
+   * The name of the pet.
+   */
+  name: string;
+  owner?: Owner | null;
+  tags?: string[];
+  /**
+   * @deprecated
+   */
+  "x-ray-id"?: string;
+}
+
+export type PetOrError = Pet | Error;
@@ -0,0 +1,361 @@
+package openapi3ts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const componentSchemasPrefix = "#/components/schemas/"
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Option allows tweaking the generation
+type Option func(*generator)
+
+// WithEnums declares the component schemas of string enums as TypeScript enums
+// rather than as unions of string literals.
+func WithEnums() Option {
+	return func(g *generator) {
+		g.enums = true
+	}
+}
+
+type generator struct {
+	enums bool
+
+	buf bytes.Buffer
+}
+
+// Generate returns the TypeScript declarations of the component schemas of doc.
+func Generate(doc *openapi3.T, opts ...Option) ([]byte, error) {
+	g := &generator{}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	g.buf.WriteString("// Code generated by kin-openapi")
+	if info := doc.Info; info != nil && info.Title != "" {
+		fmt.Fprintf(&g.buf, " from %s %s", info.Title, info.Version)
+	}
+	g.buf.WriteString(". DO NOT EDIT.\n")
+
+	schemas := doc.Components.Schemas
+	names := schemas.InSortedOrder()
+	declared := make(map[string]string, len(names))
+	for _, name := range names {
+		typeName := TypeName(name)
+		if other, ok := declared[typeName]; ok {
+			return nil, fmt.Errorf("schemas %q and %q are both declared as %s", other, name, typeName)
+		}
+		declared[typeName] = name
+	}
+
+	for _, name := range names {
+		ref := schemas.Value(name)
+		if ref == nil || ref.Value == nil {
+			return nil, fmt.Errorf("schema %q is not resolved", name)
+		}
+		g.buf.WriteByte('\n')
+		g.declare(TypeName(name), ref.Value)
+	}
+	return g.buf.Bytes(), nil
+}
+
+// TypeName returns the name of the declaration of the component schema of the given name:
+// the name with the characters not allowed in identifiers replaced with underscores.
+func TypeName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || r == '$' || unicode.IsLetter(r):
+		case unicode.IsDigit(r):
+			if i == 0 {
+				b.WriteByte('_')
+			}
+		default:
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+func (g *generator) declare(name string, schema *openapi3.Schema) {
+	g.buf.WriteString(docComment(schema, ""))
+	switch {
+	case g.enums && schema.Type == "string" && len(schema.Enum) != 0 && !schema.Nullable && isStringEnum(schema.Enum):
+		fmt.Fprintf(&g.buf, "export enum %s {\n", name)
+		used := make(map[string]int)
+		for _, value := range schema.Enum {
+			member := enumMemberName(value.(string))
+			if used[member]++; used[member] > 1 {
+				member = fmt.Sprintf("%s%d", member, used[member])
+			}
+			fmt.Fprintf(&g.buf, "  %s = %s,\n", member, literal(value))
+		}
+		g.buf.WriteString("}\n")
+	case isInterface(schema):
+		fmt.Fprintf(&g.buf, "export interface %s ", name)
+		g.buf.WriteString(g.objectType(schema, ""))
+		g.buf.WriteByte('\n')
+	default:
+		fmt.Fprintf(&g.buf, "export type %s = %s;\n", name, g.typeOf(schema, ""))
+	}
+}
+
+// isInterface tells whether schema is an object type which can be declared as an interface.
+func isInterface(schema *openapi3.Schema) bool {
+	return (schema.Type == "object" || (schema.Type == "" && schema.Properties.Len() != 0)) &&
+		len(schema.Enum) == 0 && !schema.Nullable &&
+		len(schema.AllOf) == 0 && len(schema.AnyOf) == 0 && len(schema.OneOf) == 0
+}
+
+func isStringEnum(values []interface{}) bool {
+	for _, value := range values {
+		if _, ok := value.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// enumMemberName returns the PascalCase name of the enum member of value.
+func enumMemberName(value string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range value {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if b.Len() == 0 && unicode.IsDigit(r) {
+			b.WriteByte('_')
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "Empty"
+	}
+	return b.String()
+}
+
+// refType returns the type of ref, indented by indent when it spans several lines.
+func (g *generator) refType(ref *openapi3.SchemaRef, indent string) string {
+	if ref == nil {
+		return "unknown"
+	}
+	if strings.HasPrefix(ref.Ref, componentSchemasPrefix) {
+		return TypeName(strings.TrimPrefix(ref.Ref, componentSchemasPrefix))
+	}
+	if ref.Value == nil {
+		return "unknown"
+	}
+	return g.typeOf(ref.Value, indent)
+}
+
+func (g *generator) typeOf(schema *openapi3.Schema, indent string) string {
+	var t string
+	nullable := schema.Nullable
+	switch {
+	case len(schema.Enum) != 0:
+		literals := make([]string, 0, len(schema.Enum))
+		for _, value := range schema.Enum {
+			if value == nil {
+				nullable = false
+			}
+			literals = append(literals, literal(value))
+		}
+		t = strings.Join(literals, " | ")
+	case len(schema.OneOf) != 0:
+		t = g.union(schema.OneOf, indent)
+	case len(schema.AnyOf) != 0:
+		t = g.union(schema.AnyOf, indent)
+	default:
+		var parts []string
+		for _, ref := range schema.AllOf {
+			parts = append(parts, g.refType(ref, indent))
+		}
+		if own := g.ownType(schema, indent); own != "unknown" || len(parts) == 0 {
+			parts = append(parts, own)
+		}
+		if len(parts) == 1 {
+			t = parts[0]
+		} else {
+			for i, part := range parts {
+				parts[i] = parenthesize(part)
+			}
+			t = strings.Join(parts, " & ")
+		}
+	}
+	if nullable && t != "unknown" {
+		t += " | null"
+	}
+	return t
+}
+
+func (g *generator) union(refs openapi3.SchemaRefs, indent string) string {
+	types := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		types = append(types, g.refType(ref, indent))
+	}
+	return strings.Join(types, " | ")
+}
+
+// ownType returns the type of schema ignoring its enum and subschemas.
+func (g *generator) ownType(schema *openapi3.Schema, indent string) string {
+	switch schema.Type {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		return parenthesize(g.refType(schema.Items, indent)) + "[]"
+	case "object":
+		return g.objectType(schema, indent)
+	case "":
+		if schema.Properties.Len() != 0 || schema.AdditionalProperties != nil {
+			return g.objectType(schema, indent)
+		}
+	}
+	return "unknown"
+}
+
+// objectType returns the type of the object schema describes, as a type literal
+// spanning several lines indented by indent.
+func (g *generator) objectType(schema *openapi3.Schema, indent string) string {
+	names := make([]string, 0, schema.Properties.Len())
+	for name := range schema.Properties.Map() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	var additional string
+	if ap := schema.AdditionalProperties; ap != nil {
+		additional = g.refType(ap, indent+"  ")
+	} else if allowed := schema.AdditionalPropertiesAllowed; allowed != nil && *allowed {
+		additional = "unknown"
+	}
+	if len(names) == 0 && additional == "" {
+		return "{}"
+	}
+	if len(names) != 0 && additional != "" {
+		// Index signatures constrain the types of the properties too
+		additional = "unknown"
+	}
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	inner := indent + "  "
+	for _, name := range names {
+		ref := schema.Properties.Value(name)
+		if ref != nil && ref.Value != nil && ref.Ref == "" {
+			b.WriteString(docComment(ref.Value, inner))
+		}
+		b.WriteString(inner)
+		if ref != nil && ref.Value != nil && ref.Value.ReadOnly {
+			b.WriteString("readonly ")
+		}
+		b.WriteString(propertyName(name))
+		if !required[name] {
+			b.WriteByte('?')
+		}
+		b.WriteString(": ")
+		b.WriteString(g.refType(ref, inner))
+		b.WriteString(";\n")
+	}
+	if additional != "" {
+		fmt.Fprintf(&b, "%s[key: string]: %s;\n", inner, additional)
+	}
+	b.WriteString(indent)
+	b.WriteString("}")
+	return b.String()
+}
+
+// docComment returns the JSDoc comment of schema, from its title, description
+// and deprecation, or "".
+func docComment(schema *openapi3.Schema, indent string) string {
+	var lines []string
+	for _, text := range []string{schema.Title, schema.Description} {
+		if text = strings.TrimSpace(text); text != "" {
+			lines = append(lines, strings.Split(text, "\n")...)
+		}
+	}
+	if schema.Deprecated {
+		lines = append(lines, "@deprecated")
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(indent)
+	b.WriteString("/**\n")
+	for _, line := range lines {
+		line = strings.Replace(line, "*/", "*\\/", -1)
+		b.WriteString(strings.TrimRight(indent+" * "+line, " "))
+		b.WriteByte('\n')
+	}
+	b.WriteString(indent)
+	b.WriteString(" */\n")
+	return b.String()
+}
+
+func propertyName(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	return literal(name)
+}
+
+// literal returns the TypeScript literal of a JSON value.
+func literal(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "unknown"
+	}
+	return string(data)
+}
+
+// parenthesize wraps unions and intersections so that they can be the operands of other types.
+func parenthesize(t string) string {
+	depth := 0
+	for i := 0; i < len(t); i++ {
+		switch t[i] {
+		case '{', '(', '[':
+			depth++
+		case '}', ')', ']':
+			depth--
+		case '"':
+			// Skip string literals, which are JSON-encoded
+			for i++; i < len(t) && t[i] != '"'; i++ {
+				if t[i] == '\\' {
+					i++
+				}
+			}
+		case '|', '&':
+			if depth == 0 {
+				return "(" + t + ")"
+			}
+		}
+	}
+	return t
+}
@@ -0,0 +1,48 @@
+package openapi3ts
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestGenerate(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("testdata/pets.yaml")
+	require.NoError(t, err)
+
+	data, err := Generate(doc)
+	require.NoError(t, err)
+	expected, err := ioutil.ReadFile("testdata/pets.ts")
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(data))
+}
+
+func TestGenerateEnums(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("testdata/pets.yaml")
+	require.NoError(t, err)
+
+	data, err := Generate(doc, WithEnums())
+	require.NoError(t, err)
+	require.Contains(t, string(data), `export enum Kind {
+  Dog = "dog",
+  Cat = "cat",
+  GuineaPig = "guinea-pig",
+}
+`)
+	require.Contains(t, string(data), `export enum _2fa_method {
+  Sms = "sms",
+  Totp = "totp",
+}
+`)
+}
+
+func TestGenerateConflictingNames(t *testing.T) {
+	doc := &openapi3.T{Components: openapi3.Components{Schemas: openapi3.NewSchemas().
+		With("pet-id", openapi3.NewStringSchema().NewRef()).
+		With("pet_id", openapi3.NewStringSchema().NewRef())}}
+	_, err := Generate(doc)
+	require.EqualError(t, err, `schemas "pet-id" and "pet_id" are both declared as pet_id`)
+}
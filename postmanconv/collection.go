@@ -0,0 +1,153 @@
+package postmanconv
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// SchemaV21 is the schema URL of Postman collections in format v2.1.
+const SchemaV21 = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// Collection is a Postman collection.
+type Collection struct {
+	Info     Info       `json:"info"`
+	Items    []*Item    `json:"item"`
+	Variable []Variable `json:"variable,omitempty"`
+}
+
+// Info describes a Collection.
+type Info struct {
+	PostmanID   string      `json:"_postman_id,omitempty"`
+	Name        string      `json:"name"`
+	Description Description `json:"description,omitempty"`
+	Schema      string      `json:"schema"`
+}
+
+// Item is either a folder, holding Items, or a request.
+type Item struct {
+	Name        string      `json:"name"`
+	Description Description `json:"description,omitempty"`
+	Items       []*Item     `json:"item,omitempty"`
+	Request     *Request    `json:"request,omitempty"`
+	Responses   []*Response `json:"response,omitempty"`
+}
+
+// IsFolder reports whether the item is a folder.
+func (item *Item) IsFolder() bool {
+	return item.Request == nil
+}
+
+// Request is a Postman request.
+type Request struct {
+	Method      string      `json:"method"`
+	Header      []KeyValue  `json:"header,omitempty"`
+	URL         URL         `json:"url"`
+	Body        *Body       `json:"body,omitempty"`
+	Description Description `json:"description,omitempty"`
+}
+
+// Response is a response saved along a request.
+type Response struct {
+	Name   string     `json:"name"`
+	Code   int        `json:"code,omitempty"`
+	Status string     `json:"status,omitempty"`
+	Header []KeyValue `json:"header,omitempty"`
+	Body   string     `json:"body,omitempty"`
+}
+
+// URL is the URL of a Request.
+// Path variables are written ":name" in Path.
+type URL struct {
+	Raw      string     `json:"raw"`
+	Host     []string   `json:"host,omitempty"`
+	Path     []string   `json:"path,omitempty"`
+	Query    []KeyValue `json:"query,omitempty"`
+	Variable []KeyValue `json:"variable,omitempty"`
+}
+
+// UnmarshalJSON sets URL to a copy of data, which may also be a plain string.
+func (u *URL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		*u = parseRawURL(raw)
+		return nil
+	}
+	type urlObject URL
+	return json.Unmarshal(data, (*urlObject)(u))
+}
+
+// parseRawURL splits a raw URL, as Postman does when the URL is given as a string.
+func parseRawURL(raw string) URL {
+	u := URL{Raw: raw}
+	rest := raw
+	if i := strings.Index(rest, "://"); i >= 0 {
+		rest = rest[i+3:]
+	}
+	var query string
+	if i := strings.IndexByte(rest, '?'); i >= 0 {
+		rest, query = rest[:i], rest[i+1:]
+	}
+	segments := strings.Split(rest, "/")
+	u.Host = []string{segments[0]}
+	for _, segment := range segments[1:] {
+		if segment != "" {
+			u.Path = append(u.Path, segment)
+		}
+	}
+	if query != "" {
+		for _, pair := range strings.Split(query, "&") {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) == 1 {
+				kv = append(kv, "")
+			}
+			u.Query = append(u.Query, KeyValue{Key: kv[0], Value: kv[1]})
+		}
+	}
+	return u
+}
+
+// KeyValue is a header, query parameter or path variable.
+type KeyValue struct {
+	Key         string      `json:"key"`
+	Value       string      `json:"value"`
+	Description Description `json:"description,omitempty"`
+	Disabled    bool        `json:"disabled,omitempty"`
+}
+
+// Body is the body of a Request.
+// Only the "raw" mode is produced by FromV3.
+type Body struct {
+	Mode       string      `json:"mode"`
+	Raw        string      `json:"raw,omitempty"`
+	URLEncoded []KeyValue  `json:"urlencoded,omitempty"`
+	FormData   []KeyValue  `json:"formdata,omitempty"`
+	Options    interface{} `json:"options,omitempty"`
+}
+
+// Variable is a collection variable.
+type Variable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Type  string `json:"type,omitempty"`
+}
+
+// Description is a description, which Postman writes
+// either as a string or as an object with a "content" field.
+type Description string
+
+// UnmarshalJSON sets Description to a copy of data.
+func (d *Description) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*d = Description(s)
+		return nil
+	}
+	var object struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*d = Description(object.Content)
+	return nil
+}
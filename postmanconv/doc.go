@@ -0,0 +1,9 @@
+// Package postmanconv converts OpenAPI v3 documents to Postman collections (format v2.1) and back.
+//
+// Exported collections hold a folder per operation tag, with requests whose bodies
+// and parameter values are taken from examples or generated from schemas.
+// Imported collections produce a skeleton document with an operation per request,
+// to be completed by hand.
+//
+// See https://schema.getpostman.com/json/collection/v2.1.0/collection.json
+package postmanconv
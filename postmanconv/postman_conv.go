@@ -0,0 +1,526 @@
+package postmanconv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// BaseURLVariable is the collection variable holding the URL of the API server.
+const BaseURLVariable = "baseUrl"
+
+// FromV3 converts an OpenAPIv3 document to a Postman collection.
+//
+// Operations are grouped in folders named after their first tag.
+// The URL of the first server is stored in the BaseURLVariable collection variable.
+// Parameter values and request bodies are taken from examples
+// or else generated from schemas, the "application/json" body being preferred.
+func FromV3(doc *openapi3.T) (*Collection, error) {
+	c := &Collection{Info: Info{Schema: SchemaV21}}
+	if info := doc.Info; info != nil {
+		c.Info.Name = info.Title
+		c.Info.Description = Description(info.Description)
+	}
+	baseURL := ""
+	if len(doc.Servers) != 0 {
+		u, err := doc.Servers[0].ResolveURL(nil)
+		if err != nil {
+			return nil, err
+		}
+		baseURL = strings.TrimSuffix(u, "/")
+	}
+	c.Variable = []Variable{{Key: BaseURLVariable, Value: baseURL, Type: "string"}}
+
+	folders := make(map[string]*Item)
+	paths := make([]string, 0, doc.Paths.Len())
+	for path := range doc.Paths.Map() {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		pathItem := doc.Paths.Value(path)
+		methods := make([]string, 0, len(pathItem.Operations()))
+		for method := range pathItem.Operations() {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			operation := pathItem.GetOperation(method)
+			item, err := fromV3Operation(path, method, pathItem, operation)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			if len(operation.Tags) == 0 {
+				c.Items = append(c.Items, item)
+				continue
+			}
+			tag := operation.Tags[0]
+			folder, ok := folders[tag]
+			if !ok {
+				folder = &Item{Name: tag}
+				if t := doc.Tags.Get(tag); t != nil {
+					folder.Description = Description(t.Description)
+				}
+				folders[tag] = folder
+				c.Items = append(c.Items, folder)
+			}
+			folder.Items = append(folder.Items, item)
+		}
+	}
+	return c, nil
+}
+
+func fromV3Operation(path, method string, pathItem *openapi3.PathItem, operation *openapi3.Operation) (*Item, error) {
+	name := operation.Summary
+	if name == "" {
+		name = operation.OperationID
+	}
+	if name == "" {
+		name = method + " " + path
+	}
+	request := &Request{
+		Method:      method,
+		Description: Description(operation.Description),
+	}
+
+	parameters := make(openapi3.Parameters, 0, len(pathItem.Parameters)+len(operation.Parameters))
+	for _, parameterRef := range pathItem.Parameters {
+		if p := parameterRef.Value; p != nil && operation.Parameters.GetByInAndName(p.In, p.Name) == nil {
+			parameters = append(parameters, parameterRef)
+		}
+	}
+	parameters = append(parameters, operation.Parameters...)
+
+	var cookies []string
+	for _, parameterRef := range parameters {
+		parameter := parameterRef.Value
+		if parameter == nil {
+			continue
+		}
+		kv := KeyValue{
+			Key:         parameter.Name,
+			Value:       formatValue(parameterExample(parameter)),
+			Description: Description(parameter.Description),
+		}
+		switch parameter.In {
+		case openapi3.ParameterInPath:
+			request.URL.Variable = append(request.URL.Variable, kv)
+		case openapi3.ParameterInQuery:
+			kv.Disabled = !parameter.Required
+			request.URL.Query = append(request.URL.Query, kv)
+		case openapi3.ParameterInHeader:
+			request.Header = append(request.Header, kv)
+		case openapi3.ParameterInCookie:
+			cookies = append(cookies, kv.Key+"="+kv.Value)
+		}
+	}
+	if len(cookies) != 0 {
+		request.Header = append(request.Header, KeyValue{Key: "Cookie", Value: strings.Join(cookies, "; ")})
+	}
+
+	request.URL.Host = []string{"{{" + BaseURLVariable + "}}"}
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if segment != "" {
+			request.URL.Path = append(request.URL.Path, toPostmanVariables(segment))
+		}
+	}
+	request.URL.Raw = "{{" + BaseURLVariable + "}}" + toPostmanVariables(path)
+	var query []string
+	for _, kv := range request.URL.Query {
+		if !kv.Disabled {
+			query = append(query, kv.Key+"="+kv.Value)
+		}
+	}
+	if len(query) != 0 {
+		request.URL.Raw += "?" + strings.Join(query, "&")
+	}
+
+	if requestBody := operation.RequestBody; requestBody != nil && requestBody.Value != nil {
+		if contentTypes := requestBody.Value.Content.MediaTypes(); len(contentTypes) != 0 {
+			contentType := contentTypes[0]
+			body, err := fromV3MediaType(contentType, requestBody.Value.Content[contentType])
+			if err != nil {
+				return nil, err
+			}
+			request.Body = body
+			request.Header = append(request.Header, KeyValue{Key: "Content-Type", Value: contentType})
+		}
+	}
+
+	return &Item{Name: name, Request: request}, nil
+}
+
+// toPostmanVariables rewrites path template variables "{name}" as Postman path variables ":name".
+func toPostmanVariables(path string) string {
+	path = strings.Replace(path, "{", ":", -1)
+	return strings.Replace(path, "}", "", -1)
+}
+
+func fromV3MediaType(contentType string, mediaType *openapi3.MediaType) (*Body, error) {
+	value := mediaTypeExample(mediaType)
+	switch {
+	case contentType == "application/x-www-form-urlencoded" || contentType == "multipart/form-data":
+		var fields []KeyValue
+		if m, ok := value.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(m))
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fields = append(fields, KeyValue{Key: k, Value: formatValue(m[k])})
+			}
+		}
+		if contentType == "multipart/form-data" {
+			return &Body{Mode: "formdata", FormData: fields}, nil
+		}
+		return &Body{Mode: "urlencoded", URLEncoded: fields}, nil
+	case strings.Contains(contentType, "json"):
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return &Body{
+			Mode:    "raw",
+			Raw:     string(data),
+			Options: map[string]interface{}{"raw": map[string]interface{}{"language": "json"}},
+		}, nil
+	default:
+		return &Body{Mode: "raw", Raw: formatValue(value)}, nil
+	}
+}
+
+func parameterExample(parameter *openapi3.Parameter) interface{} {
+	if parameter.Example != nil {
+		return parameter.Example
+	}
+	if v := firstExample(parameter.Examples); v != nil {
+		return v
+	}
+	if parameter.Schema != nil {
+		return parameter.Schema.Value.GenerateExample(openapi3.VisitAsRequest())
+	}
+	return nil
+}
+
+func mediaTypeExample(mediaType *openapi3.MediaType) interface{} {
+	if mediaType == nil {
+		return nil
+	}
+	if mediaType.Example != nil {
+		return mediaType.Example
+	}
+	if v := firstExample(mediaType.Examples); v != nil {
+		return v
+	}
+	if mediaType.Schema != nil {
+		return mediaType.Schema.Value.GenerateExample(openapi3.VisitAsRequest())
+	}
+	return nil
+}
+
+// firstExample returns the value of the first example, in lexical order of their names.
+func firstExample(examples openapi3.Examples) interface{} {
+	names := make([]string, 0, len(examples))
+	for name := range examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if example := examples[name]; example != nil && example.Value != nil && example.Value.Value != nil {
+			return example.Value.Value
+		}
+	}
+	return nil
+}
+
+// formatValue formats a parameter value: arrays as comma-separated values
+// and objects as JSON.
+func formatValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case []interface{}:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, formatValue(item))
+		}
+		return strings.Join(items, ",")
+	case map[string]interface{}:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// ToV3 converts a Postman collection to a skeleton OpenAPIv3 document.
+//
+// Each request becomes an operation tagged with the name of its folder.
+// Paths are made of the request URL path, where Postman variables (":name" and "{{name}}")
+// become path parameters. Query parameters and headers become parameters
+// and bodies and saved responses become request bodies and responses,
+// with schemas inferred from their JSON values.
+// Of several requests with the same method and path, the first one is kept.
+func ToV3(c *Collection) (*openapi3.T, error) {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:       c.Info.Name,
+			Description: string(c.Info.Description),
+			Version:     "1.0.0",
+		},
+		Paths: openapi3.NewPaths(),
+	}
+	for _, variable := range c.Variable {
+		if variable.Key == BaseURLVariable && variable.Value != "" {
+			doc.AddServer(&openapi3.Server{URL: variable.Value})
+		}
+	}
+	if err := toV3Items(doc, "", c.Items); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func toV3Items(doc *openapi3.T, tag string, items []*Item) error {
+	for _, item := range items {
+		if item.IsFolder() {
+			if doc.Tags.Get(item.Name) == nil {
+				doc.Tags = append(doc.Tags, &openapi3.Tag{Name: item.Name, Description: string(item.Description)})
+			}
+			if err := toV3Items(doc, item.Name, item.Items); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := toV3Request(doc, tag, item); err != nil {
+			return fmt.Errorf("%s: %w", item.Name, err)
+		}
+	}
+	return nil
+}
+
+func toV3Request(doc *openapi3.T, tag string, item *Item) error {
+	request := item.Request
+	method := strings.ToUpper(request.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	operation := openapi3.NewOperation()
+	operation.Summary = item.Name
+	operation.Description = string(request.Description)
+	if operation.Description == "" {
+		operation.Description = string(item.Description)
+	}
+	if tag != "" {
+		operation.Tags = []string{tag}
+	}
+
+	variables := make(map[string]KeyValue, len(request.URL.Variable))
+	for _, kv := range request.URL.Variable {
+		variables[kv.Key] = kv
+	}
+	segments := make([]string, 0, len(request.URL.Path))
+	for _, segment := range request.URL.Path {
+		name := ""
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			name = segment[1:]
+		case strings.HasPrefix(segment, "{{") && strings.HasSuffix(segment, "}}"):
+			name = segment[2 : len(segment)-2]
+		}
+		if name == "" {
+			segments = append(segments, segment)
+			continue
+		}
+		segments = append(segments, "{"+name+"}")
+		if operation.Parameters.GetByInAndName(openapi3.ParameterInPath, name) != nil {
+			continue
+		}
+		parameter := openapi3.NewPathParameter(name).WithSchema(openapi3.NewStringSchema())
+		if kv, ok := variables[name]; ok {
+			parameter.Description = string(kv.Description)
+			if kv.Value != "" {
+				parameter.Example = kv.Value
+			}
+		}
+		operation.AddParameter(parameter)
+	}
+	path := "/" + strings.Join(segments, "/")
+
+	if pathItem := doc.Paths.Value(path); pathItem != nil && pathItem.GetOperation(method) != nil {
+		return nil
+	}
+
+	for _, kv := range request.URL.Query {
+		if operation.Parameters.GetByInAndName(openapi3.ParameterInQuery, kv.Key) != nil {
+			continue
+		}
+		parameter := openapi3.NewQueryParameter(kv.Key).WithSchema(openapi3.NewStringSchema())
+		parameter.Description = string(kv.Description)
+		if kv.Value != "" {
+			parameter.Example = kv.Value
+		}
+		operation.AddParameter(parameter)
+	}
+
+	contentType := ""
+	for _, kv := range request.Header {
+		switch http.CanonicalHeaderKey(kv.Key) {
+		case "Content-Type":
+			contentType = kv.Value
+			continue
+		case "Accept", "Authorization", "Cookie":
+			continue
+		}
+		if operation.Parameters.GetByInAndName(openapi3.ParameterInHeader, kv.Key) != nil {
+			continue
+		}
+		parameter := openapi3.NewHeaderParameter(kv.Key).WithSchema(openapi3.NewStringSchema())
+		parameter.Description = string(kv.Description)
+		if kv.Value != "" {
+			parameter.Example = kv.Value
+		}
+		operation.AddParameter(parameter)
+	}
+
+	if body := request.Body; body != nil {
+		if content := toV3Body(body, contentType); content != nil {
+			operation.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithContent(content)}
+		}
+	}
+
+	operation.Responses = &openapi3.Responses{}
+	for _, response := range item.Responses {
+		code := response.Code
+		if code == 0 {
+			continue
+		}
+		status := fmt.Sprintf("%d", code)
+		if operation.Responses.Get(code) != nil {
+			continue
+		}
+		description := response.Status
+		if description == "" {
+			description = response.Name
+		}
+		r := openapi3.NewResponse().WithDescription(description)
+		responseContentType := ""
+		for _, kv := range response.Header {
+			if http.CanonicalHeaderKey(kv.Key) == "Content-Type" {
+				responseContentType = kv.Value
+			}
+		}
+		if response.Body != "" {
+			r.Content = toV3Raw(response.Body, responseContentType)
+		}
+		operation.Responses.Set(status, &openapi3.ResponseRef{Value: r})
+	}
+	if operation.Responses.Len() == 0 {
+		operation.Responses.Set("default", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Default response")})
+	}
+
+	doc.AddOperation(path, method, operation)
+	return nil
+}
+
+func toV3Body(body *Body, contentType string) openapi3.Content {
+	switch body.Mode {
+	case "urlencoded", "formdata":
+		fields := body.URLEncoded
+		if body.Mode == "formdata" {
+			fields = body.FormData
+			contentType = "multipart/form-data"
+		} else {
+			contentType = "application/x-www-form-urlencoded"
+		}
+		schema := openapi3.NewObjectSchema()
+		example := make(map[string]interface{}, len(fields))
+		for _, kv := range fields {
+			schema.WithProperty(kv.Key, openapi3.NewStringSchema())
+			example[kv.Key] = kv.Value
+		}
+		mediaType := openapi3.NewMediaType().WithSchema(schema)
+		mediaType.Example = example
+		return openapi3.Content{contentType: mediaType}
+	case "raw":
+		if contentType == "" {
+			if options, ok := body.Options.(map[string]interface{}); ok {
+				if raw, ok := options["raw"].(map[string]interface{}); ok && raw["language"] == "json" {
+					contentType = "application/json"
+				}
+			}
+		}
+		return toV3Raw(body.Raw, contentType)
+	}
+	return nil
+}
+
+// toV3Raw returns the content for a raw body, inferring a schema when it is valid JSON.
+func toV3Raw(raw, contentType string) openapi3.Content {
+	var value interface{}
+	isJSON := json.Unmarshal([]byte(raw), &value) == nil
+	if contentType == "" {
+		contentType = "text/plain"
+		if isJSON {
+			contentType = "application/json"
+		}
+	}
+	mediaType := openapi3.NewMediaType()
+	if isJSON && strings.Contains(contentType, "json") {
+		mediaType.WithSchema(inferSchema(value))
+		mediaType.Example = value
+	} else {
+		mediaType.WithSchema(openapi3.NewStringSchema())
+		mediaType.Example = raw
+	}
+	return openapi3.Content{contentType: mediaType}
+}
+
+// inferSchema returns a schema describing value.
+func inferSchema(value interface{}) *openapi3.Schema {
+	switch value := value.(type) {
+	case bool:
+		return openapi3.NewBoolSchema()
+	case float64:
+		if value == float64(int64(value)) {
+			return openapi3.NewIntegerSchema()
+		}
+		return openapi3.NewFloat64Schema()
+	case string:
+		return openapi3.NewStringSchema()
+	case []interface{}:
+		items := openapi3.NewSchema()
+		if len(value) != 0 {
+			items = inferSchema(value[0])
+		}
+		return openapi3.NewArraySchema().WithItems(items)
+	case map[string]interface{}:
+		schema := openapi3.NewObjectSchema()
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			schema.WithProperty(k, inferSchema(value[k]))
+		}
+		return schema
+	default:
+		// null or unknown
+		schema := openapi3.NewSchema()
+		schema.Nullable = true
+		return schema
+	}
+}
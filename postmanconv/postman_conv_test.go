@@ -0,0 +1,171 @@
+package postmanconv
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestFromV3(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.3
+info: {title: Pets, version: '1.0', description: Pet store}
+servers:
+- url: https://{host}/v1
+  variables:
+    host: {default: api.example.com}
+tags:
+- {name: pets, description: Everything about pets}
+paths:
+  /pets/{id}:
+    parameters:
+    - {name: id, in: path, required: true, schema: {type: integer, minimum: 1}}
+    put:
+      summary: Update a pet
+      tags: [pets]
+      parameters:
+      - {name: dryRun, in: query, schema: {type: boolean}}
+      - {name: X-Request-ID, in: header, required: true, schema: {type: string, format: uuid}}
+      - {name: session, in: cookie, schema: {type: string}, example: abc}
+      requestBody:
+        content:
+          application/xml:
+            schema: {type: object}
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                id: {type: integer, readOnly: true}
+                name: {type: string, example: Fido}
+                tags: {type: array, items: {type: string}}
+      responses:
+        '200': {description: OK}
+  /health:
+    get:
+      operationId: health
+      responses:
+        '200': {description: OK}
+`)
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	require.NoError(t, err)
+
+	c, err := FromV3(doc)
+	require.NoError(t, err)
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+{
+  "info": {
+    "name": "Pets",
+    "description": "Pet store",
+    "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+  },
+  "variable": [{"key": "baseUrl", "value": "https://api.example.com/v1", "type": "string"}],
+  "item": [
+    {
+      "name": "health",
+      "request": {
+        "method": "GET",
+        "url": {"raw": "{{baseUrl}}/health", "host": ["{{baseUrl}}"], "path": ["health"]}
+      }
+    },
+    {
+      "name": "pets",
+      "description": "Everything about pets",
+      "item": [
+        {
+          "name": "Update a pet",
+          "request": {
+            "method": "PUT",
+            "header": [
+              {"key": "X-Request-ID", "value": "3fa85f64-5717-4562-b3fc-2c963f66afa6"},
+              {"key": "Cookie", "value": "session=abc"},
+              {"key": "Content-Type", "value": "application/json"}
+            ],
+            "url": {
+              "raw": "{{baseUrl}}/pets/:id",
+              "host": ["{{baseUrl}}"],
+              "path": ["pets", ":id"],
+              "query": [{"key": "dryRun", "value": "true", "disabled": true}],
+              "variable": [{"key": "id", "value": "1"}]
+            },
+            "body": {
+              "mode": "raw",
+              "raw": "{\n  \"name\": \"Fido\",\n  \"tags\": [\n    \"string\"\n  ]\n}",
+              "options": {"raw": {"language": "json"}}
+            }
+          }
+        }
+      ]
+    }
+  ]
+}
+`, string(data))
+}
+
+func TestToV3(t *testing.T) {
+	collection := []byte(`
+{
+  "info": {"name": "Imported", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+  "variable": [{"key": "baseUrl", "value": "https://api.example.com"}],
+  "item": [
+    {
+      "name": "Users",
+      "description": {"content": "User management"},
+      "item": [
+        {
+          "name": "Create user",
+          "request": {
+            "method": "post",
+            "header": [{"key": "Content-Type", "value": "application/json"}, {"key": "X-Trace", "value": "1"}],
+            "url": {"raw": "{{baseUrl}}/orgs/:org/users", "host": ["{{baseUrl}}"], "path": ["orgs", ":org", "users"],
+                    "variable": [{"key": "org", "value": "acme"}]},
+            "body": {"mode": "raw", "raw": "{\"name\": \"Bob\", \"age\": 42, \"tags\": [\"a\"]}"}
+          },
+          "response": [
+            {"name": "Created", "code": 201, "status": "Created", "header": [{"key": "Content-Type", "value": "application/json"}], "body": "{\"id\": 1.5}"}
+          ]
+        }
+      ]
+    },
+    {
+      "name": "Search",
+      "request": {"method": "GET", "url": "https://api.example.com/search?q=cats"}
+    }
+  ]
+}
+`)
+	var c Collection
+	err := json.Unmarshal(collection, &c)
+	require.NoError(t, err)
+
+	doc, err := ToV3(&c)
+	require.NoError(t, err)
+	err = doc.Validate(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, "https://api.example.com", doc.Servers[0].URL)
+	require.Equal(t, "User management", doc.Tags.Get("Users").Description)
+
+	operation := doc.Paths.Value("/orgs/{org}/users").Post
+	require.NotNil(t, operation)
+	require.Equal(t, []string{"Users"}, operation.Tags)
+	require.Equal(t, "acme", operation.Parameters.GetByInAndName("path", "org").Example)
+	require.NotNil(t, operation.Parameters.GetByInAndName("header", "X-Trace"))
+	schema := operation.RequestBody.Value.Content.Get("application/json").Schema.Value
+	require.Equal(t, "integer", schema.Properties.Value("age").Value.Type)
+	require.Equal(t, "array", schema.Properties.Value("tags").Value.Type)
+	response := operation.Responses.Get(201).Value
+	require.Equal(t, "Created", *response.Description)
+	require.Equal(t, "number", response.Content.Get("application/json").Schema.Value.Properties.Value("id").Value.Type)
+
+	operation = doc.Paths.Value("/search").Get
+	require.NotNil(t, operation)
+	require.Equal(t, "cats", operation.Parameters.GetByInAndName("query", "q").Example)
+	require.NotNil(t, operation.Responses.Default())
+}
@@ -0,0 +1,252 @@
+package protoconv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// FileDescriptorSet mirrors google.protobuf.FileDescriptorSet.
+type FileDescriptorSet struct {
+	File []*FileDescriptor `json:"file,omitempty"`
+}
+
+// FileDescriptor mirrors google.protobuf.FileDescriptorProto.
+type FileDescriptor struct {
+	Name        string               `json:"name,omitempty"`
+	Package     string               `json:"package,omitempty"`
+	MessageType []*MessageDescriptor `json:"messageType,omitempty"`
+	EnumType    []*EnumDescriptor    `json:"enumType,omitempty"`
+	Service     []*ServiceDescriptor `json:"service,omitempty"`
+	Syntax      string               `json:"syntax,omitempty"`
+}
+
+// MessageDescriptor mirrors google.protobuf.DescriptorProto.
+type MessageDescriptor struct {
+	Name       string               `json:"name,omitempty"`
+	Field      []*FieldDescriptor   `json:"field,omitempty"`
+	NestedType []*MessageDescriptor `json:"nestedType,omitempty"`
+	EnumType   []*EnumDescriptor    `json:"enumType,omitempty"`
+	Options    *MessageOptions      `json:"options,omitempty"`
+}
+
+// MessageOptions mirrors google.protobuf.MessageOptions.
+type MessageOptions struct {
+	Deprecated bool `json:"deprecated,omitempty"`
+	MapEntry   bool `json:"mapEntry,omitempty"`
+}
+
+// FieldDescriptor mirrors google.protobuf.FieldDescriptorProto.
+type FieldDescriptor struct {
+	Name     string        `json:"name,omitempty"`
+	Number   int32         `json:"number,omitempty"`
+	Label    FieldLabel    `json:"label,omitempty"`
+	Type     FieldType     `json:"type,omitempty"`
+	TypeName string        `json:"typeName,omitempty"`
+	JSONName string        `json:"jsonName,omitempty"`
+	Options  *FieldOptions `json:"options,omitempty"`
+}
+
+// FieldOptions mirrors google.protobuf.FieldOptions.
+type FieldOptions struct {
+	Deprecated bool `json:"deprecated,omitempty"`
+}
+
+// EnumDescriptor mirrors google.protobuf.EnumDescriptorProto.
+type EnumDescriptor struct {
+	Name  string                 `json:"name,omitempty"`
+	Value []*EnumValueDescriptor `json:"value,omitempty"`
+}
+
+// EnumValueDescriptor mirrors google.protobuf.EnumValueDescriptorProto.
+type EnumValueDescriptor struct {
+	Name   string `json:"name,omitempty"`
+	Number int32  `json:"number,omitempty"`
+}
+
+// ServiceDescriptor mirrors google.protobuf.ServiceDescriptorProto.
+type ServiceDescriptor struct {
+	Name   string              `json:"name,omitempty"`
+	Method []*MethodDescriptor `json:"method,omitempty"`
+}
+
+// MethodDescriptor mirrors google.protobuf.MethodDescriptorProto.
+type MethodDescriptor struct {
+	Name            string         `json:"name,omitempty"`
+	InputType       string         `json:"inputType,omitempty"`
+	OutputType      string         `json:"outputType,omitempty"`
+	Options         *MethodOptions `json:"options,omitempty"`
+	ClientStreaming bool           `json:"clientStreaming,omitempty"`
+	ServerStreaming bool           `json:"serverStreaming,omitempty"`
+}
+
+// MethodOptions mirrors google.protobuf.MethodOptions
+// along with its google.api.http extension.
+type MethodOptions struct {
+	Deprecated bool      `json:"deprecated,omitempty"`
+	HTTP       *HTTPRule `json:"[google.api.http],omitempty"`
+}
+
+// HTTPRule mirrors google.api.HttpRule.
+type HTTPRule struct {
+	Selector           string             `json:"selector,omitempty"`
+	Get                string             `json:"get,omitempty"`
+	Put                string             `json:"put,omitempty"`
+	Post               string             `json:"post,omitempty"`
+	Delete             string             `json:"delete,omitempty"`
+	Patch              string             `json:"patch,omitempty"`
+	Custom             *CustomHTTPPattern `json:"custom,omitempty"`
+	Body               string             `json:"body,omitempty"`
+	ResponseBody       string             `json:"responseBody,omitempty"`
+	AdditionalBindings []*HTTPRule        `json:"additionalBindings,omitempty"`
+}
+
+// CustomHTTPPattern mirrors google.api.CustomHttpPattern.
+type CustomHTTPPattern struct {
+	Kind string `json:"kind,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+// Pattern returns the HTTP method and path template of the rule.
+func (rule *HTTPRule) Pattern() (method, path string) {
+	switch {
+	case rule.Get != "":
+		return "GET", rule.Get
+	case rule.Put != "":
+		return "PUT", rule.Put
+	case rule.Post != "":
+		return "POST", rule.Post
+	case rule.Delete != "":
+		return "DELETE", rule.Delete
+	case rule.Patch != "":
+		return "PATCH", rule.Patch
+	case rule.Custom != nil:
+		return strings.ToUpper(rule.Custom.Kind), rule.Custom.Path
+	}
+	return "", ""
+}
+
+// FieldLabel mirrors google.protobuf.FieldDescriptorProto.Label.
+type FieldLabel int32
+
+// Field labels
+const (
+	LabelOptional FieldLabel = 1
+	LabelRequired FieldLabel = 2
+	LabelRepeated FieldLabel = 3
+)
+
+var fieldLabelNames = map[string]FieldLabel{
+	"LABEL_OPTIONAL": LabelOptional,
+	"LABEL_REQUIRED": LabelRequired,
+	"LABEL_REPEATED": LabelRepeated,
+}
+
+// UnmarshalJSON accepts enum names as well as numbers.
+func (label *FieldLabel) UnmarshalJSON(data []byte) error {
+	n, err := unmarshalEnum(data, "label", func(name string) (int32, bool) {
+		v, ok := fieldLabelNames[name]
+		return int32(v), ok
+	})
+	*label = FieldLabel(n)
+	return err
+}
+
+// FieldType mirrors google.protobuf.FieldDescriptorProto.Type.
+type FieldType int32
+
+// Field types
+const (
+	TypeDouble   FieldType = 1
+	TypeFloat    FieldType = 2
+	TypeInt64    FieldType = 3
+	TypeUint64   FieldType = 4
+	TypeInt32    FieldType = 5
+	TypeFixed64  FieldType = 6
+	TypeFixed32  FieldType = 7
+	TypeBool     FieldType = 8
+	TypeString   FieldType = 9
+	TypeGroup    FieldType = 10
+	TypeMessage  FieldType = 11
+	TypeBytes    FieldType = 12
+	TypeUint32   FieldType = 13
+	TypeEnum     FieldType = 14
+	TypeSfixed32 FieldType = 15
+	TypeSfixed64 FieldType = 16
+	TypeSint32   FieldType = 17
+	TypeSint64   FieldType = 18
+)
+
+var fieldTypeNames = map[string]FieldType{
+	"TYPE_DOUBLE":   TypeDouble,
+	"TYPE_FLOAT":    TypeFloat,
+	"TYPE_INT64":    TypeInt64,
+	"TYPE_UINT64":   TypeUint64,
+	"TYPE_INT32":    TypeInt32,
+	"TYPE_FIXED64":  TypeFixed64,
+	"TYPE_FIXED32":  TypeFixed32,
+	"TYPE_BOOL":     TypeBool,
+	"TYPE_STRING":   TypeString,
+	"TYPE_GROUP":    TypeGroup,
+	"TYPE_MESSAGE":  TypeMessage,
+	"TYPE_BYTES":    TypeBytes,
+	"TYPE_UINT32":   TypeUint32,
+	"TYPE_ENUM":     TypeEnum,
+	"TYPE_SFIXED32": TypeSfixed32,
+	"TYPE_SFIXED64": TypeSfixed64,
+	"TYPE_SINT32":   TypeSint32,
+	"TYPE_SINT64":   TypeSint64,
+}
+
+// UnmarshalJSON accepts enum names as well as numbers.
+func (typ *FieldType) UnmarshalJSON(data []byte) error {
+	n, err := unmarshalEnum(data, "type", func(name string) (int32, bool) {
+		v, ok := fieldTypeNames[name]
+		return int32(v), ok
+	})
+	*typ = FieldType(n)
+	return err
+}
+
+func unmarshalEnum(data []byte, what string, lookup func(string) (int32, bool)) (int32, error) {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		n, ok := lookup(name)
+		if !ok {
+			return 0, fmt.Errorf("unknown field %s %q", what, name)
+		}
+		return n, nil
+	}
+	var n int32
+	if err := json.Unmarshal(data, &n); err != nil {
+		return 0, fmt.Errorf("invalid field %s: %w", what, err)
+	}
+	return n, nil
+}
+
+// ParseDescriptorSet decodes a FileDescriptorSet from either its binary
+// or its JSON representation.
+func ParseDescriptorSet(data []byte) (*FileDescriptorSet, error) {
+	set := &FileDescriptorSet{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) != 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, set); err != nil {
+			return nil, err
+		}
+		return set, nil
+	}
+	if err := decodeFileDescriptorSet(data, set); err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %w", err)
+	}
+	return set, nil
+}
+
+// ReadDescriptorSetFile reads and decodes the FileDescriptorSet at path.
+func ReadDescriptorSetFile(path string) (*FileDescriptorSet, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDescriptorSet(data)
+}
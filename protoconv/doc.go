@@ -0,0 +1,17 @@
+// Package protoconv generates OpenAPI v3 documents from protobuf descriptors,
+// describing the JSON transcoding of gRPC services annotated with google.api.http rules
+// (as served by grpc-gateway for instance).
+//
+// Descriptor sets are read either in the binary format written by
+// `protoc --include_imports --descriptor_set_out=...`
+// or in their JSON representation, as written by `buf build -o descriptors.json`.
+// Only the parts of the descriptors relevant to the generation are decoded.
+//
+// Messages are described by schemas following the protobuf JSON mapping:
+// 64-bit integers are strings, bytes are base64-encoded strings,
+// well-known types such as google.protobuf.Timestamp get their JSON representation
+// and field names are lowerCamelCase unless UseProtoNames is set.
+//
+// See https://github.com/googleapis/googleapis/blob/master/google/api/http.proto
+// and https://protobuf.dev/programming-guides/proto3/#json
+package protoconv
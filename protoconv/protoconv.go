@@ -0,0 +1,569 @@
+package protoconv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// StatusSchemaName is the name of the component schema describing google.rpc.Status,
+// the body of default (error) responses.
+const StatusSchemaName = "google.rpc.Status"
+
+// Option allows tweaking the generation
+type Option func(*generator)
+
+// WithInfo sets the info object of the generated document.
+// It defaults to a title naming the first file holding services.
+func WithInfo(info *openapi3.Info) Option {
+	return func(g *generator) { g.info = info }
+}
+
+// WithFiles restricts the described services to the ones declared in the given files.
+// Descriptor sets built with --include_imports hold all the imported files too.
+func WithFiles(names ...string) Option {
+	return func(g *generator) {
+		g.files = make(map[string]bool, len(names))
+		for _, name := range names {
+			g.files[name] = true
+		}
+	}
+}
+
+// UseProtoNames makes properties and parameters use the original field names
+// instead of their lowerCamelCase JSON names.
+func UseProtoNames() Option {
+	return func(g *generator) { g.useProtoNames = true }
+}
+
+// UnboundMethods describes methods without google.api.http annotation too,
+// as `POST /<package>.<Service>/<Method>` operations taking the whole input message as body.
+func UnboundMethods() Option {
+	return func(g *generator) { g.unboundMethods = true }
+}
+
+type generator struct {
+	info           *openapi3.Info
+	files          map[string]bool
+	useProtoNames  bool
+	unboundMethods bool
+
+	doc      *openapi3.T
+	messages map[string]*MessageDescriptor
+	enums    map[string]*EnumDescriptor
+}
+
+// Generate returns an OpenAPI v3 document describing the HTTP bindings
+// of the services declared in set.
+//
+// Each google.api.http rule (additional bindings included) produces an operation
+// with ID `<Service>_<Method>` (suffixed by the binding index for additional bindings),
+// tagged with the service name.
+// Path template variables become path parameters. A variable matching several segments
+// (e.g. `{name=shelves/*/books/*}`) at the end of the path is marked with routers.ExtGreedy.
+// Input message fields bound neither to the path nor to the body become query parameters,
+// nested messages being flattened with dotted names.
+// Messages and enums are described in the components section under their full names.
+//
+// Streaming methods are skipped.
+func Generate(set *FileDescriptorSet, opts ...Option) (*openapi3.T, error) {
+	g := &generator{
+		messages: make(map[string]*MessageDescriptor),
+		enums:    make(map[string]*EnumDescriptor),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	for _, file := range set.File {
+		prefix := "."
+		if file.Package != "" {
+			prefix += file.Package + "."
+		}
+		g.registerTypes(prefix, file.MessageType, file.EnumType)
+	}
+
+	g.doc = &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info:    g.info,
+		Paths:   openapi3.NewPaths(),
+		Components: openapi3.Components{
+			Schemas: openapi3.NewSchemas(),
+		},
+	}
+	for _, file := range set.File {
+		if len(file.Service) == 0 || (g.files != nil && !g.files[file.Name]) {
+			continue
+		}
+		if g.doc.Info == nil {
+			g.doc.Info = &openapi3.Info{Title: file.Name, Version: "version not set"}
+		}
+		for _, service := range file.Service {
+			if err := g.addService(file, service); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if g.doc.Info == nil {
+		g.doc.Info = &openapi3.Info{Title: "API", Version: "version not set"}
+	}
+	return g.doc, nil
+}
+
+func (g *generator) registerTypes(prefix string, messages []*MessageDescriptor, enums []*EnumDescriptor) {
+	for _, enum := range enums {
+		g.enums[prefix+enum.Name] = enum
+	}
+	for _, message := range messages {
+		name := prefix + message.Name
+		g.messages[name] = message
+		g.registerTypes(name+".", message.NestedType, message.EnumType)
+	}
+}
+
+func (g *generator) addService(file *FileDescriptor, service *ServiceDescriptor) error {
+	fullName := service.Name
+	if file.Package != "" {
+		fullName = file.Package + "." + service.Name
+	}
+	tagged := false
+	for _, method := range service.Method {
+		if method.ClientStreaming || method.ServerStreaming {
+			continue
+		}
+		var rules []*HTTPRule
+		if method.Options != nil && method.Options.HTTP != nil {
+			rules = append([]*HTTPRule{method.Options.HTTP}, method.Options.HTTP.AdditionalBindings...)
+		} else if g.unboundMethods {
+			rules = []*HTTPRule{{Post: "/" + fullName + "/" + method.Name, Body: "*"}}
+		}
+		for i, rule := range rules {
+			operationID := service.Name + "_" + method.Name
+			if i != 0 {
+				operationID += strconv.Itoa(i)
+			}
+			if err := g.addOperation(service.Name, operationID, method, rule); err != nil {
+				return fmt.Errorf("%s.%s: %w", fullName, method.Name, err)
+			}
+			tagged = true
+		}
+	}
+	if tagged {
+		g.doc.Tags = append(g.doc.Tags, &openapi3.Tag{Name: service.Name})
+	}
+	return nil
+}
+
+func (g *generator) addOperation(tag, operationID string, method *MethodDescriptor, rule *HTTPRule) error {
+	httpMethod, template := rule.Pattern()
+	if httpMethod == "" {
+		return fmt.Errorf("binding %q has no pattern", operationID)
+	}
+	input := g.messages[method.InputType]
+	if input == nil {
+		return fmt.Errorf("unknown input type %q", method.InputType)
+	}
+
+	path, variables, err := parsePathTemplate(template)
+	if err != nil {
+		return fmt.Errorf("invalid path template %q: %w", template, err)
+	}
+
+	operation := &openapi3.Operation{
+		OperationID: operationID,
+		Tags:        []string{tag},
+		Responses:   &openapi3.Responses{},
+	}
+	if method.Options != nil {
+		operation.Deprecated = method.Options.Deprecated
+	}
+
+	bound := make(map[string]bool)
+	for _, variable := range variables {
+		field, err := g.fieldByPath(input, variable.fieldPath)
+		if err != nil {
+			return err
+		}
+		bound[variable.fieldPath] = true
+		parameter := openapi3.NewPathParameter(g.fieldPathName(input, variable.fieldPath)).
+			WithSchema(g.scalarSchema(field).Value)
+		if variable.greedy {
+			parameter.Extensions = map[string]interface{}{routers.ExtGreedy: true}
+		}
+		// Rename the variable in the path to match the parameter name
+		path = strings.Replace(path, "{"+variable.fieldPath+"}", "{"+parameter.Name+"}", 1)
+		operation.AddParameter(parameter)
+	}
+
+	switch rule.Body {
+	case "":
+		g.addQueryParameters(operation, input, "", "", bound, map[string]bool{})
+	case "*":
+		operation.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().
+			WithRequired(true).
+			WithJSONSchemaRef(g.messageRef(method.InputType))}
+	default:
+		field := fieldByName(input, rule.Body)
+		if field == nil {
+			return fmt.Errorf("unknown body field %q", rule.Body)
+		}
+		bound[rule.Body] = true
+		operation.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().
+			WithRequired(true).
+			WithJSONSchemaRef(g.fieldSchema(field))}
+		g.addQueryParameters(operation, input, "", "", bound, map[string]bool{})
+	}
+
+	var output *openapi3.SchemaRef
+	if rule.ResponseBody == "" {
+		output = g.messageRef(method.OutputType)
+	} else {
+		outputMessage := g.messages[method.OutputType]
+		if outputMessage == nil {
+			return fmt.Errorf("unknown output type %q", method.OutputType)
+		}
+		field := fieldByName(outputMessage, rule.ResponseBody)
+		if field == nil {
+			return fmt.Errorf("unknown response body field %q", rule.ResponseBody)
+		}
+		output = g.fieldSchema(field)
+	}
+	operation.Responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().
+		WithDescription("A successful response.").
+		WithJSONSchemaRef(output)})
+	operation.Responses.Set("default", &openapi3.ResponseRef{Value: openapi3.NewResponse().
+		WithDescription("An unexpected error response.").
+		WithJSONSchemaRef(g.statusRef())})
+
+	pathItem := g.doc.Paths.Value(path)
+	if pathItem == nil {
+		pathItem = &openapi3.PathItem{}
+		g.doc.Paths.Set(path, pathItem)
+	}
+	if pathItem.GetOperation(httpMethod) != nil {
+		return fmt.Errorf("%s %s is already bound", httpMethod, path)
+	}
+	pathItem.SetOperation(httpMethod, operation)
+	return nil
+}
+
+// addQueryParameters adds a query parameter for each field of message not bound elsewhere.
+func (g *generator) addQueryParameters(operation *openapi3.Operation, message *MessageDescriptor, fieldPrefix, namePrefix string, bound, visiting map[string]bool) {
+	for _, field := range message.Field {
+		fieldPath := fieldPrefix + field.Name
+		if bound[fieldPath] {
+			continue
+		}
+		name := namePrefix + g.propertyName(field)
+		if field.Type == TypeMessage {
+			nested := g.messages[field.TypeName]
+			if nested == nil || nested.Options != nil && nested.Options.MapEntry {
+				continue
+			}
+			if _, ok := wellKnownSchema(field.TypeName); ok {
+				if field.Label == LabelRepeated || !isScalarWellKnownType(field.TypeName) {
+					continue
+				}
+			} else {
+				if field.Label == LabelRepeated || visiting[field.TypeName] {
+					continue
+				}
+				visiting[field.TypeName] = true
+				g.addQueryParameters(operation, nested, fieldPath+".", name+".", bound, visiting)
+				delete(visiting, field.TypeName)
+				continue
+			}
+		}
+		operation.AddParameter(openapi3.NewQueryParameter(name).WithSchema(g.fieldSchema(field).Value))
+	}
+}
+
+func (g *generator) fieldByPath(message *MessageDescriptor, fieldPath string) (*FieldDescriptor, error) {
+	names := strings.Split(fieldPath, ".")
+	for i, name := range names {
+		field := fieldByName(message, name)
+		if field == nil {
+			return nil, fmt.Errorf("unknown field %q", fieldPath)
+		}
+		if i == len(names)-1 {
+			if field.Type == TypeMessage && !isScalarWellKnownType(field.TypeName) || field.Label == LabelRepeated {
+				return nil, fmt.Errorf("field %q is not a scalar", fieldPath)
+			}
+			return field, nil
+		}
+		if message = g.messages[field.TypeName]; field.Type != TypeMessage || message == nil {
+			return nil, fmt.Errorf("field %q is not a message", strings.Join(names[:i+1], "."))
+		}
+	}
+	return nil, fmt.Errorf("unknown field %q", fieldPath)
+}
+
+// fieldPathName returns the parameter name of fieldPath, made of property names.
+func (g *generator) fieldPathName(message *MessageDescriptor, fieldPath string) string {
+	names := strings.Split(fieldPath, ".")
+	for i, name := range names {
+		field := fieldByName(message, name)
+		names[i] = g.propertyName(field)
+		message = g.messages[field.TypeName]
+	}
+	return strings.Join(names, ".")
+}
+
+func fieldByName(message *MessageDescriptor, name string) *FieldDescriptor {
+	for _, field := range message.Field {
+		if field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+func (g *generator) propertyName(field *FieldDescriptor) string {
+	if g.useProtoNames {
+		return field.Name
+	}
+	if field.JSONName != "" {
+		return field.JSONName
+	}
+	return jsonCamelCase(field.Name)
+}
+
+// jsonCamelCase mirrors protoc's default JSON name computation.
+func jsonCamelCase(name string) string {
+	var b strings.Builder
+	upper := false
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_':
+			upper = true
+		case upper && 'a' <= c && c <= 'z':
+			b.WriteByte(c - 'a' + 'A')
+			upper = false
+		default:
+			b.WriteByte(c)
+			upper = false
+		}
+	}
+	return b.String()
+}
+
+func (g *generator) fieldSchema(field *FieldDescriptor) *openapi3.SchemaRef {
+	var ref *openapi3.SchemaRef
+	if message := g.messages[field.TypeName]; field.Type == TypeMessage && message != nil &&
+		message.Options != nil && message.Options.MapEntry {
+		schema := openapi3.NewObjectSchema()
+		if value := fieldByName(message, "value"); value != nil {
+			schema.AdditionalProperties = g.fieldSchema(value)
+		}
+		ref = openapi3.NewSchemaRef("", schema)
+	} else {
+		ref = g.scalarSchema(field)
+		if field.Label == LabelRepeated {
+			schema := openapi3.NewArraySchema()
+			schema.Items = ref
+			ref = openapi3.NewSchemaRef("", schema)
+		}
+	}
+	if field.Options != nil && field.Options.Deprecated {
+		if ref.Ref != "" {
+			// Sibling keywords of $ref are ignored
+			ref = openapi3.NewSchemaRef("", &openapi3.Schema{AllOf: openapi3.SchemaRefs{ref}})
+		}
+		ref.Value.Deprecated = true
+	}
+	return ref
+}
+
+// scalarSchema returns the schema of a single value of field, ignoring its label.
+func (g *generator) scalarSchema(field *FieldDescriptor) *openapi3.SchemaRef {
+	var schema *openapi3.Schema
+	switch field.Type {
+	case TypeDouble:
+		schema = openapi3.NewFloat64Schema().WithFormat("double")
+	case TypeFloat:
+		schema = openapi3.NewFloat64Schema().WithFormat("float")
+	case TypeInt32, TypeSint32, TypeSfixed32:
+		schema = openapi3.NewInt32Schema()
+	case TypeUint32, TypeFixed32:
+		schema = openapi3.NewInt64Schema().WithMin(0)
+	case TypeInt64, TypeSint64, TypeSfixed64:
+		schema = openapi3.NewStringSchema().WithFormat("int64")
+	case TypeUint64, TypeFixed64:
+		schema = openapi3.NewStringSchema().WithFormat("uint64")
+	case TypeBool:
+		schema = openapi3.NewBoolSchema()
+	case TypeString:
+		schema = openapi3.NewStringSchema()
+	case TypeBytes:
+		schema = openapi3.NewBytesSchema()
+	case TypeEnum:
+		return g.enumRef(field.TypeName)
+	case TypeMessage, TypeGroup:
+		return g.messageRef(field.TypeName)
+	default:
+		schema = &openapi3.Schema{}
+	}
+	return openapi3.NewSchemaRef("", schema)
+}
+
+func (g *generator) messageRef(typeName string) *openapi3.SchemaRef {
+	if schema, ok := wellKnownSchema(typeName); ok {
+		return openapi3.NewSchemaRef("", schema)
+	}
+	name := strings.TrimPrefix(typeName, ".")
+	ref := "#/components/schemas/" + name
+	if existing := g.doc.Components.Schemas.Value(name); existing != nil {
+		return openapi3.NewSchemaRef(ref, existing.Value)
+	}
+	message := g.messages[typeName]
+	if message == nil {
+		// Undeclared type: accept anything
+		return openapi3.NewSchemaRef("", openapi3.NewObjectSchema())
+	}
+	schema := openapi3.NewObjectSchema()
+	schema.Properties = openapi3.NewSchemas()
+	if message.Options != nil {
+		schema.Deprecated = message.Options.Deprecated
+	}
+	// Registered before the fields are described, for recursive messages
+	g.doc.Components.Schemas.Set(name, openapi3.NewSchemaRef("", schema))
+	for _, field := range message.Field {
+		schema.Properties.Set(g.propertyName(field), g.fieldSchema(field))
+	}
+	return openapi3.NewSchemaRef(ref, schema)
+}
+
+func (g *generator) enumRef(typeName string) *openapi3.SchemaRef {
+	name := strings.TrimPrefix(typeName, ".")
+	ref := "#/components/schemas/" + name
+	if existing := g.doc.Components.Schemas.Value(name); existing != nil {
+		return openapi3.NewSchemaRef(ref, existing.Value)
+	}
+	enum := g.enums[typeName]
+	if enum == nil {
+		return openapi3.NewSchemaRef("", openapi3.NewStringSchema())
+	}
+	schema := openapi3.NewStringSchema()
+	for _, value := range enum.Value {
+		schema.Enum = append(schema.Enum, value.Name)
+	}
+	if len(enum.Value) != 0 {
+		schema.Default = enum.Value[0].Name
+	}
+	g.doc.Components.Schemas.Set(name, openapi3.NewSchemaRef("", schema))
+	return openapi3.NewSchemaRef(ref, schema)
+}
+
+func (g *generator) statusRef() *openapi3.SchemaRef {
+	ref := "#/components/schemas/" + StatusSchemaName
+	if existing := g.doc.Components.Schemas.Value(StatusSchemaName); existing != nil {
+		return openapi3.NewSchemaRef(ref, existing.Value)
+	}
+	anySchema, _ := wellKnownSchema(".google.protobuf.Any")
+	schema := openapi3.NewObjectSchema().
+		WithProperty("code", openapi3.NewInt32Schema()).
+		WithProperty("message", openapi3.NewStringSchema()).
+		WithProperty("details", openapi3.NewArraySchema().WithItems(anySchema))
+	g.doc.Components.Schemas.Set(StatusSchemaName, openapi3.NewSchemaRef("", schema))
+	return openapi3.NewSchemaRef(ref, schema)
+}
+
+// wellKnownSchema returns the schema of the JSON representation of a google.protobuf well-known type.
+func wellKnownSchema(typeName string) (*openapi3.Schema, bool) {
+	var schema *openapi3.Schema
+	switch typeName {
+	case ".google.protobuf.Timestamp":
+		schema = openapi3.NewDateTimeSchema()
+	case ".google.protobuf.Duration":
+		schema = openapi3.NewStringSchema().WithPattern(`^-?[0-9]+(\.[0-9]+)?s$`)
+	case ".google.protobuf.FieldMask":
+		schema = openapi3.NewStringSchema()
+	case ".google.protobuf.Empty":
+		schema = openapi3.NewObjectSchema()
+	case ".google.protobuf.Struct":
+		schema = openapi3.NewObjectSchema().WithAnyAdditionalProperties()
+	case ".google.protobuf.Value":
+		schema = &openapi3.Schema{Nullable: true}
+	case ".google.protobuf.ListValue":
+		schema = openapi3.NewArraySchema().WithItems(&openapi3.Schema{Nullable: true})
+	case ".google.protobuf.Any":
+		schema = openapi3.NewObjectSchema().
+			WithProperty("@type", openapi3.NewStringSchema()).
+			WithAnyAdditionalProperties()
+	case ".google.protobuf.DoubleValue":
+		schema = openapi3.NewFloat64Schema().WithFormat("double").WithNullable()
+	case ".google.protobuf.FloatValue":
+		schema = openapi3.NewFloat64Schema().WithFormat("float").WithNullable()
+	case ".google.protobuf.Int64Value":
+		schema = openapi3.NewStringSchema().WithFormat("int64").WithNullable()
+	case ".google.protobuf.UInt64Value":
+		schema = openapi3.NewStringSchema().WithFormat("uint64").WithNullable()
+	case ".google.protobuf.Int32Value":
+		schema = openapi3.NewInt32Schema().WithNullable()
+	case ".google.protobuf.UInt32Value":
+		schema = openapi3.NewInt64Schema().WithMin(0).WithNullable()
+	case ".google.protobuf.BoolValue":
+		schema = openapi3.NewBoolSchema().WithNullable()
+	case ".google.protobuf.StringValue":
+		schema = openapi3.NewStringSchema().WithNullable()
+	case ".google.protobuf.BytesValue":
+		schema = openapi3.NewBytesSchema().WithNullable()
+	default:
+		return nil, false
+	}
+	return schema, true
+}
+
+// isScalarWellKnownType reports whether the JSON representation of typeName is a primitive value.
+func isScalarWellKnownType(typeName string) bool {
+	schema, ok := wellKnownSchema(typeName)
+	return ok && schema.Type != "" && schema.Type != openapi3.TypeObject && schema.Type != openapi3.TypeArray
+}
+
+type pathVariable struct {
+	fieldPath string
+	greedy    bool
+}
+
+// parsePathTemplate converts an http.proto path template into an OpenAPI path,
+// with variables named after their field paths.
+func parsePathTemplate(template string) (string, []pathVariable, error) {
+	if !strings.HasPrefix(template, "/") {
+		return "", nil, fmt.Errorf("must start with a slash")
+	}
+	var b strings.Builder
+	var variables []pathVariable
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		if c != '{' {
+			if c == '}' {
+				return "", nil, fmt.Errorf("unbalanced braces")
+			}
+			b.WriteByte(c)
+			continue
+		}
+		end := strings.IndexByte(template[i:], '}')
+		if end < 0 {
+			return "", nil, fmt.Errorf("unbalanced braces")
+		}
+		variable := template[i+1 : i+end]
+		i += end
+		fieldPath, pattern := variable, ""
+		if eq := strings.IndexByte(variable, '='); eq >= 0 {
+			fieldPath, pattern = variable[:eq], variable[eq+1:]
+		}
+		if fieldPath == "" {
+			return "", nil, fmt.Errorf("empty variable name")
+		}
+		rest := template[i+1:]
+		variables = append(variables, pathVariable{
+			fieldPath: fieldPath,
+			greedy:    (strings.Contains(pattern, "/") || strings.Contains(pattern, "**")) && rest == "",
+		})
+		b.WriteString("{" + fieldPath + "}")
+	}
+	return b.String(), variables, nil
+}
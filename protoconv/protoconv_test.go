@@ -0,0 +1,160 @@
+package protoconv_test
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/protoconv"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+func TestGenerate(t *testing.T) {
+	set, err := protoconv.ReadDescriptorSetFile("testdata/library.json")
+	require.NoError(t, err)
+
+	doc, err := protoconv.Generate(set, protoconv.WithFiles("example/library/v1/library.proto"))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+
+	require.Equal(t, "example/library/v1/library.proto", doc.Info.Title)
+	require.Equal(t, []string{"/v1/books/{name}", "/v1/{name}", "/v1/{parent}/books"}, sortedPaths(doc))
+
+	getBook := doc.Paths.Value("/v1/{name}").Get
+	require.Equal(t, "LibraryService_GetBook", getBook.OperationID)
+	require.Equal(t, []string{"LibraryService"}, getBook.Tags)
+	require.Equal(t, "name", routers.GreedyPathParameter("/v1/{name}", doc.Paths.Value("/v1/{name}")))
+	require.Equal(t, "#/components/schemas/example.library.v1.Book", getBook.Responses.Get(200).Value.Content.Get("application/json").Schema.Ref)
+	require.Equal(t, "#/components/schemas/google.rpc.Status", getBook.Responses.Default().Value.Content.Get("application/json").Schema.Ref)
+	require.Equal(t, "LibraryService_GetBook1", doc.Paths.Value("/v1/books/{name}").Get.OperationID)
+	require.Empty(t, routers.GreedyPathParameter("/v1/books/{name}", doc.Paths.Value("/v1/books/{name}")))
+
+	listBooks := doc.Paths.Value("/v1/{parent}/books").Get
+	require.JSONEq(t, `[
+		{"in": "path", "name": "parent", "required": true, "schema": {"type": "string"}},
+		{"in": "query", "name": "pageSize", "schema": {"type": "integer", "format": "int32"}},
+		{"in": "query", "name": "genres", "schema": {"type": "array", "items": {"$ref": "#/components/schemas/example.library.v1.Book.Genre"}}},
+		{"in": "query", "name": "filter.author", "schema": {"type": "string"}}
+	]`, marshal(t, listBooks.Parameters))
+	require.JSONEq(t, `{"type": "array", "items": {"$ref": "#/components/schemas/example.library.v1.Book"}}`,
+		marshal(t, listBooks.Responses.Get(200).Value.Content.Get("application/json").Schema))
+
+	createBook := doc.Paths.Value("/v1/{parent}/books").Post
+	require.True(t, createBook.RequestBody.Value.Required)
+	require.Equal(t, "#/components/schemas/example.library.v1.Book", createBook.RequestBody.Value.Content.Get("application/json").Schema.Ref)
+	require.Len(t, createBook.Parameters, 2)
+	require.Equal(t, "validateOnly", createBook.Parameters[1].Value.Name)
+
+	require.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"pageCount": {"type": "string", "format": "int64"},
+			"genre": {"$ref": "#/components/schemas/example.library.v1.Book.Genre"},
+			"published": {"type": "string", "format": "date-time"},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+			"sequel": {"$ref": "#/components/schemas/example.library.v1.Book"},
+			"isbn": {"type": "string", "deprecated": true}
+		}
+	}`, marshal(t, doc.Components.Schemas.Value("example.library.v1.Book")))
+	require.JSONEq(t, `{"type": "string", "enum": ["GENRE_UNSPECIFIED", "FICTION", "POETRY"], "default": "GENRE_UNSPECIFIED"}`,
+		marshal(t, doc.Components.Schemas.Value("example.library.v1.Book.Genre")))
+	require.NotContains(t, doc.Components.Schemas.Map(), "example.library.v1.ListBooksResponse")
+	require.NotContains(t, doc.Components.Schemas.Map(), "google.protobuf.Timestamp")
+}
+
+func TestGenerateOptions(t *testing.T) {
+	set, err := protoconv.ReadDescriptorSetFile("testdata/library.json")
+	require.NoError(t, err)
+
+	doc, err := protoconv.Generate(set,
+		protoconv.WithInfo(&openapi3.Info{Title: "Library", Version: "1.0.0"}),
+		protoconv.UseProtoNames(),
+		protoconv.UnboundMethods(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+	require.Equal(t, "Library", doc.Info.Title)
+	require.Contains(t, doc.Components.Schemas.Value("example.library.v1.Book").Value.Properties.Map(), "page_count")
+	require.Equal(t, "page_size", doc.Paths.Value("/v1/{parent}/books").Get.Parameters[1].Value.Name)
+	// Streaming methods are never described
+	require.Nil(t, doc.Paths.Value("/example.library.v1.LibraryService/WatchBooks"))
+}
+
+func TestParseDescriptorSetBinary(t *testing.T) {
+	// service S { rpc M(M) returns (M) { option (google.api.http) = { get: "/v1/{id}" }; } }
+	// message M { int64 id = 1; }
+	field := concat(
+		bytesField(1, []byte("id")), varintField(3, 1), varintField(4, 1), varintField(5, 3), bytesField(10, []byte("id")),
+	)
+	message := concat(bytesField(1, []byte("M")), bytesField(2, field))
+	httpRule := bytesField(2, []byte("/v1/{id}"))
+	method := concat(
+		bytesField(1, []byte("M")), bytesField(2, []byte(".p.M")), bytesField(3, []byte(".p.M")),
+		bytesField(4, bytesField(72295728, httpRule)),
+	)
+	service := concat(bytesField(1, []byte("S")), bytesField(2, method))
+	file := concat(
+		bytesField(1, []byte("p.proto")), bytesField(2, []byte("p")),
+		bytesField(4, message), bytesField(6, service), bytesField(12, []byte("proto3")),
+	)
+
+	set, err := protoconv.ParseDescriptorSet(bytesField(1, file))
+	require.NoError(t, err)
+	require.Len(t, set.File, 1)
+	require.Equal(t, protoconv.TypeInt64, set.File[0].MessageType[0].Field[0].Type)
+	require.Equal(t, "/v1/{id}", set.File[0].Service[0].Method[0].Options.HTTP.Get)
+
+	doc, err := protoconv.Generate(set)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+	require.JSONEq(t, `[{"in": "path", "name": "id", "required": true, "schema": {"type": "string", "format": "int64"}}]`,
+		marshal(t, doc.Paths.Value("/v1/{id}").Get.Parameters))
+
+	_, err = protoconv.ParseDescriptorSet(bytesField(1, file)[:10])
+	require.EqualError(t, err, "invalid descriptor set: truncated message")
+}
+
+func sortedPaths(doc *openapi3.T) []string {
+	paths := make([]string, 0, doc.Paths.Len())
+	for path := range doc.Paths.Map() {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func marshal(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func concat(parts ...[]byte) []byte {
+	var b []byte
+	for _, part := range parts {
+		b = append(b, part...)
+	}
+	return b
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func varintField(number int, v uint64) []byte {
+	return appendVarint(appendVarint(nil, uint64(number)<<3), v)
+}
+
+func bytesField(number int, data []byte) []byte {
+	b := appendVarint(appendVarint(nil, uint64(number)<<3|2), uint64(len(data)))
+	return append(b, data...)
+}
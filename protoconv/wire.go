@@ -0,0 +1,319 @@
+package protoconv
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Decoding of the protobuf binary wire format, restricted to the descriptor fields
+// this package uses. Unknown fields are skipped.
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// Field number of the google.api.http extension of google.protobuf.MethodOptions
+const fieldGoogleAPIHTTP = 72295728
+
+var errTruncated = errors.New("truncated message")
+
+type wireField struct {
+	number int32
+	typ    int
+	varint uint64
+	bytes  []byte
+}
+
+// decodeMessage calls fn for each field of the message encoded in data.
+func decodeMessage(data []byte, fn func(f *wireField) error) error {
+	for len(data) != 0 {
+		key, n := decodeVarint(data)
+		if n == 0 {
+			return errTruncated
+		}
+		data = data[n:]
+		f := &wireField{number: int32(key >> 3), typ: int(key & 7)}
+		switch f.typ {
+		case wireVarint:
+			if f.varint, n = decodeVarint(data); n == 0 {
+				return errTruncated
+			}
+		case wireFixed64:
+			n = 8
+		case wireBytes:
+			length, m := decodeVarint(data)
+			if m == 0 || uint64(len(data)-m) < length {
+				return errTruncated
+			}
+			f.bytes = data[m : m+int(length)]
+			n = m + int(length)
+		case wireFixed32:
+			n = 4
+		default:
+			return fmt.Errorf("unsupported wire type %d for field %d", f.typ, f.number)
+		}
+		if len(data) < n {
+			return errTruncated
+		}
+		data = data[n:]
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeVarint(data []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < len(data) && i < 10; i++ {
+		b := data[i]
+		v |= uint64(b&0x7f) << (7 * uint(i))
+		if b < 0x80 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
+
+func decodeFileDescriptorSet(data []byte, set *FileDescriptorSet) error {
+	return decodeMessage(data, func(f *wireField) error {
+		if f.number == 1 && f.typ == wireBytes {
+			file := &FileDescriptor{}
+			if err := decodeFileDescriptor(f.bytes, file); err != nil {
+				return err
+			}
+			set.File = append(set.File, file)
+		}
+		return nil
+	})
+}
+
+func decodeFileDescriptor(data []byte, file *FileDescriptor) error {
+	return decodeMessage(data, func(f *wireField) error {
+		if f.typ != wireBytes {
+			return nil
+		}
+		switch f.number {
+		case 1:
+			file.Name = string(f.bytes)
+		case 2:
+			file.Package = string(f.bytes)
+		case 4:
+			message := &MessageDescriptor{}
+			if err := decodeMessageDescriptor(f.bytes, message); err != nil {
+				return err
+			}
+			file.MessageType = append(file.MessageType, message)
+		case 5:
+			enum := &EnumDescriptor{}
+			if err := decodeEnumDescriptor(f.bytes, enum); err != nil {
+				return err
+			}
+			file.EnumType = append(file.EnumType, enum)
+		case 6:
+			service := &ServiceDescriptor{}
+			if err := decodeServiceDescriptor(f.bytes, service); err != nil {
+				return err
+			}
+			file.Service = append(file.Service, service)
+		case 12:
+			file.Syntax = string(f.bytes)
+		}
+		return nil
+	})
+}
+
+func decodeMessageDescriptor(data []byte, message *MessageDescriptor) error {
+	return decodeMessage(data, func(f *wireField) error {
+		if f.typ != wireBytes {
+			return nil
+		}
+		switch f.number {
+		case 1:
+			message.Name = string(f.bytes)
+		case 2:
+			field := &FieldDescriptor{}
+			if err := decodeFieldDescriptor(f.bytes, field); err != nil {
+				return err
+			}
+			message.Field = append(message.Field, field)
+		case 3:
+			nested := &MessageDescriptor{}
+			if err := decodeMessageDescriptor(f.bytes, nested); err != nil {
+				return err
+			}
+			message.NestedType = append(message.NestedType, nested)
+		case 4:
+			enum := &EnumDescriptor{}
+			if err := decodeEnumDescriptor(f.bytes, enum); err != nil {
+				return err
+			}
+			message.EnumType = append(message.EnumType, enum)
+		case 7:
+			message.Options = &MessageOptions{}
+			return decodeMessage(f.bytes, func(f *wireField) error {
+				if f.typ == wireVarint {
+					switch f.number {
+					case 3:
+						message.Options.Deprecated = f.varint != 0
+					case 7:
+						message.Options.MapEntry = f.varint != 0
+					}
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+}
+
+func decodeFieldDescriptor(data []byte, field *FieldDescriptor) error {
+	return decodeMessage(data, func(f *wireField) error {
+		switch {
+		case f.number == 1 && f.typ == wireBytes:
+			field.Name = string(f.bytes)
+		case f.number == 3 && f.typ == wireVarint:
+			field.Number = int32(f.varint)
+		case f.number == 4 && f.typ == wireVarint:
+			field.Label = FieldLabel(f.varint)
+		case f.number == 5 && f.typ == wireVarint:
+			field.Type = FieldType(f.varint)
+		case f.number == 6 && f.typ == wireBytes:
+			field.TypeName = string(f.bytes)
+		case f.number == 10 && f.typ == wireBytes:
+			field.JSONName = string(f.bytes)
+		case f.number == 8 && f.typ == wireBytes:
+			field.Options = &FieldOptions{}
+			return decodeMessage(f.bytes, func(f *wireField) error {
+				if f.number == 3 && f.typ == wireVarint {
+					field.Options.Deprecated = f.varint != 0
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+}
+
+func decodeEnumDescriptor(data []byte, enum *EnumDescriptor) error {
+	return decodeMessage(data, func(f *wireField) error {
+		if f.typ != wireBytes {
+			return nil
+		}
+		switch f.number {
+		case 1:
+			enum.Name = string(f.bytes)
+		case 2:
+			value := &EnumValueDescriptor{}
+			if err := decodeMessage(f.bytes, func(f *wireField) error {
+				switch {
+				case f.number == 1 && f.typ == wireBytes:
+					value.Name = string(f.bytes)
+				case f.number == 2 && f.typ == wireVarint:
+					value.Number = int32(f.varint)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			enum.Value = append(enum.Value, value)
+		}
+		return nil
+	})
+}
+
+func decodeServiceDescriptor(data []byte, service *ServiceDescriptor) error {
+	return decodeMessage(data, func(f *wireField) error {
+		if f.typ != wireBytes {
+			return nil
+		}
+		switch f.number {
+		case 1:
+			service.Name = string(f.bytes)
+		case 2:
+			method := &MethodDescriptor{}
+			if err := decodeMethodDescriptor(f.bytes, method); err != nil {
+				return err
+			}
+			service.Method = append(service.Method, method)
+		}
+		return nil
+	})
+}
+
+func decodeMethodDescriptor(data []byte, method *MethodDescriptor) error {
+	return decodeMessage(data, func(f *wireField) error {
+		switch {
+		case f.number == 1 && f.typ == wireBytes:
+			method.Name = string(f.bytes)
+		case f.number == 2 && f.typ == wireBytes:
+			method.InputType = string(f.bytes)
+		case f.number == 3 && f.typ == wireBytes:
+			method.OutputType = string(f.bytes)
+		case f.number == 5 && f.typ == wireVarint:
+			method.ClientStreaming = f.varint != 0
+		case f.number == 6 && f.typ == wireVarint:
+			method.ServerStreaming = f.varint != 0
+		case f.number == 4 && f.typ == wireBytes:
+			method.Options = &MethodOptions{}
+			return decodeMessage(f.bytes, func(f *wireField) error {
+				switch {
+				case f.number == 33 && f.typ == wireVarint:
+					method.Options.Deprecated = f.varint != 0
+				case f.number == fieldGoogleAPIHTTP && f.typ == wireBytes:
+					method.Options.HTTP = &HTTPRule{}
+					return decodeHTTPRule(f.bytes, method.Options.HTTP)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+}
+
+func decodeHTTPRule(data []byte, rule *HTTPRule) error {
+	return decodeMessage(data, func(f *wireField) error {
+		if f.typ != wireBytes {
+			return nil
+		}
+		switch f.number {
+		case 1:
+			rule.Selector = string(f.bytes)
+		case 2:
+			rule.Get = string(f.bytes)
+		case 3:
+			rule.Put = string(f.bytes)
+		case 4:
+			rule.Post = string(f.bytes)
+		case 5:
+			rule.Delete = string(f.bytes)
+		case 6:
+			rule.Patch = string(f.bytes)
+		case 7:
+			rule.Body = string(f.bytes)
+		case 8:
+			rule.Custom = &CustomHTTPPattern{}
+			return decodeMessage(f.bytes, func(f *wireField) error {
+				switch {
+				case f.number == 1 && f.typ == wireBytes:
+					rule.Custom.Kind = string(f.bytes)
+				case f.number == 2 && f.typ == wireBytes:
+					rule.Custom.Path = string(f.bytes)
+				}
+				return nil
+			})
+		case 11:
+			binding := &HTTPRule{}
+			if err := decodeHTTPRule(f.bytes, binding); err != nil {
+				return err
+			}
+			rule.AdditionalBindings = append(rule.AdditionalBindings, binding)
+		case 12:
+			rule.ResponseBody = string(f.bytes)
+		}
+		return nil
+	})
+}
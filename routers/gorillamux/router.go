@@ -4,6 +4,7 @@
 // * it provides somewhat granular errors: "path not found", "method not allowed".
 // * it handles matching routes with extensions (e.g. /books/{id}.json)
 // * it handles path patterns with a different syntax (e.g. /params/{x}/{y}/{z:.*})
+// * it handles greedy trailing path parameters (see routers.ExtGreedy)
 package gorillamux
 
 import (
@@ -23,8 +24,52 @@ var _ routers.Router = &Router{}
 
 // Router helps link http.Request.s and an OpenAPIv3 spec
 type Router struct {
-	muxes  []routeMux
-	routes []*routers.Route
+	muxes []routeMux
+
+	strictServers bool
+	// servers holds the request matchers of servers, when strictServers is set.
+	servers []*mux.Route
+
+	implicitHEAD    bool
+	implicitOPTIONS bool
+	// implicitOptionsOperation is the operation of Implicit OPTIONS routes, if implicitOPTIONS is set.
+	implicitOptionsOperation *openapi3.Operation
+
+	logger routers.Logger
+}
+
+// Option allows tweaking the Router built by NewRouter.
+type Option func(*Router)
+
+// WithStrictServers makes FindRoute fail with routers.ErrServerNotFound when the
+// scheme, host or base path of a request matches none of the servers, rather
+// than with routers.ErrPathNotFound.
+//
+// Ports are then compared as well: a server URL without port only matches
+// requests on the default port of their scheme. Servers with a relative URL
+// (e.g. "/api") match any host.
+func WithStrictServers() Option {
+	return func(r *Router) { r.strictServers = true }
+}
+
+// WithImplicitHEAD makes FindRoute route HEAD requests to the GET operation of paths
+// declaring no HEAD operation, with an Implicit route of method HEAD.
+func WithImplicitHEAD() Option {
+	return func(r *Router) { r.implicitHEAD = true }
+}
+
+// WithImplicitOPTIONS makes FindRoute route OPTIONS requests to paths declaring
+// no OPTIONS operation to an Implicit route of method OPTIONS, whose operation
+// only has a 204 response. Its Allow field lists the methods of the path,
+// which openapi3filter.Validator answers such requests with.
+func WithImplicitOPTIONS() Option {
+	return func(r *Router) { r.implicitOPTIONS = true }
+}
+
+// WithLogger makes FindRoute log the requests it finds no route for,
+// with their method and path, at the info level.
+func WithLogger(logger routers.Logger) Option {
+	return func(r *Router) { r.logger = logger }
 }
 
 type varsf func(vars map[string]string)
@@ -32,6 +77,17 @@ type varsf func(vars map[string]string)
 type routeMux struct {
 	muxRoute    *mux.Route
 	varsUpdater varsf
+
+	// prefix is the literal (escaped) start of the route's path template.
+	// Requests whose path does not begin with it are skipped without
+	// running the (regular expression based) gorilla/mux matcher.
+	prefix string
+
+	// routes holds the route returned for each of the path's methods.
+	routes map[string]*routers.Route
+
+	// server is the index of the route's server in Router.servers.
+	server int
 }
 
 type srv struct {
@@ -48,31 +104,55 @@ var singleVariableMatcher = regexp.MustCompile(`^\{([^{}]+)\}$`)
 // NewRouter creates a gorilla/mux router.
 // Assumes spec is .Validate()d
 // Note that a variable for the port number MUST have a default value and only this value will match as the port (see issue #367).
-func NewRouter(doc *openapi3.T) (routers.Router, error) {
+func NewRouter(doc *openapi3.T, opts ...Option) (routers.Router, error) {
+	r := &Router{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.implicitOPTIONS {
+		r.implicitOptionsOperation = newImplicitOptionsOperation()
+	}
+
 	servers, err := makeServers(doc.Servers)
 	if err != nil {
 		return nil, err
 	}
+	firstServer, err := r.addServers(servers)
+	if err != nil {
+		return nil, err
+	}
 
 	muxRouter := mux.NewRouter().UseEncodedPath()
-	r := &Router{}
 	for _, path := range doc.Paths.InMatchingOrder() {
-		pathItem := doc.Paths[path]
+		pathItem := doc.Paths.Value(path)
 		if len(pathItem.Servers) > 0 {
 			if servers, err = makeServers(pathItem.Servers); err != nil {
 				return nil, err
 			}
+			if firstServer, err = r.addServers(servers); err != nil {
+				return nil, err
+			}
 		}
 
 		operations := pathItem.Operations()
-		methods := make([]string, 0, len(operations))
+		implicitOperations := r.implicitOperations(operations)
+		methods := make([]string, 0, len(operations)+len(implicitOperations))
 		for method := range operations {
 			methods = append(methods, method)
 		}
+		for method := range implicitOperations {
+			methods = append(methods, method)
+		}
 		sort.Strings(methods)
 
-		for _, s := range servers {
-			muxRoute := muxRouter.Path(s.base + path).Methods(methods...)
+		muxPath := path
+		if name := routers.GreedyPathParameter(path, pathItem); name != "" {
+			muxPath = strings.TrimSuffix(path, "{"+name+"}") + "{" + name + ":.+}"
+		}
+
+		for i, s := range servers {
+			template := s.base + muxPath
+			muxRoute := muxRouter.Path(template).Methods(methods...)
 			if schemes := s.schemes; len(schemes) != 0 {
 				muxRoute.Schemes(schemes...)
 			}
@@ -82,26 +162,86 @@ func NewRouter(doc *openapi3.T) (routers.Router, error) {
 			if err := muxRoute.GetError(); err != nil {
 				return nil, err
 			}
+			routes := make(map[string]*routers.Route, len(methods))
+			for _, method := range methods {
+				operation, ok := operations[method]
+				if !ok {
+					operation = implicitOperations[method]
+				}
+				routes[method] = &routers.Route{
+					Spec:      doc,
+					Server:    s.server,
+					Path:      path,
+					PathItem:  pathItem,
+					Method:    method,
+					Operation: operation,
+					Implicit:  !ok,
+					Allow:     methods,
+				}
+				routers.Compile(routes[method])
+			}
 			r.muxes = append(r.muxes, routeMux{
 				muxRoute:    muxRoute,
 				varsUpdater: s.varsUpdater,
-			})
-			r.routes = append(r.routes, &routers.Route{
-				Spec:      doc,
-				Server:    s.server,
-				Path:      path,
-				PathItem:  pathItem,
-				Method:    "",
-				Operation: nil,
+				prefix:      literalPrefix(template),
+				routes:      routes,
+				server:      firstServer + i,
 			})
 		}
 	}
 	return r, nil
 }
 
+// newImplicitOptionsOperation returns an operation for Implicit OPTIONS routes.
+func newImplicitOptionsOperation() *openapi3.Operation {
+	return &openapi3.Operation{
+		Responses: (&openapi3.Responses{}).
+			With("204", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("No Content")}),
+	}
+}
+
+// implicitOperations returns the operations of the methods routed implicitly
+// besides the declared operations.
+func (r *Router) implicitOperations(operations map[string]*openapi3.Operation) map[string]*openapi3.Operation {
+	implicit := make(map[string]*openapi3.Operation, 2)
+	if get := operations[http.MethodGet]; r.implicitHEAD && get != nil && operations[http.MethodHead] == nil {
+		implicit[http.MethodHead] = get
+	}
+	if r.implicitOPTIONS && operations[http.MethodOptions] == nil {
+		implicit[http.MethodOptions] = r.implicitOptionsOperation
+	}
+	return implicit
+}
+
 // FindRoute extracts the route and parameters of an http.Request
+//
+// Routes are built once by NewRouter: FindRoute returns a copy of the route,
+// which callers may modify. What it points to, such as its operation, is shared.
 func (r *Router) FindRoute(req *http.Request) (*routers.Route, map[string]string, error) {
-	for i, m := range r.muxes {
+	route, pathParams, err := r.findRoute(req)
+	if err != nil && r.logger != nil {
+		r.logger.InfoContext(req.Context(), "route not found",
+			"method", req.Method,
+			"path", req.URL.Path,
+			"error", err.Error(),
+		)
+	}
+	return route, pathParams, err
+}
+
+func (r *Router) findRoute(req *http.Request) (*routers.Route, map[string]string, error) {
+	var servers map[int]bool
+	if r.strictServers {
+		if servers = r.matchServers(req); len(servers) == 0 {
+			return nil, nil, routers.ErrServerNotFound
+		}
+	}
+
+	path := req.URL.EscapedPath()
+	for _, m := range r.muxes {
+		if !strings.HasPrefix(path, m.prefix) || (servers != nil && !servers[m.server]) {
+			continue
+		}
 		var match mux.RouteMatch
 		if m.muxRoute.Match(req, &match) {
 			if err := match.MatchErr; err != nil {
@@ -111,10 +251,12 @@ func (r *Router) FindRoute(req *http.Request) (*routers.Route, map[string]string
 			if f := m.varsUpdater; f != nil {
 				f(vars)
 			}
-			route := *r.routes[i]
-			route.Method = req.Method
-			route.Operation = route.Spec.Paths[route.Path].GetOperation(route.Method)
-			return &route, vars, nil
+			route := m.routes[req.Method]
+			if route == nil {
+				return nil, nil, routers.ErrMethodNotAllowed
+			}
+			found := *route
+			return &found, vars, nil
 		}
 		switch match.MatchErr {
 		case nil:
@@ -126,6 +268,89 @@ func (r *Router) FindRoute(req *http.Request) (*routers.Route, map[string]string
 	return nil, nil, routers.ErrPathNotFound
 }
 
+// addServers records the request matchers of servers, when strictServers is set,
+// and returns the index of the first one.
+func (r *Router) addServers(servers []srv) (int, error) {
+	first := len(r.servers)
+	if !r.strictServers {
+		return first, nil
+	}
+	for _, s := range servers {
+		matcher := mux.NewRouter().UseEncodedPath().NewRoute()
+		if s.base != "" {
+			matcher.PathPrefix(s.base + "/")
+		}
+		if len(s.schemes) != 0 {
+			matcher.Schemes(s.schemes...)
+		}
+		if s.host != "" {
+			matcher.Host(s.host)
+		}
+		if err := matcher.GetError(); err != nil {
+			return 0, err
+		}
+		r.servers = append(r.servers, matcher)
+	}
+	return first, nil
+}
+
+// matchServers returns the indices of the servers matching the scheme, host and path of req.
+func (r *Router) matchServers(req *http.Request) map[int]bool {
+	servers := make(map[int]bool)
+	for i, matcher := range r.servers {
+		var match mux.RouteMatch
+		if !matcher.Match(req, &match) {
+			continue
+		}
+		if tpl, _ := matcher.GetHostTemplate(); tpl != "" && !strings.Contains(tpl, ":") && !isDefaultPort(req) {
+			// gorilla/mux ignores the port of requests when the host has none
+			continue
+		}
+		servers[i] = true
+	}
+	return servers
+}
+
+// isDefaultPort tells whether req is sent to the default port of its scheme, or to no explicit port.
+func isDefaultPort(req *http.Request) bool {
+	host := req.URL.Host
+	if host == "" {
+		host = req.Host
+	}
+	i := strings.LastIndexByte(host, ':')
+	if i < 0 || strings.HasSuffix(host, "]") {
+		return true
+	}
+	scheme := req.URL.Scheme
+	if scheme == "" {
+		scheme = "http"
+		if req.TLS != nil {
+			scheme = "https"
+		}
+	}
+	port := host[i+1:]
+	return (scheme == "http" && port == "80") || (scheme == "https" && port == "443")
+}
+
+// literalPrefix returns the part of a path template that precedes its first variable.
+// An empty string is returned when that part contains characters that would be
+// escaped in a request's path, as it could then not be compared as is.
+func literalPrefix(template string) string {
+	prefix := template
+	if i := strings.IndexByte(template, '{'); i >= 0 {
+		prefix = template[:i]
+	}
+	for i := 0; i < len(prefix); i++ {
+		switch c := prefix[i]; {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '/' || c == '-' || c == '.' || c == '_' || c == '~':
+		default:
+			return ""
+		}
+	}
+	return prefix
+}
+
 func makeServers(in openapi3.Servers) ([]srv, error) {
 	servers := make([]srv, 0, len(in))
 	for _, server := range in {
@@ -32,8 +32,8 @@ func TestRouter(t *testing.T) {
 			Title:   "MyAPI",
 			Version: "0.1",
 		},
-		Paths: openapi3.Paths{
-			"/hello": &openapi3.PathItem{
+		Paths: openapi3.NewPaths().
+			With("/hello", &openapi3.PathItem{
 				Connect: helloCONNECT,
 				Delete:  helloDELETE,
 				Get:     helloGET,
@@ -43,34 +43,33 @@ func TestRouter(t *testing.T) {
 				Post:    helloPOST,
 				Put:     helloPUT,
 				Trace:   helloTRACE,
-			},
-			"/onlyGET": &openapi3.PathItem{
+			}).
+			With("/onlyGET", &openapi3.PathItem{
 				Get: helloGET,
-			},
-			"/params/{x}/{y}/{z:.*}": &openapi3.PathItem{
+			}).
+			With("/params/{x}/{y}/{z:.*}", &openapi3.PathItem{
 				Get: paramsGET,
 				Parameters: openapi3.Parameters{
 					&openapi3.ParameterRef{Value: openapi3.NewPathParameter("x")},
 					&openapi3.ParameterRef{Value: openapi3.NewPathParameter("y")},
 					&openapi3.ParameterRef{Value: openapi3.NewPathParameter("z")},
 				},
-			},
-			"/books/{bookid}": &openapi3.PathItem{
+			}).
+			With("/books/{bookid}", &openapi3.PathItem{
 				Get: paramsGET,
 				Parameters: openapi3.Parameters{
 					&openapi3.ParameterRef{Value: openapi3.NewPathParameter("bookid")},
 				},
-			},
-			"/books/{bookid}.json": &openapi3.PathItem{
+			}).
+			With("/books/{bookid}.json", &openapi3.PathItem{
 				Post: booksPOST,
 				Parameters: openapi3.Parameters{
 					&openapi3.ParameterRef{Value: openapi3.NewPathParameter("bookid")},
 				},
-			},
-			"/partial": &openapi3.PathItem{
+			}).
+			With("/partial", &openapi3.PathItem{
 				Get: partialGET,
-			},
-		},
+			}),
 	}
 
 	expect := func(r routers.Router, method string, uri string, operation *openapi3.Operation, params map[string]string) {
@@ -80,7 +79,7 @@ func TestRouter(t *testing.T) {
 		route, pathParams, err := r.FindRoute(req)
 		if err != nil {
 			if operation == nil {
-				pathItem := doc.Paths[uri]
+				pathItem := doc.Paths.Value(uri)
 				if pathItem == nil {
 					if err.Error() != routers.ErrPathNotFound.Error() {
 						t.Fatalf("'%s %s': should have returned %q, but it returned an error: %v", method, uri, routers.ErrPathNotFound, err)
@@ -277,16 +276,15 @@ func TestServerOverrideAtPathLevel(t *testing.T) {
 				URL: "https://example.com",
 			},
 		},
-		Paths: openapi3.Paths{
-			"/hello": &openapi3.PathItem{
+		Paths: openapi3.NewPaths().
+			With("/hello", &openapi3.PathItem{
 				Servers: openapi3.Servers{
 					&openapi3.Server{
 						URL: "https://another.com",
 					},
 				},
 				Get: helloGET,
-			},
-		},
+			}),
 	}
 	err := doc.Validate(context.Background())
 	require.NoError(t, err)
@@ -318,11 +316,10 @@ func TestRelativeURL(t *testing.T) {
 				URL: "/api/v1",
 			},
 		},
-		Paths: openapi3.Paths{
-			"/hello": &openapi3.PathItem{
+		Paths: openapi3.NewPaths().
+			With("/hello", &openapi3.PathItem{
 				Get: helloGET,
-			},
-		},
+			}),
 	}
 	err := doc.Validate(context.Background())
 	require.NoError(t, err)
@@ -509,3 +506,193 @@ func newServerVariable(defaultValue string) *openapi3.ServerVariable {
 		Description:    "",
 	}
 }
+
+func BenchmarkFindRoute(b *testing.B) {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info:    &openapi3.Info{Title: "MyAPI", Version: "0.1"},
+		Paths:   openapi3.NewPaths(),
+	}
+	for _, resource := range []string{"books", "authors", "shelves", "loans", "members", "fines"} {
+		doc.AddOperation("/"+resource, http.MethodGet, &openapi3.Operation{Responses: openapi3.NewResponses()})
+		doc.AddOperation("/"+resource+"/{id}", http.MethodGet, &openapi3.Operation{Responses: openapi3.NewResponses()})
+		doc.Paths.Value("/" + resource + "/{id}").Parameters = openapi3.Parameters{
+			&openapi3.ParameterRef{Value: openapi3.NewPathParameter("id")},
+		}
+	}
+	require.NoError(b, doc.Validate(context.Background()))
+	r, err := NewRouter(doc)
+	require.NoError(b, err)
+
+	for _, uri := range []string{"/fines", "/fines/42"} {
+		req, err := http.NewRequest(http.MethodGet, uri, nil)
+		require.NoError(b, err)
+		b.Run(uri, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := r.FindRoute(req); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestStrictServers(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+servers:
+- url: https://api.example.com/v1
+- url: http://localhost:8080/{version}
+  variables:
+    version: {default: v1, enum: [v1, v2]}
+paths:
+  /pets:
+    get:
+      responses: {'200': {description: Pets}}
+  /legacy:
+    servers:
+    - url: /old
+    get:
+      responses: {'200': {description: Legacy}}
+`))
+	require.NoError(t, err)
+
+	lenient, err := NewRouter(doc)
+	require.NoError(t, err)
+	strict, err := NewRouter(doc, WithStrictServers())
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		url       string
+		lenient   error
+		strict    error
+		strictURL string
+	}{
+		{url: "https://api.example.com/v1/pets", strictURL: "https://api.example.com/v1"},
+		{url: "https://api.example.com:443/v1/pets", strictURL: "https://api.example.com/v1"},
+		{url: "http://localhost:8080/v2/pets", strictURL: "http://localhost:8080/{version}"},
+		{url: "http://anyhost/old/legacy", strictURL: "/old"},
+		{url: "https://api.example.com:8443/v1/pets", strict: routers.ErrServerNotFound},
+		{url: "https://other.example.com/v1/pets", lenient: routers.ErrPathNotFound, strict: routers.ErrServerNotFound},
+		{url: "http://api.example.com/v1/pets", lenient: routers.ErrPathNotFound, strict: routers.ErrServerNotFound},
+		{url: "https://api.example.com/v1beta/pets", lenient: routers.ErrPathNotFound, strict: routers.ErrServerNotFound},
+		{url: "https://api.example.com/v1/unknown", lenient: routers.ErrPathNotFound, strict: routers.ErrPathNotFound},
+		{url: "http://localhost:8081/v1/pets", lenient: routers.ErrPathNotFound, strict: routers.ErrServerNotFound},
+	} {
+		t.Run(tc.url, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+
+			_, _, err = lenient.FindRoute(req)
+			require.Equal(t, tc.lenient, err)
+
+			route, _, err := strict.FindRoute(req)
+			require.Equal(t, tc.strict, err)
+			if err == nil {
+				require.Equal(t, tc.strictURL, route.Server.URL)
+			}
+		})
+	}
+}
+
+func TestImplicitMethods(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses: {'200': {description: Pets}}
+    post:
+      operationId: createPet
+      responses: {'201': {description: Created}}
+  /pets/{id}:
+    parameters:
+    - {name: id, in: path, required: true, schema: {type: string}}
+    head:
+      operationId: petExists
+      responses: {'200': {description: Exists}}
+    get:
+      operationId: getPet
+      responses: {'200': {description: Pet}}
+`))
+	require.NoError(t, err)
+
+	explicit, err := NewRouter(doc)
+	require.NoError(t, err)
+	for _, method := range []string{http.MethodHead, http.MethodOptions} {
+		req, err := http.NewRequest(method, "/pets", nil)
+		require.NoError(t, err)
+		_, _, err = explicit.FindRoute(req)
+		require.Equal(t, routers.ErrMethodNotAllowed, err)
+	}
+
+	implicit, err := NewRouter(doc, WithImplicitHEAD(), WithImplicitOPTIONS())
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodHead, "/pets", nil)
+	require.NoError(t, err)
+	route, _, err := implicit.FindRoute(req)
+	require.NoError(t, err)
+	require.True(t, route.Implicit)
+	require.Equal(t, http.MethodHead, route.Method)
+	require.Equal(t, "listPets", route.Operation.OperationID)
+
+	req, err = http.NewRequest(http.MethodHead, "/pets/rex", nil)
+	require.NoError(t, err)
+	route, pathParams, err := implicit.FindRoute(req)
+	require.NoError(t, err)
+	require.False(t, route.Implicit)
+	require.Equal(t, "petExists", route.Operation.OperationID)
+	require.Equal(t, map[string]string{"id": "rex"}, pathParams)
+
+	req, err = http.NewRequest(http.MethodOptions, "/pets", nil)
+	require.NoError(t, err)
+	route, _, err = implicit.FindRoute(req)
+	require.NoError(t, err)
+	require.True(t, route.Implicit)
+	require.Equal(t, []string{"GET", "HEAD", "OPTIONS", "POST"}, route.Allow)
+	require.Contains(t, route.Operation.Responses.Map(), "204")
+
+	// Each router has its own implicit operation
+	other, err := NewRouter(doc, WithImplicitOPTIONS())
+	require.NoError(t, err)
+	otherRoute, _, err := other.FindRoute(req)
+	require.NoError(t, err)
+	require.NotSame(t, route.Operation, otherRoute.Operation)
+
+	req, err = http.NewRequest(http.MethodDelete, "/pets", nil)
+	require.NoError(t, err)
+	_, _, err = implicit.FindRoute(req)
+	require.Equal(t, routers.ErrMethodNotAllowed, err)
+}
+
+func TestFindRouteReturnsCopies(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses: {'200': {description: Pets}}
+`))
+	require.NoError(t, err)
+	router, err := NewRouter(doc)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodGet, "/pets", nil)
+	require.NoError(t, err)
+
+	route, _, err := router.FindRoute(req)
+	require.NoError(t, err)
+	route.Path = "/cats"
+	route.Operation = nil
+
+	route, _, err = router.FindRoute(req)
+	require.NoError(t, err)
+	require.Equal(t, "/pets", route.Path)
+	require.Same(t, doc.Paths.Value("/pets").Get, route.Operation)
+}
@@ -0,0 +1,81 @@
+package routers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+func TestGreedyPathParameter(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.0
+info: {title: Example, version: '1.0'}
+servers:
+- url: /api
+paths:
+  /files/{bucket}/{path}:
+    parameters:
+    - {name: bucket, in: path, required: true, schema: {type: string}}
+    get:
+      operationId: getFile
+      parameters:
+      - {name: path, in: path, required: true, x-kin-greedy: true, schema: {type: string}}
+      responses:
+        '200': {description: OK}
+  /items/{id}:
+    get:
+      operationId: getItem
+      parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        '200': {description: OK}
+`)
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	require.NoError(t, err)
+	err = doc.Validate(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, "path", routers.GreedyPathParameter("/files/{bucket}/{path}", doc.Paths.Value("/files/{bucket}/{path}")))
+	require.Equal(t, "", routers.GreedyPathParameter("/items/{id}", doc.Paths.Value("/items/{id}")))
+
+	u, err := routers.BuildURL(doc, "getFile", map[string]interface{}{"bucket": "b", "path": "a/b c/d.png"})
+	require.NoError(t, err)
+	require.Equal(t, "/api/files/b/a/b%20c/d.png", u)
+
+	gorillamuxRouter, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+	legacyRouter, err := legacy.NewRouter(doc)
+	require.NoError(t, err)
+
+	for name, router := range map[string]routers.Router{
+		"gorillamux": gorillamuxRouter,
+		"legacy":     legacyRouter,
+	} {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/api/files/b/a/b/c.png", nil)
+			require.NoError(t, err)
+			route, pathParams, err := router.FindRoute(req)
+			require.NoError(t, err)
+			require.Equal(t, "getFile", route.Operation.OperationID)
+			require.Equal(t, map[string]string{"bucket": "b", "path": "a/b/c.png"}, pathParams)
+
+			req, err = http.NewRequest(http.MethodGet, "/api/files/b/c.png", nil)
+			require.NoError(t, err)
+			_, pathParams, err = router.FindRoute(req)
+			require.NoError(t, err)
+			require.Equal(t, map[string]string{"bucket": "b", "path": "c.png"}, pathParams)
+
+			req, err = http.NewRequest(http.MethodGet, "/api/items/a/b", nil)
+			require.NoError(t, err)
+			_, _, err = router.FindRoute(req)
+			require.Error(t, err)
+		})
+	}
+}
@@ -266,8 +266,8 @@ func (currentNode *Node) Match(path string) (*Node, []string) {
 	for strings.HasSuffix(path, "/") {
 		path = path[:len(path)-1]
 	}
-	variableValues := make([]string, 0, 8)
-	return currentNode.matchRemaining(path, variableValues)
+	// Values are only allocated once a variable matches
+	return currentNode.matchRemaining(path, nil)
 }
 
 func (currentNode *Node) matchRemaining(remaining string, paramValues []string) (*Node, []string) {
@@ -4,6 +4,7 @@
 // * it provides granular errors: "path not found", "method not allowed", "variable missing from path"
 // * it does not handle matching routes with extensions (e.g. /books/{id}.json)
 // * it handles path patterns with a different syntax (e.g. /params/{x}/{y}/{z.*})
+// * it handles greedy trailing path parameters (see routers.ExtGreedy)
 package legacy
 
 import (
@@ -11,6 +12,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -52,6 +54,32 @@ func (rs Routers) FindRoute(req *http.Request) (routers.Router, *routers.Route,
 type Router struct {
 	doc      *openapi3.T
 	pathNode *pathpattern.Node
+
+	validationOptions []openapi3.ValidationOption
+	implicitHEAD      bool
+	implicitOPTIONS   bool
+}
+
+// Option allows tweaking the Router built by NewRouterWithOptions.
+type Option func(*Router)
+
+// WithValidationOptions sets the options the document is validated with.
+func WithValidationOptions(opts ...openapi3.ValidationOption) Option {
+	return func(r *Router) { r.validationOptions = append(r.validationOptions, opts...) }
+}
+
+// WithImplicitHEAD makes FindRoute route HEAD requests to the GET operation of paths
+// declaring no HEAD operation, with an Implicit route of method HEAD.
+func WithImplicitHEAD() Option {
+	return func(r *Router) { r.implicitHEAD = true }
+}
+
+// WithImplicitOPTIONS makes FindRoute route OPTIONS requests to paths declaring
+// no OPTIONS operation to an Implicit route of method OPTIONS, whose operation
+// only has a 204 response. Its Allow field lists the methods of the path,
+// which openapi3filter.Validator answers such requests with.
+func WithImplicitOPTIONS() Option {
+	return func(r *Router) { r.implicitOPTIONS = true }
 }
 
 // NewRouter creates a new router.
@@ -59,21 +87,64 @@ type Router struct {
 // If the given OpenAPIv3 document has servers, router will use them.
 // All operations of the document will be added to the router.
 func NewRouter(doc *openapi3.T, opts ...openapi3.ValidationOption) (routers.Router, error) {
-	if err := doc.Validate(context.Background(), opts...); err != nil {
+	return NewRouterWithOptions(doc, WithValidationOptions(opts...))
+}
+
+// NewRouterWithOptions creates a new router as NewRouter does, tweaked by opts.
+func NewRouterWithOptions(doc *openapi3.T, opts ...Option) (routers.Router, error) {
+	router := &Router{doc: doc}
+	for _, opt := range opts {
+		opt(router)
+	}
+	if err := doc.Validate(context.Background(), router.validationOptions...); err != nil {
 		return nil, fmt.Errorf("validating OpenAPI failed: %w", err)
 	}
-	router := &Router{doc: doc}
+	var implicitOptionsOperation *openapi3.Operation
+	if router.implicitOPTIONS {
+		implicitOptionsOperation = &openapi3.Operation{
+			Responses: (&openapi3.Responses{}).
+				With("204", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("No Content")}),
+		}
+	}
 	root := router.node()
-	for path, pathItem := range doc.Paths {
-		for method, operation := range pathItem.Operations() {
-			method = strings.ToUpper(method)
-			if err := root.Add(method+" "+path, &routers.Route{
+	for path, pathItem := range doc.Paths.Map() {
+		pattern := path
+		if name := routers.GreedyPathParameter(path, pathItem); name != "" {
+			pattern = strings.TrimSuffix(path, "{"+name+"}") + "{" + name + "*}"
+		}
+		operations := pathItem.Operations()
+		implicitOperations := make(map[string]*openapi3.Operation, 2)
+		if get := operations[http.MethodGet]; router.implicitHEAD && get != nil && operations[http.MethodHead] == nil {
+			implicitOperations[http.MethodHead] = get
+		}
+		if implicitOptionsOperation != nil && operations[http.MethodOptions] == nil {
+			implicitOperations[http.MethodOptions] = implicitOptionsOperation
+		}
+		methods := make([]string, 0, len(operations)+len(implicitOperations))
+		for method := range operations {
+			methods = append(methods, method)
+		}
+		for method := range implicitOperations {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			operation, ok := operations[method]
+			if !ok {
+				operation = implicitOperations[method]
+			}
+			route := &routers.Route{
 				Spec:      doc,
 				Path:      path,
 				PathItem:  pathItem,
 				Method:    method,
 				Operation: operation,
-			}, nil); err != nil {
+				Implicit:  !ok,
+				Allow:     methods,
+			}
+			routers.Compile(route)
+			if err := root.Add(method+" "+pattern, route, nil); err != nil {
 				return nil, err
 			}
 		}
@@ -117,22 +188,21 @@ func (router *Router) FindRoute(req *http.Request) (*routers.Route, map[string]s
 	if len(servers) == 0 {
 		remainingPath = url.Path
 	} else {
-		var paramValues []string
-		server, paramValues, remainingPath = servers.MatchURL(url)
+		rawURL := url.String()
+		if i := strings.IndexByte(rawURL, '?'); i >= 0 {
+			rawURL = rawURL[:i]
+		}
+		for _, s := range servers {
+			if vars, remaining, ok := s.ParseURL(rawURL); ok {
+				server, pathParams, remainingPath = s, vars, remaining
+				break
+			}
+		}
 		if server == nil {
 			return nil, nil, &routers.RouteError{
 				Reason: routers.ErrPathNotFound.Error(),
 			}
 		}
-		pathParams = make(map[string]string)
-		paramNames, err := server.ParameterNames()
-		if err != nil {
-			return nil, nil, err
-		}
-		for i, value := range paramValues {
-			name := paramNames[i]
-			pathParams[name] = value
-		}
 	}
 
 	// Get PathItem
@@ -143,7 +213,7 @@ func (router *Router) FindRoute(req *http.Request) (*routers.Route, map[string]s
 		route, _ = node.Value.(*routers.Route)
 	}
 	if route == nil {
-		pathItem := doc.Paths[remainingPath]
+		pathItem := doc.Paths.Value(remainingPath)
 		if pathItem == nil {
 			return nil, nil, &routers.RouteError{Reason: routers.ErrPathNotFound.Error()}
 		}
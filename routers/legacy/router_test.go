@@ -31,8 +31,8 @@ func TestRouter(t *testing.T) {
 			Title:   "MyAPI",
 			Version: "0.1",
 		},
-		Paths: openapi3.Paths{
-			"/hello": &openapi3.PathItem{
+		Paths: openapi3.NewPaths().
+			With("/hello", &openapi3.PathItem{
 				Connect: helloCONNECT,
 				Delete:  helloDELETE,
 				Get:     helloGET,
@@ -42,34 +42,33 @@ func TestRouter(t *testing.T) {
 				Post:    helloPOST,
 				Put:     helloPUT,
 				Trace:   helloTRACE,
-			},
-			"/onlyGET": &openapi3.PathItem{
+			}).
+			With("/onlyGET", &openapi3.PathItem{
 				Get: helloGET,
-			},
-			"/params/{x}/{y}/{z.*}": &openapi3.PathItem{
+			}).
+			With("/params/{x}/{y}/{z.*}", &openapi3.PathItem{
 				Get: paramsGET,
 				Parameters: openapi3.Parameters{
 					&openapi3.ParameterRef{Value: openapi3.NewPathParameter("x")},
 					&openapi3.ParameterRef{Value: openapi3.NewPathParameter("y")},
 					&openapi3.ParameterRef{Value: openapi3.NewPathParameter("z")},
 				},
-			},
-			"/books/{bookid}": &openapi3.PathItem{
+			}).
+			With("/books/{bookid}", &openapi3.PathItem{
 				Get: paramsGET,
 				Parameters: openapi3.Parameters{
 					&openapi3.ParameterRef{Value: openapi3.NewPathParameter("bookid")},
 				},
-			},
-			"/books/{bookid2}.json": &openapi3.PathItem{
+			}).
+			With("/books/{bookid2}.json", &openapi3.PathItem{
 				Post: booksPOST,
 				Parameters: openapi3.Parameters{
 					&openapi3.ParameterRef{Value: openapi3.NewPathParameter("bookid2")},
 				},
-			},
-			"/partial": &openapi3.PathItem{
+			}).
+			With("/partial", &openapi3.PathItem{
 				Get: partialGET,
-			},
-		},
+			}),
 	}
 
 	expect := func(r routers.Router, method string, uri string, operation *openapi3.Operation, params map[string]string) {
@@ -78,7 +77,7 @@ func TestRouter(t *testing.T) {
 		route, pathParams, err := r.FindRoute(req)
 		if err != nil {
 			if operation == nil {
-				pathItem := doc.Paths[uri]
+				pathItem := doc.Paths.Value(uri)
 				if pathItem == nil {
 					if err.Error() != routers.ErrPathNotFound.Error() {
 						t.Fatalf("'%s %s': should have returned %q, but it returned an error: %v", method, uri, routers.ErrPathNotFound, err)
@@ -200,10 +199,10 @@ func TestRouter(t *testing.T) {
 	}
 	content := openapi3.NewContentWithJSONSchema(schema)
 	responses := openapi3.NewResponses()
-	responses["default"].Value.Content = content
-	doc.Paths["/withExamples"] = &openapi3.PathItem{
+	responses.Value("default").Value.Content = content
+	doc.Paths.Set("/withExamples", &openapi3.PathItem{
 		Get: &openapi3.Operation{Responses: responses},
-	}
+	})
 	err = doc.Validate(context.Background())
 	require.Error(t, err)
 	r, err = NewRouter(doc)
@@ -211,3 +210,107 @@ func TestRouter(t *testing.T) {
 	r, err = NewRouter(doc, openapi3.DisableExamplesValidation())
 	require.NoError(t, err)
 }
+
+func BenchmarkFindRoute(b *testing.B) {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info:    &openapi3.Info{Title: "MyAPI", Version: "0.1"},
+		Paths:   openapi3.NewPaths(),
+	}
+	for _, resource := range []string{"books", "authors", "shelves", "loans", "members", "fines"} {
+		doc.AddOperation("/"+resource, http.MethodGet, &openapi3.Operation{Responses: openapi3.NewResponses()})
+		doc.AddOperation("/"+resource+"/{id}", http.MethodGet, &openapi3.Operation{Responses: openapi3.NewResponses()})
+		doc.Paths.Value("/" + resource + "/{id}").Parameters = openapi3.Parameters{
+			&openapi3.ParameterRef{Value: openapi3.NewPathParameter("id")},
+		}
+	}
+	require.NoError(b, doc.Validate(context.Background()))
+	r, err := NewRouter(doc)
+	require.NoError(b, err)
+
+	for _, uri := range []string{"/fines", "/fines/42"} {
+		req, err := http.NewRequest(http.MethodGet, uri, nil)
+		require.NoError(b, err)
+		b.Run(uri, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := r.FindRoute(req); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestImplicitMethods(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses: {'200': {description: Pets}}
+    post:
+      operationId: createPet
+      responses: {'201': {description: Created}}
+  /pet/{id}:
+    parameters:
+    - {name: id, in: path, required: true, schema: {type: string}}
+    head:
+      operationId: petExists
+      responses: {'200': {description: Exists}}
+    get:
+      operationId: getPet
+      responses: {'200': {description: Pet}}
+`))
+	require.NoError(t, err)
+
+	explicit, err := NewRouter(doc)
+	require.NoError(t, err)
+	for _, method := range []string{http.MethodHead, http.MethodOptions} {
+		req, err := http.NewRequest(method, "/pets", nil)
+		require.NoError(t, err)
+		_, _, err = explicit.FindRoute(req)
+		require.EqualError(t, err, routers.ErrMethodNotAllowed.Error())
+	}
+
+	implicit, err := NewRouterWithOptions(doc, WithImplicitHEAD(), WithImplicitOPTIONS())
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodHead, "/pets", nil)
+	require.NoError(t, err)
+	route, _, err := implicit.FindRoute(req)
+	require.NoError(t, err)
+	require.True(t, route.Implicit)
+	require.Equal(t, http.MethodHead, route.Method)
+	require.Equal(t, "listPets", route.Operation.OperationID)
+
+	req, err = http.NewRequest(http.MethodHead, "/pet/rex", nil)
+	require.NoError(t, err)
+	route, pathParams, err := implicit.FindRoute(req)
+	require.NoError(t, err)
+	require.False(t, route.Implicit)
+	require.Equal(t, "petExists", route.Operation.OperationID)
+	require.Equal(t, map[string]string{"id": "rex"}, pathParams)
+
+	req, err = http.NewRequest(http.MethodOptions, "/pets", nil)
+	require.NoError(t, err)
+	route, _, err = implicit.FindRoute(req)
+	require.NoError(t, err)
+	require.True(t, route.Implicit)
+	require.Equal(t, []string{"GET", "HEAD", "OPTIONS", "POST"}, route.Allow)
+	require.Contains(t, route.Operation.Responses.Map(), "204")
+
+	// Each router has its own implicit operation
+	other, err := NewRouterWithOptions(doc, WithImplicitOPTIONS())
+	require.NoError(t, err)
+	otherRoute, _, err := other.FindRoute(req)
+	require.NoError(t, err)
+	require.NotSame(t, route.Operation, otherRoute.Operation)
+
+	req, err = http.NewRequest(http.MethodDelete, "/pets", nil)
+	require.NoError(t, err)
+	_, _, err = implicit.FindRoute(req)
+	require.EqualError(t, err, routers.ErrMethodNotAllowed.Error())
+}
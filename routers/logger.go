@@ -0,0 +1,12 @@
+package routers
+
+import (
+	"context"
+)
+
+// Logger receives structured log records, with args alternating keys and values.
+// *slog.Logger implements it.
+type Logger interface {
+	InfoContext(ctx context.Context, msg string, args ...interface{})
+	WarnContext(ctx context.Context, msg string, args ...interface{})
+}
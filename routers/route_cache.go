@@ -0,0 +1,55 @@
+package routers
+
+import "sync"
+
+// routeCache holds values derived from a route.
+type routeCache struct {
+	values sync.Map
+}
+
+var (
+	routeCompilersMu sync.RWMutex
+	routeCompilers   []func(*Route)
+)
+
+// RegisterRouteCompiler registers a function that routers call on each of their routes
+// when constructed, so that data used when handling requests of the route
+// is computed once, typically with Route.Cached.
+func RegisterRouteCompiler(f func(route *Route)) {
+	if f == nil {
+		panic("route compiler is not defined")
+	}
+	routeCompilersMu.Lock()
+	defer routeCompilersMu.Unlock()
+	routeCompilers = append(routeCompilers, f)
+}
+
+// Compile enables caching values derived from route then calls the registered
+// route compilers on it. Router implementations call it on each route they build,
+// once it is no longer modified.
+func Compile(route *Route) {
+	if route.cache == nil {
+		route.cache = &routeCache{}
+	}
+	routeCompilersMu.RLock()
+	compilers := routeCompilers
+	routeCompilersMu.RUnlock()
+	for _, f := range compilers {
+		f(route)
+	}
+}
+
+// Cached returns the value stored under key for the route, storing the result of compute
+// first if there is none.
+// Values are only cached for routes passed to Compile: compute is called each time otherwise.
+func (route *Route) Cached(key interface{}, compute func() interface{}) interface{} {
+	cache := route.cache
+	if cache == nil {
+		return compute()
+	}
+	if v, ok := cache.values.Load(key); ok {
+		return v
+	}
+	v, _ := cache.values.LoadOrStore(key, compute())
+	return v
+}
@@ -0,0 +1,25 @@
+package routers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteCached(t *testing.T) {
+	type key struct{}
+	calls := 0
+	compute := func() interface{} {
+		calls++
+		return calls
+	}
+
+	route := &Route{}
+	require.Equal(t, 1, route.Cached(key{}, compute))
+	require.Equal(t, 2, route.Cached(key{}, compute))
+
+	Compile(route)
+	require.Equal(t, 3, route.Cached(key{}, compute))
+	require.Equal(t, 3, route.Cached(key{}, compute))
+	require.Equal(t, 3, calls)
+}
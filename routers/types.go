@@ -2,6 +2,7 @@ package routers
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
@@ -26,11 +27,27 @@ type Route struct {
 	PathItem  *openapi3.PathItem
 	Method    string
 	Operation *openapi3.Operation
+
+	// Implicit is set on the routes routers make up for methods the path item does not declare,
+	// such as HEAD requests routed to the GET operation (see gorillamux.WithImplicitHEAD
+	// and legacy.WithImplicitHEAD) or OPTIONS requests (see gorillamux.WithImplicitOPTIONS
+	// and legacy.WithImplicitOPTIONS).
+	Implicit bool
+	// Allow lists the methods requests to the path may use, sorted, e.g. for the Allow header
+	// of responses to OPTIONS requests. It is set by the gorillamux and legacy routers.
+	Allow []string
+
+	// cache is set by Compile
+	cache *routeCache
 }
 
 // ErrPathNotFound is returned when no route match is found
 var ErrPathNotFound error = &RouteError{"no matching operation was found"}
 
+// ErrServerNotFound is returned by routers checking servers strictly
+// when the scheme, host or base path of a request matches no server.
+var ErrServerNotFound error = &RouteError{"no matching server was found"}
+
 // ErrMethodNotAllowed is returned when no method of the matched route matches
 var ErrMethodNotAllowed error = &RouteError{"method not allowed"}
 
@@ -40,3 +57,44 @@ type RouteError struct {
 }
 
 func (e *RouteError) Error() string { return e.Reason }
+
+// ExtGreedy is the extension that marks the trailing parameter of a path as greedy:
+// it then matches all the remaining path segments, slashes included.
+// For instance `/files/{path}` with `x-kin-greedy: true` set on parameter "path"
+// matches `/files/a/b/c.png` and captures "a/b/c.png".
+const ExtGreedy = "x-kin-greedy"
+
+// GreedyPathParameter returns the name of the greedy trailing parameter of path
+// (see ExtGreedy) or an empty string if there is none.
+// Parameters are looked up in the path item and in each of its operations.
+func GreedyPathParameter(path string, pathItem *openapi3.PathItem) string {
+	if !strings.HasSuffix(path, "}") {
+		return ""
+	}
+	i := strings.LastIndexByte(path, '{')
+	if i < 0 {
+		return ""
+	}
+	name := path[i+1 : len(path)-1]
+
+	isGreedy := func(parameters openapi3.Parameters) bool {
+		for _, parameterRef := range parameters {
+			if p := parameterRef.Value; p != nil && p.In == openapi3.ParameterInPath && p.Name == name {
+				var greedy bool
+				if _, err := p.DecodeExtension(ExtGreedy, &greedy); err == nil && greedy {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if isGreedy(pathItem.Parameters) {
+		return name
+	}
+	for _, operation := range pathItem.Operations() {
+		if isGreedy(operation.Parameters) {
+			return name
+		}
+	}
+	return ""
+}
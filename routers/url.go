@@ -0,0 +1,263 @@
+package routers
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// BuildURL returns the URL of the operation identified by operationID,
+// with path and query parameters serialized according to their style and explode settings.
+//
+// params maps parameter names to values, which may be primitives, slices or maps.
+// Path parameters and required query parameters must be present in params.
+//
+// The URL is prefixed with the first server applicable to the operation
+// (operation servers, then path item servers, then document servers)
+// where server variables are replaced by their default values.
+func BuildURL(doc *openapi3.T, operationID string, params map[string]interface{}) (string, error) {
+	path, _, operation := doc.OperationByID(operationID)
+	pathItem := doc.Paths.Value(path)
+	if operation == nil || pathItem == nil {
+		return "", &RouteError{Reason: fmt.Sprintf("operation %q not found", operationID)}
+	}
+
+	parameters := make(openapi3.Parameters, 0, len(pathItem.Parameters)+len(operation.Parameters))
+	for _, parameterRef := range pathItem.Parameters {
+		if p := parameterRef.Value; p != nil && operation.Parameters.GetByInAndName(p.In, p.Name) == nil {
+			parameters = append(parameters, parameterRef)
+		}
+	}
+	parameters = append(parameters, operation.Parameters...)
+
+	var query []string
+	for _, parameterRef := range parameters {
+		parameter := parameterRef.Value
+		if parameter == nil {
+			continue
+		}
+		value, ok := params[parameter.Name]
+		switch parameter.In {
+		case openapi3.ParameterInPath:
+			if !ok || value == nil {
+				return "", fmt.Errorf("missing value for path parameter %q", parameter.Name)
+			}
+			sm, err := parameter.SerializationMethod()
+			if err != nil {
+				return "", err
+			}
+			greedy := false
+			if _, err := parameter.DecodeExtension(ExtGreedy, &greedy); err != nil {
+				return "", err
+			}
+			encoded, err := encodePathParameter(parameter.Name, sm, greedy, value)
+			if err != nil {
+				return "", fmt.Errorf("path parameter %q: %w", parameter.Name, err)
+			}
+			path = strings.Replace(path, "{"+parameter.Name+"}", encoded, -1)
+		case openapi3.ParameterInQuery:
+			if !ok || value == nil {
+				if parameter.Required {
+					return "", fmt.Errorf("missing value for query parameter %q", parameter.Name)
+				}
+				continue
+			}
+			sm, err := parameter.SerializationMethod()
+			if err != nil {
+				return "", err
+			}
+			pairs, err := encodeQueryParameter(parameter.Name, sm, parameter.AllowReserved, value)
+			if err != nil {
+				return "", fmt.Errorf("query parameter %q: %w", parameter.Name, err)
+			}
+			query = append(query, pairs...)
+		}
+	}
+
+	var servers openapi3.Servers
+	switch {
+	case operation.Servers != nil && len(*operation.Servers) > 0:
+		servers = *operation.Servers
+	case len(pathItem.Servers) > 0:
+		servers = pathItem.Servers
+	default:
+		servers = doc.Servers
+	}
+	prefix := ""
+	if len(servers) > 0 {
+		u, err := servers[0].ResolveURL(nil)
+		if err != nil {
+			return "", err
+		}
+		prefix = strings.TrimSuffix(u, "/")
+	}
+
+	u := prefix + path
+	if len(query) > 0 {
+		u += "?" + strings.Join(query, "&")
+	}
+	return u, nil
+}
+
+func encodePathParameter(name string, sm *openapi3.SerializationMethod, greedy bool, value interface{}) (string, error) {
+	var prefix, itemDelim, kvDelim string
+	switch sm.Style {
+	case openapi3.SerializationSimple:
+		itemDelim, kvDelim = ",", ","
+		if sm.Explode {
+			kvDelim = "="
+		}
+	case openapi3.SerializationLabel:
+		prefix, itemDelim, kvDelim = ".", ",", ","
+		if sm.Explode {
+			itemDelim, kvDelim = ".", "="
+		}
+	case openapi3.SerializationMatrix:
+		prefix, itemDelim, kvDelim = ";"+name+"=", ",", ","
+		if sm.Explode {
+			itemDelim, kvDelim = ";"+name+"=", "="
+		}
+	default:
+		return "", fmt.Errorf("unsupported serialization style %q", sm.Style)
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, escapePathValue(formatPrimitive(v.Index(i).Interface())))
+		}
+		return prefix + strings.Join(items, itemDelim), nil
+	case reflect.Map:
+		keys, values := sortedMapEntries(v)
+		items := make([]string, 0, 2*len(keys))
+		for i, key := range keys {
+			items = append(items, escapePathValue(key)+kvDelim+escapePathValue(values[i]))
+		}
+		if sm.Style == openapi3.SerializationMatrix && sm.Explode {
+			return ";" + strings.Join(items, ";"), nil
+		}
+		return prefix + strings.Join(items, itemDelim), nil
+	default:
+		if greedy {
+			// Keep slashes as segment separators
+			segments := strings.Split(formatPrimitive(value), "/")
+			for i, segment := range segments {
+				segments[i] = escapePathValue(segment)
+			}
+			return prefix + strings.Join(segments, "/"), nil
+		}
+		return prefix + escapePathValue(formatPrimitive(value)), nil
+	}
+}
+
+func encodeQueryParameter(name string, sm *openapi3.SerializationMethod, allowReserved bool, value interface{}) ([]string, error) {
+	escape := func(s string) string { return escapeQueryValue(s, allowReserved) }
+	name = url.QueryEscape(name)
+
+	var delim string
+	switch sm.Style {
+	case openapi3.SerializationForm:
+		delim = ","
+	case openapi3.SerializationSpaceDelimited:
+		delim = "%20"
+	case openapi3.SerializationPipeDelimited:
+		delim = "|"
+	case openapi3.SerializationDeepObject:
+	default:
+		return nil, fmt.Errorf("unsupported serialization style %q", sm.Style)
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if sm.Style == openapi3.SerializationDeepObject {
+			return nil, fmt.Errorf("style %q does not support arrays", sm.Style)
+		}
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, escape(formatPrimitive(v.Index(i).Interface())))
+		}
+		if sm.Explode {
+			pairs := make([]string, 0, len(items))
+			for _, item := range items {
+				pairs = append(pairs, name+"="+item)
+			}
+			return pairs, nil
+		}
+		return []string{name + "=" + strings.Join(items, delim)}, nil
+	case reflect.Map:
+		keys, values := sortedMapEntries(v)
+		switch {
+		case sm.Style == openapi3.SerializationDeepObject:
+			pairs := make([]string, 0, len(keys))
+			for i, key := range keys {
+				pairs = append(pairs, name+"["+escape(key)+"]="+escape(values[i]))
+			}
+			return pairs, nil
+		case sm.Explode:
+			pairs := make([]string, 0, len(keys))
+			for i, key := range keys {
+				pairs = append(pairs, escape(key)+"="+escape(values[i]))
+			}
+			return pairs, nil
+		default:
+			items := make([]string, 0, 2*len(keys))
+			for i, key := range keys {
+				items = append(items, escape(key), escape(values[i]))
+			}
+			return []string{name + "=" + strings.Join(items, delim)}, nil
+		}
+	default:
+		return []string{name + "=" + escape(formatPrimitive(value))}, nil
+	}
+}
+
+// escapePathValue percent-encodes s for use in a path segment,
+// including the delimiters of the path parameter styles.
+func escapePathValue(s string) string {
+	return pathDelimiterEscaper.Replace(url.PathEscape(s))
+}
+
+var pathDelimiterEscaper = strings.NewReplacer(",", "%2C", ";", "%3B", "=", "%3D")
+
+// escapeQueryValue percent-encodes s for use in a query string.
+// When allowReserved is set, characters reserved by RFC3986 are kept as is,
+// but for those delimiting query parameters and fragments (#&=+).
+func escapeQueryValue(s string, allowReserved bool) string {
+	escaped := url.QueryEscape(s)
+	escaped = strings.Replace(escaped, "+", "%20", -1)
+	if !allowReserved {
+		return escaped
+	}
+	for _, c := range ":/?[]@!$'()*,;" {
+		escaped = strings.Replace(escaped, url.QueryEscape(string(c)), string(c), -1)
+	}
+	return escaped
+}
+
+func sortedMapEntries(v reflect.Value) ([]string, []string) {
+	entries := make(map[string]string, v.Len())
+	keys := make([]string, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key := formatPrimitive(iter.Key().Interface())
+		entries[key] = formatPrimitive(iter.Value().Interface())
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, entries[key])
+	}
+	return keys, values
+}
+
+func formatPrimitive(value interface{}) string {
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,90 @@
+package routers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+func TestBuildURL(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.0
+info: {title: Example, version: '1.0'}
+servers:
+- url: https://{env}.example.com/v1
+  variables:
+    env: {default: api}
+paths:
+  /pets/{petId}:
+    parameters:
+    - {name: petId, in: path, required: true, schema: {type: integer}}
+    get:
+      operationId: getPet
+      parameters:
+      - {name: fields, in: query, schema: {type: array, items: {type: string}}}
+      - {name: csv, in: query, explode: false, schema: {type: array, items: {type: string}}}
+      - {name: filter, in: query, style: deepObject, explode: true, schema: {type: object}}
+      - {name: q, in: query, required: true, schema: {type: string}}
+      - {name: raw, in: query, allowReserved: true, schema: {type: string}}
+      responses:
+        '200': {description: OK}
+  /files/{ids}/{point}:
+    get:
+      operationId: getFiles
+      servers:
+      - url: /files-api
+      parameters:
+      - {name: ids, in: path, required: true, style: label, explode: true, schema: {type: array, items: {type: integer}}}
+      - {name: point, in: path, required: true, style: matrix, explode: true, schema: {type: object}}
+      responses:
+        '200': {description: OK}
+`)
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+
+	u, err := routers.BuildURL(doc, "getPet", map[string]interface{}{
+		"petId":  42,
+		"fields": []string{"name", "tag"},
+		"csv":    []interface{}{"a", "b c"},
+		"filter": map[string]interface{}{"color": "red", "age": 3},
+		"q":      "x&y",
+		"raw":    "a/b?c",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://api.example.com/v1/pets/42?fields=name&fields=tag&csv=a,b%20c&filter[age]=3&filter[color]=red&q=x%26y&raw=a/b?c", u)
+
+	u, err = routers.BuildURL(doc, "getFiles", map[string]interface{}{
+		"ids":   []int{1, 2},
+		"point": map[string]int{"x": 1, "y": 2},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "/files-api/files/.1.2/;x=1;y=2", u)
+
+	// Delimiters are escaped in values, even reserved characters are allowed
+	u, err = routers.BuildURL(doc, "getPet", map[string]interface{}{
+		"petId": "a,b;c=d",
+		"q":     "1",
+		"raw":   "a/b?c#d&e=f+g",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://api.example.com/v1/pets/a%2Cb%3Bc%3Dd?q=1&raw=a/b?c%23d%26e%3Df%2Bg", u)
+
+	_, err = routers.BuildURL(doc, "getPet", map[string]interface{}{"petId": 1})
+	require.EqualError(t, err, `missing value for query parameter "q"`)
+
+	_, err = routers.BuildURL(doc, "getPet", map[string]interface{}{"q": "1"})
+	require.EqualError(t, err, `missing value for path parameter "petId"`)
+
+	_, err = routers.BuildURL(doc, "nope", nil)
+	require.EqualError(t, err, `operation "nope" not found`)
+
+	doc.Servers[0].URL = "https://{env}.{region}.example.com/v1"
+	_, err = routers.BuildURL(doc, "getPet", map[string]interface{}{"petId": 1, "q": "1"})
+	require.EqualError(t, err, `server "https://{env}.{region}.example.com/v1" has undeclared variable "region"`)
+}
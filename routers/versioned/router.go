@@ -0,0 +1,181 @@
+// Package versioned implements a router serving several versions of an API,
+// each described by its own document, and dispatching requests to the router
+// of the version they are for.
+//
+// A single openapi3filter.Validator then validates the requests of all versions,
+// as routes refer to the document of their version:
+//
+//	router, err := versioned.NewRouterFromDocuments(versioned.Header("Accept-Version"),
+//		map[string]*openapi3.T{"1": v1, "2": v2}, versioned.WithDefaultVersion("2"))
+//	validator := openapi3filter.NewValidator(router)
+package versioned
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// ErrVersionNotFound is returned when a request is for no version of the router,
+// which has no default version.
+var ErrVersionNotFound error = &routers.RouteError{Reason: "no matching API version was found"}
+
+// VersionFunc returns the version a request is for, or "" if it is unknown,
+// along with the request to route with the router of that version.
+type VersionFunc func(req *http.Request) (version string, routed *http.Request)
+
+// Header returns a VersionFunc reading versions from the given request header,
+// e.g. Accept-Version.
+func Header(name string) VersionFunc {
+	return func(req *http.Request) (string, *http.Request) {
+		return strings.TrimSpace(req.Header.Get(name)), req
+	}
+}
+
+// Host returns a VersionFunc reading versions from the host of requests, without its port,
+// e.g. "v1.api.example.com".
+func Host() VersionFunc {
+	return func(req *http.Request) (string, *http.Request) {
+		host := req.Host
+		if host == "" {
+			host = req.URL.Host
+		}
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		return strings.ToLower(host), req
+	}
+}
+
+// PathPrefix returns a VersionFunc reading versions from the first segment of the path
+// of requests, e.g. "v1" for "/v1/pets". Requests are routed as is: the servers
+// of the document of each version are expected to hold the prefix (e.g. "/v1").
+func PathPrefix() VersionFunc {
+	return func(req *http.Request) (string, *http.Request) {
+		version, _ := splitPath(req.URL.Path)
+		return version, req
+	}
+}
+
+// TrimPathPrefix returns a VersionFunc reading versions from the first segment of the path
+// of requests, as PathPrefix does, and routing requests without that segment,
+// e.g. with the path "/pets" for "/v1/pets".
+func TrimPathPrefix() VersionFunc {
+	return func(req *http.Request) (string, *http.Request) {
+		version, rest := splitPath(req.URL.Path)
+		if version == "" {
+			return "", req
+		}
+		routed := new(http.Request)
+		*routed = *req
+		u := *req.URL
+		u.Path = rest
+		u.RawPath = ""
+		if prefix := "/" + version; strings.HasPrefix(req.URL.RawPath, prefix+"/") {
+			u.RawPath = strings.TrimPrefix(req.URL.RawPath, prefix)
+		}
+		routed.URL = &u
+		return version, routed
+	}
+}
+
+// splitPath returns the first segment of path and the path that follows it.
+func splitPath(path string) (string, string) {
+	path = strings.TrimPrefix(path, "/")
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		return path[:i], path[i:]
+	}
+	return path, "/"
+}
+
+// Option allows tweaking the Router built by NewRouter.
+type Option func(*Router)
+
+// WithDefaultVersion makes the router route the requests of unknown versions
+// with the router of version, as they are.
+func WithDefaultVersion(version string) Option {
+	return func(r *Router) { r.defaultVersion = version }
+}
+
+// Router dispatches requests to the router of the version they are for.
+type Router struct {
+	version        VersionFunc
+	routers        map[string]routers.Router
+	defaultVersion string
+}
+
+var _ routers.Router = &Router{}
+
+// NewRouter creates a router dispatching requests to versions, the routers by version,
+// where version tells which version a request is for.
+func NewRouter(version VersionFunc, versions map[string]routers.Router, opts ...Option) (*Router, error) {
+	if version == nil {
+		return nil, errors.New("missing VersionFunc")
+	}
+	r := &Router{
+		version: version,
+		routers: make(map[string]routers.Router, len(versions)),
+	}
+	for v, router := range versions {
+		r.routers[v] = router
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.defaultVersion != "" {
+		if _, ok := r.routers[r.defaultVersion]; !ok {
+			return nil, fmt.Errorf("default version %q has no router", r.defaultVersion)
+		}
+	}
+	return r, nil
+}
+
+// NewRouterFromDocuments creates a router dispatching requests to gorillamux routers
+// of docs, the documents by version.
+func NewRouterFromDocuments(version VersionFunc, docs map[string]*openapi3.T, opts ...Option) (*Router, error) {
+	names := make([]string, 0, len(docs))
+	for v := range docs {
+		names = append(names, v)
+	}
+	sort.Strings(names)
+	versions := make(map[string]routers.Router, len(docs))
+	for _, v := range names {
+		router, err := gorillamux.NewRouter(docs[v])
+		if err != nil {
+			return nil, err
+		}
+		versions[v] = router
+	}
+	return NewRouter(version, versions, opts...)
+}
+
+// Versions returns the sorted versions of the router.
+func (r *Router) Versions() []string {
+	versions := make([]string, 0, len(r.routers))
+	for v := range r.routers {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// FindRoute matches req with the router of the version its VersionFunc returns,
+// or else of the default version.
+func (r *Router) FindRoute(req *http.Request) (*routers.Route, map[string]string, error) {
+	version, routed := r.version(req)
+	router, ok := r.routers[version]
+	if !ok {
+		if r.defaultVersion == "" {
+			return nil, nil, ErrVersionNotFound
+		}
+		router, routed = r.routers[r.defaultVersion], req
+	}
+	return router.FindRoute(routed)
+}
@@ -0,0 +1,100 @@
+package versioned
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func loadVersion(t *testing.T, version, servers string) *openapi3.T {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Pets, version: ` + version + `}
+` + servers + `
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+      responses: {'200': {description: Pet}}
+`))
+	require.NoError(t, err)
+	return doc
+}
+
+func TestRouter(t *testing.T) {
+	docs := map[string]*openapi3.T{
+		"1": loadVersion(t, "1.0.0", ""),
+		"2": loadVersion(t, "2.0.0", ""),
+	}
+
+	for _, tc := range []struct {
+		name    string
+		version VersionFunc
+		opts    []Option
+		url     string
+		header  http.Header
+		want    string
+		err     error
+	}{
+		{name: "header", version: Header("Accept-Version"), url: "/pets/rex", header: http.Header{"Accept-Version": {"2"}}, want: "2.0.0"},
+		{name: "missing header", version: Header("Accept-Version"), url: "/pets/rex", err: ErrVersionNotFound},
+		{name: "default", version: Header("Accept-Version"), opts: []Option{WithDefaultVersion("1")}, url: "/pets/rex", header: http.Header{"Accept-Version": {"3"}}, want: "1.0.0"},
+		{name: "trimmed prefix", version: TrimPathPrefix(), url: "/2/pets/rex", want: "2.0.0"},
+		{name: "unknown prefix", version: TrimPathPrefix(), url: "/3/pets/rex", err: ErrVersionNotFound},
+		{name: "default prefix", version: TrimPathPrefix(), opts: []Option{WithDefaultVersion("1")}, url: "/pets/rex", want: "1.0.0"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			router, err := NewRouterFromDocuments(tc.version, docs, tc.opts...)
+			require.NoError(t, err)
+			require.Equal(t, []string{"1", "2"}, router.Versions())
+
+			req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			for name, values := range tc.header {
+				req.Header[name] = values
+			}
+			route, pathParams, err := router.FindRoute(req)
+			require.Equal(t, tc.err, err)
+			if err == nil {
+				require.Equal(t, tc.want, route.Spec.Info.Version)
+				require.Equal(t, map[string]string{"id": "rex"}, pathParams)
+			}
+		})
+	}
+
+	_, err := NewRouterFromDocuments(Header("Accept-Version"), docs, WithDefaultVersion("3"))
+	require.EqualError(t, err, `default version "3" has no router`)
+}
+
+func TestRouterByPrefixAndHost(t *testing.T) {
+	router, err := NewRouterFromDocuments(PathPrefix(), map[string]*openapi3.T{
+		"v1": loadVersion(t, "1.0.0", "servers: [{url: /v1}]"),
+		"v2": loadVersion(t, "2.0.0", "servers: [{url: /v2}]"),
+	})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodGet, "/v2/pets/rex", nil)
+	require.NoError(t, err)
+	route, _, err := router.FindRoute(req)
+	require.NoError(t, err)
+	require.Equal(t, "2.0.0", route.Spec.Info.Version)
+
+	router, err = NewRouterFromDocuments(Host(), map[string]*openapi3.T{
+		"v1.example.com": loadVersion(t, "1.0.0", ""),
+		"v2.example.com": loadVersion(t, "2.0.0", ""),
+	})
+	require.NoError(t, err)
+	req, err = http.NewRequest(http.MethodGet, "http://V1.example.com:8080/pets/rex", nil)
+	require.NoError(t, err)
+	route, _, err = router.FindRoute(req)
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", route.Spec.Info.Version)
+
+	req, err = http.NewRequest(http.MethodGet, "http://v3.example.com/pets/rex", nil)
+	require.NoError(t, err)
+	_, _, err = router.FindRoute(req)
+	require.Equal(t, ErrVersionNotFound, err)
+}
@@ -0,0 +1,95 @@
+// Package webhook implements a router for the webhooks of a document.
+//
+// Webhooks describe the requests an API sends rather than receives:
+// their deliveries are matched by webhook name rather than URL path,
+// as the receiving URL is chosen by the subscriber.
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// ErrWebhookNotFound is returned when a delivery is for no webhook of the document
+var ErrWebhookNotFound error = &routers.RouteError{Reason: "no matching webhook was found"}
+
+// NameFunc returns the name of the webhook a delivery is for, or "" if it is unknown.
+type NameFunc func(req *http.Request) string
+
+// Header returns a NameFunc reading webhook names from the given request header,
+// as many APIs send the kind of event they deliver in a header (e.g. X-GitHub-Event).
+func Header(name string) NameFunc {
+	return func(req *http.Request) string { return req.Header.Get(name) }
+}
+
+// Router maps webhook deliveries to the operations of the webhooks of a document.
+type Router struct {
+	name   NameFunc
+	routes map[string]map[string]*routers.Route
+}
+
+var _ routers.Router = &Router{}
+
+// NewRouter creates a router for the webhooks of doc,
+// where name tells which webhook a request is a delivery of.
+//
+// Routes have the webhook name as Path, and no Server.
+func NewRouter(doc *openapi3.T, name NameFunc) (*Router, error) {
+	if name == nil {
+		return nil, errors.New("missing webhook NameFunc")
+	}
+	r := &Router{
+		name:   name,
+		routes: make(map[string]map[string]*routers.Route, len(doc.Webhooks)),
+	}
+	for webhook, pathItem := range doc.Webhooks {
+		if pathItem == nil {
+			continue
+		}
+		operations := pathItem.Operations()
+		routes := make(map[string]*routers.Route, len(operations))
+		for method, operation := range operations {
+			route := &routers.Route{
+				Spec:      doc,
+				Path:      webhook,
+				PathItem:  pathItem,
+				Method:    method,
+				Operation: operation,
+			}
+			routers.Compile(route)
+			routes[method] = route
+		}
+		r.routes[webhook] = routes
+	}
+	return r, nil
+}
+
+// FindRoute matches req with the webhook its NameFunc returns and with its method.
+//
+// Webhooks have no path parameters: the returned map is always empty.
+func (r *Router) FindRoute(req *http.Request) (*routers.Route, map[string]string, error) {
+	route, err := r.FindWebhook(r.name(req), req.Method)
+	if err != nil {
+		return nil, nil, err
+	}
+	return route, map[string]string{}, nil
+}
+
+// FindWebhook returns the route of the operation of method of the named webhook.
+// The route is a copy the caller may modify.
+func (r *Router) FindWebhook(name, method string) (*routers.Route, error) {
+	routes, ok := r.routes[name]
+	if !ok {
+		return nil, ErrWebhookNotFound
+	}
+	route := routes[strings.ToUpper(method)]
+	if route == nil {
+		return nil, routers.ErrMethodNotAllowed
+	}
+	found := *route
+	return &found, nil
+}
@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+const spec = `
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths: {}
+x-webhooks:
+  newPet:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        '200': {description: OK}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+`
+
+func TestRouter(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	require.Contains(t, doc.Webhooks, "newPet")
+
+	router, err := NewRouter(doc, Header("X-Event"))
+	require.NoError(t, err)
+
+	deliver := func(event, method, body string) (*routers.Route, error) {
+		req, err := http.NewRequest(method, "https://subscriber.example.com/hooks", bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Event", event)
+		route, pathParams, err := router.FindRoute(req)
+		if err != nil {
+			return nil, err
+		}
+		return route, openapi3filter.ValidateRequest(context.Background(), &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+		})
+	}
+
+	route, err := deliver("newPet", http.MethodPost, `{"name":"Rex"}`)
+	require.NoError(t, err)
+	require.Equal(t, "newPet", route.Path)
+	require.Same(t, doc.Webhooks["newPet"].Post, route.Operation)
+
+	_, err = deliver("newPet", http.MethodPost, `{}`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `property "name" is missing`)
+
+	_, err = deliver("newPet", http.MethodGet, ``)
+	require.Equal(t, routers.ErrMethodNotAllowed, err)
+	_, err = deliver("oldPet", http.MethodPost, `{"name":"Rex"}`)
+	require.Equal(t, ErrWebhookNotFound, err)
+
+	route, err = router.FindWebhook("newPet", "post")
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPost, route.Method)
+
+	// Routes are copies
+	route.Path = "changed"
+	route, err = router.FindWebhook("newPet", http.MethodPost)
+	require.NoError(t, err)
+	require.Equal(t, "newPet", route.Path)
+
+	_, err = NewRouter(doc, nil)
+	require.EqualError(t, err, "missing webhook NameFunc")
+}